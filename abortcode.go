@@ -0,0 +1,110 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// AbortCode classify the error of an aborted node,
+// to dispatch on it in retry policies and dead letters.
+type AbortCode string
+
+const (
+	// UnclassifiedAbort is the code of an error without classification
+	UnclassifiedAbort AbortCode = ""
+	// TransientAbort is the code of an error who can disappear by computing again (e.g. timeout, unavailable service)
+	TransientAbort AbortCode = "transient"
+	// PermanentAbort is the code of an error who stay by computing again
+	PermanentAbort AbortCode = "permanent"
+	// ValidationAbort is the code of an error due to invalid data
+	ValidationAbort AbortCode = "validation"
+	// BudgetExceededAbort is the code of a node not computed as its computation exceeded its budget (see ComputationBudget)
+	BudgetExceededAbort AbortCode = "budget-exceeded"
+)
+
+// ExitCode give the process exit code of an abort code, based on sysexits.
+func (c AbortCode) ExitCode() int {
+	switch c {
+	case TransientAbort:
+		return 75
+	case PermanentAbort:
+		return 70
+	case ValidationAbort:
+		return 65
+	default:
+		return 1
+	}
+}
+
+// codedError is an error classified by an abort code.
+type codedError struct {
+	code AbortCode
+	err  error
+}
+
+func (e codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e codedError) Unwrap() error {
+	return e.err
+}
+
+// WithAbortCode classify an error with an abort code,
+// a node returning this error is aborted with the code.
+func WithAbortCode(code AbortCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return codedError{code: code, err: err}
+}
+
+// AbortCodeOf give the abort code of an error classified by WithAbortCode.
+func AbortCodeOf(err error) AbortCode {
+	var coded codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return UnclassifiedAbort
+}
+
+// NewAbortWithCodeComputeState generate a computation state to throw an error classified by a code
+func NewAbortWithCodeComputeState(code AbortCode, err error) ComputeState {
+	return ComputeState{
+		Value: AbortState,
+		Error: err,
+		Code:  code,
+	}
+}
+
+// AbortCode give the code of the aborted node of the computation (by node name if many),
+// or UnclassifiedAbort if not aborted.
+func (r ComputationResult) AbortCode() AbortCode {
+	names := make([]string, 0)
+	codes := make(map[string]AbortCode)
+	for node, state := range r.Report {
+		if state.Value == AbortState {
+			name := fmt.Sprint(node)
+			names = append(names, name)
+			codes[name] = state.Code
+		}
+	}
+	if len(names) == 0 {
+		return UnclassifiedAbort
+	}
+	sort.Strings(names)
+	return codes[names[0]]
+}
+
+// ExitCode give the process exit code of the computation,
+// 0 when ended without abort nor error, the exit code of the abort code when aborted, 1 otherwise.
+func (r ComputationResult) ExitCode() int {
+	if r.IsAborted() {
+		return r.AbortCode().ExitCode()
+	}
+	if r.Error != nil {
+		return 1
+	}
+	return 0
+}
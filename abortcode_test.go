@@ -0,0 +1,94 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_AbortCodeOf(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenError   error
+		expectedCode AbortCode
+	}{
+		{
+			name:         "Can classify an error without code",
+			givenError:   errors.New("error"),
+			expectedCode: UnclassifiedAbort,
+		},
+		{
+			name:         "Can classify an error with code",
+			givenError:   WithAbortCode(ValidationAbort, errors.New("error")),
+			expectedCode: ValidationAbort,
+		},
+		{
+			name:         "Can classify a wrapped error with code",
+			givenError:   fmt.Errorf("can't call api: %w", WithAbortCode(TransientAbort, errors.New("unavailable"))),
+			expectedCode: TransientAbort,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := AbortCodeOf(testCase.givenError)
+
+			if code != testCase.expectedCode {
+				t.Errorf("got: %+v, want: %+v", code, testCase.expectedCode)
+			}
+		})
+	}
+}
+
+func Test_ComputationResult_ExitCode(t *testing.T) {
+	validateInput, _ := NewActionNode("validateInput", func(c *Context) error {
+		if !c.HaveKey("input") {
+			return WithAbortCode(ValidationAbort, errors.New("missing input"))
+		}
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(validateInput)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	testCases := []struct {
+		name             string
+		givenData        map[string]interface{}
+		expectedState    ComputeState
+		expectedCode     AbortCode
+		expectedExitCode int
+	}{
+		{
+			name:             "Can end without abort",
+			givenData:        map[string]interface{}{"input": true},
+			expectedState:    NewContinueComputeState(),
+			expectedCode:     UnclassifiedAbort,
+			expectedExitCode: 0,
+		},
+		{
+			name:             "Can end on abort with code",
+			givenData:        map[string]interface{}{},
+			expectedState:    NewAbortWithCodeComputeState(ValidationAbort, errors.New("missing input")),
+			expectedCode:     ValidationAbort,
+			expectedExitCode: 65,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := eng.Compute(testCase.givenData)
+
+			if !cmp.Equal(result.Report[validateInput], testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", result.Report[validateInput], testCase.expectedState)
+			}
+			if result.AbortCode() != testCase.expectedCode {
+				t.Errorf("code - got: %+v, want: %+v", result.AbortCode(), testCase.expectedCode)
+			}
+			if result.ExitCode() != testCase.expectedExitCode {
+				t.Errorf("exit code - got: %+v, want: %+v", result.ExitCode(), testCase.expectedExitCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package hoff
+
+// ActivatedNodeSystem is an immutable activated node system, created by NodeSystem.Activate.
+type ActivatedNodeSystem struct {
+	system *NodeSystem
+}
+
+// NodeSystem get the activated node system, freezed, to be used by a computation or an engine.
+func (a *ActivatedNodeSystem) NodeSystem() *NodeSystem {
+	return a.system
+}
+
+// InitialNodes get the initial nodes
+func (a *ActivatedNodeSystem) InitialNodes() []Node {
+	return a.system.InitialNodes()
+}
+
+// Follow get the set of nodes accessible from a specific node and one of its branch.
+func (a *ActivatedNodeSystem) Follow(n Node, branch *bool) ([]Node, error) {
+	return a.system.Follow(n, branch)
+}
+
+// Ancestors get the set of nodes who access using one of their branch to a specific node.
+func (a *ActivatedNodeSystem) Ancestors(n Node, branch *bool) ([]Node, error) {
+	return a.system.Ancestors(n, branch)
+}
+
+// TerminalNodes get the terminal nodes.
+func (a *ActivatedNodeSystem) TerminalNodes() []Node {
+	nodes, _ := a.system.TerminalNodes()
+	return nodes
+}
+
+// copy give a not activated copy of the node system configuration.
+func (s *NodeSystem) copy() *NodeSystem {
+	c := NewNodeSystem()
+	c.identity = s.identity
+	c.validation = s.validation
+	c.nodes = append(c.nodes, s.nodes...)
+	for node, mode := range s.nodesJoinModes {
+		c.nodesJoinModes[node] = mode
+	}
+	for node, expression := range s.nodesJoinExpressions {
+		c.nodesJoinExpressions[node] = expression
+	}
+	for node, flag := range s.nodesFlags {
+		c.nodesFlags[node] = flag
+	}
+	for node, tags := range s.nodesTags {
+		c.nodesTags[node] = append([]string(nil), tags...)
+	}
+	for node, typ := range s.nodesTypes {
+		if c.nodesTypes == nil {
+			c.nodesTypes = make(map[string]registeredNodeType)
+		}
+		c.nodesTypes[node] = typ
+	}
+	for node, keys := range s.nodesKeys {
+		c.nodesKeys[node] = nodeKeys{
+			Required: append([]string(nil), keys.Required...),
+			Produced: append([]string(nil), keys.Produced...),
+		}
+	}
+	for node, ports := range s.nodesPorts {
+		c.nodesPorts[node] = nodePorts{
+			Inputs:  append([]Port(nil), ports.Inputs...),
+			Outputs: append([]Port(nil), ports.Outputs...),
+		}
+	}
+	for _, link := range s.links {
+		if link.Metadata != nil {
+			metadata := make(map[string]string, len(link.Metadata))
+			for key, value := range link.Metadata {
+				metadata[key] = value
+			}
+			link.Metadata = metadata
+		}
+		c.links = append(c.links, link)
+	}
+	c.portLinks = append(c.portLinks, s.portLinks...)
+	for id := range s.terminalNodes {
+		if c.terminalNodes == nil {
+			c.terminalNodes = make(map[string]bool)
+		}
+		c.terminalNodes[id] = true
+	}
+	c.declaredInitialNodes = append(c.declaredInitialNodes, s.declaredInitialNodes...)
+	for name, nodes := range s.triggers {
+		if c.triggers == nil {
+			c.triggers = make(map[string][]Node)
+		}
+		c.triggers[name] = append([]Node(nil), nodes...)
+	}
+	c.inputs = append(c.inputs, s.inputs...)
+	c.outputs = append(c.outputs, s.outputs...)
+	for id, probability := range s.nodesProbabilities {
+		if c.nodesProbabilities == nil {
+			c.nodesProbabilities = make(map[string]float64)
+		}
+		c.nodesProbabilities[id] = probability
+	}
+	return c
+}
@@ -0,0 +1,148 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func Test_NodeSystem_Activate_copy(t *testing.T) {
+	system := NewNodeSystem()
+	system.AddNode(someActionNode)
+	system.AddNode(anotherActionNode)
+	system.AddLink(someActionNode, anotherActionNode)
+	system.ConfigureMetadataOnLink(someActionNode, anotherActionNode, nil, map[string]string{"owner": "team"})
+
+	activated, err := system.Activate()
+	if err != nil {
+		t.Fatalf("activate - got: %+v, want: %+v", err, nil)
+	}
+
+	if system.IsActivated() {
+		t.Errorf("builder activation - got: %+v, want: %+v", true, false)
+	}
+	if !activated.NodeSystem().IsActivated() {
+		t.Errorf("copy activation - got: %+v, want: %+v", false, true)
+	}
+
+	_, err = system.AddNode(alwaysTrueDecisionNode)
+	if err != nil {
+		t.Errorf("builder edition - got: %+v, want: %+v", err, nil)
+	}
+	system.ConfigureMetadataOnLink(someActionNode, anotherActionNode, nil, map[string]string{"owner": "other team"})
+
+	if len(activated.NodeSystem().nodes) != 2 {
+		t.Errorf("copy nodes - got: %+v, want: %+v", activated.NodeSystem().nodes, 2)
+	}
+	metadata := activated.NodeSystem().MetadataOfLink(someActionNode, anotherActionNode, nil)
+	if metadata["owner"] != "team" {
+		t.Errorf("copy metadata - got: %+v, want: %+v", metadata["owner"], "team")
+	}
+	initialNodes := activated.InitialNodes()
+	if len(initialNodes) != 1 || initialNodes[0] != someActionNode {
+		t.Errorf("initial nodes - got: %+v, want: %+v", initialNodes, []Node{someActionNode})
+	}
+	followingNodes, _ := activated.Follow(someActionNode, nil)
+	if len(followingNodes) != 1 || followingNodes[0] != anotherActionNode {
+		t.Errorf("following nodes - got: %+v, want: %+v", followingNodes, []Node{anotherActionNode})
+	}
+}
+
+func Test_NodeSystem_Activate_invalid(t *testing.T) {
+	system := NewNodeSystem()
+	system.AddLink(someActionNode, anotherActionNode)
+
+	activated, err := system.Activate()
+
+	if activated != nil || err == nil {
+		t.Errorf("got: %+v (error: %+v), want: an error", activated, err)
+	}
+}
+
+func Test_NodeSystem_copy_allFields(t *testing.T) {
+	// the fields computed on activation are not copied
+	activationFields := map[string]bool{
+		"activated":          true,
+		"activationWarnings": true,
+		"initialNodes":       true,
+		"nodesIDs":           true,
+		"nodesMapKeys":       true,
+		"fingerprint":        true,
+		"followingNodesTree": true,
+		"ancestorsNodesTree": true,
+	}
+
+	system := NewNodeSystem()
+	fields := reflect.ValueOf(system).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		if !activationFields[fields.Type().Field(i).Name] {
+			settableField(fields.Field(i)).Set(nonZeroValue(fields.Field(i).Type(), 0))
+		}
+	}
+
+	copiedFields := reflect.ValueOf(system.copy()).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Type().Field(i).Name
+		if activationFields[name] {
+			continue
+		}
+		if got, want := fmt.Sprint(copiedFields.Field(i)), fmt.Sprint(fields.Field(i)); got != want {
+			t.Errorf("%v - got: %+v, want: %+v", name, got, want)
+		}
+	}
+}
+
+// settableField give a settable value of a field, even unexported.
+func settableField(field reflect.Value) reflect.Value {
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
+
+// nonZeroValue give a value of a type with all its fields and elements set, up to a depth.
+func nonZeroValue(typ reflect.Type, depth int) reflect.Value {
+	value := reflect.New(typ).Elem()
+	if depth > 3 {
+		return value
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(0.5)
+	case reflect.String:
+		value.SetString("value")
+	case reflect.Slice:
+		value.Set(reflect.Append(value, nonZeroValue(typ.Elem(), depth+1)))
+	case reflect.Map:
+		value.Set(reflect.MakeMap(typ))
+		value.SetMapIndex(nonZeroValue(typ.Key(), depth+1), nonZeroValue(typ.Elem(), depth+1))
+	case reflect.Ptr:
+		value.Set(reflect.New(typ.Elem()))
+		value.Elem().Set(nonZeroValue(typ.Elem(), depth+1))
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			settableField(value.Field(i)).Set(nonZeroValue(typ.Field(i).Type, depth+1))
+		}
+	case reflect.Interface:
+		switch {
+		case reflect.TypeOf(someActionNode).Implements(typ):
+			value.Set(reflect.ValueOf(someActionNode))
+		case reflect.TypeOf(errors.New("")).Implements(typ):
+			value.Set(reflect.ValueOf(errors.New("value")))
+		}
+	case reflect.Func:
+		value.Set(reflect.MakeFunc(typ, func([]reflect.Value) []reflect.Value {
+			results := make([]reflect.Value, typ.NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(typ.Out(i))
+			}
+			return results
+		}))
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ActivationReport explain the activation of a node system, to understand where its computations start and end.
+type ActivationReport struct {
+	// Nodes explain the classification of each node, in the order of declaration
+	Nodes []NodeActivation
+	// FollowingNodes give the names of the following nodes of each node by branch ("true", "false", or "" without branch)
+	FollowingNodes map[string]map[string][]string
+	// AncestorsNodes give the names of the ancestors nodes of each node by branch ("true", "false", or "" without branch)
+	AncestorsNodes map[string]map[string][]string
+	// Warnings are the errors of the validation checks downgraded to warnings
+	Warnings []error
+}
+
+// NodeActivation explain why a node is, or is not, an initial node and a terminal node.
+type NodeActivation struct {
+	Node           Node
+	Initial        bool
+	InitialReason  string
+	Terminal       bool
+	TerminalReason string
+}
+
+// ActivationReport give the report of the activation of the node system.
+func (s *NodeSystem) ActivationReport() (ActivationReport, error) {
+	if !s.activated {
+		return ActivationReport{}, errors.New("can't report activation of a not activated node system")
+	}
+	report := ActivationReport{
+		Nodes:          make([]NodeActivation, 0, len(s.nodes)),
+		FollowingNodes: make(map[string]map[string][]string),
+		AncestorsNodes: make(map[string]map[string][]string),
+		Warnings:       s.activationWarnings,
+	}
+	for _, node := range s.nodes {
+		activation := NodeActivation{
+			Node:     node,
+			Initial:  s.isInitialNode(node),
+			Terminal: s.IsTerminal(node),
+		}
+		ancestors := reportedNodesTree(s.ancestorsNodesTree[s.nodeID(node)])
+		following := reportedNodesTree(s.followingNodesTree[s.nodeID(node)])
+		switch {
+		case len(s.declaredInitialNodes) > 0 && activation.Initial:
+			activation.InitialReason = "declared as initial"
+		case len(s.declaredInitialNodes) > 0:
+			activation.InitialReason = "not declared as initial"
+		case activation.Initial:
+			activation.InitialReason = "no link to it"
+		default:
+			activation.InitialReason = "linked from " + describeNodesTree(ancestors)
+		}
+		switch {
+		case len(s.terminalNodes) > 0 && activation.Terminal:
+			activation.TerminalReason = "marked as terminal"
+		case len(s.terminalNodes) > 0:
+			activation.TerminalReason = "not marked as terminal"
+		case activation.Terminal:
+			activation.TerminalReason = "no link from it"
+		default:
+			activation.TerminalReason = "linked to " + describeNodesTree(following)
+		}
+		report.Nodes = append(report.Nodes, activation)
+		if len(ancestors) > 0 {
+			report.AncestorsNodes[fmt.Sprint(node)] = ancestors
+		}
+		if len(following) > 0 {
+			report.FollowingNodes[fmt.Sprint(node)] = following
+		}
+	}
+	return report, nil
+}
+
+func (s *NodeSystem) isInitialNode(n Node) bool {
+	for _, node := range s.initialNodes {
+		if s.sameNode(node, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportedNodesTree give the names of the nodes of a tree by branch.
+func reportedNodesTree(tree map[*bool][]Node) map[string][]string {
+	reported := make(map[string][]string, len(tree))
+	for branch, nodes := range tree {
+		names := make([]string, len(nodes))
+		for i, node := range nodes {
+			names[i] = fmt.Sprint(node)
+		}
+		reported[branchName(branch)] = names
+	}
+	return reported
+}
+
+// describeNodesTree describe the nodes of a tree, without branch first.
+func describeNodesTree(tree map[string][]string) string {
+	descriptions := make([]string, 0)
+	for _, branch := range []string{"", "true", "false"} {
+		for _, name := range tree[branch] {
+			if branch == "" {
+				descriptions = append(descriptions, name)
+			} else {
+				descriptions = append(descriptions, fmt.Sprintf("%v (on branch %v)", name, branch))
+			}
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func branchName(branch *bool) string {
+	if branch == nil {
+		return ""
+	}
+	return fmt.Sprint(*branch)
+}
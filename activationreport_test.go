@@ -0,0 +1,70 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ActivationReport(t *testing.T) {
+	action1, _ := NewActionNode("action1", func(*Context) error { return nil })
+	decision2, _ := NewDecisionNode("decision2", func(*Context) (bool, error) { return true, nil })
+	action3, _ := NewActionNode("action3", func(*Context) error { return nil })
+	action4, _ := NewActionNode("action4", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(action1)
+	ns.AddNode(decision2)
+	ns.AddNode(action3)
+	ns.AddNode(action4)
+	ns.AddLink(action1, decision2)
+	ns.AddLinkOnBranch(decision2, action3, true)
+	ns.AddLinkOnBranch(decision2, action4, false)
+
+	_, err := ns.ActivationReport()
+	expectedError := errors.New("can't report activation of a not activated node system")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("not activated - got: %+v, want: %+v", err, expectedError)
+	}
+
+	ns.ActivateInPlace()
+	report, err := ns.ActivationReport()
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	expectedReport := ActivationReport{
+		Nodes: []NodeActivation{
+			{Node: action1, Initial: true, InitialReason: "no link to it", TerminalReason: "linked to decision2"},
+			{Node: decision2, InitialReason: "linked from action1", TerminalReason: "linked to action3 (on branch true), action4 (on branch false)"},
+			{Node: action3, InitialReason: "linked from decision2 (on branch true)", Terminal: true, TerminalReason: "no link from it"},
+			{Node: action4, InitialReason: "linked from decision2 (on branch false)", Terminal: true, TerminalReason: "no link from it"},
+		},
+		FollowingNodes: map[string]map[string][]string{
+			"action1":   {"": {"decision2"}},
+			"decision2": {"true": {"action3"}, "false": {"action4"}},
+		},
+		AncestorsNodes: map[string]map[string][]string{
+			"decision2": {"": {"action1"}},
+			"action3":   {"true": {"decision2"}},
+			"action4":   {"false": {"decision2"}},
+		},
+	}
+	if !cmp.Equal(report, expectedReport, NodeComparator, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", report, expectedReport)
+	}
+
+	marked := NewNodeSystem()
+	marked.AddNode(action1)
+	marked.AddNode(action3)
+	marked.ConfigureAsTerminal(action3)
+	marked.ActivateInPlace()
+	report, _ = marked.ActivationReport()
+	expectedNodes := []NodeActivation{
+		{Node: action1, Initial: true, InitialReason: "no link to it", TerminalReason: "not marked as terminal"},
+		{Node: action3, Initial: true, InitialReason: "no link to it", Terminal: true, TerminalReason: "marked as terminal"},
+	}
+	if !cmp.Equal(report.Nodes, expectedNodes, NodeComparator) {
+		t.Errorf("marked - got: %+v, want: %+v", report.Nodes, expectedNodes)
+	}
+}
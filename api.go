@@ -0,0 +1,404 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Authenticator check the requests made to an APIHandler.
+type Authenticator interface {
+	// Authenticate return an error when the request is not allowed.
+	Authenticate(r *http.Request) error
+}
+
+// TenantAuthenticator is an Authenticator who also give the tenant of the authenticated requests,
+// e.g. from the claims of their token, or from a header set by a trusted middleware.
+type TenantAuthenticator interface {
+	Authenticator
+	// Tenant give the tenant of an authenticated request, or an empty tenant for a request on all the tenants.
+	Tenant(r *http.Request) string
+}
+
+// APIHandler is an http.Handler to manage the computations of an engine with JSON payloads:
+//
+//	POST   /computations               {"data": {..}, "priority": 0} start a computation,
+//	                                   with 429 as status when the tenant quota is exceeded
+//	GET    /computations               list the computations
+//	GET    /computations/<id>          get the state and report of a computation
+//	DELETE /computations/<id>          forget an ended computation
+//	POST   /computations/<id>/cancel   cancel a computation
+//	POST   /computations/<id>/resume   {"token": "..", "payload": ..} resume a paused computation
+//	POST   /events/<token>             {"payload": ..} deliver an external event to a paused node
+//	GET    /health                     get the health of the engine, with 503 as status when not ready
+//	GET    /profile?limit=<n>          get the profile of the nodes from the last stored reports of the engine workflow
+//	GET    /schema                     get the input schema of the node system, to generate an input form
+//
+// The health route is not authenticated, to be used as readiness probe.
+// The tenant of a request is given by the authenticator (see TenantAuthenticator):
+// the computations are started for the tenant of the request, and only visible to the requests of their tenant,
+// like their paused nodes for the events delivery.
+// The computations are identified by the handler, the engine computation ID is given once started.
+type APIHandler struct {
+	engine        *Engine
+	authenticator Authenticator
+
+	mu           sync.Mutex
+	computations map[string]*apiComputation
+}
+
+type apiComputation struct {
+	handle *Handle
+	tenant string
+	// resumed is the result of the last resume of the computation, if any
+	resumed *ComputationResult
+}
+
+type apiStartRequest struct {
+	Data     map[string]interface{} `json:"data"`
+	Priority int                    `json:"priority"`
+}
+
+type apiEventRequest struct {
+	Token   string      `json:"token"`
+	Payload interface{} `json:"payload"`
+}
+
+type apiComputationStatus struct {
+	ID            string             `json:"id"`
+	ComputationID string             `json:"computation_id,omitempty"`
+	Tenant        string             `json:"tenant,omitempty"`
+	State         HandleState        `json:"state"`
+	Report        []nodeStateRecord  `json:"report"`
+	PausedTokens  []string           `json:"paused_tokens,omitempty"`
+	Result        *computationRecord `json:"result,omitempty"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// NewAPIHandler create a management API of an engine configured with a node system.
+func NewAPIHandler(engine *Engine) (*APIHandler, error) {
+	if engine == nil || engine.system == nil {
+		return nil, errors.New("can't create api handler without an engine configured with a node system")
+	}
+	return &APIHandler{
+		engine:       engine,
+		computations: make(map[string]*apiComputation),
+	}, nil
+}
+
+// ConfigureAuthenticator add the authenticator checking each request before handling it.
+func (a *APIHandler) ConfigureAuthenticator(authenticator Authenticator) {
+	a.authenticator = authenticator
+}
+
+// ServeHTTP handle the management requests.
+func (a *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/health" && r.Method == http.MethodGet {
+		a.health(w, r)
+		return
+	}
+	tenant := ""
+	if a.authenticator != nil {
+		if err := a.authenticator.Authenticate(r); err != nil {
+			writeAPIResponse(w, http.StatusUnauthorized, apiError{Error: err.Error()})
+			return
+		}
+		if tenantAuthenticator, isTenantAuthenticator := a.authenticator.(TenantAuthenticator); isTenantAuthenticator {
+			tenant = tenantAuthenticator.Tenant(r)
+		}
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "computations" && r.Method == http.MethodGet:
+		a.listComputations(w, tenant)
+	case len(segments) == 1 && segments[0] == "computations" && r.Method == http.MethodPost:
+		a.startComputation(w, r, tenant)
+	case len(segments) == 2 && segments[0] == "computations" && r.Method == http.MethodGet:
+		a.getComputation(w, segments[1], tenant)
+	case len(segments) == 2 && segments[0] == "computations" && r.Method == http.MethodDelete:
+		a.forgetComputation(w, segments[1], tenant)
+	case len(segments) == 3 && segments[0] == "computations" && segments[2] == "cancel" && r.Method == http.MethodPost:
+		a.cancelComputation(w, segments[1], tenant)
+	case len(segments) == 3 && segments[0] == "computations" && segments[2] == "resume" && r.Method == http.MethodPost:
+		a.resumeComputation(w, r, segments[1], tenant)
+	case len(segments) == 2 && segments[0] == "events" && r.Method == http.MethodPost:
+		a.deliverEvent(w, r, segments[1], tenant)
+	case len(segments) == 1 && segments[0] == "profile" && r.Method == http.MethodGet:
+		a.profile(w, r, tenant)
+	case len(segments) == 1 && segments[0] == "schema" && r.Method == http.MethodGet:
+		writeAPIResponse(w, http.StatusOK, a.engine.system.InputSchema())
+	default:
+		writeAPIResponse(w, http.StatusNotFound, apiError{Error: fmt.Sprintf("can't find route %v %v", r.Method, r.URL.Path)})
+	}
+}
+
+func (a *APIHandler) health(w http.ResponseWriter, r *http.Request) {
+	health := a.engine.Health(r.Context())
+	if !health.Ready {
+		writeAPIResponse(w, http.StatusServiceUnavailable, health)
+		return
+	}
+	writeAPIResponse(w, http.StatusOK, health)
+}
+
+func (a *APIHandler) profile(w http.ResponseWriter, r *http.Request, tenant string) {
+	if a.engine.reportStore == nil {
+		writeAPIResponse(w, http.StatusNotFound, apiError{Error: "can't profile without report store"})
+		return
+	}
+	query := ReportQuery{Workflow: a.engine.workflow, Tenant: tenant}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 0 {
+			writeAPIResponse(w, http.StatusBadRequest, apiError{Error: fmt.Sprintf("can't profile with invalid limit: %v", limit)})
+			return
+		}
+		query.Limit = value
+	}
+	reports, err := a.engine.reportStore.Query(query)
+	if err != nil {
+		writeAPIResponse(w, http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+	writeAPIResponse(w, http.StatusOK, NewProfile(reports))
+}
+
+func (a *APIHandler) startComputation(w http.ResponseWriter, r *http.Request, tenant string) {
+	var request apiStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIResponse(w, http.StatusBadRequest, apiError{Error: fmt.Sprintf("can't read computation request: %v", err)})
+		return
+	}
+	if request.Data == nil {
+		request.Data = make(map[string]interface{})
+	}
+
+	id, err := newToken()
+	if err != nil {
+		writeAPIResponse(w, http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+	// the computation outlive the request, but keep its values for the nodes
+	handle, err := a.engine.TrySubmit(valuesContext{parent: r.Context()}, request.Data, SubmitOptions{Priority: request.Priority, Tenant: tenant})
+	if errors.Is(err, ErrQueueFull) {
+		writeAPIResponse(w, http.StatusServiceUnavailable, apiError{Error: err.Error()})
+		return
+	}
+	if errors.Is(err, ErrTenantQuotaExceeded) {
+		writeAPIResponse(w, http.StatusTooManyRequests, apiError{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		writeAPIResponse(w, http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+
+	a.mu.Lock()
+	computation := &apiComputation{handle: handle, tenant: tenant}
+	a.computations[id] = computation
+	a.mu.Unlock()
+	writeAPIResponse(w, http.StatusAccepted, a.status(id, computation))
+}
+
+func (a *APIHandler) listComputations(w http.ResponseWriter, tenant string) {
+	a.mu.Lock()
+	ids := make([]string, 0, len(a.computations))
+	for id := range a.computations {
+		ids = append(ids, id)
+	}
+	a.mu.Unlock()
+	sort.Strings(ids)
+
+	statuses := make([]apiComputationStatus, 0, len(ids))
+	for _, id := range ids {
+		if computation, found := a.computation(id, tenant); found {
+			statuses = append(statuses, a.status(id, computation))
+		}
+	}
+	writeAPIResponse(w, http.StatusOK, statuses)
+}
+
+func (a *APIHandler) getComputation(w http.ResponseWriter, id, tenant string) {
+	computation, found := a.computation(id, tenant)
+	if !found {
+		writeAPIComputationNotFound(w, id)
+		return
+	}
+	writeAPIResponse(w, http.StatusOK, a.status(id, computation))
+}
+
+func (a *APIHandler) forgetComputation(w http.ResponseWriter, id, tenant string) {
+	computation, found := a.computation(id, tenant)
+	if !found {
+		writeAPIComputationNotFound(w, id)
+		return
+	}
+	status := a.status(id, computation)
+	if status.State == HandlePending || status.State == HandleRunning || status.State == HandlePaused {
+		writeAPIResponse(w, http.StatusConflict, apiError{Error: fmt.Sprintf("can't forget computation '%v' not ended", id)})
+		return
+	}
+	a.mu.Lock()
+	delete(a.computations, id)
+	a.mu.Unlock()
+	writeAPIResponse(w, http.StatusOK, status)
+}
+
+func (a *APIHandler) cancelComputation(w http.ResponseWriter, id, tenant string) {
+	computation, found := a.computation(id, tenant)
+	if !found {
+		writeAPIComputationNotFound(w, id)
+		return
+	}
+	computation.handle.Cancel()
+	writeAPIResponse(w, http.StatusAccepted, a.status(id, computation))
+}
+
+func (a *APIHandler) resumeComputation(w http.ResponseWriter, r *http.Request, id, tenant string) {
+	computation, found := a.computation(id, tenant)
+	if !found {
+		writeAPIComputationNotFound(w, id)
+		return
+	}
+	var request apiEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIResponse(w, http.StatusBadRequest, apiError{Error: fmt.Sprintf("can't read resume request: %v", err)})
+		return
+	}
+
+	tokens := a.status(id, computation).PausedTokens
+	if request.Token == "" && len(tokens) == 1 {
+		request.Token = tokens[0]
+	}
+	pausedOnToken := false
+	for _, token := range tokens {
+		pausedOnToken = pausedOnToken || token == request.Token
+	}
+	if !pausedOnToken {
+		writeAPIResponse(w, http.StatusConflict, apiError{Error: fmt.Sprintf("can't resume computation '%v' not paused on token '%v'", id, request.Token)})
+		return
+	}
+	a.deliver(w, request.Token, request.Payload)
+}
+
+func (a *APIHandler) deliverEvent(w http.ResponseWriter, r *http.Request, token, tenant string) {
+	var request apiEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIResponse(w, http.StatusBadRequest, apiError{Error: fmt.Sprintf("can't read event request: %v", err)})
+		return
+	}
+	if tenant != "" && !a.pausedOnToken(token, tenant) {
+		writeAPIResponse(w, http.StatusNotFound, apiError{Error: fmt.Sprintf("can't find paused computation for token '%v'", token)})
+		return
+	}
+	a.deliver(w, token, request.Payload)
+}
+
+// pausedOnToken tell if a computation of a tenant is paused on a token.
+func (a *APIHandler) pausedOnToken(token, tenant string) bool {
+	a.mu.Lock()
+	computations := make(map[string]*apiComputation)
+	for id, computation := range a.computations {
+		if computation.tenant == tenant {
+			computations[id] = computation
+		}
+	}
+	a.mu.Unlock()
+
+	for id, computation := range computations {
+		for _, pausedToken := range a.status(id, computation).PausedTokens {
+			if pausedToken == token {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deliver give the payload to the node paused on the token, and update the resumed computation.
+func (a *APIHandler) deliver(w http.ResponseWriter, token string, payload interface{}) {
+	result := a.engine.Deliver(token, payload)
+	if result.ID == "" {
+		writeAPIResponse(w, http.StatusNotFound, apiError{Error: result.Error.Error()})
+		return
+	}
+
+	a.mu.Lock()
+	for id, computation := range a.computations {
+		if computation.handle.ID() == result.ID {
+			computation.resumed = &result
+			a.mu.Unlock()
+			writeAPIResponse(w, http.StatusOK, a.status(id, computation))
+			return
+		}
+	}
+	a.mu.Unlock()
+
+	record := newComputationRecord(result)
+	writeAPIResponse(w, http.StatusOK, apiComputationStatus{
+		ComputationID: result.ID,
+		State:         resultHandleState(result),
+		Report:        record.Report,
+		PausedTokens:  result.PausedTokens(),
+		Result:        &record,
+	})
+}
+
+// computation give a computation visible to a tenant, all the computations being visible without tenant.
+func (a *APIHandler) computation(id, tenant string) (*apiComputation, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	computation, found := a.computations[id]
+	if !found || (tenant != "" && computation.tenant != tenant) {
+		return nil, false
+	}
+	return computation, true
+}
+
+func (a *APIHandler) status(id string, computation *apiComputation) apiComputationStatus {
+	a.mu.Lock()
+	resumed := computation.resumed
+	a.mu.Unlock()
+
+	handle := computation.handle
+	status := apiComputationStatus{
+		ID:            id,
+		ComputationID: handle.ID(),
+		Tenant:        computation.tenant,
+		State:         handle.State(),
+		Report:        newNodeStateRecords(handle.Report()),
+	}
+	select {
+	case <-handle.Done():
+		result := handle.result
+		if resumed != nil {
+			result = *resumed
+			status.State = resultHandleState(result)
+		}
+		record := newComputationRecord(result)
+		status.Report = record.Report
+		status.PausedTokens = result.PausedTokens()
+		status.Result = &record
+	default:
+	}
+	return status
+}
+
+func writeAPIComputationNotFound(w http.ResponseWriter, id string) {
+	writeAPIResponse(w, http.StatusNotFound, apiError{Error: fmt.Sprintf("can't find computation '%v'", id)})
+}
+
+func writeAPIResponse(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,272 @@
+package hoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type authenticatorFunc func(*http.Request) error
+
+func (f authenticatorFunc) Authenticate(r *http.Request) error {
+	return f(r)
+}
+
+type tenantAuthenticator struct{}
+
+func (tenantAuthenticator) Authenticate(r *http.Request) error {
+	if r.Header.Get("Authorization") == "" {
+		return errors.New("can't authenticate request without token")
+	}
+	return nil
+}
+
+func (tenantAuthenticator) Tenant(r *http.Request) string {
+	return r.Header.Get("Authorization")
+}
+
+func Test_NewAPIHandler(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenEngine   *Engine
+		expectedError error
+	}{
+		{
+			name:          "Can't create an api handler without engine",
+			expectedError: errors.New("can't create api handler without an engine configured with a node system"),
+		},
+		{
+			name:          "Can't create an api handler without node system",
+			givenEngine:   NewEngine(SequentialComputation),
+			expectedError: errors.New("can't create api handler without an engine configured with a node system"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewAPIHandler(testCase.givenEngine)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func serveAPI(api *APIHandler, method, path, body string) (int, map[string]interface{}) {
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	var response map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder.Code, response
+}
+
+func Test_APIHandler_ServeHTTP(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.AddNode(someActionNode)
+	ns.AddLink(approval, someActionNode)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	api, _ := NewAPIHandler(eng)
+
+	status, response := serveAPI(api, http.MethodPost, "/computations", `{"data": {"key": "value"}}`)
+	if status != http.StatusAccepted {
+		t.Errorf("start status - got: %+v, want: %+v", status, http.StatusAccepted)
+	}
+	id := response["id"].(string)
+	computation, _ := api.computation(id, "")
+	<-computation.handle.Done()
+
+	status, response = serveAPI(api, http.MethodGet, "/computations/"+id, "")
+	if status != http.StatusOK || response["state"] != string(HandlePaused) {
+		t.Errorf("paused - got: %+v %+v, want: %+v %+v", status, response["state"], http.StatusOK, HandlePaused)
+	}
+
+	status, response = serveAPI(api, http.MethodDelete, "/computations/"+id, "")
+	expectedError := "can't forget computation '" + id + "' not ended"
+	if status != http.StatusConflict || response["error"] != expectedError {
+		t.Errorf("forget paused - got: %+v %+v, want: %+v %+v", status, response["error"], http.StatusConflict, expectedError)
+	}
+
+	status, response = serveAPI(api, http.MethodPost, "/computations/"+id+"/resume", `{"payload": "approved"}`)
+	if status != http.StatusOK || response["state"] != string(HandleSucceeded) {
+		t.Errorf("resume - got: %+v %+v, want: %+v %+v", status, response["state"], http.StatusOK, HandleSucceeded)
+	}
+	data := response["result"].(map[string]interface{})["data"]
+	expectedData := map[string]interface{}{"key": "value", "approval_payload": "approved"}
+	if !cmp.Equal(data, expectedData) {
+		t.Errorf("resumed data - got: %+v, want: %+v", data, expectedData)
+	}
+
+	status, response = serveAPI(api, http.MethodPost, "/computations/"+id+"/resume", `{"payload": "approved"}`)
+	expectedError = "can't resume computation '" + id + "' not paused on token ''"
+	if status != http.StatusConflict || response["error"] != expectedError {
+		t.Errorf("resume again - got: %+v %+v, want: %+v %+v", status, response["error"], http.StatusConflict, expectedError)
+	}
+
+	status, _ = serveAPI(api, http.MethodDelete, "/computations/"+id, "")
+	if status != http.StatusOK {
+		t.Errorf("forget - got: %+v, want: %+v", status, http.StatusOK)
+	}
+	status, response = serveAPI(api, http.MethodGet, "/computations/"+id, "")
+	expectedError = "can't find computation '" + id + "'"
+	if status != http.StatusNotFound || response["error"] != expectedError {
+		t.Errorf("forgotten - got: %+v %+v, want: %+v %+v", status, response["error"], http.StatusNotFound, expectedError)
+	}
+}
+
+func Test_APIHandler_ServeHTTP_Errors(t *testing.T) {
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(activatedNodeSystem())
+	api, _ := NewAPIHandler(eng)
+
+	testCases := []struct {
+		name           string
+		givenMethod    string
+		givenPath      string
+		givenBody      string
+		givenHeader    string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Can't serve an unknown route",
+			givenMethod:    http.MethodPut,
+			givenPath:      "/computations",
+			givenHeader:    "secret",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "can't find route PUT /computations",
+		},
+		{
+			name:           "Can't start a computation with an invalid body",
+			givenMethod:    http.MethodPost,
+			givenPath:      "/computations",
+			givenBody:      "{",
+			givenHeader:    "secret",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "can't read computation request: unexpected EOF",
+		},
+		{
+			name:           "Can't cancel an unknown computation",
+			givenMethod:    http.MethodPost,
+			givenPath:      "/computations/unknown/cancel",
+			givenHeader:    "secret",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "can't find computation 'unknown'",
+		},
+		{
+			name:           "Can't deliver an event on an unknown token",
+			givenMethod:    http.MethodPost,
+			givenPath:      "/events/unknown",
+			givenBody:      `{"payload": 1}`,
+			givenHeader:    "secret",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "can't find paused computation for token 'unknown'",
+		},
+		{
+			name:           "Can't serve an unauthenticated request",
+			givenMethod:    http.MethodGet,
+			givenPath:      "/computations",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "can't authenticate request without token",
+		},
+	}
+	api.ConfigureAuthenticator(authenticatorFunc(func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "secret" {
+			return errors.New("can't authenticate request without token")
+		}
+		return nil
+	}))
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(testCase.givenMethod, testCase.givenPath, strings.NewReader(testCase.givenBody))
+			request.Header.Set("Authorization", testCase.givenHeader)
+			api.ServeHTTP(recorder, request)
+			var response apiError
+			json.Unmarshal(recorder.Body.Bytes(), &response)
+
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("status - got: %+v, want: %+v", recorder.Code, testCase.expectedStatus)
+			}
+			if response.Error != testCase.expectedError {
+				t.Errorf("error - got: %+v, want: %+v", response.Error, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_APIHandler_ServeHTTP_tenant(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	api, _ := NewAPIHandler(eng)
+	api.ConfigureAuthenticator(tenantAuthenticator{})
+
+	serve := func(tenant, method, path, body string) (int, string) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Authorization", tenant)
+		api.ServeHTTP(recorder, request)
+		return recorder.Code, recorder.Body.String()
+	}
+
+	_, body := serve("tenant-a", http.MethodPost, "/computations", `{"data": {}, "tenant": "tenant-b"}`)
+	var started apiComputationStatus
+	json.Unmarshal([]byte(body), &started)
+	if started.Tenant != "tenant-a" {
+		t.Errorf("tenant - got: %+v, want: %+v", started.Tenant, "tenant-a")
+	}
+	computation, _ := api.computation(started.ID, "tenant-a")
+	<-computation.handle.Done()
+
+	var listed []apiComputationStatus
+	_, body = serve("tenant-b", http.MethodGet, "/computations", "")
+	json.Unmarshal([]byte(body), &listed)
+	if len(listed) != 0 {
+		t.Errorf("other tenant list - got: %+v, want: none", listed)
+	}
+	_, body = serve("tenant-a", http.MethodGet, "/computations", "")
+	json.Unmarshal([]byte(body), &listed)
+	if len(listed) != 1 || listed[0].ID != started.ID {
+		t.Errorf("tenant list - got: %+v, want: %+v", listed, started.ID)
+	}
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/computations/" + started.ID},
+		{http.MethodPost, "/computations/" + started.ID + "/cancel"},
+		{http.MethodPost, "/computations/" + started.ID + "/resume"},
+		{http.MethodDelete, "/computations/" + started.ID},
+	} {
+		status, _ := serve("tenant-b", route.method, route.path, `{"payload": 1}`)
+		if status != http.StatusNotFound {
+			t.Errorf("other tenant %v %v - got: %+v, want: %+v", route.method, route.path, status, http.StatusNotFound)
+		}
+	}
+	paused, _ := computation.handle.Wait(context.Background())
+	token := paused.PausedTokens()[0]
+	status, body := serve("tenant-b", http.MethodPost, "/events/"+token, `{"payload": "approved"}`)
+	expectedBody := `{"error":"can't find paused computation for token '` + token + `'"}`
+	if status != http.StatusNotFound || strings.TrimSpace(body) != expectedBody {
+		t.Errorf("other tenant event - got: %+v %v, want: %+v %v", status, body, http.StatusNotFound, expectedBody)
+	}
+	var resumed apiComputationStatus
+	status, body = serve("tenant-a", http.MethodPost, "/events/"+token, `{"payload": "approved"}`)
+	json.Unmarshal([]byte(body), &resumed)
+	if status != http.StatusOK || resumed.State != HandleSucceeded {
+		t.Errorf("tenant event - got: %+v %+v, want: %+v %+v", status, resumed.State, http.StatusOK, HandleSucceeded)
+	}
+}
@@ -0,0 +1,235 @@
+package hoff
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// ArchiveFormat is the format of an archive (or a compressed content).
+type ArchiveFormat string
+
+const (
+	// GzipFormat is a gzip compressed content
+	GzipFormat ArchiveFormat = "gzip"
+	// ZipFormat is a zip archive of files, kept in the context as a map of file names to contents
+	ZipFormat ArchiveFormat = "zip"
+)
+
+// defaultArchiveMaxSize is the default limit of the extracted content of an archive.
+const defaultArchiveMaxSize = 64 << 20
+
+// ArchiveNode is a type of Node who compress a context value into an archive, or extract it.
+// The archive is kept as bytes, the content of a file as a string, and for a zip archive,
+// the files are kept as a map of file names to contents.
+// A string or bytes value is archived as is, another value as indented JSON.
+type ArchiveNode struct {
+	name    string
+	format  ArchiveFormat
+	extract bool
+	source  string
+	target  string
+	maxSize int64
+}
+
+func (n ArchiveNode) String() string {
+	return n.name
+}
+
+// Compute archive or extract the context value, and store the result.
+func (n *ArchiveNode) Compute(c *Context) ComputeState {
+	value, found := c.Read(n.source)
+	if !found {
+		return NewAbortWithCodeComputeState(ValidationAbort, fmt.Errorf("can't %v of node '%v' with missing key: %v", n.action(), n.name, n.source))
+	}
+	var result interface{}
+	var err error
+	switch {
+	case n.extract && n.format == GzipFormat:
+		result, err = n.gunzip(value)
+	case n.extract:
+		result, err = n.unzip(value)
+	case n.format == GzipFormat:
+		result, err = n.gzip(value)
+	default:
+		result, err = n.zip(value)
+	}
+	if err != nil {
+		return NewAbortWithCodeComputeState(ValidationAbort, fmt.Errorf("can't %v of node '%v': %w", n.action(), n.name, err))
+	}
+	c.Store(n.target, result)
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that an archive don't take a decision.
+func (n *ArchiveNode) DecideCapability() bool {
+	return false
+}
+
+// RequiredKeys give the context key of the value to archive or extract.
+func (n *ArchiveNode) RequiredKeys() []string {
+	return []string{n.source}
+}
+
+// ProducedKeys give the context key of the result.
+func (n *ArchiveNode) ProducedKeys() []string {
+	return []string{n.target}
+}
+
+// ConfigureMaxSize limit the size of the extracted content of an archive (64 MiB by default),
+// to protect the computation against a decompression bomb.
+func (n *ArchiveNode) ConfigureMaxSize(maxSize int64) error {
+	if maxSize <= 0 {
+		return fmt.Errorf("can't configure max size not positive: %v", maxSize)
+	}
+	n.maxSize = maxSize
+	return nil
+}
+
+func (n *ArchiveNode) action() string {
+	if n.extract {
+		return "extract " + string(n.format)
+	}
+	return string(n.format)
+}
+
+func (n *ArchiveNode) gzip(value interface{}) ([]byte, error) {
+	content, err := encodeContent(value)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err = writer.Write(content)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	return buffer.Bytes(), err
+}
+
+func (n *ArchiveNode) gunzip(value interface{}) (string, error) {
+	archive, err := archiveBytes(value)
+	if err != nil {
+		return "", err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	content, err := n.readLimited(reader)
+	return string(content), err
+}
+
+func (n *ArchiveNode) zip(value interface{}) ([]byte, error) {
+	files, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("value not being a map of file names to contents: %T", value)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, name := range names {
+		content, err := encodeContent(files[name])
+		if err != nil {
+			return nil, fmt.Errorf("file '%v': %w", name, err)
+		}
+		file, err := writer.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("file '%v': %w", name, err)
+		}
+		_, err = file.Write(content)
+		if err != nil {
+			return nil, fmt.Errorf("file '%v': %w", name, err)
+		}
+	}
+	err := writer.Close()
+	return buffer.Bytes(), err
+}
+
+func (n *ArchiveNode) unzip(value interface{}) (map[string]interface{}, error) {
+	archive, err := archiveBytes(value)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]interface{})
+	var size int64
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		opened, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("file '%v': %w", file.Name, err)
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(opened, n.maxSize-size+1))
+		opened.Close()
+		if err != nil {
+			return nil, fmt.Errorf("file '%v': %w", file.Name, err)
+		}
+		size += int64(len(content))
+		if size > n.maxSize {
+			return nil, fmt.Errorf("file '%v': extracted content larger than %v bytes", file.Name, n.maxSize)
+		}
+		files[file.Name] = string(content)
+	}
+	return files, nil
+}
+
+// readLimited read a content up to the max size.
+func (n *ArchiveNode) readLimited(reader io.Reader) ([]byte, error) {
+	content, err := ioutil.ReadAll(io.LimitReader(reader, n.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > n.maxSize {
+		return nil, fmt.Errorf("extracted content larger than %v bytes", n.maxSize)
+	}
+	return content, nil
+}
+
+func archiveBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("archive not being bytes: %T", value)
+}
+
+// NewArchiveNode create an ArchiveNode who compress a context value into an archive of a format,
+// based on a name, the format, the context key of the value, and the context key of the archive.
+func NewArchiveNode(name string, format ArchiveFormat, source, target string) (*ArchiveNode, error) {
+	return newArchiveNode(name, format, false, source, target)
+}
+
+// NewExtractNode create an ArchiveNode who extract an archive of a format from a context value,
+// based on a name, the format, the context key of the archive, and the context key of the extracted content.
+func NewExtractNode(name string, format ArchiveFormat, source, target string) (*ArchiveNode, error) {
+	return newArchiveNode(name, format, true, source, target)
+}
+
+func newArchiveNode(name string, format ArchiveFormat, extract bool, source, target string) (*ArchiveNode, error) {
+	if format != GzipFormat && format != ZipFormat {
+		return nil, fmt.Errorf("can't create archive node with unknown format: %v", format)
+	}
+	if source == "" || target == "" {
+		return nil, errors.New("can't create archive node without source and target keys")
+	}
+	return &ArchiveNode{name: name, format: format, extract: extract, source: source, target: target, maxSize: defaultArchiveMaxSize}, nil
+}
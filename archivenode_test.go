@@ -0,0 +1,175 @@
+package hoff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewArchiveNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenFormat   ArchiveFormat
+		givenSource   string
+		expectedError error
+	}{
+		{
+			name:        "Can create an archive node",
+			givenFormat: ZipFormat,
+			givenSource: "files",
+		},
+		{
+			name:          "Can't create an archive node with unknown format",
+			givenFormat:   "rar",
+			givenSource:   "files",
+			expectedError: errors.New("can't create archive node with unknown format: rar"),
+		},
+		{
+			name:          "Can't create an archive node without source key",
+			givenFormat:   GzipFormat,
+			expectedError: errors.New("can't create archive node without source and target keys"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewArchiveNode("archive", testCase.givenFormat, testCase.givenSource, "archive")
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_ArchiveNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenFormat   ArchiveFormat
+		givenValue    interface{}
+		expectedValue interface{}
+	}{
+		{
+			name:          "Can gzip and extract a string",
+			givenFormat:   GzipFormat,
+			givenValue:    "id;total\n42;10\n",
+			expectedValue: "id;total\n42;10\n",
+		},
+		{
+			name:          "Can gzip and extract a value as JSON",
+			givenFormat:   GzipFormat,
+			givenValue:    map[string]interface{}{"total": 10},
+			expectedValue: "{\n  \"total\": 10\n}",
+		},
+		{
+			name:        "Can zip and extract files",
+			givenFormat: ZipFormat,
+			givenValue: map[string]interface{}{
+				"orders/42.csv":  "id;total\n42;10\n",
+				"orders/43.json": map[string]interface{}{"total": 20},
+				"empty.txt":      []byte{},
+			},
+			expectedValue: map[string]interface{}{
+				"orders/42.csv":  "id;total\n42;10\n",
+				"orders/43.json": "{\n  \"total\": 20\n}",
+				"empty.txt":      "",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			archive, _ := NewArchiveNode("archive", testCase.givenFormat, "content", "archive")
+			extract, _ := NewExtractNode("extract", testCase.givenFormat, "archive", "extracted")
+			c := NewContext(map[string]interface{}{"content": testCase.givenValue})
+
+			states := []ComputeState{archive.Compute(c), extract.Compute(c)}
+
+			expectedStates := []ComputeState{NewContinueComputeState(), NewContinueComputeState()}
+			if !cmp.Equal(states, expectedStates) {
+				t.Errorf("states - got: %+v, want: %+v", states, expectedStates)
+			}
+			if _, isBytes := c.Data["archive"].([]byte); !isBytes {
+				t.Errorf("archive - got: %T, want: %T", c.Data["archive"], []byte{})
+			}
+			if !cmp.Equal(c.Data["extracted"], testCase.expectedValue) {
+				t.Errorf("extracted - got: %+v, want: %+v", c.Data["extracted"], testCase.expectedValue)
+			}
+		})
+	}
+}
+
+func Test_ArchiveNode_Compute_failures(t *testing.T) {
+	large := strings.Repeat("a", 1024)
+
+	testCases := []struct {
+		name          string
+		givenNode     func() *ArchiveNode
+		givenData     map[string]interface{}
+		expectedState ComputeState
+	}{
+		{
+			name: "Can't zip a value not being a map of files",
+			givenNode: func() *ArchiveNode {
+				node, _ := NewArchiveNode("archive", ZipFormat, "content", "archive")
+				return node
+			},
+			givenData:     map[string]interface{}{"content": "text"},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't zip of node 'archive': value not being a map of file names to contents: string")),
+		},
+		{
+			name: "Can't extract an invalid archive",
+			givenNode: func() *ArchiveNode {
+				node, _ := NewExtractNode("extract", GzipFormat, "archive", "content")
+				return node
+			},
+			givenData:     map[string]interface{}{"archive": "text"},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't extract gzip of node 'extract': unexpected EOF")),
+		},
+		{
+			name: "Can't extract a missing archive",
+			givenNode: func() *ArchiveNode {
+				node, _ := NewExtractNode("extract", ZipFormat, "archive", "content")
+				return node
+			},
+			givenData:     map[string]interface{}{},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't extract zip of node 'extract' with missing key: archive")),
+		},
+		{
+			name: "Can't extract a gzip archive larger than the max size",
+			givenNode: func() *ArchiveNode {
+				node, _ := NewExtractNode("extract", GzipFormat, "archive", "content")
+				node.ConfigureMaxSize(1000)
+				return node
+			},
+			givenData:     map[string]interface{}{"archive": archiveOf(GzipFormat, large)},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't extract gzip of node 'extract': extracted content larger than 1000 bytes")),
+		},
+		{
+			name: "Can't extract a zip archive larger than the max size",
+			givenNode: func() *ArchiveNode {
+				node, _ := NewExtractNode("extract", ZipFormat, "archive", "content")
+				node.ConfigureMaxSize(1500)
+				return node
+			},
+			givenData:     map[string]interface{}{"archive": archiveOf(ZipFormat, map[string]interface{}{"a.txt": large, "b.txt": large})},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't extract zip of node 'extract': file 'b.txt': extracted content larger than 1500 bytes")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			state := testCase.givenNode().Compute(NewContext(testCase.givenData))
+
+			if errorMessage(state.Error) != errorMessage(testCase.expectedState.Error) || state.Value != testCase.expectedState.Value || state.Code != testCase.expectedState.Code {
+				t.Errorf("got: %+v, want: %+v", state, testCase.expectedState)
+			}
+		})
+	}
+}
+
+func archiveOf(format ArchiveFormat, value interface{}) []byte {
+	node, _ := NewArchiveNode("archive", format, "content", "archive")
+	c := NewContext(map[string]interface{}{"content": value})
+	node.Compute(c)
+	return c.Data["archive"].([]byte)
+}
@@ -0,0 +1,64 @@
+package hoff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String print the node system as an ASCII drawing (see RenderASCII).
+func (s *NodeSystem) String() string {
+	return s.RenderASCII()
+}
+
+// RenderASCII draw the node system as a tree in ASCII, from the nodes without ancestors,
+// with the branch of the links from a decision node between brackets.
+// A node with several ancestors is drawn under the first one, and marked as "(see above)" under the others.
+func (s *NodeSystem) RenderASCII() string {
+	incomingLinks := make(map[string]int)
+	for _, link := range s.links {
+		incomingLinks[s.nodeID(link.To)]++
+	}
+
+	var builder strings.Builder
+	drawn := make(map[string]bool)
+	for _, node := range s.nodes {
+		if incomingLinks[s.nodeID(node)] == 0 {
+			s.renderASCIINode(&builder, node, "", "", drawn)
+		}
+	}
+	for _, node := range s.nodes {
+		if !drawn[s.nodeID(node)] {
+			s.renderASCIINode(&builder, node, "", "", drawn)
+		}
+	}
+	return builder.String()
+}
+
+// renderASCIINode draw a node after its label, then its following nodes with the indentation.
+func (s *NodeSystem) renderASCIINode(builder *strings.Builder, node Node, label, indentation string, drawn map[string]bool) {
+	id := s.nodeID(node)
+	if drawn[id] {
+		fmt.Fprintf(builder, "%v%v (see above)\n", label, node)
+		return
+	}
+	drawn[id] = true
+	fmt.Fprintf(builder, "%v%v\n", label, node)
+
+	links := make([]nodeLink, 0)
+	for _, link := range s.links {
+		if s.sameNode(link.From, node) {
+			links = append(links, link)
+		}
+	}
+	for index, link := range links {
+		connector, childIndentation := "|-- ", indentation+"|   "
+		if index == len(links)-1 {
+			connector, childIndentation = "`-- ", indentation+"    "
+		}
+		branch := ""
+		if link.Branch != nil {
+			branch = fmt.Sprintf("[%v] ", *link.Branch)
+		}
+		s.renderASCIINode(builder, link.To, indentation+connector+branch, childIndentation, drawn)
+	}
+}
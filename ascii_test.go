@@ -0,0 +1,67 @@
+package hoff
+
+import (
+	"testing"
+)
+
+func Test_NodeSystem_RenderASCII(t *testing.T) {
+	action1, _ := NewActionNode("action1", func(*Context) error { return nil })
+	decision2, _ := NewDecisionNode("decision2", func(*Context) (bool, error) { return true, nil })
+	action3, _ := NewActionNode("action3", func(*Context) error { return nil })
+	action4, _ := NewActionNode("action4", func(*Context) error { return nil })
+	action5, _ := NewActionNode("action5", func(*Context) error { return nil })
+
+	linked := NewNodeSystem()
+	linked.AddNode(action1)
+	linked.AddNode(decision2)
+	linked.AddNode(action3)
+	linked.AddNode(action4)
+	linked.AddNode(action5)
+	linked.AddLink(action1, decision2)
+	linked.AddLinkOnBranch(decision2, action3, true)
+	linked.AddLinkOnBranch(decision2, action4, false)
+	linked.AddLink(action3, action5)
+	linked.AddLink(action4, action5)
+
+	unlinked := NewNodeSystem()
+	unlinked.AddNode(action1)
+	unlinked.AddNode(action3)
+
+	tests := []struct {
+		name           string
+		system         *NodeSystem
+		expectedString string
+	}{
+		{
+			name:           "Empty node system",
+			system:         NewNodeSystem(),
+			expectedString: "",
+		},
+		{
+			name:           "Unlinked nodes",
+			system:         unlinked,
+			expectedString: "action1\naction3\n",
+		},
+		{
+			name:   "Linked nodes with branches",
+			system: linked,
+			expectedString: "action1\n" +
+				"`-- decision2\n" +
+				"    |-- [true] action3\n" +
+				"    |   `-- action5\n" +
+				"    `-- [false] action4\n" +
+				"        `-- action5 (see above)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.system.RenderASCII()
+			if s != tt.expectedString {
+				t.Errorf("got: %+v, want: %+v", s, tt.expectedString)
+			}
+			if s := tt.system.String(); s != tt.expectedString {
+				t.Errorf("string - got: %+v, want: %+v", s, tt.expectedString)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package hoff
+
+// stagedWrite is a write of a node kept in the staging buffer of the context until the node end.
+type stagedWrite struct {
+	value   interface{}
+	deleted bool
+}
+
+// stagingBuffer hold the staged writes of a node computation,
+// and the context store values to delete once the writes are committed.
+type stagingBuffer struct {
+	writes     map[string]stagedWrite
+	references []string
+}
+
+// ConfigureAtomicWrites make the context writes of a node (see Context.Store and Context.Delete) visible
+// to the other nodes all at once when the node end, e.g. to never observe a partially written state
+// from a concurrent goroutine of the node. The writes are kept in a staging buffer of the node computation,
+// read by the node itself, then committed under the lock of the context data (see Context.Update).
+// The goroutines of a node need to end with it, a later write is not part of its staged writes.
+func (e *Engine) ConfigureAtomicWrites(enabled bool) {
+	e.atomicWrites = enabled
+}
+
+// stageNodeWrites is the interceptor staging the writes of a node, and committing them when it end.
+func stageNodeWrites(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	defer c.commitStaging(c.startStaging())
+	return compute(c)
+}
+
+// startStaging start a staging buffer for the writes of a node computation.
+func (c *Context) startStaging() *stagingBuffer {
+	locks := c.keyLocks()
+	locks.staging.Lock()
+	defer locks.staging.Unlock()
+	c.staged = &stagingBuffer{writes: make(map[string]stagedWrite)}
+	return c.staged
+}
+
+// commitStaging apply the staged writes of a node computation to the context data at once.
+func (c *Context) commitStaging(buffer *stagingBuffer) {
+	locks := c.keyLocks()
+	locks.staging.Lock()
+	if c.staged == buffer {
+		c.staged = nil
+	}
+	writes, references := buffer.writes, buffer.references
+	buffer.writes, buffer.references = nil, nil
+	locks.staging.Unlock()
+
+	locks.data.Lock()
+	defer locks.data.Unlock()
+	for key, write := range writes {
+		if write.deleted {
+			c.deleteValue(key)
+		} else {
+			c.storeValue(key, write.value)
+		}
+	}
+	for _, reference := range references {
+		c.store.Delete(reference)
+	}
+}
+
+// deleteReference remove a value of the context store by its reference,
+// once the staged writes are committed if the staging is started.
+func (c *Context) deleteReference(reference string) {
+	locks := c.keyLocks()
+	locks.staging.Lock()
+	if c.staged != nil {
+		c.staged.references = append(c.staged.references, reference)
+		locks.staging.Unlock()
+		return
+	}
+	locks.staging.Unlock()
+	c.store.Delete(reference)
+}
+
+// stage keep a write in the staging buffer, and tell if the staging is started.
+func (c *Context) stage(key string, write stagedWrite) bool {
+	locks := c.keyLocks()
+	locks.staging.Lock()
+	defer locks.staging.Unlock()
+	if c.staged == nil {
+		return false
+	}
+	c.staged.writes[key] = write
+	return true
+}
+
+// stagedValue give the staged write of a key, if any.
+func (c *Context) stagedValue(key string) (stagedWrite, bool) {
+	locks := c.keyLocks()
+	locks.staging.Lock()
+	defer locks.staging.Unlock()
+	if c.staged == nil {
+		return stagedWrite{}, false
+	}
+	write, found := c.staged.writes[key]
+	return write, found
+}
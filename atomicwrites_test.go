@@ -0,0 +1,140 @@
+package hoff
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigureAtomicWrites(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenAtomic     bool
+		expectedVisible map[string]interface{}
+	}{
+		{
+			name:            "Can stage the writes of a node until it end",
+			givenAtomic:     true,
+			expectedVisible: map[string]interface{}{"draft": "yes"},
+		},
+		{
+			name:            "Can write directly without atomic writes",
+			givenAtomic:     false,
+			expectedVisible: map[string]interface{}{"total": 42},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var visible map[string]interface{}
+			var read []interface{}
+			write, _ := NewActionNode("write", func(c *Context) error {
+				c.Store("total", 42)
+				c.Delete("draft")
+				visible = make(map[string]interface{})
+				for key, value := range c.Data {
+					visible[key] = value
+				}
+				total, _ := c.Read("total")
+				read = []interface{}{total, c.HaveKey("draft")}
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(write)
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigureAtomicWrites(testCase.givenAtomic)
+
+			result := eng.Compute(map[string]interface{}{"draft": "yes"})
+
+			if !cmp.Equal(visible, testCase.expectedVisible) {
+				t.Errorf("visible during compute - got: %+v, want: %+v", visible, testCase.expectedVisible)
+			}
+			expectedRead := []interface{}{42, false}
+			if !cmp.Equal(read, expectedRead) {
+				t.Errorf("read during compute - got: %+v, want: %+v", read, expectedRead)
+			}
+			expectedData := map[string]interface{}{"total": 42}
+			if !cmp.Equal(result.Data, expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", result.Data, expectedData)
+			}
+		})
+	}
+}
+
+func Test_Context_commitStaging(t *testing.T) {
+	c := NewContext(map[string]interface{}{"count": 1, "draft": "yes"})
+	buffer := c.startStaging()
+	c.Update("count", func(old interface{}, found bool) (interface{}, error) {
+		return old.(int) + 1, nil
+	})
+	c.Delete("draft")
+	c.Store("draft", "no")
+
+	if !cmp.Equal(c.Data, map[string]interface{}{"count": 1, "draft": "yes"}) {
+		t.Errorf("staged - got: %+v", c.Data)
+	}
+	c.commitStaging(buffer)
+	c.Store("other", true)
+
+	expected := map[string]interface{}{"count": 2, "draft": "no", "other": true}
+	if !cmp.Equal(c.Data, expected) {
+		t.Errorf("committed - got: %+v, want: %+v", c.Data, expected)
+	}
+}
+
+func Test_Engine_ConfigureAtomicWrites_goroutines(t *testing.T) {
+	for _, atomic := range []bool{true, false} {
+		t.Run(fmt.Sprintf("atomic writes %v", atomic), func(t *testing.T) {
+			fanOut, _ := NewActionNode("fanOut", func(c *Context) error {
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						c.Store(fmt.Sprint("key", i), i)
+						c.Read(fmt.Sprint("key", (i+1)%10))
+						c.HaveKey("other")
+						c.Delete("other")
+					}(i)
+				}
+				wg.Wait()
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(fanOut)
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigureAtomicWrites(atomic)
+
+			result := eng.Compute(map[string]interface{}{"other": true})
+
+			if result.Error != nil || len(result.Data) != 10 {
+				t.Errorf("got: %+v (error: %+v), want: 10 keys", result.Data, result.Error)
+			}
+		})
+	}
+}
+
+func Test_Context_commitStaging_afterEnd(t *testing.T) {
+	c := NewContextWithoutData()
+	buffer := c.startStaging()
+	c.Store("staged", true)
+	c.commitStaging(buffer)
+
+	next := c.startStaging()
+	c.commitStaging(buffer)
+	c.Store("next", true)
+	if c.HaveKey("next") != true || c.Data["next"] != nil {
+		t.Errorf("next - got: %+v, want: staged on the next buffer", c.Data)
+	}
+	c.commitStaging(next)
+
+	expected := map[string]interface{}{"staged": true, "next": true}
+	if !cmp.Equal(c.Data, expected) {
+		t.Errorf("got: %+v, want: %+v", c.Data, expected)
+	}
+}
@@ -0,0 +1,71 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is the error of a node aborted as its computation exceeded its budget.
+var ErrBudgetExceeded = errors.New("can't compute node, computation budget exceeded")
+
+// ComputationBudget limit the node executions of a computation, to stop a computation gone wrong
+// (e.g. retried nodes in a loop). A zero value disable the corresponding limit.
+// The node computed over the budget is aborted with BudgetExceededAbort as code, without computing it.
+type ComputationBudget struct {
+	// MaxNodeExecutions is the maximum number of node executions, each retry of a node counting as an execution
+	MaxNodeExecutions int
+	// MaxDuration is the maximum wall-clock time of the computation, checked before each node execution
+	MaxDuration time.Duration
+}
+
+func (b ComputationBudget) isZero() bool {
+	return b.MaxNodeExecutions == 0 && b.MaxDuration == 0
+}
+
+// ConfigureComputationBudget limit the node executions of each computation,
+// unless replaced by the budget of a submitted computation (see SubmitOptions.Budget).
+func (e *Engine) ConfigureComputationBudget(budget ComputationBudget) error {
+	if budget.MaxNodeExecutions < 0 || budget.MaxDuration < 0 {
+		return fmt.Errorf("can't configure budget with negative values: %+v", budget)
+	}
+	e.budget = budget
+	return nil
+}
+
+// computationBudget is the spent budget of a computation.
+type computationBudget struct {
+	budget ComputationBudget
+	start  time.Time
+
+	mu         sync.Mutex
+	executions int
+}
+
+// enforceBudget give the interceptor aborting the nodes of a computation once its budget is exceeded,
+// with CanceledByBudget as cancellation reason of the computation.
+func enforceBudget(cp *Computation, budget ComputationBudget) nodeInterceptor {
+	spent := &computationBudget{budget: budget, start: time.Now()}
+	return func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+		if err := spent.spend(); err != nil {
+			cp.recordCancellation(CanceledByBudget)
+			return NewAbortWithCodeComputeState(BudgetExceededAbort, fmt.Errorf("%w: %v", err, node))
+		}
+		return compute(c)
+	}
+}
+
+// spend count a node execution, or give the exceeded part of the budget.
+func (b *computationBudget) spend() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.budget.MaxDuration > 0 && time.Since(b.start) > b.budget.MaxDuration {
+		return fmt.Errorf("%w (over %v)", ErrBudgetExceeded, b.budget.MaxDuration)
+	}
+	if b.budget.MaxNodeExecutions > 0 && b.executions >= b.budget.MaxNodeExecutions {
+		return fmt.Errorf("%w (over %v node executions)", ErrBudgetExceeded, b.budget.MaxNodeExecutions)
+	}
+	b.executions++
+	return nil
+}
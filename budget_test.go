@@ -0,0 +1,128 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigureComputationBudget(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenBudget        ComputationBudget
+		givenSubmitBudget  *ComputationBudget
+		givenRetries       int
+		givenSlowness      time.Duration
+		expectedExecutions int
+		expectedStates     map[string]StateType
+		expectedError      string
+	}{
+		{
+			name:               "Can compute within the budget",
+			givenBudget:        ComputationBudget{MaxNodeExecutions: 3},
+			expectedExecutions: 3,
+			expectedStates:     map[string]StateType{"first": ContinueState, "second": ContinueState, "third": ContinueState},
+		},
+		{
+			name:               "Can't compute more node executions than the budget",
+			givenBudget:        ComputationBudget{MaxNodeExecutions: 2},
+			expectedExecutions: 2,
+			expectedStates:     map[string]StateType{"first": ContinueState, "second": ContinueState, "third": AbortState},
+			expectedError:      "can't compute node, computation budget exceeded (over 2 node executions): third",
+		},
+		{
+			name:               "Can't retry more node executions than the budget",
+			givenBudget:        ComputationBudget{MaxNodeExecutions: 4},
+			givenRetries:       5,
+			expectedExecutions: 4,
+			expectedStates:     map[string]StateType{"first": ContinueState, "second": ContinueState, "third": AbortState},
+			expectedError:      "can't compute node, computation budget exceeded (over 4 node executions): third",
+		},
+		{
+			name:               "Can't compute longer than the budget",
+			givenBudget:        ComputationBudget{MaxDuration: 5 * time.Millisecond},
+			givenSlowness:      10 * time.Millisecond,
+			expectedExecutions: 1,
+			expectedStates:     map[string]StateType{"first": ContinueState, "second": AbortState},
+			expectedError:      "can't compute node, computation budget exceeded (over 5ms): second",
+		},
+		{
+			name:               "Can replace the budget on submission",
+			givenBudget:        ComputationBudget{MaxNodeExecutions: 1},
+			givenSubmitBudget:  &ComputationBudget{},
+			expectedExecutions: 3,
+			expectedStates:     map[string]StateType{"first": ContinueState, "second": ContinueState, "third": ContinueState},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			executions := 0
+			first, _ := NewActionNode("first", func(*Context) error {
+				executions++
+				time.Sleep(testCase.givenSlowness)
+				return nil
+			})
+			second, _ := NewActionNode("second", func(*Context) error {
+				executions++
+				return nil
+			})
+			third, _ := NewActionNode("third", func(*Context) error {
+				executions++
+				if testCase.givenRetries > 0 {
+					return errors.New("unavailable")
+				}
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(first)
+			ns.AddNode(second)
+			ns.AddNode(third)
+			ns.AddLink(first, second)
+			ns.AddLink(second, third)
+			ns.ConfigureTagsOnNode(third, "flaky")
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigurePolicyOnTag("flaky", TagPolicy{Retries: testCase.givenRetries})
+			err := eng.ConfigureComputationBudget(testCase.givenBudget)
+			if err != nil {
+				t.Errorf("configure error - got: %+v, want: nothing", err)
+			}
+
+			result, _ := eng.Submit(nil, SubmitOptions{Budget: testCase.givenSubmitBudget}).Wait(context.Background())
+
+			if executions != testCase.expectedExecutions {
+				t.Errorf("executions - got: %+v, want: %+v", executions, testCase.expectedExecutions)
+			}
+			states := make(map[string]StateType)
+			var budgetErr string
+			for node, state := range result.Report {
+				states[fmt.Sprint(node)] = state.Value
+				if state.Code == BudgetExceededAbort {
+					budgetErr = state.Error.Error()
+					if !errors.Is(state.Error, ErrBudgetExceeded) {
+						t.Errorf("error - got: %+v, want: %+v", state.Error, ErrBudgetExceeded)
+					}
+				}
+			}
+			if !cmp.Equal(states, testCase.expectedStates) {
+				t.Errorf("states - got: %+v, want: %+v", states, testCase.expectedStates)
+			}
+			if budgetErr != testCase.expectedError {
+				t.Errorf("budget error - got: %+v, want: %+v", budgetErr, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureComputationBudget_negative(t *testing.T) {
+	err := NewEngine(SequentialComputation).ConfigureComputationBudget(ComputationBudget{MaxNodeExecutions: -1})
+	expectedError := errors.New("can't configure budget with negative values: {MaxNodeExecutions:-1 MaxDuration:0s}")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -0,0 +1,66 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// CancellationReason tell why a computation was interrupted before its end,
+// e.g. to distinguish an operator cancel from a SLA timeout.
+type CancellationReason string
+
+const (
+	// NotCanceled is the reason of a computation not interrupted
+	NotCanceled CancellationReason = ""
+	// CanceledByUser is the reason of a computation canceled through its handle (see Handle.Cancel)
+	CanceledByUser CancellationReason = "user"
+	// CanceledByTimeout is the reason of a computation whose context reached its deadline
+	CanceledByTimeout CancellationReason = "timeout"
+	// CanceledByContext is the reason of a computation whose context was canceled
+	CanceledByContext CancellationReason = "context"
+	// CanceledByShutdown is the reason of a computation interrupted by the shutdown of its engine
+	CanceledByShutdown CancellationReason = "shutdown"
+	// CanceledByBudget is the reason of a computation who exceeded its budget (see ComputationBudget)
+	CanceledByBudget CancellationReason = "budget"
+)
+
+// cancellationReasons are the reasons by their index, stored as int32 on the computation
+var cancellationReasons = []CancellationReason{NotCanceled, CanceledByUser, CanceledByTimeout, CanceledByContext, CanceledByShutdown, CanceledByBudget}
+
+// cancel interrupt the computation for a reason, the first reason being kept.
+func (cp *Computation) cancel(reason CancellationReason) {
+	cp.recordCancellation(reason)
+	cp.interrupt()
+}
+
+// recordCancellation keep the reason of the end of the computation, unless it already have one.
+func (cp *Computation) recordCancellation(reason CancellationReason) {
+	for index, known := range cancellationReasons {
+		if known == reason {
+			atomic.CompareAndSwapInt32(&cp.cancellation, 0, int32(index))
+			return
+		}
+	}
+}
+
+// cancellationReason give the reason of the interruption of the computation, if any.
+func (cp *Computation) cancellationReason() CancellationReason {
+	return cancellationReasons[atomic.LoadInt32(&cp.cancellation)]
+}
+
+// contextCancellationReason give the reason of a done context.
+func contextCancellationReason(ctx context.Context) CancellationReason {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return CanceledByTimeout
+	}
+	return CanceledByContext
+}
+
+// AddCleanupWithReason register a function to release a resource once the computation end (see AddCleanup),
+// called with the reason of the interruption of the computation, or NotCanceled.
+func (c *Context) AddCleanupWithReason(cleanup func(reason CancellationReason)) {
+	c.AddCleanup(func() {
+		cleanup(c.cancellation)
+	})
+}
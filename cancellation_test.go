@@ -0,0 +1,118 @@
+package hoff
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_ComputationResult_Cancellation(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenCompute func(eng *Engine, started, release chan struct{}) ComputationResult
+		expected     CancellationReason
+	}{
+		{
+			name: "Can end without cancellation",
+			givenCompute: func(eng *Engine, started, release chan struct{}) ComputationResult {
+				close(release)
+				return eng.Compute(nil)
+			},
+			expected: NotCanceled,
+		},
+		{
+			name: "Can be canceled by user",
+			givenCompute: func(eng *Engine, started, release chan struct{}) ComputationResult {
+				handle := eng.Submit(nil, SubmitOptions{})
+				<-started
+				handle.Cancel()
+				close(release)
+				result, _ := handle.Wait(context.Background())
+				return result
+			},
+			expected: CanceledByUser,
+		},
+		{
+			name: "Can be canceled by timeout",
+			givenCompute: func(eng *Engine, started, release chan struct{}) ComputationResult {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+				return eng.ComputeWithContext(ctx, nil)
+			},
+			expected: CanceledByTimeout,
+		},
+		{
+			name: "Can be canceled by context",
+			givenCompute: func(eng *Engine, started, release chan struct{}) ComputationResult {
+				ctx, cancel := context.WithCancel(context.Background())
+				go func() {
+					<-started
+					cancel()
+				}()
+				return eng.ComputeWithContext(ctx, nil)
+			},
+			expected: CanceledByContext,
+		},
+		{
+			name: "Can be canceled by shutdown",
+			givenCompute: func(eng *Engine, started, release chan struct{}) ComputationResult {
+				handle := eng.Submit(nil, SubmitOptions{})
+				<-started
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				go func() {
+					time.Sleep(time.Millisecond)
+					close(release)
+				}()
+				eng.Shutdown(ctx)
+				result, _ := handle.Wait(context.Background())
+				return result
+			},
+			expected: CanceledByShutdown,
+		},
+		{
+			name: "Can be canceled by budget",
+			givenCompute: func(eng *Engine, started, release chan struct{}) ComputationResult {
+				close(release)
+				eng.ConfigureComputationBudget(ComputationBudget{MaxNodeExecutions: 1})
+				return eng.Compute(nil)
+			},
+			expected: CanceledByBudget,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			started, release := make(chan struct{}), make(chan struct{})
+			var cleanupReason CancellationReason
+			first, _ := NewActionNode("first", func(c *Context) error {
+				c.AddCleanupWithReason(func(reason CancellationReason) {
+					cleanupReason = reason
+				})
+				close(started)
+				select {
+				case <-release:
+				case <-c.GoContext().Done():
+					// let the engine interrupt the computation
+					time.Sleep(10 * time.Millisecond)
+				}
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(first)
+			ns.AddNode(someActionNode)
+			ns.AddLink(first, someActionNode)
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+
+			result := testCase.givenCompute(eng, started, release)
+
+			if result.Cancellation != testCase.expected {
+				t.Errorf("cancellation - got: %+v, want: %+v", result.Cancellation, testCase.expected)
+			}
+			if cleanupReason != testCase.expected {
+				t.Errorf("cleanup reason - got: %+v, want: %+v", cleanupReason, testCase.expected)
+			}
+		})
+	}
+}
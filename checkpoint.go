@@ -0,0 +1,358 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrCheckpointNotFound is the error of a CheckpointStore without the requested checkpoint.
+	ErrCheckpointNotFound = errors.New("can't find checkpoint")
+	// ErrCheckpointConflict is the error of a CheckpointStore when a checkpoint is saved
+	// from an outdated version, like when another engine resumed the computation before.
+	ErrCheckpointConflict = errors.New("can't save checkpoint, updated since loaded")
+)
+
+// Checkpoint is the persisted state of a computation, to resume it on another engine or after a restart.
+type Checkpoint struct {
+	Report ComputationReport
+	// Completed tell if the computation is ended, neither paused nor interrupted
+	Completed bool
+	// Version is increased on each save, 0 for a checkpoint never saved
+	Version int64
+	Updated time.Time
+}
+
+// CheckpointStore persist the checkpoints of the computations, with optimistic locking on their version.
+type CheckpointStore interface {
+	// Save store a checkpoint if its version is the stored one (or 0 for a new checkpoint),
+	// and give the checkpoint with its new version, or ErrCheckpointConflict.
+	Save(checkpoint Checkpoint) (Checkpoint, error)
+	// Load give the checkpoint of a computation, or ErrCheckpointNotFound.
+	Load(id string) (Checkpoint, error)
+	// FindPaused give the checkpoint of the computation paused on a token, or ErrCheckpointNotFound.
+	FindPaused(token string) (Checkpoint, error)
+	// Cleanup remove the checkpoints of the completed computations updated before a time,
+	// and give the number of removed checkpoints.
+	Cleanup(before time.Time) (int, error)
+}
+
+// ConfigureCheckpointStore add a store to save a checkpoint of each ended, paused, or interrupted computation.
+// A paused computation can then be resumed by Deliver on another engine (or after a restart),
+// and an interrupted one by ResumeCheckpoint.
+// The context values are restored from JSON (e.g. a number become a float64),
+// except the secrets kept encrypted in the checkpoints (see Context.StoreSecret)
+// and the references to the values of the context store (see Context.StoreExternal), restored as such.
+func (e *Engine) ConfigureCheckpointStore(store CheckpointStore) {
+	e.checkpointStore = store
+}
+
+// ResumeCheckpoint continue an interrupted computation from its checkpoint.
+func (e *Engine) ResumeCheckpoint(id string) ComputationResult {
+	if e.checkpointStore == nil {
+		return ComputationResult{ID: id, Error: errors.New("can't resume checkpoint without checkpoint store")}
+	}
+	checkpoint, err := e.checkpointStore.Load(id)
+	if err != nil {
+		return ComputationResult{ID: id, Error: fmt.Errorf("can't resume computation '%v': %w", id, err)}
+	}
+	if checkpoint.Completed {
+		return ComputationResult{ID: id, Error: fmt.Errorf("can't resume completed computation '%v'", id)}
+	}
+	cp, err := e.restoreComputation(checkpoint)
+	if err != nil {
+		return ComputationResult{ID: id, Error: err}
+	}
+	unlock, err := e.lockComputation(cp)
+	if err != nil {
+		return newComputationResult(cp, err)
+	}
+	defer unlock()
+	err = e.claimCheckpoint(cp)
+	if err != nil {
+		return newComputationResult(cp, err)
+	}
+
+	err = e.startComputation(cp)
+	if err != nil {
+		return newComputationResult(cp, err)
+	}
+	defer e.endComputation(cp)
+
+	start := time.Now()
+	err = cp.Continue()
+	return e.endResult(cp, err, start)
+}
+
+// findPausedCheckpoint give the computation paused on a token from its checkpoint.
+func (e *Engine) findPausedCheckpoint(token string) (pausedComputation, bool, error) {
+	checkpoint, err := e.checkpointStore.FindPaused(token)
+	if errors.Is(err, ErrCheckpointNotFound) {
+		return pausedComputation{}, false, nil
+	}
+	if err != nil {
+		return pausedComputation{}, false, err
+	}
+	cp, err := e.restoreComputation(checkpoint)
+	if err != nil {
+		return pausedComputation{}, false, err
+	}
+	for node, state := range cp.Report {
+		if state.Value == PauseState && state.Token == token {
+			return pausedComputation{computation: cp, node: node}, true, nil
+		}
+	}
+	return pausedComputation{}, false, nil
+}
+
+// restoreComputation create a computation from its checkpoint.
+func (e *Engine) restoreComputation(checkpoint Checkpoint) (*Computation, error) {
+	report := checkpoint.Report
+	if report.Fingerprint != e.system.Fingerprint() {
+		return nil, fmt.Errorf("can't restore checkpoint '%v' of another node system", report.ID)
+	}
+	data, err := decodeCheckpointData(report.Data, report.Types)
+	if err != nil {
+		return nil, err
+	}
+	cp, err := NewComputation(e.system, NewContext(data))
+	if err != nil {
+		return nil, err
+	}
+	cp.ID = report.ID
+	cp.Context.computationID = report.ID
+	cp.Context.setSeed(report.Seed)
+	cp.checkpointVersion = checkpoint.Version
+	cp.tenant = report.Tenant
+
+	cp.Report, err = e.restoreNodeStates(report.States)
+	if err != nil {
+		return nil, fmt.Errorf("can't restore checkpoint '%v' with %w", report.ID, err)
+	}
+	completedNodes := int32(0)
+	for _, state := range cp.Report {
+		if state.Value != PauseState {
+			completedNodes++
+		}
+	}
+	atomic.StoreInt32(&cp.completedNodes, completedNodes)
+
+	e.prepareComputation(cp, SubmitOptions{Tenant: report.Tenant})
+	return cp, nil
+}
+
+// restoreNodeStates give the compute states of the nodes from their reports.
+func (e *Engine) restoreNodeStates(stateReports []NodeStateReport) (map[Node]ComputeState, error) {
+	nodes := make(map[string]Node, len(e.system.nodes))
+	for _, node := range e.system.nodes {
+		nodes[fmt.Sprint(node)] = node
+	}
+	states := make(map[Node]ComputeState, len(stateReports))
+	for _, stateReport := range stateReports {
+		node, found := nodes[stateReport.Node]
+		if !found {
+			return nil, fmt.Errorf("unknown node: %v", stateReport.Node)
+		}
+		state := ComputeState{
+			Value:    stateReport.State,
+			Branch:   stateReport.Branch,
+			Code:     stateReport.Code,
+			Token:    stateReport.Token,
+			Override: stateReport.Override,
+		}
+		if stateReport.Error != "" {
+			state.Error = restoreError(stateReport.Error, stateReport.Causes)
+		}
+		states[e.system.nodeMapKey(node)] = state
+	}
+	return states, nil
+}
+
+// claimCheckpoint save the checkpoint of a computation before resuming it,
+// to fail when another engine resumed it first.
+func (e *Engine) claimCheckpoint(cp *Computation) error {
+	err := e.saveCheckpoint(cp, newComputationResult(cp, nil), false)
+	if err != nil {
+		return fmt.Errorf("can't resume computation '%v': %w", cp.ID, err)
+	}
+	return nil
+}
+
+// saveCheckpoint save the checkpoint of a computation and keep its new version.
+func (e *Engine) saveCheckpoint(cp *Computation, result ComputationResult, completed bool) error {
+	report, err := newEncodableComputationReport(result)
+	if err != nil {
+		return err
+	}
+	report.Data, report.Types, err = encodeCheckpointData(result.Data)
+	if err != nil {
+		return err
+	}
+	checkpoint, err := e.checkpointStore.Save(Checkpoint{
+		Report:    report,
+		Completed: completed,
+		Version:   cp.checkpointVersion,
+		Updated:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	cp.checkpointVersion = checkpoint.Version
+	return nil
+}
+
+// secretValueType and referenceValueType are the types of a secret value, and of a context reference, of a checkpoint.
+const (
+	secretValueType    = "secret"
+	referenceValueType = "reference"
+)
+
+// encodeCheckpointData give the JSON-encoded context values of a checkpoint and the types of its typed values,
+// a secret being kept as its ciphertext instead of being redacted like in the reports, and a context reference as is,
+// and a value who can't be encoded in JSON as its string representation.
+func encodeCheckpointData(data map[string]interface{}) (map[string][]byte, map[string]string, error) {
+	encodedData := make(map[string][]byte, len(data))
+	var types map[string]string
+	for key, value := range data {
+		encodedValue, typ, err := encodeTypedValue(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't encode context value of '%v': %w", key, err)
+		}
+		encodedData[key] = encodedValue
+		if typ != "" {
+			if types == nil {
+				types = make(map[string]string)
+			}
+			types[key] = typ
+		}
+	}
+	return encodedData, types, nil
+}
+
+// encodeTypedValue give the JSON-encoded context value and its type (see encodeCheckpointData).
+func encodeTypedValue(value interface{}) ([]byte, string, error) {
+	typ := ""
+	switch typedValue := value.(type) {
+	case Secret:
+		value, typ = typedValue.Ciphertext, secretValueType
+	case ContextReference:
+		typ = referenceValueType
+	}
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		encodedValue, err = json.Marshal(fmt.Sprint(value))
+	}
+	return encodedValue, typ, err
+}
+
+// decodeCheckpointData give the context values of a checkpoint, with its typed values restored.
+func decodeCheckpointData(encodedData map[string][]byte, types map[string]string) (map[string]interface{}, error) {
+	data, err := decodeContextData(encodedData)
+	if err != nil {
+		return nil, err
+	}
+	for key, typ := range types {
+		data[key], err = decodeTypedValue(key, encodedData[key], typ)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// decodeTypedValue give a typed context value from its JSON encoding.
+func decodeTypedValue(key string, encodedValue []byte, typ string) (interface{}, error) {
+	switch typ {
+	case secretValueType:
+		var ciphertext []byte
+		err := json.Unmarshal(encodedValue, &ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode secret of '%v': %w", key, err)
+		}
+		return Secret{Ciphertext: ciphertext}, nil
+	case referenceValueType:
+		var reference ContextReference
+		err := json.Unmarshal(encodedValue, &reference)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode context reference of '%v': %w", key, err)
+		}
+		return reference, nil
+	default:
+		return nil, fmt.Errorf("can't decode context value of '%v' with unknown type '%v'", key, typ)
+	}
+}
+
+// MemoryCheckpointStore is a CheckpointStore keeping the checkpoints in memory, mainly for testing.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore create an empty in memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+// Save store a checkpoint if its version is the stored one (or 0 for a new checkpoint).
+func (s *MemoryCheckpointStore) Save(checkpoint Checkpoint) (Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, found := s.checkpoints[checkpoint.Report.ID]
+	if (found && stored.Version != checkpoint.Version) || (!found && checkpoint.Version != 0) {
+		return Checkpoint{}, ErrCheckpointConflict
+	}
+	checkpoint.Version++
+	if checkpoint.Updated.IsZero() {
+		checkpoint.Updated = time.Now()
+	}
+	s.checkpoints[checkpoint.Report.ID] = checkpoint
+	return checkpoint, nil
+}
+
+// Load give the checkpoint of a computation.
+func (s *MemoryCheckpointStore) Load(id string) (Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint, found := s.checkpoints[id]
+	if !found {
+		return Checkpoint{}, ErrCheckpointNotFound
+	}
+	return checkpoint, nil
+}
+
+// FindPaused give the checkpoint of the computation paused on a token.
+func (s *MemoryCheckpointStore) FindPaused(token string) (Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.Completed {
+			continue
+		}
+		for _, state := range checkpoint.Report.States {
+			if state.State == PauseState && state.Token == token {
+				return checkpoint, nil
+			}
+		}
+	}
+	return Checkpoint{}, ErrCheckpointNotFound
+}
+
+// Cleanup remove the checkpoints of the completed computations updated before a time.
+func (s *MemoryCheckpointStore) Cleanup(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for id, checkpoint := range s.checkpoints {
+		if checkpoint.Completed && checkpoint.Updated.Before(before) {
+			delete(s.checkpoints, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
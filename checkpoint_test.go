@@ -0,0 +1,265 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_MemoryCheckpointStore(t *testing.T) {
+	store := NewMemoryCheckpointStore()
+	updated := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	paused := Checkpoint{
+		Report:  ComputationReport{ID: "1", States: []NodeStateReport{{Node: "approval", State: PauseState, Token: "token"}}},
+		Updated: updated,
+	}
+
+	saved, err := store.Save(paused)
+	if err != nil || saved.Version != 1 {
+		t.Errorf("save - got: %+v %+v, want: version 1", saved, err)
+	}
+	_, err = store.Save(paused)
+	if err != ErrCheckpointConflict {
+		t.Errorf("save again - got: %+v, want: %+v", err, ErrCheckpointConflict)
+	}
+
+	found, err := store.FindPaused("token")
+	if err != nil || !cmp.Equal(found, saved) {
+		t.Errorf("find paused - got: %+v %+v, want: %+v", found, err, saved)
+	}
+
+	completed := saved
+	completed.Completed = true
+	completed.Report.States = []NodeStateReport{{Node: "approval", State: ContinueState}}
+	completed, err = store.Save(completed)
+	if err != nil || completed.Version != 2 {
+		t.Errorf("save completed - got: %+v %+v, want: version 2", completed, err)
+	}
+	_, err = store.Save(saved)
+	if err != ErrCheckpointConflict {
+		t.Errorf("save outdated - got: %+v, want: %+v", err, ErrCheckpointConflict)
+	}
+	_, err = store.FindPaused("token")
+	if err != ErrCheckpointNotFound {
+		t.Errorf("find completed - got: %+v, want: %+v", err, ErrCheckpointNotFound)
+	}
+
+	removed, _ := store.Cleanup(updated)
+	if removed != 0 {
+		t.Errorf("cleanup before update - got: %+v, want: %+v", removed, 0)
+	}
+	removed, _ = store.Cleanup(updated.Add(time.Second))
+	if removed != 1 {
+		t.Errorf("cleanup after update - got: %+v, want: %+v", removed, 1)
+	}
+	_, err = store.Load("1")
+	if err != ErrCheckpointNotFound {
+		t.Errorf("load removed - got: %+v, want: %+v", err, ErrCheckpointNotFound)
+	}
+}
+
+func Test_Engine_ConfigureCheckpointStore_Deliver(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.AddNode(someActionNode)
+	ns.AddLink(approval, someActionNode)
+	ns.ActivateInPlace()
+
+	store := NewMemoryCheckpointStore()
+	firstEngine := NewEngine(SequentialComputation)
+	firstEngine.ConfigureNodeSystem(ns)
+	firstEngine.ConfigureCheckpointStore(store)
+	secondEngine := NewEngine(SequentialComputation)
+	secondEngine.ConfigureNodeSystem(ns)
+	secondEngine.ConfigureCheckpointStore(store)
+
+	paused := firstEngine.Compute(map[string]interface{}{"count": 1})
+	token := paused.PausedTokens()[0]
+
+	result := secondEngine.Deliver(token, "approved")
+	expectedResult := ComputationResult{
+		ID:   paused.ID,
+		Data: map[string]interface{}{"count": float64(1), "approval_payload": "approved"},
+		Report: map[Node]ComputeState{
+			approval:       NewContinueComputeState(),
+			someActionNode: NewContinueComputeState(),
+		},
+		Success: true,
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator, cmp.FilterPath(func(p cmp.Path) bool {
+		return p.String() == "Fingerprint" || p.String() == "Durations" || p.String() == "Seed"
+	}, cmp.Ignore())) {
+		t.Errorf("resumed on another engine - got: %+v, want: %+v", result, expectedResult)
+	}
+
+	result = firstEngine.Deliver(token, "approved")
+	expectedError := fmt.Errorf("can't resume computation '%v': %w", paused.ID, ErrCheckpointConflict)
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("resumed twice - got: %+v, want: %+v", result.Error, expectedError)
+	}
+
+	checkpoint, _ := store.Load(paused.ID)
+	if !checkpoint.Completed || checkpoint.Version != 3 {
+		t.Errorf("checkpoint - got: %+v, want: completed on version 3", checkpoint)
+	}
+}
+
+func Test_Engine_ConfigureCheckpointStore_Deliver_secret(t *testing.T) {
+	storeSecret, _ := NewActionNode("storeSecret", func(c *Context) error {
+		return c.StoreSecret("password", "s3cr3t")
+	})
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	readSecret, _ := NewActionNode("readSecret", func(c *Context) error {
+		password, err := c.ReadSecret("password")
+		if err != nil {
+			return err
+		}
+		c.Store("password_read", password == "s3cr3t")
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(storeSecret)
+	ns.AddNode(approval)
+	ns.AddNode(readSecret)
+	ns.AddLink(storeSecret, approval)
+	ns.AddLink(approval, readSecret)
+	ns.ActivateInPlace()
+
+	cipher, _ := NewAESCipher([]byte("0123456789abcdef"))
+	store := NewMemoryCheckpointStore()
+	firstEngine := NewEngine(SequentialComputation)
+	firstEngine.ConfigureNodeSystem(ns)
+	firstEngine.ConfigureCipher(cipher)
+	firstEngine.ConfigureCheckpointStore(store)
+	secondEngine := NewEngine(SequentialComputation)
+	secondEngine.ConfigureNodeSystem(ns)
+	secondEngine.ConfigureCipher(cipher)
+	secondEngine.ConfigureCheckpointStore(store)
+
+	paused := firstEngine.Compute(map[string]interface{}{})
+	checkpoint, _ := store.Load(paused.ID)
+	if string(checkpoint.Report.Data["password"]) == `"[REDACTED]"` || checkpoint.Report.Types["password"] != "secret" {
+		t.Errorf("checkpoint - got: %+v, want: encrypted secret", checkpoint.Report)
+	}
+
+	result := secondEngine.Deliver(paused.PausedTokens()[0], "approved")
+	if !result.Success || result.Data["password_read"] != true {
+		t.Errorf("resumed - got: %+v, want: secret read", result)
+	}
+	report, _ := newEncodableComputationReport(result)
+	if string(report.Data["password"]) != `"[REDACTED]"` {
+		t.Errorf("report - got: %s, want: redacted secret", report.Data["password"])
+	}
+}
+
+func Test_Engine_ConfigureCheckpointStore_Deliver_reference(t *testing.T) {
+	storeDocument, _ := NewActionNode("storeDocument", func(c *Context) error {
+		return c.StoreExternal("document", "content")
+	})
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	readDocument, _ := NewActionNode("readDocument", func(c *Context) error {
+		document, _ := c.Read("document")
+		c.Store("document_read", document)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(storeDocument)
+	ns.AddNode(approval)
+	ns.AddNode(readDocument)
+	ns.AddLink(storeDocument, approval)
+	ns.AddLink(approval, readDocument)
+	ns.ActivateInPlace()
+
+	contextStore := NewMemoryContextStore()
+	store := NewMemoryCheckpointStore()
+	firstEngine := NewEngine(SequentialComputation)
+	firstEngine.ConfigureNodeSystem(ns)
+	firstEngine.ConfigureContextStore(contextStore)
+	firstEngine.ConfigureCheckpointStore(store)
+	secondEngine := NewEngine(SequentialComputation)
+	secondEngine.ConfigureNodeSystem(ns)
+	secondEngine.ConfigureContextStore(contextStore)
+	secondEngine.ConfigureCheckpointStore(store)
+
+	paused := firstEngine.Compute(map[string]interface{}{})
+	result := secondEngine.Deliver(paused.PausedTokens()[0], "approved")
+	if !result.Success || result.Data["document_read"] != "content" {
+		t.Errorf("resumed - got: %+v, want: document read", result)
+	}
+	if _, isReference := result.Data["document"].(ContextReference); !isReference {
+		t.Errorf("reference - got: %T, want: %T", result.Data["document"], ContextReference{})
+	}
+}
+
+func Test_Engine_ResumeCheckpoint(t *testing.T) {
+	ns := activatedNodeSystem()
+	store := NewMemoryCheckpointStore()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureCheckpointStore(store)
+
+	store.Save(Checkpoint{Report: ComputationReport{
+		ID:          "interrupted",
+		Fingerprint: ns.Fingerprint(),
+		States:      []NodeStateReport{{Node: "someActionNode", State: ContinueState}},
+	}})
+	store.Save(Checkpoint{Report: ComputationReport{ID: "completed", Fingerprint: ns.Fingerprint()}, Completed: true})
+	store.Save(Checkpoint{Report: ComputationReport{ID: "other", Fingerprint: "other"}})
+	store.Save(Checkpoint{Report: ComputationReport{
+		ID:          "unknown",
+		Fingerprint: ns.Fingerprint(),
+		States:      []NodeStateReport{{Node: "unknownNode", State: ContinueState}},
+	}})
+
+	testCases := []struct {
+		name           string
+		givenID        string
+		expectedReport map[Node]ComputeState
+		expectedError  error
+	}{
+		{
+			name:    "Can resume an interrupted computation",
+			givenID: "interrupted",
+			expectedReport: map[Node]ComputeState{
+				someActionNode:    NewContinueComputeState(),
+				anotherActionNode: NewContinueComputeState(),
+			},
+		},
+		{
+			name:          "Can't resume a completed computation",
+			givenID:       "completed",
+			expectedError: errors.New("can't resume completed computation 'completed'"),
+		},
+		{
+			name:          "Can't resume a computation of another node system",
+			givenID:       "other",
+			expectedError: errors.New("can't restore checkpoint 'other' of another node system"),
+		},
+		{
+			name:          "Can't resume a computation with an unknown node",
+			givenID:       "unknown",
+			expectedError: fmt.Errorf("can't restore checkpoint 'unknown' with %w", errors.New("unknown node: unknownNode")),
+		},
+		{
+			name:          "Can't resume a missing computation",
+			givenID:       "missing",
+			expectedError: fmt.Errorf("can't resume computation 'missing': %w", ErrCheckpointNotFound),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := eng.ResumeCheckpoint(testCase.givenID)
+
+			if !cmp.Equal(result.Error, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.expectedError)
+			}
+			if testCase.expectedReport != nil && !cmp.Equal(result.Report, testCase.expectedReport, NodeComparator) {
+				t.Errorf("report - got: %+v, want: %+v", result.Report, testCase.expectedReport)
+			}
+		})
+	}
+}
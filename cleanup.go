@@ -0,0 +1,45 @@
+package hoff
+
+// AddCleanup register a function to release a resource (close a file, release a lock) once the computation end,
+// on success, abort, or interruption, but not while paused.
+// The cleanup functions are run by the engine in the reverse order of their registration,
+// and a panicking cleanup function don't stop the others.
+// The cleanup functions are lost when a paused computation is resumed from its checkpoint on another engine.
+func (c *Context) AddCleanup(cleanup func()) {
+	c.cleanups = append(c.cleanups, cleanup)
+}
+
+// runCleanups run and forget the registered cleanup functions.
+func (c *Context) runCleanups() {
+	cleanups := c.cleanups
+	c.cleanups = nil
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		runCleanup(cleanups[i])
+	}
+}
+
+// resource give a value kept by a node for the computation.
+func (c *Context) resource(key interface{}) (interface{}, bool) {
+	value, found := c.resources[key]
+	return value, found
+}
+
+// keepResource keep a value for the computation, released by a cleanup function if needed.
+func (c *Context) keepResource(key, value interface{}) {
+	if c.resources == nil {
+		c.resources = make(map[interface{}]interface{})
+	}
+	c.resources[key] = value
+}
+
+// forgetResource forget a value kept for the computation.
+func (c *Context) forgetResource(key interface{}) {
+	delete(c.resources, key)
+}
+
+func runCleanup(cleanup func()) {
+	defer func() {
+		recover()
+	}()
+	cleanup()
+}
@@ -0,0 +1,75 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Context_AddCleanup(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenActionError error
+	}{
+		{
+			name: "Can run the cleanup functions of a successful computation",
+		},
+		{
+			name:             "Can run the cleanup functions of an aborted computation",
+			givenActionError: errors.New("action failure"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cleaned := make([]string, 0)
+			openingAction, _ := NewActionNode("openingAction", func(c *Context) error {
+				c.AddCleanup(func() { cleaned = append(cleaned, "file") })
+				c.AddCleanup(func() { panic("can't close") })
+				c.AddCleanup(func() { cleaned = append(cleaned, "lock") })
+				return testCase.givenActionError
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(openingAction)
+			ns.ActivateInPlace()
+
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			result := eng.Compute(map[string]interface{}{})
+
+			if !cmp.Equal(result.Error, testCase.givenActionError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.givenActionError)
+			}
+			expectedCleaned := []string{"lock", "file"}
+			if !cmp.Equal(cleaned, expectedCleaned) {
+				t.Errorf("cleaned - got: %+v, want: %+v", cleaned, expectedCleaned)
+			}
+		})
+	}
+}
+
+func Test_Context_AddCleanup_whilePaused(t *testing.T) {
+	cleaned := 0
+	openingAction, _ := NewActionNode("openingAction", func(c *Context) error {
+		c.AddCleanup(func() { cleaned++ })
+		return nil
+	})
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(openingAction)
+	ns.AddNode(approval)
+	ns.AddLink(openingAction, approval)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	paused := eng.Compute(map[string]interface{}{})
+	if cleaned != 0 {
+		t.Errorf("cleaned while paused - got: %+v, want: %+v", cleaned, 0)
+	}
+
+	eng.Deliver(paused.PausedTokens()[0], "approved")
+	if cleaned != 1 {
+		t.Errorf("cleaned once resumed - got: %+v, want: %+v", cleaned, 1)
+	}
+}
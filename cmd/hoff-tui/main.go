@@ -0,0 +1,323 @@
+// Command hoff-tui run a workflow step by step in the terminal.
+//
+// The workflow file is a node system description, as written by NodeSystem.ExportJSON.
+// Before each node, the graph position and the context contents are shown,
+// and the operator choose to run, skip or abort the node. A decision node ask for its branch,
+// unless it evaluate an expression.
+// The required inputs of the workflow missing from the data are asked before the computation.
+//
+// Usage:
+//
+//	hoff-tui [-data data.json] workflow.json
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rlespinasse/hoff"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+func main() {
+	dataFile := flag.String("data", "", "JSON file with the initial context data")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: hoff-tui [-data data.json] workflow.json")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := run(flag.Arg(0), *dataFile, os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(workflowFile, dataFile string, in io.Reader, out io.Writer) error {
+	var description hoff.SystemDescription
+	err := readJSONFile(workflowFile, &description)
+	if err != nil {
+		return fmt.Errorf("can't read workflow: %v", err)
+	}
+	data := make(map[string]interface{})
+	if dataFile != "" {
+		err = readJSONFile(dataFile, &data)
+		if err != nil {
+			return fmt.Errorf("can't read data: %v", err)
+		}
+	}
+
+	ui := newTUI(description, in, out)
+	system, err := ui.nodeSystem()
+	if err != nil {
+		return err
+	}
+	err = ui.askInputs(data)
+	if err != nil {
+		return err
+	}
+	engine := hoff.NewEngine(hoff.SequentialComputation)
+	err = engine.ConfigureNodeSystem(system)
+	if err != nil {
+		return err
+	}
+	engine.ConfigureStepper(ui)
+	engine.AddEventSink(ui)
+
+	result := engine.Compute(data)
+	for node, state := range result.Report {
+		ui.states[fmt.Sprint(node)] = state
+	}
+	ui.render(nil, result.Data)
+	fmt.Fprintf(ui.out, "computation ended, success: %v\n", result.IsSuccess())
+	if result.Error != nil {
+		fmt.Fprintf(ui.out, "error: %v\n", result.Error)
+	}
+	return nil
+}
+
+func readJSONFile(name string, value interface{}) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(value)
+}
+
+// tui is the stepper and the event sink of the computation.
+type tui struct {
+	description hoff.SystemDescription
+	in          *bufio.Scanner
+	out         io.Writer
+	runAll      bool
+
+	mu     sync.Mutex
+	states map[string]hoff.ComputeState
+}
+
+func newTUI(description hoff.SystemDescription, in io.Reader, out io.Writer) *tui {
+	return &tui{
+		description: description,
+		in:          bufio.NewScanner(in),
+		out:         out,
+		states:      make(map[string]hoff.ComputeState),
+	}
+}
+
+// nodeSystem create the activated node system of the description,
+// with no-op action nodes (or transform nodes) and decision nodes asking the operator for their branch
+// (or evaluating their expression).
+func (ui *tui) nodeSystem() (*hoff.NodeSystem, error) {
+	nodes := make(map[string]hoff.Node, len(ui.description.Nodes))
+	system := hoff.NewNodeSystem()
+	for _, description := range ui.description.Nodes {
+		name := description.Name
+		var node hoff.Node
+		var err error
+		if description.Expression != "" {
+			node, err = hoff.NewExpressionDecisionNode(name, description.Expression)
+		} else if len(description.Transforms) > 0 {
+			node, err = hoff.NewTransformNode(name, description.Transforms...)
+		} else if description.Decision {
+			node, err = hoff.NewDecisionNode(name, func(*hoff.Context) (bool, error) {
+				return ui.askBranch(name)
+			})
+		} else {
+			node, err = hoff.NewActionNode(name, func(*hoff.Context) error { return nil })
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes[name] = node
+
+		_, err = system.AddNode(node)
+		if err != nil {
+			return nil, err
+		}
+		if description.JoinMode != "" {
+			_, err = system.ConfigureJoinModeOnNode(node, description.JoinMode)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(description.Tags) > 0 {
+			_, err = system.ConfigureTagsOnNode(node, description.Tags...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, link := range ui.description.Links {
+		from, to := nodes[link.From], nodes[link.To]
+		var err error
+		if link.Branch == nil {
+			_, err = system.AddLink(from, to)
+		} else {
+			_, err = system.AddLinkOnBranch(from, to, *link.Branch)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, input := range ui.description.Inputs {
+		_, err := system.DeclareInput(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, output := range ui.description.Outputs {
+		_, err := system.DeclareOutput(output)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := system.ActivateInPlace()
+	if err != nil {
+		return nil, err
+	}
+	return system, nil
+}
+
+// Handle record the compute state of the ended nodes.
+func (ui *tui) Handle(event hoff.Event) {
+	if event.Type != hoff.NodeEndedEvent {
+		return
+	}
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.states[fmt.Sprint(event.Node)] = event.State
+}
+
+// Step show the graph position and the context contents, and ask the operator what to do with the node.
+func (ui *tui) Step(node hoff.Node, c *hoff.Context) hoff.StepAction {
+	if ui.runAll {
+		return hoff.StepRun
+	}
+	for {
+		ui.render(node, c.Data)
+		fmt.Fprint(ui.out, "[r]un, [s]kip, [a]bort, run [c]ontinuously, set key=value > ")
+		if !ui.in.Scan() {
+			fmt.Fprintln(ui.out)
+			return hoff.StepAbort
+		}
+		answer := strings.TrimSpace(ui.in.Text())
+		switch {
+		case answer == "r" || answer == "":
+			return hoff.StepRun
+		case answer == "s":
+			return hoff.StepSkip
+		case answer == "a":
+			return hoff.StepAbort
+		case answer == "c":
+			ui.runAll = true
+			return hoff.StepRun
+		case strings.HasPrefix(answer, "set "):
+			assignment := strings.SplitN(strings.TrimPrefix(answer, "set "), "=", 2)
+			if len(assignment) == 2 {
+				c.Store(strings.TrimSpace(assignment[0]), parseValue(strings.TrimSpace(assignment[1])))
+			}
+		}
+	}
+}
+
+// askInputs ask the operator the value of the required inputs missing from the data.
+func (ui *tui) askInputs(data map[string]interface{}) error {
+	for _, input := range ui.description.Inputs {
+		if _, found := data[input.Key]; found || !input.Required {
+			continue
+		}
+		description := ""
+		if input.Description != "" {
+			description = ", " + input.Description
+		}
+		fmt.Fprintf(ui.out, "input '%v' (%v%v) > ", input.Key, input.Type, description)
+		if !ui.in.Scan() {
+			fmt.Fprintln(ui.out)
+			return fmt.Errorf("can't read input '%v' without answer", input.Key)
+		}
+		data[input.Key] = parseValue(strings.TrimSpace(ui.in.Text()))
+	}
+	return nil
+}
+
+// askBranch ask the operator the branch taken by a decision node.
+func (ui *tui) askBranch(name string) (bool, error) {
+	for {
+		fmt.Fprintf(ui.out, "branch of '%v' [t]rue or [f]alse > ", name)
+		if !ui.in.Scan() {
+			fmt.Fprintln(ui.out)
+			return false, fmt.Errorf("can't decide branch of '%v' without answer", name)
+		}
+		switch strings.TrimSpace(ui.in.Text()) {
+		case "t":
+			return true, nil
+		case "f":
+			return false, nil
+		}
+	}
+}
+
+// render show the nodes with their state, the current node, and the context contents.
+func (ui *tui) render(current hoff.Node, data map[string]interface{}) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	var builder strings.Builder
+	builder.WriteString(clearScreen)
+	builder.WriteString("nodes:\n")
+	for _, node := range ui.description.Nodes {
+		marker := " "
+		if current != nil && fmt.Sprint(current) == node.Name {
+			marker = ">"
+		}
+		state := "pending"
+		if computeState, found := ui.states[node.Name]; found {
+			state = computeState.String()
+		}
+		fmt.Fprintf(&builder, "%v %v: %v\n", marker, node.Name, state)
+		for _, link := range ui.description.Links {
+			if link.From != node.Name {
+				continue
+			}
+			branch := ""
+			if link.Branch != nil {
+				branch = fmt.Sprintf(" (%v)", *link.Branch)
+			}
+			fmt.Fprintf(&builder, "    -> %v%v\n", link.To, branch)
+		}
+	}
+
+	builder.WriteString("context:\n")
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&builder, "  %v = %v\n", key, data[key])
+	}
+	io.WriteString(ui.out, builder.String())
+}
+
+// parseValue give the JSON value of a text, or the text itself.
+func parseValue(text string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return text
+	}
+	return value
+}
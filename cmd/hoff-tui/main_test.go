@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hoff-tui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	workflowFile := filepath.Join(dir, "workflow.json")
+	ioutil.WriteFile(workflowFile, []byte(`{
+  "nodes": [
+    {"name": "key is present", "decision": true},
+    {"name": "read key"},
+    {"name": "write key"}
+  ],
+  "links": [
+    {"from": "key is present", "to": "read key", "branch": true},
+    {"from": "key is present", "to": "write key", "branch": false}
+  ]
+}`), 0644)
+	dataFile := filepath.Join(dir, "data.json")
+	ioutil.WriteFile(dataFile, []byte(`{"key": "value"}`), 0644)
+
+	testCases := []struct {
+		name           string
+		givenInput     string
+		expectedOutput []string
+	}{
+		{
+			name:       "Can run nodes step by step",
+			givenInput: "set count=2\nr\nt\nr\nr\n",
+			expectedOutput: []string{
+				"> key is present: pending",
+				"    -> read key (true)",
+				"  count = 2",
+				"  key = value",
+				"> read key: pending",
+				"  key is present: 'Continue on true'",
+				"  write key: 'Skip'",
+				"computation ended, success: true",
+			},
+		},
+		{
+			name:       "Can abort a node",
+			givenInput: "a\n",
+			expectedOutput: []string{
+				"  key is present: 'Abort on can't compute node, aborted by stepper'",
+				"computation ended, success: false",
+				"error: can't compute node, aborted by stepper",
+			},
+		},
+		{
+			name:       "Can run continuously",
+			givenInput: "c\nf\n",
+			expectedOutput: []string{
+				"  write key: 'Continue'",
+				"computation ended, success: true",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := run(workflowFile, dataFile, strings.NewReader(testCase.givenInput), &output)
+
+			if err != nil {
+				t.Errorf("error - got: %+v, want: %+v", err, nil)
+			}
+			for _, expectedLine := range testCase.expectedOutput {
+				if !strings.Contains(output.String(), expectedLine+"\n") {
+					t.Errorf("got: %v, want line: %v", output.String(), expectedLine)
+				}
+			}
+		})
+	}
+}
+
+func Test_run_inputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hoff-tui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	workflowFile := filepath.Join(dir, "workflow.json")
+	ioutil.WriteFile(workflowFile, []byte(`{
+  "nodes": [
+    {"name": "greet"}
+  ],
+  "links": [],
+  "inputs": [
+    {"key": "name", "type": "string", "required": true, "description": "name to greet"},
+    {"key": "count", "type": "number", "default": 1}
+  ]
+}`), 0644)
+
+	var output bytes.Buffer
+	err = run(workflowFile, "", strings.NewReader("\"bob\"\nc\n"), &output)
+
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	for _, expectedLine := range []string{
+		"  count = 1",
+		"  name = bob",
+		"computation ended, success: true",
+	} {
+		if !strings.Contains(output.String(), expectedLine+"\n") {
+			t.Errorf("got: %v, want line: %v", output.String(), expectedLine)
+		}
+	}
+	if !strings.Contains(output.String(), "input 'name' (string, name to greet) > ") {
+		t.Errorf("got: %v, want input form of: %v", output.String(), "name")
+	}
+}
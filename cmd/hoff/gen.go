@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rlespinasse/hoff"
+)
+
+// generate give the formatted Go code of a node system description.
+func generate(description hoff.SystemDescription, packageName, source string) ([]byte, error) {
+	identifiers := make(map[string]string, len(description.Nodes))
+	names := make(map[string]string, len(description.Nodes))
+	for _, node := range description.Nodes {
+		identifier := exportedIdentifier(node.Name)
+		if identifier == "" {
+			return nil, fmt.Errorf("can't name node '%v' in go", node.Name)
+		}
+		if other, found := names[identifier]; found {
+			return nil, fmt.Errorf("can't name nodes '%v' and '%v' in go with the same identifier: %v", other, node.Name, identifier)
+		}
+		names[identifier] = node.Name
+		identifiers[node.Name] = identifier
+	}
+
+	keys, err := contextKeys(description)
+	if err != nil {
+		return nil, err
+	}
+
+	var code bytes.Buffer
+	fmt.Fprintf(&code, "// Code generated by hoff gen from %v. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&code, "package %v\n\n", packageName)
+	provided := false
+	for _, node := range description.Nodes {
+		provided = provided || !isGeneratedNode(node)
+	}
+	if provided {
+		fmt.Fprintf(&code, "import (\n\"errors\"\n\n\"github.com/rlespinasse/hoff\"\n)\n\n")
+	} else {
+		fmt.Fprintf(&code, "import \"github.com/rlespinasse/hoff\"\n\n")
+	}
+
+	if len(keys) > 0 {
+		fmt.Fprintf(&code, "// Context keys of the workflow.\nconst (\n")
+		for _, key := range keys {
+			fmt.Fprintf(&code, "Key%v = %q\n", exportedIdentifier(key), key)
+		}
+		fmt.Fprintf(&code, ")\n\n")
+	}
+
+	fmt.Fprintf(&code, "// Nodes hold the nodes of the workflow to provide.\ntype Nodes struct {\n")
+	for _, node := range description.Nodes {
+		if !isGeneratedNode(node) {
+			fmt.Fprintf(&code, "// %v is the %v '%v'\n%v hoff.Node\n", identifiers[node.Name], kindOf(node), node.Name, identifiers[node.Name])
+		}
+	}
+	fmt.Fprintf(&code, "}\n\n")
+
+	nodeRef := func(name string) (string, error) {
+		identifier, found := identifiers[name]
+		if !found {
+			return "", fmt.Errorf("can't link unknown node: %v", name)
+		}
+		for _, node := range description.Nodes {
+			if node.Name == name && isGeneratedNode(node) {
+				return "node" + identifier, nil
+			}
+		}
+		return "nodes." + identifier, nil
+	}
+
+	fmt.Fprintf(&code, "// NewNodeSystem create the node system of the workflow with its nodes, to be activated.\n")
+	fmt.Fprintf(&code, "func NewNodeSystem(nodes Nodes) (*hoff.NodeSystem, error) {\n")
+	for _, node := range description.Nodes {
+		identifier := identifiers[node.Name]
+		if isGeneratedNode(node) {
+			continue
+		}
+		capability := ""
+		if node.Decision {
+			capability = "!"
+		}
+		fmt.Fprintf(&code, "if nodes.%v == nil || %vnodes.%v.DecideCapability() {\n", identifier, capability, identifier)
+		fmt.Fprintf(&code, "return nil, errors.New(%q)\n}\n", fmt.Sprintf("can't create node system without %v: %v", kindOf(node), node.Name))
+	}
+	for _, node := range description.Nodes {
+		identifier := identifiers[node.Name]
+		if node.Expression != "" {
+			fmt.Fprintf(&code, "node%v, err := hoff.NewExpressionDecisionNode(%q, %q)\n", identifier, node.Name, node.Expression)
+			fmt.Fprintf(&code, "if err != nil {\nreturn nil, err\n}\n")
+		} else if len(node.Transforms) > 0 {
+			fmt.Fprintf(&code, "node%v, err := hoff.NewTransformNode(%q,\n", identifier, node.Name)
+			for _, transform := range node.Transforms {
+				fmt.Fprintf(&code, "hoff.Transform{Key: Key%v, Expression: %q},\n", exportedIdentifier(transform.Key), transform.Expression)
+			}
+			fmt.Fprintf(&code, ")\nif err != nil {\nreturn nil, err\n}\n")
+		}
+	}
+
+	calls := make([]string, 0)
+	for _, node := range description.Nodes {
+		ref, _ := nodeRef(node.Name)
+		calls = append(calls, fmt.Sprintf("ns.AddNode(%v)", ref))
+	}
+	for _, link := range description.Links {
+		from, err := nodeRef(link.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := nodeRef(link.To)
+		if err != nil {
+			return nil, err
+		}
+		if link.Branch != nil {
+			calls = append(calls, fmt.Sprintf("ns.AddLinkOnBranch(%v, %v, %v)", from, to, *link.Branch))
+		} else {
+			calls = append(calls, fmt.Sprintf("ns.AddLink(%v, %v)", from, to))
+		}
+	}
+	for _, node := range description.Nodes {
+		ref, _ := nodeRef(node.Name)
+		switch node.JoinMode {
+		case "", hoff.JoinNone:
+		case hoff.JoinAnd:
+			calls = append(calls, fmt.Sprintf("ns.ConfigureJoinModeOnNode(%v, hoff.JoinAnd)", ref))
+		case hoff.JoinOr:
+			calls = append(calls, fmt.Sprintf("ns.ConfigureJoinModeOnNode(%v, hoff.JoinOr)", ref))
+		default:
+			return nil, fmt.Errorf("can't configure unknown join mode on node '%v': %v", node.Name, node.JoinMode)
+		}
+		if len(node.Tags) > 0 {
+			tags := make([]string, len(node.Tags))
+			for i, tag := range node.Tags {
+				tags[i] = fmt.Sprintf("%q", tag)
+			}
+			calls = append(calls, fmt.Sprintf("ns.ConfigureTagsOnNode(%v, %v)", ref, strings.Join(tags, ", ")))
+		}
+	}
+	for _, input := range description.Inputs {
+		fields := parameterFields(input.Key, input.Type, input.Required, input.Description)
+		if input.Default != nil {
+			fields = append(fields, fmt.Sprintf("Default: %#v", input.Default))
+		}
+		calls = append(calls, fmt.Sprintf("ns.DeclareInput(hoff.InputParameter{%v})", strings.Join(fields, ", ")))
+	}
+	for _, output := range description.Outputs {
+		fields := parameterFields(output.Key, output.Type, output.Required, output.Description)
+		calls = append(calls, fmt.Sprintf("ns.DeclareOutput(hoff.OutputParameter{%v})", strings.Join(fields, ", ")))
+	}
+
+	fmt.Fprintf(&code, "ns := hoff.NewNodeSystem()\n")
+	for _, call := range calls {
+		fmt.Fprintf(&code, "if _, err := %v; err != nil {\nreturn nil, err\n}\n", call)
+	}
+	fmt.Fprintf(&code, "return ns, nil\n}\n")
+
+	formatted, err := format.Source(code.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can't format generated code: %v", err)
+	}
+	return formatted, nil
+}
+
+// contextKeys give the sorted context keys of the inputs, outputs, transforms, and expressions of a description.
+func contextKeys(description hoff.SystemDescription) ([]string, error) {
+	found := make(map[string]bool)
+	for _, input := range description.Inputs {
+		found[input.Key] = true
+	}
+	for _, output := range description.Outputs {
+		found[output.Key] = true
+	}
+	for _, node := range description.Nodes {
+		expressions := make([]string, 0)
+		if node.Expression != "" {
+			expressions = append(expressions, node.Expression)
+		}
+		for _, transform := range node.Transforms {
+			found[transform.Key] = true
+			expressions = append(expressions, transform.Expression)
+		}
+		for _, source := range expressions {
+			expression, err := hoff.ParseExpression(source)
+			if err != nil {
+				return nil, fmt.Errorf("can't read keys of node '%v': %v", node.Name, err)
+			}
+			for _, key := range expression.Keys() {
+				found[key] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(found))
+	identifiers := make(map[string]string, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		identifier := exportedIdentifier(key)
+		if identifier == "" {
+			return nil, fmt.Errorf("can't name context key '%v' in go", key)
+		}
+		if other, found := identifiers[identifier]; found {
+			return nil, fmt.Errorf("can't name context keys '%v' and '%v' in go with the same identifier: Key%v", other, key, identifier)
+		}
+		identifiers[identifier] = key
+	}
+	return keys, nil
+}
+
+// parameterFields give the not empty fields of an input or output parameter.
+func parameterFields(key string, parameterType hoff.InputType, required bool, description string) []string {
+	fields := []string{fmt.Sprintf("Key: Key%v", exportedIdentifier(key))}
+	if parameterType != "" {
+		fields = append(fields, fmt.Sprintf("Type: %q", parameterType))
+	}
+	if required {
+		fields = append(fields, "Required: true")
+	}
+	if description != "" {
+		fields = append(fields, fmt.Sprintf("Description: %q", description))
+	}
+	return fields
+}
+
+// isGeneratedNode tell if a node is created by the generated code, instead of provided.
+func isGeneratedNode(node hoff.NodeDescription) bool {
+	return node.Expression != "" || len(node.Transforms) > 0
+}
+
+func kindOf(node hoff.NodeDescription) string {
+	if node.Decision {
+		return "decision node"
+	}
+	return "action node"
+}
+
+// exportedIdentifier give an exported go identifier of a name, e.g. "validate order" give ValidateOrder.
+func exportedIdentifier(name string) string {
+	var identifier strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if identifier.Len() == 0 && unicode.IsDigit(r) {
+			identifier.WriteRune('N')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		identifier.WriteRune(r)
+	}
+	return identifier.String()
+}
+
+func readJSONFile(name string, value interface{}) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(value)
+}
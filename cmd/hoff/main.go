@@ -0,0 +1,73 @@
+// Command hoff is the tooling of hoff workflows.
+//
+// The gen command emit Go code from a workflow file, a node system description as written by NodeSystem.ExportJSON,
+// with the context keys as constants, and a typed wiring of the nodes to provide
+// (see the generated Nodes type and NewNodeSystem function).
+// The expression decision nodes and transform nodes of the description are created by the generated code.
+//
+// Usage:
+//
+//	hoff gen [-package name] [-o output.go] workflow.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/rlespinasse/hoff"
+)
+
+const usage = "usage: hoff gen [-package name] [-o output.go] workflow.json"
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	if err == flag.ErrHelp {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "gen" {
+		fmt.Fprintln(out, usage)
+		return flag.ErrHelp
+	}
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(out)
+	packageName := flags.String("package", "workflow", "package of the generated code")
+	outputFile := flags.String("o", "", "file of the generated code, standard output if empty")
+	flags.Usage = func() {
+		fmt.Fprintln(out, usage)
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	var description hoff.SystemDescription
+	err = readJSONFile(flags.Arg(0), &description)
+	if err != nil {
+		return fmt.Errorf("can't read workflow: %v", err)
+	}
+	code, err := generate(description, *packageName, filepath.Base(flags.Arg(0)))
+	if err != nil {
+		return fmt.Errorf("can't generate code: %v", err)
+	}
+	if *outputFile == "" {
+		_, err = out.Write(code)
+		return err
+	}
+	return ioutil.WriteFile(*outputFile, code, 0644)
+}
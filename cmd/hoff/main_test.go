@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rlespinasse/hoff"
+)
+
+func Test_run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hoff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	workflowFile := filepath.Join(dir, "workflow.json")
+	ioutil.WriteFile(workflowFile, []byte(`{
+  "nodes": [
+    {"name": "compute total", "transforms": [{"key": "total", "expression": "price * quantity"}]},
+    {"name": "is expensive", "decision": true, "expression": "total > 100"},
+    {"name": "ask approval", "decision": true},
+    {"name": "charge", "join_mode": "or", "tags": ["payment"]}
+  ],
+  "links": [
+    {"from": "compute total", "to": "is expensive"},
+    {"from": "is expensive", "to": "ask approval", "branch": true},
+    {"from": "is expensive", "to": "charge", "branch": false},
+    {"from": "ask approval", "to": "charge", "branch": true}
+  ],
+  "inputs": [{"key": "price", "type": "number", "required": true}, {"key": "quantity", "type": "number", "default": 1}],
+  "outputs": [{"key": "total", "type": "number", "description": "total price"}]
+}`), 0644)
+
+	var output bytes.Buffer
+	err = run([]string{"gen", "-package", "orders", workflowFile}, &output)
+
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	for _, expectedLine := range []string{
+		"// Code generated by hoff gen from workflow.json. DO NOT EDIT.",
+		"package orders",
+		"\tKeyPrice    = \"price\"",
+		"\tKeyQuantity = \"quantity\"",
+		"\tKeyTotal    = \"total\"",
+		"\t// AskApproval is the decision node 'ask approval'",
+		"\tAskApproval hoff.Node",
+		"\tCharge hoff.Node",
+		"\tif nodes.AskApproval == nil || !nodes.AskApproval.DecideCapability() {",
+		"\t\treturn nil, errors.New(\"can't create node system without decision node: ask approval\")",
+		"\tif nodes.Charge == nil || nodes.Charge.DecideCapability() {",
+		"\tnodeComputeTotal, err := hoff.NewTransformNode(\"compute total\",",
+		"\t\thoff.Transform{Key: KeyTotal, Expression: \"price * quantity\"},",
+		"\tnodeIsExpensive, err := hoff.NewExpressionDecisionNode(\"is expensive\", \"total > 100\")",
+		"\tif _, err := ns.AddLinkOnBranch(nodeIsExpensive, nodes.AskApproval, true); err != nil {",
+		"\tif _, err := ns.ConfigureJoinModeOnNode(nodes.Charge, hoff.JoinOr); err != nil {",
+		"\tif _, err := ns.ConfigureTagsOnNode(nodes.Charge, \"payment\"); err != nil {",
+		"\tif _, err := ns.DeclareInput(hoff.InputParameter{Key: KeyQuantity, Type: \"number\", Default: 1}); err != nil {",
+		"\tif _, err := ns.DeclareOutput(hoff.OutputParameter{Key: KeyTotal, Type: \"number\", Description: \"total price\"}); err != nil {",
+	} {
+		if !strings.Contains(output.String(), expectedLine+"\n") {
+			t.Errorf("got: %v, want line: %v", output.String(), expectedLine)
+		}
+	}
+
+	outputFile := filepath.Join(dir, "orders_gen.go")
+	err = run([]string{"gen", "-package", "orders", "-o", outputFile, workflowFile}, ioutil.Discard)
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if content, _ := ioutil.ReadFile(outputFile); !bytes.Equal(content, output.Bytes()) {
+		t.Errorf("file - got: %v, want: %v", string(content), output.String())
+	}
+}
+
+func Test_generate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenNodes    []hoff.NodeDescription
+		givenLinks    []hoff.LinkDescription
+		expectedError error
+	}{
+		{
+			name:       "Can generate without provided node",
+			givenNodes: []hoff.NodeDescription{{Name: "check", Decision: true, Expression: "count > 1"}},
+		},
+		{
+			name:          "Can't generate with nodes of the same identifier",
+			givenNodes:    []hoff.NodeDescription{{Name: "send mail"}, {Name: "send-mail"}},
+			expectedError: errors.New("can't name nodes 'send mail' and 'send-mail' in go with the same identifier: SendMail"),
+		},
+		{
+			name:          "Can't generate with a node without identifier",
+			givenNodes:    []hoff.NodeDescription{{Name: "-"}},
+			expectedError: errors.New("can't name node '-' in go"),
+		},
+		{
+			name:          "Can't generate with a link to an unknown node",
+			givenNodes:    []hoff.NodeDescription{{Name: "send"}},
+			givenLinks:    []hoff.LinkDescription{{From: "send", To: "log"}},
+			expectedError: errors.New("can't link unknown node: log"),
+		},
+		{
+			name:          "Can't generate with an invalid expression",
+			givenNodes:    []hoff.NodeDescription{{Name: "check", Decision: true, Expression: "count >"}},
+			expectedError: errors.New("can't read keys of node 'check': can't parse expression 'count >': unexpected end of expression"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			code, err := generate(hoff.SystemDescription{Nodes: testCase.givenNodes, Links: testCase.givenLinks}, "flow", "flow.json")
+
+			if !cmp.Equal(err, testCase.expectedError, cmp.Comparer(func(x, y error) bool {
+				return (x == nil && y == nil) || (x != nil && y != nil && x.Error() == y.Error())
+			})) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && !strings.Contains(string(code), "import \"github.com/rlespinasse/hoff\"\n") {
+				t.Errorf("code - got: %v, want: import of hoff only", string(code))
+			}
+		})
+	}
+}
+
+func Test_run_usage(t *testing.T) {
+	var output bytes.Buffer
+	err := run([]string{"build"}, &output)
+
+	if err == nil || output.String() != usage+"\n" {
+		t.Errorf("got: %v %v, want: %v", err, output.String(), usage)
+	}
+}
@@ -2,16 +2,64 @@ package hoff
 
 import (
 	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
 
+// ErrComputationInterrupted is the error of a computation interrupted before its end.
+// The computation can continue later from where it was interrupted.
+var ErrComputationInterrupted = errors.New("computation interrupted")
+
 // Computation take a NodeSystem and compute a Context against it.
 type Computation struct {
+	ID      string
 	System  *NodeSystem
 	Context *Context
 	Status  bool
-	Report  map[Node]ComputeState
+	// Report give the compute state by node, a node who can't be used as map key
+	// (e.g. a node value holding a slice) is reported by a node standing for it, printed as the node.
+	Report map[Node]ComputeState
+
+	interrupted      int32
+	walkedNodes      map[string]bool
+	completedNodes   int32
+	progressCallback func(ComputationProgress)
+	stateCallback    func(Node, ComputeState)
+	interceptors     []nodeInterceptor
+	snapshots        map[string]ContextSnapshot
+	durations        map[string]time.Duration
+	// checkpointVersion is the version of the last saved checkpoint
+	checkpointVersion int64
+	// entryNodes replace the initial nodes, and scope limit the computed nodes, of a partial computation
+	entryNodes []Node
+	scope      map[string]bool
+	overrides  map[string]NodeOverride
+	// tenant is the tenant of the computation
+	tenant string
+	// cancellation is the index of the reason of the interruption of the computation
+	cancellation int32
+}
+
+// nodeInterceptor wrap the computation of a node, the compute function run the node
+// (or the next interceptor) on a context, the computation context or a branch of it, and give its compute state.
+type nodeInterceptor func(node Node, c *Context, compute func(c *Context) ComputeState) ComputeState
+
+// ComputationProgress hold the number of completed nodes (computed or skipped)
+// over the total number of nodes of a computation.
+type ComputationProgress struct {
+	Completed int
+	Total     int
+}
+
+// Percentage give the progress as a percentage.
+func (p ComputationProgress) Percentage() float64 {
+	if p.Total == 0 {
+		return 100
+	}
+	return float64(p.Completed) * 100 / float64(p.Total)
 }
 
 // NewComputation create a computation based on a valid, and activated NodeSystem and a Context.
@@ -25,7 +73,18 @@ func NewComputation(system *NodeSystem, context *Context) (*Computation, error)
 	if context == nil {
 		return nil, errors.New("must have a context to work properly")
 	}
+	id, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	seed, err := newSeed()
+	if err != nil {
+		return nil, err
+	}
+	context.computationID = id
+	context.setSeed(seed)
 	return &Computation{
+		ID:      id,
 		Status:  false,
 		System:  system,
 		Context: context,
@@ -40,13 +99,86 @@ func (cp Computation) Equal(o Computation) bool {
 // Compute run all nodes in the defined order to enhance the Context.
 // At the end of the computation (Status at true), you can read the compute state
 // of each node in the Report.
+// If some nodes are paused, the computation stay with Status at false until resumed.
 func (cp *Computation) Compute() error {
+	cp.entryNodes, cp.scope = nil, nil
 	cp.Report = make(map[Node]ComputeState)
+	atomic.StoreInt32(&cp.completedNodes, 0)
 	err := cp.computeNodes(cp.System.InitialNodes())
 	if err != nil {
 		return err
 	}
-	cp.Status = true
+	cp.Status = !cp.IsPaused()
+	return nil
+}
+
+// Continue run the nodes not yet computed of an interrupted computation.
+func (cp *Computation) Continue() error {
+	if cp.Report == nil {
+		return cp.Compute()
+	}
+	atomic.StoreInt32(&cp.interrupted, 0)
+	cp.walkedNodes = make(map[string]bool)
+	defer func() {
+		cp.walkedNodes = nil
+	}()
+
+	entryNodes := cp.System.InitialNodes()
+	if cp.entryNodes != nil {
+		entryNodes = cp.entryNodes
+	}
+	err := cp.computeNodes(entryNodes)
+	if err != nil {
+		return err
+	}
+	cp.Status = !cp.IsPaused()
+	return nil
+}
+
+// Progress give the progress of the computation, it can be called during the computation.
+// As the nodes on a branch not taken are skipped, the progress reach its total
+// once all nodes are completed.
+func (cp *Computation) Progress() ComputationProgress {
+	return ComputationProgress{
+		Completed: int(atomic.LoadInt32(&cp.completedNodes)),
+		Total:     len(cp.System.nodes),
+	}
+}
+
+// ConfigureProgressCallback add a function called each time a node is completed.
+func (cp *Computation) ConfigureProgressCallback(callback func(ComputationProgress)) {
+	cp.progressCallback = callback
+}
+
+// IsPaused tell if some nodes of the computation wait for an external event.
+func (cp *Computation) IsPaused() bool {
+	for _, state := range cp.Report {
+		if state.Value == PauseState {
+			return true
+		}
+	}
+	return false
+}
+
+// Resume replace the compute state of a paused node
+// and continue the computation from it.
+func (cp *Computation) Resume(node Node, state ComputeState) error {
+	report, found := cp.stateOf(node)
+	if !found || report.Value != PauseState {
+		return fmt.Errorf("can't resume a not paused node: %+v", node)
+	}
+
+	cp.recordState(node, state)
+	switch state.Value {
+	case AbortState:
+		return state.Error
+	case ContinueState, SkipState:
+		err := cp.computeFollowingNodes(node, nodeBranches(node)...)
+		if err != nil {
+			return err
+		}
+	}
+	cp.Status = !cp.IsPaused()
 	return nil
 }
 
@@ -61,24 +193,95 @@ func (cp *Computation) computeNodes(nodes []Node) error {
 }
 
 func (cp *Computation) computeNode(node Node) error {
-	order := cp.calculateComputeOrder(node)
+	order, override := cp.overrideComputeOrder(node, cp.calculateComputeOrder(node))
 
 	switch order {
-	case dontRunIt, alreadyRunOnce:
+	case dontRunIt:
 		return nil
+	case alreadyRunOnce:
+		id := cp.System.nodeID(node)
+		if cp.walkedNodes == nil || cp.walkedNodes[id] {
+			return nil
+		}
+		cp.walkedNodes[id] = true
+		report, _ := cp.stateOf(node)
+		state := report.Value
+		if state == AbortState || state == PauseState {
+			return nil
+		}
 	case skipIt:
-		cp.Report[node] = NewSkipComputeState()
+		state := NewSkipComputeState()
+		state.Override = override
+		cp.recordState(node, state)
 	case computeIt:
-		state := node.Compute(cp.Context)
-		cp.Report[node] = state
-		if state.Value == AbortState {
+		if atomic.LoadInt32(&cp.interrupted) == 1 {
+			return ErrComputationInterrupted
+		}
+		start := time.Now()
+		state, err := cp.waitNode(node, cp.runNode(node))
+		if err != nil {
+			return err
+		}
+		if cp.durations == nil {
+			cp.durations = make(map[string]time.Duration)
+		}
+		cp.durations[cp.System.nodeID(node)] = time.Since(start)
+		state.Override = override
+		cp.recordState(node, state)
+		switch state.Value {
+		case AbortState:
 			return state.Error
+		case PauseState:
+			return nil
 		}
 	}
 
 	return cp.computeFollowingNodes(node, nodeBranches(node)...)
 }
 
+func (cp *Computation) runNode(node Node) ComputeState {
+	cp.takeSnapshot(node)
+	compute := func(c *Context) ComputeState {
+		err := cp.feedInputPorts(node, c)
+		if err != nil {
+			return NewAbortComputeState(err)
+		}
+		return node.Compute(c)
+	}
+	for i := len(cp.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := cp.interceptors[i], compute
+		compute = func(c *Context) ComputeState {
+			return interceptor(node, c, next)
+		}
+	}
+	return compute(cp.Context)
+}
+
+// stateOf give the reported compute state of a node, if any.
+func (cp *Computation) stateOf(node Node) (ComputeState, bool) {
+	state, found := cp.Report[cp.System.nodeMapKey(node)]
+	return state, found
+}
+
+func (cp *Computation) recordState(node Node, state ComputeState) {
+	key := cp.System.nodeMapKey(node)
+	previousState, found := cp.Report[key]
+	cp.Report[key] = state
+	if cp.stateCallback != nil {
+		cp.stateCallback(node, state)
+	}
+	if state.Value != PauseState && (!found || previousState.Value == PauseState) {
+		atomic.AddInt32(&cp.completedNodes, 1)
+		if cp.progressCallback != nil {
+			cp.progressCallback(cp.Progress())
+		}
+	}
+}
+
+func (cp *Computation) interrupt() {
+	atomic.StoreInt32(&cp.interrupted, 1)
+}
+
 func (cp *Computation) computeFollowingNodes(node Node, branches ...*bool) error {
 	for _, branch := range branches {
 		nextNodes, _ := cp.System.Follow(node, branch)
@@ -91,9 +294,15 @@ func (cp *Computation) computeFollowingNodes(node Node, branches ...*bool) error
 }
 
 func (cp *Computation) calculateComputeOrder(node Node) computeOrder {
-	if _, ok := cp.Report[node]; ok {
+	if _, ok := cp.stateOf(node); ok {
 		return alreadyRunOnce
 	}
+	if !cp.inScope(node) {
+		return dontRunIt
+	}
+	if cp.System.isDeclaredInitialNode(node) {
+		return computeIt
+	}
 
 	ancestorsCount, ancestorsComputed, ancestorsWithContinueState := cp.ansectorsComputationStatistics(node)
 	if ancestorsCount != ancestorsComputed {
@@ -102,6 +311,13 @@ func (cp *Computation) calculateComputeOrder(node Node) computeOrder {
 		return computeIt
 	}
 
+	if expression, found := cp.System.nodesJoinExpressions[cp.System.nodeID(node)]; found {
+		if cp.evalJoinExpression(node, expression) {
+			return computeIt
+		}
+		return skipIt
+	}
+
 	joinMode := cp.System.JoinModeOfNode(node)
 	switch joinMode {
 	case JoinAnd:
@@ -140,16 +356,21 @@ func (cp *Computation) ansectorsComputationStatisticsOnBranch(node Node, branch
 	linkedNodes, _ := cp.System.Ancestors(node, branch)
 	computedNodes := 0
 	nodesWithContinueState := 0
+	linkedNodesCount := 0
 	for _, linkedNode := range linkedNodes {
-		report, found := cp.Report[linkedNode]
-		if found {
+		report, found := cp.stateOf(linkedNode)
+		if !found && !cp.inScope(linkedNode) {
+			continue
+		}
+		linkedNodesCount++
+		if found && report.Value != PauseState {
 			computedNodes++
 			if report.Value == ContinueState && report.Branch == branch {
 				nodesWithContinueState++
 			}
 		}
 	}
-	return len(linkedNodes), computedNodes, nodesWithContinueState
+	return linkedNodesCount, computedNodes, nodesWithContinueState
 }
 
 type computeOrder string
@@ -10,7 +10,7 @@ import (
 
 func Test_NewComputation(t *testing.T) {
 	var activatedSystem = NewNodeSystem()
-	activatedSystem.Activate()
+	activatedSystem.ActivateInPlace()
 
 	var emptyContext = NewContextWithoutData()
 
@@ -508,11 +508,13 @@ func Test_Computation_Compute(t *testing.T) {
 			system := NewNodeSystem()
 			loadNodeSystem(system, testCase.givenNodes, testCase.givenNodesJoinModes, testCase.givenLinks)
 
-			_, errs := system.IsValid()
+			_, validationErr := system.IsValid()
+
+			errs := validationErrors(validationErr)
 			if errs != nil {
 				t.Errorf("validation errors - %+v\n", errs)
 			}
-			system.Activate()
+			system.ActivateInPlace()
 
 			if testCase.givenContextData == nil {
 				testCase.givenContextData = make(map[string]interface{})
@@ -544,6 +546,170 @@ func Test_Computation_Compute(t *testing.T) {
 	}
 }
 
+func Test_Computation_Resume(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	writeAction, _ := NewActionNode("writeAction", func(c *Context) error {
+		c.Store("write_action", "done")
+		return nil
+	})
+	joinAction, _ := NewActionNode("joinAction", func(c *Context) error {
+		c.Store("join_action", "done")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.AddNode(writeAction)
+	ns.AddNode(joinAction)
+	ns.AddLink(approval, joinAction)
+	ns.AddLink(writeAction, joinAction)
+	ns.ConfigureJoinModeOnNode(joinAction, JoinAnd)
+	ns.ActivateInPlace()
+
+	cp, _ := NewComputation(ns, NewContextWithoutData())
+	cp.Compute()
+
+	if cp.Status || !cp.IsPaused() {
+		t.Errorf("paused computation - got status: %+v and paused: %+v, want: false and true", cp.Status, cp.IsPaused())
+	}
+	if _, found := cp.Report[joinAction]; found {
+		t.Errorf("join action must wait for the paused node, got: %+v", cp.Report[joinAction])
+	}
+
+	err := cp.Resume(writeAction, NewContinueComputeState())
+	expectedError := errors.New("can't resume a not paused node: writeAction")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	err = cp.Resume(approval, NewContinueComputeState())
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+	expectedReport := map[Node]ComputeState{
+		approval:    NewContinueComputeState(),
+		writeAction: NewContinueComputeState(),
+		joinAction:  NewContinueComputeState(),
+	}
+	if !cp.Status || !cmp.Equal(cp.Report, expectedReport, errorComparator) {
+		t.Errorf("report - got: %+v (status %+v), want: %+v", cp.Report, cp.Status, expectedReport)
+	}
+}
+
+func Test_Computation_Continue(t *testing.T) {
+	var cp *Computation
+	interruptAction, _ := NewActionNode("interruptAction", func(c *Context) error {
+		cp.interrupt()
+		return nil
+	})
+	followingAction, _ := NewActionNode("followingAction", func(c *Context) error {
+		c.Store("following_action", "done")
+		return nil
+	})
+	writeAction, _ := NewActionNode("writeAction", func(c *Context) error {
+		c.Store("write_action", "done")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(interruptAction)
+	ns.AddNode(followingAction)
+	ns.AddNode(writeAction)
+	ns.AddLink(interruptAction, followingAction)
+	ns.ActivateInPlace()
+
+	cp, _ = NewComputation(ns, NewContextWithoutData())
+	err := cp.Compute()
+	if !cmp.Equal(err, ErrComputationInterrupted, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, ErrComputationInterrupted)
+	}
+	if cp.Status || len(cp.Report) != 1 {
+		t.Errorf("interrupted computation - got status: %+v and report: %+v", cp.Status, cp.Report)
+	}
+
+	err = cp.Continue()
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+	expectedReport := map[Node]ComputeState{
+		interruptAction: NewContinueComputeState(),
+		followingAction: NewContinueComputeState(),
+		writeAction:     NewContinueComputeState(),
+	}
+	if !cp.Status || !cmp.Equal(cp.Report, expectedReport, errorComparator) {
+		t.Errorf("report - got: %+v (status %+v), want: %+v", cp.Report, cp.Status, expectedReport)
+	}
+}
+
+func Test_Computation_Progress(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("keyIsPresent", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	writeAction, _ := NewActionNode("writeAction", func(c *Context) error {
+		c.Store("write_action", "done")
+		return nil
+	})
+	readAction, _ := NewActionNode("readAction", func(c *Context) error {
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(writeAction)
+	ns.AddNode(readAction)
+	ns.AddLinkOnBranch(keyIsPresent, writeAction, true)
+	ns.AddLink(writeAction, readAction)
+	ns.ActivateInPlace()
+
+	cp, _ := NewComputation(ns, NewContextWithoutData())
+	progresses := make([]ComputationProgress, 0)
+	cp.ConfigureProgressCallback(func(progress ComputationProgress) {
+		progresses = append(progresses, progress)
+	})
+
+	initialProgress := cp.Progress()
+	if !cmp.Equal(initialProgress, ComputationProgress{Completed: 0, Total: 3}) {
+		t.Errorf("initial progress - got: %+v, want: %+v", initialProgress, ComputationProgress{Completed: 0, Total: 3})
+	}
+
+	cp.Compute()
+	expectedProgresses := []ComputationProgress{
+		{Completed: 1, Total: 3},
+		{Completed: 2, Total: 3},
+		{Completed: 3, Total: 3},
+	}
+	if !cmp.Equal(progresses, expectedProgresses) {
+		t.Errorf("progresses - got: %+v, want: %+v", progresses, expectedProgresses)
+	}
+}
+
+func Test_ComputationProgress_Percentage(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenProgress      ComputationProgress
+		expectedPercentage float64
+	}{
+		{
+			name:               "Empty node system is always completed",
+			givenProgress:      ComputationProgress{Completed: 0, Total: 0},
+			expectedPercentage: 100,
+		},
+		{
+			name:               "Partial progress",
+			givenProgress:      ComputationProgress{Completed: 1, Total: 4},
+			expectedPercentage: 25,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			percentage := testCase.givenProgress.Percentage()
+			if percentage != testCase.expectedPercentage {
+				t.Errorf("got: %+v, want: %+v", percentage, testCase.expectedPercentage)
+			}
+		})
+	}
+}
+
 func Test_Github_Issue_11_JoinMode_AND(t *testing.T) {
 	testGithubIssue11JoinMode(JoinAnd, t)
 }
@@ -600,7 +766,7 @@ func testGithubIssue11JoinMode(mode JoinMode, t *testing.T) {
 	ns.AddLink(action3, action5)
 	ns.AddLink(action1, action5)
 	ns.ConfigureJoinModeOnNode(action5, mode)
-	ns.Activate()
+	ns.ActivateInPlace()
 
 	cp, _ := NewComputation(ns, NewContext(data))
 	cp.Compute()
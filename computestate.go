@@ -2,6 +2,7 @@ package hoff
 
 import (
 	"fmt"
+	"time"
 )
 
 // ComputeState hold the result of a Node computation
@@ -9,6 +10,13 @@ type ComputeState struct {
 	Value  StateType
 	Branch *bool
 	Error  error
+	Code   AbortCode
+	Token  string
+	// PollInterval and Deadline are set on WaitState
+	PollInterval time.Duration
+	Deadline     time.Time
+	// Override is set when the state is forced by a node override
+	Override NodeOverride
 }
 
 // String print human-readable version of a compute state
@@ -21,7 +29,18 @@ func (cs ComputeState) String() string {
 	if cs.Error != nil {
 		err = fmt.Sprintf(" on %v", cs.Error)
 	}
-	return fmt.Sprintf("'%v%v%v'", cs.Value, branch, err)
+	if cs.Code != UnclassifiedAbort {
+		err += fmt.Sprintf(" (%v)", cs.Code)
+	}
+	token := ""
+	if cs.Token != "" {
+		token = fmt.Sprintf(" on token %v", cs.Token)
+	}
+	override := ""
+	if cs.Override != "" {
+		override = fmt.Sprintf(" by %v", cs.Override)
+	}
+	return fmt.Sprintf("'%v%v%v%v%v'", cs.Value, branch, err, token, override)
 }
 
 // NewContinueComputeState generate a computation state to continue to following nodes
@@ -48,10 +67,32 @@ func NewSkipComputeState() ComputeState {
 	}
 }
 
-// NewAbortComputeState generate a computation state to throw an unexpected error
+// NewAbortComputeState generate a computation state to throw an unexpected error,
+// classified by the code of the error (see WithAbortCode)
 func NewAbortComputeState(err error) ComputeState {
 	return ComputeState{
 		Value: AbortState,
 		Error: err,
+		Code:  AbortCodeOf(err),
+	}
+}
+
+// NewPauseComputeState generate a computation state to wait for an external event,
+// identified by a token, before continuing to following nodes
+func NewPauseComputeState(token string) ComputeState {
+	return ComputeState{
+		Value: PauseState,
+		Token: token,
+	}
+}
+
+// NewWaitComputeState generate a computation state to compute again the node after a poll interval,
+// until the node stop to wait or the deadline (if not zero) is passed.
+// The deadline of the first wait state of a node is kept for the following ones.
+func NewWaitComputeState(pollInterval time.Duration, deadline time.Time) ComputeState {
+	return ComputeState{
+		Value:        WaitState,
+		PollInterval: pollInterval,
+		Deadline:     deadline,
 	}
 }
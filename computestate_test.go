@@ -14,6 +14,7 @@ func Test_ComputeState_Call(t *testing.T) {
 		expectedState         StateType
 		expectedNodeBranch    *bool
 		expectedError         error
+		expectedToken         string
 		expectedString        string
 	}{
 		{
@@ -42,6 +43,13 @@ func Test_ComputeState_Call(t *testing.T) {
 			expectedError:         errors.New("error"),
 			expectedString:        "'Abort on error'",
 		},
+		{
+			name:                  "Should generate a pause state",
+			givenComputeStateCall: func() ComputeState { return NewPauseComputeState("token") },
+			expectedState:         PauseState,
+			expectedToken:         "token",
+			expectedString:        "'Pause on token token'",
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -55,6 +63,9 @@ func Test_ComputeState_Call(t *testing.T) {
 			if !cmp.Equal(computeState.Error, testCase.expectedError, errorComparator) {
 				t.Errorf("error - got: %+v, want: %+v", computeState.Error, testCase.expectedError)
 			}
+			if computeState.Token != testCase.expectedToken {
+				t.Errorf("token - got: %+v, want: %+v", computeState.Token, testCase.expectedToken)
+			}
 			if computeState.String() != testCase.expectedString {
 				t.Errorf("string - got: %+v, want: %+v", computeState.String(), testCase.expectedString)
 			}
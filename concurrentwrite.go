@@ -0,0 +1,95 @@
+package hoff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// checkForConcurrentWrites report each pair of nodes who may run concurrently and write the same declared context key,
+// the last computed one override the value of the other.
+// Two nodes may run concurrently when no node links path go from one to the other,
+// and they are not on the exclusive branches of a decision node.
+func checkForConcurrentWrites(s *NodeSystem) []error {
+	errs := make([]error, 0)
+	ids := make([]string, len(s.nodes))
+	produced := make([]map[string]bool, len(s.nodes))
+	for i, node := range s.nodes {
+		ids[i] = s.nodeID(node)
+		_, keys := s.KeysOfNode(node)
+		produced[i] = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			produced[i][key] = true
+		}
+	}
+
+	successors := make(map[string][]nodeLink)
+	for _, link := range s.links {
+		from := s.nodeID(link.From)
+		successors[from] = append(successors[from], link)
+	}
+	reachable := make(map[string]map[string]bool)
+	var reach func(id string) map[string]bool
+	reach = func(id string) map[string]bool {
+		if nodes, found := reachable[id]; found {
+			return nodes
+		}
+		nodes := make(map[string]bool)
+		reachable[id] = nodes
+		for _, link := range successors[id] {
+			to := s.nodeID(link.To)
+			nodes[to] = true
+			for next := range reach(to) {
+				nodes[next] = true
+			}
+		}
+		return nodes
+	}
+	branchReach := func(id string, branch bool) map[string]bool {
+		nodes := make(map[string]bool)
+		for _, link := range successors[id] {
+			if link.Branch == nil || *link.Branch != branch {
+				continue
+			}
+			to := s.nodeID(link.To)
+			nodes[to] = true
+			for next := range reach(to) {
+				nodes[next] = true
+			}
+		}
+		return nodes
+	}
+	exclusive := func(x, y string) bool {
+		for _, node := range s.nodes {
+			if !node.DecideCapability() {
+				continue
+			}
+			id := s.nodeID(node)
+			onTrue, onFalse := branchReach(id, true), branchReach(id, false)
+			if onTrue[x] && !onFalse[x] && onFalse[y] && !onTrue[y] {
+				return true
+			}
+			if onFalse[x] && !onTrue[x] && onTrue[y] && !onFalse[y] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range s.nodes {
+		for j := i + 1; j < len(s.nodes); j++ {
+			keys := make([]string, 0)
+			for key := range produced[i] {
+				if produced[j][key] {
+					keys = append(keys, key)
+				}
+			}
+			if len(keys) == 0 || ids[i] == ids[j] || reach(ids[i])[ids[j]] || reach(ids[j])[ids[i]] || exclusive(ids[i], ids[j]) {
+				continue
+			}
+			sort.Strings(keys)
+			errs = append(errs, fmt.Errorf("can't have nodes '%v' and '%v' writing the same keys on parallel branches: %v", s.nodes[i], s.nodes[j], strings.Join(keys, ", ")))
+		}
+	}
+	return errs
+}
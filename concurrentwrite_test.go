@@ -0,0 +1,96 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_Validate_concurrentWrites(t *testing.T) {
+	start := &ActionNode{name: "start", actionFunc: func(*Context) error { return nil }}
+	left := &ActionNode{name: "left", actionFunc: func(*Context) error { return nil }}
+	right := &ActionNode{name: "right", actionFunc: func(*Context) error { return nil }}
+	decision := &DecisionNode{name: "decision", decisionFunc: func(*Context) (bool, error) { return true, nil }}
+
+	testCases := []struct {
+		name             string
+		givenSystem      func(ns *NodeSystem)
+		givenConfig      ValidationConfig
+		expectedErrors   []error
+		expectedWarnings []error
+	}{
+		{
+			name: "Can warn about nodes writing the same key on parallel branches",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(start)
+				ns.AddNode(left)
+				ns.AddNode(right)
+				ns.AddLink(start, left)
+				ns.AddLink(start, right)
+				ns.ConfigureKeysOnNode(left, nil, []string{"total", "label"})
+				ns.ConfigureKeysOnNode(right, nil, []string{"label", "total", "other"})
+			},
+			expectedWarnings: []error{errors.New("can't have nodes 'left' and 'right' writing the same keys on parallel branches: label, total")},
+		},
+		{
+			name: "Can upgrade the warnings to errors",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(left)
+				ns.AddNode(right)
+				ns.ConfigureKeysOnNode(left, nil, []string{"total"})
+				ns.ConfigureKeysOnNode(right, nil, []string{"total"})
+			},
+			givenConfig:    ValidationConfig{Errors: []ValidationCheck{ConcurrentWriteCheck}},
+			expectedErrors: []error{errors.New("can't have nodes 'left' and 'right' writing the same keys on parallel branches: total")},
+		},
+		{
+			name: "Can write the same key on nodes linked one after the other",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(start)
+				ns.AddNode(left)
+				ns.AddNode(right)
+				ns.AddLink(start, left)
+				ns.AddLink(left, right)
+				ns.ConfigureKeysOnNode(start, nil, []string{"total"})
+				ns.ConfigureKeysOnNode(right, nil, []string{"total"})
+			},
+		},
+		{
+			name: "Can write the same key on exclusive branches of a decision node",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(decision)
+				ns.AddNode(left)
+				ns.AddNode(right)
+				ns.AddLinkOnBranch(decision, left, true)
+				ns.AddLinkOnBranch(decision, right, false)
+				ns.ConfigureKeysOnNode(left, nil, []string{"total"})
+				ns.ConfigureKeysOnNode(right, nil, []string{"total"})
+			},
+		},
+		{
+			name: "Can write different keys on parallel branches",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(left)
+				ns.AddNode(right)
+				ns.ConfigureKeysOnNode(left, nil, []string{"total"})
+				ns.ConfigureKeysOnNode(right, nil, []string{"label"})
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			testCase.givenSystem(ns)
+
+			result := ns.Validate(testCase.givenConfig)
+
+			if !cmp.Equal(result.Errors, testCase.expectedErrors, errorComparator) {
+				t.Errorf("errors - got: %+v, want: %+v", result.Errors, testCase.expectedErrors)
+			}
+			if !cmp.Equal(result.Warnings, testCase.expectedWarnings, errorComparator) {
+				t.Errorf("warnings - got: %+v, want: %+v", result.Warnings, testCase.expectedWarnings)
+			}
+		})
+	}
+}
@@ -1,25 +1,53 @@
 package hoff
 
 import (
+	"context"
+	"math/rand"
+
 	"github.com/google/go-cmp/cmp"
 )
 
 // Context hold data during an Computation
 type Context struct {
 	Data map[string]interface{}
+
+	strict       bool
+	knownKeys    map[string]bool
+	unknownReads []string
+
+	store       ContextStore
+	storeErrors []error
+	cipher      Cipher
+
+	computationID string
+	cleanups      []func()
+	// cancellation is the reason of the interruption of the computation, set before running the cleanups
+	cancellation CancellationReason
+	// resources are the values kept by the nodes for the computation (e.g. the sql transactions)
+	resources map[interface{}]interface{}
+
+	seed   int64
+	random *rand.Rand
+
+	goContext context.Context
+
+	locks  *keyLocks
+	staged *stagingBuffer
 }
 
 // NewContextWithoutData generate a new empty Context
 func NewContextWithoutData() *Context {
 	return &Context{
-		Data: make(map[string]interface{}),
+		Data:  make(map[string]interface{}),
+		locks: newKeyLocks(),
 	}
 }
 
 // NewContext generate a new Context with data
 func NewContext(data map[string]interface{}) *Context {
 	return &Context{
-		Data: data,
+		Data:  data,
+		locks: newKeyLocks(),
 	}
 }
 
@@ -30,22 +58,121 @@ func (c Context) Equal(o Context) bool {
 
 // Store add a key and its value to the context
 func (c *Context) Store(key string, value interface{}) {
+	if c.stage(key, stagedWrite{value: value}) {
+		return
+	}
+	locks := c.keyLocks()
+	locks.data.Lock()
+	defer locks.data.Unlock()
+	c.storeValue(key, value)
+}
+
+func (c *Context) storeValue(key string, value interface{}) {
 	c.Data[key] = value
+	if c.strict {
+		c.knownKeys[key] = true
+	}
 }
 
-// Delete remove a value in the context by its key
+// Delete remove a value in the context by its key,
+// a value persisted in the context store is removed from the store.
 func (c *Context) Delete(key string) {
+	if c.stage(key, stagedWrite{deleted: true}) {
+		return
+	}
+	locks := c.keyLocks()
+	locks.data.Lock()
+	defer locks.data.Unlock()
+	c.deleteValue(key)
+}
+
+func (c *Context) deleteValue(key string) {
+	if reference, isReference := c.Data[key].(ContextReference); isReference && c.store != nil {
+		c.store.Delete(reference.Reference)
+	}
 	delete(c.Data, key)
 }
 
 // Read get a value in the context by its key
+// In strict mode, reading a key never written is recorded as an unknown read.
+// A value persisted in the context store is fetched from the store.
 func (c *Context) Read(key string) (interface{}, bool) {
+	if write, staged := c.stagedValue(key); staged {
+		if write.deleted {
+			return nil, false
+		}
+		return c.fetch(key, write.value)
+	}
+	locks := c.keyLocks()
+	locks.data.Lock()
 	value, ok := c.Data[key]
-	return value, ok
+	if !ok && c.strict && !c.knownKeys[key] {
+		c.unknownReads = append(c.unknownReads, key)
+	}
+	locks.data.Unlock()
+	if !ok {
+		return value, ok
+	}
+	return c.fetch(key, value)
 }
 
 // HaveKey validate that a key is in the context
 func (c *Context) HaveKey(key string) bool {
+	if write, staged := c.stagedValue(key); staged {
+		return !write.deleted
+	}
+	locks := c.keyLocks()
+	locks.data.Lock()
+	defer locks.data.Unlock()
 	_, ok := c.Data[key]
 	return ok
 }
+
+// readAll give all the context values as read by the node (see Read), with its staged writes
+// and the values fetched from the context store, e.g. to give the whole context data to a sandbox.
+func (c *Context) readAll() map[string]interface{} {
+	locks := c.keyLocks()
+	locks.data.Lock()
+	values := make(map[string]interface{}, len(c.Data))
+	for key, value := range c.Data {
+		values[key] = value
+	}
+	locks.data.Unlock()
+
+	locks.staging.Lock()
+	if c.staged != nil {
+		for key, write := range c.staged.writes {
+			if write.deleted {
+				delete(values, key)
+			} else {
+				values[key] = write.value
+			}
+		}
+	}
+	locks.staging.Unlock()
+
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if fetchedValue, found := c.fetch(key, value); found {
+			data[key] = fetchedValue
+		}
+	}
+	return data
+}
+
+// enableStrictMode start to record the reads of keys never written,
+// the keys already in the context are known.
+func (c *Context) enableStrictMode() {
+	c.strict = true
+	c.knownKeys = make(map[string]bool)
+	for key := range c.Data {
+		c.knownKeys[key] = true
+	}
+}
+
+// takeUnknownReads give the keys read without being written since the last call.
+func (c *Context) takeUnknownReads() []string {
+	unknownReads := c.unknownReads
+	c.unknownReads = nil
+	return unknownReads
+}
@@ -132,6 +132,60 @@ func Test_Context_Read(t *testing.T) {
 	}
 }
 
+func Test_Context_Read_StrictMode(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		givenContextData     map[string]interface{}
+		givenStoredKey       string
+		givenDeletedKey      string
+		givenKey             string
+		expectedUnknownReads []string
+	}{
+		{
+			name:             "Can read key from input data",
+			givenContextData: map[string]interface{}{"key": "value"},
+			givenKey:         "key",
+		},
+		{
+			name:           "Can read stored key",
+			givenStoredKey: "key",
+			givenKey:       "key",
+		},
+		{
+			name:             "Can read deleted key",
+			givenContextData: map[string]interface{}{"key": "value"},
+			givenDeletedKey:  "key",
+			givenKey:         "key",
+		},
+		{
+			name:                 "Can't read unknown key",
+			givenKey:             "key",
+			expectedUnknownReads: []string{"key"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := NewContextWithoutData()
+			for key, value := range testCase.givenContextData {
+				c.Store(key, value)
+			}
+			c.enableStrictMode()
+			if testCase.givenStoredKey != "" {
+				c.Store(testCase.givenStoredKey, "value")
+			}
+			if testCase.givenDeletedKey != "" {
+				c.Delete(testCase.givenDeletedKey)
+			}
+			c.Read(testCase.givenKey)
+
+			unknownReads := c.takeUnknownReads()
+			if !cmp.Equal(unknownReads, testCase.expectedUnknownReads) {
+				t.Errorf("got: %+v, want: %+v", unknownReads, testCase.expectedUnknownReads)
+			}
+		})
+	}
+}
+
 func Test_Context_Delete(t *testing.T) {
 	testCases := []struct {
 		name                string
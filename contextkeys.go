@@ -0,0 +1,148 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeysDeclarerNode is a Node who declare the context keys it read and write.
+type KeysDeclarerNode interface {
+	Node
+	// RequiredKeys give the context keys needed by the node to compute.
+	RequiredKeys() []string
+	// ProducedKeys give the context keys written by the node.
+	ProducedKeys() []string
+}
+
+// nodeKeys hold the context keys read and written by a node.
+type nodeKeys struct {
+	Required []string
+	Produced []string
+}
+
+// ConfigureKeysOnNode configure the context keys read (required) and written (produced)
+// by a node into the system before activation.
+// It take precedence over the keys declared by a KeysDeclarerNode.
+func (s *NodeSystem) ConfigureKeysOnNode(n Node, required, produced []string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add node keys, node system is freeze due to activation")
+	}
+	if s.nodesKeys == nil {
+		s.nodesKeys = make(map[string]nodeKeys)
+	}
+	s.nodesKeys[s.nodeID(n)] = nodeKeys{Required: required, Produced: produced}
+	return true, nil
+}
+
+// KeysOfNode get the context keys read (required) and written (produced) by a node.
+func (s *NodeSystem) KeysOfNode(n Node) ([]string, []string) {
+	if keys, found := s.nodesKeys[s.nodeID(n)]; found {
+		return keys.Required, keys.Produced
+	}
+	if declarer, ok := n.(KeysDeclarerNode); ok {
+		return declarer.RequiredKeys(), declarer.ProducedKeys()
+	}
+	return nil, nil
+}
+
+// CheckContextKeys walk the activated node system and report each node requiring a context key
+// who is not in the seed keys nor produced upstream, with a path where the key is missing.
+// A node with JoinAnd mode get the keys produced on all its incoming paths,
+// otherwise only the keys produced on every incoming path are guaranteed.
+func (s *NodeSystem) CheckContextKeys(seedKeys ...string) []error {
+	if !s.activated {
+		return []error{errors.New("can't check context keys of a node system if not activated")}
+	}
+
+	availableKeys := make(map[string]map[string]bool)
+	errs := make([]error, 0)
+	for _, layer := range nodeLayers(s) {
+		for _, node := range layer {
+			keys := s.availableKeysOfNode(node, availableKeys, seedKeys)
+			availableKeys[s.nodeID(node)] = keys
+
+			required, _ := s.KeysOfNode(node)
+			for _, key := range required {
+				if !keys[key] {
+					path := s.pathWithoutKey(node, key, availableKeys)
+					errs = append(errs, fmt.Errorf("can't have node '%v' requiring key '%v' not produced upstream on path: %v", node, key, strings.Join(path, " -> ")))
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// availableKeysOfNode give the context keys guaranteed to be available when a node compute.
+func (s *NodeSystem) availableKeysOfNode(node Node, availableKeys map[string]map[string]bool, seedKeys []string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, key := range seedKeys {
+		keys[key] = true
+	}
+
+	ancestors := allAncestors(s, node)
+	if len(ancestors) == 0 {
+		return keys
+	}
+
+	counts := make(map[string]int)
+	for _, ancestor := range ancestors {
+		for key := range s.keysAfterNode(ancestor, availableKeys) {
+			counts[key]++
+		}
+	}
+	for key, count := range counts {
+		if s.JoinModeOfNode(node) == JoinAnd || count == len(ancestors) {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// keysAfterNode give the context keys available once a node is computed.
+func (s *NodeSystem) keysAfterNode(node Node, availableKeys map[string]map[string]bool) map[string]bool {
+	keys := make(map[string]bool)
+	for key := range availableKeys[s.nodeID(node)] {
+		keys[key] = true
+	}
+	_, produced := s.KeysOfNode(node)
+	for _, key := range produced {
+		keys[key] = true
+	}
+	return keys
+}
+
+// pathWithoutKey give a path from an initial node to a node where the key is never produced.
+func (s *NodeSystem) pathWithoutKey(node Node, key string, availableKeys map[string]map[string]bool) []string {
+	path := []string{fmt.Sprint(node)}
+	current := node
+	for {
+		var next Node
+		for _, ancestor := range allAncestors(s, current) {
+			if !s.keysAfterNode(ancestor, availableKeys)[key] {
+				next = ancestor
+				break
+			}
+		}
+		if next == nil {
+			return path
+		}
+		path = append([]string{fmt.Sprint(next)}, path...)
+		current = next
+	}
+}
+
+// allAncestors give the ancestors of a node on all branches.
+func allAncestors(s *NodeSystem, node Node) []Node {
+	ancestors := make([]Node, 0)
+	for _, branch := range []*bool{nil, boolPointer(true), boolPointer(false)} {
+		nodes, _ := s.Ancestors(node, branch)
+		ancestors = append(ancestors, nodes...)
+	}
+	return ancestors
+}
@@ -0,0 +1,152 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type keysDeclarerNode struct {
+	SomeNode
+	name string
+}
+
+func (n *keysDeclarerNode) RequiredKeys() []string {
+	return []string{"input"}
+}
+
+func (n *keysDeclarerNode) ProducedKeys() []string {
+	return []string{"message"}
+}
+
+func Test_NodeSystem_KeysOfNode(t *testing.T) {
+	declarer := &keysDeclarerNode{name: "declarer"}
+	configuredDeclarer := &keysDeclarerNode{name: "configuredDeclarer"}
+
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(declarer)
+	ns.AddNode(configuredDeclarer)
+	ns.ConfigureKeysOnNode(configuredDeclarer, []string{"other_input"}, nil)
+
+	testCases := []struct {
+		name             string
+		givenNode        Node
+		expectedRequired []string
+		expectedProduced []string
+	}{
+		{
+			name:      "Node without keys",
+			givenNode: someActionNode,
+		},
+		{
+			name:             "Node declaring its keys",
+			givenNode:        declarer,
+			expectedRequired: []string{"input"},
+			expectedProduced: []string{"message"},
+		},
+		{
+			name:             "Node with configured keys",
+			givenNode:        configuredDeclarer,
+			expectedRequired: []string{"other_input"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			required, produced := ns.KeysOfNode(testCase.givenNode)
+
+			if !cmp.Equal(required, testCase.expectedRequired) {
+				t.Errorf("required - got: %+v, want: %+v", required, testCase.expectedRequired)
+			}
+			if !cmp.Equal(produced, testCase.expectedProduced) {
+				t.Errorf("produced - got: %+v, want: %+v", produced, testCase.expectedProduced)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_CheckContextKeys(t *testing.T) {
+	readInput, _ := NewActionNode("readInput", func(*Context) error { return nil })
+	inputIsValid, _ := NewDecisionNode("inputIsValid", func(*Context) (bool, error) { return true, nil })
+	enrich, _ := NewActionNode("enrich", func(*Context) error { return nil })
+	fallback, _ := NewActionNode("fallback", func(*Context) error { return nil })
+	write, _ := NewActionNode("write", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name           string
+		givenJoinMode  JoinMode
+		givenSeedKeys  []string
+		givenKeys      map[Node][2][]string
+		expectedErrors []error
+	}{
+		{
+			name:          "Can have all required keys produced upstream",
+			givenJoinMode: JoinOr,
+			givenSeedKeys: []string{"input"},
+			givenKeys: map[Node][2][]string{
+				readInput: {{"input"}, {"data"}},
+				enrich:    {{"data"}, {"output"}},
+				fallback:  {{"data"}, {"output"}},
+				write:     {{"data", "output"}, nil},
+			},
+		},
+		{
+			name:          "Can't have a required key missing in seed keys",
+			givenJoinMode: JoinOr,
+			givenKeys: map[Node][2][]string{
+				readInput: {{"input"}, {"data"}},
+			},
+			expectedErrors: []error{
+				errors.New("can't have node 'readInput' requiring key 'input' not produced upstream on path: readInput"),
+			},
+		},
+		{
+			name:          "Can't have a required key produced on only one path",
+			givenJoinMode: JoinOr,
+			givenSeedKeys: []string{"input"},
+			givenKeys: map[Node][2][]string{
+				enrich: {nil, {"output"}},
+				write:  {{"output"}, nil},
+			},
+			expectedErrors: []error{
+				errors.New("can't have node 'write' requiring key 'output' not produced upstream on path: readInput -> inputIsValid -> fallback -> write"),
+			},
+		},
+		{
+			name:          "Can have a required key produced on one path of a join and",
+			givenJoinMode: JoinAnd,
+			givenSeedKeys: []string{"input"},
+			givenKeys: map[Node][2][]string{
+				enrich: {nil, {"output"}},
+				write:  {{"output"}, nil},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(readInput)
+			ns.AddNode(inputIsValid)
+			ns.AddNode(enrich)
+			ns.AddNode(fallback)
+			ns.AddNode(write)
+			ns.AddLink(readInput, inputIsValid)
+			ns.AddLinkOnBranch(inputIsValid, enrich, true)
+			ns.AddLinkOnBranch(inputIsValid, fallback, false)
+			ns.AddLink(enrich, write)
+			ns.AddLink(fallback, write)
+			ns.ConfigureJoinModeOnNode(write, testCase.givenJoinMode)
+			for node, keys := range testCase.givenKeys {
+				ns.ConfigureKeysOnNode(node, keys[0], keys[1])
+			}
+			ns.ActivateInPlace()
+
+			errs := ns.CheckContextKeys(testCase.givenSeedKeys...)
+
+			if !cmp.Equal(errs, testCase.expectedErrors, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", errs, testCase.expectedErrors)
+			}
+		})
+	}
+}
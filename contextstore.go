@@ -0,0 +1,111 @@
+package hoff
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ContextStore persist large context values outside of the memory of a computation.
+type ContextStore interface {
+	// Put store a value and give a reference to fetch it.
+	Put(key string, value interface{}) (string, error)
+	// Get fetch a value by its reference.
+	Get(reference string) (interface{}, error)
+	// Delete remove a value by its reference.
+	Delete(reference string) error
+}
+
+// ContextReference is the value kept in a context for a value stored in a ContextStore.
+type ContextReference struct {
+	Reference string `json:"reference"`
+}
+
+// MemoryContextStore is a ContextStore keeping the values in memory, mainly for testing.
+type MemoryContextStore struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// NewMemoryContextStore create an empty in memory context store.
+func NewMemoryContextStore() *MemoryContextStore {
+	return &MemoryContextStore{
+		values: make(map[string]interface{}),
+	}
+}
+
+// Put store a value and give a reference to fetch it.
+func (s *MemoryContextStore) Put(key string, value interface{}) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reference := fmt.Sprintf("%v-%v", key, token)
+	s.values[reference] = value
+	return reference, nil
+}
+
+// Get fetch a value by its reference.
+func (s *MemoryContextStore) Get(reference string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, found := s.values[reference]
+	if !found {
+		return nil, fmt.Errorf("can't find value for reference '%v'", reference)
+	}
+	return value, nil
+}
+
+// Delete remove a value by its reference.
+func (s *MemoryContextStore) Delete(reference string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, reference)
+	return nil
+}
+
+// ConfigureStore add a store to persist context values outside of the memory.
+func (c *Context) ConfigureStore(store ContextStore) {
+	c.store = store
+}
+
+// StoreExternal add a key and its value to the context, with the value persisted
+// in the context store and only its reference kept in the context.
+// The value is fetched from the store when the key is read.
+func (c *Context) StoreExternal(key string, value interface{}) error {
+	if c.store == nil {
+		return fmt.Errorf("can't store key '%v' without context store", key)
+	}
+	reference, err := c.store.Put(key, value)
+	if err != nil {
+		return fmt.Errorf("can't store key '%v' in context store: %w", key, err)
+	}
+	c.Store(key, ContextReference{Reference: reference})
+	return nil
+}
+
+// fetch give the value of a context reference from the context store.
+// A failure is recorded as a store error.
+func (c *Context) fetch(key string, value interface{}) (interface{}, bool) {
+	reference, isReference := value.(ContextReference)
+	if !isReference || c.store == nil {
+		return value, true
+	}
+	fetchedValue, err := c.store.Get(reference.Reference)
+	if err != nil {
+		locks := c.keyLocks()
+		locks.data.Lock()
+		c.storeErrors = append(c.storeErrors, fmt.Errorf("can't fetch key '%v' from context store: %w", key, err))
+		locks.data.Unlock()
+		return nil, false
+	}
+	return fetchedValue, true
+}
+
+// takeStoreErrors give the errors of the context store since the last call.
+func (c *Context) takeStoreErrors() []error {
+	storeErrors := c.storeErrors
+	c.storeErrors = nil
+	return storeErrors
+}
@@ -0,0 +1,85 @@
+package hoff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Context_StoreExternal(t *testing.T) {
+	store := NewMemoryContextStore()
+	c := NewContextWithoutData()
+	c.ConfigureStore(store)
+
+	err := c.StoreExternal("dataset", []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("error - got: %+v, want: %+v", err, nil)
+	}
+
+	reference, isReference := c.Data["dataset"].(ContextReference)
+	if !isReference || !strings.HasPrefix(reference.Reference, "dataset-") {
+		t.Errorf("reference - got: %+v, want: a reference", c.Data["dataset"])
+	}
+	value, ok := c.Read("dataset")
+	if !ok || !cmp.Equal(value, []int{1, 2, 3}) {
+		t.Errorf("value - got: %+v (ok: %+v), want: %+v", value, ok, []int{1, 2, 3})
+	}
+
+	c.Delete("dataset")
+	_, err = store.Get(reference.Reference)
+	expectedError := errors.New("can't find value for reference '" + reference.Reference + "'")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("deleted - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Context_StoreExternal_without_store(t *testing.T) {
+	c := NewContextWithoutData()
+
+	err := c.StoreExternal("dataset", []int{1, 2, 3})
+
+	expectedError := errors.New("can't store key 'dataset' without context store")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Engine_ConfigureContextStore(t *testing.T) {
+	var readValue interface{}
+	var readOk bool
+	writeDataset, _ := NewActionNode("writeDataset", func(c *Context) error {
+		return c.StoreExternal("dataset", "large value")
+	})
+	readDataset, _ := NewActionNode("readDataset", func(c *Context) error {
+		readValue, _ = c.Read("dataset")
+		_, readOk = c.Read("missing")
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(writeDataset)
+	ns.AddNode(readDataset)
+	ns.AddLink(writeDataset, readDataset)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureContextStore(NewMemoryContextStore())
+
+	result := eng.Compute(map[string]interface{}{"missing": ContextReference{Reference: "unknown"}})
+
+	expectedReport := map[Node]ComputeState{
+		writeDataset: NewContinueComputeState(),
+		readDataset:  NewAbortComputeState(errors.New("can't fetch key 'missing' from context store: can't find value for reference 'unknown'")),
+	}
+	if !cmp.Equal(result.Report, expectedReport, NodeComparator, errorComparator) {
+		t.Errorf("report - got: %+v, want: %+v", result.Report, expectedReport)
+	}
+	if readValue != "large value" || readOk {
+		t.Errorf("read - got: %+v (missing ok: %+v), want: %+v", readValue, readOk, "large value")
+	}
+	if _, isReference := result.Data["dataset"].(ContextReference); !isReference {
+		t.Errorf("data - got: %+v, want: a reference", result.Data["dataset"])
+	}
+}
@@ -0,0 +1,78 @@
+package hoff
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// keyLocks hold a lock by context key, shared by the context of a computation and its branches
+// (see RaceNode and TagPolicy.HedgeAfter), a lock of the data of the context, and a lock of its staged writes.
+type keyLocks struct {
+	mu      sync.Mutex
+	keys    map[string]*sync.Mutex
+	data    sync.Mutex
+	staging sync.Mutex
+}
+
+// unsharedKeyLocks is the locks of the contexts not created by NewContext or NewContextWithoutData.
+var unsharedKeyLocks = newKeyLocks()
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{keys: make(map[string]*sync.Mutex)}
+}
+
+// lock lock a key and give the function to unlock it.
+func (l *keyLocks) lock(key string) func() {
+	l.mu.Lock()
+	lock, found := l.keys[key]
+	if !found {
+		lock = &sync.Mutex{}
+		l.keys[key] = lock
+	}
+	l.mu.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+func (c *Context) keyLocks() *keyLocks {
+	if c.locks == nil {
+		return unsharedKeyLocks
+	}
+	return c.locks
+}
+
+// Update replace the value of a key by the value given by a function of its current value, or keep it on error,
+// while no other update of the key run, e.g. to aggregate a counter or a list from concurrent goroutines of a node.
+// The keys updated concurrently must only be written with Update or CompareAndSwap,
+// as a Store or a Delete of the key is not serialized with the updates.
+// The branches of a RaceNode, or the attempts of a hedged node, update their own copy of the context,
+// so only the updates of the kept branch are applied, the ones of the other branches are dropped with their changes.
+func (c *Context) Update(key string, update func(old interface{}, found bool) (interface{}, error)) error {
+	defer c.keyLocks().lock(key)()
+
+	old, found := c.Read(key)
+	value, err := update(old, found)
+	if err != nil {
+		return err
+	}
+	c.Store(key, value)
+	return nil
+}
+
+// CompareAndSwap store a new value of a key only if its current value is equal to an old value,
+// a nil old value matching a missing key, and tell if the value was stored (see Update).
+func (c *Context) CompareAndSwap(key string, old, value interface{}) bool {
+	swapped := false
+	c.Update(key, func(current interface{}, found bool) (interface{}, error) {
+		if (!found && old == nil) || (found && reflect.DeepEqual(current, old)) {
+			swapped = true
+			return value, nil
+		}
+		return nil, errNotSwapped
+	})
+	return swapped
+}
+
+// errNotSwapped keep the value of a key not swapped by CompareAndSwap.
+var errNotSwapped = errors.New("can't swap value")
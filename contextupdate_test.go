@@ -0,0 +1,179 @@
+package hoff
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Context_Update(t *testing.T) {
+	increment := func(old interface{}, found bool) (interface{}, error) {
+		if !found {
+			return 1, nil
+		}
+		return old.(int) + 1, nil
+	}
+
+	testCases := []struct {
+		name          string
+		givenContext  *Context
+		givenUpdate   func(interface{}, bool) (interface{}, error)
+		expectedError error
+		expectedData  map[string]interface{}
+	}{
+		{
+			name:         "Can update a missing key",
+			givenContext: NewContextWithoutData(),
+			givenUpdate:  increment,
+			expectedData: map[string]interface{}{"count": 1},
+		},
+		{
+			name:         "Can update a key",
+			givenContext: NewContext(map[string]interface{}{"count": 41}),
+			givenUpdate:  increment,
+			expectedData: map[string]interface{}{"count": 42},
+		},
+		{
+			name:         "Can update a key of a context without locks",
+			givenContext: &Context{Data: map[string]interface{}{"count": 1}},
+			givenUpdate:  increment,
+			expectedData: map[string]interface{}{"count": 2},
+		},
+		{
+			name:         "Can't update a key on error",
+			givenContext: NewContext(map[string]interface{}{"count": 1}),
+			givenUpdate: func(interface{}, bool) (interface{}, error) {
+				return nil, errors.New("can't count")
+			},
+			expectedError: errors.New("can't count"),
+			expectedData:  map[string]interface{}{"count": 1},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.givenContext.Update("count", testCase.givenUpdate)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(testCase.givenContext.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", testCase.givenContext.Data, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_Context_Update_concurrently(t *testing.T) {
+	c := NewContextWithoutData()
+	branch := c.branch(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Update("count", func(old interface{}, found bool) (interface{}, error) {
+				if !found {
+					return 1, nil
+				}
+				return old.(int) + 1, nil
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			branch.Update("items", func(old interface{}, found bool) (interface{}, error) {
+				items, _ := old.([]int)
+				return append(items, len(items)), nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if count, _ := c.Read("count"); count != 50 {
+		t.Errorf("count - got: %+v, want: %+v", count, 50)
+	}
+	if items, _ := branch.Read("items"); len(items.([]int)) != 50 {
+		t.Errorf("items - got: %+v, want: %+v", len(items.([]int)), 50)
+	}
+}
+
+func Test_Context_CompareAndSwap(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenData       map[string]interface{}
+		givenOld        interface{}
+		givenNew        interface{}
+		expectedSwapped bool
+		expectedData    map[string]interface{}
+	}{
+		{
+			name:            "Can swap an equal value",
+			givenData:       map[string]interface{}{"items": []string{"a"}},
+			givenOld:        []string{"a"},
+			givenNew:        []string{"a", "b"},
+			expectedSwapped: true,
+			expectedData:    map[string]interface{}{"items": []string{"a", "b"}},
+		},
+		{
+			name:            "Can swap a missing key with a nil old value",
+			givenData:       map[string]interface{}{},
+			givenNew:        []string{"a"},
+			expectedSwapped: true,
+			expectedData:    map[string]interface{}{"items": []string{"a"}},
+		},
+		{
+			name:         "Can't swap a different value",
+			givenData:    map[string]interface{}{"items": []string{"a", "b"}},
+			givenOld:     []string{"a"},
+			givenNew:     []string{"a", "c"},
+			expectedData: map[string]interface{}{"items": []string{"a", "b"}},
+		},
+		{
+			name:         "Can't swap a missing key with an old value",
+			givenData:    map[string]interface{}{},
+			givenOld:     []string{"a"},
+			givenNew:     []string{"a", "c"},
+			expectedData: map[string]interface{}{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := NewContext(testCase.givenData)
+
+			swapped := c.CompareAndSwap("items", testCase.givenOld, testCase.givenNew)
+
+			if swapped != testCase.expectedSwapped {
+				t.Errorf("swapped - got: %+v, want: %+v", swapped, testCase.expectedSwapped)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_Context_Update_raceBranches(t *testing.T) {
+	newIncrement := func(name string, delay time.Duration) Node {
+		node, _ := NewActionNode(name, func(c *Context) error {
+			time.Sleep(delay)
+			return c.Update("count", func(old interface{}, found bool) (interface{}, error) {
+				return old.(int) + 1, nil
+			})
+		})
+		return node
+	}
+	race, _ := NewRaceNode("race", newIncrement("fast", 0), newIncrement("slow", 20*time.Millisecond))
+	c := NewContext(map[string]interface{}{"count": 0})
+
+	state := race.Compute(c)
+
+	if state.Value != ContinueState {
+		t.Errorf("state - got: %+v, want: %+v", state, NewContinueComputeState())
+	}
+	if count, _ := c.Read("count"); count != 1 {
+		t.Errorf("count - got: %+v, want: %+v (update of the kept branch only)", count, 1)
+	}
+}
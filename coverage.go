@@ -0,0 +1,84 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// CoverageCollector record the node links exercised by a set of computations of a node system.
+type CoverageCollector struct {
+	system *NodeSystem
+
+	mu           sync.Mutex
+	coveredLinks map[int]bool
+}
+
+// CoverageReport hold the node links coverage of a node system.
+type CoverageReport struct {
+	Covered   int
+	Total     int
+	Uncovered []string
+}
+
+// NewCoverageCollector create a collector of node links coverage on an activated node system.
+func NewCoverageCollector(system *NodeSystem) (*CoverageCollector, error) {
+	if system == nil || !system.IsActivated() {
+		return nil, errors.New("can't collect coverage without an activated node system")
+	}
+	return &CoverageCollector{
+		system:       system,
+		coveredLinks: make(map[int]bool),
+	}, nil
+}
+
+// Add record the node links exercised by a computation result.
+// A link is exercised when its from node continue on the branch of the link.
+func (c *CoverageCollector) Add(result ComputationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for index, link := range c.system.links {
+		state, found := result.Report[c.system.nodeMapKey(link.From)]
+		if !found || state.Value != ContinueState {
+			continue
+		}
+		if link.Branch == nil || (state.Branch != nil && *state.Branch == *link.Branch) {
+			c.coveredLinks[index] = true
+		}
+	}
+}
+
+// Report give the node links coverage of all recorded computations.
+func (c *CoverageCollector) Report() CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report := CoverageReport{
+		Total: len(c.system.links),
+	}
+	for index, link := range c.system.links {
+		if c.coveredLinks[index] {
+			report.Covered++
+		} else {
+			report.Uncovered = append(report.Uncovered, link.String())
+		}
+	}
+	return report
+}
+
+// Percentage give the percentage of covered node links, a node system without links is fully covered.
+func (r CoverageReport) Percentage() float64 {
+	if r.Total == 0 {
+		return 100
+	}
+	return float64(r.Covered) * 100 / float64(r.Total)
+}
+
+// IsComplete tell if all node links are covered.
+func (r CoverageReport) IsComplete() bool {
+	return r.Covered == r.Total
+}
+
+// String print human-readable version of a coverage report
+func (r CoverageReport) String() string {
+	return fmt.Sprintf("%.1f%% of links covered (%v/%v), uncovered: %v", r.Percentage(), r.Covered, r.Total, r.Uncovered)
+}
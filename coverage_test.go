@@ -0,0 +1,95 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewCoverageCollector(t *testing.T) {
+	_, err := NewCoverageCollector(NewNodeSystem())
+
+	expectedError := errors.New("can't collect coverage without an activated node system")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_CoverageCollector_Report(t *testing.T) {
+	readInput, _ := NewActionNode("readInput", func(*Context) error { return nil })
+	inputIsValid, _ := NewDecisionNode("inputIsValid", func(c *Context) (bool, error) {
+		return c.HaveKey("valid"), nil
+	})
+	enrich, _ := NewActionNode("enrich", func(*Context) error { return nil })
+	fallback, _ := NewActionNode("fallback", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(readInput)
+	ns.AddNode(inputIsValid)
+	ns.AddNode(enrich)
+	ns.AddNode(fallback)
+	ns.AddLink(readInput, inputIsValid)
+	ns.AddLinkOnBranch(inputIsValid, enrich, true)
+	ns.AddLinkOnBranch(inputIsValid, fallback, false)
+	ns.ActivateInPlace()
+
+	testCases := []struct {
+		name               string
+		givenData          []map[string]interface{}
+		expectedReport     CoverageReport
+		expectedPercentage float64
+		expectedComplete   bool
+	}{
+		{
+			name: "Without computation",
+			expectedReport: CoverageReport{
+				Total:     3,
+				Uncovered: []string{"{from:'readInput' to:'inputIsValid'}", "{from:'inputIsValid' to:'enrich' branch:true}", "{from:'inputIsValid' to:'fallback' branch:false}"},
+			},
+			expectedPercentage: 0,
+		},
+		{
+			name:      "With one branch exercised",
+			givenData: []map[string]interface{}{{"valid": true}},
+			expectedReport: CoverageReport{
+				Covered:   2,
+				Total:     3,
+				Uncovered: []string{"{from:'inputIsValid' to:'fallback' branch:false}"},
+			},
+			expectedPercentage: 200.0 / 3,
+		},
+		{
+			name:      "With all branches exercised",
+			givenData: []map[string]interface{}{{"valid": true}, {}},
+			expectedReport: CoverageReport{
+				Covered: 3,
+				Total:   3,
+			},
+			expectedPercentage: 100,
+			expectedComplete:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			collector, _ := NewCoverageCollector(ns)
+			for _, data := range testCase.givenData {
+				collector.Add(eng.Compute(data))
+			}
+
+			report := collector.Report()
+
+			if !cmp.Equal(report, testCase.expectedReport) {
+				t.Errorf("report - got: %+v, want: %+v", report, testCase.expectedReport)
+			}
+			if report.Percentage() != testCase.expectedPercentage {
+				t.Errorf("percentage - got: %+v, want: %+v", report.Percentage(), testCase.expectedPercentage)
+			}
+			if report.IsComplete() != testCase.expectedComplete {
+				t.Errorf("complete - got: %+v, want: %+v", report.IsComplete(), testCase.expectedComplete)
+			}
+		})
+	}
+}
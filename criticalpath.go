@@ -0,0 +1,104 @@
+package hoff
+
+import (
+	"errors"
+	"time"
+)
+
+// CriticalPathAnalysis hold the critical path of a node system, the longest chain of linked nodes,
+// and the slack of each node, the time a node can be delayed without delaying the end of the computation.
+// A node with slack is not worth to optimize to shorten the computation.
+type CriticalPathAnalysis struct {
+	Duration time.Duration
+	Path     []Node
+	Slacks   map[Node]time.Duration
+}
+
+// CriticalPath analyze the activated node system with the expected durations of the nodes (their mean),
+// like the configured ones or the ones learned from past reports.
+// A node without duration take no time, and all the links are followed whatever the branch.
+func (s *NodeSystem) CriticalPath(durations map[Node]NodeDuration) (CriticalPathAnalysis, error) {
+	if !s.activated {
+		return CriticalPathAnalysis{}, errors.New("can't analyze critical path of a not activated node system")
+	}
+	durationOf := make(map[string]time.Duration, len(durations))
+	for node, duration := range durations {
+		durationOf[s.nodeID(node)] = duration.Mean
+	}
+
+	order := make([]Node, 0, len(s.nodes))
+	for _, layer := range nodeLayers(s) {
+		order = append(order, layer...)
+	}
+
+	earliestStarts := make(map[string]time.Duration, len(order))
+	var total time.Duration
+	for _, node := range order {
+		id := s.nodeID(node)
+		earliestFinish := earliestStarts[id] + durationOf[id]
+		if earliestFinish > total {
+			total = earliestFinish
+		}
+		for _, followingNode := range s.followingNodes(node) {
+			followingID := s.nodeID(followingNode)
+			if earliestStarts[followingID] < earliestFinish {
+				earliestStarts[followingID] = earliestFinish
+			}
+		}
+	}
+
+	latestStarts := make(map[string]time.Duration, len(order))
+	for index := len(order) - 1; index >= 0; index-- {
+		node := order[index]
+		latestFinish := total
+		for _, followingNode := range s.followingNodes(node) {
+			if latestStart := latestStarts[s.nodeID(followingNode)]; latestStart < latestFinish {
+				latestFinish = latestStart
+			}
+		}
+		latestStarts[s.nodeID(node)] = latestFinish - durationOf[s.nodeID(node)]
+	}
+
+	analysis := CriticalPathAnalysis{
+		Duration: total,
+		Path:     make([]Node, 0),
+		Slacks:   make(map[Node]time.Duration, len(order)),
+	}
+	slacks := make(map[string]time.Duration, len(order))
+	for _, node := range order {
+		id := s.nodeID(node)
+		slacks[id] = latestStarts[id] - earliestStarts[id]
+		analysis.Slacks[s.nodeMapKey(node)] = slacks[id]
+	}
+
+	var current Node
+	for _, node := range order {
+		if id := s.nodeID(node); slacks[id] == 0 && earliestStarts[id] == 0 {
+			current = node
+			break
+		}
+	}
+	for current != nil {
+		analysis.Path = append(analysis.Path, current)
+		earliestFinish := earliestStarts[s.nodeID(current)] + durationOf[s.nodeID(current)]
+		next := Node(nil)
+		for _, followingNode := range s.followingNodes(current) {
+			if id := s.nodeID(followingNode); slacks[id] == 0 && earliestStarts[id] == earliestFinish {
+				next = followingNode
+				break
+			}
+		}
+		current = next
+	}
+	return analysis, nil
+}
+
+// followingNodes give the nodes following a node on all its branches.
+func (s *NodeSystem) followingNodes(node Node) []Node {
+	nodes := make([]Node, 0)
+	for _, branch := range nodeBranches(node) {
+		followingNodes, _ := s.Follow(node, branch)
+		nodes = append(nodes, followingNodes...)
+	}
+	return nodes
+}
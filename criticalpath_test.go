@@ -0,0 +1,58 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_CriticalPath(t *testing.T) {
+	fetch, _ := NewActionNode("fetch", func(*Context) error { return nil })
+	transform, _ := NewActionNode("transform", func(*Context) error { return nil })
+	notify, _ := NewActionNode("notify", func(*Context) error { return nil })
+	store, _ := NewActionNode("store", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(fetch)
+	ns.AddNode(transform)
+	ns.AddNode(notify)
+	ns.AddNode(store)
+	ns.AddLink(fetch, transform)
+	ns.AddLink(fetch, notify)
+	ns.AddLink(transform, store)
+	ns.AddLink(notify, store)
+	ns.ConfigureJoinModeOnNode(store, JoinAnd)
+	ns.IsValid()
+
+	_, err := ns.CriticalPath(nil)
+	expectedError := errors.New("can't analyze critical path of a not activated node system")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("not activated - got: %+v, want: %+v", err, expectedError)
+	}
+	ns.ActivateInPlace()
+
+	analysis, err := ns.CriticalPath(map[Node]NodeDuration{
+		fetch:     {Mean: 10 * time.Millisecond},
+		transform: {Mean: 50 * time.Millisecond},
+		notify:    {Mean: 20 * time.Millisecond},
+		store:     {Mean: 10 * time.Millisecond},
+	})
+	expectedAnalysis := CriticalPathAnalysis{
+		Duration: 70 * time.Millisecond,
+		Path:     []Node{fetch, transform, store},
+		Slacks: map[Node]time.Duration{
+			fetch:     0,
+			transform: 0,
+			notify:    30 * time.Millisecond,
+			store:     0,
+		},
+	}
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(analysis, expectedAnalysis, NodeComparator) {
+		t.Errorf("got: %+v, want: %+v", analysis, expectedAnalysis)
+	}
+}
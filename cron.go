@@ -0,0 +1,155 @@
+package hoff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule hold the allowed values of each field of a cron expression.
+type cronSchedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCronSchedule read a standard cron expression (minute, hour, day of month, month, day of week)
+// or one of the descriptors (@yearly, @monthly, @weekly, @daily, @hourly).
+func parseCronSchedule(spec string) (*cronSchedule, error) {
+	expression := strings.TrimSpace(spec)
+	if descriptor, found := cronDescriptors[expression]; found {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("can't parse cron expression '%v': need 5 fields", spec)
+	}
+
+	bounds := []struct {
+		min, max int
+	}{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	values := make([]map[int]bool, len(fields))
+	for i, field := range fields {
+		fieldValues, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse cron expression '%v': %v", spec, err)
+		}
+		values[i] = fieldValues
+	}
+
+	// sunday can be written as 0 or 7
+	if values[4][7] {
+		values[4][0] = true
+		delete(values[4], 7)
+	}
+
+	return &cronSchedule{
+		minutes:       values[0],
+		hours:         values[1],
+		daysOfMonth:   values[2],
+		months:        values[3],
+		daysOfWeek:    values[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			parsedStep, err := strconv.Atoi(part[index+1:])
+			if err != nil || parsedStep <= 0 {
+				return nil, fmt.Errorf("invalid step in '%v'", part)
+			}
+			step = parsedStep
+			part = part[:index]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			parsedStart, startErr := strconv.Atoi(bounds[0])
+			parsedEnd, endErr := strconv.Atoi(bounds[1])
+			if startErr != nil || endErr != nil {
+				return nil, fmt.Errorf("invalid range '%v'", part)
+			}
+			start, end = parsedStart, parsedEnd
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value '%v'", part)
+			}
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("out of range '%v' (%v-%v)", part, min, max)
+		}
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+// next give the first time matching the schedule strictly after a time.
+// It give a zero time if no time match the schedule in the next five years.
+func (cs *cronSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !cs.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !cs.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !cs.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !cs.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDay follow the cron rule: when both day of month and day of week are restricted,
+// a day matching one of them is enough.
+func (cs *cronSchedule) matchDay(t time.Time) bool {
+	dayOfMonth := cs.daysOfMonth[t.Day()]
+	dayOfWeek := cs.daysOfWeek[int(t.Weekday())]
+	if cs.anyDayOfMonth || cs.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
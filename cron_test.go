@@ -0,0 +1,114 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseCronSchedule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenSpec     string
+		expectedError error
+	}{
+		{
+			name:      "Can parse a cron expression",
+			givenSpec: "*/15 8-18 * 1,6 1-5",
+		},
+		{
+			name:      "Can parse a cron descriptor",
+			givenSpec: "@daily",
+		},
+		{
+			name:          "Can't parse a cron expression without 5 fields",
+			givenSpec:     "* * * *",
+			expectedError: errors.New("can't parse cron expression '* * * *': need 5 fields"),
+		},
+		{
+			name:          "Can't parse a cron expression with invalid value",
+			givenSpec:     "a * * * *",
+			expectedError: errors.New("can't parse cron expression 'a * * * *': invalid value 'a'"),
+		},
+		{
+			name:          "Can't parse a cron expression with out of range value",
+			givenSpec:     "* 24 * * *",
+			expectedError: errors.New("can't parse cron expression '* 24 * * *': out of range '24' (0-23)"),
+		},
+		{
+			name:          "Can't parse a cron expression with invalid step",
+			givenSpec:     "*/0 * * * *",
+			expectedError: errors.New("can't parse cron expression '*/0 * * * *': invalid step in '*/0'"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseCronSchedule(testCase.givenSpec)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_cronSchedule_next(t *testing.T) {
+	// 2019-01-02 is a wednesday
+	after := time.Date(2019, 1, 2, 10, 7, 30, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		givenSpec    string
+		expectedNext time.Time
+	}{
+		{
+			name:         "Every minute",
+			givenSpec:    "* * * * *",
+			expectedNext: time.Date(2019, 1, 2, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name:         "Every quarter of hour",
+			givenSpec:    "*/15 * * * *",
+			expectedNext: time.Date(2019, 1, 2, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:         "Every day at midnight",
+			givenSpec:    "@daily",
+			expectedNext: time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Every sunday",
+			givenSpec:    "30 9 * * 7",
+			expectedNext: time.Date(2019, 1, 6, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "Every first day of month or monday",
+			givenSpec:    "0 0 1 * 1",
+			expectedNext: time.Date(2019, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Every year in march",
+			givenSpec:    "0 12 1 3 *",
+			expectedNext: time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Never",
+			givenSpec: "0 0 31 2 *",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schedule, err := parseCronSchedule(testCase.givenSpec)
+			if err != nil {
+				t.Fatalf("can't parse: %+v", err)
+			}
+
+			next := schedule.next(after)
+			if !next.Equal(testCase.expectedNext) {
+				t.Errorf("got: %+v, want: %+v", next, testCase.expectedNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package hoff
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DashboardStatus is the status of a computation followed by a Dashboard.
+type DashboardStatus string
+
+const (
+	// DashboardRunning is the status of a started computation.
+	DashboardRunning DashboardStatus = "Running"
+	// DashboardPaused is the status of a computation waiting for an external event.
+	DashboardPaused = "Paused"
+	// DashboardSucceeded is the status of a computation ended without error.
+	DashboardSucceeded = "Succeeded"
+	// DashboardFailed is the status of a computation ended on error.
+	DashboardFailed = "Failed"
+)
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Computations</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+.Running { background: #cfe2f3; }
+.Paused { background: #f8d49a; }
+.Succeeded { background: #b7e4b0; }
+.Failed { background: #f4a6a6; }
+</style>
+</head>
+<body>
+<h1>Computations</h1>
+<table>
+<tr><th>ID</th><th>Status</th><th>Started</th><th>Duration</th><th>Nodes</th><th>Error</th></tr>
+{{range .}}<tr class="{{.Status}}"><td><a href="computations/{{.ID}}">{{.ID}}</a></td><td>{{.Status}}</td><td>{{.Started.Format "2006-01-02T15:04:05Z07:00"}}</td><td>{{.Duration}}</td><td>{{.Computed}}/{{.Total}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// DashboardComputation is the summary of a computation followed by a Dashboard.
+type DashboardComputation struct {
+	ID       string
+	Status   DashboardStatus
+	Started  time.Time
+	Duration time.Duration
+	Computed int
+	Total    int
+	Error    string
+}
+
+type dashboardComputation struct {
+	summary DashboardComputation
+	err     error
+	report  map[Node]ComputeState
+}
+
+// Dashboard is an http.Handler serving a dashboard of the computations of an engine,
+// listing the running and the last ended computations with a drill-down to their node states and graph.
+// It follow the computations as an EventSink of the engine,
+// and can be mounted in an existing service with http.StripPrefix.
+type Dashboard struct {
+	system       *NodeSystem
+	endedLimit   int
+	mu           sync.Mutex
+	computations map[string]*dashboardComputation
+	endedOrder   []string
+	startedOrder []string
+	formatter    StateFormatter
+}
+
+// NewDashboard create a dashboard of the computations of an activated node system,
+// keeping the given number of ended computations.
+func NewDashboard(system *NodeSystem, endedLimit int) (*Dashboard, error) {
+	if system == nil || !system.IsActivated() {
+		return nil, errors.New("can't create dashboard without an activated node system")
+	}
+	if endedLimit < 0 {
+		return nil, errors.New("can't create dashboard with a negative limit of ended computations")
+	}
+	return &Dashboard{
+		system:       system,
+		endedLimit:   endedLimit,
+		computations: make(map[string]*dashboardComputation),
+		formatter:    EnglishStateFormatter{},
+	}, nil
+}
+
+// ConfigureStateFormatter configure the formatter of the node states in the computation pages, e.g. to localize them.
+func (d *Dashboard) ConfigureStateFormatter(formatter StateFormatter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.formatter = formatter
+}
+
+// Handle follow the computation events of an engine.
+func (d *Dashboard) Handle(event Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	computation, found := d.computations[event.ComputationID]
+	if !found {
+		if event.Type != ComputationStartedEvent {
+			return
+		}
+		computation = &dashboardComputation{
+			summary: DashboardComputation{
+				ID:      event.ComputationID,
+				Started: event.Time,
+				Total:   len(d.system.nodes),
+			},
+			report: make(map[Node]ComputeState),
+		}
+		d.computations[event.ComputationID] = computation
+		d.startedOrder = append(d.startedOrder, event.ComputationID)
+	}
+
+	switch event.Type {
+	case ComputationStartedEvent:
+		computation.summary.Status = DashboardRunning
+		d.removeEnded(event.ComputationID)
+	case NodeEndedEvent:
+		computation.report[d.system.nodeMapKey(event.Node)] = event.State
+		computation.summary.Computed = len(computation.report)
+	case ComputationEndedEvent:
+		computation.summary.Duration += event.Duration
+		computation.err = event.Error
+		computation.summary.Status = DashboardSucceeded
+		if event.Error != nil {
+			computation.summary.Status = DashboardFailed
+			computation.summary.Error = event.Error.Error()
+		}
+		for _, state := range computation.report {
+			if state.Value == PauseState {
+				computation.summary.Status = DashboardPaused
+			}
+		}
+		d.endedOrder = append(d.endedOrder, event.ComputationID)
+		for len(d.endedOrder) > d.endedLimit {
+			d.forget(d.endedOrder[0])
+		}
+	}
+}
+
+// removeEnded remove a computation from the ended ones, like when a paused computation resume.
+func (d *Dashboard) removeEnded(id string) {
+	for index, endedID := range d.endedOrder {
+		if endedID == id {
+			d.endedOrder = append(d.endedOrder[:index], d.endedOrder[index+1:]...)
+			return
+		}
+	}
+}
+
+func (d *Dashboard) forget(id string) {
+	d.removeEnded(id)
+	delete(d.computations, id)
+	for index, startedID := range d.startedOrder {
+		if startedID == id {
+			d.startedOrder = append(d.startedOrder[:index], d.startedOrder[index+1:]...)
+			break
+		}
+	}
+}
+
+// Computations give the summary of the followed computations, the last started first.
+func (d *Dashboard) Computations() []DashboardComputation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	computations := make([]DashboardComputation, 0, len(d.startedOrder))
+	for index := len(d.startedOrder) - 1; index >= 0; index-- {
+		computations = append(computations, d.computations[d.startedOrder[index]].summary)
+	}
+	return computations
+}
+
+// ServeHTTP serve the list of computations on '/', and the report of a computation on '/computations/<id>'.
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/" || r.URL.Path == "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := dashboardTemplate.Execute(w, d.Computations())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case strings.HasPrefix(r.URL.Path, "/computations/"):
+		result, found := d.result(strings.TrimPrefix(r.URL.Path, "/computations/"))
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		d.mu.Lock()
+		formatter := d.formatter
+		d.mu.Unlock()
+		err := RenderHTMLReportWithFormatter(w, d.system, result, formatter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// result give the computation result known by the dashboard, without the context data.
+func (d *Dashboard) result(id string) (ComputationResult, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	computation, found := d.computations[id]
+	if !found {
+		return ComputationResult{}, false
+	}
+	report := make(map[Node]ComputeState, len(computation.report))
+	for node, state := range computation.report {
+		report[node] = state
+	}
+	return ComputationResult{
+		ID:          id,
+		Fingerprint: d.system.Fingerprint(),
+		Error:       computation.err,
+		Report:      report,
+	}, true
+}
@@ -0,0 +1,130 @@
+package hoff
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_NewDashboard(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenLimit    int
+		expectedError error
+	}{
+		{
+			name:          "Can't create a dashboard without node system",
+			expectedError: errors.New("can't create dashboard without an activated node system"),
+		},
+		{
+			name:          "Can't create a dashboard with a not activated node system",
+			givenSystem:   NewNodeSystem(),
+			expectedError: errors.New("can't create dashboard without an activated node system"),
+		},
+		{
+			name:          "Can't create a dashboard with a negative limit",
+			givenSystem:   activatedNodeSystem(),
+			givenLimit:    -1,
+			expectedError: errors.New("can't create dashboard with a negative limit of ended computations"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewDashboard(testCase.givenSystem, testCase.givenLimit)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func activatedNodeSystem() *NodeSystem {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.ActivateInPlace()
+	return ns
+}
+
+func Test_Dashboard_ServeHTTP(t *testing.T) {
+	ns := activatedNodeSystem()
+	dashboard, _ := NewDashboard(ns, 1)
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.AddEventSink(dashboard)
+
+	forgottenResult := eng.Compute(map[string]interface{}{})
+	result := eng.Compute(map[string]interface{}{})
+
+	computations := dashboard.Computations()
+	expectedComputations := []DashboardComputation{
+		{ID: result.ID, Status: DashboardSucceeded, Computed: 2, Total: 2},
+	}
+	if !cmp.Equal(computations, expectedComputations, cmpopts.IgnoreFields(DashboardComputation{}, "Started", "Duration")) {
+		t.Errorf("computations - got: %+v, want: %+v", computations, expectedComputations)
+	}
+
+	testCases := []struct {
+		name             string
+		givenMethod      string
+		givenPath        string
+		expectedStatus   int
+		expectedContents []string
+	}{
+		{
+			name:             "Can list the computations",
+			givenMethod:      http.MethodGet,
+			givenPath:        "/",
+			expectedStatus:   http.StatusOK,
+			expectedContents: []string{`<a href="computations/` + result.ID + `">`, "<td>Succeeded</td>", "<td>2/2</td>"},
+		},
+		{
+			name:             "Can show a computation",
+			givenMethod:      http.MethodGet,
+			givenPath:        "/computations/" + result.ID,
+			expectedStatus:   http.StatusOK,
+			expectedContents: []string{"<h1>Computation " + result.ID + "</h1>", `<g class="node Continue">`},
+		},
+		{
+			name:           "Can't show a forgotten computation",
+			givenMethod:    http.MethodGet,
+			givenPath:      "/computations/" + forgottenResult.ID,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Can't serve an unknown path",
+			givenMethod:    http.MethodGet,
+			givenPath:      "/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Can't serve another method",
+			givenMethod:    http.MethodPost,
+			givenPath:      "/",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			dashboard.ServeHTTP(recorder, httptest.NewRequest(testCase.givenMethod, testCase.givenPath, nil))
+
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("status - got: %+v, want: %+v", recorder.Code, testCase.expectedStatus)
+			}
+			for _, content := range testCase.expectedContents {
+				if !strings.Contains(recorder.Body.String(), content) {
+					t.Errorf("got: %v, want content: %v", recorder.Body.String(), content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// DeadLetter receive the result of the computations ended in Abort,
+// with their report and final context data.
+type DeadLetter interface {
+	Send(result ComputationResult) error
+}
+
+// ChannelDeadLetter is a DeadLetter who send the results into a channel.
+// Sending block while the channel is full.
+type ChannelDeadLetter struct {
+	Results chan ComputationResult
+}
+
+// NewChannelDeadLetter create a ChannelDeadLetter with a buffered channel.
+func NewChannelDeadLetter(size int) *ChannelDeadLetter {
+	return &ChannelDeadLetter{
+		Results: make(chan ComputationResult, size),
+	}
+}
+
+// Send put the result into the channel.
+func (d *ChannelDeadLetter) Send(result ComputationResult) error {
+	d.Results <- result
+	return nil
+}
+
+// RoutingDeadLetter is a DeadLetter who send the results to another dead letter
+// based on the abort code of the result, or to the default one (if any) for an abort code without route.
+type RoutingDeadLetter struct {
+	Routes  map[AbortCode]DeadLetter
+	Default DeadLetter
+}
+
+// Send put the result into the dead letter of its abort code.
+func (d *RoutingDeadLetter) Send(result ComputationResult) error {
+	deadLetter, found := d.Routes[result.AbortCode()]
+	if !found {
+		deadLetter = d.Default
+	}
+	if deadLetter == nil {
+		return nil
+	}
+	return deadLetter.Send(result)
+}
+
+// FileDeadLetter is a DeadLetter who append the results as JSON lines into a file.
+// A context value who can't be encoded in JSON is written as its string representation.
+type FileDeadLetter struct {
+	mu       sync.Mutex
+	file     *os.File
+	redactor *Redactor
+}
+
+// NewFileDeadLetter create a FileDeadLetter who append to a file (created if needed).
+func NewFileDeadLetter(path string) (*FileDeadLetter, error) {
+	if path == "" {
+		return nil, errors.New("can't create file dead letter without path")
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileDeadLetter{file: file}, nil
+}
+
+// ConfigureRedactor mask the sensitive values of the results before writing them.
+func (d *FileDeadLetter) ConfigureRedactor(redactor *Redactor) {
+	d.redactor = redactor
+}
+
+// Send append the result as a JSON line.
+func (d *FileDeadLetter) Send(result ComputationResult) error {
+	if d.redactor != nil {
+		result = d.redactor.Redact(result)
+	}
+	line, err := json.Marshal(newComputationRecord(result))
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err = d.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close close the file.
+func (d *FileDeadLetter) Close() error {
+	return d.file.Close()
+}
+
+// computationRecord is the JSON representation of a computation result.
+type computationRecord struct {
+	ID          string                 `json:"id"`
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	Data        map[string]interface{} `json:"data"`
+	Outputs     map[string]interface{} `json:"outputs,omitempty"`
+	Report      []nodeStateRecord      `json:"report"`
+}
+
+// nodeStateRecord is the JSON representation of the compute state of a node.
+type nodeStateRecord struct {
+	Node     string       `json:"node"`
+	State    StateType    `json:"state"`
+	Branch   *bool        `json:"branch,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Causes   []string     `json:"causes,omitempty"`
+	Code     AbortCode    `json:"code,omitempty"`
+	Token    string       `json:"token,omitempty"`
+	Override NodeOverride `json:"override,omitempty"`
+}
+
+func newComputationRecord(result ComputationResult) computationRecord {
+	record := computationRecord{
+		ID:          result.ID,
+		Fingerprint: result.Fingerprint,
+		Data:        make(map[string]interface{}),
+		Report:      newNodeStateRecords(result.Report),
+	}
+	if result.Error != nil {
+		record.Error = result.Error.Error()
+	}
+	for key, value := range result.Data {
+		if _, err := json.Marshal(value); err != nil {
+			value = fmt.Sprint(value)
+		}
+		record.Data[key] = value
+	}
+	if result.Outputs != nil {
+		record.Outputs = make(map[string]interface{}, len(result.Outputs))
+		for key := range result.Outputs {
+			record.Outputs[key] = record.Data[key]
+		}
+	}
+	return record
+}
+
+func newNodeStateRecords(report map[Node]ComputeState) []nodeStateRecord {
+	records := make([]nodeStateRecord, 0, len(report))
+	for node, state := range report {
+		record := nodeStateRecord{
+			Node:     fmt.Sprint(node),
+			State:    state.Value,
+			Branch:   state.Branch,
+			Code:     state.Code,
+			Token:    state.Token,
+			Override: state.Override,
+		}
+		if state.Error != nil {
+			record.Error = state.Error.Error()
+			record.Causes = errorCauses(state.Error)
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Node < records[j].Node
+	})
+	return records
+}
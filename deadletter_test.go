@@ -0,0 +1,112 @@
+package hoff
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ChannelDeadLetter_Send(t *testing.T) {
+	errorAction, _ := NewActionNode("errorAction", func(*Context) error {
+		return errors.New("action error")
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(errorAction)
+	ns.ActivateInPlace()
+
+	deadLetter := NewChannelDeadLetter(1)
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureDeadLetter(deadLetter)
+
+	result := eng.Compute(map[string]interface{}{"key": "value"})
+	deadResult := <-deadLetter.Results
+
+	if !cmp.Equal(deadResult, result, NodeComparator, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", deadResult, result)
+	}
+}
+
+func Test_RoutingDeadLetter_Send(t *testing.T) {
+	transient := NewChannelDeadLetter(1)
+	fallback := NewChannelDeadLetter(1)
+	deadLetter := &RoutingDeadLetter{
+		Routes:  map[AbortCode]DeadLetter{TransientAbort: transient},
+		Default: fallback,
+	}
+
+	testCases := []struct {
+		name               string
+		givenCode          AbortCode
+		expectedDeadLetter *ChannelDeadLetter
+	}{
+		{
+			name:               "Can route a result by its abort code",
+			givenCode:          TransientAbort,
+			expectedDeadLetter: transient,
+		},
+		{
+			name:               "Can route a result without route to the default dead letter",
+			givenCode:          ValidationAbort,
+			expectedDeadLetter: fallback,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := ComputationResult{
+				ID:     testCase.name,
+				Report: map[Node]ComputeState{someActionNode: NewAbortWithCodeComputeState(testCase.givenCode, errors.New("error"))},
+			}
+			deadLetter.Send(result)
+
+			select {
+			case deadResult := <-testCase.expectedDeadLetter.Results:
+				if deadResult.ID != result.ID {
+					t.Errorf("got: %+v, want: %+v", deadResult.ID, result.ID)
+				}
+			default:
+				t.Errorf("got: no result, want: %+v", result.ID)
+			}
+		})
+	}
+}
+
+func Test_NewFileDeadLetter(t *testing.T) {
+	_, err := NewFileDeadLetter("")
+	expectedError := errors.New("can't create file dead letter without path")
+
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_FileDeadLetter_Send(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "hoff")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "deadletter.jsonl")
+
+	errorAction, _ := NewActionNode("errorAction", func(*Context) error {
+		return errors.New("action error")
+	})
+	deadLetter, _ := NewFileDeadLetter(path)
+	deadLetter.Send(ComputationResult{
+		ID:    "id",
+		Error: errors.New("action error"),
+		Data:  map[string]interface{}{"key": "value"},
+		Report: map[Node]ComputeState{
+			errorAction: NewAbortComputeState(errors.New("action error")),
+		},
+	})
+	deadLetter.Close()
+
+	content, _ := ioutil.ReadFile(path)
+	expectedContent := `{"id":"id","error":"action error","data":{"key":"value"},"report":[{"node":"errorAction","state":"Abort","error":"action error"}]}` + "\n"
+
+	if string(content) != expectedContent {
+		t.Errorf("got: %s, want: %s", content, expectedContent)
+	}
+}
@@ -0,0 +1,115 @@
+package hoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SystemDescription is a serializable description of a node system.
+type SystemDescription struct {
+	Nodes []NodeDescription `json:"nodes"`
+	Links []LinkDescription `json:"links"`
+	// Inputs is the input schema of the node system
+	Inputs []InputParameter `json:"inputs,omitempty"`
+	// Outputs is the output contract of the node system
+	Outputs []OutputParameter `json:"outputs,omitempty"`
+}
+
+// NodeDescription is a serializable description of a node.
+type NodeDescription struct {
+	Name     string   `json:"name"`
+	Decision bool     `json:"decision,omitempty"`
+	JoinMode JoinMode `json:"join_mode,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// Expression is the boolean expression of a decision node evaluated over the context data (see ExpressionDecisionNode)
+	Expression string `json:"expression,omitempty"`
+	// Transforms are the transforms of a transform node (see TransformNode)
+	Transforms []Transform `json:"transforms,omitempty"`
+	// Type is the registered node type of the node (see RegisterNodeType)
+	Type string `json:"type,omitempty"`
+	// Config is the config of the node given to its node type factory
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// LinkDescription is a serializable description of a link between two nodes.
+type LinkDescription struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Branch   *bool             `json:"branch,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Describe give a description of the nodes and links of the node system,
+// with the nodes named after their string representation.
+func (s *NodeSystem) Describe() SystemDescription {
+	description := SystemDescription{
+		Nodes: make([]NodeDescription, 0, len(s.nodes)),
+		Links: make([]LinkDescription, 0, len(s.links)),
+	}
+	for _, node := range s.nodes {
+		nodeDescription := NodeDescription{
+			Name:     fmt.Sprint(node),
+			Decision: node.DecideCapability(),
+			Tags:     s.TagsOfNode(node),
+		}
+		if expressionNode, ok := node.(*ExpressionDecisionNode); ok {
+			nodeDescription.Expression = expressionNode.Expression()
+		}
+		if transformNode, ok := node.(*TransformNode); ok {
+			nodeDescription.Transforms = transformNode.Transforms()
+		}
+		nodeDescription.Type, nodeDescription.Config = s.TypeOfNode(node)
+		if mode := s.JoinModeOfNode(node); mode != JoinNone {
+			nodeDescription.JoinMode = mode
+		}
+		description.Nodes = append(description.Nodes, nodeDescription)
+	}
+	for _, link := range s.links {
+		description.Links = append(description.Links, LinkDescription{
+			From:     fmt.Sprint(link.From),
+			To:       fmt.Sprint(link.To),
+			Branch:   link.Branch,
+			Metadata: link.Metadata,
+		})
+	}
+	description.Inputs = s.InputSchema()
+	description.Outputs = s.OutputContract()
+	return description
+}
+
+// systemDescriptionJSON is the JSON format of a system description, without its methods.
+type systemDescriptionJSON SystemDescription
+
+// MarshalJSON encode the description as JSON, with the FormatVersion.
+func (d SystemDescription) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		FormatVersion int `json:"format_version"`
+		systemDescriptionJSON
+	}{FormatVersion, systemDescriptionJSON(d)})
+}
+
+// UnmarshalJSON decode a description from JSON, a missing format version being the version 1.
+// Unsupported format versions give an ErrUnsupportedFormatVersion.
+func (d *SystemDescription) UnmarshalJSON(data []byte) error {
+	var description struct {
+		FormatVersion int64 `json:"format_version"`
+		systemDescriptionJSON
+	}
+	err := json.Unmarshal(data, &description)
+	if err != nil {
+		return err
+	}
+	if err := checkFormatVersion(description.FormatVersion); err != nil {
+		return fmt.Errorf("can't unmarshal system description: %w", err)
+	}
+	*d = SystemDescription(description.systemDescriptionJSON)
+	return nil
+}
+
+// ExportJSON write the description of the node system as JSON.
+func (s *NodeSystem) ExportJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s.Describe())
+}
@@ -0,0 +1,86 @@
+package hoff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_Describe(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("keyIsPresent", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	readKey, _ := NewActionNode("readKey", func(*Context) error { return nil })
+	writeKey, _ := NewActionNode("writeKey", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(readKey)
+	ns.AddNode(writeKey)
+	ns.AddLinkOnBranch(keyIsPresent, readKey, true)
+	ns.AddLinkOnBranch(keyIsPresent, writeKey, false)
+	ns.AddLink(readKey, writeKey)
+	ns.ConfigureJoinModeOnNode(writeKey, JoinOr)
+	ns.ConfigureMetadataOnLink(readKey, writeKey, nil, map[string]string{"owner": "team"})
+
+	description := ns.Describe()
+
+	expectedDescription := SystemDescription{
+		Nodes: []NodeDescription{
+			{Name: "keyIsPresent", Decision: true},
+			{Name: "readKey"},
+			{Name: "writeKey", JoinMode: JoinOr},
+		},
+		Links: []LinkDescription{
+			{From: "keyIsPresent", To: "readKey", Branch: boolPointer(true)},
+			{From: "keyIsPresent", To: "writeKey", Branch: boolPointer(false)},
+			{From: "readKey", To: "writeKey", Metadata: map[string]string{"owner": "team"}},
+		},
+	}
+	if !cmp.Equal(description, expectedDescription) {
+		t.Errorf("got: %+v, want: %+v", description, expectedDescription)
+	}
+}
+
+func Test_NodeSystem_ExportJSON(t *testing.T) {
+	readKey, _ := NewActionNode("readKey", func(*Context) error { return nil })
+	writeKey, _ := NewActionNode("writeKey", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(readKey)
+	ns.AddNode(writeKey)
+	ns.AddLink(readKey, writeKey)
+	ns.ConfigureMetadataOnLink(readKey, writeKey, nil, map[string]string{"owner": "team"})
+
+	var buffer bytes.Buffer
+	err := ns.ExportJSON(&buffer)
+
+	expectedJSON := `{
+  "format_version": 2,
+  "nodes": [
+    {
+      "name": "readKey"
+    },
+    {
+      "name": "writeKey"
+    }
+  ],
+  "links": [
+    {
+      "from": "readKey",
+      "to": "writeKey",
+      "metadata": {
+        "owner": "team"
+      }
+    }
+  ]
+}
+`
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(buffer.String(), expectedJSON) {
+		t.Errorf("got: %+v, want: %+v", buffer.String(), expectedJSON)
+	}
+}
@@ -0,0 +1,154 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DirectoryMessageSource is a MessageSource who watch a directory, and give a message for each file appearing in it
+// with a name matching a pattern (see filepath.Match), to trigger a computation by file through a QueueRunner.
+// The message ID is the path of the file, and its headers hold the 'path' and 'name' of the file.
+// As the directory is polled, a file must be written elsewhere then moved into the directory.
+//
+// By default, an acked file is removed, and a nacked file is kept but not given again until a restart of the source.
+type DirectoryMessageSource struct {
+	dir      string
+	pattern  string
+	interval time.Duration
+
+	doneDir   string
+	failedDir string
+
+	mu      sync.Mutex
+	pending map[string]bool
+	ignored map[string]bool
+}
+
+// NewDirectoryMessageSource create a DirectoryMessageSource based on a directory
+// and the pattern of the names of the files, polling the directory every second.
+func NewDirectoryMessageSource(dir, pattern string) (*DirectoryMessageSource, error) {
+	if dir == "" {
+		return nil, errors.New("can't create directory message source without directory")
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("can't create directory message source with invalid pattern '%v': %v", pattern, err)
+	}
+	return &DirectoryMessageSource{
+		dir:      dir,
+		pattern:  pattern,
+		interval: time.Second,
+		pending:  make(map[string]bool),
+		ignored:  make(map[string]bool),
+	}, nil
+}
+
+// ConfigurePollInterval replace the interval between two polls of the directory.
+func (s *DirectoryMessageSource) ConfigurePollInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("can't configure poll interval not positive: %v", interval)
+	}
+	s.interval = interval
+	return nil
+}
+
+// ConfigureArchiveDirectories move the acked files into the done directory,
+// and the nacked files into the failed directory, instead of removing or keeping them.
+func (s *DirectoryMessageSource) ConfigureArchiveDirectories(doneDir, failedDir string) error {
+	if doneDir == "" || failedDir == "" {
+		return errors.New("can't configure archive directories without done and failed directories")
+	}
+	s.doneDir = doneDir
+	s.failedDir = failedDir
+	return nil
+}
+
+// Receive wait for the next file in the directory.
+func (s *DirectoryMessageSource) Receive(ctx context.Context) (*Message, error) {
+	for {
+		message, err := s.next()
+		if err != nil || message != nil {
+			return message, err
+		}
+		timer := time.NewTimer(s.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Ack remove the file of the message, or move it into the done directory.
+func (s *DirectoryMessageSource) Ack(ctx context.Context, m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, m.ID)
+	if s.doneDir == "" {
+		err := os.Remove(m.ID)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("can't ack file '%v': %w", m.ID, err)
+		}
+		return nil
+	}
+	return s.archive(m.ID, s.doneDir)
+}
+
+// Nack keep the file of the message without giving it again, or move it into the failed directory.
+func (s *DirectoryMessageSource) Nack(ctx context.Context, m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, m.ID)
+	if s.failedDir == "" {
+		s.ignored[m.ID] = true
+		return nil
+	}
+	return s.archive(m.ID, s.failedDir)
+}
+
+// next give a message for the first file not yet given, sorted by name, or nil without such file.
+func (s *DirectoryMessageSource) next() (*Message, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("can't watch directory '%v': %w", s.dir, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if matched, _ := filepath.Match(s.pattern, file.Name()); !matched {
+			continue
+		}
+		path := filepath.Join(s.dir, file.Name())
+		if s.pending[path] || s.ignored[path] {
+			continue
+		}
+		s.pending[path] = true
+		return &Message{
+			ID:      path,
+			Key:     []byte(file.Name()),
+			Headers: map[string]string{"path": path, "name": file.Name()},
+		}, nil
+	}
+	return nil, nil
+}
+
+func (s *DirectoryMessageSource) archive(path, dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err == nil {
+		err = os.Rename(path, filepath.Join(dir, filepath.Base(path)))
+	}
+	if err != nil {
+		s.ignored[path] = true
+		return fmt.Errorf("can't archive file '%v': %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewDirectoryMessageSource(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenDir      string
+		givenPattern  string
+		expectedError error
+	}{
+		{
+			name:         "Can create a directory message source",
+			givenDir:     "inbox",
+			givenPattern: "*.json",
+		},
+		{
+			name:          "Can't create a directory message source without directory",
+			givenPattern:  "*.json",
+			expectedError: errors.New("can't create directory message source without directory"),
+		},
+		{
+			name:          "Can't create a directory message source with invalid pattern",
+			givenDir:      "inbox",
+			givenPattern:  "[*.json",
+			expectedError: errors.New("can't create directory message source with invalid pattern '[*.json': syntax error in pattern"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewDirectoryMessageSource(testCase.givenDir, testCase.givenPattern)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_DirectoryMessageSource_QueueRunner(t *testing.T) {
+	testCases := []struct {
+		name              string
+		givenArchive      bool
+		expectedInbox     []string
+		expectedDone      []string
+		expectedFailed    []string
+		expectedProcessed []string
+	}{
+		{
+			name:              "Can remove the acked files, and keep the nacked files",
+			expectedInbox:     []string{"bad.json", "notes.txt"},
+			expectedProcessed: []string{"42.json", "43.json", "bad.json"},
+		},
+		{
+			name:              "Can archive the acked and nacked files",
+			givenArchive:      true,
+			expectedInbox:     []string{"notes.txt"},
+			expectedDone:      []string{"42.json", "43.json"},
+			expectedFailed:    []string{"bad.json"},
+			expectedProcessed: []string{"42.json", "43.json", "bad.json"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir, _ := ioutil.TempDir("", "hoff")
+			defer os.RemoveAll(dir)
+			inbox := filepath.Join(dir, "inbox")
+			os.Mkdir(inbox, 0755)
+			ioutil.WriteFile(filepath.Join(inbox, "42.json"), []byte(`{"id": 42}`), 0644)
+			ioutil.WriteFile(filepath.Join(inbox, "bad.json"), []byte(`{`), 0644)
+			ioutil.WriteFile(filepath.Join(inbox, "notes.txt"), []byte(`notes`), 0644)
+
+			source, _ := NewDirectoryMessageSource(inbox, "*.json")
+			source.ConfigurePollInterval(time.Millisecond)
+			if testCase.givenArchive {
+				source.ConfigureArchiveDirectories(filepath.Join(dir, "done"), filepath.Join(dir, "failed"))
+			}
+			read, _ := NewFileReadNode("read", "{{.path}}", "content")
+			query, _ := NewJSONQueryNode("query", ".id", "content", "id")
+			ns := NewNodeSystem()
+			ns.AddNode(read)
+			ns.AddNode(query)
+			ns.AddLink(read, query)
+			ns.ActivateInPlace()
+			engine := NewEngine(SequentialComputation)
+			engine.ConfigureNodeSystem(ns)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var mu sync.Mutex
+			processed := make([]string, 0)
+			runner, _ := NewQueueRunner(engine, source, func(m *Message) (map[string]interface{}, error) {
+				return map[string]interface{}{"path": m.Headers["path"]}, nil
+			})
+			runner.ConfigureResultHandler(func(m *Message, result ComputationResult) {
+				mu.Lock()
+				defer mu.Unlock()
+				processed = append(processed, string(m.Key))
+				if len(processed) == 1 {
+					ioutil.WriteFile(filepath.Join(inbox, "43.json"), []byte(`{"id": 43}`), 0644)
+				}
+				if len(processed) == 3 {
+					cancel()
+				}
+			})
+
+			err := runner.Run(ctx)
+
+			if err != context.Canceled {
+				t.Errorf("error - got: %+v, want: %+v", err, context.Canceled)
+			}
+			sort.Strings(processed)
+			if !cmp.Equal(processed, testCase.expectedProcessed) {
+				t.Errorf("processed - got: %+v, want: %+v", processed, testCase.expectedProcessed)
+			}
+			for directory, expectedFiles := range map[string][]string{"inbox": testCase.expectedInbox, "done": testCase.expectedDone, "failed": testCase.expectedFailed} {
+				files := make([]string, 0)
+				infos, _ := ioutil.ReadDir(filepath.Join(dir, directory))
+				for _, info := range infos {
+					if !info.IsDir() {
+						files = append(files, info.Name())
+					}
+				}
+				if !cmp.Equal(files, append([]string{}, expectedFiles...)) {
+					t.Errorf("%v - got: %+v, want: %+v", directory, files, expectedFiles)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package hoff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ExportDOT write the node system as a Graphviz DOT graph.
+// A decision node is drawn as a diamond, and a link is labeled with its branch and its metadata.
+func (s *NodeSystem) ExportDOT(w io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph {\n")
+	for _, node := range s.nodes {
+		shape := "box"
+		if node.DecideCapability() {
+			shape = "diamond"
+		}
+		fmt.Fprintf(&builder, "  %v [shape=%v];\n", dotQuote(fmt.Sprint(node)), shape)
+	}
+	for _, link := range s.links {
+		labels := make([]string, 0, len(link.Metadata)+1)
+		if link.Branch != nil {
+			labels = append(labels, fmt.Sprint(*link.Branch))
+		}
+		keys := make([]string, 0, len(link.Metadata))
+		for key := range link.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			labels = append(labels, fmt.Sprintf("%v: %v", key, link.Metadata[key]))
+		}
+
+		fmt.Fprintf(&builder, "  %v -> %v", dotQuote(fmt.Sprint(link.From)), dotQuote(fmt.Sprint(link.To)))
+		if len(labels) > 0 {
+			fmt.Fprintf(&builder, " [label=%v]", dotQuote(strings.Join(labels, "\n")))
+		}
+		builder.WriteString(";\n")
+	}
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// dotQuote give a DOT quoted string.
+func dotQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
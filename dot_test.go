@@ -0,0 +1,37 @@
+package hoff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ExportDOT(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("key is present", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	readKey, _ := NewActionNode(`read "key"`, func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(readKey)
+	ns.AddLinkOnBranch(keyIsPresent, readKey, true)
+	ns.ConfigureMetadataOnLink(keyIsPresent, readKey, boolPointer(true), map[string]string{"sla": "1s", "owner": "team"})
+
+	var buffer bytes.Buffer
+	err := ns.ExportDOT(&buffer)
+
+	expectedDOT := `digraph {
+  "key is present" [shape=diamond];
+  "read \"key\"" [shape=box];
+  "key is present" -> "read \"key\"" [label="true\nowner: team\nsla: 1s"];
+}
+`
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(buffer.String(), expectedDOT) {
+		t.Errorf("got: %+v, want: %+v", buffer.String(), expectedDOT)
+	}
+}
@@ -0,0 +1,150 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+)
+
+// SMTPConfig is the configuration of the SMTP server used to send emails.
+type SMTPConfig struct {
+	// Address of the server (host:port)
+	Address string
+	// Username and Password to authenticate on the server (PLAIN auth), if any
+	Username string
+	Password string
+	// From is the sender of the emails
+	From string
+}
+
+// EmailTemplate is the templates (see text/template) of an email, executed on the context data
+// (e.g. '{{.order.id}}'), To give the recipients separated by commas.
+type EmailTemplate struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailNode is a type of Node who send a templated email through a SMTP server,
+// and decide on the true branch if the email is sent, or on the false branch otherwise.
+type EmailNode struct {
+	name     string
+	config   SMTPConfig
+	to       *template.Template
+	subject  *template.Template
+	body     *template.Template
+	errorKey string
+	sendMail func(address string, auth smtp.Auth, from string, to []string, message []byte) error
+}
+
+func (n EmailNode) String() string {
+	return n.name
+}
+
+// Compute execute the templates and send the email. A failure to send the email is a decision,
+// the error is stored in the error key if configured.
+// An email who can't be build from the context abort the computation.
+func (n *EmailNode) Compute(c *Context) ComputeState {
+	to, err := n.execute(n.to, c)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	subject, err := n.execute(n.subject, c)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	body, err := n.execute(n.body, c)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	recipients := make([]string, 0)
+	for _, recipient := range strings.Split(to, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	if len(recipients) == 0 {
+		return NewAbortComputeState(fmt.Errorf("can't send email of node '%v' without recipient", n.name))
+	}
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return NewAbortComputeState(fmt.Errorf("can't send email of node '%v' with multi-line recipients or subject", n.name))
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %v\r\n", n.config.From)
+	fmt.Fprintf(&message, "To: %v\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&message, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", subject))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	message.WriteString(body)
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		host, _, _ := net.SplitHostPort(n.config.Address)
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, host)
+	}
+	err = n.sendMail(n.config.Address, auth, n.config.From, recipients, message.Bytes())
+	if err != nil {
+		if n.errorKey != "" {
+			c.Store(n.errorKey, fmt.Sprintf("can't send email of node '%v': %v", n.name, err))
+		}
+		return NewContinueOnBranchComputeState(false)
+	}
+	if n.errorKey != "" {
+		c.Delete(n.errorKey)
+	}
+	return NewContinueOnBranchComputeState(true)
+}
+
+// DecideCapability is actived due to the fact that the sending of the email is a decision.
+func (n *EmailNode) DecideCapability() bool {
+	return true
+}
+
+// ConfigureErrorKey store the error of a failed sending into a context key.
+func (n *EmailNode) ConfigureErrorKey(key string) {
+	n.errorKey = key
+}
+
+func (n *EmailNode) execute(t *template.Template, c *Context) (string, error) {
+	var buffer bytes.Buffer
+	err := t.Execute(&buffer, c.Data)
+	if err != nil {
+		return "", fmt.Errorf("can't build email of node '%v': %v", n.name, err)
+	}
+	return buffer.String(), nil
+}
+
+// NewEmailNode create an EmailNode based on a name, the configuration of the SMTP server,
+// and the templates of the email, parsed at creation.
+func NewEmailNode(name string, config SMTPConfig, email EmailTemplate) (*EmailNode, error) {
+	if config.Address == "" || config.From == "" {
+		return nil, errors.New("can't create email node without server address and sender")
+	}
+	if _, _, err := net.SplitHostPort(config.Address); err != nil {
+		return nil, fmt.Errorf("can't create email node with invalid server address: %v", err)
+	}
+	node := &EmailNode{name: name, config: config, sendMail: smtp.SendMail}
+	templates := []struct {
+		part     string
+		source   string
+		template **template.Template
+	}{
+		{"to", email.To, &node.to},
+		{"subject", email.Subject, &node.subject},
+		{"body", email.Body, &node.body},
+	}
+	for _, t := range templates {
+		parsed, err := template.New(t.part).Option("missingkey=error").Parse(t.source)
+		if err != nil {
+			return nil, fmt.Errorf("can't create email node with invalid %v template: %v", t.part, err)
+		}
+		*t.template = parsed
+	}
+	return node, nil
+}
@@ -0,0 +1,235 @@
+package hoff
+
+import (
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// fakeSMTP serve the commands used to send an email on a local listener, and keep the received emails.
+type fakeSMTP struct {
+	listener net.Listener
+	password string
+	reject   bool
+	mu       sync.Mutex
+	emails   []string
+}
+
+func newFakeSMTP(t *testing.T, password string, reject bool) *fakeSMTP {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeSMTP{listener: listener, password: password, reject: reject}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ready")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case command == "EHLO" && s.password != "":
+			text.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+		case command == "EHLO":
+			text.PrintfLine("250 localhost")
+		case command == "AUTH" && strings.HasSuffix(line, fakeSMTPCredentials(s.password)):
+			text.PrintfLine("235 authenticated")
+		case command == "AUTH":
+			text.PrintfLine("535 invalid credentials")
+		case command == "RCPT" && s.reject:
+			text.PrintfLine("550 unknown recipient")
+		case command == "MAIL" || command == "RCPT":
+			text.PrintfLine("250 ok")
+		case command == "DATA":
+			text.PrintfLine("354 go ahead")
+			lines, err := text.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.emails = append(s.emails, strings.Join(lines, "\n"))
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case command == "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 unknown command")
+		}
+	}
+}
+
+func (s *fakeSMTP) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.emails...)
+}
+
+func fakeSMTPCredentials(password string) string {
+	return base64.StdEncoding.EncodeToString([]byte("\x00bob\x00" + password))
+}
+
+func Test_NewEmailNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenConfig   SMTPConfig
+		givenEmail    EmailTemplate
+		expectedError error
+	}{
+		{
+			name:        "Can create an email node",
+			givenConfig: SMTPConfig{Address: "localhost:25", From: "hoff@localhost"},
+			givenEmail:  EmailTemplate{To: "{{.email}}", Subject: "Order {{.order}}", Body: "Hello"},
+		},
+		{
+			name:          "Can't create an email node without sender",
+			givenConfig:   SMTPConfig{Address: "localhost:25"},
+			expectedError: errors.New("can't create email node without server address and sender"),
+		},
+		{
+			name:          "Can't create an email node with invalid server address",
+			givenConfig:   SMTPConfig{Address: "localhost", From: "hoff@localhost"},
+			expectedError: errors.New("can't create email node with invalid server address: address localhost: missing port in address"),
+		},
+		{
+			name:          "Can't create an email node with invalid template",
+			givenConfig:   SMTPConfig{Address: "localhost:25", From: "hoff@localhost"},
+			givenEmail:    EmailTemplate{To: "{{.email}}", Subject: "Order {{.order"},
+			expectedError: errors.New("can't create email node with invalid subject template: template: subject:1: unclosed action"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewEmailNode("email", testCase.givenConfig, testCase.givenEmail)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_EmailNode_Compute(t *testing.T) {
+	email := EmailTemplate{
+		To:      "{{.email}}, ops@localhost",
+		Subject: "Order {{.order}} to approve",
+		Body:    "Hello,\n\nPlease approve order {{.order}}.\n.\n",
+	}
+	expectedEmail := strings.Join([]string{
+		"From: hoff@localhost",
+		"To: bob@localhost, ops@localhost",
+		"Subject: Order 42 to approve",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		"Hello,",
+		"",
+		"Please approve order 42.",
+		".",
+	}, "\n")
+
+	testCases := []struct {
+		name           string
+		givenPassword  string
+		givenConfig    SMTPConfig
+		givenReject    bool
+		givenData      map[string]interface{}
+		expectedState  ComputeState
+		expectedData   map[string]interface{}
+		expectedEmails []string
+	}{
+		{
+			name:           "Can send an email",
+			givenData:      map[string]interface{}{"email": "bob@localhost", "order": 42},
+			expectedState:  NewContinueOnBranchComputeState(true),
+			expectedData:   map[string]interface{}{"email": "bob@localhost", "order": 42},
+			expectedEmails: []string{expectedEmail},
+		},
+		{
+			name:           "Can send an email with authentication",
+			givenPassword:  "secret",
+			givenConfig:    SMTPConfig{Username: "bob", Password: "secret"},
+			givenData:      map[string]interface{}{"email": "bob@localhost", "order": 42, "email_error": "previous error"},
+			expectedState:  NewContinueOnBranchComputeState(true),
+			expectedData:   map[string]interface{}{"email": "bob@localhost", "order": 42},
+			expectedEmails: []string{expectedEmail},
+		},
+		{
+			name:          "Can decide on failure to authenticate",
+			givenPassword: "secret",
+			givenConfig:   SMTPConfig{Username: "bob", Password: "guess"},
+			givenData:     map[string]interface{}{"email": "bob@localhost", "order": 42},
+			expectedState: NewContinueOnBranchComputeState(false),
+			expectedData:  map[string]interface{}{"email": "bob@localhost", "order": 42, "email_error": "can't send email of node 'email': 535 \"invalid credentials\""},
+		},
+		{
+			name:          "Can decide on rejected email",
+			givenReject:   true,
+			givenData:     map[string]interface{}{"email": "bob@localhost", "order": 42},
+			expectedState: NewContinueOnBranchComputeState(false),
+			expectedData:  map[string]interface{}{"email": "bob@localhost", "order": 42, "email_error": "can't send email of node 'email': 550 \"unknown recipient\""},
+		},
+		{
+			name:          "Can't send an email with missing key",
+			givenData:     map[string]interface{}{"email": "bob@localhost"},
+			expectedState: NewAbortComputeState(errors.New(`can't build email of node 'email': template: subject:1:8: executing "subject" at <.order>: map has no entry for key "order"`)),
+			expectedData:  map[string]interface{}{"email": "bob@localhost"},
+		},
+		{
+			name:          "Can't send an email with multi-line recipients",
+			givenData:     map[string]interface{}{"email": "bob@localhost\r\nBcc: eve@localhost", "order": 42},
+			expectedState: NewAbortComputeState(errors.New("can't send email of node 'email' with multi-line recipients or subject")),
+			expectedData:  map[string]interface{}{"email": "bob@localhost\r\nBcc: eve@localhost", "order": 42},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newFakeSMTP(t, testCase.givenPassword, testCase.givenReject)
+			defer server.listener.Close()
+			config := testCase.givenConfig
+			config.Address = server.listener.Addr().String()
+			config.From = "hoff@localhost"
+			node, err := NewEmailNode("email", config, email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			node.ConfigureErrorKey("email_error")
+			c := NewContext(testCase.givenData)
+
+			state := node.Compute(c)
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+			if !cmp.Equal(server.received(), testCase.expectedEmails, cmpopts.EquateEmpty()) {
+				t.Errorf("emails - got: %q, want: %q", server.received(), testCase.expectedEmails)
+			}
+		})
+	}
+}
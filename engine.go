@@ -1,13 +1,83 @@
 package hoff
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Engine expose an engine to manage multiple computations based on a node system.
 type Engine struct {
-	mode   ComputationMode
-	system *NodeSystem
+	mode             ComputationMode
+	system           *NodeSystem
+	progressCallback func(string, ComputationProgress)
+	nodesConcurrency map[string]chan struct{}
+	tagsPolicies     map[string]*tagPolicyState
+	deadLetter       DeadLetter
+	reportStore      ReportStore
+	checkpointStore  CheckpointStore
+	guarantee        ExecutionGuarantee
+	locker           Locker
+	lockTTL          time.Duration
+	workflow         string
+	flagProvider     FlagProvider
+	disabledNodeMode DisabledNodeMode
+	eventSinks       []EventSink
+	eventStore       EventStore
+	stepper          Stepper
+	strict           bool
+	atomicWrites     bool
+	sloTracker       *SLOTracker
+	budget           ComputationBudget
+	contextStore     ContextStore
+	cipher           Cipher
+	snapshots        bool
+	successPredicate func(ComputationResult) bool
+	panicPolicy      PanicPolicy
+	pool             *workerPool
+	sharedPool       bool
+	poolWorkflow     string
+	workQueue        WorkQueue
+	workQueuePoll    time.Duration
+	queueBound       int
+
+	mu                  sync.Mutex
+	shutdown            bool
+	pendingComputations int
+	rejectedSubmissions uint64
+	runningComputations map[string]*Computation
+	pausedComputations  map[string]pausedComputation
+	idempotencyKeys     map[string]bool
+	tenantQuotas        map[string]TenantQuota
+	tenantSlots         map[string]chan struct{}
+	tenantRunning       map[string]int
+	tenantRejected      map[string]uint64
+	inFlight            sync.WaitGroup
+
+	lifecycleMu      sync.Mutex
+	nodesInitialized bool
+}
+
+// ErrQueueFull is the error when the pending computations of an engine reach the queue bound.
+var ErrQueueFull = errors.New("can't submit computation, queue is full")
+
+// QueueMetrics hold the state of the pending computations queue of an engine.
+type QueueMetrics struct {
+	// Depth is the number of submitted computations not ended
+	Depth int
+	// Bound is the maximum depth accepted by TrySubmit, 0 for no bound
+	Bound int
+	// Rejected is the number of submissions rejected by TrySubmit
+	Rejected uint64
+}
+
+type pausedComputation struct {
+	computation *Computation
+	node        Node
 }
 
 // NewEngine create an engine with computation mode.
@@ -30,8 +100,176 @@ func (e *Engine) ConfigureNodeSystem(system *NodeSystem) error {
 	return nil
 }
 
+// ConfigureProgressCallback add a function called with the computation ID
+// and its progress each time a node is completed.
+func (e *Engine) ConfigureProgressCallback(callback func(string, ComputationProgress)) {
+	e.progressCallback = callback
+}
+
+// ConfigureConcurrencyOnNode limit the number of simultaneous computations of a node
+// across all computations running on the engine, it need a configured node system.
+func (e *Engine) ConfigureConcurrencyOnNode(n Node, max int) error {
+	if max <= 0 {
+		return fmt.Errorf("can't limit concurrency of node '%v' under 1: %v", n, max)
+	}
+	if e.system == nil {
+		return fmt.Errorf("can't limit concurrency of node '%v' without node system", n)
+	}
+	if e.nodesConcurrency == nil {
+		e.nodesConcurrency = make(map[string]chan struct{})
+	}
+	e.nodesConcurrency[e.system.nodeID(n)] = make(chan struct{}, max)
+	return nil
+}
+
+// ConfigureDeadLetter add a dead letter to receive the computations ended in Abort.
+// A failure to send a result to the dead letter don't change the result.
+func (e *Engine) ConfigureDeadLetter(deadLetter DeadLetter) {
+	e.deadLetter = deadLetter
+}
+
+// ConfigureFlagProvider add the provider of the feature flags configured on nodes,
+// and how to handle a disabled node.
+// Without provider, a node with a feature flag is always disabled.
+func (e *Engine) ConfigureFlagProvider(provider FlagProvider, mode DisabledNodeMode) error {
+	if mode != DisabledNodePassThrough && mode != DisabledNodeSkip {
+		return fmt.Errorf("can't handle disabled nodes with unknown mode: %v", mode)
+	}
+	e.flagProvider = provider
+	e.disabledNodeMode = mode
+	return nil
+}
+
+// ConfigureStrictMode make the computations abort a node reading a context key
+// who is not in the input data and never written before.
+func (e *Engine) ConfigureStrictMode(strict bool) {
+	e.strict = strict
+}
+
+// ConfigureContextSnapshots make the computations keep a snapshot of the context data
+// each time a node start, available through ComputationResult.ContextAt.
+func (e *Engine) ConfigureContextSnapshots(enabled bool) {
+	e.snapshots = enabled
+}
+
+// ConfigureContextStore add a store to the context of the computations,
+// a node failing to fetch a value from the store is aborted.
+func (e *Engine) ConfigureContextStore(store ContextStore) {
+	e.contextStore = store
+}
+
+// ConfigureCipher add a cipher to the context of the computations to handle their secrets.
+func (e *Engine) ConfigureCipher(cipher Cipher) {
+	e.cipher = cipher
+}
+
+// ConfigureWorkerPool run the nodes of all computations on a fixed number of workers,
+// the nodes of the computations with the highest priority first.
+// The workers are stopped on engine shutdown.
+func (e *Engine) ConfigureWorkerPool(workers int) error {
+	if workers <= 0 {
+		return fmt.Errorf("can't create worker pool under 1 worker: %v", workers)
+	}
+	if e.pool != nil {
+		return errors.New("worker pool already configured")
+	}
+	e.pool = newWorkerPool(workers)
+	return nil
+}
+
+// ConfigureQueueBound limit the number of submitted computations not ended,
+// above it TrySubmit reject the submissions with ErrQueueFull.
+func (e *Engine) ConfigureQueueBound(bound int) error {
+	if bound <= 0 {
+		return fmt.Errorf("can't bound queue under 1 computation: %v", bound)
+	}
+	e.queueBound = bound
+	return nil
+}
+
+// AddEventSink add a sink to receive the events of the computations.
+func (e *Engine) AddEventSink(sink EventSink) {
+	e.eventSinks = append(e.eventSinks, sink)
+}
+
 // Compute run computation against node system with input data.
 func (e *Engine) Compute(data map[string]interface{}) ComputationResult {
+	return e.compute(context.Background(), data, SubmitOptions{}, nil)
+}
+
+// Submit run computation against node system with input data in background,
+// and give a handle to follow it.
+// The queue bound is ignored.
+func (e *Engine) Submit(data map[string]interface{}, options SubmitOptions) *Handle {
+	e.mu.Lock()
+	e.pendingComputations++
+	e.mu.Unlock()
+
+	return e.submit(context.Background(), data, options)
+}
+
+// TrySubmit run computation against node system with input data in background,
+// and give a handle to follow it, or return ErrQueueFull when the queue bound is reached
+// (or ErrTenantQuotaExceeded when the quota of its tenant is reached).
+// The computation is interrupted when the context is done.
+func (e *Engine) TrySubmit(ctx context.Context, data map[string]interface{}, options SubmitOptions) (*Handle, error) {
+	release, err := e.admitTenant(options.Tenant)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	if e.queueBound > 0 && e.pendingComputations >= e.queueBound {
+		e.rejectedSubmissions++
+		e.mu.Unlock()
+		release()
+		return nil, ErrQueueFull
+	}
+	e.pendingComputations++
+	e.mu.Unlock()
+
+	options.release = release
+	return e.submit(ctx, data, options), nil
+}
+
+// QueueMetrics give the state of the pending computations queue.
+func (e *Engine) QueueMetrics() QueueMetrics {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return QueueMetrics{
+		Depth:    e.pendingComputations,
+		Bound:    e.queueBound,
+		Rejected: e.rejectedSubmissions,
+	}
+}
+
+// submit run a computation already counted as pending in background.
+func (e *Engine) submit(ctx context.Context, data map[string]interface{}, options SubmitOptions) *Handle {
+	ctx, cancel := context.WithCancel(ctx)
+	handle := newHandle(cancel)
+	go func() {
+		result := e.compute(ctx, data, options, handle)
+		e.mu.Lock()
+		e.pendingComputations--
+		e.mu.Unlock()
+		handle.complete(result)
+	}()
+	return handle
+}
+
+func (e *Engine) compute(ctx context.Context, data map[string]interface{}, options SubmitOptions, handle *Handle) ComputationResult {
+	if options.release == nil {
+		release, err := e.admitTenant(options.Tenant)
+		if err != nil {
+			return ComputationResult{
+				Data:   data,
+				Error:  err,
+				Tenant: options.Tenant,
+			}
+		}
+		options.release = release
+	}
+	defer options.release()
+
 	if e.system == nil {
 		return ComputationResult{
 			Data:  data,
@@ -39,19 +277,513 @@ func (e *Engine) Compute(data map[string]interface{}) ComputationResult {
 		}
 	}
 
-	cp, _ := NewComputation(e.system, NewContext(data))
+	if len(e.system.inputs) > 0 {
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		err := e.system.ValidateInput(data)
+		if err != nil {
+			return ComputationResult{
+				Data:  data,
+				Error: fmt.Errorf("can't compute with invalid input: %w", err),
+			}
+		}
+	}
 
-	err := cp.Compute()
-	return ComputationResult{
-		Data:   cp.Context.Data,
-		Error:  err,
-		Report: cp.Report,
+	cp, err := NewComputation(e.system, NewContext(data))
+	if err != nil {
+		return ComputationResult{
+			Data:  data,
+			Error: err,
+		}
+	}
+
+	if options.computationID != "" {
+		cp.ID = options.computationID
+		cp.Context.computationID = options.computationID
+	}
+	if options.Seed != nil {
+		cp.Context.setSeed(*options.Seed)
+	}
+	cp.tenant = options.Tenant
+	cp.Context.goContext = ctx
+	e.prepareComputation(cp, options)
+	err = cp.configureOverrides(options.Overrides)
+	if err != nil {
+		return newComputationResult(cp, err)
+	}
+
+	cancellation := func() CancellationReason {
+		if handle != nil && handle.isCanceled() {
+			return CanceledByUser
+		}
+		return contextCancellationReason(ctx)
+	}
+	if ctx.Err() != nil {
+		cp.recordCancellation(cancellation())
+		return newComputationResult(cp, ErrComputationInterrupted)
+	}
+	err = e.startComputation(cp)
+	if err != nil {
+		return ComputationResult{
+			Data:  data,
+			Error: err,
+		}
+	}
+	defer e.endComputation(cp)
+	if handle != nil {
+		cp.stateCallback = handle.recordState
+		handle.start(cp)
+	}
+
+	if ctx.Done() != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				// the context can be canceled once the computation is ended
+				select {
+				case <-stop:
+				default:
+					cp.cancel(cancellation())
+				}
+			case <-stop:
+			}
+		}()
+	}
+
+	start := time.Now()
+	switch {
+	case options.fromNode != nil:
+		err = cp.ComputeFromNode(options.fromNode)
+	case options.Trigger != "":
+		err = cp.ComputeFrom(options.Trigger)
+	default:
+		err = cp.Compute()
+	}
+	return e.endResult(cp, err, start)
+}
+
+// prepareComputation configure a computation with the engine policies.
+func (e *Engine) prepareComputation(cp *Computation, options SubmitOptions) {
+	cp.interceptors = e.interceptors(cp, options)
+	if e.strict {
+		cp.Context.enableStrictMode()
+	}
+	if e.contextStore != nil {
+		cp.Context.ConfigureStore(e.contextStore)
+	}
+	if e.cipher != nil {
+		cp.Context.ConfigureCipher(e.cipher)
+	}
+	if e.snapshots {
+		cp.ConfigureContextSnapshots()
+	}
+	if e.progressCallback != nil {
+		id := cp.ID
+		cp.ConfigureProgressCallback(func(progress ComputationProgress) {
+			e.progressCallback(id, progress)
+		})
+	}
+}
+
+// Deliver give the payload of an external event to the node paused on the token,
+// and resume its computation.
+// The paused node need to be an EventReceiverNode to handle the payload.
+func (e *Engine) Deliver(token string, payload interface{}) ComputationResult {
+	e.mu.Lock()
+	paused, found := e.pausedComputations[token]
+	if found {
+		delete(e.pausedComputations, token)
+	}
+	e.mu.Unlock()
+
+	pausedInMemory := found
+	if !found && e.checkpointStore != nil {
+		var err error
+		paused, found, err = e.findPausedCheckpoint(token)
+		if err != nil {
+			return ComputationResult{Error: err}
+		}
+	}
+	if !found {
+		return ComputationResult{
+			Error: fmt.Errorf("can't find paused computation for token '%v'", token),
+		}
+	}
+
+	cp := paused.computation
+	unlock, err := e.lockComputation(cp)
+	if err != nil {
+		if pausedInMemory {
+			e.pauseComputation(cp)
+		}
+		return newComputationResult(cp, err)
+	}
+	defer unlock()
+	if e.checkpointStore != nil {
+		if err := e.claimCheckpoint(cp); err != nil {
+			return newComputationResult(cp, err)
+		}
+	}
+	receiver, ok := paused.node.(EventReceiverNode)
+	if !ok {
+		return newComputationResult(cp, fmt.Errorf("can't deliver event to node: %+v", paused.node))
+	}
+
+	err = e.startComputation(cp)
+	if err != nil {
+		e.pauseComputation(cp)
+		return newComputationResult(cp, err)
+	}
+	defer e.endComputation(cp)
+
+	start := time.Now()
+	err = cp.Resume(paused.node, receiver.Receive(cp.Context, payload))
+	return e.endResult(cp, err, start)
+}
+
+// Shutdown stop accepting new computations and wait for the running ones to end,
+// then close the initialized nodes (see ClosableNode) and stop the worker pool.
+// When the context is done before, the running computations are interrupted
+// (once their running nodes end) and returned with ErrComputationInterrupted as error,
+// their context and report can be kept to continue them later.
+func (e *Engine) Shutdown(ctx context.Context) ([]ComputationResult, error) {
+	e.mu.Lock()
+	e.shutdown = true
+	e.mu.Unlock()
+	if e.pool != nil && !e.sharedPool {
+		defer e.pool.close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		e.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil, e.closeInitializedNodes()
+	case <-ctx.Done():
+	}
+
+	e.mu.Lock()
+	interruptedComputations := make([]*Computation, 0, len(e.runningComputations))
+	for _, cp := range e.runningComputations {
+		cp.cancel(CanceledByShutdown)
+		interruptedComputations = append(interruptedComputations, cp)
+	}
+	e.mu.Unlock()
+	<-done
+	e.closeInitializedNodes()
+
+	results := make([]ComputationResult, 0, len(interruptedComputations))
+	for _, cp := range interruptedComputations {
+		if !cp.Status && !cp.IsPaused() {
+			results = append(results, newComputationResult(cp, ErrComputationInterrupted))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	return results, ctx.Err()
+}
+
+func (e *Engine) interceptors(cp *Computation, options SubmitOptions) []nodeInterceptor {
+	interceptors := make([]nodeInterceptor, 0)
+	if e.eventStore != nil {
+		interceptors = append(interceptors, e.recordNodeEvents(cp))
+	}
+	if len(e.eventSinks) > 0 {
+		id, tenant := cp.ID, cp.tenant
+		interceptors = append(interceptors, func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+			start := time.Now()
+			e.emit(Event{Type: NodeStartedEvent, Time: start, ComputationID: id, Tenant: tenant, Node: node})
+			state := compute(c)
+			end := time.Now()
+			e.emit(Event{Type: NodeEndedEvent, Time: end, ComputationID: id, Tenant: tenant, Node: node, State: state, Duration: end.Sub(start)})
+			return state
+		})
+	}
+	if e.guarantee != CheckpointOnEnd {
+		interceptors = append(interceptors, e.checkpointNode(cp))
+	}
+	if len(e.system.nodesFlags) > 0 {
+		interceptors = append(interceptors, e.disableNodeByFlag)
+	}
+	if e.sloTracker != nil {
+		interceptors = append(interceptors, e.breakOnExhaustedBudget)
+	}
+	if e.stepper != nil {
+		interceptors = append(interceptors, e.stepNode)
+	}
+	if len(e.nodesConcurrency) > 0 {
+		interceptors = append(interceptors, e.limitNodeConcurrency)
+	}
+	if len(e.tagsPolicies) > 0 {
+		interceptors = append(interceptors, e.applyTagPolicies)
+	}
+	if slots := e.tenantShare(cp.tenant); slots != nil {
+		interceptors = append(interceptors, limitTenantShare(slots))
+	}
+	if e.pool != nil {
+		priority := options.Priority
+		interceptors = append(interceptors, func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+			var state ComputeState
+			e.pool.executeFor(e.poolWorkflow, priority, func() {
+				state = compute(c)
+			})
+			return state
+		})
+	}
+	if e.eventStore != nil {
+		interceptors = append(interceptors, e.recordNodeStarted(cp))
+	}
+	budget := e.budget
+	if options.Budget != nil {
+		budget = *options.Budget
+	}
+	if !budget.isZero() {
+		interceptors = append(interceptors, enforceBudget(cp, budget))
+	}
+	if e.strict {
+		interceptors = append(interceptors, abortOnUnknownReads)
+	}
+	if e.contextStore != nil {
+		interceptors = append(interceptors, abortOnStoreErrors)
+	}
+	if e.workQueue != nil {
+		interceptors = append(interceptors, e.shareOnWorkQueue(cp))
+	}
+	if len(e.tagsPolicies) > 0 {
+		interceptors = append(interceptors, e.hedgeNode)
+	}
+	if e.atomicWrites {
+		interceptors = append(interceptors, stageNodeWrites)
+	}
+	interceptors = append(interceptors, e.recoverNodePanic)
+	return interceptors
+}
+
+func abortOnStoreErrors(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	c.takeStoreErrors()
+	state := compute(c)
+	storeErrors := c.takeStoreErrors()
+	if len(storeErrors) > 0 {
+		return NewAbortComputeState(storeErrors[0])
+	}
+	return state
+}
+
+func abortOnUnknownReads(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	c.takeUnknownReads()
+	state := compute(c)
+	unknownReads := c.takeUnknownReads()
+	if len(unknownReads) > 0 {
+		return NewAbortComputeState(fmt.Errorf("can't read unknown context keys in strict mode: %v", strings.Join(unknownReads, ", ")))
+	}
+	return state
+}
+
+func (e *Engine) disableNodeByFlag(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	flag, found := e.system.FlagOfNode(node)
+	if !found || (e.flagProvider != nil && e.flagProvider.IsEnabled(flag, c)) {
+		return compute(c)
+	}
+	if e.disabledNodeMode == DisabledNodePassThrough && !node.DecideCapability() {
+		return NewContinueComputeState()
+	}
+	return NewSkipComputeState()
+}
+
+func (e *Engine) limitNodeConcurrency(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	slots, found := e.nodesConcurrency[e.system.nodeID(node)]
+	if !found {
+		return compute(c)
+	}
+	slots <- struct{}{}
+	defer func() {
+		<-slots
+	}()
+	return compute(c)
+}
+
+func (e *Engine) emit(event Event) {
+	for _, sink := range e.eventSinks {
+		sink.Handle(event)
+	}
+}
+
+func (e *Engine) startComputation(cp *Computation) error {
+	e.mu.Lock()
+	if e.shutdown {
+		e.mu.Unlock()
+		return errors.New("can't compute, engine is shut down")
+	}
+	if e.runningComputations == nil {
+		e.runningComputations = make(map[string]*Computation)
+	}
+	e.runningComputations[cp.ID] = cp
+	e.inFlight.Add(1)
+	e.mu.Unlock()
+
+	err := e.Init(context.Background())
+	if err != nil {
+		e.endComputation(cp)
+		return err
+	}
+
+	e.emit(Event{Type: ComputationStartedEvent, Time: time.Now(), ComputationID: cp.ID, Tenant: cp.tenant})
+	if e.eventStore != nil {
+		err = e.recordComputationStarted(cp)
+		if err != nil {
+			e.endComputation(cp)
+			return fmt.Errorf("can't append events of computation: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) endComputation(cp *Computation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.runningComputations, cp.ID)
+	e.inFlight.Done()
+}
+
+func (e *Engine) endResult(cp *Computation, err error, start time.Time) ComputationResult {
+	e.pauseComputation(cp)
+	if err == nil && !cp.IsPaused() {
+		if outputErr := cp.System.ValidateOutput(cp.Context.Data); outputErr != nil {
+			err = fmt.Errorf("can't end computation with invalid output: %w", outputErr)
+		}
+	}
+	result := newComputationResult(cp, err)
+	if len(result.PausedTokens()) == 0 {
+		cp.Context.cancellation = result.Cancellation
+		cp.Context.runCleanups()
+	}
+	result.Success = e.isSuccess(result)
+	end := time.Now()
+	e.emit(Event{Type: ComputationEndedEvent, Time: end, ComputationID: cp.ID, Tenant: cp.tenant, Duration: end.Sub(start), Error: err})
+	if e.eventStore != nil {
+		e.recordComputationEnded(cp, err)
+	}
+	if e.deadLetter != nil && result.IsAborted() {
+		e.deadLetter.Send(result)
+	}
+	if e.reportStore != nil {
+		e.saveReport(result, start, end)
+	}
+	if e.checkpointStore != nil {
+		e.saveCheckpoint(cp, result, len(result.PausedTokens()) == 0 && !errors.Is(result.Error, ErrComputationInterrupted))
+	}
+	return result
+}
+
+func (e *Engine) pauseComputation(cp *Computation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for node, state := range cp.Report {
+		if state.Value == PauseState {
+			if e.pausedComputations == nil {
+				e.pausedComputations = make(map[string]pausedComputation)
+			}
+			e.pausedComputations[state.Token] = pausedComputation{
+				computation: cp,
+				node:        node,
+			}
+		}
 	}
 }
 
 // ComputationResult store the result of a computation.
 type ComputationResult struct {
-	Error  error
-	Data   map[string]interface{}
-	Report map[Node]ComputeState
+	ID          string
+	Fingerprint string
+	Error       error
+	Data        map[string]interface{}
+	Report      map[Node]ComputeState
+	// Snapshots hold the context data when each node started, when enabled on the engine
+	Snapshots map[Node]ContextSnapshot
+	// Success tell if the computation is successful based on the success criteria of the engine
+	Success bool
+	// Durations hold the time taken by each computed node
+	Durations map[Node]time.Duration
+	// Seed is the seed of the computation random generator (see Context.Rand)
+	Seed int64
+	// Outputs hold the context data of the declared outputs of the node system (see NodeSystem.DeclareOutput)
+	Outputs map[string]interface{}
+	// Tenant is the tenant of the computation (see SubmitOptions.Tenant)
+	Tenant string
+	// Cancellation is the reason of the interruption of the computation, NotCanceled without interruption
+	Cancellation CancellationReason
+}
+
+// IsAborted tell if a node of the computation end in Abort.
+func (r ComputationResult) IsAborted() bool {
+	for _, state := range r.Report {
+		if state.Value == AbortState {
+			return true
+		}
+	}
+	return false
+}
+
+// PausedTokens give the tokens of the nodes waiting for an external event.
+func (r ComputationResult) PausedTokens() []string {
+	tokens := make([]string, 0)
+	for _, state := range r.Report {
+		if state.Value == PauseState {
+			tokens = append(tokens, state.Token)
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// resultDurations give the durations of the computed nodes by node, keyed like the report.
+func (cp *Computation) resultDurations() map[Node]time.Duration {
+	if cp.durations == nil {
+		return nil
+	}
+	durations := make(map[Node]time.Duration, len(cp.durations))
+	for _, node := range cp.System.nodes {
+		if duration, found := cp.durations[cp.System.nodeID(node)]; found {
+			durations[cp.System.nodeMapKey(node)] = duration
+		}
+	}
+	return durations
+}
+
+// resultSnapshots give the context snapshots of the computed nodes by node, keyed like the report.
+func (cp *Computation) resultSnapshots() map[Node]ContextSnapshot {
+	if cp.snapshots == nil {
+		return nil
+	}
+	snapshots := make(map[Node]ContextSnapshot, len(cp.snapshots))
+	for _, node := range cp.System.nodes {
+		if snapshot, found := cp.snapshots[cp.System.nodeID(node)]; found {
+			snapshots[cp.System.nodeMapKey(node)] = snapshot
+		}
+	}
+	return snapshots
+}
+
+func newComputationResult(cp *Computation, err error) ComputationResult {
+	return ComputationResult{
+		ID:           cp.ID,
+		Fingerprint:  cp.System.Fingerprint(),
+		Data:         cp.Context.Data,
+		Error:        err,
+		Report:       cp.Report,
+		Snapshots:    cp.resultSnapshots(),
+		Durations:    cp.resultDurations(),
+		Seed:         cp.Context.seed,
+		Outputs:      cp.System.outputsOf(cp.Context.Data),
+		Tenant:       cp.tenant,
+		Cancellation: cp.cancellationReason(),
+	}
 }
@@ -1,16 +1,21 @@
 package hoff
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func Test_Engine_ConfigureNodeSystem(t *testing.T) {
 	activatedNodeSystem := NewNodeSystem()
-	activatedNodeSystem.Activate()
+	activatedNodeSystem.ActivateInPlace()
 
 	configuredEngine := &Engine{
 		mode:   SequentialComputation,
@@ -79,7 +84,7 @@ func Test_Engine_Compute(t *testing.T) {
 	ns.AddNode(throwError)
 	ns.AddLinkOnBranch(keyIsPresent, stringAction, true)
 	ns.AddLinkOnBranch(keyIsPresent, throwError, false)
-	ns.Activate()
+	ns.ActivateInPlace()
 
 	eng := NewEngine(SequentialComputation)
 	eng.ConfigureNodeSystem(ns)
@@ -117,6 +122,7 @@ func Test_Engine_Compute(t *testing.T) {
 					stringAction: NewContinueComputeState(),
 					throwError:   NewSkipComputeState(),
 				},
+				Success: true,
 			},
 		},
 	}
@@ -124,13 +130,404 @@ func Test_Engine_Compute(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			result := eng.Compute(testCase.givenData)
 
-			if !cmp.Equal(result, testCase.expectedResult, NodeComparator, errorComparator) {
+			if !cmp.Equal(result, testCase.expectedResult, NodeComparator, errorComparator, computationResultGeneratedFieldsIgnorer) {
 				t.Errorf("got: %+v, want: %+v", result, testCase.expectedResult)
 			}
 		})
 	}
 }
 
+func Test_Engine_ConfigureProgressCallback(t *testing.T) {
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(action)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	progresses := make(map[string]ComputationProgress)
+	eng.ConfigureProgressCallback(func(id string, progress ComputationProgress) {
+		progresses[id] = progress
+	})
+	result := eng.Compute(make(map[string]interface{}))
+
+	expectedProgresses := map[string]ComputationProgress{
+		result.ID: {Completed: 1, Total: 1},
+	}
+	if !cmp.Equal(progresses, expectedProgresses) {
+		t.Errorf("got: %+v, want: %+v", progresses, expectedProgresses)
+	}
+}
+
+func Test_Engine_ConfigureConcurrencyOnNode(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	sharedResource, _ := NewActionNode("sharedResource", func(*Context) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(sharedResource)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	err := eng.ConfigureConcurrencyOnNode(sharedResource, 0)
+	expectedError := errors.New("can't limit concurrency of node 'sharedResource' under 1: 0")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	eng.ConfigureConcurrencyOnNode(sharedResource, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			eng.Compute(make(map[string]interface{}))
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning > 2 {
+		t.Errorf("simultaneous computations - got: %+v, want: at most %+v", maxRunning, 2)
+	}
+}
+
+type staticFlagProvider map[string]bool
+
+func (p staticFlagProvider) IsEnabled(flag string, c *Context) bool {
+	return p[flag]
+}
+
+func Test_Engine_ConfigureFlagProvider(t *testing.T) {
+	writeAction, _ := NewActionNode("writeAction", func(c *Context) error {
+		c.Store("write_action", "done")
+		return nil
+	})
+	newAction, _ := NewActionNode("newAction", func(c *Context) error {
+		c.Store("new_action", "done")
+		return nil
+	})
+	readAction, _ := NewActionNode("readAction", func(c *Context) error {
+		c.Store("read_action", "done")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(writeAction)
+	ns.AddNode(newAction)
+	ns.AddNode(readAction)
+	ns.AddLink(writeAction, newAction)
+	ns.AddLink(newAction, readAction)
+	ns.ConfigureEnabledWhen(newAction, "new_step")
+	ns.ActivateInPlace()
+
+	testCases := []struct {
+		name           string
+		givenProvider  FlagProvider
+		givenMode      DisabledNodeMode
+		expectedReport map[Node]ComputeState
+		expectedError  error
+	}{
+		{
+			name:          "Compute enabled node",
+			givenProvider: staticFlagProvider{"new_step": true},
+			givenMode:     DisabledNodeSkip,
+			expectedReport: map[Node]ComputeState{
+				writeAction: NewContinueComputeState(),
+				newAction:   NewContinueComputeState(),
+				readAction:  NewContinueComputeState(),
+			},
+		},
+		{
+			name:          "Skip disabled node",
+			givenProvider: staticFlagProvider{},
+			givenMode:     DisabledNodeSkip,
+			expectedReport: map[Node]ComputeState{
+				writeAction: NewContinueComputeState(),
+				newAction:   NewSkipComputeState(),
+				readAction:  NewSkipComputeState(),
+			},
+		},
+		{
+			name:      "Pass through disabled node without provider",
+			givenMode: DisabledNodePassThrough,
+			expectedReport: map[Node]ComputeState{
+				writeAction: NewContinueComputeState(),
+				newAction:   NewContinueComputeState(),
+				readAction:  NewContinueComputeState(),
+			},
+		},
+		{
+			name:          "Can't handle disabled nodes with unknown mode",
+			givenMode:     "unknown",
+			expectedError: errors.New("can't handle disabled nodes with unknown mode: unknown"),
+			expectedReport: map[Node]ComputeState{
+				writeAction: NewContinueComputeState(),
+				newAction:   NewSkipComputeState(),
+				readAction:  NewSkipComputeState(),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			err := eng.ConfigureFlagProvider(testCase.givenProvider, testCase.givenMode)
+
+			result := eng.Compute(make(map[string]interface{}))
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(result.Report, testCase.expectedReport, NodeComparator, errorComparator) {
+				t.Errorf("report - got: %+v, want: %+v", result.Report, testCase.expectedReport)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureStrictMode(t *testing.T) {
+	readAction, _ := NewActionNode("readAction", func(c *Context) error {
+		c.Read("input")
+		c.Read("mesage")
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(readAction)
+	ns.ActivateInPlace()
+
+	testCases := []struct {
+		name           string
+		givenStrict    bool
+		expectedReport map[Node]ComputeState
+	}{
+		{
+			name:        "Ignore unknown reads",
+			givenStrict: false,
+			expectedReport: map[Node]ComputeState{
+				readAction: NewContinueComputeState(),
+			},
+		},
+		{
+			name:        "Abort node on unknown reads",
+			givenStrict: true,
+			expectedReport: map[Node]ComputeState{
+				readAction: NewAbortComputeState(errors.New("can't read unknown context keys in strict mode: mesage")),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigureStrictMode(testCase.givenStrict)
+
+			result := eng.Compute(map[string]interface{}{"input": "value"})
+
+			if !cmp.Equal(result.Report, testCase.expectedReport, NodeComparator, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", result.Report, testCase.expectedReport)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureWorkerPool(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenWorkers  []int
+		expectedError error
+	}{
+		{
+			name:         "Can configure a worker pool",
+			givenWorkers: []int{2},
+		},
+		{
+			name:          "Can't configure a worker pool without worker",
+			givenWorkers:  []int{0},
+			expectedError: errors.New("can't create worker pool under 1 worker: 0"),
+		},
+		{
+			name:          "Can't configure a worker pool twice",
+			givenWorkers:  []int{2, 2},
+			expectedError: errors.New("worker pool already configured"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			var err error
+			for _, workers := range testCase.givenWorkers {
+				err = eng.ConfigureWorkerPool(workers)
+			}
+			eng.Shutdown(context.Background())
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Engine_Submit(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var mu sync.Mutex
+	order := make([]interface{}, 0)
+	blockingAction, _ := NewActionNode("blockingAction", func(c *Context) error {
+		if c.HaveKey("blocking") {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		priority, _ := c.Read("priority")
+		order = append(order, priority)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(blockingAction)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureWorkerPool(1)
+
+	blockingResult := eng.Submit(map[string]interface{}{"blocking": true, "priority": "none"}, SubmitOptions{})
+	<-started
+	lowResult := eng.Submit(map[string]interface{}{"priority": "low"}, SubmitOptions{Priority: 1})
+	highResult := eng.Submit(map[string]interface{}{"priority": "high"}, SubmitOptions{Priority: 2})
+	for waitingTasks := 0; waitingTasks < 2; {
+		time.Sleep(time.Millisecond)
+		eng.pool.mu.Lock()
+		waitingTasks = eng.pool.pending
+		eng.pool.mu.Unlock()
+	}
+	close(release)
+
+	for _, handle := range []*Handle{blockingResult, highResult, lowResult} {
+		result, _ := handle.Wait(context.Background())
+		if result.Error != nil {
+			t.Errorf("error - got: %+v, want: %+v", result.Error, nil)
+		}
+	}
+	expectedOrder := []interface{}{"none", "high", "low"}
+	if !cmp.Equal(order, expectedOrder) {
+		t.Errorf("order - got: %+v, want: %+v", order, expectedOrder)
+	}
+	eng.Shutdown(context.Background())
+}
+
+func Test_Engine_ConfigureQueueBound(t *testing.T) {
+	eng := NewEngine(SequentialComputation)
+	err := eng.ConfigureQueueBound(0)
+
+	expectedError := errors.New("can't bound queue under 1 computation: 0")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Engine_TrySubmit(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	blockingAction, _ := NewActionNode("blockingAction", func(c *Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	followingAction, _ := NewActionNode("followingAction", func(c *Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(blockingAction)
+	ns.AddNode(followingAction)
+	ns.AddLink(blockingAction, followingAction)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureQueueBound(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handle, err := eng.TrySubmit(ctx, make(map[string]interface{}), SubmitOptions{})
+	if err != nil {
+		t.Fatalf("first submission - got: %+v, want: %+v", err, nil)
+	}
+	<-started
+
+	_, err = eng.TrySubmit(context.Background(), make(map[string]interface{}), SubmitOptions{})
+	if err != ErrQueueFull {
+		t.Errorf("second submission - got: %+v, want: %+v", err, ErrQueueFull)
+	}
+	metrics := eng.QueueMetrics()
+	expectedMetrics := QueueMetrics{Depth: 1, Bound: 1, Rejected: 1}
+	if !cmp.Equal(metrics, expectedMetrics) {
+		t.Errorf("metrics - got: %+v, want: %+v", metrics, expectedMetrics)
+	}
+
+	cancel()
+	for interrupted := false; !interrupted; {
+		time.Sleep(time.Millisecond)
+		eng.mu.Lock()
+		for _, cp := range eng.runningComputations {
+			interrupted = atomic.LoadInt32(&cp.interrupted) == 1
+		}
+		eng.mu.Unlock()
+	}
+	close(release)
+	result, _ := handle.Wait(context.Background())
+	if result.Error != ErrComputationInterrupted {
+		t.Errorf("result - got: %+v, want: %+v", result.Error, ErrComputationInterrupted)
+	}
+	metrics = eng.QueueMetrics()
+	expectedMetrics = QueueMetrics{Depth: 0, Bound: 1, Rejected: 1}
+	if !cmp.Equal(metrics, expectedMetrics) {
+		t.Errorf("metrics after end - got: %+v, want: %+v", metrics, expectedMetrics)
+	}
+}
+
+type recordingEventSink struct {
+	events []Event
+}
+
+func (s *recordingEventSink) Handle(event Event) {
+	s.events = append(s.events, event)
+}
+
+func Test_Engine_AddEventSink(t *testing.T) {
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(action)
+	ns.ActivateInPlace()
+
+	sink := &recordingEventSink{}
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.AddEventSink(sink)
+	result := eng.Compute(make(map[string]interface{}))
+
+	eventTypes := make([]EventType, 0)
+	for _, event := range sink.events {
+		eventTypes = append(eventTypes, event.Type)
+		if event.ComputationID != result.ID {
+			t.Errorf("computation id - got: %+v, want: %+v", event.ComputationID, result.ID)
+		}
+	}
+	expectedEventTypes := []EventType{ComputationStartedEvent, NodeStartedEvent, NodeEndedEvent, ComputationEndedEvent}
+	if !cmp.Equal(eventTypes, expectedEventTypes) {
+		t.Errorf("got: %+v, want: %+v", eventTypes, expectedEventTypes)
+	}
+}
+
 func Test_UnconfiguredEngine_Compute(t *testing.T) {
 	eng := NewEngine(SequentialComputation)
 	data := make(map[string]interface{})
@@ -146,8 +543,154 @@ func Test_UnconfiguredEngine_Compute(t *testing.T) {
 	}
 }
 
+func Test_Engine_Deliver(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	readApproval, _ := NewActionNode("readApproval", func(c *Context) error {
+		payload, _ := c.Read("approval_payload")
+		c.Store("approved", payload == "approved")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.AddNode(readApproval)
+	ns.AddLink(approval, readApproval)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	pausedResult := eng.Compute(make(map[string]interface{}))
+	tokens := pausedResult.PausedTokens()
+	if len(tokens) != 1 {
+		t.Fatalf("paused tokens - got: %+v, want: 1 token", tokens)
+	}
+
+	result := eng.Deliver(tokens[0], "approved")
+	expectedResult := ComputationResult{
+		Data: map[string]interface{}{
+			"approval_payload": "approved",
+			"approved":         true,
+		},
+		Report: map[Node]ComputeState{
+			approval:     NewContinueComputeState(),
+			readApproval: NewContinueComputeState(),
+		},
+		Success: true,
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator, computationResultGeneratedFieldsIgnorer) {
+		t.Errorf("got: %+v, want: %+v", result, expectedResult)
+	}
+
+	alreadyDeliveredResult := eng.Deliver(tokens[0], "approved")
+	expectedError := fmt.Errorf("can't find paused computation for token '%v'", tokens[0])
+	if !cmp.Equal(alreadyDeliveredResult.Error, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", alreadyDeliveredResult.Error, expectedError)
+	}
+}
+
+func Test_Engine_Shutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blockingAction, _ := NewActionNode("blockingAction", func(c *Context) error {
+		close(started)
+		<-release
+		c.Store("blocking_action", "done")
+		return nil
+	})
+	followingAction, _ := NewActionNode("followingAction", func(c *Context) error {
+		c.Store("following_action", "done")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(blockingAction)
+	ns.AddNode(followingAction)
+	ns.AddLink(blockingAction, followingAction)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	computed := make(chan ComputationResult)
+	go func() {
+		computed <- eng.Compute(make(map[string]interface{}))
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	interruptedResults, err := eng.Shutdown(ctx)
+	result := <-computed
+
+	expectedResult := ComputationResult{
+		ID:          result.ID,
+		Fingerprint: ns.Fingerprint(),
+		Error:       ErrComputationInterrupted,
+		Data: map[string]interface{}{
+			"blocking_action": "done",
+		},
+		Report: map[Node]ComputeState{
+			blockingAction: NewContinueComputeState(),
+		},
+		Cancellation: CanceledByShutdown,
+	}
+	if !cmp.Equal(err, context.Canceled, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, context.Canceled)
+	}
+	durationsIgnorer := cmpopts.IgnoreFields(ComputationResult{}, "Durations", "Seed")
+	if !cmp.Equal(interruptedResults, []ComputationResult{expectedResult}, NodeComparator, errorComparator, durationsIgnorer) {
+		t.Errorf("interrupted results - got: %+v, want: %+v", interruptedResults, []ComputationResult{expectedResult})
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator, durationsIgnorer) {
+		t.Errorf("result - got: %+v, want: %+v", result, expectedResult)
+	}
+
+	refusedResult := eng.Compute(make(map[string]interface{}))
+	expectedError := errors.New("can't compute, engine is shut down")
+	if !cmp.Equal(refusedResult.Error, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", refusedResult.Error, expectedError)
+	}
+}
+
 var (
-	engineComparator = cmp.Comparer(func(x, y Engine) bool {
+	computationResultGeneratedFieldsIgnorer = cmpopts.IgnoreFields(ComputationResult{}, "ID", "Fingerprint", "Durations", "Seed")
+	engineComparator                        = cmp.Comparer(func(x, y *Engine) bool {
 		return x.mode == y.mode && ((x.system == nil && y.system == nil) || (x.system != nil && y.system != nil && cmp.Equal(x.system, y.system)))
 	})
 )
+
+func Test_Engine_Deliver_afterContextCancel(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	readApproval, _ := NewActionNode("readApproval", func(c *Context) error {
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.AddNode(readApproval)
+	ns.AddLink(approval, readApproval)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	for i := 0; i < 20; i++ {
+		// the context of a submitted computation is canceled once the computation is ended
+		pausedResult, _ := eng.Submit(make(map[string]interface{}), SubmitOptions{}).Wait(context.Background())
+		time.Sleep(time.Millisecond)
+		tokens := pausedResult.PausedTokens()
+		if len(tokens) != 1 {
+			t.Fatalf("paused tokens - got: %+v, want: 1 token", tokens)
+		}
+
+		result := eng.Deliver(tokens[0], "approved")
+		if result.Error != nil || !result.Success {
+			t.Fatalf("got: %+v, want: a success", result)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package hoff
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	registeredErrorsMu    sync.RWMutex
+	registeredErrors      = make(map[string]error)
+	registeredErrorsNames []string
+)
+
+// RegisterError register by name a sentinel error of a node package,
+// to find it with errors.Is (or errors.As) in the errors restored from a report,
+// a checkpoint, or a remote node, where only the error message is kept otherwise.
+// Registering again a name replace its error.
+func RegisterError(name string, err error) {
+	registeredErrorsMu.Lock()
+	defer registeredErrorsMu.Unlock()
+	if _, found := registeredErrors[name]; !found {
+		registeredErrorsNames = append(registeredErrorsNames, name)
+	}
+	registeredErrors[name] = err
+}
+
+// RestoredError is an error restored from its message, who wrap the registered errors of the original error.
+type RestoredError struct {
+	Message string
+	Causes  []error
+}
+
+func (e *RestoredError) Error() string {
+	return e.Message
+}
+
+// Is tell if a registered error of the original error match the target.
+func (e *RestoredError) Is(target error) bool {
+	for _, cause := range e.Causes {
+		if errors.Is(cause, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As find the first registered error of the original error matching the target.
+func (e *RestoredError) As(target interface{}) bool {
+	for _, cause := range e.Causes {
+		if errors.As(cause, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorCauses give the names of the registered errors wrapped by an error.
+func errorCauses(err error) []string {
+	if err == nil {
+		return nil
+	}
+	registeredErrorsMu.RLock()
+	defer registeredErrorsMu.RUnlock()
+	var causes []string
+	for _, name := range registeredErrorsNames {
+		if errors.Is(err, registeredErrors[name]) {
+			causes = append(causes, name)
+		}
+	}
+	return causes
+}
+
+// restoreError create an error from its message, and the names of its registered errors.
+// The names not registered are ignored.
+func restoreError(message string, causes []string) error {
+	registeredErrorsMu.RLock()
+	defer registeredErrorsMu.RUnlock()
+	var errs []error
+	for _, name := range causes {
+		if err, found := registeredErrors[name]; found {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return errors.New(message)
+	}
+	return &RestoredError{Message: message, Causes: errs}
+}
@@ -0,0 +1,80 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type quotaError struct {
+	Limit int
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("quota of %v exceeded", e.Limit)
+}
+
+var (
+	errTestUnavailable = errors.New("service unavailable")
+	errTestQuota       = &quotaError{Limit: 10}
+)
+
+func Test_restoreError(t *testing.T) {
+	RegisterError("test.unavailable", errTestUnavailable)
+	RegisterError("test.quota", errTestQuota)
+
+	err := fmt.Errorf("can't call service: %w", errTestUnavailable)
+	report, _ := NewComputationReport(ComputationResult{
+		Error:  err,
+		Report: map[Node]ComputeState{someActionNode: NewAbortComputeState(err)},
+	})
+	buffer, _ := report.MarshalProto()
+	var unmarshaled ComputationReport
+	unmarshaled.UnmarshalProto(buffer)
+
+	restored := restoreError(unmarshaled.Error, unmarshaled.ErrorCauses)
+	if restored.Error() != err.Error() || !errors.Is(restored, errTestUnavailable) || errors.Is(restored, errTestQuota) {
+		t.Errorf("computation error - got: %+v (causes %+v), want: %+v wrapping %+v", restored, unmarshaled.ErrorCauses, err, errTestUnavailable)
+	}
+	state := unmarshaled.States[0]
+	restored = restoreError(state.Error, state.Causes)
+	if !errors.Is(restored, errTestUnavailable) {
+		t.Errorf("node error - got: %+v (causes %+v), want: wrapping %+v", restored, state.Causes, errTestUnavailable)
+	}
+
+	restored = restoreError("can't compute: quota of 10 exceeded", errorCauses(fmt.Errorf("can't compute: %w", errTestQuota)))
+	var quota *quotaError
+	if !errors.As(restored, &quota) || quota.Limit != 10 {
+		t.Errorf("typed error - got: %+v, want: %+v", quota, errTestQuota)
+	}
+
+	restored = restoreError("unknown", []string{"test.unknown"})
+	if _, isRestored := restored.(*RestoredError); isRestored || restored.Error() != "unknown" {
+		t.Errorf("unknown cause - got: %#v, want: %+v", restored, errors.New("unknown"))
+	}
+}
+
+func Test_RemoteNode_Compute_wrappedError(t *testing.T) {
+	RegisterError("test.unavailable", errTestUnavailable)
+	failingAction, _ := NewActionNode("failingAction", func(*Context) error {
+		return fmt.Errorf("can't call service: %w", errTestUnavailable)
+	})
+	server, _ := NewComputeServer(failingAction)
+	remote, _ := NewRemoteNode("failingAction", server, false)
+
+	state := remote.Compute(NewContextWithoutData())
+	if state.Value != AbortState || !errors.Is(state.Error, errTestUnavailable) {
+		t.Errorf("got: %+v, want: abort wrapping %+v", state, errTestUnavailable)
+	}
+}
+
+func Test_Redactor_Redact_wrappedError(t *testing.T) {
+	redactor := NewRedactor()
+	redactor.AddPattern("secret-[0-9]+")
+	err := fmt.Errorf("can't call service with secret-42: %w", errTestUnavailable)
+
+	redacted := redactor.Redact(ComputationResult{Error: err})
+	if redacted.Error.Error() != "can't call service with [REDACTED]: service unavailable" || !errors.Is(redacted.Error, errTestUnavailable) {
+		t.Errorf("got: %+v, want: redacted error wrapping %+v", redacted.Error, errTestUnavailable)
+	}
+}
@@ -0,0 +1,42 @@
+package hoff
+
+import (
+	"time"
+)
+
+// EventType define the type of an Event emitted by the engine.
+type EventType string
+
+const (
+	// ComputationStartedEvent is emitted when a computation start (or resume).
+	ComputationStartedEvent EventType = "computation_started"
+	// ComputationEndedEvent is emitted when a computation end (or pause).
+	ComputationEndedEvent = "computation_ended"
+	// NodeStartedEvent is emitted when a node start to compute.
+	NodeStartedEvent = "node_started"
+	// NodeEndedEvent is emitted when a node end to compute.
+	NodeEndedEvent = "node_ended"
+)
+
+// Event hold what happen during a computation run by an engine.
+type Event struct {
+	Type          EventType
+	Time          time.Time
+	ComputationID string
+	// Tenant is the tenant of the computation, e.g. to label metrics.
+	Tenant string
+	// Node is set on node events.
+	Node Node
+	// State is set on NodeEndedEvent.
+	State ComputeState
+	// Duration is set on NodeEndedEvent and ComputationEndedEvent.
+	Duration time.Duration
+	// Error is set on ComputationEndedEvent when the computation end on error.
+	Error error
+}
+
+// EventSink receive the events emitted by an engine.
+// The events are sent synchronously, a sink need to return quickly.
+type EventSink interface {
+	Handle(event Event)
+}
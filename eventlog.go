@@ -0,0 +1,97 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLinesEventSink is an EventSink who write each event as a JSON line,
+// giving an audit trail of the computations ingestible by log pipelines.
+type JSONLinesEventSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	err      error
+	redactor *Redactor
+}
+
+// NewJSONLinesEventSink create a JSONLinesEventSink who write into a writer.
+func NewJSONLinesEventSink(w io.Writer) (*JSONLinesEventSink, error) {
+	if w == nil {
+		return nil, errors.New("can't create json lines event sink without writer")
+	}
+	return &JSONLinesEventSink{w: w}, nil
+}
+
+// ConfigureRedactor mask the sensitive values of the events before writing them.
+func (s *JSONLinesEventSink) ConfigureRedactor(redactor *Redactor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redactor = redactor
+}
+
+// Handle write the event as a JSON line.
+// Once a write fail, the following events are dropped (see Err).
+func (s *JSONLinesEventSink) Handle(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+
+	if s.redactor != nil {
+		event = s.redactor.RedactEvent(event)
+	}
+	line, err := json.Marshal(newEventRecord(event))
+	if err != nil {
+		s.err = err
+		return
+	}
+	_, s.err = s.w.Write(append(line, '\n'))
+}
+
+// Err give the error who stop the writing of the events.
+func (s *JSONLinesEventSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// eventRecord is the JSON representation of an event.
+type eventRecord struct {
+	Type          EventType `json:"type"`
+	Time          time.Time `json:"time"`
+	ComputationID string    `json:"computation_id"`
+	Node          string    `json:"node,omitempty"`
+	State         StateType `json:"state,omitempty"`
+	Branch        *bool     `json:"branch,omitempty"`
+	Token         string    `json:"token,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Code          AbortCode `json:"code,omitempty"`
+	Duration      int64     `json:"duration_ns,omitempty"`
+}
+
+func newEventRecord(event Event) eventRecord {
+	record := eventRecord{
+		Type:          event.Type,
+		Time:          event.Time,
+		ComputationID: event.ComputationID,
+		State:         event.State.Value,
+		Branch:        event.State.Branch,
+		Token:         event.State.Token,
+		Code:          event.State.Code,
+		Duration:      int64(event.Duration),
+	}
+	if event.Node != nil {
+		record.Node = fmt.Sprint(event.Node)
+	}
+	if event.State.Error != nil {
+		record.Error = event.State.Error.Error()
+	} else if event.Error != nil {
+		record.Error = event.Error.Error()
+	}
+	return record
+}
@@ -0,0 +1,62 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewJSONLinesEventSink(t *testing.T) {
+	_, err := NewJSONLinesEventSink(nil)
+	expectedError := errors.New("can't create json lines event sink without writer")
+
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_JSONLinesEventSink_Handle(t *testing.T) {
+	errorAction, _ := NewActionNode("errorAction", func(*Context) error {
+		return errors.New("action error")
+	})
+	eventTime := time.Date(2019, 1, 2, 10, 7, 30, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		givenEvent   Event
+		expectedLine string
+	}{
+		{
+			name:         "Write a computation started event",
+			givenEvent:   Event{Type: ComputationStartedEvent, Time: eventTime, ComputationID: "id"},
+			expectedLine: `{"type":"computation_started","time":"2019-01-02T10:07:30Z","computation_id":"id"}` + "\n",
+		},
+		{
+			name:         "Write a node ended event",
+			givenEvent:   Event{Type: NodeEndedEvent, Time: eventTime, ComputationID: "id", Node: errorAction, State: NewAbortComputeState(errors.New("action error")), Duration: time.Millisecond},
+			expectedLine: `{"type":"node_ended","time":"2019-01-02T10:07:30Z","computation_id":"id","node":"errorAction","state":"Abort","error":"action error","duration_ns":1000000}` + "\n",
+		},
+		{
+			name:         "Write a computation ended event",
+			givenEvent:   Event{Type: ComputationEndedEvent, Time: eventTime, ComputationID: "id", Error: errors.New("action error"), Duration: time.Millisecond},
+			expectedLine: `{"type":"computation_ended","time":"2019-01-02T10:07:30Z","computation_id":"id","error":"action error","duration_ns":1000000}` + "\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			sink, _ := NewJSONLinesEventSink(&buffer)
+			sink.Handle(testCase.givenEvent)
+
+			if buffer.String() != testCase.expectedLine {
+				t.Errorf("got: %v, want: %v", buffer.String(), testCase.expectedLine)
+			}
+			if sink.Err() != nil {
+				t.Errorf("error - got: %+v, want: <nil>", sink.Err())
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// NodeScheduledEvent is recorded in the event store when a node is about to compute,
+	// before waiting for its policies or a worker.
+	NodeScheduledEvent EventType = "node_scheduled"
+	// ContextMutatedEvent is recorded in the event store for each context key stored or deleted,
+	// by a node or as initial data of the computation.
+	ContextMutatedEvent EventType = "context_mutated"
+)
+
+// StoredEvent is a serializable event of the append-only log of a computation, recorded by an EventStore.
+// The node events are NodeScheduledEvent, NodeStartedEvent, and NodeEndedEvent, with the ContextMutatedEvent
+// of the node recorded just before its NodeEndedEvent.
+type StoredEvent struct {
+	// Sequence is the position of the event in the log of the computation, from 1, set by the event store
+	Sequence      int64
+	Type          EventType
+	Time          time.Time
+	ComputationID string
+	// Fingerprint, Seed, and Tenant are set on ComputationStartedEvent, to resume the computation from its events
+	Fingerprint string
+	Seed        int64
+	Tenant      string
+	// Interrupted is set on ComputationEndedEvent when the computation is interrupted before its end
+	Interrupted bool
+	// Node is the name of the node of the node events, and of the ContextMutatedEvent done by a node
+	Node string
+	// State, Branch, Error, Code, and Token are the compute state of a NodeEndedEvent
+	State  StateType
+	Branch *bool
+	Error  string
+	Code   AbortCode
+	Token  string
+	// Key and Value are set on ContextMutatedEvent, the value is JSON-encoded and nil when the key is deleted,
+	// with ValueType for a typed value (e.g. 'secret' for a Secret kept as its ciphertext, see Checkpoint)
+	Key       string
+	Value     []byte
+	ValueType string
+}
+
+// EventStore persist the append-only log of the events of the computations,
+// to audit them, resume them, or know their state at a given time (see ReplayComputation).
+type EventStore interface {
+	// Append add events at the end of the log of their computation, with the following sequences.
+	Append(events ...StoredEvent) error
+	// Events give the events of a computation with a sequence after a given one, in order.
+	Events(computationID string, after int64) ([]StoredEvent, error)
+}
+
+// ConfigureEventStore add a store to record the events of the computations as an append-only log.
+// A node is aborted when its events can't be appended.
+func (e *Engine) ConfigureEventStore(store EventStore) {
+	e.eventStore = store
+}
+
+// ReplayedComputation is the state of a computation rebuilt from its events.
+type ReplayedComputation struct {
+	ID          string
+	Fingerprint string
+	Seed        int64
+	Tenant      string
+	// Data hold the context values, with the secrets and the context references as is
+	Data map[string]interface{}
+	// States hold the last ended state of each node, by node name
+	States map[string]NodeStateReport
+	// Running is the nodes scheduled or started without ended state, sorted by name
+	Running []string
+	Ended   bool
+	// Interrupted tell if the computation is ended on interruption, and can be resumed
+	Interrupted bool
+	Error       string
+	// Sequence is the sequence of the last replayed event
+	Sequence int64
+	Updated  time.Time
+}
+
+// ReplayComputation rebuild the state of a computation from its events in an event store,
+// up to a time (or all the events for a zero time), e.g. to know the context of a computation at a given time.
+func ReplayComputation(store EventStore, computationID string, until time.Time) (ReplayedComputation, error) {
+	events, err := store.Events(computationID, 0)
+	if err != nil {
+		return ReplayedComputation{}, err
+	}
+	if !until.IsZero() {
+		for index, event := range events {
+			if event.Time.After(until) {
+				events = events[:index]
+				break
+			}
+		}
+	}
+	return ReplayEvents(computationID, events)
+}
+
+// ReplayEvents rebuild the state of a computation from its events, in order.
+func ReplayEvents(computationID string, events []StoredEvent) (ReplayedComputation, error) {
+	replayed := ReplayedComputation{
+		ID:     computationID,
+		Data:   make(map[string]interface{}),
+		States: make(map[string]NodeStateReport),
+	}
+	running := make(map[string]bool)
+	for _, event := range events {
+		switch event.Type {
+		case ComputationStartedEvent:
+			replayed.Fingerprint = event.Fingerprint
+			replayed.Seed = event.Seed
+			replayed.Tenant = event.Tenant
+			replayed.Ended = false
+			replayed.Interrupted = false
+			replayed.Error = ""
+		case ComputationEndedEvent:
+			replayed.Ended = true
+			replayed.Interrupted = event.Interrupted
+			replayed.Error = event.Error
+		case NodeScheduledEvent, NodeStartedEvent:
+			running[event.Node] = true
+		case NodeEndedEvent:
+			delete(running, event.Node)
+			replayed.States[event.Node] = NodeStateReport{
+				Node:   event.Node,
+				State:  event.State,
+				Branch: event.Branch,
+				Error:  event.Error,
+				Code:   event.Code,
+				Token:  event.Token,
+			}
+		case ContextMutatedEvent:
+			if event.Value == nil {
+				delete(replayed.Data, event.Key)
+				break
+			}
+			var value interface{}
+			var err error
+			if event.ValueType != "" {
+				value, err = decodeTypedValue(event.Key, event.Value, event.ValueType)
+			} else {
+				err = json.Unmarshal(event.Value, &value)
+			}
+			if err != nil {
+				return ReplayedComputation{}, fmt.Errorf("can't replay event %v of computation '%v': %w", event.Sequence, computationID, err)
+			}
+			replayed.Data[event.Key] = value
+		}
+		replayed.Sequence = event.Sequence
+		replayed.Updated = event.Time
+	}
+	replayed.Running = make([]string, 0, len(running))
+	for node := range running {
+		replayed.Running = append(replayed.Running, node)
+	}
+	sort.Strings(replayed.Running)
+	return replayed, nil
+}
+
+// Checkpoint give a checkpoint of the replayed computation, to resume it once saved into a CheckpointStore
+// (see Engine.ResumeCheckpoint), or to deliver an event to its paused nodes (see Engine.Deliver).
+// The computation is completed once ended without pause nor interruption.
+func (r ReplayedComputation) Checkpoint() (Checkpoint, error) {
+	data, types, err := encodeCheckpointData(r.Data)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	report := ComputationReport{
+		ID:          r.ID,
+		Fingerprint: r.Fingerprint,
+		Error:       r.Error,
+		Data:        data,
+		States:      make([]NodeStateReport, 0, len(r.States)),
+		Seed:        r.Seed,
+		Tenant:      r.Tenant,
+		Types:       types,
+	}
+	paused := false
+	for _, state := range r.States {
+		report.States = append(report.States, state)
+		paused = paused || state.State == PauseState
+	}
+	sort.Slice(report.States, func(i, j int) bool {
+		return report.States[i].Node < report.States[j].Node
+	})
+	completed := r.Ended && !r.Interrupted && !paused && len(r.Running) == 0
+	return Checkpoint{Report: report, Completed: completed, Updated: r.Updated}, nil
+}
+
+// MemoryEventStore is an EventStore in memory, e.g. for tests.
+type MemoryEventStore struct {
+	mu     sync.Mutex
+	events map[string][]StoredEvent
+}
+
+// NewMemoryEventStore create an empty MemoryEventStore.
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{events: make(map[string][]StoredEvent)}
+}
+
+// Append add events at the end of the log of their computation.
+func (s *MemoryEventStore) Append(events ...StoredEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		log := s.events[event.ComputationID]
+		event.Sequence = int64(len(log)) + 1
+		s.events[event.ComputationID] = append(log, event)
+	}
+	return nil
+}
+
+// Events give the events of a computation with a sequence after a given one.
+func (s *MemoryEventStore) Events(computationID string, after int64) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log := s.events[computationID]
+	if after >= int64(len(log)) {
+		return []StoredEvent{}, nil
+	}
+	if after < 0 {
+		after = 0
+	}
+	return append([]StoredEvent(nil), log[after:]...), nil
+}
+
+// recordComputationStarted append the start of a computation, with its context data as mutations.
+func (e *Engine) recordComputationStarted(cp *Computation) error {
+	now := time.Now()
+	events := []StoredEvent{{
+		Type:          ComputationStartedEvent,
+		Time:          now,
+		ComputationID: cp.ID,
+		Fingerprint:   cp.System.Fingerprint(),
+		Seed:          cp.Context.seed,
+		Tenant:        cp.tenant,
+	}}
+	keys := make([]string, 0, len(cp.Context.Data))
+	for key := range cp.Context.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, typ := encodeEventValue(cp.Context.Data[key])
+		events = append(events, StoredEvent{Type: ContextMutatedEvent, Time: now, ComputationID: cp.ID, Key: key, Value: value, ValueType: typ})
+	}
+	return e.eventStore.Append(events...)
+}
+
+// recordComputationEnded append the end of a computation.
+func (e *Engine) recordComputationEnded(cp *Computation, err error) error {
+	event := StoredEvent{Type: ComputationEndedEvent, Time: time.Now(), ComputationID: cp.ID, Interrupted: errors.Is(err, ErrComputationInterrupted)}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return e.eventStore.Append(event)
+}
+
+// recordNodeEvents is the interceptor appending the scheduling of a node, then its context mutations and its state.
+func (e *Engine) recordNodeEvents(cp *Computation) nodeInterceptor {
+	return func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+		name := fmt.Sprint(node)
+		err := e.eventStore.Append(StoredEvent{Type: NodeScheduledEvent, Time: time.Now(), ComputationID: cp.ID, Node: name})
+		if err != nil {
+			return NewAbortComputeState(fmt.Errorf("can't append events of node '%v': %w", node, err))
+		}
+		before := make(map[string][]byte, len(c.Data))
+		for key, value := range c.Data {
+			before[key], _ = encodeEventValue(value)
+		}
+
+		state := compute(c)
+
+		now := time.Now()
+		events := make([]StoredEvent, 0)
+		keys := make([]string, 0, len(c.Data)+len(before))
+		for key := range c.Data {
+			keys = append(keys, key)
+		}
+		for key := range before {
+			if _, found := c.Data[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			var after []byte
+			var typ string
+			if value, found := c.Data[key]; found {
+				after, typ = encodeEventValue(value)
+			}
+			if previous, found := before[key]; found && string(previous) == string(after) {
+				continue
+			}
+			events = append(events, StoredEvent{Type: ContextMutatedEvent, Time: now, ComputationID: cp.ID, Node: name, Key: key, Value: after, ValueType: typ})
+		}
+		ended := StoredEvent{Type: NodeEndedEvent, Time: now, ComputationID: cp.ID, Node: name, State: state.Value, Branch: state.Branch, Code: state.Code, Token: state.Token}
+		if state.Error != nil {
+			ended.Error = state.Error.Error()
+		}
+		err = e.eventStore.Append(append(events, ended)...)
+		if err != nil {
+			return NewAbortComputeState(fmt.Errorf("can't append events of node '%v': %w", node, err))
+		}
+		return state
+	}
+}
+
+// recordNodeStarted is the interceptor appending the start of a node, once its policies or a worker let it run.
+func (e *Engine) recordNodeStarted(cp *Computation) nodeInterceptor {
+	return func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+		err := e.eventStore.Append(StoredEvent{Type: NodeStartedEvent, Time: time.Now(), ComputationID: cp.ID, Node: fmt.Sprint(node)})
+		if err != nil {
+			return NewAbortComputeState(fmt.Errorf("can't append events of node '%v': %w", node, err))
+		}
+		return compute(c)
+	}
+}
+
+// encodeEventValue encode a context value in JSON, or its string representation when it can't be encoded,
+// and give its type for a typed value, like in a checkpoint.
+func encodeEventValue(value interface{}) ([]byte, string) {
+	encoded, typ, _ := encodeTypedValue(value)
+	return encoded, typ
+}
@@ -0,0 +1,315 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_Engine_ConfigureEventStore(t *testing.T) {
+	check, _ := NewDecisionNode("check", func(c *Context) (bool, error) {
+		c.Store("checked", true)
+		return true, nil
+	})
+	total, _ := NewActionNode("total", func(c *Context) error {
+		c.Store("total", 42)
+		c.Delete("draft")
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(check)
+	ns.AddNode(total)
+	ns.AddLinkOnBranch(check, total, true)
+	ns.ActivateInPlace()
+
+	store := NewMemoryEventStore()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureEventStore(store)
+
+	result := eng.Compute(map[string]interface{}{"draft": "yes", "count": 2})
+	events, err := store.Events(result.ID, 0)
+	if err != nil {
+		t.Fatalf("error - got: %+v, want: %+v", err, nil)
+	}
+
+	expectedEvents := []StoredEvent{
+		{Sequence: 1, Type: ComputationStartedEvent, Fingerprint: ns.Fingerprint(), Seed: result.Seed},
+		{Sequence: 2, Type: ContextMutatedEvent, Key: "count", Value: []byte("2")},
+		{Sequence: 3, Type: ContextMutatedEvent, Key: "draft", Value: []byte(`"yes"`)},
+		{Sequence: 4, Type: NodeScheduledEvent, Node: "check"},
+		{Sequence: 5, Type: NodeStartedEvent, Node: "check"},
+		{Sequence: 6, Type: ContextMutatedEvent, Node: "check", Key: "checked", Value: []byte("true")},
+		{Sequence: 7, Type: NodeEndedEvent, Node: "check", State: ContinueState, Branch: boolPointer(true)},
+		{Sequence: 8, Type: NodeScheduledEvent, Node: "total"},
+		{Sequence: 9, Type: NodeStartedEvent, Node: "total"},
+		{Sequence: 10, Type: ContextMutatedEvent, Node: "total", Key: "draft"},
+		{Sequence: 11, Type: ContextMutatedEvent, Node: "total", Key: "total", Value: []byte("42")},
+		{Sequence: 12, Type: NodeEndedEvent, Node: "total", State: ContinueState},
+		{Sequence: 13, Type: ComputationEndedEvent},
+	}
+	for index := range expectedEvents {
+		expectedEvents[index].ComputationID = result.ID
+	}
+	if !cmp.Equal(events, expectedEvents, cmpopts.IgnoreFields(StoredEvent{}, "Time")) {
+		t.Errorf("events - got: %+v, want: %+v", events, expectedEvents)
+	}
+
+	replayed, err := ReplayComputation(store, result.ID, time.Time{})
+	if err != nil {
+		t.Fatalf("replay error - got: %+v, want: %+v", err, nil)
+	}
+	expectedReplay := ReplayedComputation{
+		ID:          result.ID,
+		Fingerprint: ns.Fingerprint(),
+		Seed:        result.Seed,
+		Data:        map[string]interface{}{"count": 2.0, "checked": true, "total": 42.0},
+		States: map[string]NodeStateReport{
+			"check": {Node: "check", State: ContinueState, Branch: boolPointer(true)},
+			"total": {Node: "total", State: ContinueState},
+		},
+		Running:  []string{},
+		Ended:    true,
+		Sequence: 13,
+	}
+	if !cmp.Equal(replayed, expectedReplay, cmpopts.IgnoreFields(ReplayedComputation{}, "Updated")) {
+		t.Errorf("replay - got: %+v, want: %+v", replayed, expectedReplay)
+	}
+}
+
+func Test_ReplayEvents(t *testing.T) {
+	events := []StoredEvent{
+		{Sequence: 1, Type: ComputationStartedEvent},
+		{Sequence: 2, Type: ContextMutatedEvent, Key: "count", Value: []byte("1")},
+		{Sequence: 3, Type: NodeScheduledEvent, Node: "a"},
+		{Sequence: 4, Type: NodeEndedEvent, Node: "a", State: AbortState, Error: "boom", Code: TransientAbort},
+		{Sequence: 5, Type: NodeScheduledEvent, Node: "b"},
+		{Sequence: 6, Type: NodeStartedEvent, Node: "b"},
+		{Sequence: 7, Type: ContextMutatedEvent, Node: "b", Key: "count", Value: []byte("2")},
+	}
+
+	testCases := []struct {
+		name           string
+		givenEvents    []StoredEvent
+		expectedReplay ReplayedComputation
+		expectedError  error
+	}{
+		{
+			name:        "Can replay a running computation",
+			givenEvents: events,
+			expectedReplay: ReplayedComputation{
+				ID:       "id",
+				Data:     map[string]interface{}{"count": 2.0},
+				States:   map[string]NodeStateReport{"a": {Node: "a", State: AbortState, Error: "boom", Code: TransientAbort}},
+				Running:  []string{"b"},
+				Sequence: 7,
+			},
+		},
+		{
+			name:        "Can replay a part of the events",
+			givenEvents: events[:3],
+			expectedReplay: ReplayedComputation{
+				ID:       "id",
+				Data:     map[string]interface{}{"count": 1.0},
+				States:   map[string]NodeStateReport{},
+				Running:  []string{"a"},
+				Sequence: 3,
+			},
+		},
+		{
+			name:          "Can't replay an invalid value",
+			givenEvents:   []StoredEvent{{Sequence: 1, Type: ContextMutatedEvent, Key: "count", Value: []byte("{")}},
+			expectedError: errors.New("can't replay event 1 of computation 'id': unexpected end of JSON input"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			replayed, err := ReplayEvents("id", testCase.givenEvents)
+
+			if !cmp.Equal(errorMessage(err), errorMessage(testCase.expectedError)) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && !cmp.Equal(replayed, testCase.expectedReplay) {
+				t.Errorf("replay - got: %+v, want: %+v", replayed, testCase.expectedReplay)
+			}
+		})
+	}
+}
+
+func Test_ReplayComputation_until(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryEventStore()
+	store.Append(
+		StoredEvent{Type: ContextMutatedEvent, Time: start, ComputationID: "id", Key: "count", Value: []byte("1")},
+		StoredEvent{Type: ContextMutatedEvent, Time: start.Add(time.Minute), ComputationID: "id", Key: "count", Value: []byte("2")},
+		StoredEvent{Type: ContextMutatedEvent, Time: start, ComputationID: "other", Key: "count", Value: []byte("3")},
+	)
+
+	replayed, _ := ReplayComputation(store, "id", start.Add(time.Second))
+
+	expectedData := map[string]interface{}{"count": 1.0}
+	if !cmp.Equal(replayed.Data, expectedData) || replayed.Sequence != 1 {
+		t.Errorf("got: %+v %v, want: %+v %v", replayed.Data, replayed.Sequence, expectedData, 1)
+	}
+}
+
+func Test_ReplayedComputation_Checkpoint(t *testing.T) {
+	storeValues, _ := NewActionNode("storeValues", func(c *Context) error {
+		c.Store("count", 2)
+		err := c.StoreSecret("password", "s3cr3t")
+		if err != nil {
+			return err
+		}
+		return c.StoreExternal("document", "content")
+	})
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	readValues, _ := NewActionNode("readValues", func(c *Context) error {
+		password, err := c.ReadSecret("password")
+		if err != nil {
+			return err
+		}
+		document, _ := c.Read("document")
+		c.Store("read", fmt.Sprint(password, " ", document, " ", c.Rand().Int63()))
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(storeValues)
+	ns.AddNode(approval)
+	ns.AddNode(readValues)
+	ns.AddLink(storeValues, approval)
+	ns.AddLink(approval, readValues)
+	ns.ActivateInPlace()
+
+	cipher, _ := NewAESCipher([]byte("0123456789abcdef"))
+	contextStore := NewMemoryContextStore()
+	eventStore := NewMemoryEventStore()
+	recordingEngine := NewEngine(SequentialComputation)
+	recordingEngine.ConfigureNodeSystem(ns)
+	recordingEngine.ConfigureCipher(cipher)
+	recordingEngine.ConfigureContextStore(contextStore)
+	recordingEngine.ConfigureEventStore(eventStore)
+	paused, _ := recordingEngine.Submit(map[string]interface{}{}, SubmitOptions{Tenant: "tenant"}).Wait(context.Background())
+	token := paused.PausedTokens()[0]
+
+	replayed, err := ReplayComputation(eventStore, paused.ID, time.Time{})
+	if err != nil {
+		t.Fatalf("replay error - got: %+v, want: %+v", err, nil)
+	}
+	checkpoint, err := replayed.Checkpoint()
+	if err != nil {
+		t.Fatalf("checkpoint error - got: %+v, want: %+v", err, nil)
+	}
+	if checkpoint.Completed || checkpoint.Report.Tenant != "tenant" || checkpoint.Report.Seed != paused.Seed {
+		t.Errorf("checkpoint - got: %+v, want: paused checkpoint of the tenant with the seed", checkpoint)
+	}
+	checkpointStore := NewMemoryCheckpointStore()
+	checkpointStore.Save(checkpoint)
+
+	resumingEngine := NewEngine(SequentialComputation)
+	resumingEngine.ConfigureNodeSystem(ns)
+	resumingEngine.ConfigureCipher(cipher)
+	resumingEngine.ConfigureContextStore(contextStore)
+	resumingEngine.ConfigureCheckpointStore(checkpointStore)
+	result := resumingEngine.Deliver(token, "approved")
+
+	expected := recordingEngine.Deliver(token, "approved")
+	if !result.Success || result.ID != paused.ID || result.Data["read"] != expected.Data["read"] {
+		t.Errorf("resumed - got: %+v %+v, want: %+v", result.Error, result.Data["read"], expected.Data["read"])
+	}
+}
+
+func Test_ReplayedComputation_Checkpoint_completed(t *testing.T) {
+	testCases := []struct {
+		name              string
+		givenReplay       ReplayedComputation
+		expectedCompleted bool
+	}{
+		{
+			name:              "Can complete an ended computation",
+			givenReplay:       ReplayedComputation{Ended: true, States: map[string]NodeStateReport{"a": {Node: "a", State: ContinueState}}},
+			expectedCompleted: true,
+		},
+		{
+			name:        "Can't complete a paused computation",
+			givenReplay: ReplayedComputation{Ended: true, States: map[string]NodeStateReport{"a": {Node: "a", State: PauseState, Token: "token"}}},
+		},
+		{
+			name:        "Can't complete an interrupted computation",
+			givenReplay: ReplayedComputation{Ended: true, Interrupted: true},
+		},
+		{
+			name:        "Can't complete a running computation",
+			givenReplay: ReplayedComputation{Running: []string{"a"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checkpoint, _ := testCase.givenReplay.Checkpoint()
+
+			if checkpoint.Completed != testCase.expectedCompleted {
+				t.Errorf("got: %+v, want: %+v", checkpoint.Completed, testCase.expectedCompleted)
+			}
+		})
+	}
+}
+
+type failingEventStore struct {
+	*MemoryEventStore
+	failOn EventType
+}
+
+func (s failingEventStore) Append(events ...StoredEvent) error {
+	for _, event := range events {
+		if event.Type == s.failOn {
+			return errors.New("store is down")
+		}
+	}
+	return s.MemoryEventStore.Append(events...)
+}
+
+func Test_Engine_ConfigureEventStore_appendError(t *testing.T) {
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(action)
+	ns.ActivateInPlace()
+
+	testCases := []struct {
+		name          string
+		givenFailOn   EventType
+		expectedError error
+	}{
+		{
+			name:          "Can't compute a node without its scheduled event",
+			givenFailOn:   NodeScheduledEvent,
+			expectedError: fmt.Errorf("can't append events of node 'action': %w", errors.New("store is down")),
+		},
+		{
+			name:          "Can't compute a node without its ended event",
+			givenFailOn:   NodeEndedEvent,
+			expectedError: fmt.Errorf("can't append events of node 'action': %w", errors.New("store is down")),
+		},
+		{
+			name:          "Can't compute without the started event of the computation",
+			givenFailOn:   ComputationStartedEvent,
+			expectedError: fmt.Errorf("can't append events of computation: %w", errors.New("store is down")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigureEventStore(failingEventStore{MemoryEventStore: NewMemoryEventStore(), failOn: testCase.givenFailOn})
+
+			result := eng.Compute(nil)
+
+			if !cmp.Equal(errorMessage(result.Error), errorMessage(testCase.expectedError)) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.expectedError)
+			}
+		})
+	}
+}
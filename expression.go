@@ -0,0 +1,864 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed expression of the hoff expression language over the context data.
+// This language is specific to hoff, without type checking, macros, timestamps, durations, nor extensions,
+// and a CEL (Common Expression Language) expression is not expected to be accepted or evaluated the same way.
+//
+// The supported subset is:
+//
+//	expression     := or
+//	or             := and ( "||" and )*
+//	and            := comparison ( "&&" comparison )*
+//	comparison     := additive [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) additive ]
+//	additive       := multiplicative ( ( "+" | "-" ) multiplicative )*
+//	multiplicative := unary ( ( "*" | "/" | "%" ) unary )*
+//	unary          := ( "!" | "-" ) unary | primary
+//	primary        := literal | path | function "(" [ expression ( "," expression )* ] ")"
+//	                | "(" expression ")" | "[" [ expression ( "," expression )* ] "]"
+//	path           := key ( "." name | "[" expression "]" )*
+//	literal        := number | string | "true" | "false" | "null"
+//
+// where a string is written between double or single quotes, and the functions are
+// has(path), size(value), string(value), number(value), contains(value, item),
+// startsWith(text, prefix), endsWith(text, suffix), and matches(text, pattern).
+// The numbers are evaluated as float64, whatever their type into the context.
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// exprReader read a key of the context data.
+type exprReader func(key string) (interface{}, bool)
+
+type exprNode interface {
+	eval(read exprReader) (interface{}, error)
+}
+
+// ParseExpression parse an expression over the context data.
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse expression '%v': %v", source, err)
+	}
+	parser := &expressionParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected '%v'", tokens[parser.position].value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse expression '%v': %v", source, err)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluate the expression against the context data.
+func (e *Expression) Eval(data map[string]interface{}) (interface{}, error) {
+	return e.evalRead(func(key string) (interface{}, bool) {
+		value, found := data[key]
+		return value, found
+	})
+}
+
+// EvalBool evaluate the expression against the context data, as a bool.
+func (e *Expression) EvalBool(data map[string]interface{}) (bool, error) {
+	value, err := e.Eval(data)
+	if err != nil {
+		return false, err
+	}
+	return e.asBool(value)
+}
+
+// Keys give the context keys read by the expression, sorted, without the keys only checked by has.
+func (e *Expression) Keys() []string {
+	found := make(map[string]bool)
+	collectExpressionKeys(e.root, found)
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *Expression) evalRead(read exprReader) (interface{}, error) {
+	value, err := e.root.eval(read)
+	if err != nil {
+		return nil, fmt.Errorf("can't evaluate expression '%v': %v", e.source, err)
+	}
+	return value, nil
+}
+
+func (e *Expression) asBool(value interface{}) (bool, error) {
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("can't evaluate expression '%v' as bool: %v", e.source, value)
+	}
+	return result, nil
+}
+
+func collectExpressionKeys(node exprNode, keys map[string]bool) {
+	switch n := node.(type) {
+	case *exprPath:
+		keys[n.key] = true
+		for _, accessor := range n.accessors {
+			if accessor.index != nil {
+				collectExpressionKeys(accessor.index, keys)
+			}
+		}
+	case *exprUnary:
+		collectExpressionKeys(n.operand, keys)
+	case *exprBinary:
+		collectExpressionKeys(n.left, keys)
+		collectExpressionKeys(n.right, keys)
+	case *exprList:
+		for _, item := range n.items {
+			collectExpressionKeys(item, keys)
+		}
+	case *exprCall:
+		if n.name == "has" {
+			return
+		}
+		for _, argument := range n.arguments {
+			collectExpressionKeys(argument, keys)
+		}
+	}
+}
+
+type exprLiteral struct {
+	value interface{}
+}
+
+func (n *exprLiteral) eval(exprReader) (interface{}, error) {
+	return n.value, nil
+}
+
+// exprAccessor access a field (by name) or an item (by index) of a value.
+type exprAccessor struct {
+	name  string
+	index exprNode
+}
+
+type exprPath struct {
+	source    string
+	key       string
+	accessors []exprAccessor
+}
+
+func (n *exprPath) eval(read exprReader) (interface{}, error) {
+	value, found, err := n.lookup(read)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("can't find key: %v", n.source)
+	}
+	return value, nil
+}
+
+func (n *exprPath) lookup(read exprReader) (interface{}, bool, error) {
+	value, found := read(n.key)
+	if !found {
+		return nil, false, nil
+	}
+	for _, accessor := range n.accessors {
+		var key interface{} = accessor.name
+		if accessor.index != nil {
+			index, err := accessor.index.eval(read)
+			if err != nil {
+				return nil, false, err
+			}
+			key = index
+		}
+		value, found = accessValue(value, key)
+		if !found {
+			return nil, false, nil
+		}
+	}
+	return normalizeExprValue(value), true, nil
+}
+
+type exprUnary struct {
+	operator string
+	operand  exprNode
+}
+
+func (n *exprUnary) eval(read exprReader) (interface{}, error) {
+	value, err := n.operand.eval(read)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "!":
+		if b, ok := value.(bool); ok {
+			return !b, nil
+		}
+	case "-":
+		if number, ok := value.(float64); ok {
+			return -number, nil
+		}
+	}
+	return nil, fmt.Errorf("can't apply '%v' on %v", n.operator, value)
+}
+
+type exprBinary struct {
+	operator    string
+	left, right exprNode
+}
+
+func (n *exprBinary) eval(read exprReader) (interface{}, error) {
+	left, err := n.left.eval(read)
+	if err != nil {
+		return nil, err
+	}
+	if n.operator == "&&" || n.operator == "||" {
+		leftBool, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("can't apply '%v' on %v", n.operator, left)
+		}
+		if leftBool == (n.operator == "||") {
+			return leftBool, nil
+		}
+		right, err := n.right.eval(read)
+		if err != nil {
+			return nil, err
+		}
+		rightBool, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("can't apply '%v' on %v", n.operator, right)
+		}
+		return rightBool, nil
+	}
+
+	right, err := n.right.eval(read)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "==":
+		return equalExprValues(left, right), nil
+	case "!=":
+		return !equalExprValues(left, right), nil
+	case "in":
+		return containsExprValue(right, left)
+	case "<", "<=", ">", ">=":
+		return compareExprValues(n.operator, left, right)
+	}
+	return arithmeticExprValues(n.operator, left, right)
+}
+
+type exprList struct {
+	items []exprNode
+}
+
+func (n *exprList) eval(read exprReader) (interface{}, error) {
+	list := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(read)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+type exprCall struct {
+	name      string
+	arguments []exprNode
+}
+
+// exprFunctions are the functions of the expressions, with their number of arguments.
+var exprFunctions = map[string]int{
+	"has":        1,
+	"size":       1,
+	"string":     1,
+	"number":     1,
+	"contains":   2,
+	"startsWith": 2,
+	"endsWith":   2,
+	"matches":    2,
+}
+
+func (n *exprCall) eval(read exprReader) (interface{}, error) {
+	if n.name == "has" {
+		path := n.arguments[0].(*exprPath)
+		_, found, err := path.lookup(read)
+		return found, err
+	}
+	arguments := make([]interface{}, 0, len(n.arguments))
+	for _, argument := range n.arguments {
+		value, err := argument.eval(read)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, value)
+	}
+
+	switch n.name {
+	case "size":
+		switch value := arguments[0].(type) {
+		case string:
+			return float64(len([]rune(value))), nil
+		case nil:
+		default:
+			kind := reflect.TypeOf(value).Kind()
+			if kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+				return float64(reflect.ValueOf(value).Len()), nil
+			}
+		}
+	case "string":
+		return formatExprValue(arguments[0]), nil
+	case "number":
+		switch value := arguments[0].(type) {
+		case float64:
+			return value, nil
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("can't convert to number: %v", value)
+			}
+			return number, nil
+		case bool:
+			if value {
+				return float64(1), nil
+			}
+			return float64(0), nil
+		}
+	case "contains":
+		if text, ok := arguments[0].(string); ok {
+			if substring, ok := arguments[1].(string); ok {
+				return strings.Contains(text, substring), nil
+			}
+			break
+		}
+		return containsExprValue(arguments[0], arguments[1])
+	case "startsWith", "endsWith", "matches":
+		text, textOk := arguments[0].(string)
+		other, otherOk := arguments[1].(string)
+		if !textOk || !otherOk {
+			break
+		}
+		switch n.name {
+		case "startsWith":
+			return strings.HasPrefix(text, other), nil
+		case "endsWith":
+			return strings.HasSuffix(text, other), nil
+		}
+		pattern, err := regexp.Compile(other)
+		if err != nil {
+			return nil, fmt.Errorf("can't compile pattern '%v': %v", other, err)
+		}
+		return pattern.MatchString(text), nil
+	}
+	return nil, fmt.Errorf("can't apply %v on %v", n.name, formatExprArguments(arguments))
+}
+
+func formatExprArguments(arguments []interface{}) string {
+	formatted := make([]string, 0, len(arguments))
+	for _, argument := range arguments {
+		formatted = append(formatted, fmt.Sprint(argument))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// normalizeExprValue give a number of any type as a float64.
+func normalizeExprValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return value
+}
+
+// accessValue give a field of a map or struct, or an item of a slice.
+func accessValue(value interface{}, key interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, false
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		name, ok := key.(string)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		item := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !item.IsValid() {
+			return nil, false
+		}
+		return item.Interface(), true
+	case reflect.Struct:
+		name, ok := key.(string)
+		if !ok {
+			return nil, false
+		}
+		field := v.FieldByName(name)
+		if !field.IsValid() || !field.CanInterface() {
+			return nil, false
+		}
+		return field.Interface(), true
+	case reflect.Slice, reflect.Array:
+		index, ok := key.(float64)
+		if !ok || index != math.Trunc(index) || index < 0 || int(index) >= v.Len() {
+			return nil, false
+		}
+		return v.Index(int(index)).Interface(), true
+	}
+	return nil, false
+}
+
+func equalExprValues(left, right interface{}) bool {
+	left, right = normalizeExprValue(left), normalizeExprValue(right)
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	leftValue, rightValue := reflect.ValueOf(left), reflect.ValueOf(right)
+	if isExprList(leftValue) && isExprList(rightValue) {
+		if leftValue.Len() != rightValue.Len() {
+			return false
+		}
+		for i := 0; i < leftValue.Len(); i++ {
+			if !equalExprValues(leftValue.Index(i).Interface(), rightValue.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func isExprList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func containsExprValue(container, item interface{}) (interface{}, error) {
+	if container != nil {
+		v := reflect.ValueOf(container)
+		switch {
+		case isExprList(v):
+			for i := 0; i < v.Len(); i++ {
+				if equalExprValues(v.Index(i).Interface(), item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case v.Kind() == reflect.Map:
+			_, found := accessValue(container, item)
+			return found, nil
+		}
+	}
+	return nil, fmt.Errorf("can't look for %v in %v", item, container)
+}
+
+func compareExprValues(operator string, left, right interface{}) (interface{}, error) {
+	var comparison int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, fmt.Errorf("can't compare %v with %v", left, right)
+		}
+		switch {
+		case l < r:
+			comparison = -1
+		case l > r:
+			comparison = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("can't compare %v with %v", left, right)
+		}
+		comparison = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("can't compare %v with %v", left, right)
+	}
+	switch operator {
+	case "<":
+		return comparison < 0, nil
+	case "<=":
+		return comparison <= 0, nil
+	case ">":
+		return comparison > 0, nil
+	}
+	return comparison >= 0, nil
+}
+
+func arithmeticExprValues(operator string, left, right interface{}) (interface{}, error) {
+	if operator == "+" {
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+		if l, ok := left.([]interface{}); ok {
+			if r, ok := right.([]interface{}); ok {
+				return append(append([]interface{}(nil), l...), r...), nil
+			}
+		}
+	}
+	l, leftOk := left.(float64)
+	r, rightOk := right.(float64)
+	if !leftOk || !rightOk {
+		return nil, fmt.Errorf("can't apply '%v' on %v and %v", operator, left, right)
+	}
+	switch operator {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	}
+	if r == 0 {
+		return nil, fmt.Errorf("can't divide %v by zero", l)
+	}
+	if operator == "/" {
+		return l / r, nil
+	}
+	return math.Mod(l, r), nil
+}
+
+func formatExprValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+type exprTokenKind int
+
+const (
+	exprOperator exprTokenKind = iota
+	exprName
+	exprNumber
+	exprString
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	value string
+}
+
+// exprOperators are the operators and punctuations, the longest first.
+var exprOperators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ",", "."}
+
+func tokenizeExpression(source string) ([]exprToken, error) {
+	tokens := make([]exprToken, 0)
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			var builder strings.Builder
+			end := i + 1
+			for ; end < len(runes) && runes[end] != r; end++ {
+				if runes[end] == '\\' && end+1 < len(runes) {
+					end++
+					switch runes[end] {
+					case 'n':
+						builder.WriteRune('\n')
+					case 't':
+						builder.WriteRune('\t')
+					default:
+						builder.WriteRune(runes[end])
+					}
+					continue
+				}
+				builder.WriteRune(runes[end])
+			}
+			if end == len(runes) {
+				return nil, errors.New("missing closing quote")
+			}
+			tokens = append(tokens, exprToken{kind: exprString, value: builder.String()})
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.' || runes[end] == 'e' || runes[end] == 'E' ||
+				((runes[end] == '+' || runes[end] == '-') && (runes[end-1] == 'e' || runes[end-1] == 'E'))) {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: exprNumber, value: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: exprName, value: string(runes[i:end])})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range exprOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected '%v'", string(r))
+			}
+			tokens = append(tokens, exprToken{kind: exprOperator, value: operator})
+			i += len([]rune(operator))
+		}
+	}
+	return tokens, nil
+}
+
+type expressionParser struct {
+	tokens   []exprToken
+	position int
+}
+
+func (p *expressionParser) peekOperator(operators ...string) (string, bool) {
+	if p.position >= len(p.tokens) {
+		return "", false
+	}
+	token := p.tokens[p.position]
+	for _, operator := range operators {
+		if (token.kind == exprOperator || (token.kind == exprName && operator == "in")) && token.value == operator {
+			return operator, true
+		}
+	}
+	return "", false
+}
+
+func (p *expressionParser) expect(operator string) error {
+	if _, found := p.peekOperator(operator); !found {
+		if p.position >= len(p.tokens) {
+			return fmt.Errorf("missing '%v'", operator)
+		}
+		return fmt.Errorf("unexpected '%v'", p.tokens[p.position].value)
+	}
+	p.position++
+	return nil
+}
+
+func (p *expressionParser) parseBinary(next func() (exprNode, error), operators ...string) (exprNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator, found := p.peekOperator(operators...)
+		if !found {
+			return left, nil
+		}
+		p.position++
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *expressionParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *expressionParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	operator, found := p.peekOperator("==", "!=", "<=", ">=", "<", ">", "in")
+	if !found {
+		return left, nil
+	}
+	p.position++
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &exprBinary{operator: operator, left: left, right: right}, nil
+}
+
+func (p *expressionParser) parseAdditive() (exprNode, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *expressionParser) parseMultiplicative() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *expressionParser) parseUnary() (exprNode, error) {
+	if operator, found := p.peekOperator("!", "-"); found {
+		p.position++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprUnary{operator: operator, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *expressionParser) parsePrimary() (exprNode, error) {
+	if p.position >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	token := p.tokens[p.position]
+	p.position++
+	switch token.kind {
+	case exprNumber:
+		number, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%v'", token.value)
+		}
+		return &exprLiteral{value: number}, nil
+	case exprString:
+		return &exprLiteral{value: token.value}, nil
+	case exprName:
+		switch token.value {
+		case "true", "false":
+			return &exprLiteral{value: token.value == "true"}, nil
+		case "null":
+			return &exprLiteral{}, nil
+		case "in":
+			return nil, errors.New("unexpected 'in'")
+		}
+		if _, found := p.peekOperator("("); found {
+			return p.parseCall(token.value)
+		}
+		return p.parsePath(token.value)
+	}
+	switch token.value {
+	case "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	case "[":
+		items, err := p.parseArguments("]")
+		if err != nil {
+			return nil, err
+		}
+		return &exprList{items: items}, nil
+	}
+	return nil, fmt.Errorf("unexpected '%v'", token.value)
+}
+
+func (p *expressionParser) parseArguments(closing string) ([]exprNode, error) {
+	arguments := make([]exprNode, 0)
+	if _, found := p.peekOperator(closing); found {
+		p.position++
+		return arguments, nil
+	}
+	for {
+		argument, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+		if _, found := p.peekOperator(","); !found {
+			return arguments, p.expect(closing)
+		}
+		p.position++
+	}
+}
+
+func (p *expressionParser) parseCall(name string) (exprNode, error) {
+	count, known := exprFunctions[name]
+	if !known {
+		return nil, fmt.Errorf("unknown function '%v'", name)
+	}
+	p.position++
+	arguments, err := p.parseArguments(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(arguments) != count {
+		return nil, fmt.Errorf("function '%v' need %v argument(s), got %v", name, count, len(arguments))
+	}
+	if _, isPath := arguments[0].(*exprPath); name == "has" && !isPath {
+		return nil, errors.New("function 'has' need a key as argument")
+	}
+	return &exprCall{name: name, arguments: arguments}, nil
+}
+
+func (p *expressionParser) parsePath(key string) (exprNode, error) {
+	path := &exprPath{source: key, key: key}
+	for {
+		operator, found := p.peekOperator(".", "[")
+		if !found {
+			return path, nil
+		}
+		p.position++
+		if operator == "." {
+			if p.position >= len(p.tokens) || p.tokens[p.position].kind != exprName {
+				return nil, fmt.Errorf("missing field name after '%v'", path.source)
+			}
+			name := p.tokens[p.position].value
+			p.position++
+			path.source += "." + name
+			path.accessors = append(path.accessors, exprAccessor{name: name})
+			continue
+		}
+		start := p.position
+		index, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect("]")
+		if err != nil {
+			return nil, err
+		}
+		path.source += "[" + joinExprTokens(p.tokens[start:p.position-1]) + "]"
+		path.accessors = append(path.accessors, exprAccessor{index: index})
+	}
+}
+
+func joinExprTokens(tokens []exprToken) string {
+	values := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token.kind == exprString {
+			values = append(values, strconv.Quote(token.value))
+			continue
+		}
+		values = append(values, token.value)
+	}
+	return strings.Join(values, "")
+}
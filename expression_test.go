@@ -0,0 +1,196 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ParseExpression(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenSource   string
+		expectedError error
+	}{
+		{
+			name:        "Can parse an expression",
+			givenSource: `user.age >= 18 && (country == "FR" || country in ["BE", 'CH']) && !has(user.banned)`,
+		},
+		{
+			name:        "Can parse an expression with functions",
+			givenSource: `size(items) > 0 && startsWith(items[0].name, "a") && matches(code, "^[A-Z]+$")`,
+		},
+		{
+			name:          "Can't parse an expression with missing closing quote",
+			givenSource:   `name == "bob`,
+			expectedError: errors.New("can't parse expression 'name == \"bob': missing closing quote"),
+		},
+		{
+			name:          "Can't parse an expression with unknown character",
+			givenSource:   `name = "bob"`,
+			expectedError: errors.New("can't parse expression 'name = \"bob\"': unexpected '='"),
+		},
+		{
+			name:          "Can't parse an expression with missing parenthesis",
+			givenSource:   `(a || b`,
+			expectedError: errors.New("can't parse expression '(a || b': missing ')'"),
+		},
+		{
+			name:          "Can't parse an expression with trailing token",
+			givenSource:   `a b`,
+			expectedError: errors.New("can't parse expression 'a b': unexpected 'b'"),
+		},
+		{
+			name:          "Can't parse an expression with unknown function",
+			givenSource:   `lower(name) == "bob"`,
+			expectedError: errors.New("can't parse expression 'lower(name) == \"bob\"': unknown function 'lower'"),
+		},
+		{
+			name:          "Can't parse an expression with wrong number of arguments",
+			givenSource:   `contains(name)`,
+			expectedError: errors.New("can't parse expression 'contains(name)': function 'contains' need 2 argument(s), got 1"),
+		},
+		{
+			name:          "Can't parse has without key",
+			givenSource:   `has("name")`,
+			expectedError: errors.New("can't parse expression 'has(\"name\")': function 'has' need a key as argument"),
+		},
+		{
+			name:          "Can't parse an empty expression",
+			givenSource:   ``,
+			expectedError: errors.New("can't parse expression '': unexpected end of expression"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expression, err := ParseExpression(testCase.givenSource)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && expression.String() != testCase.givenSource {
+				t.Errorf("source - got: %+v, want: %+v", expression.String(), testCase.givenSource)
+			}
+		})
+	}
+}
+
+func Test_Expression_Eval(t *testing.T) {
+	type address struct {
+		City string
+	}
+	data := map[string]interface{}{
+		"name":    "bob",
+		"age":     42,
+		"ratio":   float32(0.5),
+		"admin":   false,
+		"tags":    []string{"a", "b"},
+		"items":   []interface{}{map[string]interface{}{"id": 1.0}},
+		"user":    map[string]interface{}{"roles": []interface{}{"dev"}},
+		"address": &address{City: "Paris"},
+		"empty":   nil,
+	}
+
+	testCases := []struct {
+		name          string
+		givenSource   string
+		expectedValue interface{}
+		expectedError error
+	}{
+		{name: "Can evaluate literals", givenSource: `[1, "a", true, null]`, expectedValue: []interface{}{1.0, "a", true, nil}},
+		{name: "Can evaluate numbers of any type", givenSource: `age + ratio * 2`, expectedValue: 43.0},
+		{name: "Can evaluate arithmetic", givenSource: `-(10 - 4) / 4 + 7 % 4`, expectedValue: 1.5},
+		{name: "Can concatenate strings", givenSource: `name + "!"`, expectedValue: "bob!"},
+		{name: "Can concatenate lists", givenSource: `[1] + [2]`, expectedValue: []interface{}{1.0, 2.0}},
+		{name: "Can compare numbers", givenSource: `age >= 18 && age < 100`, expectedValue: true},
+		{name: "Can compare strings", givenSource: `name > "alice"`, expectedValue: true},
+		{name: "Can check equality", givenSource: `age == 42 && name != "alice" && empty == null`, expectedValue: true},
+		{name: "Can check equality of lists", givenSource: `tags == ["a", "b"]`, expectedValue: true},
+		{name: "Can check membership", givenSource: `"b" in tags && !("c" in tags) && "roles" in user`, expectedValue: true},
+		{name: "Can negate", givenSource: `!admin`, expectedValue: true},
+		{name: "Can short-circuit", givenSource: `admin && missing`, expectedValue: false},
+		{name: "Can access fields and items", givenSource: `items[0].id + size(user.roles)`, expectedValue: 2.0},
+		{name: "Can access items by key", givenSource: `user["roles"][0]`, expectedValue: "dev"},
+		{name: "Can access struct fields", givenSource: `address.City`, expectedValue: "Paris"},
+		{name: "Can check a key presence", givenSource: `has(user.roles) && !has(user.team) && !has(missing.key)`, expectedValue: true},
+		{name: "Can get a size", givenSource: `size(name) + size(tags)`, expectedValue: 5.0},
+		{name: "Can convert to string", givenSource: `string(age) + string(admin)`, expectedValue: "42false"},
+		{name: "Can convert to number", givenSource: `number("1.5") + number(true)`, expectedValue: 2.5},
+		{name: "Can check text", givenSource: `contains(name, "o") && startsWith(name, "b") && endsWith(name, "b") && matches(name, "^b.b$")`, expectedValue: true},
+		{name: "Can check list content", givenSource: `contains(tags, "a")`, expectedValue: true},
+		{
+			name:          "Can't evaluate missing key",
+			givenSource:   `user.team == "core"`,
+			expectedError: errors.New("can't evaluate expression 'user.team == \"core\"': can't find key: user.team"),
+		},
+		{
+			name:          "Can't evaluate missing item",
+			givenSource:   `items[1]`,
+			expectedError: errors.New("can't evaluate expression 'items[1]': can't find key: items[1]"),
+		},
+		{
+			name:          "Can't compare values of different types",
+			givenSource:   `age > "18"`,
+			expectedError: errors.New("can't evaluate expression 'age > \"18\"': can't compare 42 with 18"),
+		},
+		{
+			name:          "Can't divide by zero",
+			givenSource:   `age / 0`,
+			expectedError: errors.New("can't evaluate expression 'age / 0': can't divide 42 by zero"),
+		},
+		{
+			name:          "Can't apply logical operator on non bool",
+			givenSource:   `name && admin`,
+			expectedError: errors.New("can't evaluate expression 'name && admin': can't apply '&&' on bob"),
+		},
+		{
+			name:          "Can't apply function on wrong type",
+			givenSource:   `size(age)`,
+			expectedError: errors.New("can't evaluate expression 'size(age)': can't apply size on 42"),
+		},
+		{
+			name:          "Can't convert text to number",
+			givenSource:   `number(name)`,
+			expectedError: errors.New("can't evaluate expression 'number(name)': can't convert to number: bob"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expression, err := ParseExpression(testCase.givenSource)
+			if err != nil {
+				t.Fatalf("parse error - got: %+v, want: %+v", err, nil)
+			}
+			value, err := expression.Eval(data)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(value, testCase.expectedValue) {
+				t.Errorf("value - got: %+v, want: %+v", value, testCase.expectedValue)
+			}
+		})
+	}
+}
+
+func Test_Expression_EvalBool(t *testing.T) {
+	expression, _ := ParseExpression(`name`)
+
+	_, err := expression.EvalBool(map[string]interface{}{"name": "bob"})
+
+	expectedError := errors.New("can't evaluate expression 'name' as bool: bob")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Expression_Keys(t *testing.T) {
+	expression, _ := ParseExpression(`user.age > limit && has(user.banned) && has(flag) && tags[index] == "a"`)
+
+	keys := expression.Keys()
+
+	expectedKeys := []string{"index", "limit", "tags", "user"}
+	if !cmp.Equal(keys, expectedKeys) {
+		t.Errorf("got: %+v, want: %+v", keys, expectedKeys)
+	}
+}
@@ -0,0 +1,55 @@
+package hoff
+
+// ExpressionDecisionNode is a type of Node who decide its branch
+// by evaluating a boolean expression over the context data (see Expression).
+type ExpressionDecisionNode struct {
+	name       string
+	expression *Expression
+}
+
+func (n ExpressionDecisionNode) String() string {
+	return n.name
+}
+
+// Compute evaluate the expression and decide which compute state to return.
+func (n *ExpressionDecisionNode) Compute(c *Context) ComputeState {
+	value, err := n.expression.evalRead(c.Read)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	branch, err := n.expression.asBool(value)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	return NewContinueOnBranchComputeState(branch)
+}
+
+// DecideCapability is actived due to the fact that an expression take a decision.
+func (n *ExpressionDecisionNode) DecideCapability() bool {
+	return true
+}
+
+// Expression give the source of the evaluated expression.
+func (n *ExpressionDecisionNode) Expression() string {
+	return n.expression.String()
+}
+
+// RequiredKeys give the context keys read by the expression.
+func (n *ExpressionDecisionNode) RequiredKeys() []string {
+	return n.expression.Keys()
+}
+
+// ProducedKeys give no context key, as an expression don't write into the context.
+func (n *ExpressionDecisionNode) ProducedKeys() []string {
+	return nil
+}
+
+// NewExpressionDecisionNode create a ExpressionDecisionNode based on a name and a boolean expression
+// over the context data, parsed at creation.
+func NewExpressionDecisionNode(name, expression string) (*ExpressionDecisionNode, error) {
+	parsed, err := ParseExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &ExpressionDecisionNode{name: name, expression: parsed}, nil
+}
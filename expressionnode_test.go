@@ -0,0 +1,109 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewExpressionDecisionNode(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenExpression string
+		expectedError   error
+		expectedKeys    []string
+	}{
+		{
+			name:            "Can create an expression decision node",
+			givenExpression: `amount > limit && has(approved)`,
+			expectedKeys:    []string{"amount", "limit"},
+		},
+		{
+			name:            "Can't create an expression decision node with invalid expression",
+			givenExpression: `amount >`,
+			expectedError:   errors.New("can't parse expression 'amount >': unexpected end of expression"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewExpressionDecisionNode("decision", testCase.givenExpression)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err != nil {
+				return
+			}
+			if !node.DecideCapability() || node.Expression() != testCase.givenExpression {
+				t.Errorf("node - got: %+v %+v, want: %+v %+v", node.DecideCapability(), node.Expression(), true, testCase.givenExpression)
+			}
+			if !cmp.Equal(node.RequiredKeys(), testCase.expectedKeys) || node.ProducedKeys() != nil {
+				t.Errorf("keys - got: %+v %+v, want: %+v %+v", node.RequiredKeys(), node.ProducedKeys(), testCase.expectedKeys, nil)
+			}
+		})
+	}
+}
+
+func Test_ExpressionDecisionNode_Compute(t *testing.T) {
+	node, _ := NewExpressionDecisionNode("decision", `amount > 100 && country in ["FR", "BE"]`)
+
+	testCases := []struct {
+		name          string
+		givenData     map[string]interface{}
+		expectedState ComputeState
+	}{
+		{
+			name:          "Can continue on branch true",
+			givenData:     map[string]interface{}{"amount": 150, "country": "FR"},
+			expectedState: NewContinueOnBranchComputeState(true),
+		},
+		{
+			name:          "Can continue on branch false",
+			givenData:     map[string]interface{}{"amount": 150.0, "country": "US"},
+			expectedState: NewContinueOnBranchComputeState(false),
+		},
+		{
+			name:          "Can't decide with missing key",
+			givenData:     map[string]interface{}{"country": "FR"},
+			expectedState: NewAbortComputeState(errors.New("can't evaluate expression 'amount > 100 && country in [\"FR\", \"BE\"]': can't find key: amount")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			state := node.Compute(NewContext(testCase.givenData))
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", state, testCase.expectedState)
+			}
+		})
+	}
+
+	notBool, _ := NewExpressionDecisionNode("decision", `amount + 1`)
+	state := notBool.Compute(NewContext(map[string]interface{}{"amount": 1}))
+	expectedState := NewAbortComputeState(errors.New("can't evaluate expression 'amount + 1' as bool: 2"))
+	if !cmp.Equal(state, expectedState, errorComparator) {
+		t.Errorf("not bool - got: %+v, want: %+v", state, expectedState)
+	}
+}
+
+func Test_ExpressionDecisionNode_Describe(t *testing.T) {
+	node, _ := NewExpressionDecisionNode("isLarge", `amount > 100`)
+	ns := NewNodeSystem()
+	ns.AddNode(node)
+	ns.AddNode(someActionNode)
+	ns.AddLinkOnBranch(node, someActionNode, true)
+
+	description := ns.Describe()
+	expectedNode := NodeDescription{Name: "isLarge", Decision: true, Expression: "amount > 100"}
+	if !cmp.Equal(description.Nodes[0], expectedNode) {
+		t.Errorf("describe - got: %+v, want: %+v", description.Nodes[0], expectedNode)
+	}
+
+	buffer, _ := description.MarshalProto()
+	var unmarshaled SystemDescription
+	unmarshaled.UnmarshalProto(buffer)
+	if !cmp.Equal(unmarshaled.Nodes[0], expectedNode) {
+		t.Errorf("proto - got: %+v, want: %+v", unmarshaled.Nodes[0], expectedNode)
+	}
+}
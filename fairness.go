@@ -0,0 +1,68 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WorkerPool is a worker pool shared by the engines of several workflows (see Engine.ConfigureSharedWorkerPool),
+// running their nodes on a fixed number of workers.
+// By default the nodes with the highest priority run first, whatever their workflow,
+// and with fairness the workers are shared across the workflows (see ConfigureFairness).
+//
+// The engine of a SubFlowNode can't share the pool of the engine computing the node,
+// as the node hold a worker while waiting for its sub-flow.
+type WorkerPool struct {
+	pool *workerPool
+}
+
+// NewWorkerPool create a WorkerPool with a number of workers.
+func NewWorkerPool(workers int) (*WorkerPool, error) {
+	if workers <= 0 {
+		return nil, fmt.Errorf("can't create worker pool under 1 worker: %v", workers)
+	}
+	return &WorkerPool{pool: newWorkerPool(workers)}, nil
+}
+
+// ConfigureFairness run the nodes by weighted round-robin across the workflows, so a chatty workflow
+// can't monopolize the workers: a workflow with a weight of 2 run twice as many nodes as a workflow
+// with a weight of 1 when both have nodes waiting for a worker.
+// The workflows without weight have a weight of 1, so no weights give a round-robin.
+// The priority of the nodes only apply within a workflow.
+func (p *WorkerPool) ConfigureFairness(weights map[string]int) error {
+	for workflow, weight := range weights {
+		if weight <= 0 {
+			return fmt.Errorf("can't configure weight of workflow '%v' under 1: %v", workflow, weight)
+		}
+	}
+	p.pool.mu.Lock()
+	defer p.pool.mu.Unlock()
+	p.pool.fair = true
+	p.pool.weights = make(map[string]int, len(weights))
+	for workflow, weight := range weights {
+		p.pool.weights[workflow] = weight
+	}
+	return nil
+}
+
+// Close stop the workers once the waiting nodes are run,
+// the nodes submitted after run directly.
+func (p *WorkerPool) Close() {
+	p.pool.close()
+}
+
+// ConfigureSharedWorkerPool run the nodes of all computations on a worker pool shared with other engines,
+// under a workflow name used by the fairness of the pool.
+// The workers are not stopped on engine shutdown (see WorkerPool.Close).
+func (e *Engine) ConfigureSharedWorkerPool(pool *WorkerPool, workflow string) error {
+	if pool == nil {
+		return errors.New("can't configure shared worker pool without pool")
+	}
+	if e.pool != nil {
+		return errors.New("worker pool already configured")
+	}
+	e.pool = pool.pool
+	e.sharedPool = true
+	e.poolWorkflow = workflow
+	return nil
+}
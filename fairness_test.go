@@ -0,0 +1,117 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_WorkerPool_ConfigureFairness(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenWeights  map[string]int
+		expectedOrder []string
+		expectedError error
+	}{
+		{
+			name:          "Can run the workflows by round-robin",
+			expectedOrder: []string{"chatty", "quiet", "chatty", "quiet", "chatty", "chatty"},
+		},
+		{
+			name:          "Can run the workflows by weight",
+			givenWeights:  map[string]int{"chatty": 2},
+			expectedOrder: []string{"chatty", "quiet", "chatty", "chatty", "quiet", "chatty"},
+		},
+		{
+			name:          "Can't configure a weight under 1",
+			givenWeights:  map[string]int{"chatty": 0},
+			expectedError: errors.New("can't configure weight of workflow 'chatty' under 1: 0"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pool, _ := NewWorkerPool(1)
+			defer pool.Close()
+			err := pool.ConfigureFairness(testCase.givenWeights)
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err != nil {
+				return
+			}
+
+			blocked, release := make(chan struct{}), make(chan struct{})
+			go pool.pool.execute(0, func() {
+				close(blocked)
+				<-release
+			})
+			<-blocked
+
+			var mu sync.Mutex
+			order := make([]string, 0)
+			var wg sync.WaitGroup
+			for _, workflow := range []string{"chatty", "chatty", "chatty", "chatty", "quiet", "quiet"} {
+				wg.Add(1)
+				go func(workflow string) {
+					defer wg.Done()
+					pool.pool.executeFor(workflow, 0, func() {
+						mu.Lock()
+						order = append(order, workflow)
+						mu.Unlock()
+					})
+				}(workflow)
+			}
+			for waitingTasks := 0; waitingTasks < 6; {
+				time.Sleep(time.Millisecond)
+				pool.pool.mu.Lock()
+				waitingTasks = pool.pool.pending
+				pool.pool.mu.Unlock()
+			}
+			close(release)
+			wg.Wait()
+
+			if !cmp.Equal(order, testCase.expectedOrder) {
+				t.Errorf("order - got: %+v, want: %+v", order, testCase.expectedOrder)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureSharedWorkerPool(t *testing.T) {
+	pool, _ := NewWorkerPool(2)
+	defer pool.Close()
+	engines := make([]*Engine, 0)
+	for _, workflow := range []string{"billing", "shipping"} {
+		ns := NewNodeSystem()
+		ns.AddNode(someActionNode)
+		ns.ActivateInPlace()
+		eng := NewEngine(SequentialComputation)
+		eng.ConfigureNodeSystem(ns)
+		err := eng.ConfigureSharedWorkerPool(pool, workflow)
+		if err != nil {
+			t.Errorf("error - got: %+v, want: nothing", err)
+		}
+		engines = append(engines, eng)
+	}
+
+	engines[0].Compute(nil)
+	engines[0].Shutdown(context.Background())
+	result := engines[1].Compute(nil)
+
+	if result.Error != nil {
+		t.Errorf("result error - got: %+v, want: nothing", result.Error)
+	}
+	pool.pool.mu.Lock()
+	closed := pool.pool.closed
+	pool.pool.mu.Unlock()
+	if closed {
+		t.Errorf("closed - got: %+v, want: %+v", closed, false)
+	}
+	if err := engines[1].ConfigureSharedWorkerPool(pool, "shipping"); err == nil {
+		t.Errorf("error - got: nothing, want: worker pool already configured")
+	}
+}
@@ -0,0 +1,18 @@
+package hoff
+
+// FlagProvider tell if a feature flag is enabled for a computation.
+type FlagProvider interface {
+	// IsEnabled evaluate the feature flag against the context of a computation.
+	IsEnabled(flag string, c *Context) bool
+}
+
+// DisabledNodeMode define how a node disabled by its feature flag is handled during a computation.
+type DisabledNodeMode string
+
+const (
+	// DisabledNodePassThrough will continue to the following nodes without computing the disabled node.
+	// As a decision can't be taken without computing it, a disabled decision node is skipped.
+	DisabledNodePassThrough DisabledNodeMode = "pass_through"
+	// DisabledNodeSkip will skip the disabled node, and so its following nodes.
+	DisabledNodeSkip = "skip"
+)
@@ -0,0 +1,178 @@
+package hoff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// FileReadNode is a type of Node who read a file into a context key, as a string.
+// The path is a template (see text/template) executed on the context data, like 'orders/{{.order}}.json'.
+type FileReadNode struct {
+	name   string
+	path   *template.Template
+	target string
+}
+
+func (n FileReadNode) String() string {
+	return n.name
+}
+
+// Compute read the file and store its content.
+func (n *FileReadNode) Compute(c *Context) ComputeState {
+	path, err := executeNodeTemplate(n.name, n.path, c)
+	if err != nil {
+		return NewAbortWithCodeComputeState(ValidationAbort, err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't read file of node '%v': %w", n.name, err))
+	}
+	c.Store(n.target, string(content))
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a read don't take a decision.
+func (n *FileReadNode) DecideCapability() bool {
+	return false
+}
+
+// ProducedKeys give the context key of the file content.
+func (n *FileReadNode) ProducedKeys() []string {
+	return []string{n.target}
+}
+
+// NewFileReadNode create a FileReadNode based on a name, the template of the path, and the context key of the content.
+func NewFileReadNode(name, path, target string) (*FileReadNode, error) {
+	if target == "" {
+		return nil, errors.New("can't create file node without key")
+	}
+	parsed, err := parseNodeTemplate("file", "path", path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileReadNode{name: name, path: parsed, target: target}, nil
+}
+
+// FileWriteNode is a type of Node who write a context value into a file, creating its directory if needed.
+// A string or bytes value is written as is, another value is written as indented JSON.
+// The file is written to a temporary file renamed at the end, so a reader never see a partial file.
+// The path is a template (see text/template) executed on the context data, like 'invoices/{{.order}}.txt'.
+type FileWriteNode struct {
+	name   string
+	path   *template.Template
+	source string
+}
+
+func (n FileWriteNode) String() string {
+	return n.name
+}
+
+// Compute write the context value into the file.
+func (n *FileWriteNode) Compute(c *Context) ComputeState {
+	path, err := executeNodeTemplate(n.name, n.path, c)
+	if err != nil {
+		return NewAbortWithCodeComputeState(ValidationAbort, err)
+	}
+	value, found := c.Read(n.source)
+	if !found {
+		return NewAbortWithCodeComputeState(ValidationAbort, fmt.Errorf("can't write file of node '%v' with missing key: %v", n.name, n.source))
+	}
+	content, err := encodeContent(value)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't write file of node '%v': %w", n.name, err))
+	}
+	err = writeFileAtomically(path, content)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't write file of node '%v': %w", n.name, err))
+	}
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a write don't take a decision.
+func (n *FileWriteNode) DecideCapability() bool {
+	return false
+}
+
+// RequiredKeys give the context key of the written value.
+func (n *FileWriteNode) RequiredKeys() []string {
+	return []string{n.source}
+}
+
+// NewFileWriteNode create a FileWriteNode based on a name, the template of the path, and the context key of the value to write.
+func NewFileWriteNode(name, path, source string) (*FileWriteNode, error) {
+	if source == "" {
+		return nil, errors.New("can't create file node without key")
+	}
+	parsed, err := parseNodeTemplate("file", "path", path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileWriteNode{name: name, path: parsed, source: source}, nil
+}
+
+// parseNodeTemplate parse a template part of a node, like a path.
+func parseNodeTemplate(kind, part, source string) (*template.Template, error) {
+	if source == "" {
+		return nil, fmt.Errorf("can't create %v node without %v", kind, part)
+	}
+	parsed, err := template.New(part).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("can't create %v node with invalid %v template: %v", kind, part, err)
+	}
+	return parsed, nil
+}
+
+// executeNodeTemplate execute a template part of a node on the context data.
+func executeNodeTemplate(name string, t *template.Template, c *Context) (string, error) {
+	var buffer bytes.Buffer
+	err := t.Execute(&buffer, c.Data)
+	if err != nil {
+		return "", fmt.Errorf("can't build %v of node '%v': %v", t.Name(), name, err)
+	}
+	if buffer.Len() == 0 {
+		return "", fmt.Errorf("can't build %v of node '%v': empty %v", t.Name(), name, t.Name())
+	}
+	return buffer.String(), nil
+}
+
+// encodeContent give the content of a value, a string or bytes value as is, another value as indented JSON.
+func encodeContent(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+	return json.MarshalIndent(value, "", "  ")
+}
+
+func writeFileAtomically(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(file.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
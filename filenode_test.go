@@ -0,0 +1,153 @@
+package hoff
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewFileReadNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenPath     string
+		givenTarget   string
+		expectedError error
+	}{
+		{
+			name:        "Can create a file read node",
+			givenPath:   "orders/{{.order}}.json",
+			givenTarget: "content",
+		},
+		{
+			name:          "Can't create a file read node without path",
+			givenTarget:   "content",
+			expectedError: errors.New("can't create file node without path"),
+		},
+		{
+			name:          "Can't create a file read node without key",
+			givenPath:     "orders/{{.order}}.json",
+			expectedError: errors.New("can't create file node without key"),
+		},
+		{
+			name:          "Can't create a file read node with invalid path template",
+			givenPath:     "orders/{{.order}.json",
+			givenTarget:   "content",
+			expectedError: errors.New("can't create file node with invalid path template: template: path:1: bad character U+007D '}'"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewFileReadNode("read", testCase.givenPath, testCase.givenTarget)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_FileReadNode_Compute(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "hoff")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "42.json"), []byte(`{"id": 42}`), 0644)
+
+	testCases := []struct {
+		name          string
+		givenData     map[string]interface{}
+		expectedState ComputeState
+		expectedData  map[string]interface{}
+	}{
+		{
+			name:          "Can read a file",
+			givenData:     map[string]interface{}{"order": 42},
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"order": 42, "content": `{"id": 42}`},
+		},
+		{
+			name:          "Can't read a missing file",
+			givenData:     map[string]interface{}{"order": 43},
+			expectedState: NewAbortComputeState(errors.New("can't read file of node 'read': open " + filepath.Join(dir, "43.json") + ": no such file or directory")),
+			expectedData:  map[string]interface{}{"order": 43},
+		},
+		{
+			name:          "Can't read a file without path",
+			givenData:     map[string]interface{}{},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New(`can't build path of node 'read': template: path:1:`+strconv.Itoa(len(dir)+3)+`: executing "path" at <.order>: map has no entry for key "order"`)),
+			expectedData:  map[string]interface{}{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, _ := NewFileReadNode("read", filepath.Join(dir, "{{.order}}.json"), "content")
+			c := NewContext(testCase.givenData)
+
+			state := node.Compute(c)
+
+			if errorMessage(state.Error) != errorMessage(testCase.expectedState.Error) || state.Value != testCase.expectedState.Value || state.Code != testCase.expectedState.Code {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_FileWriteNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenData       map[string]interface{}
+		expectedState   ComputeState
+		expectedContent string
+	}{
+		{
+			name:            "Can write a string",
+			givenData:       map[string]interface{}{"order": 42, "invoice": "total: 10"},
+			expectedState:   NewContinueComputeState(),
+			expectedContent: "total: 10",
+		},
+		{
+			name:            "Can write bytes",
+			givenData:       map[string]interface{}{"order": 42, "invoice": []byte("total: 10")},
+			expectedState:   NewContinueComputeState(),
+			expectedContent: "total: 10",
+		},
+		{
+			name:            "Can write a value as JSON",
+			givenData:       map[string]interface{}{"order": 42, "invoice": map[string]interface{}{"total": 10}},
+			expectedState:   NewContinueComputeState(),
+			expectedContent: "{\n  \"total\": 10\n}",
+		},
+		{
+			name:          "Can't write a missing value",
+			givenData:     map[string]interface{}{"order": 42},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't write file of node 'write' with missing key: invoice")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir, _ := ioutil.TempDir("", "hoff")
+			defer os.RemoveAll(dir)
+			node, _ := NewFileWriteNode("write", filepath.Join(dir, "invoices", "{{.order}}.txt"), "invoice")
+
+			state := node.Compute(NewContext(testCase.givenData))
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			content, _ := ioutil.ReadFile(filepath.Join(dir, "invoices", "42.txt"))
+			if string(content) != testCase.expectedContent {
+				t.Errorf("content - got: %+v, want: %+v", string(content), testCase.expectedContent)
+			}
+			files, _ := ioutil.ReadDir(filepath.Join(dir, "invoices"))
+			if testCase.expectedContent != "" && len(files) != 1 {
+				t.Errorf("files - got: %+v, want: %+v", len(files), 1)
+			}
+		})
+	}
+}
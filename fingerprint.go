@@ -0,0 +1,24 @@
+package hoff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Fingerprint give a stable hash of the node system, based on the nodes (by type and name)
+// with their configuration, and the links, to detect a change of node system between
+// replicas, reports, or checkpoints.
+// The fingerprint of an activated node system is computed once on activation.
+func (s *NodeSystem) Fingerprint() string {
+	if s.activated && s.fingerprint != "" {
+		return s.fingerprint
+	}
+	semantic := newSemanticNodeSystem(s)
+	hash := sha256.New()
+	for _, part := range [][]string{semantic.Nodes, semantic.Links, semantic.PortLinks} {
+		hash.Write([]byte(strings.Join(part, "\n")))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
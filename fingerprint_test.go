@@ -0,0 +1,61 @@
+package hoff
+
+import (
+	"testing"
+)
+
+func Test_NodeSystem_Fingerprint(t *testing.T) {
+	newSystem := func(mode JoinMode) *NodeSystem {
+		readKey, _ := NewActionNode("readKey", func(*Context) error { return nil })
+		writeKey, _ := NewActionNode("writeKey", func(*Context) error { return nil })
+		ns := NewNodeSystem()
+		ns.AddNode(readKey)
+		ns.AddNode(writeKey)
+		ns.AddLink(readKey, writeKey)
+		ns.ConfigureJoinModeOnNode(writeKey, mode)
+		return ns
+	}
+
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenOther    *NodeSystem
+		expectedEqual bool
+	}{
+		{
+			name:          "Same fingerprint with other node instances",
+			givenSystem:   newSystem(JoinNone),
+			givenOther:    newSystem(JoinNone),
+			expectedEqual: true,
+		},
+		{
+			name:        "Different fingerprint with other join mode",
+			givenSystem: newSystem(JoinNone),
+			givenOther:  newSystem(JoinOr),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			equal := testCase.givenSystem.Fingerprint() == testCase.givenOther.Fingerprint()
+
+			if equal != testCase.expectedEqual {
+				t.Errorf("got: %+v, want: %+v", equal, testCase.expectedEqual)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_Fingerprint_activation(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	fingerprint := ns.Fingerprint()
+
+	ns.ActivateInPlace()
+
+	if ns.Fingerprint() != fingerprint {
+		t.Errorf("got: %+v, want: %+v", ns.Fingerprint(), fingerprint)
+	}
+	if len(fingerprint) != 64 {
+		t.Errorf("length - got: %+v, want: %+v", len(fingerprint), 64)
+	}
+}
@@ -0,0 +1,101 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Follower compute the node executions shared on a WorkQueue by a leader engine (see Engine.ConfigureWorkQueue).
+// Several followers, in several processes, can share the same work queue.
+type Follower struct {
+	name              string
+	queue             WorkQueue
+	server            *ComputeServer
+	pollInterval      time.Duration
+	heartbeatInterval time.Duration
+}
+
+// NewFollower create a follower, named to identify its claims,
+// who compute the tasks of a work queue with the nodes of a compute server.
+// The work queue is polled every 100ms and the claims are kept by a heartbeat every second.
+func NewFollower(name string, queue WorkQueue, server *ComputeServer) (*Follower, error) {
+	if name == "" {
+		return nil, errors.New("can't create follower without name")
+	}
+	if queue == nil {
+		return nil, errors.New("can't create follower without work queue")
+	}
+	if server == nil {
+		return nil, errors.New("can't create follower without compute server")
+	}
+	return &Follower{
+		name:              name,
+		queue:             queue,
+		server:            server,
+		pollInterval:      100 * time.Millisecond,
+		heartbeatInterval: time.Second,
+	}, nil
+}
+
+// ConfigureIntervals replace the interval to poll the work queue for a task,
+// and the interval of the heartbeats on a claimed task (to keep under the claim timeout of the work queue).
+func (f *Follower) ConfigureIntervals(poll, heartbeat time.Duration) error {
+	if poll <= 0 || heartbeat <= 0 {
+		return fmt.Errorf("can't configure follower with a non positive interval: %v, %v", poll, heartbeat)
+	}
+	f.pollInterval = poll
+	f.heartbeatInterval = heartbeat
+	return nil
+}
+
+// Run compute the tasks of the work queue until the context is done or the work queue fail.
+// A task whose claim is lost during its computation is ignored, as computed by another follower.
+func (f *Follower) Run(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		task, claimed, err := f.queue.Claim(f.name)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			select {
+			case <-time.After(f.pollInterval):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		err = f.process(ctx, task)
+		if err != nil && !errors.Is(err, ErrClaimLost) {
+			return err
+		}
+	}
+}
+
+func (f *Follower) process(ctx context.Context, task WorkTask) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(f.heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if f.queue.Heartbeat(f.name, task.ID) != nil {
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	response, err := f.server.Compute(ctx, &task.Request)
+	if err != nil {
+		response = &ComputeResponse{State: AbortState, Error: err.Error()}
+	}
+	return f.queue.Complete(f.name, task.ID, *response)
+}
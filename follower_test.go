@@ -0,0 +1,99 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewFollower(t *testing.T) {
+	queue, _ := NewMemoryWorkQueue(time.Second, 1)
+	server, _ := NewComputeServer()
+
+	testCases := []struct {
+		name          string
+		givenName     string
+		givenQueue    WorkQueue
+		givenServer   *ComputeServer
+		expectedError error
+	}{
+		{
+			name:        "Can create a follower",
+			givenName:   "follower",
+			givenQueue:  queue,
+			givenServer: server,
+		},
+		{
+			name:          "Can't create a follower without name",
+			givenQueue:    queue,
+			givenServer:   server,
+			expectedError: errors.New("can't create follower without name"),
+		},
+		{
+			name:          "Can't create a follower without work queue",
+			givenName:     "follower",
+			givenServer:   server,
+			expectedError: errors.New("can't create follower without work queue"),
+		},
+		{
+			name:          "Can't create a follower without compute server",
+			givenName:     "follower",
+			givenQueue:    queue,
+			expectedError: errors.New("can't create follower without compute server"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewFollower(testCase.givenName, testCase.givenQueue, testCase.givenServer)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Follower_ConfigureIntervals(t *testing.T) {
+	queue, _ := NewMemoryWorkQueue(time.Second, 1)
+	server, _ := NewComputeServer()
+	follower, _ := NewFollower("follower", queue, server)
+
+	err := follower.ConfigureIntervals(0, time.Second)
+	expectedError := errors.New("can't configure follower with a non positive interval: 0s, 1s")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Follower_Run(t *testing.T) {
+	slowAction, _ := NewActionNode("slowAction", func(*Context) error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	})
+	server, _ := NewComputeServer(slowAction)
+	queue, _ := NewMemoryWorkQueue(10*time.Millisecond, 1)
+	queue.Enqueue(WorkTask{ID: "slow", Request: ComputeRequest{Node: "slowAction"}})
+	queue.Enqueue(WorkTask{ID: "unknown", Request: ComputeRequest{Node: "unknownNode"}})
+
+	follower, _ := NewFollower("follower", queue, server)
+	follower.ConfigureIntervals(time.Millisecond, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := follower.Run(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("error - got: %+v, want: %+v", err, context.DeadlineExceeded)
+	}
+
+	response, completed, _ := queue.Result("slow")
+	if !completed || response.State != ContinueState {
+		t.Errorf("slow task - got: %+v, %v, want: completed in Continue thanks to heartbeats", response, completed)
+	}
+	response, completed, _ = queue.Result("unknown")
+	expectedResponse := ComputeResponse{State: AbortState, Error: "can't find node 'unknownNode'"}
+	if !completed || !cmp.Equal(response, expectedResponse) {
+		t.Errorf("unknown task - got: %+v, %v, want: %+v", response, completed, expectedResponse)
+	}
+}
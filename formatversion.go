@@ -0,0 +1,30 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// FormatVersion is the version of the format of the serialized node system descriptions and computation reports,
+	// increased on each change of the format who can't be decoded by the previous versions of the library.
+	FormatVersion = 2
+	// MinFormatVersion is the oldest version of the format still decoded, to keep the stored computations and workflows
+	// readable after an upgrade of the library. The version 1 is the format without version.
+	MinFormatVersion = FormatVersion - 1
+)
+
+// ErrUnsupportedFormatVersion is the error of a serialized description or report with a version not decoded
+// by this version of the library, like a version written by a newer library.
+var ErrUnsupportedFormatVersion = errors.New("can't decode unsupported format version")
+
+// checkFormatVersion check a decoded format version is supported, 0 being the version 1 without version field.
+func checkFormatVersion(version int64) error {
+	if version == 0 {
+		version = 1
+	}
+	if version < MinFormatVersion || version > FormatVersion {
+		return fmt.Errorf("%w %v, supported versions: %v to %v", ErrUnsupportedFormatVersion, version, MinFormatVersion, FormatVersion)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_SystemDescription_formatVersion(t *testing.T) {
+	unsupportedError := func(version int) error {
+		return fmt.Errorf("can't unmarshal system description: %w", fmt.Errorf("%w %v, supported versions: 1 to 2", ErrUnsupportedFormatVersion, version))
+	}
+
+	testCases := []struct {
+		name                string
+		givenBuffer         []byte
+		givenJSON           string
+		expectedDescription SystemDescription
+		expectedError       error
+	}{
+		{
+			name: "Can decode the version 1 without version",
+			// nodes { name: "a" }
+			givenBuffer:         []byte{0x0a, 0x03, 0x0a, 0x01, 'a'},
+			givenJSON:           `{"nodes": [{"name": "a"}], "links": []}`,
+			expectedDescription: SystemDescription{Nodes: []NodeDescription{{Name: "a"}}, Links: []LinkDescription{}},
+		},
+		{
+			name: "Can decode the current version",
+			// nodes { name: "a" } format_version: 2
+			givenBuffer:         []byte{0x0a, 0x03, 0x0a, 0x01, 'a', 0x18, 0x02},
+			givenJSON:           `{"format_version": 2, "nodes": [{"name": "a"}], "links": []}`,
+			expectedDescription: SystemDescription{Nodes: []NodeDescription{{Name: "a"}}, Links: []LinkDescription{}},
+		},
+		{
+			name: "Can't decode a newer version",
+			// nodes { name: "a" } format_version: 3
+			givenBuffer:   []byte{0x0a, 0x03, 0x0a, 0x01, 'a', 0x18, 0x03},
+			givenJSON:     `{"format_version": 3, "nodes": [{"name": "a"}], "links": []}`,
+			expectedError: unsupportedError(3),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var fromProto, fromJSON SystemDescription
+			protoErr := fromProto.UnmarshalProto(testCase.givenBuffer)
+			jsonErr := json.Unmarshal([]byte(testCase.givenJSON), &fromJSON)
+
+			for _, err := range []error{protoErr, jsonErr} {
+				if !cmp.Equal(errorMessage(err), errorMessage(testCase.expectedError)) {
+					t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+				}
+				if testCase.expectedError != nil && !errors.Is(err, ErrUnsupportedFormatVersion) {
+					t.Errorf("error - got: %+v, want: %+v", err, ErrUnsupportedFormatVersion)
+				}
+			}
+			if testCase.expectedError == nil && (!cmp.Equal(fromProto, testCase.expectedDescription) || !cmp.Equal(fromJSON, testCase.expectedDescription)) {
+				t.Errorf("description - got: %+v %+v, want: %+v", fromProto, fromJSON, testCase.expectedDescription)
+			}
+		})
+	}
+}
+
+func Test_SystemDescription_MarshalJSON(t *testing.T) {
+	description := SystemDescription{Nodes: []NodeDescription{{Name: "a"}}, Links: []LinkDescription{}}
+
+	encoded, err := json.Marshal(description)
+	if err != nil {
+		t.Fatalf("error - got: %+v, want: %+v", err, nil)
+	}
+	expected := `{"format_version":2,"nodes":[{"name":"a"}],"links":[]}`
+	if string(encoded) != expected {
+		t.Errorf("json - got: %v, want: %v", string(encoded), expected)
+	}
+
+	var decoded SystemDescription
+	json.Unmarshal(encoded, &decoded)
+	if !cmp.Equal(decoded, description) {
+		t.Errorf("decoded - got: %+v, want: %+v", decoded, description)
+	}
+}
+
+func Test_ComputationReport_formatVersion(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenBuffer    []byte
+		expectedReport ComputationReport
+		expectedError  error
+	}{
+		{
+			name: "Can decode the version 1 without version",
+			// id: "a"
+			givenBuffer:    []byte{0x0a, 0x01, 'a'},
+			expectedReport: ComputationReport{ID: "a", Data: map[string][]byte{}, States: []NodeStateReport{}},
+		},
+		{
+			name: "Can decode the current version",
+			// id: "a" format_version: 2
+			givenBuffer:    []byte{0x0a, 0x01, 'a', 0x50, 0x02},
+			expectedReport: ComputationReport{ID: "a", Data: map[string][]byte{}, States: []NodeStateReport{}},
+		},
+		{
+			name: "Can't decode a newer version",
+			// id: "a" format_version: 3
+			givenBuffer:   []byte{0x0a, 0x01, 'a', 0x50, 0x03},
+			expectedError: errors.New("can't unmarshal computation report: can't decode unsupported format version 3, supported versions: 1 to 2"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var report ComputationReport
+			err := report.UnmarshalProto(testCase.givenBuffer)
+
+			if !cmp.Equal(errorMessage(err), errorMessage(testCase.expectedError)) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if testCase.expectedError != nil && !errors.Is(err, ErrUnsupportedFormatVersion) {
+				t.Errorf("error - got: %+v, want: %+v", err, ErrUnsupportedFormatVersion)
+			}
+			if !cmp.Equal(report, testCase.expectedReport) {
+				t.Errorf("report - got: %+v, want: %+v", report, testCase.expectedReport)
+			}
+		})
+	}
+}
+
+func Test_ComputationReport_MarshalProto_formatVersion(t *testing.T) {
+	buffer, _ := ComputationReport{ID: "a"}.MarshalProto()
+
+	expected := []byte{0x0a, 0x01, 'a', 0x50, 0x02}
+	if !cmp.Equal(buffer, expected) {
+		t.Errorf("got: %v, want: %v", buffer, expected)
+	}
+}
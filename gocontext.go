@@ -0,0 +1,44 @@
+package hoff
+
+import (
+	"context"
+	"time"
+)
+
+// GoContext give the Go context of the computation, to read the values set by the caller
+// (like a trace ID, or an authenticated principal) or to follow its cancellation.
+// A computation without Go context, like a resumed one, give an empty context.
+func (c *Context) GoContext() context.Context {
+	if c.goContext == nil {
+		return context.Background()
+	}
+	return c.goContext
+}
+
+// ComputeWithContext run computation against node system with input data, and a Go context
+// accessible to the nodes (see Context.GoContext).
+// The computation is interrupted when the context is done.
+func (e *Engine) ComputeWithContext(ctx context.Context, data map[string]interface{}) ComputationResult {
+	return e.compute(ctx, data, SubmitOptions{}, nil)
+}
+
+// valuesContext keep the values of a context, without its deadline and cancellation.
+type valuesContext struct {
+	parent context.Context
+}
+
+func (c valuesContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c valuesContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c valuesContext) Err() error {
+	return nil
+}
+
+func (c valuesContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,51 @@
+package hoff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type traceKey struct{}
+
+func Test_Engine_ComputeWithContext(t *testing.T) {
+	traceAction, _ := NewActionNode("traceAction", func(c *Context) error {
+		c.Store("trace", c.GoContext().Value(traceKey{}))
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(traceAction)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	result := eng.ComputeWithContext(ctx, make(map[string]interface{}))
+	expectedData := map[string]interface{}{"trace": "trace-1"}
+	if !cmp.Equal(result.Data, expectedData) {
+		t.Errorf("with context - got: %+v, want: %+v", result.Data, expectedData)
+	}
+
+	result = eng.Compute(make(map[string]interface{}))
+	expectedData = map[string]interface{}{"trace": nil}
+	if !cmp.Equal(result.Data, expectedData) {
+		t.Errorf("without context - got: %+v, want: %+v", result.Data, expectedData)
+	}
+}
+
+func Test_valuesContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-1"))
+	cancel()
+	ctx := valuesContext{parent: parent}
+
+	if err := ctx.Err(); err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if done := ctx.Done(); done != nil {
+		t.Errorf("done - got: %+v, want: %+v", done, nil)
+	}
+	if value := ctx.Value(traceKey{}); value != "trace-1" {
+		t.Errorf("value - got: %+v, want: %+v", value, "trace-1")
+	}
+}
@@ -0,0 +1,251 @@
+package hoff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	graphmlNamespace      = "http://graphml.graphdrawing.org/xmlns"
+	graphmlNameKey        = "name"
+	graphmlDecisionKey    = "decision"
+	graphmlJoinModeKey    = "join_mode"
+	graphmlTagsKey        = "tags"
+	graphmlBranchKey      = "branch"
+	graphmlMetadataPrefix = "metadata."
+)
+
+type graphmlDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	ID     string        `xml:"id,attr,omitempty"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExportGraphML write the node system as a GraphML graph.
+// A node carry its name, its decision capability, its join mode and its tags as data,
+// and an edge carry its branch and its metadata (as 'metadata.<key>' attributes).
+func (s *NodeSystem) ExportGraphML(w io.Writer) error {
+	metadataKeys := make(map[string]bool)
+	for _, link := range s.links {
+		for key := range link.Metadata {
+			metadataKeys[key] = true
+		}
+	}
+	sortedMetadataKeys := make([]string, 0, len(metadataKeys))
+	for key := range metadataKeys {
+		sortedMetadataKeys = append(sortedMetadataKeys, key)
+	}
+	sort.Strings(sortedMetadataKeys)
+
+	document := graphmlDocument{
+		Xmlns: graphmlNamespace,
+		Keys: []graphmlKey{
+			{ID: graphmlNameKey, For: "node", AttrName: graphmlNameKey, AttrType: "string"},
+			{ID: graphmlDecisionKey, For: "node", AttrName: graphmlDecisionKey, AttrType: "boolean"},
+			{ID: graphmlJoinModeKey, For: "node", AttrName: graphmlJoinModeKey, AttrType: "string"},
+			{ID: graphmlTagsKey, For: "node", AttrName: graphmlTagsKey, AttrType: "string"},
+			{ID: graphmlBranchKey, For: "edge", AttrName: graphmlBranchKey, AttrType: "boolean"},
+		},
+		Graph: graphmlGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphmlNode, 0, len(s.nodes)),
+			Edges:       make([]graphmlEdge, 0, len(s.links)),
+		},
+	}
+	for _, key := range sortedMetadataKeys {
+		document.Keys = append(document.Keys, graphmlKey{ID: graphmlMetadataPrefix + key, For: "edge", AttrName: graphmlMetadataPrefix + key, AttrType: "string"})
+	}
+
+	description := s.Describe()
+	nodeIDs := make(map[string]string, len(description.Nodes))
+	for index, node := range description.Nodes {
+		id := fmt.Sprintf("n%d", index)
+		nodeIDs[node.Name] = id
+		graphNode := graphmlNode{
+			ID: id,
+			Data: []graphmlData{
+				{Key: graphmlNameKey, Value: node.Name},
+				{Key: graphmlDecisionKey, Value: strconv.FormatBool(node.Decision)},
+			},
+		}
+		if node.JoinMode != "" {
+			graphNode.Data = append(graphNode.Data, graphmlData{Key: graphmlJoinModeKey, Value: string(node.JoinMode)})
+		}
+		if len(node.Tags) > 0 {
+			graphNode.Data = append(graphNode.Data, graphmlData{Key: graphmlTagsKey, Value: strings.Join(node.Tags, ",")})
+		}
+		document.Graph.Nodes = append(document.Graph.Nodes, graphNode)
+	}
+	for index, link := range description.Links {
+		edge := graphmlEdge{
+			ID:     fmt.Sprintf("e%d", index),
+			Source: nodeIDs[link.From],
+			Target: nodeIDs[link.To],
+		}
+		if link.Branch != nil {
+			edge.Data = append(edge.Data, graphmlData{Key: graphmlBranchKey, Value: strconv.FormatBool(*link.Branch)})
+		}
+		for _, key := range sortedMetadataKeys {
+			if value, found := link.Metadata[key]; found {
+				edge.Data = append(edge.Data, graphmlData{Key: graphmlMetadataPrefix + key, Value: value})
+			}
+		}
+		document.Graph.Edges = append(document.Graph.Edges, edge)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// ImportGraphML create a node system from a GraphML graph, like one exported by ExportGraphML
+// and edited with a graph editor (yEd, Gephi, ...).
+// The graph nodes are resolved against the given nodes by name (the 'name' data, or the node id if missing),
+// the join modes, tags, branches and metadata are read from the data, and other data (layout, ...) are ignored.
+func ImportGraphML(r io.Reader, nodes ...Node) (*NodeSystem, error) {
+	var document graphmlDocument
+	err := xml.NewDecoder(r).Decode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("can't read graphml: %v", err)
+	}
+
+	keyNames := make(map[string]string, len(document.Keys))
+	for _, key := range document.Keys {
+		keyNames[key.ID] = key.AttrName
+	}
+	namedNodes := make(map[string]Node, len(nodes))
+	for _, node := range nodes {
+		namedNodes[fmt.Sprint(node)] = node
+	}
+
+	system := NewNodeSystem()
+	graphNodes := make(map[string]Node, len(document.Graph.Nodes))
+	for _, graphNode := range document.Graph.Nodes {
+		data := graphmlDataByName(graphNode.Data, keyNames)
+		name, found := data[graphmlNameKey]
+		if !found {
+			name = graphNode.ID
+		}
+		node, found := namedNodes[name]
+		if !found {
+			return nil, fmt.Errorf("can't import graphml node '%v' without matching node", name)
+		}
+		graphNodes[graphNode.ID] = node
+
+		_, err = system.AddNode(node)
+		if err != nil {
+			return nil, err
+		}
+		if mode, found := data[graphmlJoinModeKey]; found && mode != "" {
+			_, err = system.ConfigureJoinModeOnNode(node, JoinMode(mode))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if tags, found := data[graphmlTagsKey]; found && tags != "" {
+			_, err = system.ConfigureTagsOnNode(node, strings.Split(tags, ",")...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, edge := range document.Graph.Edges {
+		from, foundFrom := graphNodes[edge.Source]
+		to, foundTo := graphNodes[edge.Target]
+		if !foundFrom || !foundTo {
+			return nil, fmt.Errorf("can't import graphml edge from '%v' to '%v' with missing node", edge.Source, edge.Target)
+		}
+
+		data := graphmlDataByName(edge.Data, keyNames)
+		var branch *bool
+		if value, found := data[graphmlBranchKey]; found {
+			parsedBranch, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("can't import graphml edge from '%v' to '%v' with invalid branch: %v", edge.Source, edge.Target, value)
+			}
+			branch = &parsedBranch
+		}
+		if branch == nil {
+			_, err = system.AddLink(from, to)
+		} else {
+			_, err = system.AddLinkOnBranch(from, to, *branch)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		metadata := make(map[string]string)
+		for name, value := range data {
+			if strings.HasPrefix(name, graphmlMetadataPrefix) {
+				metadata[strings.TrimPrefix(name, graphmlMetadataPrefix)] = value
+			}
+		}
+		if len(metadata) > 0 {
+			_, err = system.ConfigureMetadataOnLink(from, to, branch, metadata)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return system, nil
+}
+
+// graphmlDataByName give the data values by their attribute name (or key id if not declared).
+func graphmlDataByName(data []graphmlData, keyNames map[string]string) map[string]string {
+	values := make(map[string]string, len(data))
+	for _, value := range data {
+		name, found := keyNames[value.Key]
+		if !found || name == "" {
+			name = value.Key
+		}
+		values[name] = strings.TrimSpace(value.Value)
+	}
+	return values
+}
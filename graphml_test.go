@@ -0,0 +1,178 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ExportGraphML(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("key is present", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	readKey, _ := NewActionNode(`read "key"`, func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(readKey)
+	ns.ConfigureTagsOnNode(readKey, "io", "slow")
+	ns.AddLinkOnBranch(keyIsPresent, readKey, true)
+	ns.ConfigureMetadataOnLink(keyIsPresent, readKey, boolPointer(true), map[string]string{"sla": "1s", "owner": "team"})
+
+	var buffer bytes.Buffer
+	err := ns.ExportGraphML(&buffer)
+
+	expectedGraphML := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="name" for="node" attr.name="name" attr.type="string"></key>
+  <key id="decision" for="node" attr.name="decision" attr.type="boolean"></key>
+  <key id="join_mode" for="node" attr.name="join_mode" attr.type="string"></key>
+  <key id="tags" for="node" attr.name="tags" attr.type="string"></key>
+  <key id="branch" for="edge" attr.name="branch" attr.type="boolean"></key>
+  <key id="metadata.owner" for="edge" attr.name="metadata.owner" attr.type="string"></key>
+  <key id="metadata.sla" for="edge" attr.name="metadata.sla" attr.type="string"></key>
+  <graph id="G" edgedefault="directed">
+    <node id="n0">
+      <data key="name">key is present</data>
+      <data key="decision">true</data>
+    </node>
+    <node id="n1">
+      <data key="name">read &#34;key&#34;</data>
+      <data key="decision">false</data>
+      <data key="tags">io,slow</data>
+    </node>
+    <edge id="e0" source="n0" target="n1">
+      <data key="branch">true</data>
+      <data key="metadata.owner">team</data>
+      <data key="metadata.sla">1s</data>
+    </edge>
+  </graph>
+</graphml>
+`
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(buffer.String(), expectedGraphML) {
+		t.Errorf("got: %+v, want: %+v", buffer.String(), expectedGraphML)
+	}
+
+	imported, err := ImportGraphML(&buffer, keyIsPresent, readKey)
+	if err != nil {
+		t.Errorf("import error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(imported.Describe(), ns.Describe()) {
+		t.Errorf("import - got: %+v, want: %+v", imported.Describe(), ns.Describe())
+	}
+}
+
+func Test_ImportGraphML(t *testing.T) {
+	testCases := []struct {
+		name                string
+		givenGraphML        string
+		expectedDescription SystemDescription
+		expectedError       error
+	}{
+		{
+			name: "Can import a graph edited with another tool",
+			givenGraphML: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:y="http://www.yworks.com/xml/graphml">
+  <key id="d0" for="node" attr.name="name" attr.type="string"/>
+  <key id="d1" for="node" yfiles.type="nodegraphics"/>
+  <key id="d2" for="edge" attr.name="metadata.owner" attr.type="string"/>
+  <key id="d3" for="node" attr.name="join_mode" attr.type="string"/>
+  <graph id="G" edgedefault="directed">
+    <node id="n0">
+      <data key="d0">someActionNode</data>
+      <data key="d1"><y:ShapeNode><y:Geometry x="10" y="20"/></y:ShapeNode></data>
+    </node>
+    <node id="n1">
+      <data key="d0">anotherActionNode</data>
+      <data key="d3">and</data>
+    </node>
+    <edge id="e0" source="n0" target="n1">
+      <data key="d2">team</data>
+    </edge>
+  </graph>
+</graphml>`,
+			expectedDescription: SystemDescription{
+				Nodes: []NodeDescription{
+					{Name: "someActionNode"},
+					{Name: "anotherActionNode", JoinMode: JoinAnd},
+				},
+				Links: []LinkDescription{
+					{From: "someActionNode", To: "anotherActionNode", Metadata: map[string]string{"owner": "team"}},
+				},
+			},
+		},
+		{
+			name: "Can import a graph with node names as node ids",
+			givenGraphML: `<graphml>
+  <graph edgedefault="directed">
+    <node id="someActionNode"/>
+    <node id="anotherActionNode"/>
+    <edge source="someActionNode" target="anotherActionNode"/>
+  </graph>
+</graphml>`,
+			expectedDescription: SystemDescription{
+				Nodes: []NodeDescription{
+					{Name: "someActionNode"},
+					{Name: "anotherActionNode"},
+				},
+				Links: []LinkDescription{
+					{From: "someActionNode", To: "anotherActionNode"},
+				},
+			},
+		},
+		{
+			name: "Can't import a graph with unknown node",
+			givenGraphML: `<graphml>
+  <graph edgedefault="directed">
+    <node id="unknown"/>
+  </graph>
+</graphml>`,
+			expectedError: errors.New("can't import graphml node 'unknown' without matching node"),
+		},
+		{
+			name: "Can't import a graph with an edge to a missing node",
+			givenGraphML: `<graphml>
+  <graph edgedefault="directed">
+    <node id="someActionNode"/>
+    <edge source="someActionNode" target="n9"/>
+  </graph>
+</graphml>`,
+			expectedError: errors.New("can't import graphml edge from 'someActionNode' to 'n9' with missing node"),
+		},
+		{
+			name: "Can't import a graph with invalid branch",
+			givenGraphML: `<graphml>
+  <key id="branch" for="edge" attr.name="branch" attr.type="boolean"/>
+  <graph edgedefault="directed">
+    <node id="someActionNode"/>
+    <node id="anotherActionNode"/>
+    <edge source="someActionNode" target="anotherActionNode"><data key="branch">maybe</data></edge>
+  </graph>
+</graphml>`,
+			expectedError: errors.New("can't import graphml edge from 'someActionNode' to 'anotherActionNode' with invalid branch: maybe"),
+		},
+		{
+			name:          "Can't import an invalid document",
+			givenGraphML:  `<graphml>`,
+			expectedError: errors.New("can't read graphml: XML syntax error on line 1: unexpected EOF"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			system, err := ImportGraphML(strings.NewReader(testCase.givenGraphML), someActionNode, anotherActionNode)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && !cmp.Equal(system.Describe(), testCase.expectedDescription) {
+				t.Errorf("got: %+v, want: %+v", system.Describe(), testCase.expectedDescription)
+			}
+		})
+	}
+}
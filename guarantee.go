@@ -0,0 +1,69 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExecutionGuarantee define when the checkpoint of a computation is saved relative to the side effects of its nodes.
+type ExecutionGuarantee string
+
+const (
+	// CheckpointOnEnd save the checkpoint only when the computation is ended, paused, or interrupted,
+	// all the nodes computed since the last checkpoint are computed again after a restart.
+	CheckpointOnEnd ExecutionGuarantee = ""
+	// AtLeastOnce also save the checkpoint after each node, a node is computed again after a restart
+	// only when the engine stopped between its side effects and its checkpoint.
+	// A failure to save the checkpoint of a node is ignored.
+	AtLeastOnce = "at_least_once"
+	// ExactlyOnce also save the checkpoint before each node, to compute it only on the engine owning the computation,
+	// and after each node, with the node aborted when one of the checkpoints can't be saved.
+	// A node stopped during its computation is computed again with the same idempotency key (see Context.IdempotencyKey),
+	// so its side effects are done once when deduplicated by this key.
+	ExactlyOnce = "exactly_once"
+)
+
+// ConfigureExecutionGuarantee choose when to save the checkpoint of the computations,
+// the checkpoint store need to be configured before.
+func (e *Engine) ConfigureExecutionGuarantee(guarantee ExecutionGuarantee) error {
+	if guarantee != CheckpointOnEnd && guarantee != AtLeastOnce && guarantee != ExactlyOnce {
+		return fmt.Errorf("can't configure unknown execution guarantee: %v", guarantee)
+	}
+	if guarantee != CheckpointOnEnd && e.checkpointStore == nil {
+		return errors.New("can't configure execution guarantee without checkpoint store")
+	}
+	e.guarantee = guarantee
+	return nil
+}
+
+// IdempotencyKey give a key of the computation of a node in the computation of the context,
+// the same when the node is computed again after a restart, to deduplicate its side effects.
+func (c *Context) IdempotencyKey(node Node) string {
+	return fmt.Sprintf("%v:%v", c.computationID, node)
+}
+
+// checkpointNode save the checkpoint of the computation around the computation of a node.
+func (e *Engine) checkpointNode(cp *Computation) nodeInterceptor {
+	return func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+		if e.guarantee == ExactlyOnce {
+			err := e.saveCheckpoint(cp, newComputationResult(cp, nil), false)
+			if err != nil {
+				return NewAbortComputeState(fmt.Errorf("can't checkpoint computation before node '%v': %w", node, err))
+			}
+		}
+
+		state := compute(c)
+
+		result := newComputationResult(cp, nil)
+		result.Report = make(map[Node]ComputeState, len(cp.Report)+1)
+		for reportedNode, reportedState := range cp.Report {
+			result.Report[reportedNode] = reportedState
+		}
+		result.Report[cp.System.nodeMapKey(node)] = state
+		err := e.saveCheckpoint(cp, result, false)
+		if err != nil && e.guarantee == ExactlyOnce {
+			return NewAbortComputeState(fmt.Errorf("can't checkpoint computation after node '%v': %w", node, err))
+		}
+		return state
+	}
+}
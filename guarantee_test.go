@@ -0,0 +1,143 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// countingCheckpointStore count the saved checkpoints, with their number of states.
+type countingCheckpointStore struct {
+	*MemoryCheckpointStore
+	savedStates []int
+}
+
+func (s *countingCheckpointStore) Save(checkpoint Checkpoint) (Checkpoint, error) {
+	s.savedStates = append(s.savedStates, len(checkpoint.Report.States))
+	return s.MemoryCheckpointStore.Save(checkpoint)
+}
+
+func Test_Engine_ConfigureExecutionGuarantee(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenStore    CheckpointStore
+		givenValue    ExecutionGuarantee
+		expectedError error
+	}{
+		{
+			name:       "Can checkpoint on end without checkpoint store",
+			givenValue: CheckpointOnEnd,
+		},
+		{
+			name:       "Can checkpoint exactly once",
+			givenStore: NewMemoryCheckpointStore(),
+			givenValue: ExactlyOnce,
+		},
+		{
+			name:          "Can't checkpoint at least once without checkpoint store",
+			givenValue:    AtLeastOnce,
+			expectedError: errors.New("can't configure execution guarantee without checkpoint store"),
+		},
+		{
+			name:          "Can't checkpoint with unknown execution guarantee",
+			givenStore:    NewMemoryCheckpointStore(),
+			givenValue:    "at_most_once",
+			expectedError: errors.New("can't configure unknown execution guarantee: at_most_once"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			if testCase.givenStore != nil {
+				eng.ConfigureCheckpointStore(testCase.givenStore)
+			}
+			err := eng.ConfigureExecutionGuarantee(testCase.givenValue)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_withExecutionGuarantee(t *testing.T) {
+	testCases := []struct {
+		name                string
+		givenGuarantee      ExecutionGuarantee
+		expectedSavedStates []int
+	}{
+		{
+			name:                "Can checkpoint on end",
+			givenGuarantee:      CheckpointOnEnd,
+			expectedSavedStates: []int{2},
+		},
+		{
+			name:                "Can checkpoint after each node",
+			givenGuarantee:      AtLeastOnce,
+			expectedSavedStates: []int{1, 2, 2},
+		},
+		{
+			name:                "Can checkpoint before and after each node",
+			givenGuarantee:      ExactlyOnce,
+			expectedSavedStates: []int{0, 1, 1, 2, 2},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(someActionNode)
+			ns.AddNode(anotherActionNode)
+			ns.AddLink(someActionNode, anotherActionNode)
+			ns.ActivateInPlace()
+
+			store := &countingCheckpointStore{MemoryCheckpointStore: NewMemoryCheckpointStore()}
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigureCheckpointStore(store)
+			eng.ConfigureExecutionGuarantee(testCase.givenGuarantee)
+
+			result := eng.Compute(map[string]interface{}{})
+			if result.Error != nil {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, nil)
+			}
+			if !cmp.Equal(store.savedStates, testCase.expectedSavedStates) {
+				t.Errorf("saved states - got: %+v, want: %+v", store.savedStates, testCase.expectedSavedStates)
+			}
+			checkpoint, _ := store.Load(result.ID)
+			if !checkpoint.Completed {
+				t.Errorf("completed - got: %+v, want: %+v", checkpoint.Completed, true)
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_withExactlyOnceConflict(t *testing.T) {
+	store := NewMemoryCheckpointStore()
+	var key string
+	var takeOverAction *ActionNode
+	takeOverAction, _ = NewActionNode("takeOverAction", func(c *Context) error {
+		key = c.IdempotencyKey(takeOverAction)
+		checkpoint, _ := store.Load(c.computationID)
+		store.Save(checkpoint)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(takeOverAction)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureCheckpointStore(store)
+	eng.ConfigureExecutionGuarantee(ExactlyOnce)
+
+	result := eng.Compute(map[string]interface{}{})
+	expectedError := fmt.Errorf("can't checkpoint computation after node 'takeOverAction': %w", ErrCheckpointConflict)
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, expectedError)
+	}
+	if key != result.ID+":takeOverAction" {
+		t.Errorf("idempotency key - got: %+v, want: %+v", key, result.ID+":takeOverAction")
+	}
+}
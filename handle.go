@@ -0,0 +1,151 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// HandleState is the state of a computation submitted to an engine.
+type HandleState string
+
+const (
+	// HandlePending is the state of a computation waiting to start
+	HandlePending HandleState = "Pending"
+	// HandleRunning is the state of a computation running
+	HandleRunning HandleState = "Running"
+	// HandlePaused is the state of a computation ended with paused nodes
+	HandlePaused HandleState = "Paused"
+	// HandleSucceeded is the state of a computation ended without error
+	HandleSucceeded HandleState = "Succeeded"
+	// HandleFailed is the state of a computation ended with an error
+	HandleFailed HandleState = "Failed"
+	// HandleCanceled is the state of a computation interrupted by Cancel
+	HandleCanceled HandleState = "Canceled"
+)
+
+// Handle follow a computation submitted to an engine.
+type Handle struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	computation *Computation
+	state       HandleState
+	canceled    bool
+	report      map[Node]ComputeState
+	result      ComputationResult
+}
+
+func newHandle(cancel context.CancelFunc) *Handle {
+	return &Handle{
+		done:   make(chan struct{}),
+		cancel: cancel,
+		state:  HandlePending,
+		report: make(map[Node]ComputeState),
+	}
+}
+
+// Done give a channel closed once the computation is ended.
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait wait for the end of the computation and give its result,
+// or the context error when the context is done before.
+func (h *Handle) Wait(ctx context.Context) (ComputationResult, error) {
+	select {
+	case <-h.done:
+		return h.result, nil
+	case <-ctx.Done():
+		return ComputationResult{}, ctx.Err()
+	}
+}
+
+// Cancel interrupt the computation once its running nodes end,
+// a pending computation is not started.
+func (h *Handle) Cancel() {
+	h.mu.Lock()
+	h.canceled = true
+	if h.computation != nil {
+		h.computation.cancel(CanceledByUser)
+	}
+	h.mu.Unlock()
+	h.cancel()
+}
+
+// ID give the identifier of the computation, empty while pending.
+func (h *Handle) ID() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.computation == nil {
+		return ""
+	}
+	return h.computation.ID
+}
+
+// State give the current state of the computation.
+func (h *Handle) State() HandleState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+// Report give the compute state of the nodes already computed.
+func (h *Handle) Report() map[Node]ComputeState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	report := make(map[Node]ComputeState, len(h.report))
+	for node, state := range h.report {
+		report[node] = state
+	}
+	return report
+}
+
+func (h *Handle) start(cp *Computation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.computation = cp
+	h.state = HandleRunning
+	if h.canceled {
+		cp.cancel(CanceledByUser)
+	}
+}
+
+// isCanceled tell if the computation was canceled through the handle.
+func (h *Handle) isCanceled() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.canceled
+}
+
+func (h *Handle) recordState(node Node, state ComputeState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.report[h.computation.System.nodeMapKey(node)] = state
+}
+
+func (h *Handle) complete(result ComputationResult) {
+	h.mu.Lock()
+	if h.canceled && errors.Is(result.Error, ErrComputationInterrupted) {
+		h.state = HandleCanceled
+	} else {
+		h.state = resultHandleState(result)
+	}
+	h.result = result
+	h.mu.Unlock()
+	h.cancel()
+	close(h.done)
+}
+
+// resultHandleState give the state of an ended computation.
+func resultHandleState(result ComputationResult) HandleState {
+	switch {
+	case result.Error != nil || result.IsAborted():
+		return HandleFailed
+	case len(result.PausedTokens()) > 0:
+		return HandlePaused
+	default:
+		return HandleSucceeded
+	}
+}
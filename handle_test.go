@@ -0,0 +1,148 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Handle_Wait(t *testing.T) {
+	handle := newHandle(func() {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := handle.Wait(ctx)
+	if err != context.Canceled {
+		t.Errorf("error before end - got: %+v, want: %+v", err, context.Canceled)
+	}
+
+	expectedResult := ComputationResult{ID: "id", Error: errors.New("error")}
+	handle.complete(expectedResult)
+
+	result, err := handle.Wait(context.Background())
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(result, expectedResult, errorComparator) {
+		t.Errorf("result - got: %+v, want: %+v", result, expectedResult)
+	}
+}
+
+func Test_Handle_State(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenCanceled bool
+		givenResult   *ComputationResult
+		expectedState HandleState
+	}{
+		{
+			name:          "Pending computation",
+			expectedState: HandlePending,
+		},
+		{
+			name: "Succeeded computation",
+			givenResult: &ComputationResult{
+				Report: map[Node]ComputeState{someActionNode: NewContinueComputeState()},
+			},
+			expectedState: HandleSucceeded,
+		},
+		{
+			name: "Failed computation",
+			givenResult: &ComputationResult{
+				Error:  errors.New("error"),
+				Report: map[Node]ComputeState{someActionNode: NewAbortComputeState(errors.New("error"))},
+			},
+			expectedState: HandleFailed,
+		},
+		{
+			name: "Paused computation",
+			givenResult: &ComputationResult{
+				Report: map[Node]ComputeState{someActionNode: NewPauseComputeState("token")},
+			},
+			expectedState: HandlePaused,
+		},
+		{
+			name:          "Canceled computation",
+			givenCanceled: true,
+			givenResult: &ComputationResult{
+				Error: ErrComputationInterrupted,
+			},
+			expectedState: HandleCanceled,
+		},
+		{
+			name: "Interrupted computation without cancel",
+			givenResult: &ComputationResult{
+				Error: ErrComputationInterrupted,
+			},
+			expectedState: HandleFailed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handle := newHandle(func() {})
+			if testCase.givenCanceled {
+				handle.Cancel()
+			}
+			if testCase.givenResult != nil {
+				handle.complete(*testCase.givenResult)
+			}
+
+			state := handle.State()
+			if state != testCase.expectedState {
+				t.Errorf("got: %+v, want: %+v", state, testCase.expectedState)
+			}
+		})
+	}
+}
+
+func Test_Engine_Submit_Handle(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	firstAction, _ := NewActionNode("firstAction", func(c *Context) error { return nil })
+	blockingAction, _ := NewActionNode("blockingAction", func(c *Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	lastAction, _ := NewActionNode("lastAction", func(c *Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(firstAction)
+	ns.AddNode(blockingAction)
+	ns.AddNode(lastAction)
+	ns.AddLink(firstAction, blockingAction)
+	ns.AddLink(blockingAction, lastAction)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	handle := eng.Submit(make(map[string]interface{}), SubmitOptions{})
+	<-started
+
+	if handle.State() != HandleRunning {
+		t.Errorf("running state - got: %+v, want: %+v", handle.State(), HandleRunning)
+	}
+	expectedReport := map[Node]ComputeState{firstAction: NewContinueComputeState()}
+	if !cmp.Equal(handle.Report(), expectedReport, NodeComparator) {
+		t.Errorf("running report - got: %+v, want: %+v", handle.Report(), expectedReport)
+	}
+
+	handle.Cancel()
+	close(release)
+	result, _ := handle.Wait(context.Background())
+
+	if handle.ID() != result.ID || result.ID == "" {
+		t.Errorf("id - got: %+v, want: %+v", handle.ID(), result.ID)
+	}
+	if result.Error != ErrComputationInterrupted {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, ErrComputationInterrupted)
+	}
+	if handle.State() != HandleCanceled {
+		t.Errorf("ended state - got: %+v, want: %+v", handle.State(), HandleCanceled)
+	}
+	expectedReport = map[Node]ComputeState{firstAction: NewContinueComputeState(), blockingAction: NewContinueComputeState()}
+	if !cmp.Equal(handle.Report(), expectedReport, NodeComparator) {
+		t.Errorf("ended report - got: %+v, want: %+v", handle.Report(), expectedReport)
+	}
+}
@@ -0,0 +1,68 @@
+package hoff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// HealthChecker is a Node who can tell if it's ready to be computed, like a node pinging the service it call.
+type HealthChecker interface {
+	Node
+	// CheckHealth return an error when the node is not ready.
+	CheckHealth(ctx context.Context) error
+}
+
+// Health hold the readiness of an engine and of its nodes implementing HealthChecker.
+type Health struct {
+	Ready bool `json:"ready"`
+	// Error tell why the engine is not ready, if so
+	Error string       `json:"error,omitempty"`
+	Nodes []NodeHealth `json:"nodes,omitempty"`
+}
+
+// NodeHealth hold the readiness of a node.
+type NodeHealth struct {
+	Node  string `json:"node"`
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+// Health check if the engine is ready to compute: configured with a node system, not shut down,
+// with its nodes initialized (see Init), and with its health checker nodes ready.
+func (e *Engine) Health(ctx context.Context) Health {
+	if e.system == nil {
+		return Health{Error: "need a configured node system"}
+	}
+	e.mu.Lock()
+	shutdown := e.shutdown
+	e.mu.Unlock()
+	if shutdown {
+		return Health{Error: "engine is shut down"}
+	}
+	if err := e.Init(ctx); err != nil {
+		return Health{Error: err.Error()}
+	}
+
+	health := Health{Ready: true, Nodes: make([]NodeHealth, 0)}
+	for _, node := range e.system.nodes {
+		checker, ok := node.(HealthChecker)
+		if !ok {
+			continue
+		}
+		nodeHealth := NodeHealth{Node: fmt.Sprint(node), Ready: true}
+		if err := checker.CheckHealth(ctx); err != nil {
+			nodeHealth.Ready = false
+			nodeHealth.Error = err.Error()
+			health.Ready = false
+		}
+		health.Nodes = append(health.Nodes, nodeHealth)
+	}
+	sort.Slice(health.Nodes, func(i, j int) bool {
+		return health.Nodes[i].Node < health.Nodes[j].Node
+	})
+	if !health.Ready {
+		health.Error = "can't be ready with nodes not ready"
+	}
+	return health
+}
@@ -0,0 +1,97 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// pingNode is a node checking the health of the service it call.
+type pingNode struct {
+	lifecycleNode
+	pingErr error
+}
+
+func (n *pingNode) CheckHealth(context.Context) error {
+	return n.pingErr
+}
+
+func Test_Engine_Health(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenPingErr   error
+		givenInitErr   error
+		givenShutdown  bool
+		expectedHealth Health
+	}{
+		{
+			name:           "Can be ready",
+			expectedHealth: Health{Ready: true, Nodes: []NodeHealth{{Node: "ping", Ready: true}}},
+		},
+		{
+			name:         "Can't be ready with a node not ready",
+			givenPingErr: errors.New("connection refused"),
+			expectedHealth: Health{
+				Error: "can't be ready with nodes not ready",
+				Nodes: []NodeHealth{{Node: "ping", Error: "connection refused"}},
+			},
+		},
+		{
+			name:           "Can't be ready with a node failing to initialize",
+			givenInitErr:   errors.New("unreachable"),
+			expectedHealth: Health{Error: "can't initialize node 'ping': unreachable"},
+		},
+		{
+			name:           "Can't be ready once shut down",
+			givenShutdown:  true,
+			expectedHealth: Health{Error: "engine is shut down"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ping := &pingNode{lifecycleNode: lifecycleNode{name: "ping", initErr: testCase.givenInitErr}, pingErr: testCase.givenPingErr}
+			ns := NewNodeSystem()
+			ns.AddNode(ping)
+			ns.AddNode(someActionNode)
+			ns.ActivateInPlace()
+
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			if testCase.givenShutdown {
+				eng.Shutdown(context.Background())
+			}
+
+			health := eng.Health(context.Background())
+			if !cmp.Equal(health, testCase.expectedHealth) {
+				t.Errorf("got: %+v, want: %+v", health, testCase.expectedHealth)
+			}
+		})
+	}
+}
+
+func Test_APIHandler_ServeHTTP_health(t *testing.T) {
+	ping := &pingNode{lifecycleNode: lifecycleNode{name: "ping"}, pingErr: errors.New("connection refused")}
+	ns := NewNodeSystem()
+	ns.AddNode(ping)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	api, _ := NewAPIHandler(eng)
+	api.ConfigureAuthenticator(authenticatorFunc(func(*http.Request) error {
+		return errors.New("unauthorized")
+	}))
+
+	status, response := serveAPI(api, http.MethodGet, "/health", "")
+	if status != http.StatusServiceUnavailable || response["ready"] != false {
+		t.Errorf("not ready - got: %+v %+v, want: %+v", status, response, http.StatusServiceUnavailable)
+	}
+	ping.pingErr = nil
+	status, response = serveAPI(api, http.MethodGet, "/health", "")
+	if status != http.StatusOK || response["ready"] != true {
+		t.Errorf("ready - got: %+v %+v, want: %+v", status, response, http.StatusOK)
+	}
+}
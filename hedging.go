@@ -0,0 +1,53 @@
+package hoff
+
+import (
+	"context"
+	"time"
+)
+
+// hedgeNode compute a node with a hedging delay (see TagPolicy) on a copy of the context,
+// and compute it again on another copy if still running after the delay.
+// The first attempt to end is kept, the other one is cancelled through the Go context and its changes dropped.
+// The hedged attempts are computed after the other interceptors, so a retried node is hedged at each retry,
+// and each attempt go through the next ones (atomic writes, panic recovery) on its copy of the context.
+func (e *Engine) hedgeNode(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	delay := e.hedgeDelay(node)
+	if delay == 0 {
+		return compute(c)
+	}
+	ctx, cancel := context.WithCancel(c.GoContext())
+	defer cancel()
+	results := make(chan raceResult, 2)
+	attempt := func(index int) {
+		branch := c.branch(ctx)
+		go func() {
+			results <- raceResult{index: index, state: compute(branch), branch: branch}
+		}()
+	}
+
+	attempt(0)
+	attempts := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var result raceResult
+	select {
+	case result = <-results:
+	case <-timer.C:
+		attempt(1)
+		attempts++
+		result = <-results
+	}
+	c.merge(result.branch)
+	go releaseBranches(results, attempts-1)
+	return result.state
+}
+
+// hedgeDelay give the hedging delay of a node, from the first policy of its tags with a delay.
+func (e *Engine) hedgeDelay(node Node) time.Duration {
+	for _, tag := range e.system.TagsOfNode(node) {
+		if state, found := e.tagsPolicies[tag]; found && state.policy.HedgeAfter > 0 {
+			return state.policy.HedgeAfter
+		}
+	}
+	return 0
+}
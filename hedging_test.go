@@ -0,0 +1,152 @@
+package hoff
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_Compute_TagPolicy_HedgeAfter(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenPolicy      TagPolicy
+		givenDurations   []time.Duration
+		givenErrors      []error
+		expectedState    ComputeState
+		expectedData     map[string]interface{}
+		expectedAttempts int32
+	}{
+		{
+			name:             "Can keep a fast first attempt without hedging",
+			givenPolicy:      TagPolicy{HedgeAfter: time.Second},
+			givenDurations:   []time.Duration{0},
+			givenErrors:      []error{nil},
+			expectedState:    NewContinueComputeState(),
+			expectedData:     map[string]interface{}{"order": 42, "attempt": 1},
+			expectedAttempts: 1,
+		},
+		{
+			name:             "Can keep a hedged attempt ending first",
+			givenPolicy:      TagPolicy{HedgeAfter: 5 * time.Millisecond},
+			givenDurations:   []time.Duration{time.Minute, 0},
+			givenErrors:      []error{nil, nil},
+			expectedState:    NewContinueComputeState(),
+			expectedData:     map[string]interface{}{"order": 42, "attempt": 2},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "Can keep the first attempt ending after hedging",
+			givenPolicy:      TagPolicy{HedgeAfter: 5 * time.Millisecond},
+			givenDurations:   []time.Duration{20 * time.Millisecond, time.Minute},
+			givenErrors:      []error{nil, nil},
+			expectedState:    NewContinueComputeState(),
+			expectedData:     map[string]interface{}{"order": 42, "attempt": 1},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "Can retry hedged attempts",
+			givenPolicy:      TagPolicy{HedgeAfter: time.Second, Retries: 1},
+			givenDurations:   []time.Duration{0, 0},
+			givenErrors:      []error{errors.New("unavailable"), nil},
+			expectedState:    NewContinueComputeState(),
+			expectedData:     map[string]interface{}{"order": 42, "attempt": 2},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "Can abort with the first attempt ending",
+			givenPolicy:      TagPolicy{HedgeAfter: 5 * time.Millisecond},
+			givenDurations:   []time.Duration{time.Minute, 0},
+			givenErrors:      []error{nil, errors.New("unavailable")},
+			expectedState:    NewAbortComputeState(errors.New("unavailable")),
+			expectedData:     map[string]interface{}{"order": 42},
+			expectedAttempts: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			var attempts int32
+			externalAction, _ := NewActionNode("externalAction", func(c *Context) error {
+				attempt := atomic.AddInt32(&attempts, 1)
+				select {
+				case <-time.After(testCase.givenDurations[attempt-1]):
+				case <-c.GoContext().Done():
+					c.Store("cancelled", attempt)
+					return c.GoContext().Err()
+				}
+				if err := testCase.givenErrors[attempt-1]; err != nil {
+					return err
+				}
+				c.Store("attempt", int(attempt))
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(externalAction)
+			ns.ConfigureTagsOnNode(externalAction, "external")
+			ns.ActivateInPlace()
+
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigurePolicyOnTag("external", testCase.givenPolicy)
+
+			result := eng.Compute(map[string]interface{}{"order": 42})
+
+			if !cmp.Equal(result.Report[externalAction], testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", result.Report[externalAction], testCase.expectedState)
+			}
+			if !cmp.Equal(result.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", result.Data, testCase.expectedData)
+			}
+			if atomic.LoadInt32(&attempts) != testCase.expectedAttempts {
+				t.Errorf("attempts - got: %+v, want: %+v", atomic.LoadInt32(&attempts), testCase.expectedAttempts)
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_TagPolicy_HedgeAfter_panic(t *testing.T) {
+	panickingAction, _ := NewActionNode("panickingAction", func(c *Context) error {
+		panic("unavailable")
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(panickingAction)
+	ns.ConfigureTagsOnNode(panickingAction, "external")
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigurePolicyOnTag("external", TagPolicy{HedgeAfter: time.Second})
+	eng.ConfigurePanicPolicy(PanicSkip)
+
+	result := eng.Compute(map[string]interface{}{})
+
+	state := result.Report[panickingAction]
+	var panicErr *PanicError
+	if state.Value != SkipState || !errors.As(state.Error, &panicErr) || panicErr.Value != "unavailable" {
+		t.Errorf("got: %+v, want: skipped on panic", state)
+	}
+}
+
+func Test_Engine_Compute_TagPolicy_HedgeAfter_externalDelete(t *testing.T) {
+	var attempts int32
+	externalAction, _ := NewActionNode("externalAction", func(c *Context) error {
+		if atomic.AddInt32(&attempts, 1) > 1 {
+			return nil
+		}
+		c.Delete("big")
+		<-c.GoContext().Done()
+		return c.GoContext().Err()
+	})
+
+	result, _ := computeWithExternalValue(externalAction, func(ns *NodeSystem, eng *Engine) {
+		ns.ConfigureTagsOnNode(externalAction, "external")
+		eng.ConfigurePolicyOnTag("external", TagPolicy{HedgeAfter: 5 * time.Millisecond})
+	})
+
+	if result.Error != nil || result.Data["read"] != "payload" {
+		t.Errorf("got: %+v %+v, want: %+v", result.Error, result.Data["read"], "payload")
+	}
+}
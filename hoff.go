@@ -10,8 +10,8 @@ Create a node system and activate it:
 	ns.ConfigureJoinModeOnNode(another_action_node, hoff.JoinAnd)
 	ns.AddLink(some_action_node, another_action_node)
 	ns.AddLinkOnBranch(decision_node, another_action_node, true)
-	errs := ns.Activate()
-	if errs != nil {
+	activated, err := ns.Activate()
+	if err != nil {
 		// error handling
 	}
 
@@ -19,7 +19,7 @@ Create a single computation and launch it:
 
 	cxt := hoff.NewContextWithoutData()
 	cxt.Store("input_info", input_info)
-	cp := hoff.NewComputation(ns, context)
+	cp := hoff.NewComputation(activated.NodeSystem(), context)
 	err := cp.Compute()
 	if err != nil {
 		// error handling
@@ -30,7 +30,7 @@ Create a single computation and launch it:
 Create an engine and run multiple computations:
 
 	eng := hoff.NewEngine(hoff.SequentialComputation)
-	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureNodeSystem(activated.NodeSystem())
 
 	cr1 := eng.Compute(input_info)
 	fmt.Printf("computation error: %+v", cr1.Error)
@@ -0,0 +1,171 @@
+/*
+Package hofftest provide utilities to test the node systems of hoff.
+
+Generate random valid and invalid node systems to fuzz the validation, the activation and the engine:
+
+	generator := hofftest.NewGenerator(seed)
+	ns := generator.ValidNodeSystem(hofftest.Shape{Nodes: 10, MaxFanOut: 2, DecisionRatio: 0.3})
+	ok, err := ns.IsValid() // must be valid
+
+	ns, defect := generator.InvalidNodeSystem(hofftest.Shape{Nodes: 10, MaxFanOut: 2, DecisionRatio: 0.3})
+	ok, err := ns.IsValid() // must be invalid due to the defect
+*/
+package hofftest
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/rlespinasse/hoff"
+)
+
+// Shape describe the node systems to generate.
+type Shape struct {
+	// Nodes is the number of nodes
+	Nodes int
+	// MaxFanOut is the maximum number of links from a node
+	MaxFanOut int
+	// DecisionRatio is the probability for a node to be a decision node
+	DecisionRatio float64
+}
+
+// Defect is the reason of an invalid node system.
+type Defect string
+
+const (
+	// CycleDefect is a cycle in the node links
+	CycleDefect Defect = "cycle"
+	// OrphanDecisionDefect is a decision node without link from it
+	OrphanDecisionDefect Defect = "orphan_decision"
+	// UndeclaredNodeDefect is a node link to an undeclared node
+	UndeclaredNodeDefect Defect = "undeclared_node"
+	// DuplicateNodeDefect is a node declared twice
+	DuplicateNodeDefect Defect = "duplicate_node"
+	// MissingJoinModeDefect is a node with multiple links to it without join mode
+	MissingJoinModeDefect Defect = "missing_join_mode"
+)
+
+// Defects is the list of all defects of an invalid node system.
+var Defects = []Defect{CycleDefect, OrphanDecisionDefect, UndeclaredNodeDefect, DuplicateNodeDefect, MissingJoinModeDefect}
+
+// Generator produce random node systems, the same seed produce the same node systems.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator create a generator based on a seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// ValidNodeSystem generate a valid node system (not activated) of a shape.
+// Each action node store its name in the context, each decision node take a random but fixed branch.
+func (g *Generator) ValidNodeSystem(shape Shape) *hoff.NodeSystem {
+	ns, _ := g.validNodeSystem(shape)
+	return ns
+}
+
+// InvalidNodeSystem generate an invalid node system (not activated) of a shape, with the injected defect.
+func (g *Generator) InvalidNodeSystem(shape Shape) (*hoff.NodeSystem, Defect) {
+	if shape.Nodes < 2 {
+		shape.Nodes = 2
+	}
+	ns, nodes := g.validNodeSystem(shape)
+	defect := Defects[g.rand.Intn(len(Defects))]
+	switch defect {
+	case CycleDefect:
+		from, to := nodes[0], nodes[1+g.rand.Intn(len(nodes)-1)]
+		g.link(ns, from, to)
+		g.link(ns, to, from)
+		ns.ConfigureJoinModeOnNode(from, hoff.JoinOr)
+		ns.ConfigureJoinModeOnNode(to, hoff.JoinOr)
+	case OrphanDecisionDefect:
+		ns.AddNode(g.decisionNode("orphan"))
+	case UndeclaredNodeDefect:
+		g.link(ns, nodes[g.rand.Intn(len(nodes))], g.actionNode("undeclared"))
+	case DuplicateNodeDefect:
+		ns.AddNode(nodes[g.rand.Intn(len(nodes))])
+	case MissingJoinModeDefect:
+		join := g.actionNode("join")
+		ns.AddNode(join)
+		g.link(ns, nodes[0], join)
+		g.link(ns, nodes[1], join)
+	}
+	return ns, defect
+}
+
+func (g *Generator) validNodeSystem(shape Shape) (*hoff.NodeSystem, []hoff.Node) {
+	ns := hoff.NewNodeSystem()
+	nodes := make([]hoff.Node, 0, shape.Nodes)
+	for i := 0; i < shape.Nodes; i++ {
+		name := fmt.Sprintf("node%v", i)
+		// a decision node need a following node to link to
+		if i < shape.Nodes-1 && g.rand.Float64() < shape.DecisionRatio {
+			nodes = append(nodes, g.decisionNode(name))
+		} else {
+			nodes = append(nodes, g.actionNode(name))
+		}
+		ns.AddNode(nodes[i])
+	}
+
+	incomingLinks := make(map[hoff.Node]int)
+	for i, from := range nodes {
+		followingNodes := nodes[i+1:]
+		if len(followingNodes) == 0 {
+			continue
+		}
+		fanOut := 0
+		if shape.MaxFanOut > 0 {
+			fanOut = g.rand.Intn(shape.MaxFanOut + 1)
+		}
+		if from.DecideCapability() && fanOut == 0 {
+			fanOut = 1
+		}
+		if fanOut > len(followingNodes) {
+			fanOut = len(followingNodes)
+		}
+		// links are only going to following nodes to avoid cycles
+		for _, index := range g.rand.Perm(len(followingNodes))[:fanOut] {
+			to := followingNodes[index]
+			g.link(ns, from, to)
+			incomingLinks[to]++
+		}
+	}
+
+	for _, node := range nodes {
+		if incomingLinks[node] > 1 {
+			if g.rand.Intn(2) == 0 {
+				ns.ConfigureJoinModeOnNode(node, hoff.JoinAnd)
+			} else {
+				ns.ConfigureJoinModeOnNode(node, hoff.JoinOr)
+			}
+		}
+	}
+	return ns, nodes
+}
+
+func (g *Generator) link(ns *hoff.NodeSystem, from, to hoff.Node) {
+	if from.DecideCapability() {
+		ns.AddLinkOnBranch(from, to, g.rand.Intn(2) == 0)
+	} else {
+		ns.AddLink(from, to)
+	}
+}
+
+func (g *Generator) actionNode(name string) hoff.Node {
+	node, _ := hoff.NewActionNode(name, func(c *hoff.Context) error {
+		c.Store(name, true)
+		return nil
+	})
+	return node
+}
+
+func (g *Generator) decisionNode(name string) hoff.Node {
+	branch := g.rand.Intn(2) == 0
+	node, _ := hoff.NewDecisionNode(name, func(*hoff.Context) (bool, error) {
+		return branch, nil
+	})
+	return node
+}
@@ -0,0 +1,67 @@
+package hofftest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rlespinasse/hoff"
+)
+
+var fuzzShapes = []Shape{
+	{Nodes: 1},
+	{Nodes: 5, MaxFanOut: 1, DecisionRatio: 0.5},
+	{Nodes: 8, MaxFanOut: 2, DecisionRatio: 0.3},
+	{Nodes: 10, MaxFanOut: 3, DecisionRatio: 0.2},
+}
+
+func Test_Generator_ValidNodeSystem(t *testing.T) {
+	for _, shape := range fuzzShapes {
+		for seed := int64(0); seed < 20; seed++ {
+			t.Run(fmt.Sprintf("%+v seed %v", shape, seed), func(t *testing.T) {
+				ns := NewGenerator(seed).ValidNodeSystem(shape)
+
+				valid, err := ns.IsValid()
+				if !valid {
+					t.Fatalf("valid - got: %+v, want: %+v", err, nil)
+				}
+				err = ns.ActivateInPlace()
+				if err != nil {
+					t.Fatalf("activate - got: %+v, want: %+v", err, nil)
+				}
+
+				eng := hoff.NewEngine(hoff.SequentialComputation)
+				eng.ConfigureNodeSystem(ns)
+				result := eng.Compute(make(map[string]interface{}))
+				if result.Error != nil {
+					t.Errorf("compute - got: %+v, want: %+v", result.Error, nil)
+				}
+			})
+		}
+	}
+}
+
+func Test_Generator_InvalidNodeSystem(t *testing.T) {
+	defects := make(map[Defect]bool)
+	for _, shape := range fuzzShapes {
+		for seed := int64(0); seed < 20; seed++ {
+			t.Run(fmt.Sprintf("%+v seed %v", shape, seed), func(t *testing.T) {
+				ns, defect := NewGenerator(seed).InvalidNodeSystem(shape)
+				defects[defect] = true
+
+				valid, _ := ns.IsValid()
+				if valid {
+					t.Errorf("valid with defect %v - got: %+v, want: %+v", defect, valid, false)
+				}
+				err := ns.ActivateInPlace()
+				if err == nil {
+					t.Errorf("activate with defect %v - got: %+v, want: an error", defect, err)
+				}
+			})
+		}
+	}
+	for _, defect := range Defects {
+		if !defects[defect] {
+			t.Errorf("defect %v never generated", defect)
+		}
+	}
+}
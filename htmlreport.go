@@ -0,0 +1,173 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+)
+
+const (
+	htmlNodeWidth   = 180
+	htmlNodeHeight  = 40
+	htmlLayerMargin = 80
+	htmlNodeMargin  = 30
+)
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Computation {{.ID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+svg text { font-size: 12px; }
+.node rect { stroke: #333; }
+.Continue rect, .Continue summary { background: #b7e4b0; fill: #b7e4b0; }
+.Skip rect, .Skip summary { background: #e0e0e0; fill: #e0e0e0; }
+.Abort rect, .Abort summary { background: #f4a6a6; fill: #f4a6a6; }
+.Pause rect, .Pause summary { background: #f8d49a; fill: #f8d49a; }
+.None rect, .None summary { background: #ffffff; fill: #ffffff; }
+details { margin: 0.5em 0; }
+summary { padding: 0.3em; cursor: pointer; }
+pre { background: #f6f6f6; padding: 1em; }
+</style>
+</head>
+<body>
+<h1>Computation {{.ID}}</h1>
+{{if .Fingerprint}}<p><strong>Node system:</strong> {{.Fingerprint}}</p>{{end}}
+{{if .Error}}<p><strong>Error:</strong> {{.Error}}</p>{{end}}
+<svg width="{{.Width}}" height="{{.Height}}" xmlns="http://www.w3.org/2000/svg">
+{{range .Links}}<line x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}" stroke="#333"/>
+{{if .Label}}<text x="{{.LabelX}}" y="{{.LabelY}}">{{.Label}}</text>
+{{end}}{{end}}{{range .Nodes}}<g class="node {{.State}}"><rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" rx="5"/><text x="{{.TextX}}" y="{{.TextY}}">{{.Name}}</text></g>
+{{end}}</svg>
+<h2>Nodes</h2>
+{{range .Nodes}}<details class="{{.State}}">
+<summary>{{.Name}}: {{.Description}}</summary>
+{{if .Error}}<pre>{{.Error}}</pre>{{end}}
+</details>
+{{end}}
+<h2>Context</h2>
+<details>
+<summary>Data</summary>
+<pre>{{.Data}}</pre>
+</details>
+</body>
+</html>
+`))
+
+type htmlReport struct {
+	ID          string
+	Fingerprint string
+	Error       string
+	Data        string
+	Width       int
+	Height      int
+	Nodes       []htmlReportNode
+	Links       []htmlReportLink
+}
+
+type htmlReportNode struct {
+	Name        string
+	State       string
+	Description string
+	Error       string
+	X, Y        int
+	Width       int
+	Height      int
+	TextX       int
+	TextY       int
+}
+
+type htmlReportLink struct {
+	X1, Y1, X2, Y2 int
+	Label          string
+	LabelX, LabelY int
+}
+
+// RenderHTMLReport write a standalone HTML page of a computation result,
+// with a graph view of the activated node system colored by node state,
+// and details of the errors and the context data (see Redactor.Redact to mask sensitive values).
+func RenderHTMLReport(w io.Writer, system *NodeSystem, result ComputationResult) error {
+	return RenderHTMLReportWithFormatter(w, system, result, EnglishStateFormatter{})
+}
+
+// RenderHTMLReportWithFormatter write a standalone HTML page of a computation result (see RenderHTMLReport),
+// with the node states and messages given by a StateFormatter, e.g. to localize them.
+func RenderHTMLReportWithFormatter(w io.Writer, system *NodeSystem, result ComputationResult, formatter StateFormatter) error {
+	if system == nil || !system.IsActivated() {
+		return errors.New("can't render report without an activated node system")
+	}
+
+	data, err := json.MarshalIndent(newComputationRecord(result).Data, "", "  ")
+	if err != nil {
+		return err
+	}
+	report := htmlReport{
+		ID:          result.ID,
+		Fingerprint: result.Fingerprint,
+		Data:        string(data),
+	}
+	if result.Error != nil {
+		report.Error = result.Error.Error()
+	}
+
+	descriptions := FormatReport(system, result, formatter)
+	positions := make(map[string]htmlReportNode)
+	maxNodesInLayer := 0
+	layers := nodeLayers(system)
+	for layerIndex, layer := range layers {
+		if len(layer) > maxNodesInLayer {
+			maxNodesInLayer = len(layer)
+		}
+		for nodeIndex, node := range layer {
+			x := htmlNodeMargin + layerIndex*(htmlNodeWidth+htmlLayerMargin)
+			y := htmlNodeMargin + nodeIndex*(htmlNodeHeight+htmlNodeMargin)
+			reportNode := htmlReportNode{
+				Name:        fmt.Sprint(node),
+				State:       "None",
+				Description: descriptions[system.nodeMapKey(node)],
+				X:           x,
+				Y:           y,
+				Width:       htmlNodeWidth,
+				Height:      htmlNodeHeight,
+				TextX:       x + 10,
+				TextY:       y + htmlNodeHeight/2 + 4,
+			}
+			if state, found := result.Report[system.nodeMapKey(node)]; found {
+				reportNode.State = string(state.Value)
+				if state.Error != nil {
+					reportNode.Error = state.Error.Error()
+				}
+			}
+			positions[system.nodeID(node)] = reportNode
+			report.Nodes = append(report.Nodes, reportNode)
+		}
+	}
+	report.Width = htmlNodeMargin + len(layers)*(htmlNodeWidth+htmlLayerMargin)
+	report.Height = htmlNodeMargin + maxNodesInLayer*(htmlNodeHeight+htmlNodeMargin)
+
+	for _, link := range system.links {
+		from, to := positions[system.nodeID(link.From)], positions[system.nodeID(link.To)]
+		reportLink := htmlReportLink{
+			X1: from.X + from.Width,
+			Y1: from.Y + from.Height/2,
+			X2: to.X,
+			Y2: to.Y + to.Height/2,
+		}
+		if link.Branch != nil {
+			reportLink.Label = fmt.Sprint(*link.Branch)
+			reportLink.LabelX = (reportLink.X1+reportLink.X2)/2 - 10
+			reportLink.LabelY = (reportLink.Y1+reportLink.Y2)/2 - 4
+		}
+		report.Links = append(report.Links, reportLink)
+	}
+	sort.SliceStable(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Name < report.Nodes[j].Name
+	})
+
+	return htmlReportTemplate.Execute(w, report)
+}
@@ -0,0 +1,61 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_RenderHTMLReport(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("keyIsPresent", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	readKey, _ := NewActionNode("readKey", func(*Context) error { return nil })
+	throwError, _ := NewActionNode("throwError", func(*Context) error {
+		return errors.New("missing <key>")
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(readKey)
+	ns.AddNode(throwError)
+	ns.AddLinkOnBranch(keyIsPresent, readKey, true)
+	ns.AddLinkOnBranch(keyIsPresent, throwError, false)
+
+	err := RenderHTMLReport(&bytes.Buffer{}, ns, ComputationResult{})
+	expectedError := errors.New("can't render report without an activated node system")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	result := eng.Compute(map[string]interface{}{"other": "value"})
+
+	var buffer bytes.Buffer
+	err = RenderHTMLReport(&buffer, ns, result)
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+
+	html := buffer.String()
+	expectedContents := []string{
+		"<title>Computation " + result.ID + "</title>",
+		"<p><strong>Error:</strong> missing &lt;key&gt;</p>",
+		`<g class="node Continue"><rect x="30" y="30" width="180" height="40" rx="5"/><text x="40" y="54">keyIsPresent</text></g>`,
+		`<g class="node Skip">`,
+		`<g class="node Abort">`,
+		`<text x="240" y="81">false</text>`,
+		"<summary>throwError: &#39;Abort on missing &lt;key&gt;&#39;</summary>",
+		`&#34;other&#34;: &#34;value&#34;`,
+	}
+	for _, expectedContent := range expectedContents {
+		if !strings.Contains(html, expectedContent) {
+			t.Errorf("got: %v, want to contain: %v", html, expectedContent)
+		}
+	}
+}
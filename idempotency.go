@@ -0,0 +1,109 @@
+package hoff
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ComputeIdempotent run computation against node system with input data only once by idempotency key,
+// like the ID of the message triggering it, and tell if the key was already processed.
+// An already processed key give the result of its computation from the report store, without computing again
+// (the context values are restored from JSON).
+// The computation ID is derived from the key, and the key is reserved during the computation
+// on the engine, and on the engine replicas through the locker (see ConfigureLocker).
+func (e *Engine) ComputeIdempotent(key string, data map[string]interface{}) (ComputationResult, bool) {
+	if e.reportStore == nil {
+		return ComputationResult{Data: data, Error: errors.New("can't compute idempotently without report store")}, false
+	}
+	if e.system == nil {
+		return ComputationResult{Data: data, Error: errors.New("need a configured node system")}, false
+	}
+	id := idempotentComputationID(key)
+
+	release, err := e.reserveIdempotencyKey(key, id)
+	if err != nil {
+		return ComputationResult{ID: id, Data: data, Error: err}, false
+	}
+	defer release()
+
+	reports, err := e.reportStore.Query(ReportQuery{ID: id, Limit: 1})
+	if err != nil {
+		return ComputationResult{ID: id, Data: data, Error: fmt.Errorf("can't compute idempotency key '%v': %w", key, err)}, false
+	}
+	if len(reports) > 0 {
+		result, err := e.restoreComputationResult(reports[0].Report)
+		if err != nil {
+			return ComputationResult{ID: id, Data: data, Error: fmt.Errorf("can't compute idempotency key '%v': %w", key, err)}, true
+		}
+		return result, true
+	}
+	return e.compute(context.Background(), data, SubmitOptions{computationID: id}, nil), false
+}
+
+// idempotentComputationID give the computation ID of an idempotency key, in the format of a generated ID.
+func idempotentComputationID(key string) string {
+	hash := sha256.Sum256([]byte("idempotency:" + key))
+	return hex.EncodeToString(hash[:16])
+}
+
+// reserveIdempotencyKey reserve a key on the engine, and on the replicas when a locker is configured,
+// and give the function to release it.
+func (e *Engine) reserveIdempotencyKey(key, id string) (func(), error) {
+	e.mu.Lock()
+	if e.idempotencyKeys[key] {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("can't compute idempotency key '%v', already in progress", key)
+	}
+	if e.idempotencyKeys == nil {
+		e.idempotencyKeys = make(map[string]bool)
+	}
+	e.idempotencyKeys[key] = true
+	e.mu.Unlock()
+
+	release := func() {
+		e.mu.Lock()
+		delete(e.idempotencyKeys, key)
+		e.mu.Unlock()
+	}
+	if e.locker == nil {
+		return release, nil
+	}
+	lockKey := "computation:" + id
+	token, err := e.locker.Lock(lockKey, e.lockTTL)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("can't compute idempotency key '%v': %w", key, err)
+	}
+	return func() {
+		e.locker.Unlock(lockKey, token)
+		release()
+	}, nil
+}
+
+// restoreComputationResult give the result of a computation from its report.
+func (e *Engine) restoreComputationResult(report ComputationReport) (ComputationResult, error) {
+	data, err := decodeContextData(report.Data)
+	if err != nil {
+		return ComputationResult{}, err
+	}
+	states, err := e.restoreNodeStates(report.States)
+	if err != nil {
+		return ComputationResult{}, fmt.Errorf("can't restore report '%v' with %w", report.ID, err)
+	}
+	result := ComputationResult{
+		ID:          report.ID,
+		Fingerprint: report.Fingerprint,
+		Data:        data,
+		Report:      states,
+		Success:     report.Success,
+		Seed:        report.Seed,
+		Outputs:     e.system.outputsOf(data),
+	}
+	if report.Error != "" {
+		result.Error = restoreError(report.Error, report.ErrorCauses)
+	}
+	return result, nil
+}
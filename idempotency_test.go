@@ -0,0 +1,93 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ComputeIdempotent(t *testing.T) {
+	computations := 0
+	countingAction, _ := NewActionNode("countingAction", func(c *Context) error {
+		computations++
+		c.Store("computations", computations)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(countingAction)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	result, _ := eng.ComputeIdempotent("message-1", map[string]interface{}{})
+	expectedError := errors.New("can't compute idempotently without report store")
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("error without store - got: %+v, want: %+v", result.Error, expectedError)
+	}
+
+	eng.ConfigureReportStore(NewMemoryReportStore(), "counting")
+	first, processed := eng.ComputeIdempotent("message-1", map[string]interface{}{})
+	if first.Error != nil || processed || first.ID != idempotentComputationID("message-1") {
+		t.Errorf("first - got: %+v %v, want: computed", first, processed)
+	}
+	replayed, processed := eng.ComputeIdempotent("message-1", map[string]interface{}{})
+	if !processed || replayed.ID != first.ID || !replayed.Success {
+		t.Errorf("replayed - got: %+v %v, want: already processed", replayed, processed)
+	}
+	expectedData := map[string]interface{}{"computations": float64(1)}
+	if !cmp.Equal(replayed.Data, expectedData) {
+		t.Errorf("replayed data - got: %+v, want: %+v", replayed.Data, expectedData)
+	}
+	if !cmp.Equal(replayed.Report, first.Report, errorComparator) {
+		t.Errorf("replayed report - got: %+v, want: %+v", replayed.Report, first.Report)
+	}
+	other, processed := eng.ComputeIdempotent("message-2", map[string]interface{}{})
+	if processed || other.Data["computations"] != 2 {
+		t.Errorf("other - got: %+v %v, want: computed", other, processed)
+	}
+}
+
+func Test_Engine_ComputeIdempotent_inProgress(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	blockingAction, _ := NewActionNode("blockingAction", func(*Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(blockingAction)
+	ns.ActivateInPlace()
+
+	locker := NewMemoryLocker()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureReportStore(NewMemoryReportStore(), "blocking")
+	eng.ConfigureLocker(locker, time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		eng.ComputeIdempotent("message", map[string]interface{}{})
+		close(done)
+	}()
+	<-started
+
+	result, _ := eng.ComputeIdempotent("message", map[string]interface{}{})
+	expectedError := errors.New("can't compute idempotency key 'message', already in progress")
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, expectedError)
+	}
+	close(release)
+	<-done
+
+	token, _ := locker.Lock("computation:"+idempotentComputationID("other"), time.Minute)
+	result, _ = eng.ComputeIdempotent("other", map[string]interface{}{})
+	expectedError = fmt.Errorf("can't compute idempotency key 'other': %w", ErrLocked)
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("locked error - got: %+v, want: %+v", result.Error, expectedError)
+	}
+	locker.Unlock("computation:"+idempotentComputationID("other"), token)
+}
@@ -0,0 +1,162 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ConfigureNodeIdentity replace the equality of the node instances by an identity function
+// (e.g. based on the name of the nodes) to compare, deduplicate, and map the nodes of the system.
+// A node given to the system is then replaced by the declared node with the same identity,
+// and two declared nodes with the same identity are multiple instances of the same node.
+// It need to be configured before adding nodes.
+// The identity function is called from the concurrent validation checks and computations,
+// so it must be safe for concurrent use (e.g. without modifying a shared state).
+func (s *NodeSystem) ConfigureNodeIdentity(identity func(Node) string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't configure node identity, node system is freeze due to activation")
+	}
+	if identity == nil {
+		return false, errors.New("can't configure node identity without function")
+	}
+	if len(s.nodes) > 0 {
+		return false, errors.New("can't configure node identity after adding nodes")
+	}
+	s.identity = identity
+	return true, nil
+}
+
+// nodeID give the stable identifier of a node used to compare and map the nodes of the system,
+// based on the node identity if configured.
+// The identifiers of the declared nodes are cached at activation.
+func (s *NodeSystem) nodeID(n Node) string {
+	if identified, isIdentified := n.(*identifiedNode); isIdentified {
+		return identified.id
+	}
+	if len(s.nodesIDs) > 0 && hashableNode(n) {
+		if id, found := s.nodesIDs[n]; found {
+			return id
+		}
+	}
+	if s.identity != nil && n != nil {
+		return s.identity(n)
+	}
+	return defaultNodeID(n)
+}
+
+// defaultNodeID give an identifier based on the instance of a node pointer,
+// or on the content of a node value, so nodes holding maps, slices, or functions can be mapped.
+func defaultNodeID(n Node) string {
+	if n == nil {
+		return ""
+	}
+	if reflect.ValueOf(n).Kind() == reflect.Ptr {
+		return fmt.Sprintf("%T@%p", n, n)
+	}
+	return fmt.Sprintf("%T%#v", n, n)
+}
+
+// hashableNode tell if a node can be used as map key,
+// a node value holding a map, a slice, or a function can't.
+func hashableNode(n Node) bool {
+	return n == nil || hashableValue(reflect.ValueOf(n))
+}
+
+func hashableValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || hashableValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !hashableValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !hashableValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.Type().Comparable()
+}
+
+// identifiedNode stand for a node who can't be used as map key in the computation results.
+type identifiedNode struct {
+	Node
+	id string
+}
+
+func (n *identifiedNode) String() string {
+	return fmt.Sprint(n.Node)
+}
+
+// cacheNodesIDs keep the identifiers of the declared nodes, and the keys of the nodes who can't be used as map key.
+func (s *NodeSystem) cacheNodesIDs() {
+	s.nodesIDs, s.nodesMapKeys = nil, nil
+	nodesIDs := make(map[Node]string, len(s.nodes))
+	nodesMapKeys := make(map[string]Node)
+	for _, node := range s.nodes {
+		id := s.nodeID(node)
+		if hashableNode(node) {
+			nodesIDs[node] = id
+		} else {
+			nodesMapKeys[id] = &identifiedNode{Node: node, id: id}
+		}
+	}
+	s.nodesIDs, s.nodesMapKeys = nodesIDs, nodesMapKeys
+}
+
+// nodeMapKey give the key of a node in the maps of the computation results,
+// the node itself or, for a node who can't be used as map key, a node standing for it.
+func (s *NodeSystem) nodeMapKey(n Node) Node {
+	if hashableNode(n) {
+		return n
+	}
+	id := s.nodeID(n)
+	if key, found := s.nodesMapKeys[id]; found {
+		return key
+	}
+	return &identifiedNode{Node: n, id: id}
+}
+
+// sameNode tell if two nodes are the same, based on their identifier.
+func (s *NodeSystem) sameNode(x, y Node) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	return s.nodeID(x) == s.nodeID(y)
+}
+
+// canonicalNode give the declared node who is the same as a node, or the node itself.
+func (s *NodeSystem) canonicalNode(n Node) Node {
+	if s.identity == nil || n == nil {
+		return n
+	}
+	for _, node := range s.nodes {
+		if s.sameNode(node, n) {
+			return node
+		}
+	}
+	return n
+}
+
+// canonicalizeNodes replace the nodes used in the links before their declaration
+// by the declared nodes with the same identity.
+func (s *NodeSystem) canonicalizeNodes() {
+	if s.identity == nil {
+		return
+	}
+	for index, link := range s.links {
+		s.links[index].From = s.canonicalNode(link.From)
+		s.links[index].To = s.canonicalNode(link.To)
+	}
+	for index, link := range s.portLinks {
+		s.portLinks[index].From = s.canonicalNode(link.From)
+		s.portLinks[index].To = s.canonicalNode(link.To)
+	}
+}
@@ -0,0 +1,186 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ConfigureNodeIdentity(t *testing.T) {
+	activatedSystem := NewNodeSystem()
+	activatedSystem.ActivateInPlace()
+	systemWithNodes := NewNodeSystem()
+	systemWithNodes.AddNode(someActionNode)
+
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenIdentity func(Node) string
+		expectedError error
+	}{
+		{
+			name:          "Can configure node identity",
+			givenSystem:   NewNodeSystem(),
+			givenIdentity: nodeName,
+		},
+		{
+			name:          "Can't configure node identity without function",
+			givenSystem:   NewNodeSystem(),
+			expectedError: errors.New("can't configure node identity without function"),
+		},
+		{
+			name:          "Can't configure node identity after adding nodes",
+			givenSystem:   systemWithNodes,
+			givenIdentity: nodeName,
+			expectedError: errors.New("can't configure node identity after adding nodes"),
+		},
+		{
+			name:          "Can't configure node identity on activated system",
+			givenSystem:   activatedSystem,
+			givenIdentity: nodeName,
+			expectedError: errors.New("can't configure node identity, node system is freeze due to activation"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ok, err := testCase.givenSystem.ConfigureNodeIdentity(testCase.givenIdentity)
+
+			if ok != (testCase.expectedError == nil) {
+				t.Errorf("ok - got: %+v, want: %+v", ok, testCase.expectedError == nil)
+			}
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_with_node_identity(t *testing.T) {
+	newStep := func(name string) Node {
+		node, _ := NewActionNode(name, func(*Context) error { return nil })
+		return node
+	}
+	first, second := newStep("first"), newStep("second")
+
+	ns := NewNodeSystem()
+	ns.ConfigureNodeIdentity(nodeName)
+	ns.AddLink(newStep("first"), newStep("second"))
+	ns.AddNode(first)
+	ns.AddNode(second)
+	ns.ConfigureTagsOnNode(newStep("second"), "db")
+	err := ns.ActivateInPlace()
+	if err != nil {
+		t.Fatalf("activation - got: %+v, want: %+v", err, nil)
+	}
+
+	nodes, _ := ns.Follow(newStep("first"), nil)
+	if len(nodes) != 1 || nodes[0] != second {
+		t.Errorf("follow - got: %+v, want: %+v", nodes, []Node{second})
+	}
+	if !ns.HaveTag(second, "db") {
+		t.Errorf("tags - got: %+v, want: %+v", ns.TagsOfNode(second), []string{"db"})
+	}
+
+	duplicated := NewNodeSystem()
+	duplicated.ConfigureNodeIdentity(nodeName)
+	duplicated.AddNode(first)
+	duplicated.AddNode(newStep("first"))
+	_, validationErr := duplicated.IsValid()
+	errs := validationErrors(validationErr)
+	expectedErrors := []error{fmt.Errorf("can't have multiple instances (2) of the same node: %+v", first)}
+	if !cmp.Equal(errs, expectedErrors, errorComparator) {
+		t.Errorf("duplicated - got: %+v, want: %+v", errs, expectedErrors)
+	}
+}
+
+func nodeName(node Node) string {
+	return fmt.Sprint(node)
+}
+
+// stepsNode is a node value holding a slice, who can't be compared nor used as map key.
+type stepsNode struct {
+	name  string
+	steps []string
+}
+
+func (n stepsNode) String() string                  { return n.name }
+func (n stepsNode) Compute(c *Context) ComputeState { return NewContinueComputeState() }
+func (n stepsNode) DecideCapability() bool          { return false }
+
+func Test_NodeSystem_with_uncomparable_nodes(t *testing.T) {
+	first := stepsNode{name: "first", steps: []string{"a", "b"}}
+	second := stepsNode{name: "second", steps: []string{"c"}}
+	third := stepsNode{name: "third"}
+
+	ns := NewNodeSystem()
+	ns.AddNode(first)
+	ns.AddNode(second)
+	ns.AddNode(third)
+	ns.AddLink(first, third)
+	ns.AddLink(second, third)
+	ns.ConfigureJoinModeOnNode(third, JoinAnd)
+	ns.ConfigureTagsOnNode(third, "slow")
+	err := ns.ActivateInPlace()
+	if err != nil {
+		t.Fatalf("activation - got: %+v, want: %+v", err, nil)
+	}
+
+	if !cmp.Equal(ns.InitialNodes(), []Node{first, second}, NodeComparator) {
+		t.Errorf("initial nodes - got: %+v, want: %+v", ns.InitialNodes(), []Node{first, second})
+	}
+	nodes, _ := ns.Follow(first, nil)
+	if !cmp.Equal(nodes, []Node{third}, NodeComparator) {
+		t.Errorf("follow - got: %+v, want: %+v", nodes, []Node{third})
+	}
+	ancestors, _ := ns.Ancestors(third, nil)
+	if !cmp.Equal(ancestors, []Node{first, second}, NodeComparator) {
+		t.Errorf("ancestors - got: %+v, want: %+v", ancestors, []Node{first, second})
+	}
+	if ns.JoinModeOfNode(third) != JoinAnd || !ns.HaveTag(third, "slow") {
+		t.Errorf("configuration - got: %+v %+v, want: %+v %+v", ns.JoinModeOfNode(third), ns.TagsOfNode(third), JoinAnd, []string{"slow"})
+	}
+}
+
+func Test_Engine_Compute_with_uncomparable_nodes(t *testing.T) {
+	first := stepsNode{name: "first", steps: []string{"a", "b"}}
+	second := stepsNode{name: "second", steps: []string{"c"}}
+	third := stepsNode{name: "third"}
+
+	ns := NewNodeSystem()
+	ns.AddNode(first)
+	ns.AddNode(second)
+	ns.AddNode(third)
+	ns.AddLink(first, third)
+	ns.AddLink(second, third)
+	ns.ConfigureJoinModeOnNode(third, JoinAnd)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureContextSnapshots(true)
+	err := eng.ConfigureConcurrencyOnNode(third, 1)
+	if err != nil {
+		t.Fatalf("concurrency - got: %+v, want: %+v", err, nil)
+	}
+
+	result := eng.Compute(map[string]interface{}{"key": "value"})
+	if result.Error != nil {
+		t.Fatalf("error - got: %+v, want: %+v", result.Error, nil)
+	}
+	states := make(map[string]StateType, len(result.Report))
+	for node, state := range result.Report {
+		states[fmt.Sprint(node)] = state.Value
+	}
+	expectedStates := map[string]StateType{"first": ContinueState, "second": ContinueState, "third": ContinueState}
+	if !cmp.Equal(states, expectedStates) {
+		t.Errorf("report - got: %+v, want: %+v", states, expectedStates)
+	}
+	if len(result.Durations) != 3 || len(result.Snapshots) != 3 {
+		t.Errorf("durations and snapshots - got: %+v %+v, want: 3 nodes", result.Durations, result.Snapshots)
+	}
+	if dangling := ns.DanglingNodes(result); len(dangling) != 0 {
+		t.Errorf("dangling nodes - got: %+v, want: none", dangling)
+	}
+}
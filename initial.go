@@ -0,0 +1,65 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DeclareInitialNode declare a node as an initial node into the system before activation,
+// where the computations start even with links to it (e.g. a link from an error handling node to an entry node),
+// the node being computed once at start whatever its ancestors.
+// Once a node is declared, only the declared nodes are initial nodes, instead of the nodes without links to them.
+// The links still can't form a cycle.
+func (s *NodeSystem) DeclareInitialNode(n Node) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't declare initial node, node system is freeze due to activation")
+	}
+	if n == nil {
+		return false, errors.New("can't declare nil node as initial node")
+	}
+	if s.isDeclaredInitialNode(n) {
+		return false, nil
+	}
+	s.declaredInitialNodes = append(s.declaredInitialNodes, n)
+	return true, nil
+}
+
+// isDeclaredInitialNode tell if a node is declared as initial node.
+func (s *NodeSystem) isDeclaredInitialNode(n Node) bool {
+	for _, node := range s.declaredInitialNodes {
+		if s.sameNode(node, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkForInconsistentInitialNodes check the declared initial nodes are declared nodes,
+// and the nodes without links to them are declared initial nodes, to be computed.
+func checkForInconsistentInitialNodes(s *NodeSystem) []error {
+	errs := make([]error, 0)
+	if len(s.declaredInitialNodes) == 0 {
+		return errs
+	}
+	for _, node := range s.declaredInitialNodes {
+		if !s.haveNode(node) {
+			errs = append(errs, fmt.Errorf("can't declare undeclared node as initial node: %v", node))
+		}
+	}
+	for _, node := range s.nodes {
+		if s.isDeclaredInitialNode(node) {
+			continue
+		}
+		linked := false
+		for _, link := range s.links {
+			if s.sameNode(link.To, node) {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			errs = append(errs, fmt.Errorf("can't have node without link to it not declared as initial node: %v", node))
+		}
+	}
+	return errs
+}
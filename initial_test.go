@@ -0,0 +1,118 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_DeclareInitialNode(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenSystem    func() *NodeSystem
+		givenNode      Node
+		expectedResult bool
+		expectedError  error
+	}{
+		{
+			name:           "Can declare an initial node",
+			givenSystem:    NewNodeSystem,
+			givenNode:      someActionNode,
+			expectedResult: true,
+		},
+		{
+			name: "Can declare an initial node only once",
+			givenSystem: func() *NodeSystem {
+				ns := NewNodeSystem()
+				ns.DeclareInitialNode(someActionNode)
+				return ns
+			},
+			givenNode: someActionNode,
+		},
+		{
+			name:          "Can't declare a nil node",
+			givenSystem:   NewNodeSystem,
+			expectedError: errors.New("can't declare nil node as initial node"),
+		},
+		{
+			name:          "Can't declare an initial node after activation",
+			givenSystem:   activatedNodeSystem,
+			givenNode:     someActionNode,
+			expectedError: errors.New("can't declare initial node, node system is freeze due to activation"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := testCase.givenSystem().DeclareInitialNode(testCase.givenNode)
+
+			if result != testCase.expectedResult {
+				t.Errorf("result - got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_checkForInconsistentInitialNodes(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.DeclareInitialNode(alwaysTrueDecisionNode)
+
+	errs := checkForInconsistentInitialNodes(ns)
+	expectedErrors := []error{
+		fmt.Errorf("can't declare undeclared node as initial node: %v", alwaysTrueDecisionNode),
+		fmt.Errorf("can't have node without link to it not declared as initial node: %v", someActionNode),
+		fmt.Errorf("can't have node without link to it not declared as initial node: %v", anotherActionNode),
+	}
+	if !cmp.Equal(errs, expectedErrors, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", errs, expectedErrors)
+	}
+}
+
+func Test_Engine_Compute_withDeclaredInitialNode(t *testing.T) {
+	var computed []string
+	entry, _ := NewActionNode("entry", func(*Context) error {
+		computed = append(computed, "entry")
+		return nil
+	})
+	handler, _ := NewActionNode("handler", func(*Context) error {
+		computed = append(computed, "handler")
+		return nil
+	})
+	step, _ := NewActionNode("step", func(*Context) error {
+		computed = append(computed, "step")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(handler)
+	ns.AddNode(entry)
+	ns.AddNode(step)
+	ns.AddLink(handler, entry)
+	ns.AddLink(entry, step)
+	ns.DeclareInitialNode(entry)
+	ns.DeclareInitialNode(handler)
+	err := ns.ActivateInPlace()
+	if err != nil {
+		t.Fatalf("activation - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(ns.InitialNodes(), []Node{handler, entry}, NodeComparator) {
+		t.Errorf("initial nodes - got: %+v, want: %+v", ns.InitialNodes(), []Node{handler, entry})
+	}
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	result := eng.Compute(map[string]interface{}{})
+	if result.Error != nil {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, nil)
+	}
+	expectedComputed := []string{"handler", "entry", "step"}
+	if !cmp.Equal(computed, expectedComputed) {
+		t.Errorf("computed - got: %+v, want: %+v", computed, expectedComputed)
+	}
+}
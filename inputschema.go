@@ -0,0 +1,123 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// InputType is the type of an input parameter of a node system.
+type InputType string
+
+const (
+	// StringInput is an input parameter of string type.
+	StringInput InputType = "string"
+	// NumberInput is an input parameter of any number type (int, float, ...).
+	NumberInput InputType = "number"
+	// BoolInput is an input parameter of bool type.
+	BoolInput InputType = "bool"
+	// ObjectInput is an input parameter of map type.
+	ObjectInput InputType = "object"
+	// ArrayInput is an input parameter of slice or array type.
+	ArrayInput InputType = "array"
+	// AnyInput is an input parameter of any type.
+	AnyInput InputType = "any"
+)
+
+// InputParameter is an initial context key of the computations of a node system.
+// An input parameter not required get its default value (if any) when missing.
+type InputParameter struct {
+	Key         string      `json:"key"`
+	Type        InputType   `json:"type"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// DeclareInput add an input parameter into the system before activation,
+// the initial context of each computation is validated against the declared input parameters.
+func (s *NodeSystem) DeclareInput(parameter InputParameter) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't declare input, node system is freeze due to activation")
+	}
+	if parameter.Key == "" {
+		return false, errors.New("can't declare input without key")
+	}
+	if !isInputType(parameter.Type) {
+		return false, fmt.Errorf("can't declare input '%v' with unknown type: %v", parameter.Key, parameter.Type)
+	}
+	if parameter.Default != nil {
+		if parameter.Required {
+			return false, fmt.Errorf("can't declare required input '%v' with default value", parameter.Key)
+		}
+		if !matchInputType(parameter.Type, parameter.Default) {
+			return false, fmt.Errorf("can't declare input '%v' with default value not of type %v: %v", parameter.Key, parameter.Type, parameter.Default)
+		}
+	}
+	for _, input := range s.inputs {
+		if input.Key == parameter.Key {
+			return false, fmt.Errorf("can't declare input '%v' twice", parameter.Key)
+		}
+	}
+	s.inputs = append(s.inputs, parameter)
+	return true, nil
+}
+
+// InputSchema get the declared input parameters, in declaration order.
+func (s *NodeSystem) InputSchema() []InputParameter {
+	return append([]InputParameter(nil), s.inputs...)
+}
+
+// ValidateInput check the initial context data against the declared input parameters,
+// and add the default values of the missing ones.
+// The errors of all the invalid input parameters are given as a ValidationError.
+func (s *NodeSystem) ValidateInput(data map[string]interface{}) error {
+	errs := make([]error, 0)
+	for _, input := range s.inputs {
+		value, found := data[input.Key]
+		switch {
+		case !found && input.Required:
+			errs = append(errs, fmt.Errorf("can't have missing required input: %v", input.Key))
+		case !found && input.Default != nil:
+			data[input.Key] = input.Default
+		case found && !matchInputType(input.Type, value):
+			errs = append(errs, fmt.Errorf("can't have input '%v' not of type %v: %v", input.Key, input.Type, value))
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func isInputType(inputType InputType) bool {
+	switch inputType {
+	case StringInput, NumberInput, BoolInput, ObjectInput, ArrayInput, AnyInput:
+		return true
+	}
+	return false
+}
+
+func matchInputType(inputType InputType, value interface{}) bool {
+	if inputType == AnyInput {
+		return true
+	}
+	if value == nil {
+		return false
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.String:
+		return inputType == StringInput
+	case reflect.Bool:
+		return inputType == BoolInput
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return inputType == NumberInput
+	case reflect.Map, reflect.Struct:
+		return inputType == ObjectInput
+	case reflect.Slice, reflect.Array:
+		return inputType == ArrayInput
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_DeclareInput(t *testing.T) {
+	activatedSystem := NewNodeSystem()
+	activatedSystem.AddNode(someActionNode)
+	activatedSystem.ActivateInPlace()
+
+	testCases := []struct {
+		name           string
+		givenSystem    *NodeSystem
+		givenInputs    []InputParameter
+		expectedResult bool
+		expectedError  error
+		expectedSchema []InputParameter
+	}{
+		{
+			name:           "Can declare inputs",
+			givenSystem:    NewNodeSystem(),
+			givenInputs:    []InputParameter{{Key: "name", Type: StringInput, Required: true}, {Key: "count", Type: NumberInput, Default: 1}},
+			expectedResult: true,
+			expectedSchema: []InputParameter{{Key: "name", Type: StringInput, Required: true}, {Key: "count", Type: NumberInput, Default: 1}},
+		},
+		{
+			name:          "Can't declare input without key",
+			givenSystem:   NewNodeSystem(),
+			givenInputs:   []InputParameter{{Type: StringInput}},
+			expectedError: errors.New("can't declare input without key"),
+		},
+		{
+			name:          "Can't declare input with unknown type",
+			givenSystem:   NewNodeSystem(),
+			givenInputs:   []InputParameter{{Key: "name", Type: "text"}},
+			expectedError: errors.New("can't declare input 'name' with unknown type: text"),
+		},
+		{
+			name:          "Can't declare required input with default value",
+			givenSystem:   NewNodeSystem(),
+			givenInputs:   []InputParameter{{Key: "name", Type: StringInput, Required: true, Default: "bob"}},
+			expectedError: errors.New("can't declare required input 'name' with default value"),
+		},
+		{
+			name:          "Can't declare input with default value of another type",
+			givenSystem:   NewNodeSystem(),
+			givenInputs:   []InputParameter{{Key: "count", Type: NumberInput, Default: "one"}},
+			expectedError: errors.New("can't declare input 'count' with default value not of type number: one"),
+		},
+		{
+			name:           "Can't declare input twice",
+			givenSystem:    NewNodeSystem(),
+			givenInputs:    []InputParameter{{Key: "name", Type: StringInput}, {Key: "name", Type: AnyInput}},
+			expectedError:  errors.New("can't declare input 'name' twice"),
+			expectedSchema: []InputParameter{{Key: "name", Type: StringInput}},
+		},
+		{
+			name:          "Can't declare input on activated node system",
+			givenSystem:   activatedSystem,
+			givenInputs:   []InputParameter{{Key: "name", Type: StringInput}},
+			expectedError: errors.New("can't declare input, node system is freeze due to activation"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var result bool
+			var err error
+			for _, input := range testCase.givenInputs {
+				result, err = testCase.givenSystem.DeclareInput(input)
+			}
+
+			if result != testCase.expectedResult {
+				t.Errorf("result - got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			schema := testCase.givenSystem.InputSchema()
+			if !cmp.Equal(schema, testCase.expectedSchema) {
+				t.Errorf("schema - got: %+v, want: %+v", schema, testCase.expectedSchema)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_ValidateInput(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.DeclareInput(InputParameter{Key: "name", Type: StringInput, Required: true})
+	ns.DeclareInput(InputParameter{Key: "count", Type: NumberInput, Default: 1})
+	ns.DeclareInput(InputParameter{Key: "tags", Type: ArrayInput})
+	ns.DeclareInput(InputParameter{Key: "options", Type: ObjectInput})
+	ns.DeclareInput(InputParameter{Key: "dry_run", Type: BoolInput})
+	ns.DeclareInput(InputParameter{Key: "extra", Type: AnyInput})
+
+	testCases := []struct {
+		name           string
+		givenData      map[string]interface{}
+		expectedData   map[string]interface{}
+		expectedErrors []error
+	}{
+		{
+			name:         "Can validate input with default values",
+			givenData:    map[string]interface{}{"name": "bob"},
+			expectedData: map[string]interface{}{"name": "bob", "count": 1},
+		},
+		{
+			name: "Can validate input of each type",
+			givenData: map[string]interface{}{
+				"name":    "bob",
+				"count":   2.5,
+				"tags":    []string{"a"},
+				"options": map[string]interface{}{"a": 1},
+				"dry_run": true,
+				"extra":   nil,
+			},
+			expectedData: map[string]interface{}{
+				"name":    "bob",
+				"count":   2.5,
+				"tags":    []string{"a"},
+				"options": map[string]interface{}{"a": 1},
+				"dry_run": true,
+				"extra":   nil,
+			},
+		},
+		{
+			name:         "Can't validate input with missing required input or wrong types",
+			givenData:    map[string]interface{}{"count": "two", "dry_run": "yes"},
+			expectedData: map[string]interface{}{"count": "two", "dry_run": "yes"},
+			expectedErrors: []error{
+				errors.New("can't have missing required input: name"),
+				errors.New("can't have input 'count' not of type number: two"),
+				errors.New("can't have input 'dry_run' not of type bool: yes"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ns.ValidateInput(testCase.givenData)
+
+			if !cmp.Equal(validationErrors(err), testCase.expectedErrors, errorComparator) {
+				t.Errorf("errors - got: %+v, want: %+v", validationErrors(err), testCase.expectedErrors)
+			}
+			if !cmp.Equal(testCase.givenData, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", testCase.givenData, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_inputSchema(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.DeclareInput(InputParameter{Key: "name", Type: StringInput, Required: true})
+	ns.DeclareInput(InputParameter{Key: "count", Type: NumberInput, Default: 1})
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	result := eng.Compute(map[string]interface{}{"name": "bob"})
+	expectedData := map[string]interface{}{"name": "bob", "count": 1}
+	if result.Error != nil || !cmp.Equal(result.Data, expectedData) {
+		t.Errorf("valid input - got: %+v %+v, want: %+v %+v", result.Error, result.Data, nil, expectedData)
+	}
+
+	result = eng.Compute(nil)
+	expectedError := fmt.Errorf("can't compute with invalid input: %w", &ValidationError{Errors: []error{errors.New("can't have missing required input: name")}})
+	if !cmp.Equal(errorMessage(result.Error), errorMessage(expectedError)) || len(result.Report) != 0 {
+		t.Errorf("invalid input - got: %+v %+v, want: %+v", result.Error, result.Report, expectedError)
+	}
+	var validationErr *ValidationError
+	if !errors.As(result.Error, &validationErr) {
+		t.Errorf("invalid input - got: %+v, want: a validation error", result.Error)
+	}
+}
+
+func Test_APIHandler_ServeHTTP_schema(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.DeclareInput(InputParameter{Key: "name", Type: StringInput, Required: true, Description: "name to greet"})
+	ns.DeclareInput(InputParameter{Key: "count", Type: NumberInput, Default: 1})
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	api, _ := NewAPIHandler(eng)
+
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/schema", nil))
+
+	expectedBody := `[{"key":"name","type":"string","required":true,"description":"name to greet"},{"key":"count","type":"number","default":1}]` + "\n"
+	if recorder.Code != http.StatusOK || recorder.Body.String() != expectedBody {
+		t.Errorf("got: %+v %+v, want: %+v %+v", recorder.Code, recorder.Body.String(), http.StatusOK, expectedBody)
+	}
+}
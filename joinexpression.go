@@ -0,0 +1,349 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// joinExpression is a parsed join expression of a node, who decide to compute the node
+// based on which of its incoming links have their ancestor at Continue.
+//
+// The grammar is:
+//
+//	expression := term ( "or" term )*
+//	term       := factor ( "and" factor )*
+//	factor     := "(" expression ")" | "any" | "all" | reference
+//	reference  := name [ ":" ( "true" | "false" ) ]
+//
+// where a name with spaces or parenthesis is written between single quotes.
+type joinExpression struct {
+	source string
+	root   joinTerm
+}
+
+var (
+	// joinExpressionComparator is a google/go-cmp comparator of join expressions
+	joinExpressionComparator = cmp.Comparer(func(x, y *joinExpression) bool {
+		return (x == nil && y == nil) || (x != nil && y != nil && x.source == y.source)
+	})
+)
+
+// joinLinkState hold an incoming link of a node and if its ancestor continue on it.
+type joinLinkState struct {
+	link      nodeLink
+	continued bool
+}
+
+type joinTerm interface {
+	eval(links []joinLinkState) bool
+	references() []joinReference
+}
+
+type joinOr []joinTerm
+
+func (t joinOr) eval(links []joinLinkState) bool {
+	for _, term := range t {
+		if term.eval(links) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t joinOr) references() []joinReference {
+	return joinTermsReferences(t)
+}
+
+type joinAnd []joinTerm
+
+func (t joinAnd) eval(links []joinLinkState) bool {
+	for _, term := range t {
+		if !term.eval(links) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t joinAnd) references() []joinReference {
+	return joinTermsReferences(t)
+}
+
+func joinTermsReferences(terms []joinTerm) []joinReference {
+	references := make([]joinReference, 0)
+	for _, term := range terms {
+		references = append(references, term.references()...)
+	}
+	return references
+}
+
+// joinQuantifier is satisfied by any (or all) of the incoming links.
+type joinQuantifier struct {
+	all bool
+}
+
+func (t joinQuantifier) eval(links []joinLinkState) bool {
+	for _, link := range links {
+		if link.continued != t.all {
+			return !t.all
+		}
+	}
+	return t.all
+}
+
+func (t joinQuantifier) references() []joinReference {
+	return nil
+}
+
+// joinReference is satisfied by a continued incoming link from a node (on a branch).
+type joinReference struct {
+	name   string
+	branch *bool
+}
+
+func (t joinReference) String() string {
+	if t.branch == nil {
+		return t.name
+	}
+	return fmt.Sprintf("%v:%v", t.name, *t.branch)
+}
+
+func (t joinReference) match(link nodeLink) bool {
+	return fmt.Sprint(link.From) == t.name && (t.branch == nil || t.branch == link.Branch)
+}
+
+func (t joinReference) eval(links []joinLinkState) bool {
+	for _, link := range links {
+		if link.continued && t.match(link.link) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t joinReference) references() []joinReference {
+	return []joinReference{t}
+}
+
+// parseJoinExpression parse a join expression.
+func parseJoinExpression(source string) (*joinExpression, error) {
+	tokens, err := tokenizeJoinExpression(source)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse join expression '%v': %v", source, err)
+	}
+	parser := &joinExpressionParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected '%v'", tokens[parser.position].value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse join expression '%v': %v", source, err)
+	}
+	return &joinExpression{source: source, root: root}, nil
+}
+
+type joinToken struct {
+	value  string
+	quoted bool
+}
+
+func tokenizeJoinExpression(source string) ([]joinToken, error) {
+	tokens := make([]joinToken, 0)
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, joinToken{value: string(r)})
+			i++
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("missing closing quote")
+			}
+			value := string(runes[i+1 : end])
+			end++
+			// keep the branch suffix of a quoted name
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' {
+				value += string(runes[end])
+				end++
+			}
+			tokens = append(tokens, joinToken{value: value, quoted: true})
+			i = end
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '\'' {
+				end++
+			}
+			tokens = append(tokens, joinToken{value: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type joinExpressionParser struct {
+	tokens   []joinToken
+	position int
+}
+
+func (p *joinExpressionParser) peekKeyword(keyword string) bool {
+	return p.position < len(p.tokens) && !p.tokens[p.position].quoted && p.tokens[p.position].value == keyword
+}
+
+func (p *joinExpressionParser) parseOr() (joinTerm, error) {
+	terms := make(joinOr, 0)
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.peekKeyword("or") {
+			break
+		}
+		p.position++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *joinExpressionParser) parseAnd() (joinTerm, error) {
+	terms := make(joinAnd, 0)
+	for {
+		term, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.peekKeyword("and") {
+			break
+		}
+		p.position++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *joinExpressionParser) parseFactor() (joinTerm, error) {
+	if p.position >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	token := p.tokens[p.position]
+	p.position++
+	if !token.quoted {
+		switch token.value {
+		case "(":
+			term, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.peekKeyword(")") {
+				return nil, errors.New("missing closing parenthesis")
+			}
+			p.position++
+			return term, nil
+		case ")", "and", "or":
+			return nil, fmt.Errorf("unexpected '%v'", token.value)
+		case "any":
+			return joinQuantifier{all: false}, nil
+		case "all":
+			return joinQuantifier{all: true}, nil
+		}
+	}
+
+	reference := joinReference{name: token.value}
+	if index := strings.LastIndex(token.value, ":"); index >= 0 {
+		switch token.value[index+1:] {
+		case "true":
+			reference = joinReference{name: token.value[:index], branch: boolPointer(true)}
+		case "false":
+			reference = joinReference{name: token.value[:index], branch: boolPointer(false)}
+		}
+	}
+	if reference.name == "" {
+		return nil, fmt.Errorf("missing node name in '%v'", token.value)
+	}
+	return reference, nil
+}
+
+// ConfigureJoinExpressionOnNode configure a join expression on a node into the system before activation,
+// to decide which of its incoming links need their ancestor at Continue in order to compute it.
+// e.g. "check:true and any" or "'first step' or (second and third:false)".
+// It take precedence over the join mode of the node.
+func (s *NodeSystem) ConfigureJoinExpressionOnNode(n Node, expression string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add node join expression, node system is freeze due to activation")
+	}
+	parsedExpression, err := parseJoinExpression(expression)
+	if err != nil {
+		return false, err
+	}
+	if s.nodesJoinExpressions == nil {
+		s.nodesJoinExpressions = make(map[string]*joinExpression)
+	}
+	s.nodesJoinExpressions[s.nodeID(n)] = parsedExpression
+	return true, nil
+}
+
+// JoinExpressionOfNode get the join expression of a node, if any.
+func (s *NodeSystem) JoinExpressionOfNode(n Node) (string, bool) {
+	expression, found := s.nodesJoinExpressions[s.nodeID(n)]
+	if !found {
+		return "", false
+	}
+	return expression.source, true
+}
+
+func checkForUnlinkedReferenceInJoinExpression(s *NodeSystem) []error {
+	errors := make([]error, 0)
+	for _, node := range s.nodes {
+		expression, found := s.nodesJoinExpressions[s.nodeID(node)]
+		if !found {
+			continue
+		}
+		for _, reference := range expression.root.references() {
+			linked := false
+			for _, link := range s.links {
+				if s.sameNode(link.To, node) && reference.match(link) {
+					linked = true
+					break
+				}
+			}
+			if !linked {
+				errors = append(errors, fmt.Errorf("can't have join expression on node '%v' referencing a not linked node: %v", node, reference))
+			}
+		}
+	}
+	return errors
+}
+
+// evalJoinExpression tell if a node with a join expression need to be computed,
+// once all its ancestors are computed.
+func (cp *Computation) evalJoinExpression(node Node, expression *joinExpression) bool {
+	links := make([]joinLinkState, 0)
+	for _, link := range cp.System.links {
+		if !cp.System.sameNode(link.To, node) {
+			continue
+		}
+		report, _ := cp.stateOf(link.From)
+		links = append(links, joinLinkState{
+			link:      link,
+			continued: report.Value == ContinueState && report.Branch == link.Branch,
+		})
+	}
+	return expression.root.eval(links)
+}
@@ -0,0 +1,190 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseJoinExpression(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenExpression    string
+		expectedReferences []string
+		expectedError      error
+	}{
+		{
+			name:               "Can parse references with branches",
+			givenExpression:    "check:true and (first or second:false)",
+			expectedReferences: []string{"check:true", "first", "second:false"},
+		},
+		{
+			name:               "Can parse quoted references",
+			givenExpression:    "'first step':true or 'and'",
+			expectedReferences: []string{"first step:true", "and"},
+		},
+		{
+			name:               "Can parse quantifiers",
+			givenExpression:    "check:false and any or all",
+			expectedReferences: []string{"check:false"},
+		},
+		{
+			name:            "Can't parse an empty expression",
+			givenExpression: "",
+			expectedError:   errors.New("can't parse join expression '': unexpected end of expression"),
+		},
+		{
+			name:            "Can't parse a missing closing parenthesis",
+			givenExpression: "(first or second",
+			expectedError:   errors.New("can't parse join expression '(first or second': missing closing parenthesis"),
+		},
+		{
+			name:            "Can't parse a missing closing quote",
+			givenExpression: "'first",
+			expectedError:   errors.New("can't parse join expression ''first': missing closing quote"),
+		},
+		{
+			name:            "Can't parse a missing operator",
+			givenExpression: "first second",
+			expectedError:   errors.New("can't parse join expression 'first second': unexpected 'second'"),
+		},
+		{
+			name:            "Can't parse a missing operand",
+			givenExpression: "first and or second",
+			expectedError:   errors.New("can't parse join expression 'first and or second': unexpected 'or'"),
+		},
+		{
+			name:            "Can't parse a reference without name",
+			givenExpression: ":true",
+			expectedError:   errors.New("can't parse join expression ':true': missing node name in ':true'"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expression, err := parseJoinExpression(testCase.givenExpression)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if expression != nil {
+				references := make([]string, 0)
+				for _, reference := range expression.root.references() {
+					references = append(references, reference.String())
+				}
+				if !cmp.Equal(references, testCase.expectedReferences) {
+					t.Errorf("references - got: %+v, want: %+v", references, testCase.expectedReferences)
+				}
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_IsValid_JoinExpression(t *testing.T) {
+	check, _ := NewDecisionNode("check", func(*Context) (bool, error) { return true, nil })
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	join, _ := NewActionNode("join", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name            string
+		givenExpression string
+		expectedErrors  []error
+	}{
+		{
+			name:            "Can have a join expression on linked nodes",
+			givenExpression: "check:true and action",
+		},
+		{
+			name:            "Can't have a join expression on a not linked branch",
+			givenExpression: "check:false and action",
+			expectedErrors: []error{
+				errors.New("can't have join expression on node 'join' referencing a not linked node: check:false"),
+			},
+		},
+		{
+			name:            "Can't have a join expression on a not linked node",
+			givenExpression: "check or unknown",
+			expectedErrors: []error{
+				errors.New("can't have join expression on node 'join' referencing a not linked node: unknown"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(check)
+			ns.AddNode(action)
+			ns.AddNode(join)
+			ns.AddLinkOnBranch(check, action, false)
+			ns.AddLinkOnBranch(check, join, true)
+			ns.AddLink(action, join)
+			ns.ConfigureJoinExpressionOnNode(join, testCase.givenExpression)
+
+			_, validationErr := ns.IsValid()
+
+			errs := validationErrors(validationErr)
+
+			if !cmp.Equal(errs, testCase.expectedErrors, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", errs, testCase.expectedErrors)
+			}
+		})
+	}
+}
+
+func Test_Computation_Compute_JoinExpression(t *testing.T) {
+	check, _ := NewDecisionNode("check", func(c *Context) (bool, error) {
+		return c.HaveKey("valid"), nil
+	})
+	first, _ := NewActionNode("first", func(*Context) error { return nil })
+	second, _ := NewDecisionNode("second", func(c *Context) (bool, error) {
+		return c.HaveKey("second"), nil
+	})
+	join, _ := NewActionNode("join", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name          string
+		givenData     map[string]interface{}
+		expectedState ComputeState
+	}{
+		{
+			name:          "Compute node when the expression is satisfied",
+			givenData:     map[string]interface{}{"valid": true, "second": true},
+			expectedState: NewContinueComputeState(),
+		},
+		{
+			name:          "Compute node when the expression is satisfied by any other link",
+			givenData:     map[string]interface{}{"valid": true},
+			expectedState: NewContinueComputeState(),
+		},
+		{
+			name:          "Skip node when the required branch is not taken",
+			givenData:     map[string]interface{}{"second": true},
+			expectedState: NewSkipComputeState(),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(check)
+			ns.AddNode(first)
+			ns.AddNode(second)
+			ns.AddNode(join)
+			ns.AddLinkOnBranch(check, join, true)
+			ns.AddLinkOnBranch(check, first, false)
+			ns.AddLink(first, join)
+			ns.AddLinkOnBranch(second, join, true)
+			ns.ConfigureJoinExpressionOnNode(join, "check:true and any")
+			err := ns.ActivateInPlace()
+			if err != nil {
+				t.Fatalf("activate - got: %+v, want: %+v", err, nil)
+			}
+
+			cp, _ := NewComputation(ns, NewContext(testCase.givenData))
+			cp.Compute()
+
+			if !cmp.Equal(cp.Report[join], testCase.expectedState) {
+				t.Errorf("got: %+v, want: %+v", cp.Report[join], testCase.expectedState)
+			}
+		})
+	}
+}
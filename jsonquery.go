@@ -0,0 +1,939 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// JSONQuery is a parsed jq-like query over a JSON value, who give a stream of values.
+//
+// The supported syntax is:
+//
+//	.                   the value itself
+//	.name ."name"       the field of an object (null if missing)
+//	.[n] .["name"]      the item of an array (a negative index count from the end), or the field of an object
+//	.[n:m]              the slice of an array or a string
+//	.[]                 each item of an array, or each value of an object
+//	?                   after a field, index, or iteration, ignore the errors
+//	a | b               the query b on each value of a
+//	a , b               the values of a, then the values of b
+//	[ a ]               an array of the values of a
+//	{ k: a, "k": a, k } an object of the values of a, or of the field k
+//	== != < <= > >=     comparisons, with "and", "or"
+//	length keys first last type not
+//	select(a) map(a) has(k)
+//	literals: numbers, strings, true, false, null
+type JSONQuery struct {
+	source string
+	root   queryNode
+}
+
+type queryNode interface {
+	eval(input interface{}) ([]interface{}, error)
+}
+
+// ParseJSONQuery parse a jq-like query.
+func ParseJSONQuery(source string) (*JSONQuery, error) {
+	tokens, err := tokenizeJSONQuery(source)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse query '%v': %v", source, err)
+	}
+	parser := &jsonQueryParser{tokens: tokens}
+	root, err := parser.parsePipe()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected '%v'", tokens[parser.position].value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse query '%v': %v", source, err)
+	}
+	return &JSONQuery{source: source, root: root}, nil
+}
+
+func (q *JSONQuery) String() string {
+	return q.source
+}
+
+// Run apply the query on a value, who is first converted to its JSON representation
+// (a []byte or json.RawMessage value is decoded as JSON).
+func (q *JSONQuery) Run(value interface{}) ([]interface{}, error) {
+	input, err := toJSONValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("can't query '%v': %v", q.source, err)
+	}
+	results, err := q.root.eval(input)
+	if err != nil {
+		return nil, fmt.Errorf("can't query '%v': %v", q.source, err)
+	}
+	return results, nil
+}
+
+// toJSONValue give the JSON representation of a value, with float64 numbers,
+// []interface{} arrays, and map[string]interface{} objects.
+func toJSONValue(value interface{}) (interface{}, error) {
+	var encoded []byte
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return v, nil
+	case json.RawMessage:
+		encoded = v
+	case []byte:
+		encoded = v
+	default:
+		var err error
+		encoded, err = json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var decoded interface{}
+	err := json.Unmarshal(encoded, &decoded)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+type queryIdentity struct{}
+
+func (queryIdentity) eval(input interface{}) ([]interface{}, error) {
+	return []interface{}{input}, nil
+}
+
+type queryLiteral struct {
+	value interface{}
+}
+
+func (n queryLiteral) eval(interface{}) ([]interface{}, error) {
+	return []interface{}{n.value}, nil
+}
+
+type queryPipe struct {
+	left, right queryNode
+}
+
+func (n queryPipe) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0)
+	for _, left := range lefts {
+		rights, err := n.right.eval(left)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, rights...)
+	}
+	return results, nil
+}
+
+type queryComma struct {
+	left, right queryNode
+}
+
+func (n queryComma) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	return append(lefts, rights...), nil
+}
+
+// queryIndex access a field, an item, or a slice of the values of its target.
+type queryIndex struct {
+	target   queryNode
+	index    queryNode
+	sliceEnd queryNode
+	slice    bool
+	optional bool
+}
+
+func (n queryIndex) eval(input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0, len(targets))
+	for _, target := range targets {
+		var values []interface{}
+		if n.slice {
+			values, err = n.evalSlice(input, target)
+		} else {
+			values, err = n.evalIndex(input, target)
+		}
+		if err != nil {
+			if n.optional {
+				continue
+			}
+			return nil, err
+		}
+		results = append(results, values...)
+	}
+	return results, nil
+}
+
+func (n queryIndex) evalIndex(input, target interface{}) ([]interface{}, error) {
+	indexes, err := n.index.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0, len(indexes))
+	for _, index := range indexes {
+		value, err := indexJSONValue(target, index)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, value)
+	}
+	return results, nil
+}
+
+func (n queryIndex) evalSlice(input, target interface{}) ([]interface{}, error) {
+	start, end := 0.0, math.Inf(1)
+	if n.index != nil {
+		value, err := singleQueryValue(n.index, input)
+		if err != nil {
+			return nil, err
+		}
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("can't slice with %v", formatJSONValue(value))
+		}
+		start = number
+	}
+	if n.sliceEnd != nil {
+		value, err := singleQueryValue(n.sliceEnd, input)
+		if err != nil {
+			return nil, err
+		}
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("can't slice with %v", formatJSONValue(value))
+		}
+		end = number
+	}
+	switch v := target.(type) {
+	case nil:
+		return []interface{}{nil}, nil
+	case []interface{}:
+		from, to := sliceBounds(start, end, len(v))
+		return []interface{}{append([]interface{}(nil), v[from:to]...)}, nil
+	case string:
+		runes := []rune(v)
+		from, to := sliceBounds(start, end, len(runes))
+		return []interface{}{string(runes[from:to])}, nil
+	}
+	return nil, fmt.Errorf("can't slice %v", formatJSONValue(target))
+}
+
+func sliceBounds(start, end float64, length int) (int, int) {
+	bound := func(value float64) int {
+		if value < 0 {
+			value += float64(length)
+		}
+		return int(math.Max(0, math.Min(float64(length), math.Floor(value))))
+	}
+	from, to := bound(start), bound(end)
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+func indexJSONValue(target, index interface{}) (interface{}, error) {
+	switch v := target.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		if name, ok := index.(string); ok {
+			return v[name], nil
+		}
+	case []interface{}:
+		if number, ok := index.(float64); ok {
+			position := int(math.Floor(number))
+			if position < 0 {
+				position += len(v)
+			}
+			if position < 0 || position >= len(v) {
+				return nil, nil
+			}
+			return v[position], nil
+		}
+	}
+	return nil, fmt.Errorf("can't index %v with %v", formatJSONValue(target), formatJSONValue(index))
+}
+
+type queryIterate struct {
+	target   queryNode
+	optional bool
+}
+
+func (n queryIterate) eval(input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0)
+	for _, target := range targets {
+		switch v := target.(type) {
+		case []interface{}:
+			results = append(results, v...)
+		case map[string]interface{}:
+			for _, key := range sortedJSONKeys(v) {
+				results = append(results, v[key])
+			}
+		default:
+			if !n.optional {
+				return nil, fmt.Errorf("can't iterate over %v", formatJSONValue(target))
+			}
+		}
+	}
+	return results, nil
+}
+
+type queryArray struct {
+	items queryNode
+}
+
+func (n queryArray) eval(input interface{}) ([]interface{}, error) {
+	if n.items == nil {
+		return []interface{}{[]interface{}{}}, nil
+	}
+	items, err := n.items.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{append([]interface{}{}, items...)}, nil
+}
+
+type queryObjectEntry struct {
+	key   string
+	value queryNode
+}
+
+type queryObject struct {
+	entries []queryObjectEntry
+}
+
+func (n queryObject) eval(input interface{}) ([]interface{}, error) {
+	objects := []map[string]interface{}{{}}
+	for _, entry := range n.entries {
+		values, err := entry.value.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		combined := make([]map[string]interface{}, 0, len(objects)*len(values))
+		for _, object := range objects {
+			for _, value := range values {
+				copied := make(map[string]interface{}, len(object)+1)
+				for key, existing := range object {
+					copied[key] = existing
+				}
+				copied[entry.key] = value
+				combined = append(combined, copied)
+			}
+		}
+		objects = combined
+	}
+	results := make([]interface{}, 0, len(objects))
+	for _, object := range objects {
+		results = append(results, object)
+	}
+	return results, nil
+}
+
+type queryBinary struct {
+	operator    string
+	left, right queryNode
+}
+
+func (n queryBinary) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, 0, len(lefts))
+	for _, left := range lefts {
+		if n.operator == "and" && !isJSONTruthy(left) {
+			results = append(results, false)
+			continue
+		}
+		if n.operator == "or" && isJSONTruthy(left) {
+			results = append(results, true)
+			continue
+		}
+		rights, err := n.right.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, right := range rights {
+			switch n.operator {
+			case "and", "or":
+				results = append(results, isJSONTruthy(right))
+			case "==":
+				results = append(results, reflect.DeepEqual(left, right))
+			case "!=":
+				results = append(results, !reflect.DeepEqual(left, right))
+			default:
+				result, err := compareExprValues(n.operator, left, right)
+				if err != nil {
+					return nil, fmt.Errorf("can't compare %v with %v", formatJSONValue(left), formatJSONValue(right))
+				}
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+type queryFunction struct {
+	name     string
+	argument queryNode
+}
+
+// jsonQueryFunctions are the functions of the queries, with if they need an argument.
+var jsonQueryFunctions = map[string]bool{
+	"length": false,
+	"keys":   false,
+	"first":  false,
+	"last":   false,
+	"type":   false,
+	"not":    false,
+	"select": true,
+	"map":    true,
+	"has":    true,
+}
+
+func (n queryFunction) eval(input interface{}) ([]interface{}, error) {
+	switch n.name {
+	case "select":
+		conditions, err := n.argument.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]interface{}, 0)
+		for _, condition := range conditions {
+			if isJSONTruthy(condition) {
+				results = append(results, input)
+			}
+		}
+		return results, nil
+	case "map":
+		return queryArray{items: queryPipe{left: queryIterate{target: queryIdentity{}}, right: n.argument}}.eval(input)
+	case "has":
+		keys, err := n.argument.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			switch v := input.(type) {
+			case map[string]interface{}:
+				if name, ok := key.(string); ok {
+					_, found := v[name]
+					results = append(results, found)
+					continue
+				}
+			case []interface{}:
+				if number, ok := key.(float64); ok {
+					results = append(results, number >= 0 && int(number) < len(v))
+					continue
+				}
+			}
+			return nil, fmt.Errorf("can't check if %v has %v", formatJSONValue(input), formatJSONValue(key))
+		}
+		return results, nil
+	case "not":
+		return []interface{}{!isJSONTruthy(input)}, nil
+	case "type":
+		return []interface{}{jsonType(input)}, nil
+	case "first", "last":
+		list, ok := input.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("can't get %v of %v", n.name, formatJSONValue(input))
+		}
+		if len(list) == 0 {
+			return []interface{}{nil}, nil
+		}
+		if n.name == "first" {
+			return []interface{}{list[0]}, nil
+		}
+		return []interface{}{list[len(list)-1]}, nil
+	case "keys":
+		switch v := input.(type) {
+		case map[string]interface{}:
+			keys := make([]interface{}, 0, len(v))
+			for _, key := range sortedJSONKeys(v) {
+				keys = append(keys, key)
+			}
+			return []interface{}{keys}, nil
+		case []interface{}:
+			keys := make([]interface{}, 0, len(v))
+			for i := range v {
+				keys = append(keys, float64(i))
+			}
+			return []interface{}{keys}, nil
+		}
+		return nil, fmt.Errorf("can't get keys of %v", formatJSONValue(input))
+	}
+	switch v := input.(type) {
+	case nil:
+		return []interface{}{0.0}, nil
+	case float64:
+		return []interface{}{math.Abs(v)}, nil
+	case string:
+		return []interface{}{float64(len([]rune(v)))}, nil
+	case []interface{}:
+		return []interface{}{float64(len(v))}, nil
+	case map[string]interface{}:
+		return []interface{}{float64(len(v))}, nil
+	}
+	return nil, fmt.Errorf("can't get length of %v", formatJSONValue(input))
+}
+
+func singleQueryValue(node queryNode, input interface{}) (interface{}, error) {
+	values, err := node.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("can't use %v values as one", len(values))
+	}
+	return values[0], nil
+}
+
+func isJSONTruthy(value interface{}) bool {
+	return value != nil && value != false
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+func formatJSONValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return fmt.Sprintf("%v (%v)", string(encoded), jsonType(value))
+}
+
+func sortedJSONKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type queryTokenKind int
+
+const (
+	queryOperator queryTokenKind = iota
+	queryName
+	queryNumber
+	queryString
+)
+
+type queryToken struct {
+	kind  queryTokenKind
+	value string
+	// spaced tell if the token follow a space
+	spaced bool
+}
+
+// jsonQueryOperators are the operators and punctuations, the longest first.
+var jsonQueryOperators = []string{"==", "!=", "<=", ">=", "<", ">", "|", ",", ".", "[", "]", "{", "}", "(", ")", ":", "?", "-"}
+
+func tokenizeJSONQuery(source string) ([]queryToken, error) {
+	tokens := make([]queryToken, 0)
+	runes := []rune(source)
+	spaced := false
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			spaced = true
+			i++
+			continue
+		}
+		start := len(tokens)
+		switch {
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, errors.New("missing closing quote")
+			}
+			value, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %v", string(runes[i:end+1]))
+			}
+			tokens = append(tokens, queryToken{kind: queryString, value: value})
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.' || runes[end] == 'e' || runes[end] == 'E') {
+				end++
+			}
+			tokens = append(tokens, queryToken{kind: queryNumber, value: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, queryToken{kind: queryName, value: string(runes[i:end])})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range jsonQueryOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected '%v'", string(r))
+			}
+			tokens = append(tokens, queryToken{kind: queryOperator, value: operator})
+			i += len(operator)
+		}
+		tokens[start].spaced = spaced
+		spaced = false
+	}
+	return tokens, nil
+}
+
+type jsonQueryParser struct {
+	tokens   []queryToken
+	position int
+}
+
+func (p *jsonQueryParser) peek(kind queryTokenKind, values ...string) (string, bool) {
+	if p.position >= len(p.tokens) || p.tokens[p.position].kind != kind {
+		return "", false
+	}
+	for _, value := range values {
+		if p.tokens[p.position].value == value {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func (p *jsonQueryParser) expect(operator string) error {
+	if _, found := p.peek(queryOperator, operator); !found {
+		if p.position >= len(p.tokens) {
+			return fmt.Errorf("missing '%v'", operator)
+		}
+		return fmt.Errorf("unexpected '%v'", p.tokens[p.position].value)
+	}
+	p.position++
+	return nil
+}
+
+func (p *jsonQueryParser) parsePipe() (queryNode, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, found := p.peek(queryOperator, "|"); !found {
+			return left, nil
+		}
+		p.position++
+		right, err := p.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		left = queryPipe{left: left, right: right}
+	}
+}
+
+func (p *jsonQueryParser) parseComma() (queryNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, found := p.peek(queryOperator, ","); !found {
+			return left, nil
+		}
+		p.position++
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = queryComma{left: left, right: right}
+	}
+}
+
+func (p *jsonQueryParser) parseOr() (queryNode, error) {
+	return p.parseLogical("or", p.parseAnd)
+}
+
+func (p *jsonQueryParser) parseAnd() (queryNode, error) {
+	return p.parseLogical("and", p.parseComparison)
+}
+
+func (p *jsonQueryParser) parseLogical(operator string, next func() (queryNode, error)) (queryNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, found := p.peek(queryName, operator); !found {
+			return left, nil
+		}
+		p.position++
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = queryBinary{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *jsonQueryParser) parseComparison() (queryNode, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	operator, found := p.peek(queryOperator, "==", "!=", "<=", ">=", "<", ">")
+	if !found {
+		return left, nil
+	}
+	p.position++
+	right, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	return queryBinary{operator: operator, left: left, right: right}, nil
+}
+
+func (p *jsonQueryParser) parsePostfix() (queryNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peekValue(queryOperator, "."):
+			if p.position+1 < len(p.tokens) && p.tokens[p.position+1].kind == queryOperator && p.tokens[p.position+1].value == "[" {
+				p.position++
+				continue
+			}
+			p.position++
+			name, err := p.parseFieldName()
+			if err != nil {
+				return nil, err
+			}
+			node = queryIndex{target: node, index: queryLiteral{value: name}}
+		case p.peekValue(queryOperator, "["):
+			p.position++
+			node, err = p.parseBracket(node)
+			if err != nil {
+				return nil, err
+			}
+		case p.peekValue(queryOperator, "?"):
+			p.position++
+			switch n := node.(type) {
+			case queryIndex:
+				n.optional = true
+				node = n
+			case queryIterate:
+				n.optional = true
+				node = n
+			default:
+				return nil, errors.New("unexpected '?'")
+			}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *jsonQueryParser) peekValue(kind queryTokenKind, value string) bool {
+	_, found := p.peek(kind, value)
+	return found
+}
+
+func (p *jsonQueryParser) parseFieldName() (string, error) {
+	if p.position >= len(p.tokens) {
+		return "", errors.New("missing field name")
+	}
+	token := p.tokens[p.position]
+	if token.kind != queryName && token.kind != queryString {
+		return "", fmt.Errorf("unexpected '%v'", token.value)
+	}
+	p.position++
+	return token.value, nil
+}
+
+// parseBracket parse an iteration, an index, or a slice, after its opening bracket.
+func (p *jsonQueryParser) parseBracket(target queryNode) (queryNode, error) {
+	if p.peekValue(queryOperator, "]") {
+		p.position++
+		return queryIterate{target: target}, nil
+	}
+	index := queryIndex{target: target}
+	if !p.peekValue(queryOperator, ":") {
+		start, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		index.index = start
+	}
+	if p.peekValue(queryOperator, ":") {
+		p.position++
+		index.slice = true
+		if !p.peekValue(queryOperator, "]") {
+			end, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			index.sliceEnd = end
+		}
+	}
+	return index, p.expect("]")
+}
+
+func (p *jsonQueryParser) parsePrimary() (queryNode, error) {
+	if p.position >= len(p.tokens) {
+		return nil, errors.New("unexpected end of query")
+	}
+	token := p.tokens[p.position]
+	p.position++
+	switch token.kind {
+	case queryNumber:
+		number, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%v'", token.value)
+		}
+		return queryLiteral{value: number}, nil
+	case queryString:
+		return queryLiteral{value: token.value}, nil
+	case queryName:
+		return p.parseName(token.value)
+	}
+	switch token.value {
+	case ".":
+		if (p.peekKind(queryName) || p.peekKind(queryString)) && !p.tokens[p.position].spaced {
+			name, _ := p.parseFieldName()
+			return queryIndex{target: queryIdentity{}, index: queryLiteral{value: name}}, nil
+		}
+		return queryIdentity{}, nil
+	case "-":
+		if p.peekKind(queryNumber) {
+			literal, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return queryLiteral{value: -literal.(queryLiteral).value.(float64)}, nil
+		}
+	case "(":
+		node, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	case "[":
+		if p.peekValue(queryOperator, "]") {
+			p.position++
+			return queryArray{}, nil
+		}
+		items, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return queryArray{items: items}, p.expect("]")
+	case "{":
+		return p.parseObject()
+	}
+	return nil, fmt.Errorf("unexpected '%v'", token.value)
+}
+
+func (p *jsonQueryParser) peekKind(kind queryTokenKind) bool {
+	return p.position < len(p.tokens) && p.tokens[p.position].kind == kind
+}
+
+func (p *jsonQueryParser) parseName(name string) (queryNode, error) {
+	switch name {
+	case "true", "false":
+		return queryLiteral{value: name == "true"}, nil
+	case "null":
+		return queryLiteral{}, nil
+	}
+	needArgument, known := jsonQueryFunctions[name]
+	if !known {
+		return nil, fmt.Errorf("unknown function '%v'", name)
+	}
+	if !needArgument {
+		return queryFunction{name: name}, nil
+	}
+	err := p.expect("(")
+	if err != nil {
+		return nil, fmt.Errorf("function '%v' need an argument", name)
+	}
+	argument, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	return queryFunction{name: name, argument: argument}, p.expect(")")
+}
+
+func (p *jsonQueryParser) parseObject() (queryNode, error) {
+	object := queryObject{}
+	if p.peekValue(queryOperator, "}") {
+		p.position++
+		return object, nil
+	}
+	for {
+		key, err := p.parseFieldName()
+		if err != nil {
+			return nil, err
+		}
+		var value queryNode = queryIndex{target: queryIdentity{}, index: queryLiteral{value: key}}
+		if p.peekValue(queryOperator, ":") {
+			p.position++
+			value, err = p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+		}
+		object.entries = append(object.entries, queryObjectEntry{key: key, value: value})
+		if !p.peekValue(queryOperator, ",") {
+			return object, p.expect("}")
+		}
+		p.position++
+	}
+}
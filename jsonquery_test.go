@@ -0,0 +1,150 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ParseJSONQuery(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenSource   string
+		expectedError error
+	}{
+		{
+			name:        "Can parse a query",
+			givenSource: `.items[] | select(.price > 10 and .tags[0] == "new") | {id, total: .price}`,
+		},
+		{
+			name:          "Can't parse a query with missing bracket",
+			givenSource:   `.items[0`,
+			expectedError: errors.New("can't parse query '.items[0': missing ']'"),
+		},
+		{
+			name:          "Can't parse a query with unknown function",
+			givenSource:   `.items | sort`,
+			expectedError: errors.New("can't parse query '.items | sort': unknown function 'sort'"),
+		},
+		{
+			name:          "Can't parse a query with function without argument",
+			givenSource:   `select`,
+			expectedError: errors.New("can't parse query 'select': function 'select' need an argument"),
+		},
+		{
+			name:          "Can't parse a query with missing closing quote",
+			givenSource:   `."name`,
+			expectedError: errors.New("can't parse query '.\"name': missing closing quote"),
+		},
+		{
+			name:          "Can't parse a query with unexpected character",
+			givenSource:   `.a + .b`,
+			expectedError: errors.New("can't parse query '.a + .b': unexpected '+'"),
+		},
+		{
+			name:          "Can't parse an empty query",
+			givenSource:   ``,
+			expectedError: errors.New("can't parse query '': unexpected end of query"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query, err := ParseJSONQuery(testCase.givenSource)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && query.String() != testCase.givenSource {
+				t.Errorf("source - got: %+v, want: %+v", query.String(), testCase.givenSource)
+			}
+		})
+	}
+}
+
+func Test_JSONQuery_Run(t *testing.T) {
+	response := []byte(`{
+  "status": "ok",
+  "user": {"name": "bob", "roles": ["dev", "ops"]},
+  "items": [
+    {"id": 1, "price": 5, "tags": ["old"]},
+    {"id": 2, "price": 20, "tags": ["new", "promo"]},
+    {"id": 3, "price": 15, "tags": ["new"]}
+  ]
+}`)
+
+	testCases := []struct {
+		name            string
+		givenQuery      string
+		givenValue      interface{}
+		expectedResults []interface{}
+		expectedError   error
+	}{
+		{name: "Can get the value", givenQuery: `.`, givenValue: "text", expectedResults: []interface{}{"text"}},
+		{name: "Can get a field", givenQuery: `.user.name`, givenValue: response, expectedResults: []interface{}{"bob"}},
+		{name: "Can get a quoted field", givenQuery: `."user"["name"]`, givenValue: response, expectedResults: []interface{}{"bob"}},
+		{name: "Can get a missing field as null", givenQuery: `.user.team.name`, givenValue: response, expectedResults: []interface{}{nil}},
+		{name: "Can get an item", givenQuery: `.items[1].id`, givenValue: response, expectedResults: []interface{}{2.0}},
+		{name: "Can get an item from the end", givenQuery: `.user.roles[-1]`, givenValue: response, expectedResults: []interface{}{"ops"}},
+		{name: "Can get a missing item as null", givenQuery: `.user.roles[5]`, givenValue: response, expectedResults: []interface{}{nil}},
+		{name: "Can slice an array", givenQuery: `[.items[1:] | .[].id]`, givenValue: response, expectedResults: []interface{}{[]interface{}{2.0, 3.0}}},
+		{name: "Can slice a string", givenQuery: `.status[:1]`, givenValue: response, expectedResults: []interface{}{"o"}},
+		{name: "Can iterate", givenQuery: `.items[].id`, givenValue: response, expectedResults: []interface{}{1.0, 2.0, 3.0}},
+		{name: "Can iterate over object values by key", givenQuery: `.[]`, givenValue: map[string]int{"b": 2, "a": 1}, expectedResults: []interface{}{1.0, 2.0}},
+		{name: "Can collect", givenQuery: `[.items[] | select(.price > 10) | .id]`, givenValue: response, expectedResults: []interface{}{[]interface{}{2.0, 3.0}}},
+		{name: "Can map", givenQuery: `.items | map(.tags | length)`, givenValue: response, expectedResults: []interface{}{[]interface{}{1.0, 2.0, 1.0}}},
+		{name: "Can select with logical operators", givenQuery: `[.items[] | select(.price >= 15 and (.tags | has(1)) or .id == 1) | .id]`, givenValue: response, expectedResults: []interface{}{[]interface{}{1.0, 2.0}}},
+		{name: "Can build an object", givenQuery: `.items[0] | {id, "cost": .price, first: (.tags | first)}`, givenValue: response, expectedResults: []interface{}{map[string]interface{}{"id": 1.0, "cost": 5.0, "first": "old"}}},
+		{name: "Can build values with comma", givenQuery: `.status, .user.name`, givenValue: response, expectedResults: []interface{}{"ok", "bob"}},
+		{name: "Can get keys, length, and type", givenQuery: `(.user | keys), (.items | length), (.status | type), (.user.roles | last)`, givenValue: response, expectedResults: []interface{}{[]interface{}{"name", "roles"}, 3.0, "string", "ops"}},
+		{name: "Can negate", givenQuery: `.user.team | not`, givenValue: response, expectedResults: []interface{}{true}},
+		{name: "Can query a go value", givenQuery: `.Name`, givenValue: struct{ Name string }{Name: "bob"}, expectedResults: []interface{}{"bob"}},
+		{name: "Can query a raw message", givenQuery: `.[0]`, givenValue: json.RawMessage(`[-1.5]`), expectedResults: []interface{}{-1.5}},
+		{name: "Can ignore errors", givenQuery: `[.items[]?, .status[]?, .status.name?]`, givenValue: response, expectedResults: []interface{}{[]interface{}{
+			map[string]interface{}{"id": 1.0, "price": 5.0, "tags": []interface{}{"old"}},
+			map[string]interface{}{"id": 2.0, "price": 20.0, "tags": []interface{}{"new", "promo"}},
+			map[string]interface{}{"id": 3.0, "price": 15.0, "tags": []interface{}{"new"}},
+		}}},
+		{
+			name:          "Can't index a string",
+			givenQuery:    `.status.name`,
+			givenValue:    response,
+			expectedError: errors.New(`can't query '.status.name': can't index "ok" (string) with "name" (string)`),
+		},
+		{
+			name:          "Can't iterate over a number",
+			givenQuery:    `.items[0].price[]`,
+			givenValue:    response,
+			expectedError: errors.New(`can't query '.items[0].price[]': can't iterate over 5 (number)`),
+		},
+		{
+			name:          "Can't compare values of different types",
+			givenQuery:    `.status > 1`,
+			givenValue:    response,
+			expectedError: errors.New(`can't query '.status > 1': can't compare "ok" (string) with 1 (number)`),
+		},
+		{
+			name:          "Can't query invalid JSON",
+			givenQuery:    `.`,
+			givenValue:    []byte(`{`),
+			expectedError: errors.New(`can't query '.': unexpected end of JSON input`),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query, err := ParseJSONQuery(testCase.givenQuery)
+			if err != nil {
+				t.Fatalf("parse error - got: %+v, want: %+v", err, nil)
+			}
+			results, err := query.Run(testCase.givenValue)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(results, testCase.expectedResults) {
+				t.Errorf("results - got: %+v, want: %+v", results, testCase.expectedResults)
+			}
+		})
+	}
+}
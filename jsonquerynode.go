@@ -0,0 +1,69 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// JSONQueryNode is a type of Node who apply a jq-like query (see JSONQuery) on a context value,
+// like an API response, and store its result into another context key.
+// A string or bytes value is queried as a JSON document.
+// The query must give one value, use [ .. ] to collect several values.
+type JSONQueryNode struct {
+	name   string
+	query  *JSONQuery
+	source string
+	target string
+}
+
+func (n JSONQueryNode) String() string {
+	return n.name
+}
+
+// Compute run the query on the source value and store its result in the target key.
+func (n *JSONQueryNode) Compute(c *Context) ComputeState {
+	value, found := c.Read(n.source)
+	if !found {
+		return NewAbortComputeState(fmt.Errorf("can't query missing key: %v", n.source))
+	}
+	if text, ok := value.(string); ok {
+		value = []byte(text)
+	}
+	results, err := n.query.Run(value)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	if len(results) != 1 {
+		return NewAbortComputeState(fmt.Errorf("can't store %v values of query '%v' into '%v', collect them with [ .. ]", len(results), n.query, n.target))
+	}
+	c.Store(n.target, results[0])
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a query don't take a decision.
+func (n *JSONQueryNode) DecideCapability() bool {
+	return false
+}
+
+// RequiredKeys give the queried context key.
+func (n *JSONQueryNode) RequiredKeys() []string {
+	return []string{n.source}
+}
+
+// ProducedKeys give the context key of the query result.
+func (n *JSONQueryNode) ProducedKeys() []string {
+	return []string{n.target}
+}
+
+// NewJSONQueryNode create a JSONQueryNode based on a name, a jq-like query parsed at creation,
+// the context key of the queried value, and the context key of the result.
+func NewJSONQueryNode(name, query, source, target string) (*JSONQueryNode, error) {
+	if source == "" || target == "" {
+		return nil, errors.New("can't create json query node without source and target keys")
+	}
+	parsed, err := ParseJSONQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONQueryNode{name: name, query: parsed, source: source, target: target}, nil
+}
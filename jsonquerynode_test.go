@@ -0,0 +1,126 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewJSONQueryNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenQuery    string
+		givenSource   string
+		givenTarget   string
+		expectedError error
+	}{
+		{
+			name:        "Can create a json query node",
+			givenQuery:  ".user.id",
+			givenSource: "response",
+			givenTarget: "user_id",
+		},
+		{
+			name:          "Can't create a json query node without source key",
+			givenQuery:    ".user.id",
+			givenTarget:   "user_id",
+			expectedError: errors.New("can't create json query node without source and target keys"),
+		},
+		{
+			name:          "Can't create a json query node without target key",
+			givenQuery:    ".user.id",
+			givenSource:   "response",
+			expectedError: errors.New("can't create json query node without source and target keys"),
+		},
+		{
+			name:          "Can't create a json query node with invalid query",
+			givenQuery:    ".user[",
+			givenSource:   "response",
+			givenTarget:   "user_id",
+			expectedError: errors.New("can't parse query '.user[': unexpected end of query"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewJSONQueryNode("query", testCase.givenQuery, testCase.givenSource, testCase.givenTarget)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && (!cmp.Equal(node.RequiredKeys(), []string{testCase.givenSource}) || !cmp.Equal(node.ProducedKeys(), []string{testCase.givenTarget})) {
+				t.Errorf("keys - got: %+v %+v, want: %+v %+v", node.RequiredKeys(), node.ProducedKeys(), testCase.givenSource, testCase.givenTarget)
+			}
+		})
+	}
+}
+
+func Test_JSONQueryNode_Compute(t *testing.T) {
+	response := []byte(`{"user": {"id": 42, "roles": ["dev", "ops"]}}`)
+
+	testCases := []struct {
+		name          string
+		givenQuery    string
+		givenData     map[string]interface{}
+		expectedState ComputeState
+		expectedData  map[string]interface{}
+	}{
+		{
+			name:          "Can store the result of a query",
+			givenQuery:    ".user.id",
+			givenData:     map[string]interface{}{"response": response},
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"response": response, "result": 42.0},
+		},
+		{
+			name:          "Can store the result of a query on a string",
+			givenQuery:    ".user.roles[0]",
+			givenData:     map[string]interface{}{"response": string(response)},
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"response": string(response), "result": "dev"},
+		},
+		{
+			name:          "Can store collected results of a query",
+			givenQuery:    "[.user.roles[]]",
+			givenData:     map[string]interface{}{"response": response},
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"response": response, "result": []interface{}{"dev", "ops"}},
+		},
+		{
+			name:          "Can't store several results of a query",
+			givenQuery:    ".user.roles[]",
+			givenData:     map[string]interface{}{"response": response},
+			expectedState: NewAbortComputeState(errors.New("can't store 2 values of query '.user.roles[]' into 'result', collect them with [ .. ]")),
+			expectedData:  map[string]interface{}{"response": response},
+		},
+		{
+			name:          "Can't query a missing key",
+			givenQuery:    ".user.id",
+			givenData:     map[string]interface{}{},
+			expectedState: NewAbortComputeState(errors.New("can't query missing key: response")),
+			expectedData:  map[string]interface{}{},
+		},
+		{
+			name:          "Can't store the result of a failing query",
+			givenQuery:    ".user.id[0]",
+			givenData:     map[string]interface{}{"response": response},
+			expectedState: NewAbortComputeState(errors.New("can't query '.user.id[0]': can't index 42 (number) with 0 (number)")),
+			expectedData:  map[string]interface{}{"response": response},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, _ := NewJSONQueryNode("query", testCase.givenQuery, "response", "result")
+			c := NewContext(testCase.givenData)
+
+			state := node.Compute(c)
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
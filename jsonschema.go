@@ -0,0 +1,420 @@
+package hoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchema is a JSON Schema validating a JSON value, with the keywords:
+// type, enum, const, properties, required, additionalProperties, items, minItems, maxItems, uniqueItems,
+// minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+// allOf, anyOf, oneOf, and not. The references ($ref) are not supported.
+type JSONSchema struct {
+	source string
+	root   *schemaNode
+}
+
+// SchemaViolation is a violation of a JSON Schema by a value,
+// the path is a JSON Pointer to the invalid part of the value (” for the value itself).
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%v: %v", v.Path, v.Message)
+}
+
+// schemaNode is a compiled schema, a nil keyword is absent.
+type schemaNode struct {
+	alwaysValid *bool
+
+	types   []string
+	enum    []interface{}
+	constV  *interface{}
+	pattern *regexp.Regexp
+
+	properties           map[string]*schemaNode
+	required             []string
+	additionalProperties *schemaNode
+	items                *schemaNode
+	allOf                []*schemaNode
+	anyOf                []*schemaNode
+	oneOf                []*schemaNode
+	not                  *schemaNode
+
+	minItems, maxItems, minLength, maxLength             *int
+	minimum, maximum, exclusiveMinimum, exclusiveMaximum *float64
+	multipleOf                                           *float64
+	uniqueItems                                          bool
+}
+
+var schemaTypes = map[string]bool{"null": true, "boolean": true, "number": true, "integer": true, "string": true, "array": true, "object": true}
+
+// ParseJSONSchema compile a JSON Schema.
+func ParseJSONSchema(source []byte) (*JSONSchema, error) {
+	var decoded interface{}
+	err := json.Unmarshal(source, &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse json schema: %v", err)
+	}
+	root, err := compileSchema(decoded, "")
+	if err != nil {
+		return nil, fmt.Errorf("can't parse json schema: %v", err)
+	}
+	return &JSONSchema{source: string(source), root: root}, nil
+}
+
+func (s *JSONSchema) String() string {
+	return s.source
+}
+
+// MarshalJSON serialize the JSON Schema as its source.
+func (s *JSONSchema) MarshalJSON() ([]byte, error) {
+	return []byte(s.source), nil
+}
+
+// Validate give the violations of the JSON Schema by a value, sorted by path.
+// The value is taken as its JSON representation, a bytes value being a JSON document.
+func (s *JSONSchema) Validate(value interface{}) []SchemaViolation {
+	jsonValue, err := toJSONValue(value)
+	if err != nil {
+		return []SchemaViolation{{Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+	violations := s.root.validate(jsonValue, "")
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations
+}
+
+func compileSchema(decoded interface{}, path string) (*schemaNode, error) {
+	if valid, ok := decoded.(bool); ok {
+		return &schemaNode{alwaysValid: &valid}, nil
+	}
+	object, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema not being an object or a boolean at '%v'", path)
+	}
+	node := &schemaNode{}
+	for _, keyword := range sortedJSONKeys(object) {
+		value := object[keyword]
+		keywordPath := path + "/" + keyword
+		var err error
+		switch keyword {
+		case "$ref":
+			return nil, fmt.Errorf("unsupported keyword at '%v'", keywordPath)
+		case "type":
+			node.types, err = compileSchemaTypes(value, keywordPath)
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("keyword not being an array at '%v'", keywordPath)
+			}
+			node.enum = values
+		case "const":
+			node.constV = &value
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("keyword not being a string at '%v'", keywordPath)
+			}
+			node.pattern, err = regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern at '%v': %v", keywordPath, err)
+			}
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("keyword not being an object at '%v'", keywordPath)
+			}
+			node.properties = make(map[string]*schemaNode, len(properties))
+			for name, property := range properties {
+				node.properties[name], err = compileSchema(property, keywordPath+"/"+escapeJSONPointer(name))
+				if err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			node.required, err = compileSchemaStrings(value, keywordPath)
+		case "additionalProperties":
+			node.additionalProperties, err = compileSchema(value, keywordPath)
+		case "items":
+			node.items, err = compileSchema(value, keywordPath)
+		case "not":
+			node.not, err = compileSchema(value, keywordPath)
+		case "allOf":
+			node.allOf, err = compileSchemaList(value, keywordPath)
+		case "anyOf":
+			node.anyOf, err = compileSchemaList(value, keywordPath)
+		case "oneOf":
+			node.oneOf, err = compileSchemaList(value, keywordPath)
+		case "minItems":
+			node.minItems, err = compileSchemaCount(value, keywordPath)
+		case "maxItems":
+			node.maxItems, err = compileSchemaCount(value, keywordPath)
+		case "minLength":
+			node.minLength, err = compileSchemaCount(value, keywordPath)
+		case "maxLength":
+			node.maxLength, err = compileSchemaCount(value, keywordPath)
+		case "minimum":
+			node.minimum, err = compileSchemaNumber(value, keywordPath)
+		case "maximum":
+			node.maximum, err = compileSchemaNumber(value, keywordPath)
+		case "exclusiveMinimum":
+			node.exclusiveMinimum, err = compileSchemaNumber(value, keywordPath)
+		case "exclusiveMaximum":
+			node.exclusiveMaximum, err = compileSchemaNumber(value, keywordPath)
+		case "multipleOf":
+			node.multipleOf, err = compileSchemaNumber(value, keywordPath)
+			if err == nil && *node.multipleOf <= 0 {
+				err = fmt.Errorf("keyword not being a positive number at '%v'", keywordPath)
+			}
+		case "uniqueItems":
+			unique, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("keyword not being a boolean at '%v'", keywordPath)
+			}
+			node.uniqueItems = unique
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func compileSchemaTypes(value interface{}, path string) ([]string, error) {
+	if name, ok := value.(string); ok {
+		value = []interface{}{name}
+	}
+	types, err := compileSchemaStrings(value, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range types {
+		if !schemaTypes[name] {
+			return nil, fmt.Errorf("unknown type '%v' at '%v'", name, path)
+		}
+	}
+	return types, nil
+}
+
+func compileSchemaStrings(value interface{}, path string) ([]string, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("keyword not being an array of strings at '%v'", path)
+	}
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("keyword not being an array of strings at '%v'", path)
+		}
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+func compileSchemaList(value interface{}, path string) ([]*schemaNode, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("keyword not being a non-empty array at '%v'", path)
+	}
+	nodes := make([]*schemaNode, 0, len(values))
+	for i, value := range values {
+		node, err := compileSchema(value, path+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func compileSchemaCount(value interface{}, path string) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("keyword not being a non-negative integer at '%v'", path)
+	}
+	count := int(number)
+	return &count, nil
+}
+
+func compileSchemaNumber(value interface{}, path string) (*float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("keyword not being a number at '%v'", path)
+	}
+	return &number, nil
+}
+
+func (n *schemaNode) validate(value interface{}, path string) []SchemaViolation {
+	if n.alwaysValid != nil {
+		if *n.alwaysValid {
+			return nil
+		}
+		return []SchemaViolation{{Path: path, Message: "not allowed"}}
+	}
+	violations := make([]SchemaViolation, 0)
+	violate := func(format string, a ...interface{}) {
+		violations = append(violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if len(n.types) > 0 && !n.matchType(value) {
+		violate("expected %v, got %v", strings.Join(n.types, " or "), jsonType(value))
+		return violations
+	}
+	if n.constV != nil && !reflect.DeepEqual(value, *n.constV) {
+		violate("expected %v", formatSchemaValue(*n.constV))
+	}
+	if n.enum != nil && !containsSchemaValue(n.enum, value) {
+		expected := make([]string, 0, len(n.enum))
+		for _, v := range n.enum {
+			expected = append(expected, formatSchemaValue(v))
+		}
+		violate("expected one of %v", strings.Join(expected, ", "))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if n.minLength != nil && length < *n.minLength {
+			violate("expected at least %v characters, got %v", *n.minLength, length)
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			violate("expected at most %v characters, got %v", *n.maxLength, length)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(v) {
+			violate("expected to match '%v'", n.pattern)
+		}
+	case float64:
+		if n.minimum != nil && v < *n.minimum {
+			violate("expected at least %v, got %v", *n.minimum, v)
+		}
+		if n.maximum != nil && v > *n.maximum {
+			violate("expected at most %v, got %v", *n.maximum, v)
+		}
+		if n.exclusiveMinimum != nil && v <= *n.exclusiveMinimum {
+			violate("expected more than %v, got %v", *n.exclusiveMinimum, v)
+		}
+		if n.exclusiveMaximum != nil && v >= *n.exclusiveMaximum {
+			violate("expected less than %v, got %v", *n.exclusiveMaximum, v)
+		}
+		if n.multipleOf != nil {
+			quotient := v / *n.multipleOf
+			if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				violate("expected a multiple of %v, got %v", *n.multipleOf, v)
+			}
+		}
+	case []interface{}:
+		if n.minItems != nil && len(v) < *n.minItems {
+			violate("expected at least %v items, got %v", *n.minItems, len(v))
+		}
+		if n.maxItems != nil && len(v) > *n.maxItems {
+			violate("expected at most %v items, got %v", *n.maxItems, len(v))
+		}
+		if n.uniqueItems {
+			for i := 1; i < len(v); i++ {
+				if containsSchemaValue(v[:i], v[i]) {
+					violate("expected unique items, got %v twice", formatSchemaValue(v[i]))
+					break
+				}
+			}
+		}
+		if n.items != nil {
+			for i, item := range v {
+				violations = append(violations, n.items.validate(item, path+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range n.required {
+			if _, found := v[key]; !found {
+				violate("missing required property '%v'", key)
+			}
+		}
+		for _, key := range sortedJSONKeys(v) {
+			propertyPath := path + "/" + escapeJSONPointer(key)
+			if property, found := n.properties[key]; found {
+				violations = append(violations, property.validate(v[key], propertyPath)...)
+			} else if n.additionalProperties != nil {
+				if n.additionalProperties.alwaysValid != nil && !*n.additionalProperties.alwaysValid {
+					violations = append(violations, SchemaViolation{Path: propertyPath, Message: "unexpected property"})
+					continue
+				}
+				violations = append(violations, n.additionalProperties.validate(v[key], propertyPath)...)
+			}
+		}
+	}
+
+	for _, schema := range n.allOf {
+		violations = append(violations, schema.validate(value, path)...)
+	}
+	if n.anyOf != nil && n.countValid(n.anyOf, value, path) == 0 {
+		violate("expected to match at least one schema of anyOf")
+	}
+	if n.oneOf != nil {
+		if valid := n.countValid(n.oneOf, value, path); valid != 1 {
+			violate("expected to match exactly one schema of oneOf, got %v", valid)
+		}
+	}
+	if n.not != nil && len(n.not.validate(value, path)) == 0 {
+		violate("expected to not match the schema of not")
+	}
+	return violations
+}
+
+func (n *schemaNode) matchType(value interface{}) bool {
+	actual := jsonType(value)
+	for _, expected := range n.types {
+		if expected == actual {
+			return true
+		}
+		if number, ok := value.(float64); ok && expected == "integer" && number == math.Trunc(number) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *schemaNode) countValid(schemas []*schemaNode, value interface{}, path string) int {
+	valid := 0
+	for _, schema := range schemas {
+		if len(schema.validate(value, path)) == 0 {
+			valid++
+		}
+	}
+	return valid
+}
+
+func containsSchemaValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatSchemaValue(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// escapeJSONPointer escape a key to be a JSON Pointer segment.
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
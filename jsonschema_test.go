@@ -0,0 +1,183 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ParseJSONSchema(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenSource   string
+		expectedError error
+	}{
+		{
+			name:        "Can parse a schema",
+			givenSource: `{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer", "minimum": 1}}}`,
+		},
+		{
+			name:        "Can parse a boolean schema",
+			givenSource: `true`,
+		},
+		{
+			name:          "Can't parse an invalid JSON",
+			givenSource:   `{"type": `,
+			expectedError: errors.New("can't parse json schema: unexpected end of JSON input"),
+		},
+		{
+			name:          "Can't parse a schema with unknown type",
+			givenSource:   `{"properties": {"id": {"type": "int"}}}`,
+			expectedError: errors.New("can't parse json schema: unknown type 'int' at '/properties/id/type'"),
+		},
+		{
+			name:          "Can't parse a schema with invalid pattern",
+			givenSource:   `{"pattern": "[a-z"}`,
+			expectedError: errors.New("can't parse json schema: invalid pattern at '/pattern': error parsing regexp: missing closing ]: `[a-z`"),
+		},
+		{
+			name:          "Can't parse a schema with reference",
+			givenSource:   `{"items": {"$ref": "#/definitions/item"}}`,
+			expectedError: errors.New("can't parse json schema: unsupported keyword at '/items/$ref'"),
+		},
+		{
+			name:          "Can't parse a schema with invalid keyword value",
+			givenSource:   `{"minItems": -1}`,
+			expectedError: errors.New("can't parse json schema: keyword not being a non-negative integer at '/minItems'"),
+		},
+		{
+			name:          "Can't parse a schema not being an object",
+			givenSource:   `{"anyOf": [1]}`,
+			expectedError: errors.New("can't parse json schema: schema not being an object or a boolean at '/anyOf/0'"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schema, err := ParseJSONSchema([]byte(testCase.givenSource))
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && schema.String() != testCase.givenSource {
+				t.Errorf("source - got: %+v, want: %+v", schema.String(), testCase.givenSource)
+			}
+		})
+	}
+}
+
+func Test_JSONSchema_Validate(t *testing.T) {
+	order := `{
+  "type": "object",
+  "required": ["id", "customer", "items"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "integer", "exclusiveMinimum": 0},
+    "customer": {"type": "string", "minLength": 1, "maxLength": 8, "pattern": "^[a-z]+$"},
+    "status": {"enum": ["new", "paid"]},
+    "version": {"const": 2},
+    "note": {"type": ["string", "null"]},
+    "items": {
+      "type": "array",
+      "minItems": 1,
+      "maxItems": 3,
+      "uniqueItems": true,
+      "items": {
+        "type": "object",
+        "required": ["price"],
+        "properties": {
+          "price": {"type": "number", "minimum": 0, "maximum": 100, "multipleOf": 0.01},
+          "quantity": {"type": "integer", "exclusiveMaximum": 10}
+        }
+      }
+    },
+    "payment": {
+      "oneOf": [
+        {"type": "object", "required": ["card"]},
+        {"type": "object", "required": ["iban"]}
+      ],
+      "anyOf": [{"required": ["card"]}, {"required": ["iban"]}],
+      "not": {"required": ["cash"]},
+      "allOf": [{"properties": {"card": {"type": "string"}}}]
+    }
+  }
+}`
+	schema, err := ParseJSONSchema([]byte(order))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name               string
+		givenValue         interface{}
+		expectedViolations []SchemaViolation
+	}{
+		{
+			name:               "Can validate a valid value",
+			givenValue:         []byte(`{"id": 1, "customer": "bob", "status": "new", "version": 2, "note": null, "items": [{"price": 9.99, "quantity": 2}], "payment": {"card": "4242"}}`),
+			expectedViolations: []SchemaViolation{},
+		},
+		{
+			name: "Can validate a go value",
+			givenValue: map[string]interface{}{
+				"id":       42,
+				"customer": "alice",
+				"items":    []map[string]interface{}{{"price": 10}},
+			},
+			expectedViolations: []SchemaViolation{},
+		},
+		{
+			name:       "Can give the violations of an invalid value",
+			givenValue: []byte(`{"id": 0, "customer": "Bob Smith", "status": "lost", "version": 1, "note": 2, "items": [{"price": 9.999, "quantity": 1.5}, {"quantity": 10}, {"price": 9.999, "quantity": 1.5}], "coupon": "free", "payment": {"card": 4242, "iban": "FR76", "cash": true}}`),
+			expectedViolations: []SchemaViolation{
+				{Path: "/coupon", Message: "unexpected property"},
+				{Path: "/customer", Message: "expected at most 8 characters, got 9"},
+				{Path: "/customer", Message: "expected to match '^[a-z]+$'"},
+				{Path: "/id", Message: "expected more than 0, got 0"},
+				{Path: "/items", Message: "expected unique items, got {\"price\":9.999,\"quantity\":1.5} twice"},
+				{Path: "/items/0/price", Message: "expected a multiple of 0.01, got 9.999"},
+				{Path: "/items/0/quantity", Message: "expected integer, got number"},
+				{Path: "/items/1", Message: "missing required property 'price'"},
+				{Path: "/items/1/quantity", Message: "expected less than 10, got 10"},
+				{Path: "/items/2/price", Message: "expected a multiple of 0.01, got 9.999"},
+				{Path: "/items/2/quantity", Message: "expected integer, got number"},
+				{Path: "/note", Message: "expected string or null, got number"},
+				{Path: "/payment", Message: "expected to match exactly one schema of oneOf, got 2"},
+				{Path: "/payment", Message: "expected to not match the schema of not"},
+				{Path: "/payment/card", Message: "expected string, got number"},
+				{Path: "/status", Message: "expected one of \"new\", \"paid\""},
+				{Path: "/version", Message: "expected 2"},
+			},
+		},
+		{
+			name:       "Can give the violations of missing properties",
+			givenValue: []byte(`{"items": [], "payment": {}}`),
+			expectedViolations: []SchemaViolation{
+				{Message: "missing required property 'id'"},
+				{Message: "missing required property 'customer'"},
+				{Path: "/items", Message: "expected at least 1 items, got 0"},
+				{Path: "/payment", Message: "expected to match at least one schema of anyOf"},
+				{Path: "/payment", Message: "expected to match exactly one schema of oneOf, got 0"},
+			},
+		},
+		{
+			name:               "Can give the violation of a value with another type",
+			givenValue:         []byte(`["id"]`),
+			expectedViolations: []SchemaViolation{{Message: "expected object, got array"}},
+		},
+		{
+			name:               "Can give the violation of an invalid JSON",
+			givenValue:         []byte(`{`),
+			expectedViolations: []SchemaViolation{{Message: "invalid JSON: unexpected end of JSON input"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			violations := schema.Validate(testCase.givenValue)
+
+			if !cmp.Equal(violations, testCase.expectedViolations) {
+				t.Errorf("got: %+v, want: %+v", violations, testCase.expectedViolations)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package hoff
+
+// nodeLayers assign each node of an activated node system to a layer,
+// a node being in the layer after the farthest of its ancestors.
+func nodeLayers(s *NodeSystem) [][]Node {
+	layerOfNode := make(map[string]int)
+	incomingLinks := make(map[string]int)
+	for _, link := range s.links {
+		incomingLinks[s.nodeID(link.To)]++
+	}
+
+	queue := make([]Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		if incomingLinks[s.nodeID(node)] == 0 {
+			queue = append(queue, node)
+			layerOfNode[s.nodeID(node)] = 0
+		}
+	}
+
+	layers := make([][]Node, 0)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		layer := layerOfNode[s.nodeID(node)]
+		for len(layers) <= layer {
+			layers = append(layers, make([]Node, 0))
+		}
+		layers[layer] = append(layers[layer], node)
+
+		for _, branch := range nodeBranches(node) {
+			followingNodes, _ := s.Follow(node, branch)
+			for _, followingNode := range followingNodes {
+				followingID := s.nodeID(followingNode)
+				if layerOfNode[followingID] < layer+1 {
+					layerOfNode[followingID] = layer + 1
+				}
+				incomingLinks[followingID]--
+				if incomingLinks[followingID] == 0 {
+					queue = append(queue, followingNode)
+				}
+			}
+		}
+	}
+	return layers
+}
+
+// Layers give the nodes of the activated node system by layer (or rank), to draw the node system from left to right:
+// a node is in the layer after the farthest of its ancestors, and a link always go to a following layer.
+// The nodes of a layer are in a stable order, and a not activated node system have no layers.
+func (s *NodeSystem) Layers() [][]Node {
+	if !s.activated {
+		return nil
+	}
+	return nodeLayers(s)
+}
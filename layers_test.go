@@ -0,0 +1,63 @@
+package hoff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_nodeLayers(t *testing.T) {
+	action1, _ := NewActionNode("action1", func(*Context) error { return nil })
+	decision2, _ := NewDecisionNode("decision2", func(*Context) (bool, error) { return true, nil })
+	action3, _ := NewActionNode("action3", func(*Context) error { return nil })
+	action4, _ := NewActionNode("action4", func(*Context) error { return nil })
+	action5, _ := NewActionNode("action5", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(action5)
+	ns.AddNode(action4)
+	ns.AddNode(action3)
+	ns.AddNode(decision2)
+	ns.AddNode(action1)
+	ns.AddLink(action1, decision2)
+	ns.AddLinkOnBranch(decision2, action3, true)
+	ns.AddLinkOnBranch(decision2, action4, false)
+	ns.AddLink(action3, action5)
+	ns.AddLink(action1, action5)
+	ns.ConfigureJoinModeOnNode(action5, JoinOr)
+	ns.ActivateInPlace()
+
+	layers := nodeLayers(ns)
+	expectedLayers := [][]Node{
+		{action1},
+		{decision2},
+		{action3, action4},
+		{action5},
+	}
+
+	if !cmp.Equal(layers, expectedLayers, NodeComparator) {
+		t.Errorf("got: %+v, want: %+v", layers, expectedLayers)
+	}
+}
+
+func Test_NodeSystem_Layers(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+
+	if layers := ns.Layers(); layers != nil {
+		t.Errorf("not activated - got: %+v, want: %+v", layers, nil)
+	}
+
+	ns.IsValid()
+	ns.ActivateInPlace()
+	layers := ns.Layers()
+	expectedLayers := [][]Node{
+		{someActionNode},
+		{anotherActionNode},
+	}
+	if !cmp.Equal(layers, expectedLayers, NodeComparator) {
+		t.Errorf("got: %+v, want: %+v", layers, expectedLayers)
+	}
+}
@@ -0,0 +1,73 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// InitializableNode is a Node who need to be initialized once before its computations,
+// like to open a connection or a pool.
+type InitializableNode interface {
+	Node
+	Init(ctx context.Context) error
+}
+
+// ClosableNode is a Node who hold resources to release once the engine is shut down.
+type ClosableNode interface {
+	Node
+	Close() error
+}
+
+// Init initialize the nodes of the node system once, before the first computation.
+// Without call to Init, the nodes are initialized by the first computation.
+// When a node fail to be initialized, the nodes already initialized are closed
+// and the next call to Init (or computation) initialize them again.
+func (e *Engine) Init(ctx context.Context) error {
+	if e.system == nil {
+		return errors.New("need a configured node system")
+	}
+	e.lifecycleMu.Lock()
+	defer e.lifecycleMu.Unlock()
+	if e.nodesInitialized {
+		return nil
+	}
+	initialized := make([]Node, 0)
+	for _, node := range e.system.nodes {
+		if initializable, ok := node.(InitializableNode); ok {
+			err := initializable.Init(ctx)
+			if err != nil {
+				closeNodes(initialized)
+				return fmt.Errorf("can't initialize node '%v': %w", node, err)
+			}
+		}
+		initialized = append(initialized, node)
+	}
+	e.nodesInitialized = true
+	return nil
+}
+
+// closeInitializedNodes close the nodes of the node system once initialized.
+func (e *Engine) closeInitializedNodes() error {
+	e.lifecycleMu.Lock()
+	defer e.lifecycleMu.Unlock()
+	if !e.nodesInitialized {
+		return nil
+	}
+	e.nodesInitialized = false
+	return closeNodes(e.system.nodes)
+}
+
+// closeNodes close all the nodes, and give the first error.
+func closeNodes(nodes []Node) error {
+	var firstErr error
+	for _, node := range nodes {
+		if closable, ok := node.(ClosableNode); ok {
+			err := closable.Close()
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("can't close node '%v': %w", node, err)
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,97 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// lifecycleNode count its initializations, computations, and closings.
+type lifecycleNode struct {
+	name         string
+	initErr      error
+	closeErr     error
+	inits        int
+	computations int
+	closings     int
+}
+
+func (n *lifecycleNode) String() string { return n.name }
+
+func (n *lifecycleNode) Compute(*Context) ComputeState {
+	n.computations++
+	return NewContinueComputeState()
+}
+
+func (n *lifecycleNode) DecideCapability() bool { return false }
+
+func (n *lifecycleNode) Init(context.Context) error {
+	n.inits++
+	return n.initErr
+}
+
+func (n *lifecycleNode) Close() error {
+	n.closings++
+	return n.closeErr
+}
+
+func Test_Engine_Init(t *testing.T) {
+	connection := &lifecycleNode{name: "connection"}
+	ns := NewNodeSystem()
+	ns.AddNode(connection)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.Compute(map[string]interface{}{})
+	eng.Compute(map[string]interface{}{})
+	if connection.inits != 1 || connection.computations != 2 || connection.closings != 0 {
+		t.Errorf("computed - got: %+v, want: 1 init, 2 computations", connection)
+	}
+
+	_, err := eng.Shutdown(context.Background())
+	if err != nil || connection.closings != 1 {
+		t.Errorf("shut down - got: %+v %+v, want: 1 closing", connection, err)
+	}
+}
+
+func Test_Engine_Init_withFailures(t *testing.T) {
+	connection := &lifecycleNode{name: "connection", closeErr: errors.New("already closed")}
+	pool := &lifecycleNode{name: "pool", initErr: errors.New("unreachable")}
+	ns := NewNodeSystem()
+	ns.AddNode(connection)
+	ns.AddNode(pool)
+	ns.AddLink(connection, pool)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	err := eng.Init(context.Background())
+	expectedError := errors.New("need a configured node system")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("init without node system - got: %+v, want: %+v", err, expectedError)
+	}
+
+	eng.ConfigureNodeSystem(ns)
+	result := eng.Compute(map[string]interface{}{})
+	expectedError = fmt.Errorf("can't initialize node 'pool': %w", pool.initErr)
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("compute - got: %+v, want: %+v", result.Error, expectedError)
+	}
+	if connection.inits != 1 || connection.closings != 1 || connection.computations != 0 {
+		t.Errorf("initialized node - got: %+v, want: closed without computation", connection)
+	}
+
+	pool.initErr = nil
+	err = eng.Init(context.Background())
+	if err != nil || connection.inits != 2 || pool.inits != 2 {
+		t.Errorf("init again - got: %+v %+v %+v, want: initialized again", err, connection, pool)
+	}
+	_, err = eng.Shutdown(context.Background())
+	expectedError = fmt.Errorf("can't close node 'connection': %w", connection.closeErr)
+	if !cmp.Equal(err, expectedError, errorComparator) || pool.closings != 1 {
+		t.Errorf("shut down - got: %+v %+v, want: %+v", err, pool, expectedError)
+	}
+}
@@ -0,0 +1,88 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrLocked is the error of a Locker when the lock is held by another owner.
+var ErrLocked = errors.New("can't lock, already locked")
+
+// Locker hold exclusive locks shared between engine replicas.
+type Locker interface {
+	// Lock acquire the lock of a key for a time to live, and give the token to unlock it, or ErrLocked.
+	Lock(key string, ttl time.Duration) (string, error)
+	// Unlock release the lock of a key if still acquired with the token.
+	Unlock(key, token string) error
+}
+
+// ConfigureLocker add a locker to resume a paused or interrupted computation on only one engine at a time,
+// the lock is held during the resume for at most the time to live.
+func (e *Engine) ConfigureLocker(locker Locker, ttl time.Duration) error {
+	if locker != nil && ttl <= 0 {
+		return fmt.Errorf("can't configure locker with a non positive time to live: %v", ttl)
+	}
+	e.locker = locker
+	e.lockTTL = ttl
+	return nil
+}
+
+// lockComputation acquire the lock of a computation, and give the function to release it.
+func (e *Engine) lockComputation(cp *Computation) (func(), error) {
+	if e.locker == nil {
+		return func() {}, nil
+	}
+	key := "computation:" + cp.ID
+	token, err := e.locker.Lock(key, e.lockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("can't resume computation '%v': %w", cp.ID, err)
+	}
+	return func() {
+		e.locker.Unlock(key, token)
+	}, nil
+}
+
+type memoryLock struct {
+	token   string
+	expires time.Time
+}
+
+// MemoryLocker is a Locker for the engines of a single process, mainly for testing.
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+}
+
+// NewMemoryLocker create a locker without locks.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		locks: make(map[string]memoryLock),
+	}
+}
+
+// Lock acquire the lock of a key for a time to live.
+func (l *MemoryLocker) Lock(key string, ttl time.Duration) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lock, found := l.locks[key]; found && time.Now().Before(lock.expires) {
+		return "", ErrLocked
+	}
+	l.locks[key] = memoryLock{token: token, expires: time.Now().Add(ttl)}
+	return token, nil
+}
+
+// Unlock release the lock of a key if still acquired with the token.
+func (l *MemoryLocker) Unlock(key, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lock, found := l.locks[key]; found && lock.token == token {
+		delete(l.locks, key)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_MemoryLocker(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	token, err := locker.Lock("key", time.Minute)
+	if err != nil {
+		t.Errorf("lock - got: %+v, want: %+v", err, nil)
+	}
+	_, err = locker.Lock("key", time.Minute)
+	if err != ErrLocked {
+		t.Errorf("lock again - got: %+v, want: %+v", err, ErrLocked)
+	}
+	locker.Unlock("key", "another token")
+	_, err = locker.Lock("key", time.Minute)
+	if err != ErrLocked {
+		t.Errorf("lock after unlock with another token - got: %+v, want: %+v", err, ErrLocked)
+	}
+	locker.Unlock("key", token)
+	_, err = locker.Lock("key", time.Nanosecond)
+	if err != nil {
+		t.Errorf("lock after unlock - got: %+v, want: %+v", err, nil)
+	}
+	time.Sleep(time.Millisecond)
+	_, err = locker.Lock("key", time.Minute)
+	if err != nil {
+		t.Errorf("lock after expiration - got: %+v, want: %+v", err, nil)
+	}
+}
+
+func Test_Engine_ConfigureLocker(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.ActivateInPlace()
+
+	locker := NewMemoryLocker()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	err := eng.ConfigureLocker(locker, 0)
+	expectedError := errors.New("can't configure locker with a non positive time to live: 0s")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("configure error - got: %+v, want: %+v", err, expectedError)
+	}
+	eng.ConfigureLocker(locker, time.Minute)
+
+	paused := eng.Compute(map[string]interface{}{})
+	token := paused.PausedTokens()[0]
+
+	lockToken, _ := locker.Lock("computation:"+paused.ID, time.Minute)
+	result := eng.Deliver(token, "approved")
+	expectedError = fmt.Errorf("can't resume computation '%v': %w", paused.ID, ErrLocked)
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("locked error - got: %+v, want: %+v", result.Error, expectedError)
+	}
+
+	locker.Unlock("computation:"+paused.ID, lockToken)
+	result = eng.Deliver(token, "approved")
+	if result.Error != nil || len(result.PausedTokens()) != 0 {
+		t.Errorf("unlocked - got: %+v, want: resumed computation", result)
+	}
+	_, err = locker.Lock("computation:"+paused.ID, time.Minute)
+	if err != nil {
+		t.Errorf("lock after resume - got: %+v, want: %+v", err, nil)
+	}
+}
@@ -0,0 +1,118 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Message is a message consumed from a MessageSource.
+type Message struct {
+	ID      string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+
+	raw interface{}
+}
+
+// MessageSource is a queue of messages used to trigger computations.
+type MessageSource interface {
+	// Receive wait for the next message.
+	Receive(ctx context.Context) (*Message, error)
+	// Ack tell the source that the message is processed.
+	Ack(ctx context.Context, m *Message) error
+	// Nack tell the source that the message can't be processed.
+	Nack(ctx context.Context, m *Message) error
+}
+
+// KafkaMessage is a message read from a Kafka topic.
+type KafkaMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string][]byte
+}
+
+// KafkaConsumer is the part of a Kafka client needed to consume messages,
+// e.g. a thin wrapper around a consumer group reader.
+type KafkaConsumer interface {
+	FetchMessage(ctx context.Context) (KafkaMessage, error)
+	CommitMessage(ctx context.Context, m KafkaMessage) error
+}
+
+// KafkaProducer is the part of a Kafka client needed to write messages.
+type KafkaProducer interface {
+	WriteMessage(ctx context.Context, m KafkaMessage) error
+}
+
+// KafkaMessageSource is a MessageSource who consume a Kafka topic.
+// As Kafka don't handle negative acknowledgement, a nacked message is
+// written to the dead letter producer (if any) before being committed.
+type KafkaMessageSource struct {
+	consumer   KafkaConsumer
+	deadLetter KafkaProducer
+}
+
+// NewKafkaMessageSource create a KafkaMessageSource based on a consumer,
+// and an optional producer for nacked messages.
+func NewKafkaMessageSource(consumer KafkaConsumer, deadLetter KafkaProducer) (*KafkaMessageSource, error) {
+	if consumer == nil {
+		return nil, errors.New("can't create kafka message source without consumer")
+	}
+	return &KafkaMessageSource{consumer: consumer, deadLetter: deadLetter}, nil
+}
+
+// Receive fetch the next message of the topic.
+func (s *KafkaMessageSource) Receive(ctx context.Context) (*Message, error) {
+	kafkaMessage, err := s.consumer.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string)
+	for key, value := range kafkaMessage.Headers {
+		headers[key] = string(value)
+	}
+	return &Message{
+		ID:      kafkaMessage.Topic + "/" + strconv.Itoa(kafkaMessage.Partition) + "/" + strconv.FormatInt(kafkaMessage.Offset, 10),
+		Key:     kafkaMessage.Key,
+		Value:   kafkaMessage.Value,
+		Headers: headers,
+		raw:     kafkaMessage,
+	}, nil
+}
+
+// Ack commit the message.
+func (s *KafkaMessageSource) Ack(ctx context.Context, m *Message) error {
+	kafkaMessage, err := rawKafkaMessage(m)
+	if err != nil {
+		return err
+	}
+	return s.consumer.CommitMessage(ctx, kafkaMessage)
+}
+
+// Nack write the message to the dead letter producer, and commit it.
+func (s *KafkaMessageSource) Nack(ctx context.Context, m *Message) error {
+	kafkaMessage, err := rawKafkaMessage(m)
+	if err != nil {
+		return err
+	}
+	if s.deadLetter != nil {
+		err = s.deadLetter.WriteMessage(ctx, kafkaMessage)
+		if err != nil {
+			return err
+		}
+	}
+	return s.consumer.CommitMessage(ctx, kafkaMessage)
+}
+
+func rawKafkaMessage(m *Message) (KafkaMessage, error) {
+	kafkaMessage, ok := m.raw.(KafkaMessage)
+	if !ok {
+		return KafkaMessage{}, fmt.Errorf("can't handle a message not consumed from kafka: %v", m.ID)
+	}
+	return kafkaMessage, nil
+}
@@ -0,0 +1,78 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeKafkaClient struct {
+	messages  []KafkaMessage
+	committed []int64
+	written   []int64
+}
+
+func (c *fakeKafkaClient) FetchMessage(ctx context.Context) (KafkaMessage, error) {
+	if len(c.messages) == 0 {
+		return KafkaMessage{}, errors.New("no more messages")
+	}
+	message := c.messages[0]
+	c.messages = c.messages[1:]
+	return message, nil
+}
+
+func (c *fakeKafkaClient) CommitMessage(ctx context.Context, m KafkaMessage) error {
+	c.committed = append(c.committed, m.Offset)
+	return nil
+}
+
+func (c *fakeKafkaClient) WriteMessage(ctx context.Context, m KafkaMessage) error {
+	c.written = append(c.written, m.Offset)
+	return nil
+}
+
+func Test_NewKafkaMessageSource(t *testing.T) {
+	_, err := NewKafkaMessageSource(nil, nil)
+	expectedError := errors.New("can't create kafka message source without consumer")
+
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_KafkaMessageSource(t *testing.T) {
+	client := &fakeKafkaClient{
+		messages: []KafkaMessage{
+			{Topic: "topic", Partition: 1, Offset: 10, Key: []byte("key"), Value: []byte("value"), Headers: map[string][]byte{"header": []byte("value")}},
+			{Topic: "topic", Partition: 1, Offset: 11},
+		},
+	}
+	source, _ := NewKafkaMessageSource(client, client)
+	ctx := context.Background()
+
+	message, _ := source.Receive(ctx)
+	receivedMessage := []interface{}{message.ID, message.Key, message.Value, message.Headers}
+	expectedMessage := []interface{}{"topic/1/10", []byte("key"), []byte("value"), map[string]string{"header": "value"}}
+	if !cmp.Equal(receivedMessage, expectedMessage) {
+		t.Errorf("message - got: %+v, want: %+v", receivedMessage, expectedMessage)
+	}
+	source.Ack(ctx, message)
+
+	nackedMessage, _ := source.Receive(ctx)
+	source.Nack(ctx, nackedMessage)
+
+	if !cmp.Equal(client.committed, []int64{10, 11}) {
+		t.Errorf("committed - got: %+v, want: %+v", client.committed, []int64{10, 11})
+	}
+	if !cmp.Equal(client.written, []int64{11}) {
+		t.Errorf("written - got: %+v, want: %+v", client.written, []int64{11})
+	}
+
+	err := source.Ack(ctx, &Message{ID: "other"})
+	expectedError := errors.New("can't handle a message not consumed from kafka: other")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -16,6 +16,6 @@ type Node interface {
 var (
 	// NodeComparator is a google/go-cmp comparator of Node
 	NodeComparator = cmp.Comparer(func(x, y Node) bool {
-		return x == y
+		return defaultNodeID(x) == defaultNodeID(y)
 	})
 )
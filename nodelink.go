@@ -4,20 +4,22 @@ import (
 	"fmt"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 var (
 	// nodeLinkComparator is a google/go-cmp comparator of Node Links
 	nodeLinkComparator = cmp.Comparer(func(x, y nodeLink) bool {
-		return cmp.Equal(x.From, y.From, NodeComparator) && cmp.Equal(x.To, y.To, NodeComparator) && cmp.Equal(x.Branch, y.Branch)
+		return cmp.Equal(x.From, y.From, NodeComparator) && cmp.Equal(x.To, y.To, NodeComparator) && cmp.Equal(x.Branch, y.Branch) && cmp.Equal(x.Metadata, y.Metadata, cmpopts.EquateEmpty())
 	})
 )
 
 // nodeLink store all information needed to represent a link in the node system
 type nodeLink struct {
-	From   Node
-	To     Node
-	Branch *bool
+	From     Node
+	To       Node
+	Branch   *bool
+	Metadata map[string]string
 }
 
 // newNodeLink create a new link from a node to another node
@@ -5,39 +5,65 @@ import (
 	"fmt"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 // NodeSystem is a system to configure workflow between action nodes, or decision nodes.
 // The nodes are linked between them by link and join mode options.
 // An activated Node system will be walked throw Follow and Ancestors functions
 type NodeSystem struct {
-	activated      bool
-	nodes          []Node
-	nodesJoinModes map[Node]JoinMode
-	links          []nodeLink
+	activated            bool
+	nodes                []Node
+	nodesJoinModes       map[string]JoinMode
+	nodesJoinExpressions map[string]*joinExpression
+	nodesFlags           map[string]string
+	nodesTags            map[string][]string
+	nodesTypes           map[string]registeredNodeType
+	nodesKeys            map[string]nodeKeys
+	nodesPorts           map[string]nodePorts
+	terminalNodes        map[string]bool
+	nodesProbabilities   map[string]float64
+	declaredInitialNodes []Node
+	triggers             map[string][]Node
+	inputs               []InputParameter
+	outputs              []OutputParameter
+	links                []nodeLink
+	portLinks            []portLink
+	identity             func(Node) string
+	validation           ValidationConfig
+	activationWarnings   []error
 
 	initialNodes       []Node
-	followingNodesTree map[Node]map[*bool][]Node
-	ancestorsNodesTree map[Node]map[*bool][]Node
+	fingerprint        string
+	followingNodesTree map[string]map[*bool][]Node
+	ancestorsNodesTree map[string]map[*bool][]Node
+	nodesIDs           map[Node]string
+	nodesMapKeys       map[string]Node
 }
 
 // NewNodeSystem create an empty Node system
 // who need to be valid and activated in order to be used.
 func NewNodeSystem() *NodeSystem {
 	return &NodeSystem{
-		activated:          false,
-		nodes:              make([]Node, 0),
-		links:              make([]nodeLink, 0),
-		nodesJoinModes:     make(map[Node]JoinMode),
-		initialNodes:       make([]Node, 0),
-		followingNodesTree: make(map[Node]map[*bool][]Node),
-		ancestorsNodesTree: make(map[Node]map[*bool][]Node),
+		activated:            false,
+		nodes:                make([]Node, 0),
+		links:                make([]nodeLink, 0),
+		nodesJoinModes:       make(map[string]JoinMode),
+		nodesJoinExpressions: make(map[string]*joinExpression),
+		nodesFlags:           make(map[string]string),
+		nodesTags:            make(map[string][]string),
+		nodesKeys:            make(map[string]nodeKeys),
+		nodesPorts:           make(map[string]nodePorts),
+		portLinks:            make([]portLink, 0),
+		initialNodes:         make([]Node, 0),
+		followingNodesTree:   make(map[string]map[*bool][]Node),
+		ancestorsNodesTree:   make(map[string]map[*bool][]Node),
 	}
 }
 
 // Equal validate the two NodeSystem are equals.
 func (s *NodeSystem) Equal(o *NodeSystem) bool {
-	return cmp.Equal(s.activated, o.activated) && cmp.Equal(s.nodes, o.nodes, NodeComparator) && cmp.Equal(s.nodesJoinModes, o.nodesJoinModes) && cmp.Equal(s.links, o.links, nodeLinkComparator)
+	return cmp.Equal(s.activated, o.activated) && cmp.Equal(s.nodes, o.nodes, NodeComparator) && cmp.Equal(s.nodesJoinModes, o.nodesJoinModes) && cmp.Equal(s.nodesJoinExpressions, o.nodesJoinExpressions, joinExpressionComparator, cmpopts.EquateEmpty()) && cmp.Equal(s.nodesFlags, o.nodesFlags, cmpopts.EquateEmpty()) && cmp.Equal(s.nodesTags, o.nodesTags, cmpopts.EquateEmpty()) && cmp.Equal(s.nodesTypes, o.nodesTypes, cmpopts.EquateEmpty()) && cmp.Equal(s.nodesKeys, o.nodesKeys, cmpopts.EquateEmpty()) && cmp.Equal(s.nodesPorts, o.nodesPorts, portComparator, cmpopts.EquateEmpty()) && cmp.Equal(s.links, o.links, nodeLinkComparator) && cmp.Equal(s.portLinks, o.portLinks, portLinkComparator, cmpopts.EquateEmpty()) && cmp.Equal(s.terminalNodes, o.terminalNodes, cmpopts.EquateEmpty())
 }
 
 // AddNode add a node to the system before activation.
@@ -54,10 +80,55 @@ func (s *NodeSystem) ConfigureJoinModeOnNode(n Node, m JoinMode) (bool, error) {
 	if s.activated {
 		return false, errors.New("can't add node join mode, node system is freeze due to activation")
 	}
-	s.nodesJoinModes[n] = m
+	s.nodesJoinModes[s.nodeID(n)] = m
 	return true, nil
 }
 
+// ConfigureEnabledWhen configure the feature flag who enable a node into the system before activation.
+// The flag is evaluated by the FlagProvider of the engine running the computation.
+func (s *NodeSystem) ConfigureEnabledWhen(n Node, flag string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add node feature flag, node system is freeze due to activation")
+	}
+	if s.nodesFlags == nil {
+		s.nodesFlags = make(map[string]string)
+	}
+	s.nodesFlags[s.nodeID(n)] = flag
+	return true, nil
+}
+
+// ConfigureTagsOnNode add tags (e.g. "external", "db", "slow") on a node into the system before activation.
+// The tags are used by the engine to apply the policies configured by tag.
+func (s *NodeSystem) ConfigureTagsOnNode(n Node, tags ...string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add node tags, node system is freeze due to activation")
+	}
+	if s.nodesTags == nil {
+		s.nodesTags = make(map[string][]string)
+	}
+	for _, tag := range tags {
+		if !s.HaveTag(n, tag) {
+			s.nodesTags[s.nodeID(n)] = append(s.nodesTags[s.nodeID(n)], tag)
+		}
+	}
+	return true, nil
+}
+
+// TagsOfNode get the tags of a node.
+func (s *NodeSystem) TagsOfNode(n Node) []string {
+	return s.nodesTags[s.nodeID(n)]
+}
+
+// HaveTag validate that a node have a tag.
+func (s *NodeSystem) HaveTag(n Node, tag string) bool {
+	for _, nodeTag := range s.nodesTags[s.nodeID(n)] {
+		if nodeTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // AddLink add a link from a node to another node into the system before activation.
 func (s *NodeSystem) AddLink(from, to Node) (bool, error) {
 	return s.addLink(from, to, nil)
@@ -68,55 +139,104 @@ func (s *NodeSystem) AddLinkOnBranch(from, to Node, branch bool) (bool, error) {
 	return s.addLink(from, to, &branch)
 }
 
-// IsValid check if the configuration of the node system is valid based on checks.
+// ConfigureMetadataOnLink add key/value metadata (e.g. description, owner, SLA) on an existing link
+// into the system before activation, a nil branch is for a link from a non decision node.
+func (s *NodeSystem) ConfigureMetadataOnLink(from, to Node, branch *bool, metadata map[string]string) (bool, error) {
+	from, to = s.canonicalNode(from), s.canonicalNode(to)
+	if s.activated {
+		return false, errors.New("can't add link metadata, node system is freeze due to activation")
+	}
+	for index, link := range s.links {
+		if s.sameNode(link.From, from) && s.sameNode(link.To, to) && cmp.Equal(link.Branch, branch) {
+			if link.Metadata == nil {
+				s.links[index].Metadata = make(map[string]string)
+			}
+			for key, value := range metadata {
+				s.links[index].Metadata[key] = value
+			}
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("can't find link to add metadata: %v", nodeLink{From: from, To: to, Branch: branch})
+}
+
+// MetadataOfLink get the metadata of a link, a nil branch is for a link from a non decision node.
+func (s *NodeSystem) MetadataOfLink(from, to Node, branch *bool) map[string]string {
+	from, to = s.canonicalNode(from), s.canonicalNode(to)
+	for _, link := range s.links {
+		if s.sameNode(link.From, from) && s.sameNode(link.To, to) && cmp.Equal(link.Branch, branch) {
+			return link.Metadata
+		}
+	}
+	return nil
+}
+
+// IsValid check if the configuration of the node system is valid based on checks run concurrently.
 // Check for decision node with any node links as from,
 // check for cyclic redundancy in node links,
 // check for undeclared node used in node links,
-// check for multiple declaration of same node instance.
-func (s *NodeSystem) IsValid() (bool, []error) {
-	errors := make([]error, 0)
-	errors = append(errors, checkForOrphanMultiBranchesNode(s)...)
-	errors = append(errors, checkForCyclicRedundancyInNodeLinks(s)...)
-	errors = append(errors, checkForUndeclaredNodeInNodeLink(s)...)
-	errors = append(errors, checkForMultipleInstanceOfSameNode(s)...)
-	errors = append(errors, checkForMultipleLinksToNodeWithoutJoinMode(s)...)
+// check for multiple declaration of same node instance,
+// check for port links with undeclared or not assignable ports,
+// check for join expressions referencing not linked nodes,
+// check for links from terminal nodes,
+// check for inconsistent declared initial nodes,
+// check for triggers on not initial nodes,
+// check for expressions of nodes who can't be compiled,
+// check for nodes on parallel branches writing the same context key, as warnings ignored here.
+// The errors are given as a ValidationError.
+func (s *NodeSystem) IsValid() (bool, error) {
+	return s.IsValidWith(ValidationConfig{})
+}
 
-	if len(errors) == 0 {
-		return true, nil
+// Activate give an activated copy of the node system, ready to be used,
+// and keep the node system editable to build other activated node systems.
+// In order to activate it, the node system must be valid.
+func (s *NodeSystem) Activate() (*ActivatedNodeSystem, error) {
+	if s.activated {
+		return &ActivatedNodeSystem{system: s}, nil
+	}
+	system := s.copy()
+	err := system.ActivateInPlace()
+	if err != nil {
+		return nil, err
 	}
-	return false, errors
+	return &ActivatedNodeSystem{system: system}, nil
 }
 
-// Activate prepare the node system to be used.
+// ActivateInPlace prepare the node system to be used, and freeze it.
 // In order to activate it, the node system must be valid.
 // Once activated, the initial nodes, following nodes, and ancestors nodes will be accessibles.
-func (s *NodeSystem) Activate() error {
+func (s *NodeSystem) ActivateInPlace() error {
 	if s.activated {
 		return nil
 	}
 
-	validity, _ := s.IsValid()
-	if !validity {
-		return errors.New("can't activate a unvalidated node system")
+	validation := s.Validate(s.validation)
+	if !validation.IsValid() {
+		return fmt.Errorf("can't activate a unvalidated node system: %w", &ValidationError{Errors: validation.Errors})
 	}
+	s.activationWarnings = validation.Warnings
+	s.canonicalizeNodes()
+	s.cacheNodesIDs()
 
 	initialNodes := make([]Node, 0)
-	followingNodesTree := make(map[Node]map[*bool][]Node)
-	ancestorsNodesTree := make(map[Node]map[*bool][]Node)
+	followingNodesTree := make(map[string]map[*bool][]Node)
+	ancestorsNodesTree := make(map[string]map[*bool][]Node)
 
 	toNodes := make([]Node, 0)
 	for _, link := range s.links {
-		followingNodesTreeOnBranch, foundNode := followingNodesTree[link.From]
+		fromID, toID := s.nodeID(link.From), s.nodeID(link.To)
+		followingNodesTreeOnBranch, foundNode := followingNodesTree[fromID]
 		if !foundNode {
-			followingNodesTree[link.From] = make(map[*bool][]Node)
-			followingNodesTreeOnBranch = followingNodesTree[link.From]
+			followingNodesTree[fromID] = make(map[*bool][]Node)
+			followingNodesTreeOnBranch = followingNodesTree[fromID]
 		}
 		followingNodesTreeOnBranch[link.Branch] = append(followingNodesTreeOnBranch[link.Branch], link.To)
 
-		ancestorsNodesTreeOnBranch, foundNode := ancestorsNodesTree[link.To]
+		ancestorsNodesTreeOnBranch, foundNode := ancestorsNodesTree[toID]
 		if !foundNode {
-			ancestorsNodesTree[link.To] = make(map[*bool][]Node)
-			ancestorsNodesTreeOnBranch = ancestorsNodesTree[link.To]
+			ancestorsNodesTree[toID] = make(map[*bool][]Node)
+			ancestorsNodesTreeOnBranch = ancestorsNodesTree[toID]
 		}
 		ancestorsNodesTreeOnBranch[link.Branch] = append(ancestorsNodesTreeOnBranch[link.Branch], link.From)
 
@@ -126,11 +246,14 @@ func (s *NodeSystem) Activate() error {
 	for _, node := range s.nodes {
 		isInitialNode := true
 		for _, toNode := range toNodes {
-			if node == toNode {
+			if s.sameNode(node, toNode) {
 				isInitialNode = false
 				break
 			}
 		}
+		if len(s.declaredInitialNodes) > 0 {
+			isInitialNode = s.isDeclaredInitialNode(node)
+		}
 		if isInitialNode {
 			initialNodes = append(initialNodes, node)
 		}
@@ -139,6 +262,7 @@ func (s *NodeSystem) Activate() error {
 	s.initialNodes = initialNodes
 	s.followingNodesTree = followingNodesTree
 	s.ancestorsNodesTree = ancestorsNodesTree
+	s.fingerprint = s.Fingerprint()
 
 	s.activated = true
 	return nil
@@ -146,13 +270,19 @@ func (s *NodeSystem) Activate() error {
 
 // JoinModeOfNode get the configured join mode of a node
 func (s *NodeSystem) JoinModeOfNode(n Node) JoinMode {
-	mode, foundMode := s.nodesJoinModes[n]
+	mode, foundMode := s.nodesJoinModes[s.nodeID(n)]
 	if foundMode {
 		return mode
 	}
 	return JoinNone
 }
 
+// FlagOfNode get the configured feature flag of a node
+func (s *NodeSystem) FlagOfNode(n Node) (string, bool) {
+	flag, foundFlag := s.nodesFlags[s.nodeID(n)]
+	return flag, foundFlag
+}
+
 // InitialNodes get the initial nodes
 func (s *NodeSystem) InitialNodes() []Node {
 	return s.initialNodes
@@ -169,7 +299,7 @@ func (s *NodeSystem) Follow(n Node, branch *bool) ([]Node, error) {
 	if !s.activated {
 		return nil, errors.New("can't follow a node if system is not activated")
 	}
-	links, foundLinks := s.followingNodesTree[n]
+	links, foundLinks := s.followingNodesTree[s.nodeID(n)]
 	if foundLinks {
 		nodes, foundNodes := links[branch]
 		if foundNodes {
@@ -184,7 +314,7 @@ func (s *NodeSystem) Ancestors(n Node, branch *bool) ([]Node, error) {
 	if !s.activated {
 		return nil, errors.New("can't get ancestors of a node if system is not activated")
 	}
-	links, foundLinks := s.ancestorsNodesTree[n]
+	links, foundLinks := s.ancestorsNodesTree[s.nodeID(n)]
 	if foundLinks {
 		nodes, foundNodes := links[branch]
 		if foundNodes {
@@ -195,6 +325,7 @@ func (s *NodeSystem) Ancestors(n Node, branch *bool) ([]Node, error) {
 }
 
 func (s *NodeSystem) addLink(from, to Node, branch *bool) (bool, error) {
+	from, to = s.canonicalNode(from), s.canonicalNode(to)
 	if s.activated {
 		return false, errors.New("can't add branch link, node system is freeze due to activation")
 	}
@@ -215,7 +346,7 @@ func (s *NodeSystem) addLink(from, to Node, branch *bool) (bool, error) {
 		return false, fmt.Errorf("can't have missing 'to' attribute")
 	}
 
-	if from == to {
+	if s.sameNode(from, to) {
 		return false, fmt.Errorf("can't have link on from and to the same node")
 	}
 
@@ -229,7 +360,7 @@ func (s *NodeSystem) addLink(from, to Node, branch *bool) (bool, error) {
 
 func (s *NodeSystem) haveNode(n Node) bool {
 	for _, node := range s.nodes {
-		if node == n {
+		if s.sameNode(node, n) {
 			return true
 		}
 	}
@@ -242,7 +373,7 @@ func checkForOrphanMultiBranchesNode(s *NodeSystem) []error {
 		if node.DecideCapability() {
 			noLink := true
 			for _, link := range s.links {
-				if link.From == node {
+				if s.sameNode(link.From, node) {
 					noLink = false
 					break
 				}
@@ -264,6 +395,9 @@ func checkForCyclicRedundancyInNodeLinks(s *NodeSystem) []error {
 	}
 
 	nodeLinkSliceComparator := cmp.Comparer(func(x, y []nodeLink) bool {
+		if len(x) != len(y) {
+			return false
+		}
 		sameLinkCount := 0
 		for _, xItem := range x {
 			foundIt := false
@@ -301,18 +435,18 @@ func checkForCyclicRedundancyInNodeLinks(s *NodeSystem) []error {
 
 func findCycle(s *NodeSystem, topNode, currentNode Node, walkednodeLinks []nodeLink) [][]nodeLink {
 	if walkednodeLinks != nil && len(walkednodeLinks) > 0 {
-		if topNode == currentNode {
+		if s.sameNode(topNode, currentNode) {
 			return [][]nodeLink{walkednodeLinks}
 		}
 		for _, link := range walkednodeLinks {
-			if currentNode == link.From {
+			if s.sameNode(currentNode, link.From) {
 				return [][]nodeLink{}
 			}
 		}
 	}
 	var selectedLinks []nodeLink
 	for _, link := range s.links {
-		if link.From == currentNode {
+		if s.sameNode(link.From, currentNode) {
 			selectedLinks = append(selectedLinks, link)
 		}
 	}
@@ -345,17 +479,22 @@ func checkForUndeclaredNodeInNodeLink(s *NodeSystem) []error {
 
 func checkForMultipleInstanceOfSameNode(s *NodeSystem) []error {
 	errors := make([]error, 0)
-	count := make(map[Node]int)
+	count := make(map[string]int)
+	instances := make(map[string]Node)
 	for i := 0; i < len(s.nodes); i++ {
 		for j := 0; j < len(s.nodes); j++ {
-			if i != j && s.nodes[i] == s.nodes[j] {
-				count[s.nodes[i]]++
+			if i != j && s.sameNode(s.nodes[i], s.nodes[j]) {
+				id := s.nodeID(s.nodes[i])
+				count[id]++
+				if _, found := instances[id]; !found {
+					instances[id] = s.nodes[i]
+				}
 			}
 		}
 	}
-	for n, c := range count {
+	for id, c := range count {
 		if c > 1 {
-			errors = append(errors, fmt.Errorf("can't have multiple instances (%v) of the same node: %+v", c, n))
+			errors = append(errors, fmt.Errorf("can't have multiple instances (%v) of the same node: %+v", c, instances[id]))
 		}
 	}
 	return errors
@@ -363,12 +502,16 @@ func checkForMultipleInstanceOfSameNode(s *NodeSystem) []error {
 
 func checkForMultipleLinksToNodeWithoutJoinMode(s *NodeSystem) []error {
 	errors := make([]error, 0)
-	count := make(map[Node]int)
+	count := make(map[string]int)
+	instances := make(map[string]Node)
 	for _, link := range s.links {
-		count[link.To]++
+		id := s.nodeID(link.To)
+		count[id]++
+		instances[id] = link.To
 	}
-	for n, c := range count {
-		if c > 1 && s.JoinModeOfNode(n) == JoinNone {
+	for id, c := range count {
+		n := instances[id]
+		if _, found := s.nodesJoinExpressions[id]; c > 1 && s.JoinModeOfNode(n) == JoinNone && !found {
 			errors = append(errors, fmt.Errorf("can't have multiple links (%v) to the same node: %+v without join mode", c, n))
 		}
 	}
@@ -27,7 +27,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			name: "Can have no nodes",
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 		},
@@ -40,7 +40,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodes: []Node{
 					someActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 		},
@@ -55,7 +55,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					someActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -71,7 +71,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodes: []Node{
 					alwaysTrueDecisionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -92,7 +92,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					alwaysTrueDecisionNode,
 					someActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 				},
@@ -112,7 +112,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 				},
@@ -125,7 +125,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			},
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -139,7 +139,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			},
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -153,7 +153,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			},
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -177,7 +177,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
@@ -204,8 +204,8 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{
-					anotherActionNode: JoinAnd,
+				nodesJoinModes: map[string]JoinMode{
+					defaultNodeID(anotherActionNode): JoinAnd,
 				},
 				links: []nodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
@@ -233,8 +233,8 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{
-					anotherActionNode: JoinOr,
+				nodesJoinModes: map[string]JoinMode{
+					defaultNodeID(anotherActionNode): JoinOr,
 				},
 				links: []nodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
@@ -256,7 +256,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -277,7 +277,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					alwaysTrueDecisionNode,
 					someActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links:          []nodeLink{},
 			},
 			expectedErrors: []error{
@@ -297,7 +297,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodes: []Node{
 					someActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 				},
@@ -318,7 +318,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodes: []Node{
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 				},
@@ -342,7 +342,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 					newNodeLink(anotherActionNode, someActionNode),
@@ -376,8 +376,8 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{
-					someActionNode: JoinAnd,
+				nodesJoinModes: map[string]JoinMode{
+					defaultNodeID(someActionNode): JoinAnd,
 				},
 				links: []nodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
@@ -410,7 +410,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 					anotherActionNode,
 				},
-				nodesJoinModes: map[Node]JoinMode{},
+				nodesJoinModes: map[string]JoinMode{},
 				links: []nodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false),
@@ -430,7 +430,9 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			system := NewNodeSystem()
 			errs := loadNodeSystem(system, testCase.givenNodes, testCase.givenNodesJoinModes, testCase.givenLinks)
 
-			_, validityErrs := system.IsValid()
+			_, validationErr := system.IsValid()
+
+			validityErrs := validationErrors(validationErr)
 			errs = append(errs, validityErrs...)
 
 			if !cmp.Equal(errs, testCase.expectedErrors, errorComparator) {
@@ -454,16 +456,16 @@ func Test_NodeSystem_Activate(t *testing.T) {
 		givenLinksAfterActivation          []nodeLink
 		expectedActivatation               bool
 		expectedInitialNodes               []Node
-		expectedFollowingNodesTree         map[Node]map[*bool][]Node
-		expectedAncestorsNodesTree         map[Node]map[*bool][]Node
+		expectedFollowingNodesTree         map[string]map[*bool][]Node
+		expectedAncestorsNodesTree         map[string]map[*bool][]Node
 		expectedErrors                     []error
 	}{
 		{
 			name:                       "Can activate an empty validated system",
 			expectedActivatation:       true,
 			expectedInitialNodes:       []Node{},
-			expectedFollowingNodesTree: map[Node]map[*bool][]Node{},
-			expectedAncestorsNodesTree: map[Node]map[*bool][]Node{},
+			expectedFollowingNodesTree: map[string]map[*bool][]Node{},
+			expectedAncestorsNodesTree: map[string]map[*bool][]Node{},
 		},
 		{
 			name: "Can't activate an unvalidated system",
@@ -472,7 +474,9 @@ func Test_NodeSystem_Activate(t *testing.T) {
 			},
 			expectedActivatation: false,
 			expectedErrors: []error{
-				errors.New("can't activate a unvalidated node system"),
+				errors.New("can't activate a unvalidated node system: " +
+					"can't have undeclared node 'someActionNode' as 'from' in branch link {from:'someActionNode' to:'anotherActionNode'}\n" +
+					"can't have undeclared node 'anotherActionNode' as 'to' in branch link {from:'someActionNode' to:'anotherActionNode'}"),
 			},
 		},
 		{
@@ -480,7 +484,7 @@ func Test_NodeSystem_Activate(t *testing.T) {
 			givenNodes:                 []Node{someActionNode},
 			expectedActivatation:       true,
 			expectedInitialNodes:       []Node{someActionNode},
-			expectedFollowingNodesTree: map[Node]map[*bool][]Node{},
+			expectedFollowingNodesTree: map[string]map[*bool][]Node{},
 		},
 		{
 			name: "Can activate an no needed branch node link validated system",
@@ -495,8 +499,8 @@ func Test_NodeSystem_Activate(t *testing.T) {
 			expectedInitialNodes: []Node{
 				someActionNode,
 			},
-			expectedFollowingNodesTree: map[Node]map[*bool][]Node{
-				someActionNode: {
+			expectedFollowingNodesTree: map[string]map[*bool][]Node{
+				defaultNodeID(someActionNode): {
 					nil: {anotherActionNode},
 				},
 			},
@@ -516,8 +520,8 @@ func Test_NodeSystem_Activate(t *testing.T) {
 				someActionNode,
 				alwaysTrueDecisionNode,
 			},
-			expectedFollowingNodesTree: map[Node]map[*bool][]Node{
-				alwaysTrueDecisionNode: {
+			expectedFollowingNodesTree: map[string]map[*bool][]Node{
+				defaultNodeID(alwaysTrueDecisionNode): {
 					boolPointer(true): {anotherActionNode},
 				},
 			},
@@ -562,7 +566,7 @@ func Test_NodeSystem_Activate(t *testing.T) {
 			system := NewNodeSystem()
 			errs := loadNodeSystem(system, testCase.givenNodes, testCase.givenNodesJoinModes, testCase.givenLinks)
 
-			err := system.Activate()
+			err := system.ActivateInPlace()
 			if err != nil {
 				errs = append(errs, err)
 			}
@@ -573,7 +577,8 @@ func Test_NodeSystem_Activate(t *testing.T) {
 			}
 
 			if testCase.givenNodesAfterActivation != nil || testCase.givenNodesJoinModesAfterActivation != nil || testCase.givenLinksAfterActivation != nil {
-				_, validityErrs := system.IsValid()
+				_, validationErr := system.IsValid()
+				validityErrs := validationErrors(validationErr)
 				errs = append(errs, validityErrs...)
 			}
 
@@ -598,8 +603,8 @@ func Test_NodeSystem_Activate(t *testing.T) {
 
 func Test_NodeSystem_multiple_activatation(t *testing.T) {
 	system := NewNodeSystem()
-	system.Activate()
-	err := system.Activate()
+	system.ActivateInPlace()
+	err := system.ActivateInPlace()
 	if err != nil {
 		t.Errorf("Can be activate multiple times without errors, but got: %+v", err)
 	}
@@ -710,7 +715,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 					system.AddLinkOnBranch(link.From, link.To, *link.Branch)
 				}
 			}
-			system.Activate()
+			system.ActivateInPlace()
 			nodes, err := system.Follow(testCase.givenNode, testCase.givenBranch)
 
 			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
@@ -820,7 +825,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 			system := NewNodeSystem()
 			loadNodeSystem(system, testCase.givenNodes, nil, testCase.givenLinks)
 
-			system.Activate()
+			system.ActivateInPlace()
 			nodes, err := system.Ancestors(testCase.givenNode, testCase.givenBranch)
 
 			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
@@ -840,7 +845,7 @@ func Test_JoinModeOfNode_found(t *testing.T) {
 	system := NewNodeSystem()
 	system.AddNode(givenNode)
 	system.ConfigureJoinModeOnNode(givenNode, givenJoinMode)
-	system.Activate()
+	system.ActivateInPlace()
 
 	storedJoinMode := system.JoinModeOfNode(givenNode)
 
@@ -854,7 +859,7 @@ func Test_JoinModeOfNode_notfound(t *testing.T) {
 
 	system := NewNodeSystem()
 	system.AddNode(givenNode)
-	system.Activate()
+	system.ActivateInPlace()
 
 	storedJoinMode := system.JoinModeOfNode(givenNode)
 
@@ -863,6 +868,109 @@ func Test_JoinModeOfNode_notfound(t *testing.T) {
 	}
 }
 
+func Test_FlagOfNode_found(t *testing.T) {
+	givenNode := someActionNode
+	givenFlag := "new_step"
+
+	system := NewNodeSystem()
+	system.AddNode(givenNode)
+	system.ConfigureEnabledWhen(givenNode, givenFlag)
+	system.ActivateInPlace()
+
+	storedFlag, found := system.FlagOfNode(givenNode)
+
+	if !found || givenFlag != storedFlag {
+		t.Errorf("got: %+v (found: %+v), want: %+v", storedFlag, found, givenFlag)
+	}
+}
+
+func Test_FlagOfNode_notfound(t *testing.T) {
+	givenNode := someActionNode
+
+	system := NewNodeSystem()
+	system.AddNode(givenNode)
+	system.ActivateInPlace()
+
+	_, found := system.FlagOfNode(givenNode)
+
+	if found {
+		t.Errorf("got: %+v, want: %+v", found, false)
+	}
+}
+
+func Test_NodeSystem_ConfigureEnabledWhen_after_activation(t *testing.T) {
+	system := NewNodeSystem()
+	system.AddNode(someActionNode)
+	system.ActivateInPlace()
+
+	_, err := system.ConfigureEnabledWhen(someActionNode, "new_step")
+	expectedError := errors.New("can't add node feature flag, node system is freeze due to activation")
+
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_NodeSystem_ConfigureTagsOnNode(t *testing.T) {
+	system := NewNodeSystem()
+	system.AddNode(someActionNode)
+	system.ConfigureTagsOnNode(someActionNode, "external", "slow")
+	system.ConfigureTagsOnNode(someActionNode, "slow", "db")
+
+	tags := system.TagsOfNode(someActionNode)
+	expectedTags := []string{"external", "slow", "db"}
+	if !cmp.Equal(tags, expectedTags) {
+		t.Errorf("tags - got: %+v, want: %+v", tags, expectedTags)
+	}
+	if system.HaveTag(someActionNode, "fast") {
+		t.Errorf("have tag - got: %+v, want: %+v", true, false)
+	}
+}
+
+func Test_NodeSystem_ConfigureMetadataOnLink(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenBranch      *bool
+		givenMetadata    []map[string]string
+		expectedMetadata map[string]string
+		expectedError    error
+	}{
+		{
+			name:             "Can add metadata on a link",
+			givenBranch:      boolPointer(true),
+			givenMetadata:    []map[string]string{{"owner": "team"}, {"sla": "1s"}},
+			expectedMetadata: map[string]string{"owner": "team", "sla": "1s"},
+		},
+		{
+			name:          "Can't add metadata on a missing link",
+			givenBranch:   boolPointer(false),
+			givenMetadata: []map[string]string{{"owner": "team"}},
+			expectedError: errors.New("can't find link to add metadata: {from:'alwaysTrueDecisionNode' to:'someActionNode' branch:false}"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			system := NewNodeSystem()
+			system.AddNode(alwaysTrueDecisionNode)
+			system.AddNode(someActionNode)
+			system.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+
+			var err error
+			for _, metadata := range testCase.givenMetadata {
+				_, err = system.ConfigureMetadataOnLink(alwaysTrueDecisionNode, someActionNode, testCase.givenBranch, metadata)
+			}
+			metadata := system.MetadataOfLink(alwaysTrueDecisionNode, someActionNode, testCase.givenBranch)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(metadata, testCase.expectedMetadata) {
+				t.Errorf("metadata - got: %+v, want: %+v", metadata, testCase.expectedMetadata)
+			}
+		})
+	}
+}
+
 func Test_Github_Issue_10(t *testing.T) {
 	action1, _ := NewActionNode("action1", func(c *Context) error {
 		return nil
@@ -887,7 +995,8 @@ func Test_Github_Issue_10(t *testing.T) {
 	ns.AddLinkOnBranch(decision2, action4, false)
 	ns.AddLinkOnBranch(decision3, action4, true)
 	ns.ConfigureJoinModeOnNode(action4, JoinNone)
-	_, errs := ns.IsValid()
+	_, validationErr := ns.IsValid()
+	errs := validationErrors(validationErr)
 
 	expectedErrors := []error{
 		errors.New("can't have multiple links (2) to the same node: action4 without join mode"),
@@ -922,7 +1031,8 @@ func Test_Github_Issue_16(t *testing.T) {
 	ns.AddLink(a2, a3)
 	ns.AddLink(a3, a2)
 	ns.ConfigureJoinModeOnNode(a2, JoinAnd)
-	_, errs := ns.IsValid()
+	_, validationErr := ns.IsValid()
+	errs := validationErrors(validationErr)
 
 	expectedErrors := []error{
 		fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
@@ -986,7 +1096,8 @@ func Test_multiple_cycles(t *testing.T) {
 	ns.AddLink(a6, a7)
 	ns.AddLink(a7, a5)
 	ns.ConfigureJoinModeOnNode(a5, JoinAnd)
-	_, errs := ns.IsValid()
+	_, validationErr := ns.IsValid()
+	errs := validationErrors(validationErr)
 
 	expectedErrors := []error{
 		fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
@@ -0,0 +1,201 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownNodeType is the error of a node type not registered (see RegisterNodeType).
+var ErrUnknownNodeType = errors.New("unknown node type")
+
+// NodeFactory create a node of a type, named after the given name and configured with the given config.
+type NodeFactory func(name string, config map[string]string) (Node, error)
+
+var (
+	registeredNodeTypesMu sync.RWMutex
+	registeredNodeTypes   = make(map[string]NodeFactory)
+)
+
+// RegisterNodeType register by name the factory of a node type of a node library,
+// to create its nodes from a serialized workflow (see NewNodeSystemFromDescription).
+// A node library distributed as a Go plugin register its node types in its init function (see LoadPlugin).
+func RegisterNodeType(name string, factory NodeFactory) error {
+	if name == "" {
+		return errors.New("can't register a node type without name")
+	}
+	if factory == nil {
+		return fmt.Errorf("can't register node type '%v' without factory", name)
+	}
+	registeredNodeTypesMu.Lock()
+	defer registeredNodeTypesMu.Unlock()
+	if _, found := registeredNodeTypes[name]; found {
+		return fmt.Errorf("can't register node type '%v', already registered", name)
+	}
+	registeredNodeTypes[name] = factory
+	return nil
+}
+
+// NodeTypes give the names of the registered node types, sorted.
+func NodeTypes() []string {
+	registeredNodeTypesMu.RLock()
+	defer registeredNodeTypesMu.RUnlock()
+	names := make([]string, 0, len(registeredNodeTypes))
+	for name := range registeredNodeTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewNodeOfType create a node of a registered node type.
+func NewNodeOfType(typeName, name string, config map[string]string) (Node, error) {
+	registeredNodeTypesMu.RLock()
+	factory, found := registeredNodeTypes[typeName]
+	registeredNodeTypesMu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("can't create node '%v': %w '%v'", name, ErrUnknownNodeType, typeName)
+	}
+	node, err := factory(name, config)
+	if err != nil {
+		return nil, fmt.Errorf("can't create node '%v' of type '%v': %w", name, typeName, err)
+	}
+	if node == nil {
+		return nil, fmt.Errorf("can't create node '%v' of type '%v' without node", name, typeName)
+	}
+	return node, nil
+}
+
+// registeredNodeType is the type of a node created from a registered node type, with its config.
+type registeredNodeType struct {
+	Name   string
+	Config map[string]string
+}
+
+// ConfigureTypeOnNode set the registered node type, and its config, used to create a node,
+// to keep them in the description of the node system.
+func (s *NodeSystem) ConfigureTypeOnNode(n Node, typeName string, config map[string]string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add node type, node system is freeze due to activation")
+	}
+	if s.nodesTypes == nil {
+		s.nodesTypes = make(map[string]registeredNodeType)
+	}
+	var nodeConfig map[string]string
+	if len(config) > 0 {
+		nodeConfig = make(map[string]string, len(config))
+		for key, value := range config {
+			nodeConfig[key] = value
+		}
+	}
+	s.nodesTypes[s.nodeID(n)] = registeredNodeType{Name: typeName, Config: nodeConfig}
+	return true, nil
+}
+
+// TypeOfNode get the registered node type, and its config, of a node, if any.
+func (s *NodeSystem) TypeOfNode(n Node) (string, map[string]string) {
+	typ := s.nodesTypes[s.nodeID(n)]
+	return typ.Name, typ.Config
+}
+
+// NewNodeSystemFromDescription create a node system from a description, like one read from a serialized workflow.
+// The described nodes with a type are created from the registered node types, and the others are resolved
+// against the given nodes by name. The expression and transform nodes are created from their description.
+func NewNodeSystemFromDescription(description SystemDescription, nodes ...Node) (*NodeSystem, error) {
+	namedNodes := make(map[string]Node, len(nodes))
+	for _, node := range nodes {
+		namedNodes[fmt.Sprint(node)] = node
+	}
+
+	system := NewNodeSystem()
+	describedNodes := make(map[string]Node, len(description.Nodes))
+	for _, nodeDescription := range description.Nodes {
+		node, err := describedNode(nodeDescription, namedNodes)
+		if err != nil {
+			return nil, err
+		}
+		describedNodes[nodeDescription.Name] = node
+
+		_, err = system.AddNode(node)
+		if err != nil {
+			return nil, err
+		}
+		if nodeDescription.Type != "" {
+			system.ConfigureTypeOnNode(node, nodeDescription.Type, nodeDescription.Config)
+		}
+		if nodeDescription.JoinMode != "" {
+			_, err = system.ConfigureJoinModeOnNode(node, nodeDescription.JoinMode)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(nodeDescription.Tags) > 0 {
+			_, err = system.ConfigureTagsOnNode(node, nodeDescription.Tags...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, link := range description.Links {
+		from, foundFrom := describedNodes[link.From]
+		to, foundTo := describedNodes[link.To]
+		if !foundFrom || !foundTo {
+			return nil, fmt.Errorf("can't create link from '%v' to '%v' with missing node", link.From, link.To)
+		}
+		var err error
+		if link.Branch == nil {
+			_, err = system.AddLink(from, to)
+		} else {
+			_, err = system.AddLinkOnBranch(from, to, *link.Branch)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(link.Metadata) > 0 {
+			_, err = system.ConfigureMetadataOnLink(from, to, link.Branch, link.Metadata)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, input := range description.Inputs {
+		_, err := system.DeclareInput(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, output := range description.Outputs {
+		_, err := system.DeclareOutput(output)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return system, nil
+}
+
+// describedNode give the node of a description, created from its type or resolved by name.
+func describedNode(description NodeDescription, namedNodes map[string]Node) (Node, error) {
+	switch {
+	case description.Type != "":
+		return NewNodeOfType(description.Type, description.Name, description.Config)
+	case description.Expression != "":
+		node, err := NewExpressionDecisionNode(description.Name, description.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	case len(description.Transforms) > 0:
+		node, err := NewTransformNode(description.Name, description.Transforms...)
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	node, found := namedNodes[description.Name]
+	if !found {
+		return nil, fmt.Errorf("can't create node '%v' without type or matching node", description.Name)
+	}
+	return node, nil
+}
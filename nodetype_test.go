@@ -0,0 +1,213 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func init() {
+	RegisterNodeType("test-echo", func(name string, config map[string]string) (Node, error) {
+		key, found := config["key"]
+		if !found {
+			return nil, errors.New("missing key")
+		}
+		return NewActionNode(name, func(c *Context) error {
+			c.Store(key, config["value"])
+			return nil
+		})
+	})
+}
+
+func Test_RegisterNodeType(t *testing.T) {
+	factory := func(name string, config map[string]string) (Node, error) {
+		return NewActionNode(name, func(*Context) error { return nil })
+	}
+	testCases := []struct {
+		name          string
+		givenName     string
+		givenFactory  NodeFactory
+		expectedError error
+	}{
+		{
+			name:         "Can register a node type",
+			givenName:    "test-noop",
+			givenFactory: factory,
+		},
+		{
+			name:          "Can't register a node type twice",
+			givenName:     "test-echo",
+			givenFactory:  factory,
+			expectedError: errors.New("can't register node type 'test-echo', already registered"),
+		},
+		{
+			name:          "Can't register a node type without name",
+			givenFactory:  factory,
+			expectedError: errors.New("can't register a node type without name"),
+		},
+		{
+			name:          "Can't register a node type without factory",
+			givenName:     "test-nil",
+			expectedError: errors.New("can't register node type 'test-nil' without factory"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := RegisterNodeType(testCase.givenName, testCase.givenFactory)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+	types := NodeTypes()
+	if !cmp.Equal(types, []string{"test-echo", "test-noop", "wasm"}) {
+		t.Errorf("types - got: %+v, want: %+v", types, []string{"test-echo", "test-noop", "wasm"})
+	}
+}
+
+func Test_NewNodeOfType(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenType     string
+		givenConfig   map[string]string
+		expectedNode  string
+		expectedError string
+		expectedIs    error
+	}{
+		{
+			name:         "Can create a node of a registered type",
+			givenType:    "test-echo",
+			givenConfig:  map[string]string{"key": "greeting"},
+			expectedNode: "echo",
+		},
+		{
+			name:          "Can't create a node of an unknown type",
+			givenType:     "test-unknown",
+			expectedError: "can't create node 'echo': unknown node type 'test-unknown'",
+			expectedIs:    ErrUnknownNodeType,
+		},
+		{
+			name:          "Can't create a node with an invalid config",
+			givenType:     "test-echo",
+			expectedError: "can't create node 'echo' of type 'test-echo': missing key",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewNodeOfType(testCase.givenType, "echo", testCase.givenConfig)
+
+			if testCase.expectedError != "" {
+				if err == nil || err.Error() != testCase.expectedError {
+					t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+				}
+				if testCase.expectedIs != nil && !errors.Is(err, testCase.expectedIs) {
+					t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error - got: %+v, want: nothing", err)
+			}
+			if fmt.Sprint(node) != testCase.expectedNode {
+				t.Errorf("node - got: %+v, want: %+v", node, testCase.expectedNode)
+			}
+		})
+	}
+}
+
+func Test_NewNodeSystemFromDescription(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenJSON     string
+		givenNodes    []Node
+		expectedData  map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "Can create a node system with registered node types",
+			givenJSON: `{"nodes": [
+				{"name": "hello", "type": "test-echo", "config": {"key": "greeting", "value": "hello"}},
+				{"name": "someActionNode"}
+			], "links": [{"from": "hello", "to": "someActionNode"}]}`,
+			givenNodes:   []Node{someActionNode},
+			expectedData: map[string]interface{}{"greeting": "hello"},
+		},
+		{
+			name: "Can create a node system with expression nodes",
+			givenJSON: `{"nodes": [
+				{"name": "hello", "type": "test-echo", "config": {"key": "greeting", "value": "hello"}},
+				{"name": "polite", "decision": true, "expression": "greeting == \"hello\""},
+				{"name": "someActionNode"}
+			], "links": [
+				{"from": "hello", "to": "polite"},
+				{"from": "polite", "to": "someActionNode", "branch": true}
+			]}`,
+			givenNodes:   []Node{someActionNode},
+			expectedData: map[string]interface{}{"greeting": "hello"},
+		},
+		{
+			name:          "Can't create a node system with an unknown node type",
+			givenJSON:     `{"nodes": [{"name": "hello", "type": "test-unknown"}], "links": []}`,
+			expectedError: "can't create node 'hello': unknown node type 'test-unknown'",
+		},
+		{
+			name:          "Can't create a node system without matching node",
+			givenJSON:     `{"nodes": [{"name": "someActionNode"}], "links": []}`,
+			expectedError: "can't create node 'someActionNode' without type or matching node",
+		},
+		{
+			name: "Can't create a node system with a link to a missing node",
+			givenJSON: `{"nodes": [
+				{"name": "hello", "type": "test-echo", "config": {"key": "greeting"}}
+			], "links": [{"from": "hello", "to": "missing"}]}`,
+			expectedError: "can't create link from 'hello' to 'missing' with missing node",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var description SystemDescription
+			err := description.UnmarshalJSON([]byte(testCase.givenJSON))
+			if err != nil {
+				t.Fatalf("unmarshal error - got: %+v, want: nothing", err)
+			}
+
+			system, err := NewNodeSystemFromDescription(description, testCase.givenNodes...)
+
+			if testCase.expectedError != "" {
+				if err == nil || err.Error() != testCase.expectedError {
+					t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error - got: %+v, want: nothing", err)
+			}
+			err = system.ActivateInPlace()
+			if err != nil {
+				t.Fatalf("activate error - got: %+v, want: nothing", err)
+			}
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(system)
+			result := eng.Compute(map[string]interface{}{})
+			if result.Error != nil {
+				t.Errorf("compute error - got: %+v, want: nothing", result.Error)
+			}
+			if !cmp.Equal(result.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", result.Data, testCase.expectedData)
+			}
+			if !cmp.Equal(system.Describe().Nodes, description.Nodes) {
+				t.Errorf("description - got: %+v, want: %+v", system.Describe().Nodes, description.Nodes)
+			}
+		})
+	}
+}
+
+func Test_LoadPlugin_missing(t *testing.T) {
+	err := LoadPlugin("missing.so")
+	if err == nil {
+		t.Errorf("error - got: nothing, want: an error")
+	}
+}
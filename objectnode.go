@@ -0,0 +1,237 @@
+package hoff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// ErrObjectNotFound is the error of a BlobStore for a missing object.
+var ErrObjectNotFound = errors.New("object not found")
+
+// BlobStore is an object storage, like S3, where the objects are streamed.
+type BlobStore interface {
+	// GetObject open an object by its key, a missing object give an error wrapping ErrObjectNotFound.
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+	// PutObject write an object under a key from a body.
+	PutObject(ctx context.Context, key string, body io.Reader) error
+}
+
+// S3Client is the part of an S3 client needed to get and put objects,
+// e.g. a thin wrapper around the client of an AWS SDK.
+type S3Client interface {
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, bucket, key string, body io.Reader) error
+}
+
+// S3BlobStore is a BlobStore on a S3 bucket, with the object keys prefixed.
+type S3BlobStore struct {
+	client S3Client
+	bucket string
+	prefix string
+}
+
+// NewS3BlobStore create a S3BlobStore based on a client, a bucket, and the prefix of the object keys, if any.
+func NewS3BlobStore(client S3Client, bucket, prefix string) (*S3BlobStore, error) {
+	if client == nil {
+		return nil, errors.New("can't create s3 blob store without client")
+	}
+	if bucket == "" {
+		return nil, errors.New("can't create s3 blob store without bucket")
+	}
+	return &S3BlobStore{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+// GetObject open an object of the bucket.
+func (s *S3BlobStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.client.GetObject(ctx, s.bucket, s.prefix+key)
+}
+
+// PutObject write an object in the bucket.
+func (s *S3BlobStore) PutObject(ctx context.Context, key string, body io.Reader) error {
+	return s.client.PutObject(ctx, s.bucket, s.prefix+key, body)
+}
+
+// MemoryBlobStore is a BlobStore keeping the objects in memory, mainly for testing.
+type MemoryBlobStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewMemoryBlobStore create an empty in memory blob store.
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{
+		objects: make(map[string][]byte),
+	}
+}
+
+// GetObject open an object.
+func (s *MemoryBlobStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, found := s.objects[key]
+	if !found {
+		return nil, fmt.Errorf("can't get object '%v': %w", key, ErrObjectNotFound)
+	}
+	return ioutil.NopCloser(bytes.NewReader(object)), nil
+}
+
+// PutObject write an object.
+func (s *MemoryBlobStore) PutObject(ctx context.Context, key string, body io.Reader) error {
+	object, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = object
+	return nil
+}
+
+// Keys give the keys of the objects, sorted.
+func (s *MemoryBlobStore) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ObjectGetNode is a type of Node who get an object of a BlobStore into a context key, as a string.
+// With a context store configured on the context (see Context.ConfigureStore), the object is kept
+// in the context store and only its reference in the context (see Context.StoreExternal).
+// The object key is a template (see text/template) executed on the context data, like 'orders/{{.order}}.json'.
+type ObjectGetNode struct {
+	name   string
+	store  BlobStore
+	key    *template.Template
+	target string
+}
+
+func (n ObjectGetNode) String() string {
+	return n.name
+}
+
+// Compute get the object and store its content.
+func (n *ObjectGetNode) Compute(c *Context) ComputeState {
+	key, err := executeNodeTemplate(n.name, n.key, c)
+	if err != nil {
+		return NewAbortWithCodeComputeState(ValidationAbort, err)
+	}
+	object, err := n.store.GetObject(c.GoContext(), key)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't get object of node '%v': %w", n.name, err))
+	}
+	defer object.Close()
+	var content strings.Builder
+	_, err = io.Copy(&content, object)
+	if err != nil {
+		return NewAbortWithCodeComputeState(TransientAbort, fmt.Errorf("can't get object of node '%v': %w", n.name, err))
+	}
+	if c.store == nil {
+		c.Store(n.target, content.String())
+		return NewContinueComputeState()
+	}
+	err = c.StoreExternal(n.target, content.String())
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a get don't take a decision.
+func (n *ObjectGetNode) DecideCapability() bool {
+	return false
+}
+
+// ProducedKeys give the context key of the object content.
+func (n *ObjectGetNode) ProducedKeys() []string {
+	return []string{n.target}
+}
+
+// NewObjectGetNode create an ObjectGetNode based on a name, a blob store, the template of the object key,
+// and the context key of the content.
+func NewObjectGetNode(name string, store BlobStore, key, target string) (*ObjectGetNode, error) {
+	if store == nil {
+		return nil, errors.New("can't create object node without blob store")
+	}
+	if target == "" {
+		return nil, errors.New("can't create object node without context key")
+	}
+	parsed, err := parseNodeTemplate("object", "key", key)
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectGetNode{name: name, store: store, key: parsed, target: target}, nil
+}
+
+// ObjectPutNode is a type of Node who put a context value as an object of a BlobStore,
+// a string or bytes value is written as is, another value is written as indented JSON.
+// The object key is a template (see text/template) executed on the context data, like 'invoices/{{.order}}.txt'.
+type ObjectPutNode struct {
+	name   string
+	store  BlobStore
+	key    *template.Template
+	source string
+}
+
+func (n ObjectPutNode) String() string {
+	return n.name
+}
+
+// Compute put the context value as an object.
+func (n *ObjectPutNode) Compute(c *Context) ComputeState {
+	key, err := executeNodeTemplate(n.name, n.key, c)
+	if err != nil {
+		return NewAbortWithCodeComputeState(ValidationAbort, err)
+	}
+	value, found := c.Read(n.source)
+	if !found {
+		return NewAbortWithCodeComputeState(ValidationAbort, fmt.Errorf("can't put object of node '%v' with missing key: %v", n.name, n.source))
+	}
+	content, err := encodeContent(value)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't put object of node '%v': %w", n.name, err))
+	}
+	err = n.store.PutObject(c.GoContext(), key, bytes.NewReader(content))
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't put object of node '%v': %w", n.name, err))
+	}
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a put don't take a decision.
+func (n *ObjectPutNode) DecideCapability() bool {
+	return false
+}
+
+// RequiredKeys give the context key of the put value.
+func (n *ObjectPutNode) RequiredKeys() []string {
+	return []string{n.source}
+}
+
+// NewObjectPutNode create an ObjectPutNode based on a name, a blob store, the template of the object key,
+// and the context key of the value to put.
+func NewObjectPutNode(name string, store BlobStore, key, source string) (*ObjectPutNode, error) {
+	if store == nil {
+		return nil, errors.New("can't create object node without blob store")
+	}
+	if source == "" {
+		return nil, errors.New("can't create object node without context key")
+	}
+	parsed, err := parseNodeTemplate("object", "key", key)
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectPutNode{name: name, store: store, key: parsed, source: source}, nil
+}
@@ -0,0 +1,247 @@
+package hoff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeS3Client is a S3Client keeping the objects of the buckets in memory.
+type fakeS3Client struct {
+	objects map[string]string
+}
+
+func (c *fakeS3Client) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	object, found := c.objects[bucket+"/"+key]
+	if !found {
+		return nil, fmt.Errorf("NoSuchKey: %v: %w", key, ErrObjectNotFound)
+	}
+	return ioutil.NopCloser(bytes.NewBufferString(object)), nil
+}
+
+func (c *fakeS3Client) PutObject(ctx context.Context, bucket, key string, body io.Reader) error {
+	object, err := ioutil.ReadAll(body)
+	c.objects[bucket+"/"+key] = string(object)
+	return err
+}
+
+func Test_NewS3BlobStore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenClient   S3Client
+		givenBucket   string
+		expectedError error
+	}{
+		{
+			name:        "Can create a s3 blob store",
+			givenClient: &fakeS3Client{},
+			givenBucket: "orders",
+		},
+		{
+			name:          "Can't create a s3 blob store without client",
+			givenBucket:   "orders",
+			expectedError: errors.New("can't create s3 blob store without client"),
+		},
+		{
+			name:          "Can't create a s3 blob store without bucket",
+			givenClient:   &fakeS3Client{},
+			expectedError: errors.New("can't create s3 blob store without bucket"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewS3BlobStore(testCase.givenClient, testCase.givenBucket, "")
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_S3BlobStore(t *testing.T) {
+	client := &fakeS3Client{objects: map[string]string{"orders/in/42.json": `{"id": 42}`}}
+	store, _ := NewS3BlobStore(client, "orders", "in/")
+	get, _ := NewObjectGetNode("get", store, "{{.order}}.json", "order_content")
+	put, _ := NewObjectPutNode("put", store, "{{.order}}.copy.json", "order_content")
+	c := NewContext(map[string]interface{}{"order": 42})
+
+	states := []ComputeState{get.Compute(c), put.Compute(c)}
+
+	expectedStates := []ComputeState{NewContinueComputeState(), NewContinueComputeState()}
+	if !cmp.Equal(states, expectedStates) {
+		t.Errorf("states - got: %+v, want: %+v", states, expectedStates)
+	}
+	expectedObjects := map[string]string{"orders/in/42.json": `{"id": 42}`, "orders/in/42.copy.json": `{"id": 42}`}
+	if !cmp.Equal(client.objects, expectedObjects) {
+		t.Errorf("objects - got: %+v, want: %+v", client.objects, expectedObjects)
+	}
+}
+
+func Test_ObjectGetNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenStore      ContextStore
+		givenData       map[string]interface{}
+		expectedState   ComputeState
+		expectedContent interface{}
+		expectedData    map[string]interface{}
+	}{
+		{
+			name:            "Can get an object",
+			givenData:       map[string]interface{}{"order": 42},
+			expectedState:   NewContinueComputeState(),
+			expectedContent: `{"id": 42}`,
+			expectedData:    map[string]interface{}{"order": 42, "content": `{"id": 42}`},
+		},
+		{
+			name:            "Can get an object into the context store",
+			givenStore:      NewMemoryContextStore(),
+			givenData:       map[string]interface{}{"order": 42},
+			expectedState:   NewContinueComputeState(),
+			expectedContent: `{"id": 42}`,
+		},
+		{
+			name:          "Can't get a missing object",
+			givenData:     map[string]interface{}{"order": 43},
+			expectedState: NewAbortComputeState(errors.New("can't get object of node 'get': can't get object 'orders/43.json': object not found")),
+			expectedData:  map[string]interface{}{"order": 43},
+		},
+		{
+			name:          "Can't get an object without key",
+			givenData:     map[string]interface{}{},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New(`can't build key of node 'get': template: key:1:9: executing "key" at <.order>: map has no entry for key "order"`)),
+			expectedData:  map[string]interface{}{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			blobs := NewMemoryBlobStore()
+			blobs.PutObject(context.Background(), "orders/42.json", bytes.NewBufferString(`{"id": 42}`))
+			node, _ := NewObjectGetNode("get", blobs, "orders/{{.order}}.json", "content")
+			c := NewContext(testCase.givenData)
+			if testCase.givenStore != nil {
+				c.ConfigureStore(testCase.givenStore)
+			}
+
+			state := node.Compute(c)
+
+			if errorMessage(state.Error) != errorMessage(testCase.expectedState.Error) || state.Value != testCase.expectedState.Value || state.Code != testCase.expectedState.Code {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			content, _ := c.Read("content")
+			if !cmp.Equal(content, testCase.expectedContent) {
+				t.Errorf("content - got: %+v, want: %+v", content, testCase.expectedContent)
+			}
+			if _, isReference := c.Data["content"].(ContextReference); isReference != (testCase.givenStore != nil) {
+				t.Errorf("reference - got: %+v, want: %+v", isReference, testCase.givenStore != nil)
+			}
+			if testCase.expectedData != nil && !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_ObjectPutNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenData       map[string]interface{}
+		expectedState   ComputeState
+		expectedKeys    []string
+		expectedContent string
+	}{
+		{
+			name:            "Can put a string",
+			givenData:       map[string]interface{}{"order": 42, "invoice": "total: 10"},
+			expectedState:   NewContinueComputeState(),
+			expectedKeys:    []string{"invoices/42.txt"},
+			expectedContent: "total: 10",
+		},
+		{
+			name:            "Can put a value as JSON",
+			givenData:       map[string]interface{}{"order": 42, "invoice": map[string]interface{}{"total": 10}},
+			expectedState:   NewContinueComputeState(),
+			expectedKeys:    []string{"invoices/42.txt"},
+			expectedContent: "{\n  \"total\": 10\n}",
+		},
+		{
+			name:          "Can't put a missing value",
+			givenData:     map[string]interface{}{"order": 42},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't put object of node 'put' with missing key: invoice")),
+			expectedKeys:  []string{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			blobs := NewMemoryBlobStore()
+			node, _ := NewObjectPutNode("put", blobs, "invoices/{{.order}}.txt", "invoice")
+
+			state := node.Compute(NewContext(testCase.givenData))
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(blobs.Keys(), testCase.expectedKeys) {
+				t.Errorf("keys - got: %+v, want: %+v", blobs.Keys(), testCase.expectedKeys)
+			}
+			if len(testCase.expectedKeys) > 0 {
+				object, _ := blobs.GetObject(context.Background(), testCase.expectedKeys[0])
+				content, _ := ioutil.ReadAll(object)
+				if string(content) != testCase.expectedContent {
+					t.Errorf("content - got: %+v, want: %+v", string(content), testCase.expectedContent)
+				}
+			}
+		})
+	}
+}
+
+func Test_NewObjectGetNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenStore    BlobStore
+		givenKey      string
+		givenTarget   string
+		expectedError error
+	}{
+		{
+			name:        "Can create an object get node",
+			givenStore:  NewMemoryBlobStore(),
+			givenKey:    "orders/{{.order}}.json",
+			givenTarget: "content",
+		},
+		{
+			name:          "Can't create an object get node without blob store",
+			givenKey:      "orders/{{.order}}.json",
+			givenTarget:   "content",
+			expectedError: errors.New("can't create object node without blob store"),
+		},
+		{
+			name:          "Can't create an object get node without object key",
+			givenStore:    NewMemoryBlobStore(),
+			givenTarget:   "content",
+			expectedError: errors.New("can't create object node without key"),
+		},
+		{
+			name:          "Can't create an object get node without context key",
+			givenStore:    NewMemoryBlobStore(),
+			givenKey:      "orders/{{.order}}.json",
+			expectedError: errors.New("can't create object node without context key"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewObjectGetNode("get", testCase.givenStore, testCase.givenKey, testCase.givenTarget)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package hoff
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// OptimizationChangeKind is the kind of a change made by the optimization of a node system.
+type OptimizationChangeKind string
+
+const (
+	// UnreachableNodeRemoved is the removal of a node never computed from the initial nodes
+	UnreachableNodeRemoved OptimizationChangeKind = "unreachable-node-removed"
+	// DuplicateLinkRemoved is the removal of a node link declared more than once
+	DuplicateLinkRemoved OptimizationChangeKind = "duplicate-link-removed"
+	// DuplicatePortLinkRemoved is the removal of a port link declared more than once
+	DuplicatePortLinkRemoved OptimizationChangeKind = "duplicate-port-link-removed"
+)
+
+// OptimizationChange describe a change made by the optimization of a node system.
+type OptimizationChange struct {
+	Kind        OptimizationChangeKind
+	Description string
+}
+
+func (c OptimizationChange) String() string {
+	return fmt.Sprintf("%v: %v", c.Kind, c.Description)
+}
+
+// Optimize give an equivalent not activated copy of the node system, smaller to activate and compute,
+// e.g. for very large generated workflows, and the changes made in order to get it.
+// The duplicate node links and port links are removed, the metadata of a duplicate link being merged into the kept link.
+// When initial nodes are declared, the nodes who can't be reached from them are removed
+// unless they are linked to a reachable node, to keep the join modes of the reachable nodes as is.
+func (s *NodeSystem) Optimize() (*NodeSystem, []OptimizationChange) {
+	o := s.copy()
+	changes := make([]OptimizationChange, 0)
+	changes = append(changes, o.removeDuplicateLinks()...)
+	changes = append(changes, o.removeUnreachableNodes()...)
+	return o, changes
+}
+
+func (s *NodeSystem) removeDuplicateLinks() []OptimizationChange {
+	changes := make([]OptimizationChange, 0)
+	links := make([]nodeLink, 0, len(s.links))
+	for _, link := range s.links {
+		duplicate := -1
+		for index, kept := range links {
+			if s.sameNode(kept.From, link.From) && s.sameNode(kept.To, link.To) && cmp.Equal(kept.Branch, link.Branch) {
+				duplicate = index
+				break
+			}
+		}
+		if duplicate < 0 {
+			links = append(links, link)
+			continue
+		}
+		for key, value := range link.Metadata {
+			if links[duplicate].Metadata == nil {
+				links[duplicate].Metadata = make(map[string]string)
+			}
+			if _, found := links[duplicate].Metadata[key]; !found {
+				links[duplicate].Metadata[key] = value
+			}
+		}
+		changes = append(changes, OptimizationChange{Kind: DuplicateLinkRemoved, Description: link.String()})
+	}
+	s.links = links
+
+	portLinks := make([]portLink, 0, len(s.portLinks))
+	for _, link := range s.portLinks {
+		duplicate := false
+		for _, kept := range portLinks {
+			if s.sameNode(kept.From, link.From) && s.sameNode(kept.To, link.To) && kept.Output == link.Output && kept.Input == link.Input {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			changes = append(changes, OptimizationChange{Kind: DuplicatePortLinkRemoved, Description: fmt.Sprintf("%v.%v -> %v.%v", link.From, link.Output, link.To, link.Input)})
+			continue
+		}
+		portLinks = append(portLinks, link)
+	}
+	s.portLinks = portLinks
+	return changes
+}
+
+func (s *NodeSystem) removeUnreachableNodes() []OptimizationChange {
+	changes := make([]OptimizationChange, 0)
+	if len(s.declaredInitialNodes) == 0 {
+		return changes
+	}
+
+	kept := make(map[string]bool, len(s.nodes))
+	queue := make([]string, 0, len(s.declaredInitialNodes))
+	for _, node := range s.declaredInitialNodes {
+		kept[s.nodeID(node)] = true
+		queue = append(queue, s.nodeID(node))
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, link := range s.links {
+			to := s.nodeID(link.To)
+			if s.nodeID(link.From) == id && !kept[to] {
+				kept[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, link := range s.links {
+			from := s.nodeID(link.From)
+			if !kept[from] && kept[s.nodeID(link.To)] {
+				kept[from] = true
+				changed = true
+			}
+		}
+	}
+
+	nodes := make([]Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		id := s.nodeID(node)
+		if kept[id] {
+			nodes = append(nodes, node)
+			continue
+		}
+		delete(s.nodesJoinModes, id)
+		delete(s.nodesJoinExpressions, id)
+		delete(s.nodesFlags, id)
+		delete(s.nodesTags, id)
+		delete(s.nodesKeys, id)
+		delete(s.nodesPorts, id)
+		delete(s.terminalNodes, id)
+		delete(s.nodesProbabilities, id)
+		changes = append(changes, OptimizationChange{Kind: UnreachableNodeRemoved, Description: fmt.Sprintf("%v", node)})
+	}
+	s.nodes = nodes
+
+	links := make([]nodeLink, 0, len(s.links))
+	for _, link := range s.links {
+		if kept[s.nodeID(link.From)] {
+			links = append(links, link)
+		}
+	}
+	s.links = links
+	portLinks := make([]portLink, 0, len(s.portLinks))
+	for _, link := range s.portLinks {
+		if kept[s.nodeID(link.From)] && kept[s.nodeID(link.To)] {
+			portLinks = append(portLinks, link)
+		}
+	}
+	s.portLinks = portLinks
+	return changes
+}
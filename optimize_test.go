@@ -0,0 +1,122 @@
+package hoff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_Optimize(t *testing.T) {
+	start := &ActionNode{name: "start", actionFunc: func(*Context) error { return nil }}
+	next := &ActionNode{name: "next", actionFunc: func(*Context) error { return nil }}
+	orphan := &ActionNode{name: "orphan", actionFunc: func(*Context) error { return nil }}
+	dead := &ActionNode{name: "dead", actionFunc: func(*Context) error { return nil }}
+	upstream := &ActionNode{name: "upstream", actionFunc: func(*Context) error { return nil }}
+
+	testCases := []struct {
+		name            string
+		givenSystem     func(ns *NodeSystem)
+		expectedNodes   []Node
+		expectedChanges []OptimizationChange
+		expectedValid   bool
+	}{
+		{
+			name: "Can remove duplicate links",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(start)
+				ns.AddNode(next)
+				ns.AddLink(start, next)
+				ns.AddLink(start, next)
+			},
+			expectedNodes: []Node{start, next},
+			expectedChanges: []OptimizationChange{
+				{Kind: DuplicateLinkRemoved, Description: "{from:'start' to:'next'}"},
+			},
+			expectedValid: true,
+		},
+		{
+			name: "Can remove nodes unreachable from the initial nodes",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(start)
+				ns.AddNode(next)
+				ns.AddNode(orphan)
+				ns.AddNode(dead)
+				ns.AddLink(start, next)
+				ns.AddLink(orphan, dead)
+				ns.ConfigureTagsOnNode(dead, "slow")
+				ns.DeclareInitialNode(start)
+			},
+			expectedNodes: []Node{start, next},
+			expectedChanges: []OptimizationChange{
+				{Kind: UnreachableNodeRemoved, Description: "orphan"},
+				{Kind: UnreachableNodeRemoved, Description: "dead"},
+			},
+			expectedValid: true,
+		},
+		{
+			name: "Can keep unreachable nodes linked to reachable nodes",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(start)
+				ns.AddNode(next)
+				ns.AddNode(upstream)
+				ns.AddLink(start, next)
+				ns.AddLink(upstream, next)
+				ns.ConfigureJoinModeOnNode(next, JoinAnd)
+				ns.DeclareInitialNode(start)
+			},
+			expectedNodes:   []Node{start, next, upstream},
+			expectedChanges: []OptimizationChange{},
+		},
+		{
+			name: "Can't remove nodes without declared initial nodes",
+			givenSystem: func(ns *NodeSystem) {
+				ns.AddNode(start)
+				ns.AddNode(orphan)
+			},
+			expectedNodes:   []Node{start, orphan},
+			expectedChanges: []OptimizationChange{},
+			expectedValid:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			testCase.givenSystem(ns)
+			before := len(ns.nodes)
+
+			optimized, changes := ns.Optimize()
+
+			if !cmp.Equal(changes, testCase.expectedChanges) {
+				t.Errorf("changes - got: %+v, want: %+v", changes, testCase.expectedChanges)
+			}
+			if !cmp.Equal(optimized.nodes, testCase.expectedNodes, NodeComparator) {
+				t.Errorf("nodes - got: %+v, want: %+v", optimized.nodes, testCase.expectedNodes)
+			}
+			if valid, _ := optimized.IsValid(); valid != testCase.expectedValid {
+				t.Errorf("valid - got: %+v, want: %+v", valid, testCase.expectedValid)
+			}
+			if len(ns.nodes) != before {
+				t.Errorf("original nodes - got: %+v, want: %+v", len(ns.nodes), before)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_Optimize_mergeMetadata(t *testing.T) {
+	start := &ActionNode{name: "start", actionFunc: func(*Context) error { return nil }}
+	next := &ActionNode{name: "next", actionFunc: func(*Context) error { return nil }}
+	ns := NewNodeSystem()
+	ns.AddNode(start)
+	ns.AddNode(next)
+	ns.AddLink(start, next)
+	ns.AddLink(start, next)
+	ns.links[0].Metadata = map[string]string{"owner": "first"}
+	ns.links[1].Metadata = map[string]string{"owner": "second", "sla": "1h"}
+
+	optimized, _ := ns.Optimize()
+
+	expected := map[string]string{"owner": "first", "sla": "1h"}
+	if metadata := optimized.MetadataOfLink(start, next, nil); !cmp.Equal(metadata, expected) {
+		t.Errorf("got: %+v, want: %+v", metadata, expected)
+	}
+}
@@ -0,0 +1,75 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OutputParameter is a context key given as output of the computations of a node system.
+// A required output parameter must be in the context at the end of a computation.
+type OutputParameter struct {
+	Key         string    `json:"key"`
+	Type        InputType `json:"type"`
+	Required    bool      `json:"required,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// DeclareOutput add an output parameter into the system before activation.
+// Once an output is declared, the computation results expose the declared outputs only in their Outputs.
+func (s *NodeSystem) DeclareOutput(parameter OutputParameter) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't declare output, node system is freeze due to activation")
+	}
+	if parameter.Key == "" {
+		return false, errors.New("can't declare output without key")
+	}
+	if !isInputType(parameter.Type) {
+		return false, fmt.Errorf("can't declare output '%v' with unknown type: %v", parameter.Key, parameter.Type)
+	}
+	for _, output := range s.outputs {
+		if output.Key == parameter.Key {
+			return false, fmt.Errorf("can't declare output '%v' twice", parameter.Key)
+		}
+	}
+	s.outputs = append(s.outputs, parameter)
+	return true, nil
+}
+
+// OutputContract get the declared output parameters, in declaration order.
+func (s *NodeSystem) OutputContract() []OutputParameter {
+	return append([]OutputParameter(nil), s.outputs...)
+}
+
+// ValidateOutput check the context data at the end of a computation against the declared output parameters.
+// The errors of all the invalid output parameters are given as a ValidationError.
+func (s *NodeSystem) ValidateOutput(data map[string]interface{}) error {
+	errs := make([]error, 0)
+	for _, output := range s.outputs {
+		value, found := data[output.Key]
+		switch {
+		case !found && output.Required:
+			errs = append(errs, fmt.Errorf("can't have missing required output: %v", output.Key))
+		case found && !matchInputType(output.Type, value):
+			errs = append(errs, fmt.Errorf("can't have output '%v' not of type %v: %v", output.Key, output.Type, value))
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// outputsOf give the values of the declared outputs found in the context data,
+// or nil without declared outputs.
+func (s *NodeSystem) outputsOf(data map[string]interface{}) map[string]interface{} {
+	if len(s.outputs) == 0 {
+		return nil
+	}
+	outputs := make(map[string]interface{}, len(s.outputs))
+	for _, output := range s.outputs {
+		if value, found := data[output.Key]; found {
+			outputs[output.Key] = value
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,157 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_DeclareOutput(t *testing.T) {
+	activatedSystem := NewNodeSystem()
+	activatedSystem.AddNode(someActionNode)
+	activatedSystem.ActivateInPlace()
+
+	testCases := []struct {
+		name             string
+		givenSystem      *NodeSystem
+		givenOutputs     []OutputParameter
+		expectedResult   bool
+		expectedError    error
+		expectedContract []OutputParameter
+	}{
+		{
+			name:             "Can declare outputs",
+			givenSystem:      NewNodeSystem(),
+			givenOutputs:     []OutputParameter{{Key: "message", Type: StringInput, Required: true}, {Key: "count", Type: NumberInput}},
+			expectedResult:   true,
+			expectedContract: []OutputParameter{{Key: "message", Type: StringInput, Required: true}, {Key: "count", Type: NumberInput}},
+		},
+		{
+			name:          "Can't declare output without key",
+			givenSystem:   NewNodeSystem(),
+			givenOutputs:  []OutputParameter{{Type: StringInput}},
+			expectedError: errors.New("can't declare output without key"),
+		},
+		{
+			name:          "Can't declare output with unknown type",
+			givenSystem:   NewNodeSystem(),
+			givenOutputs:  []OutputParameter{{Key: "message", Type: "text"}},
+			expectedError: errors.New("can't declare output 'message' with unknown type: text"),
+		},
+		{
+			name:             "Can't declare output twice",
+			givenSystem:      NewNodeSystem(),
+			givenOutputs:     []OutputParameter{{Key: "message", Type: StringInput}, {Key: "message", Type: AnyInput}},
+			expectedError:    errors.New("can't declare output 'message' twice"),
+			expectedContract: []OutputParameter{{Key: "message", Type: StringInput}},
+		},
+		{
+			name:          "Can't declare output on activated node system",
+			givenSystem:   activatedSystem,
+			givenOutputs:  []OutputParameter{{Key: "message", Type: StringInput}},
+			expectedError: errors.New("can't declare output, node system is freeze due to activation"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var result bool
+			var err error
+			for _, output := range testCase.givenOutputs {
+				result, err = testCase.givenSystem.DeclareOutput(output)
+			}
+
+			if result != testCase.expectedResult {
+				t.Errorf("result - got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			contract := testCase.givenSystem.OutputContract()
+			if !cmp.Equal(contract, testCase.expectedContract) {
+				t.Errorf("contract - got: %+v, want: %+v", contract, testCase.expectedContract)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_ValidateOutput(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.DeclareOutput(OutputParameter{Key: "message", Type: StringInput, Required: true})
+	ns.DeclareOutput(OutputParameter{Key: "count", Type: NumberInput})
+
+	testCases := []struct {
+		name           string
+		givenData      map[string]interface{}
+		expectedErrors []error
+	}{
+		{
+			name:      "Can validate output",
+			givenData: map[string]interface{}{"message": "done", "count": 2, "other": true},
+		},
+		{
+			name:      "Can validate output without optional output",
+			givenData: map[string]interface{}{"message": "done"},
+		},
+		{
+			name:      "Can't validate output with missing required output or wrong types",
+			givenData: map[string]interface{}{"count": "two"},
+			expectedErrors: []error{
+				errors.New("can't have missing required output: message"),
+				errors.New("can't have output 'count' not of type number: two"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ns.ValidateOutput(testCase.givenData)
+
+			if !cmp.Equal(validationErrors(err), testCase.expectedErrors, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", validationErrors(err), testCase.expectedErrors)
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_outputContract(t *testing.T) {
+	writeMessage, _ := NewActionNode("writeMessage", func(c *Context) error {
+		c.Store("message", "done")
+		c.Store("internal", 1)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(writeMessage)
+	ns.DeclareOutput(OutputParameter{Key: "message", Type: StringInput, Required: true})
+	ns.DeclareOutput(OutputParameter{Key: "count", Type: NumberInput})
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	result := eng.Compute(map[string]interface{}{})
+	expectedOutputs := map[string]interface{}{"message": "done"}
+	if result.Error != nil || !cmp.Equal(result.Outputs, expectedOutputs) {
+		t.Errorf("valid output - got: %+v %+v, want: %+v %+v", result.Error, result.Outputs, nil, expectedOutputs)
+	}
+
+	result = eng.Compute(map[string]interface{}{"count": "two"})
+	expectedError := fmt.Errorf("can't end computation with invalid output: %w", &ValidationError{Errors: []error{errors.New("can't have output 'count' not of type number: two")}})
+	if errorMessage(result.Error) != errorMessage(expectedError) || result.Success {
+		t.Errorf("invalid output - got: %+v %+v, want: %+v %+v", result.Error, result.Success, expectedError, false)
+	}
+	expectedOutputs = map[string]interface{}{"message": "done", "count": "two"}
+	if !cmp.Equal(result.Outputs, expectedOutputs) {
+		t.Errorf("invalid output - got: %+v, want: %+v", result.Outputs, expectedOutputs)
+	}
+}
+
+func Test_Engine_Compute_withoutOutputContract(t *testing.T) {
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(activatedNodeSystem())
+
+	result := eng.Compute(map[string]interface{}{"key": "value"})
+	if result.Outputs != nil {
+		t.Errorf("got: %+v, want: %+v", result.Outputs, nil)
+	}
+}
@@ -0,0 +1,57 @@
+package hoff
+
+import (
+	"context"
+	"fmt"
+)
+
+// NodeOverride force the computation of a node whatever the branch decisions, e.g. for an operational mitigation.
+type NodeOverride string
+
+const (
+	// ForceSkip skip the node, and so its following nodes not forced to run
+	ForceSkip NodeOverride = "force_skip"
+	// ForceRun compute the node even if skipped by the branch decisions, once its ancestors are computed
+	ForceRun = "force_run"
+)
+
+// ComputeWithOverrides run computation against node system with input data,
+// and overrides of the nodes by name, recorded in their compute states.
+func (e *Engine) ComputeWithOverrides(data map[string]interface{}, overrides map[string]NodeOverride) ComputationResult {
+	return e.compute(context.Background(), data, SubmitOptions{Overrides: overrides}, nil)
+}
+
+// configureOverrides set the overrides of the nodes by name.
+func (cp *Computation) configureOverrides(overrides map[string]NodeOverride) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	nodes := make(map[string]Node, len(cp.System.nodes))
+	for _, node := range cp.System.nodes {
+		nodes[fmt.Sprint(node)] = node
+	}
+	cp.overrides = make(map[string]NodeOverride, len(overrides))
+	for name, override := range overrides {
+		node, found := nodes[name]
+		if !found {
+			return fmt.Errorf("can't override unknown node: %v", name)
+		}
+		if override != ForceSkip && override != ForceRun {
+			return fmt.Errorf("can't override node '%v' with unknown override: %v", name, override)
+		}
+		cp.overrides[cp.System.nodeID(node)] = override
+	}
+	return nil
+}
+
+// overrideComputeOrder apply the override of a node to its compute order.
+func (cp *Computation) overrideComputeOrder(node Node, order computeOrder) (computeOrder, NodeOverride) {
+	override, found := cp.overrides[cp.System.nodeID(node)]
+	if !found || (order != computeIt && order != skipIt) {
+		return order, ""
+	}
+	if override == ForceSkip {
+		return skipIt, override
+	}
+	return computeIt, override
+}
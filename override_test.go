@@ -0,0 +1,73 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ComputeWithOverrides(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	forcedSkip := NewSkipComputeState()
+	forcedSkip.Override = ForceSkip
+	forcedRun := NewContinueComputeState()
+	forcedRun.Override = ForceRun
+
+	testCases := []struct {
+		name           string
+		givenOverrides map[string]NodeOverride
+		expectedReport map[Node]ComputeState
+		expectedError  error
+	}{
+		{
+			name:           "Can force to skip a node",
+			givenOverrides: map[string]NodeOverride{"someActionNode": ForceSkip},
+			expectedReport: map[Node]ComputeState{
+				alwaysTrueDecisionNode: NewContinueOnBranchComputeState(true),
+				someActionNode:         forcedSkip,
+				anotherActionNode:      NewSkipComputeState(),
+			},
+		},
+		{
+			name:           "Can force to run a node on a branch not taken",
+			givenOverrides: map[string]NodeOverride{"anotherActionNode": ForceRun},
+			expectedReport: map[Node]ComputeState{
+				alwaysTrueDecisionNode: NewContinueOnBranchComputeState(true),
+				someActionNode:         NewContinueComputeState(),
+				anotherActionNode:      forcedRun,
+			},
+		},
+		{
+			name:           "Can't override an unknown node",
+			givenOverrides: map[string]NodeOverride{"unknown": ForceRun},
+			expectedError:  errors.New("can't override unknown node: unknown"),
+		},
+		{
+			name:           "Can't override a node with an unknown override",
+			givenOverrides: map[string]NodeOverride{"someActionNode": "force_wait"},
+			expectedError:  errors.New("can't override node 'someActionNode' with unknown override: force_wait"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := eng.ComputeWithOverrides(map[string]interface{}{}, testCase.givenOverrides)
+
+			if !cmp.Equal(result.Error, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.expectedError)
+			}
+			if testCase.expectedError == nil && !cmp.Equal(result.Report, testCase.expectedReport, NodeComparator) {
+				t.Errorf("report - got: %+v, want: %+v", result.Report, testCase.expectedReport)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package hoff
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy define how a node panicking during its computation on an engine is handled.
+type PanicPolicy string
+
+const (
+	// PanicAbort abort the panicking node, and so fail the computation.
+	PanicAbort PanicPolicy = "abort"
+	// PanicSkip skip the panicking node, and so its following nodes, to continue the computation.
+	// The panic stay as error of the skipped node compute state.
+	PanicSkip = "skip"
+)
+
+// PanicError is the error of a node who panicked during its computation, with the stack trace of the panic.
+type PanicError struct {
+	Node  string
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("can't compute node '%v', panic: %v", e.Node, e.Value)
+}
+
+// ConfigurePanicPolicy choose how to handle a node panicking during its computation,
+// the panic is recovered with a PanicError as error of the node (PanicAbort by default).
+func (e *Engine) ConfigurePanicPolicy(policy PanicPolicy) error {
+	if policy != PanicAbort && policy != PanicSkip {
+		return fmt.Errorf("can't handle panics with unknown policy: %v", policy)
+	}
+	e.panicPolicy = policy
+	return nil
+}
+
+func (e *Engine) recoverNodePanic(node Node, c *Context, compute func(*Context) ComputeState) (state ComputeState) {
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+		err := &PanicError{Node: fmt.Sprint(node), Value: value, Stack: debug.Stack()}
+		if e.panicPolicy == PanicSkip {
+			state = ComputeState{Value: SkipState, Error: err}
+			return
+		}
+		state = NewAbortComputeState(err)
+	}()
+	return compute(c)
+}
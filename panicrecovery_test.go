@@ -0,0 +1,94 @@
+package hoff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigurePanicPolicy(t *testing.T) {
+	eng := NewEngine(SequentialComputation)
+	err := eng.ConfigurePanicPolicy("ignore")
+	expectedError := errors.New("can't handle panics with unknown policy: ignore")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Engine_Compute_withPanickingNode(t *testing.T) {
+	panickingAction, _ := NewActionNode("panickingAction", func(*Context) error {
+		panic("boom")
+	})
+
+	testCases := []struct {
+		name            string
+		givenPolicy     PanicPolicy
+		givenWorkers    int
+		expectedState   StateType
+		expectedError   string
+		expectedReached bool
+	}{
+		{
+			name:          "Can abort a panicking node",
+			givenPolicy:   PanicAbort,
+			expectedState: AbortState,
+			expectedError: "can't compute node 'panickingAction', panic: boom",
+		},
+		{
+			name:          "Can abort a panicking node on a worker pool",
+			givenPolicy:   PanicAbort,
+			givenWorkers:  1,
+			expectedState: AbortState,
+			expectedError: "can't compute node 'panickingAction', panic: boom",
+		},
+		{
+			name:            "Can skip a panicking node",
+			givenPolicy:     PanicSkip,
+			expectedState:   SkipState,
+			expectedReached: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(panickingAction)
+			ns.AddNode(someActionNode)
+			ns.AddNode(anotherActionNode)
+			ns.AddLink(panickingAction, someActionNode)
+			ns.ActivateInPlace()
+
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigurePanicPolicy(testCase.givenPolicy)
+			if testCase.givenWorkers > 0 {
+				eng.ConfigureWorkerPool(testCase.givenWorkers)
+			}
+
+			result := eng.Compute(map[string]interface{}{})
+			if errorMessage(result.Error) != testCase.expectedError {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.expectedError)
+			}
+			state := result.Report[panickingAction]
+			if state.Value != testCase.expectedState {
+				t.Errorf("state - got: %+v, want: %+v", state.Value, testCase.expectedState)
+			}
+			panicErr, ok := state.Error.(*PanicError)
+			if !ok || panicErr.Value != "boom" || !strings.Contains(string(panicErr.Stack), "panicrecovery_test.go") {
+				t.Errorf("panic error - got: %+v, want: panic error with stack trace", state.Error)
+			}
+			_, reached := result.Report[anotherActionNode]
+			if reached != testCase.expectedReached {
+				t.Errorf("independent node reached - got: %+v, want: %+v", reached, testCase.expectedReached)
+			}
+		})
+	}
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
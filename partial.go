@@ -0,0 +1,58 @@
+package hoff
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// ComputeFromNode run a node and its following nodes against the context as is,
+// e.g. the context of a previous computation, without computing its ancestors.
+// The nodes not following the node are ignored, including by the join modes of the following nodes.
+func (cp *Computation) ComputeFromNode(n Node) error {
+	if !cp.System.haveNode(n) {
+		return fmt.Errorf("can't compute from unknown node: %v", n)
+	}
+	node := cp.System.canonicalNode(n)
+	cp.entryNodes = []Node{node}
+	cp.scope = followingNodesOf(cp.System, node)
+	cp.Report = make(map[Node]ComputeState)
+	atomic.StoreInt32(&cp.completedNodes, 0)
+	err := cp.computeNodes(cp.entryNodes)
+	if err != nil {
+		return err
+	}
+	cp.Status = !cp.IsPaused()
+	return nil
+}
+
+// ComputeFromNode run computation against node system with input data from a node, without computing its ancestors,
+// e.g. to compute again the failed nodes of a computation with its context (see Computation.ComputeFromNode).
+func (e *Engine) ComputeFromNode(n Node, data map[string]interface{}) ComputationResult {
+	return e.compute(context.Background(), data, SubmitOptions{fromNode: n}, nil)
+}
+
+// followingNodesOf give a node and all the nodes following it, by node identifier.
+func followingNodesOf(s *NodeSystem, n Node) map[string]bool {
+	nodes := map[string]bool{s.nodeID(n): true}
+	queue := []Node{n}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, branch := range nodeBranches(node) {
+			followingNodes, _ := s.Follow(node, branch)
+			for _, followingNode := range followingNodes {
+				if id := s.nodeID(followingNode); !nodes[id] {
+					nodes[id] = true
+					queue = append(queue, followingNode)
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// inScope tell if a node is part of the computation.
+func (cp *Computation) inScope(n Node) bool {
+	return cp.scope == nil || cp.scope[cp.System.nodeID(n)]
+}
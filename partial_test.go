@@ -0,0 +1,57 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ComputeFromNode(t *testing.T) {
+	var computed []string
+	newNode := func(name string) Node {
+		node, _ := NewActionNode(name, func(c *Context) error {
+			computed = append(computed, name)
+			return nil
+		})
+		return node
+	}
+	fetch, transform, audit, store := newNode("fetch"), newNode("transform"), newNode("audit"), newNode("store")
+
+	ns := NewNodeSystem()
+	ns.AddNode(fetch)
+	ns.AddNode(transform)
+	ns.AddNode(audit)
+	ns.AddNode(store)
+	ns.AddLink(fetch, transform)
+	ns.AddLink(fetch, audit)
+	ns.AddLink(transform, store)
+	ns.AddLink(audit, store)
+	ns.ConfigureJoinModeOnNode(store, JoinAnd)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	result := eng.ComputeFromNode(transform, map[string]interface{}{"fetched": true})
+	expectedResult := ComputationResult{
+		Data: map[string]interface{}{"fetched": true},
+		Report: map[Node]ComputeState{
+			transform: NewContinueComputeState(),
+			store:     NewContinueComputeState(),
+		},
+		Success: true,
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator, computationResultGeneratedFieldsIgnorer) {
+		t.Errorf("got: %+v, want: %+v", result, expectedResult)
+	}
+	expectedComputed := []string{"transform", "store"}
+	if !cmp.Equal(computed, expectedComputed) {
+		t.Errorf("computed - got: %+v, want: %+v", computed, expectedComputed)
+	}
+
+	result = eng.ComputeFromNode(someActionNode, map[string]interface{}{})
+	expectedError := errors.New("can't compute from unknown node: someActionNode")
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("unknown node - got: %+v, want: %+v", result.Error, expectedError)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build (linux && cgo) || (darwin && cgo) || (freebsd && cgo)
+// +build linux,cgo darwin,cgo freebsd,cgo
+
+package hoff
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// LoadPlugin load a node library compiled as a Go plugin (go build -buildmode=plugin),
+// who register its node types in its init function (see RegisterNodeType).
+// The plugin must be built against the same version of this package as the program loading it.
+func LoadPlugin(path string) error {
+	_, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("can't load plugin '%v': %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !cgo || (!linux && !darwin && !freebsd)
+// +build !cgo !linux,!darwin,!freebsd
+
+package hoff
+
+import "fmt"
+
+// LoadPlugin load a node library compiled as a Go plugin (go build -buildmode=plugin),
+// who register its node types in its init function (see RegisterNodeType).
+// The Go plugins need cgo on linux, darwin or freebsd, so it always fail on this platform.
+func LoadPlugin(path string) error {
+	return fmt.Errorf("can't load plugin '%v': go plugins not supported on this platform", path)
+}
@@ -0,0 +1,183 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Port is a typed input or output of a Node.
+// The port value is stored in the context under the port name.
+type Port struct {
+	Name string
+	Type reflect.Type
+}
+
+// NewPort create a port based on a name and a sample value of its type.
+func NewPort(name string, sample interface{}) Port {
+	return Port{Name: name, Type: reflect.TypeOf(sample)}
+}
+
+// String print human-readable version of a port
+func (p Port) String() string {
+	return fmt.Sprintf("%v(%v)", p.Name, p.Type)
+}
+
+var (
+	// portComparator is a google/go-cmp comparator of Ports
+	portComparator = cmp.Comparer(func(x, y Port) bool {
+		return x.Name == y.Name && x.Type == y.Type
+	})
+	// portLinkComparator is a google/go-cmp comparator of port links
+	portLinkComparator = cmp.Comparer(func(x, y portLink) bool {
+		return cmp.Equal(x.From, y.From, NodeComparator) && x.Output == y.Output && cmp.Equal(x.To, y.To, NodeComparator) && x.Input == y.Input
+	})
+)
+
+// PortsDeclarerNode is a Node who declare its input and output ports.
+type PortsDeclarerNode interface {
+	Node
+	InputPorts() []Port
+	OutputPorts() []Port
+}
+
+// nodePorts hold the input and output ports of a node.
+type nodePorts struct {
+	Inputs  []Port
+	Outputs []Port
+}
+
+// portLink store the wiring of an output port of a node to an input port of a following node.
+type portLink struct {
+	From   Node
+	Output string
+	To     Node
+	Input  string
+}
+
+// String print human-readable version of a port link
+func (l portLink) String() string {
+	return fmt.Sprintf("{from:'%v.%v' to:'%v.%v'}", l.From, l.Output, l.To, l.Input)
+}
+
+// ConfigurePortsOnNode configure the input and output ports of a node into the system before activation.
+// It take precedence over the ports declared by a PortsDeclarerNode.
+func (s *NodeSystem) ConfigurePortsOnNode(n Node, inputs, outputs []Port) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add node ports, node system is freeze due to activation")
+	}
+	if s.nodesPorts == nil {
+		s.nodesPorts = make(map[string]nodePorts)
+	}
+	s.nodesPorts[s.nodeID(n)] = nodePorts{Inputs: inputs, Outputs: outputs}
+	return true, nil
+}
+
+// ConnectPorts wire an output port of a node to an input port of a linked node into the system before activation.
+// Before computing the linked node, the output port value is copied into the input port.
+func (s *NodeSystem) ConnectPorts(from Node, output string, to Node, input string) (bool, error) {
+	from, to = s.canonicalNode(from), s.canonicalNode(to)
+	if s.activated {
+		return false, errors.New("can't connect ports, node system is freeze due to activation")
+	}
+	if from == nil || to == nil {
+		return false, errors.New("can't connect ports of a missing node")
+	}
+	s.portLinks = append(s.portLinks, portLink{From: from, Output: output, To: to, Input: input})
+	return true, nil
+}
+
+// PortsOfNode get the input and output ports of a node.
+func (s *NodeSystem) PortsOfNode(n Node) ([]Port, []Port) {
+	if ports, found := s.nodesPorts[s.nodeID(n)]; found {
+		return ports.Inputs, ports.Outputs
+	}
+	if declarer, ok := n.(PortsDeclarerNode); ok {
+		return declarer.InputPorts(), declarer.OutputPorts()
+	}
+	return nil, nil
+}
+
+func findPort(ports []Port, name string) (Port, bool) {
+	for _, port := range ports {
+		if port.Name == name {
+			return port, true
+		}
+	}
+	return Port{}, false
+}
+
+func checkForInvalidPortLinks(s *NodeSystem) []error {
+	errors := make([]error, 0)
+	connectedInputs := make(map[string]map[string]int)
+	for _, link := range s.portLinks {
+		_, outputs := s.PortsOfNode(link.From)
+		inputs, _ := s.PortsOfNode(link.To)
+		output, foundOutput := findPort(outputs, link.Output)
+		input, foundInput := findPort(inputs, link.Input)
+		if !foundOutput {
+			errors = append(errors, fmt.Errorf("can't have undeclared output port '%v' in port link %v", link.Output, link))
+		}
+		if !foundInput {
+			errors = append(errors, fmt.Errorf("can't have undeclared input port '%v' in port link %v", link.Input, link))
+		}
+		if foundOutput && foundInput && !isAssignablePort(output, input) {
+			errors = append(errors, fmt.Errorf("can't have port of type %v to port of type %v in port link %v", output.Type, input.Type, link))
+		}
+
+		linked := false
+		for _, nodeLink := range s.links {
+			if s.sameNode(nodeLink.From, link.From) && s.sameNode(nodeLink.To, link.To) {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			errors = append(errors, fmt.Errorf("can't have port link without node link: %v", link))
+		}
+
+		toID := s.nodeID(link.To)
+		if connectedInputs[toID] == nil {
+			connectedInputs[toID] = make(map[string]int)
+		}
+		connectedInputs[toID][link.Input]++
+		if connectedInputs[toID][link.Input] == 2 {
+			errors = append(errors, fmt.Errorf("can't have multiple port links to the same input port '%v' of node: %v", link.Input, link.To))
+		}
+	}
+	return errors
+}
+
+func isAssignablePort(output, input Port) bool {
+	if output.Type == nil || input.Type == nil {
+		return output.Type == input.Type
+	}
+	return output.Type.AssignableTo(input.Type)
+}
+
+// feedInputPorts copy the output port values of the computed ancestors into the input ports of a node,
+// on the context the node is computed on.
+func (cp *Computation) feedInputPorts(node Node, c *Context) error {
+	for _, link := range cp.System.portLinks {
+		if !cp.System.sameNode(link.To, node) {
+			continue
+		}
+		state, computed := cp.stateOf(link.From)
+		if !computed || state.Value != ContinueState {
+			continue
+		}
+		value, found := c.Read(link.Output)
+		if !found {
+			continue
+		}
+		inputs, _ := cp.System.PortsOfNode(node)
+		input, _ := findPort(inputs, link.Input)
+		if input.Type != nil && value != nil && !reflect.TypeOf(value).AssignableTo(input.Type) {
+			return fmt.Errorf("can't feed input port %v with a value of type %T in port link %v", input, value, link)
+		}
+		c.Store(link.Input, value)
+	}
+	return nil
+}
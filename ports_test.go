@@ -0,0 +1,199 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type portsDeclarerNode struct {
+	SomeNode
+	name string
+}
+
+func (n *portsDeclarerNode) InputPorts() []Port {
+	return []Port{NewPort("input", "")}
+}
+
+func (n *portsDeclarerNode) OutputPorts() []Port {
+	return []Port{NewPort("output", 0)}
+}
+
+func Test_NodeSystem_PortsOfNode(t *testing.T) {
+	declarer := &portsDeclarerNode{name: "declarer"}
+	configuredDeclarer := &portsDeclarerNode{name: "configuredDeclarer"}
+
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(declarer)
+	ns.AddNode(configuredDeclarer)
+	ns.ConfigurePortsOnNode(configuredDeclarer, []Port{NewPort("other_input", true)}, nil)
+
+	testCases := []struct {
+		name            string
+		givenNode       Node
+		expectedInputs  []Port
+		expectedOutputs []Port
+	}{
+		{
+			name:      "Node without ports",
+			givenNode: someActionNode,
+		},
+		{
+			name:            "Node declaring its ports",
+			givenNode:       declarer,
+			expectedInputs:  []Port{NewPort("input", "")},
+			expectedOutputs: []Port{NewPort("output", 0)},
+		},
+		{
+			name:           "Node with configured ports",
+			givenNode:      configuredDeclarer,
+			expectedInputs: []Port{NewPort("other_input", true)},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			inputs, outputs := ns.PortsOfNode(testCase.givenNode)
+
+			if !cmp.Equal(inputs, testCase.expectedInputs, portComparator) {
+				t.Errorf("inputs - got: %+v, want: %+v", inputs, testCase.expectedInputs)
+			}
+			if !cmp.Equal(outputs, testCase.expectedOutputs, portComparator) {
+				t.Errorf("outputs - got: %+v, want: %+v", outputs, testCase.expectedOutputs)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_IsValid_PortLinks(t *testing.T) {
+	producer, _ := NewActionNode("producer", func(*Context) error { return nil })
+	consumer, _ := NewActionNode("consumer", func(*Context) error { return nil })
+	other, _ := NewActionNode("other", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name           string
+		givenPortLinks [][4]interface{}
+		expectedErrors []error
+	}{
+		{
+			name: "Can have a port link between assignable ports",
+			givenPortLinks: [][4]interface{}{
+				{producer, "count", consumer, "total"},
+			},
+		},
+		{
+			name: "Can't have a port link with undeclared ports",
+			givenPortLinks: [][4]interface{}{
+				{producer, "unknown", consumer, "missing"},
+			},
+			expectedErrors: []error{
+				errors.New("can't have undeclared output port 'unknown' in port link {from:'producer.unknown' to:'consumer.missing'}"),
+				errors.New("can't have undeclared input port 'missing' in port link {from:'producer.unknown' to:'consumer.missing'}"),
+			},
+		},
+		{
+			name: "Can't have a port link between not assignable ports",
+			givenPortLinks: [][4]interface{}{
+				{producer, "label", consumer, "total"},
+			},
+			expectedErrors: []error{
+				errors.New("can't have port of type string to port of type int in port link {from:'producer.label' to:'consumer.total'}"),
+			},
+		},
+		{
+			name: "Can't have a port link without node link",
+			givenPortLinks: [][4]interface{}{
+				{producer, "count", other, "total"},
+			},
+			expectedErrors: []error{
+				errors.New("can't have port link without node link: {from:'producer.count' to:'other.total'}"),
+			},
+		},
+		{
+			name: "Can't have multiple port links to the same input port",
+			givenPortLinks: [][4]interface{}{
+				{producer, "count", consumer, "total"},
+				{producer, "count", consumer, "total"},
+			},
+			expectedErrors: []error{
+				errors.New("can't have multiple port links to the same input port 'total' of node: consumer"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(producer)
+			ns.AddNode(consumer)
+			ns.AddNode(other)
+			ns.AddLink(producer, consumer)
+			ns.ConfigurePortsOnNode(producer, nil, []Port{NewPort("count", 0), NewPort("label", "")})
+			ns.ConfigurePortsOnNode(consumer, []Port{NewPort("total", 0)}, nil)
+			ns.ConfigurePortsOnNode(other, []Port{NewPort("total", 0)}, nil)
+			for _, portLink := range testCase.givenPortLinks {
+				ns.ConnectPorts(portLink[0].(Node), portLink[1].(string), portLink[2].(Node), portLink[3].(string))
+			}
+
+			_, validationErr := ns.IsValid()
+
+			errs := validationErrors(validationErr)
+
+			if !cmp.Equal(errs, testCase.expectedErrors, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", errs, testCase.expectedErrors)
+			}
+		})
+	}
+}
+
+func Test_Computation_Compute_PortLinks(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenValue    interface{}
+		expectedTotal interface{}
+		expectedState ComputeState
+	}{
+		{
+			name:          "Can feed an input port with an output port value",
+			givenValue:    42,
+			expectedTotal: 42,
+			expectedState: NewContinueComputeState(),
+		},
+		{
+			name:          "Can't feed an input port with a value of another type",
+			givenValue:    "42",
+			expectedState: NewAbortComputeState(errors.New("can't feed input port total(int) with a value of type string in port link {from:'producer.count' to:'consumer.total'}")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var total interface{}
+			producer, _ := NewActionNode("producer", func(c *Context) error {
+				c.Store("count", testCase.givenValue)
+				return nil
+			})
+			consumer, _ := NewActionNode("consumer", func(c *Context) error {
+				total, _ = c.Read("total")
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(producer)
+			ns.AddNode(consumer)
+			ns.AddLink(producer, consumer)
+			ns.ConfigurePortsOnNode(producer, nil, []Port{NewPort("count", 0)})
+			ns.ConfigurePortsOnNode(consumer, []Port{NewPort("total", 0)}, nil)
+			ns.ConnectPorts(producer, "count", consumer, "total")
+			ns.ActivateInPlace()
+
+			cp, _ := NewComputation(ns, NewContextWithoutData())
+			cp.Compute()
+
+			if !cmp.Equal(total, testCase.expectedTotal) {
+				t.Errorf("total - got: %+v, want: %+v", total, testCase.expectedTotal)
+			}
+			if !cmp.Equal(cp.Report[consumer], testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", cp.Report[consumer], testCase.expectedState)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Profile hold the statistics of the nodes learned from the reports of past computations.
+type Profile struct {
+	Reports int                    `json:"reports"`
+	Nodes   map[string]NodeProfile `json:"nodes"`
+}
+
+// NodeProfile hold the statistics of a node over its computations, a skipped node is not computed.
+type NodeProfile struct {
+	Computations int           `json:"computations"`
+	Mean         time.Duration `json:"mean"`
+	StdDev       time.Duration `json:"std_dev"`
+	P50          time.Duration `json:"p50"`
+	P90          time.Duration `json:"p90"`
+	P99          time.Duration `json:"p99"`
+	// ErrorRate is the ratio of computations ended in Abort
+	ErrorRate float64 `json:"error_rate"`
+	// TrueBranchFrequency is the ratio of decisions taking the true branch, for a decision node
+	TrueBranchFrequency float64 `json:"true_branch_frequency"`
+}
+
+// NewProfile learn the statistics of the nodes from the stored reports of their computations,
+// the nodes being identified by their name.
+func NewProfile(reports []StoredReport) Profile {
+	durations := make(map[string][]time.Duration)
+	aborts := make(map[string]int)
+	decisions := make(map[string]int)
+	trueDecisions := make(map[string]int)
+	for _, report := range reports {
+		for _, state := range report.Report.States {
+			if state.State != ContinueState && state.State != AbortState {
+				continue
+			}
+			durations[state.Node] = append(durations[state.Node], state.Duration)
+			if state.State == AbortState {
+				aborts[state.Node]++
+			}
+			if state.Branch != nil {
+				decisions[state.Node]++
+				if *state.Branch {
+					trueDecisions[state.Node]++
+				}
+			}
+		}
+	}
+
+	profile := Profile{Reports: len(reports), Nodes: make(map[string]NodeProfile, len(durations))}
+	for node, nodeDurations := range durations {
+		sort.Slice(nodeDurations, func(i, j int) bool {
+			return nodeDurations[i] < nodeDurations[j]
+		})
+		var total float64
+		for _, duration := range nodeDurations {
+			total += float64(duration)
+		}
+		mean := total / float64(len(nodeDurations))
+		var variance float64
+		for _, duration := range nodeDurations {
+			variance += (float64(duration) - mean) * (float64(duration) - mean)
+		}
+		variance /= float64(len(nodeDurations))
+
+		nodeProfile := NodeProfile{
+			Computations: len(nodeDurations),
+			Mean:         time.Duration(mean),
+			StdDev:       time.Duration(math.Sqrt(variance)),
+			P50:          durationPercentile(nodeDurations, 50),
+			P90:          durationPercentile(nodeDurations, 90),
+			P99:          durationPercentile(nodeDurations, 99),
+			ErrorRate:    float64(aborts[node]) / float64(len(nodeDurations)),
+		}
+		if decisions[node] > 0 {
+			nodeProfile.TrueBranchFrequency = float64(trueDecisions[node]) / float64(decisions[node])
+		}
+		profile.Nodes[node] = nodeProfile
+	}
+	return profile
+}
+
+// Durations give the durations of the profiled nodes of a node system,
+// to simulate it (see NodeSystem.Simulate) or analyze its critical path (see NodeSystem.CriticalPath).
+func (p Profile) Durations(system *NodeSystem) map[Node]NodeDuration {
+	durations := make(map[Node]NodeDuration)
+	for _, node := range system.nodes {
+		if nodeProfile, found := p.Nodes[fmt.Sprint(node)]; found {
+			durations[system.nodeMapKey(node)] = NodeDuration{Mean: nodeProfile.Mean, StdDev: nodeProfile.StdDev}
+		}
+	}
+	return durations
+}
+
+// ConfigureBranchProbabilities configure the branch probabilities of the profiled decision nodes
+// of a node system before activation, to simulate it (see NodeSystem.Simulate).
+func (p Profile) ConfigureBranchProbabilities(system *NodeSystem) (bool, error) {
+	if system.activated {
+		return false, errors.New("can't add branch probabilities, node system is freeze due to activation")
+	}
+	for _, node := range system.nodes {
+		nodeProfile, found := p.Nodes[fmt.Sprint(node)]
+		if !found || !node.DecideCapability() {
+			continue
+		}
+		if _, err := system.ConfigureBranchProbability(node, nodeProfile.TrueBranchFrequency); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
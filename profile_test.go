@@ -0,0 +1,108 @@
+package hoff
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewProfile(t *testing.T) {
+	reports := make([]StoredReport, 0)
+	for index := 1; index <= 10; index++ {
+		states := []NodeStateReport{
+			{Node: "alwaysTrueDecisionNode", State: ContinueState, Branch: boolPointer(index <= 7), Duration: time.Duration(index) * time.Millisecond},
+			{Node: "anotherActionNode", State: SkipState},
+		}
+		if index <= 7 {
+			states = append(states, NodeStateReport{Node: "someActionNode", State: ContinueState, Duration: 10 * time.Millisecond})
+		} else {
+			states = append(states, NodeStateReport{Node: "someActionNode", State: AbortState, Error: "failure", Duration: 20 * time.Millisecond})
+		}
+		reports = append(reports, StoredReport{Report: ComputationReport{States: states}})
+	}
+
+	profile := NewProfile(reports)
+	expectedProfile := Profile{
+		Reports: 10,
+		Nodes: map[string]NodeProfile{
+			"alwaysTrueDecisionNode": {
+				Computations:        10,
+				Mean:                5500 * time.Microsecond,
+				StdDev:              2872281 * time.Nanosecond,
+				P50:                 5 * time.Millisecond,
+				P90:                 9 * time.Millisecond,
+				P99:                 10 * time.Millisecond,
+				TrueBranchFrequency: 0.7,
+			},
+			"someActionNode": {
+				Computations: 10,
+				Mean:         13 * time.Millisecond,
+				StdDev:       4582575 * time.Nanosecond,
+				P50:          10 * time.Millisecond,
+				P90:          20 * time.Millisecond,
+				P99:          20 * time.Millisecond,
+				ErrorRate:    0.3,
+			},
+		},
+	}
+	if !cmp.Equal(profile, expectedProfile) {
+		t.Errorf("got: %+v, want: %+v", profile, expectedProfile)
+	}
+
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.ConfigureBranchProbability(alwaysTrueDecisionNode, 0.1)
+	profile.ConfigureBranchProbabilities(ns)
+	if ns.BranchProbabilityOfNode(alwaysTrueDecisionNode) != 0.7 {
+		t.Errorf("branch probability - got: %+v, want: %+v", ns.BranchProbabilityOfNode(alwaysTrueDecisionNode), 0.7)
+	}
+	expectedDurations := map[Node]NodeDuration{
+		alwaysTrueDecisionNode: {Mean: 5500 * time.Microsecond, StdDev: 2872281 * time.Nanosecond},
+		someActionNode:         {Mean: 13 * time.Millisecond, StdDev: 4582575 * time.Nanosecond},
+	}
+	if durations := profile.Durations(ns); !cmp.Equal(durations, expectedDurations, NodeComparator) {
+		t.Errorf("durations - got: %+v, want: %+v", durations, expectedDurations)
+	}
+
+	ns.IsValid()
+	ns.ActivateInPlace()
+	_, err := profile.ConfigureBranchProbabilities(ns)
+	expectedError := errors.New("can't add branch probabilities, node system is freeze due to activation")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_APIHandler_ServeHTTP_profile(t *testing.T) {
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(activatedNodeSystem())
+	api, _ := NewAPIHandler(eng)
+
+	status, response := serveAPI(api, http.MethodGet, "/profile", "")
+	if status != http.StatusNotFound || response["error"] != "can't profile without report store" {
+		t.Errorf("without store - got: %+v %+v, want: %+v", status, response, http.StatusNotFound)
+	}
+
+	eng.ConfigureReportStore(NewMemoryReportStore(), "workflow")
+	eng.Compute(map[string]interface{}{})
+	eng.Compute(map[string]interface{}{})
+
+	status, response = serveAPI(api, http.MethodGet, "/profile?limit=1", "")
+	nodes := response["nodes"].(map[string]interface{})
+	computations := nodes["someActionNode"].(map[string]interface{})["computations"]
+	if status != http.StatusOK || response["reports"] != float64(1) || computations != float64(1) {
+		t.Errorf("profile - got: %+v %+v, want: %+v with 1 report", status, response, http.StatusOK)
+	}
+
+	status, _ = serveAPI(api, http.MethodGet, "/profile?limit=all", "")
+	if status != http.StatusBadRequest {
+		t.Errorf("invalid limit - got: %+v, want: %+v", status, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,395 @@
+package hoff
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ComputationReport is a serializable report of a computation result,
+// with the context data as JSON-encoded values.
+type ComputationReport struct {
+	ID          string
+	Fingerprint string
+	Error       string
+	Data        map[string][]byte
+	States      []NodeStateReport
+	Success     bool
+	// Seed is the seed of the computation random generator
+	Seed int64
+	// ErrorCauses are the names of the registered errors wrapped by the error (see RegisterError)
+	ErrorCauses []string
+	// Outputs hold the declared outputs of the node system, as JSON-encoded values
+	Outputs map[string][]byte
+	// Tenant is the tenant of the computation
+	Tenant string
+	// Cancellation is the reason of the interruption of the computation, if any
+	Cancellation CancellationReason
+	// Types are the types of the typed context values by key, only set on the report of a checkpoint
+	// to restore its values (e.g. 'secret' for a Secret kept as its ciphertext)
+	Types map[string]string
+}
+
+// NodeStateReport is a serializable compute state of a node, named after its string representation.
+type NodeStateReport struct {
+	Node   string
+	State  StateType
+	Branch *bool
+	Error  string
+	Code   AbortCode
+	Token  string
+	// Duration is the time taken to compute the node
+	Duration time.Duration
+	// Causes are the names of the registered errors wrapped by the error (see RegisterError)
+	Causes []string
+	// Override is the node override forcing the state
+	Override NodeOverride
+}
+
+// NewComputationReport create a report of a computation result, with the node states sorted by node name.
+func NewComputationReport(result ComputationResult) (ComputationReport, error) {
+	data, err := encodeContextData(result.Data)
+	if err != nil {
+		return ComputationReport{}, err
+	}
+	outputs, err := encodeContextData(result.Outputs)
+	if err != nil {
+		return ComputationReport{}, err
+	}
+	report := ComputationReport{
+		ID:           result.ID,
+		Fingerprint:  result.Fingerprint,
+		Data:         data,
+		States:       make([]NodeStateReport, 0, len(result.Report)),
+		Success:      result.Success,
+		Seed:         result.Seed,
+		Tenant:       result.Tenant,
+		Cancellation: result.Cancellation,
+	}
+	if result.Outputs != nil {
+		report.Outputs = outputs
+	}
+	if result.Error != nil {
+		report.Error = result.Error.Error()
+		report.ErrorCauses = errorCauses(result.Error)
+	}
+	durations := make(map[string]time.Duration, len(result.Durations))
+	for node, duration := range result.Durations {
+		durations[fmt.Sprint(node)] = duration
+	}
+	for _, record := range newNodeStateRecords(result.Report) {
+		report.States = append(report.States, NodeStateReport{
+			Node:     record.Node,
+			State:    record.State,
+			Branch:   record.Branch,
+			Error:    record.Error,
+			Causes:   record.Causes,
+			Override: record.Override,
+			Code:     record.Code,
+			Token:    record.Token,
+			Duration: durations[record.Node],
+		})
+	}
+	return report, nil
+}
+
+// MarshalProto encode the description as a SystemDescription message of proto/flow.proto, with the FormatVersion.
+func (d SystemDescription) MarshalProto() ([]byte, error) {
+	var encoder protoEncoder
+	for _, node := range d.Nodes {
+		node := node
+		encoder.message(1, func(e *protoEncoder) {
+			e.string(1, node.Name)
+			e.bool(2, node.Decision)
+			e.string(3, string(node.JoinMode))
+			for _, tag := range node.Tags {
+				e.string(4, tag)
+			}
+			e.string(5, node.Expression)
+			for _, transform := range node.Transforms {
+				transform := transform
+				e.message(6, func(e *protoEncoder) {
+					e.string(1, transform.Key)
+					e.string(2, transform.Expression)
+				})
+			}
+			e.string(7, node.Type)
+			config := make(map[string][]byte, len(node.Config))
+			for key, value := range node.Config {
+				config[key] = []byte(value)
+			}
+			e.bytesMap(8, config)
+		})
+	}
+	for _, link := range d.Links {
+		link := link
+		encoder.message(2, func(e *protoEncoder) {
+			e.string(1, link.From)
+			e.string(2, link.To)
+			e.branch(3, link.Branch)
+			metadata := make(map[string][]byte, len(link.Metadata))
+			for key, value := range link.Metadata {
+				metadata[key] = []byte(value)
+			}
+			e.bytesMap(4, metadata)
+		})
+	}
+	encoder.int64(3, FormatVersion)
+	return encoder.buffer, nil
+}
+
+// UnmarshalProto decode a SystemDescription message of proto/flow.proto into the description.
+// Unknown fields are ignored, and unsupported format versions give an ErrUnsupportedFormatVersion.
+func (d *SystemDescription) UnmarshalProto(buffer []byte) error {
+	description := SystemDescription{
+		Nodes: make([]NodeDescription, 0),
+		Links: make([]LinkDescription, 0),
+	}
+	var version int64
+	err := decodeProto(buffer, func(field protoField) error {
+		if field.number == 3 && field.wireType == protoVarint {
+			version = int64(field.varint)
+		}
+		if field.wireType != protoLengthDelimited {
+			return nil
+		}
+		switch field.number {
+		case 1:
+			node, err := unmarshalNodeDescription(field.bytes)
+			if err != nil {
+				return err
+			}
+			description.Nodes = append(description.Nodes, node)
+		case 2:
+			link, err := unmarshalLinkDescription(field.bytes)
+			if err != nil {
+				return err
+			}
+			description.Links = append(description.Links, link)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("can't unmarshal system description: %v", err)
+	}
+	if err := checkFormatVersion(version); err != nil {
+		return fmt.Errorf("can't unmarshal system description: %w", err)
+	}
+	*d = description
+	return nil
+}
+
+func unmarshalNodeDescription(buffer []byte) (NodeDescription, error) {
+	var node NodeDescription
+	err := decodeProto(buffer, func(field protoField) error {
+		switch {
+		case field.number == 1 && field.wireType == protoLengthDelimited:
+			node.Name = field.string()
+		case field.number == 2 && field.wireType == protoVarint:
+			node.Decision = field.bool()
+		case field.number == 3 && field.wireType == protoLengthDelimited:
+			node.JoinMode = JoinMode(field.string())
+		case field.number == 4 && field.wireType == protoLengthDelimited:
+			node.Tags = append(node.Tags, field.string())
+		case field.number == 5 && field.wireType == protoLengthDelimited:
+			node.Expression = field.string()
+		case field.number == 6 && field.wireType == protoLengthDelimited:
+			var transform Transform
+			err := decodeProto(field.bytes, func(field protoField) error {
+				switch {
+				case field.number == 1 && field.wireType == protoLengthDelimited:
+					transform.Key = field.string()
+				case field.number == 2 && field.wireType == protoLengthDelimited:
+					transform.Expression = field.string()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			node.Transforms = append(node.Transforms, transform)
+		case field.number == 7 && field.wireType == protoLengthDelimited:
+			node.Type = field.string()
+		case field.number == 8 && field.wireType == protoLengthDelimited:
+			key, value, err := decodeProtoMapEntry(field.bytes)
+			if err != nil {
+				return err
+			}
+			if node.Config == nil {
+				node.Config = make(map[string]string)
+			}
+			node.Config[key] = string(value)
+		}
+		return nil
+	})
+	return node, err
+}
+
+func unmarshalLinkDescription(buffer []byte) (LinkDescription, error) {
+	var link LinkDescription
+	err := decodeProto(buffer, func(field protoField) error {
+		var err error
+		switch {
+		case field.number == 1 && field.wireType == protoLengthDelimited:
+			link.From = field.string()
+		case field.number == 2 && field.wireType == protoLengthDelimited:
+			link.To = field.string()
+		case field.number == 3 && field.wireType == protoVarint:
+			link.Branch, err = field.branch()
+		case field.number == 4 && field.wireType == protoLengthDelimited:
+			key, value, entryErr := decodeProtoMapEntry(field.bytes)
+			if entryErr != nil {
+				return entryErr
+			}
+			if link.Metadata == nil {
+				link.Metadata = make(map[string]string)
+			}
+			link.Metadata[key] = string(value)
+		}
+		return err
+	})
+	return link, err
+}
+
+// MarshalProto encode the report as a ComputationReport message of proto/flow.proto, with the FormatVersion.
+func (r ComputationReport) MarshalProto() ([]byte, error) {
+	var encoder protoEncoder
+	encoder.string(1, r.ID)
+	encoder.string(2, r.Fingerprint)
+	encoder.string(3, r.Error)
+	encoder.bytesMap(4, r.Data)
+	for _, state := range r.States {
+		state := state
+		encoder.message(5, func(e *protoEncoder) {
+			e.string(1, state.Node)
+			e.string(2, string(state.State))
+			e.branch(3, state.Branch)
+			e.string(4, state.Error)
+			e.string(5, string(state.Code))
+			e.string(6, state.Token)
+			e.int64(7, int64(state.Duration))
+			for _, cause := range state.Causes {
+				e.string(8, cause)
+			}
+			e.string(9, string(state.Override))
+		})
+	}
+	encoder.bool(6, r.Success)
+	encoder.int64(7, r.Seed)
+	for _, cause := range r.ErrorCauses {
+		encoder.string(8, cause)
+	}
+	encoder.bytesMap(9, r.Outputs)
+	encoder.int64(10, FormatVersion)
+	encoder.string(11, r.Tenant)
+	encoder.string(12, string(r.Cancellation))
+	types := make(map[string][]byte, len(r.Types))
+	for key, typ := range r.Types {
+		types[key] = []byte(typ)
+	}
+	encoder.bytesMap(13, types)
+	return encoder.buffer, nil
+}
+
+// UnmarshalProto decode a ComputationReport message of proto/flow.proto into the report.
+// Unknown fields are ignored, and unsupported format versions give an ErrUnsupportedFormatVersion.
+func (r *ComputationReport) UnmarshalProto(buffer []byte) error {
+	report := ComputationReport{
+		Data:   make(map[string][]byte),
+		States: make([]NodeStateReport, 0),
+	}
+	var version int64
+	err := decodeProto(buffer, func(field protoField) error {
+		switch {
+		case field.number == 1 && field.wireType == protoLengthDelimited:
+			report.ID = field.string()
+		case field.number == 2 && field.wireType == protoLengthDelimited:
+			report.Fingerprint = field.string()
+		case field.number == 3 && field.wireType == protoLengthDelimited:
+			report.Error = field.string()
+		case field.number == 4 && field.wireType == protoLengthDelimited:
+			key, value, err := decodeProtoMapEntry(field.bytes)
+			if err != nil {
+				return err
+			}
+			report.Data[key] = value
+		case field.number == 5 && field.wireType == protoLengthDelimited:
+			state, err := unmarshalNodeStateReport(field.bytes)
+			if err != nil {
+				return err
+			}
+			report.States = append(report.States, state)
+		case field.number == 6 && field.wireType == protoVarint:
+			report.Success = field.bool()
+		case field.number == 7 && field.wireType == protoVarint:
+			report.Seed = int64(field.varint)
+		case field.number == 8 && field.wireType == protoLengthDelimited:
+			report.ErrorCauses = append(report.ErrorCauses, field.string())
+		case field.number == 9 && field.wireType == protoLengthDelimited:
+			key, value, err := decodeProtoMapEntry(field.bytes)
+			if err != nil {
+				return err
+			}
+			if report.Outputs == nil {
+				report.Outputs = make(map[string][]byte)
+			}
+			report.Outputs[key] = value
+		case field.number == 10 && field.wireType == protoVarint:
+			version = int64(field.varint)
+		case field.number == 11 && field.wireType == protoLengthDelimited:
+			report.Tenant = field.string()
+		case field.number == 12 && field.wireType == protoLengthDelimited:
+			report.Cancellation = CancellationReason(field.string())
+		case field.number == 13 && field.wireType == protoLengthDelimited:
+			key, value, err := decodeProtoMapEntry(field.bytes)
+			if err != nil {
+				return err
+			}
+			if report.Types == nil {
+				report.Types = make(map[string]string)
+			}
+			report.Types[key] = string(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("can't unmarshal computation report: %v", err)
+	}
+	if err := checkFormatVersion(version); err != nil {
+		return fmt.Errorf("can't unmarshal computation report: %w", err)
+	}
+	sort.SliceStable(report.States, func(i, j int) bool {
+		return report.States[i].Node < report.States[j].Node
+	})
+	*r = report
+	return nil
+}
+
+func unmarshalNodeStateReport(buffer []byte) (NodeStateReport, error) {
+	var state NodeStateReport
+	err := decodeProto(buffer, func(field protoField) error {
+		var err error
+		switch {
+		case field.number == 1 && field.wireType == protoLengthDelimited:
+			state.Node = field.string()
+		case field.number == 2 && field.wireType == protoLengthDelimited:
+			state.State = StateType(field.string())
+		case field.number == 3 && field.wireType == protoVarint:
+			state.Branch, err = field.branch()
+		case field.number == 4 && field.wireType == protoLengthDelimited:
+			state.Error = field.string()
+		case field.number == 5 && field.wireType == protoLengthDelimited:
+			state.Code = AbortCode(field.string())
+		case field.number == 6 && field.wireType == protoLengthDelimited:
+			state.Token = field.string()
+		case field.number == 7 && field.wireType == protoVarint:
+			state.Duration = time.Duration(field.varint)
+		case field.number == 8 && field.wireType == protoLengthDelimited:
+			state.Causes = append(state.Causes, field.string())
+		case field.number == 9 && field.wireType == protoLengthDelimited:
+			state.Override = NodeOverride(field.string())
+		}
+		return err
+	})
+	return state, err
+}
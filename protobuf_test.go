@@ -0,0 +1,150 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_SystemDescription_MarshalProto(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.ConfigureTagsOnNode(someActionNode, "io", "slow")
+	ns.ConfigureTypeOnNode(someActionNode, "http", map[string]string{"url": "http://localhost"})
+	ns.ConfigureJoinModeOnNode(anotherActionNode, JoinAnd)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.ConfigureMetadataOnLink(someActionNode, anotherActionNode, nil, map[string]string{"sla": "1s", "owner": "team"})
+	description := ns.Describe()
+
+	buffer, err := description.MarshalProto()
+	if err != nil {
+		t.Errorf("marshal error - got: %+v, want: %+v", err, nil)
+	}
+
+	var unmarshaled SystemDescription
+	err = unmarshaled.UnmarshalProto(buffer)
+	if err != nil {
+		t.Errorf("unmarshal error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(unmarshaled, description) {
+		t.Errorf("got: %+v, want: %+v", unmarshaled, description)
+	}
+}
+
+func Test_SystemDescription_UnmarshalProto(t *testing.T) {
+	testCases := []struct {
+		name                string
+		givenBuffer         []byte
+		expectedDescription SystemDescription
+		expectedError       error
+	}{
+		{
+			name: "Can unmarshal a node",
+			// nodes { name: "a" decision: true }
+			givenBuffer: []byte{0x0a, 0x05, 0x0a, 0x01, 'a', 0x10, 0x01},
+			expectedDescription: SystemDescription{
+				Nodes: []NodeDescription{{Name: "a", Decision: true}},
+				Links: []LinkDescription{},
+			},
+		},
+		{
+			name: "Can unmarshal with unknown fields",
+			// nodes { name: "a" 15: 42 } 9: 0x01020304 (fixed32)
+			givenBuffer: []byte{0x0a, 0x05, 0x0a, 0x01, 'a', 0x78, 0x2a, 0x4d, 0x01, 0x02, 0x03, 0x04},
+			expectedDescription: SystemDescription{
+				Nodes: []NodeDescription{{Name: "a"}},
+				Links: []LinkDescription{},
+			},
+		},
+		{
+			name:          "Can't unmarshal a truncated message",
+			givenBuffer:   []byte{0x0a, 0x05, 0x0a, 0x01},
+			expectedError: errors.New("can't unmarshal system description: can't decode protobuf length of field 1"),
+		},
+		{
+			name: "Can't unmarshal an unknown branch",
+			// links { branch: 3 }
+			givenBuffer:   []byte{0x12, 0x02, 0x18, 0x03},
+			expectedError: errors.New("can't unmarshal system description: can't decode unknown branch: 3"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var description SystemDescription
+			err := description.UnmarshalProto(testCase.givenBuffer)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(description, testCase.expectedDescription) {
+				t.Errorf("got: %+v, want: %+v", description, testCase.expectedDescription)
+			}
+		})
+	}
+}
+
+func Test_ComputationReport_MarshalProto(t *testing.T) {
+	result := ComputationResult{
+		ID:          "computation-1",
+		Fingerprint: "abc",
+		Error:       errors.New("missing key"),
+		Data:        map[string]interface{}{"key": "value", "count": 2},
+		Report: map[Node]ComputeState{
+			alwaysTrueDecisionNode: NewContinueOnBranchComputeState(true),
+			someActionNode:         NewAbortWithCodeComputeState(TransientAbort, errors.New("missing key")),
+			anotherActionNode:      {Value: SkipState, Override: ForceSkip},
+		},
+		Durations: map[Node]time.Duration{
+			alwaysTrueDecisionNode: time.Millisecond,
+			someActionNode:         2 * time.Second,
+		},
+		Seed:         -42,
+		Outputs:      map[string]interface{}{"count": 2},
+		Tenant:       "team-a",
+		Cancellation: CanceledByTimeout,
+	}
+
+	report, err := NewComputationReport(result)
+	if err != nil {
+		t.Errorf("report error - got: %+v, want: %+v", err, nil)
+	}
+	expectedReport := ComputationReport{
+		ID:          "computation-1",
+		Fingerprint: "abc",
+		Error:       "missing key",
+		Data:        map[string][]byte{"key": []byte(`"value"`), "count": []byte("2")},
+		States: []NodeStateReport{
+			{Node: "alwaysTrueDecisionNode", State: ContinueState, Branch: boolPointer(true), Duration: time.Millisecond},
+			{Node: "anotherActionNode", State: SkipState, Override: ForceSkip},
+			{Node: "someActionNode", State: AbortState, Error: "missing key", Code: TransientAbort, Duration: 2 * time.Second},
+		},
+		Seed:         -42,
+		Outputs:      map[string][]byte{"count": []byte("2")},
+		Tenant:       "team-a",
+		Cancellation: CanceledByTimeout,
+	}
+	if !cmp.Equal(report, expectedReport) {
+		t.Errorf("report - got: %+v, want: %+v", report, expectedReport)
+	}
+
+	report.Types = map[string]string{"key": "secret"}
+	expectedReport.Types = map[string]string{"key": "secret"}
+	buffer, err := report.MarshalProto()
+	if err != nil {
+		t.Errorf("marshal error - got: %+v, want: %+v", err, nil)
+	}
+	var unmarshaled ComputationReport
+	err = unmarshaled.UnmarshalProto(buffer)
+	if err != nil {
+		t.Errorf("unmarshal error - got: %+v, want: %+v", err, nil)
+	}
+	if !cmp.Equal(unmarshaled, expectedReport) {
+		t.Errorf("got: %+v, want: %+v", unmarshaled, expectedReport)
+	}
+}
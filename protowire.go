@@ -0,0 +1,203 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// protobuf wire types used by the messages of proto/flow.proto
+const (
+	protoVarint          = 0
+	protoFixed64         = 1
+	protoLengthDelimited = 2
+	protoFixed32         = 5
+)
+
+// protoBranch values follow the Branch enum of proto/compute.proto
+const (
+	protoBranchNone  = 0
+	protoBranchTrue  = 1
+	protoBranchFalse = 2
+)
+
+// protoEncoder append protobuf fields to a buffer, omitting the default values like proto3.
+type protoEncoder struct {
+	buffer []byte
+}
+
+func (e *protoEncoder) varint(value uint64) {
+	for value >= 0x80 {
+		e.buffer = append(e.buffer, byte(value)|0x80)
+		value >>= 7
+	}
+	e.buffer = append(e.buffer, byte(value))
+}
+
+func (e *protoEncoder) tag(field int, wireType int) {
+	e.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (e *protoEncoder) bytes(field int, value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	e.tag(field, protoLengthDelimited)
+	e.varint(uint64(len(value)))
+	e.buffer = append(e.buffer, value...)
+}
+
+func (e *protoEncoder) string(field int, value string) {
+	e.bytes(field, []byte(value))
+}
+
+func (e *protoEncoder) bool(field int, value bool) {
+	if value {
+		e.tag(field, protoVarint)
+		e.varint(1)
+	}
+}
+
+func (e *protoEncoder) int64(field int, value int64) {
+	if value != 0 {
+		e.tag(field, protoVarint)
+		e.varint(uint64(value))
+	}
+}
+
+func (e *protoEncoder) branch(field int, branch *bool) {
+	if branch == nil {
+		return
+	}
+	value := uint64(protoBranchFalse)
+	if *branch {
+		value = protoBranchTrue
+	}
+	e.tag(field, protoVarint)
+	e.varint(value)
+}
+
+// message append an embedded message, even empty since it can be a repeated field.
+func (e *protoEncoder) message(field int, encode func(*protoEncoder)) {
+	var embedded protoEncoder
+	encode(&embedded)
+	e.tag(field, protoLengthDelimited)
+	e.varint(uint64(len(embedded.buffer)))
+	e.buffer = append(e.buffer, embedded.buffer...)
+}
+
+// bytesMap append a map field as repeated key/value entries, sorted by key.
+func (e *protoEncoder) bytesMap(field int, values map[string][]byte) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := values[key]
+		e.message(field, func(entry *protoEncoder) {
+			entry.string(1, key)
+			entry.bytes(2, value)
+		})
+	}
+}
+
+// protoField is a decoded protobuf field, the value is set based on the wire type.
+type protoField struct {
+	number   int
+	wireType int
+	varint   uint64
+	bytes    []byte
+}
+
+func (f protoField) string() string {
+	return string(f.bytes)
+}
+
+func (f protoField) bool() bool {
+	return f.varint != 0
+}
+
+func (f protoField) branch() (*bool, error) {
+	switch f.varint {
+	case protoBranchNone:
+		return nil, nil
+	case protoBranchTrue:
+		return boolPointer(true), nil
+	case protoBranchFalse:
+		return boolPointer(false), nil
+	}
+	return nil, fmt.Errorf("can't decode unknown branch: %v", f.varint)
+}
+
+// decodeProto call the handler on each field of a protobuf message.
+// The fields with an unexpected wire type are skipped to support schema evolution.
+func decodeProto(buffer []byte, handle func(protoField) error) error {
+	for len(buffer) > 0 {
+		key, size := decodeProtoVarint(buffer)
+		if size == 0 {
+			return errors.New("can't decode protobuf field key")
+		}
+		buffer = buffer[size:]
+
+		field := protoField{number: int(key >> 3), wireType: int(key & 7)}
+		switch field.wireType {
+		case protoVarint:
+			field.varint, size = decodeProtoVarint(buffer)
+			if size == 0 {
+				return fmt.Errorf("can't decode protobuf varint of field %v", field.number)
+			}
+		case protoFixed64:
+			size = 8
+		case protoFixed32:
+			size = 4
+		case protoLengthDelimited:
+			length, lengthSize := decodeProtoVarint(buffer)
+			if lengthSize == 0 || uint64(len(buffer)-lengthSize) < length {
+				return fmt.Errorf("can't decode protobuf length of field %v", field.number)
+			}
+			field.bytes = buffer[lengthSize : lengthSize+int(length)]
+			size = lengthSize + int(length)
+		default:
+			return fmt.Errorf("can't decode protobuf wire type %v of field %v", field.wireType, field.number)
+		}
+		if len(buffer) < size {
+			return fmt.Errorf("can't decode truncated protobuf field %v", field.number)
+		}
+		buffer = buffer[size:]
+
+		err := handle(field)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeProtoVarint give a varint and its size, or a zero size if it can't be decoded.
+func decodeProtoVarint(buffer []byte) (uint64, int) {
+	var value uint64
+	for index := 0; index < len(buffer) && index < 10; index++ {
+		value |= uint64(buffer[index]&0x7f) << (7 * uint(index))
+		if buffer[index] < 0x80 {
+			return value, index + 1
+		}
+	}
+	return 0, 0
+}
+
+// decodeProtoMapEntry give the key and the value of a map entry.
+func decodeProtoMapEntry(buffer []byte) (string, []byte, error) {
+	var key string
+	var value []byte
+	err := decodeProto(buffer, func(field protoField) error {
+		switch {
+		case field.number == 1 && field.wireType == protoLengthDelimited:
+			key = field.string()
+		case field.number == 2 && field.wireType == protoLengthDelimited:
+			value = field.bytes
+		}
+		return nil
+	})
+	return key, value, err
+}
@@ -0,0 +1,70 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+)
+
+// QueueRunner run a computation on an engine for each message of a MessageSource.
+// A message is acked when its computation end without error, and nacked otherwise.
+type QueueRunner struct {
+	engine        *Engine
+	source        MessageSource
+	seed          func(*Message) (map[string]interface{}, error)
+	resultHandler func(*Message, ComputationResult)
+}
+
+// NewQueueRunner create a QueueRunner based on an engine, a message source,
+// and a function to seed the context data from a message.
+func NewQueueRunner(engine *Engine, source MessageSource, seed func(*Message) (map[string]interface{}, error)) (*QueueRunner, error) {
+	if engine == nil {
+		return nil, errors.New("can't create queue runner without engine")
+	}
+	if source == nil {
+		return nil, errors.New("can't create queue runner without message source")
+	}
+	if seed == nil {
+		return nil, errors.New("can't create queue runner without seed function")
+	}
+	return &QueueRunner{engine: engine, source: source, seed: seed}, nil
+}
+
+// ConfigureResultHandler add a function to receive the result of the computation of each message.
+func (r *QueueRunner) ConfigureResultHandler(handler func(*Message, ComputationResult)) {
+	r.resultHandler = handler
+}
+
+// Run consume the messages until the context is done or the source fail to receive a message.
+func (r *QueueRunner) Run(ctx context.Context) error {
+	for {
+		message, err := r.source.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		err = r.process(ctx, message)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *QueueRunner) process(ctx context.Context, message *Message) error {
+	data, err := r.seed(message)
+	if err != nil {
+		return r.source.Nack(ctx, message)
+	}
+
+	result := r.engine.Compute(data)
+	if r.resultHandler != nil {
+		r.resultHandler(message, result)
+	}
+
+	if result.Error != nil {
+		return r.source.Nack(ctx, message)
+	}
+	return r.source.Ack(ctx, message)
+}
@@ -0,0 +1,129 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeMessageSource struct {
+	messages []*Message
+	acked    []string
+	nacked   []string
+}
+
+func (s *fakeMessageSource) Receive(ctx context.Context) (*Message, error) {
+	if len(s.messages) == 0 {
+		return nil, errors.New("no more messages")
+	}
+	message := s.messages[0]
+	s.messages = s.messages[1:]
+	return message, nil
+}
+
+func (s *fakeMessageSource) Ack(ctx context.Context, m *Message) error {
+	s.acked = append(s.acked, m.ID)
+	return nil
+}
+
+func (s *fakeMessageSource) Nack(ctx context.Context, m *Message) error {
+	s.nacked = append(s.nacked, m.ID)
+	return nil
+}
+
+func Test_NewQueueRunner(t *testing.T) {
+	seed := func(*Message) (map[string]interface{}, error) { return nil, nil }
+
+	testCases := []struct {
+		name          string
+		givenEngine   *Engine
+		givenSource   MessageSource
+		givenSeed     func(*Message) (map[string]interface{}, error)
+		expectedError error
+	}{
+		{
+			name:          "Can't create a queue runner without engine",
+			givenSource:   &fakeMessageSource{},
+			givenSeed:     seed,
+			expectedError: errors.New("can't create queue runner without engine"),
+		},
+		{
+			name:          "Can't create a queue runner without message source",
+			givenEngine:   NewEngine(SequentialComputation),
+			givenSeed:     seed,
+			expectedError: errors.New("can't create queue runner without message source"),
+		},
+		{
+			name:          "Can't create a queue runner without seed function",
+			givenEngine:   NewEngine(SequentialComputation),
+			givenSource:   &fakeMessageSource{},
+			expectedError: errors.New("can't create queue runner without seed function"),
+		},
+		{
+			name:        "Can create a queue runner",
+			givenEngine: NewEngine(SequentialComputation),
+			givenSource: &fakeMessageSource{},
+			givenSeed:   seed,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewQueueRunner(testCase.givenEngine, testCase.givenSource, testCase.givenSeed)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_QueueRunner_Run(t *testing.T) {
+	checkValue, _ := NewActionNode("checkValue", func(c *Context) error {
+		value, _ := c.Read("value")
+		if value != "valid" {
+			return errors.New("invalid value")
+		}
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(checkValue)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	source := &fakeMessageSource{
+		messages: []*Message{
+			{ID: "1", Value: []byte("valid")},
+			{ID: "2", Value: []byte("invalid")},
+			{ID: "3"},
+		},
+	}
+	runner, _ := NewQueueRunner(eng, source, func(m *Message) (map[string]interface{}, error) {
+		if m.Value == nil {
+			return nil, errors.New("empty message")
+		}
+		return map[string]interface{}{"value": string(m.Value)}, nil
+	})
+	results := 0
+	runner.ConfigureResultHandler(func(*Message, ComputationResult) {
+		results++
+	})
+
+	err := runner.Run(context.Background())
+	expectedError := errors.New("no more messages")
+
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+	if !cmp.Equal(source.acked, []string{"1"}) {
+		t.Errorf("acked - got: %+v, want: %+v", source.acked, []string{"1"})
+	}
+	if !cmp.Equal(source.nacked, []string{"2", "3"}) {
+		t.Errorf("nacked - got: %+v, want: %+v", source.nacked, []string{"2", "3"})
+	}
+	if results != 2 {
+		t.Errorf("results - got: %+v, want: %+v", results, 2)
+	}
+}
@@ -0,0 +1,282 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// RaceError is the error of a RaceNode when none of its nodes succeed,
+// with the error of each node, in the order of the nodes.
+type RaceError struct {
+	Node   string
+	Errors []error
+}
+
+func (e *RaceError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("can't win race of node '%v': %v", e.Node, strings.Join(messages, "; "))
+}
+
+// Unwrap give the errors of the nodes.
+func (e *RaceError) Unwrap() []error {
+	return e.Errors
+}
+
+// RaceNode is a type of Node who compute several nodes concurrently, like redundant providers,
+// and continue with the first node who continue. The other nodes are cancelled through the Go context
+// (see Context.GoContext), and their changes on the context data are dropped.
+// Each node compute on its own copy of the context data, only the changes of the winner are kept,
+// and the values put in the context store by the other nodes are removed (see Context.StoreExternal).
+type RaceNode struct {
+	name  string
+	nodes []Node
+}
+
+func (n RaceNode) String() string {
+	return n.name
+}
+
+// raceResult is the compute state of a node of a race, with its branch of the context.
+type raceResult struct {
+	index  int
+	state  ComputeState
+	branch *Context
+}
+
+// Compute run the nodes concurrently, and keep the first success.
+// The race abort with a RaceError if all the nodes fail, or is skipped if all the nodes are skipped.
+func (n *RaceNode) Compute(c *Context) ComputeState {
+	ctx, cancel := context.WithCancel(c.GoContext())
+	defer cancel()
+	results := make(chan raceResult, len(n.nodes))
+	for i, node := range n.nodes {
+		go func(i int, node Node, branch *Context) {
+			results <- raceResult{index: i, state: computeBranch(node, branch), branch: branch}
+		}(i, node, c.branch(ctx))
+	}
+
+	states := make([]ComputeState, len(n.nodes))
+	for received := 1; received <= len(n.nodes); received++ {
+		result := <-results
+		if result.state.Value == ContinueState {
+			c.merge(result.branch)
+			go releaseBranches(results, len(n.nodes)-received)
+			return result.state
+		}
+		result.branch.release()
+		states[result.index] = result.state
+	}
+
+	errs := make([]error, 0, len(n.nodes))
+	for i, state := range states {
+		if state.Value == SkipState && state.Error == nil {
+			continue
+		}
+		err := state.Error
+		if err == nil {
+			err = fmt.Errorf("state %v", state.Value)
+		}
+		errs = append(errs, fmt.Errorf("%v: %w", n.nodes[i], err))
+	}
+	if len(errs) == 0 {
+		return NewSkipComputeState()
+	}
+	return NewAbortComputeState(&RaceError{Node: n.name, Errors: errs})
+}
+
+// DecideCapability tell if the nodes of the race take a decision during compute.
+func (n *RaceNode) DecideCapability() bool {
+	return n.nodes[0].DecideCapability()
+}
+
+// Nodes give the nodes of the race.
+func (n *RaceNode) Nodes() []Node {
+	return append([]Node(nil), n.nodes...)
+}
+
+// NewRaceNode create a RaceNode based on a name and the nodes to race,
+// the nodes must have the same decide capability.
+func NewRaceNode(name string, nodes ...Node) (*RaceNode, error) {
+	if len(nodes) < 2 {
+		return nil, errors.New("can't create race node with less than two nodes")
+	}
+	for _, node := range nodes {
+		if node == nil {
+			return nil, errors.New("can't create race node with nil node")
+		}
+		if node.DecideCapability() != nodes[0].DecideCapability() {
+			return nil, errors.New("can't create race node with nodes of different decide capabilities")
+		}
+	}
+	return &RaceNode{name: name, nodes: append([]Node(nil), nodes...)}, nil
+}
+
+// computeBranch compute a node on a branch of a context, a panic abort the node.
+func computeBranch(node Node, branch *Context) (state ComputeState) {
+	defer func() {
+		if value := recover(); value != nil {
+			state = NewAbortComputeState(&PanicError{Node: fmt.Sprint(node), Value: value, Stack: debug.Stack()})
+		}
+	}()
+	return node.Compute(branch)
+}
+
+// releaseBranches wait for the remaining nodes of a race to release their branches of the context.
+func releaseBranches(results chan raceResult, remaining int) {
+	for i := 0; i < remaining; i++ {
+		result := <-results
+		result.branch.release()
+	}
+}
+
+// branch give a copy of the context, with its own data, context store, and Go context,
+// to compute a node concurrently with others (see merge).
+func (c *Context) branch(ctx context.Context) *Context {
+	data := make(map[string]interface{}, len(c.Data))
+	for key, value := range c.Data {
+		data[key] = value
+	}
+	branch := &Context{
+		Data:          data,
+		strict:        c.strict,
+		store:         newBranchStore(c.store),
+		cipher:        c.cipher,
+		computationID: c.computationID,
+		seed:          c.seed,
+		random:        c.Rand(),
+		goContext:     ctx,
+		locks:         c.locks,
+	}
+	if c.strict {
+		branch.knownKeys = make(map[string]bool, len(c.knownKeys))
+		for key := range c.knownKeys {
+			branch.knownKeys[key] = true
+		}
+	}
+	return branch
+}
+
+// merge apply the changes of a branch of the context through its writes (see Context.Store and Context.Delete),
+// with the deletions of the context store values done by the branch.
+func (c *Context) merge(branch *Context) {
+	locks := c.keyLocks()
+	locks.data.Lock()
+	deletedKeys := make([]string, 0)
+	deletedReferences := make(map[string]bool)
+	for key, value := range c.Data {
+		if _, found := branch.Data[key]; !found {
+			deletedKeys = append(deletedKeys, key)
+			if reference, isReference := value.(ContextReference); isReference {
+				deletedReferences[reference.Reference] = true
+			}
+		}
+	}
+	locks.data.Unlock()
+	for _, key := range deletedKeys {
+		c.Delete(key)
+	}
+	for key, value := range branch.Data {
+		c.Store(key, value)
+	}
+	if store, isBranchStore := branch.store.(*branchStore); isBranchStore {
+		puts, deletions := store.take()
+		if parent, isBranchStore := c.store.(*branchStore); isBranchStore {
+			parent.keepPuts(puts)
+		}
+		for _, reference := range deletions {
+			if !deletedReferences[reference] {
+				c.deleteReference(reference)
+			}
+		}
+	}
+	c.unknownReads = append(c.unknownReads, branch.unknownReads...)
+	c.storeErrors = append(c.storeErrors, branch.storeErrors...)
+	c.cleanups = append(c.cleanups, branch.cleanups...)
+}
+
+// release run the cleanup functions of a dropped branch of the context,
+// and remove the context store values put by the branch, its deletions being forgotten
+// as the other branches still reference the deleted values.
+func (c *Context) release() {
+	c.runCleanups()
+	if store, isBranchStore := c.store.(*branchStore); isBranchStore {
+		puts, _ := store.take()
+		for _, reference := range puts {
+			store.ContextStore.Delete(reference)
+		}
+	}
+}
+
+// branchStore is the context store of a branch of a context,
+// who keep the deletions of the branch until the branch is merged (see Context.merge),
+// and the values put by the branch to remove them if the branch is dropped (see Context.release).
+type branchStore struct {
+	ContextStore
+
+	mu        sync.Mutex
+	puts      []string
+	deletions []string
+}
+
+// newBranchStore give the context store of a branch, on top of the store shared by all the branches.
+func newBranchStore(store ContextStore) ContextStore {
+	if store == nil {
+		return nil
+	}
+	if parent, isBranchStore := store.(*branchStore); isBranchStore {
+		store = parent.ContextStore
+	}
+	return &branchStore{ContextStore: store}
+}
+
+// Put store a value in the shared store, and keep its reference.
+func (s *branchStore) Put(key string, value interface{}) (string, error) {
+	reference, err := s.ContextStore.Put(key, value)
+	if err != nil {
+		return "", err
+	}
+	s.keepPuts([]string{reference})
+	return reference, nil
+}
+
+// Delete keep the reference to remove the value once the branch is merged.
+func (s *branchStore) Delete(reference string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletions = append(s.deletions, reference)
+	return nil
+}
+
+func (s *branchStore) keepPuts(references []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puts = append(s.puts, references...)
+}
+
+// take give and forget the references put and deleted by the branch,
+// the put references being the ones not deleted.
+func (s *branchStore) take() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := make(map[string]bool, len(s.deletions))
+	for _, reference := range s.deletions {
+		deleted[reference] = true
+	}
+	puts := make([]string, 0, len(s.puts))
+	for _, reference := range s.puts {
+		if !deleted[reference] {
+			puts = append(puts, reference)
+		}
+	}
+	deletions := s.deletions
+	s.puts = nil
+	s.deletions = nil
+	return puts, deletions
+}
@@ -0,0 +1,279 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// providerNode build an action node who store its name as provider after a delay,
+// or fail with an error, and who record if it was cancelled.
+func providerNode(name string, delay time.Duration, err error, cancelled *sync.Map) Node {
+	node, _ := NewActionNode(name, func(c *Context) error {
+		select {
+		case <-time.After(delay):
+		case <-c.GoContext().Done():
+			cancelled.Store(name, true)
+			c.Store("cancelled_"+name, true)
+			return c.GoContext().Err()
+		}
+		if err != nil {
+			return err
+		}
+		c.Store("provider", name)
+		c.Delete("stale")
+		return nil
+	})
+	return node
+}
+
+func Test_NewRaceNode(t *testing.T) {
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	decision, _ := NewDecisionNode("decision", func(*Context) (bool, error) { return true, nil })
+
+	testCases := []struct {
+		name          string
+		givenNodes    []Node
+		expectedError error
+	}{
+		{
+			name:       "Can create a race node",
+			givenNodes: []Node{action, action},
+		},
+		{
+			name:          "Can't create a race node with one node",
+			givenNodes:    []Node{action},
+			expectedError: errors.New("can't create race node with less than two nodes"),
+		},
+		{
+			name:          "Can't create a race node with nil node",
+			givenNodes:    []Node{action, nil},
+			expectedError: errors.New("can't create race node with nil node"),
+		},
+		{
+			name:          "Can't create a race node with nodes of different decide capabilities",
+			givenNodes:    []Node{action, decision},
+			expectedError: errors.New("can't create race node with nodes of different decide capabilities"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewRaceNode("race", testCase.givenNodes...)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_RaceNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name              string
+		givenProviders    func(cancelled *sync.Map) []Node
+		expectedState     ComputeState
+		expectedData      map[string]interface{}
+		expectedCancelled []string
+	}{
+		{
+			name: "Can keep the first success and cancel the others",
+			givenProviders: func(cancelled *sync.Map) []Node {
+				return []Node{
+					providerNode("slow", time.Minute, nil, cancelled),
+					providerNode("fast", time.Millisecond, nil, cancelled),
+				}
+			},
+			expectedState:     NewContinueComputeState(),
+			expectedData:      map[string]interface{}{"order": 42, "provider": "fast"},
+			expectedCancelled: []string{"slow"},
+		},
+		{
+			name: "Can keep a success after a failure",
+			givenProviders: func(cancelled *sync.Map) []Node {
+				return []Node{
+					providerNode("failing", time.Millisecond, errors.New("unavailable"), cancelled),
+					providerNode("slow", 20*time.Millisecond, nil, cancelled),
+				}
+			},
+			expectedState:     NewContinueComputeState(),
+			expectedData:      map[string]interface{}{"order": 42, "provider": "slow"},
+			expectedCancelled: []string{},
+		},
+		{
+			name: "Can't win a race with only failures",
+			givenProviders: func(cancelled *sync.Map) []Node {
+				panicking, _ := NewActionNode("panicking", func(*Context) error { panic("broken") })
+				return []Node{
+					providerNode("failing", 10*time.Millisecond, errors.New("unavailable"), cancelled),
+					panicking,
+				}
+			},
+			expectedState:     NewAbortComputeState(errors.New("can't win race of node 'race': failing: unavailable; panicking: can't compute node 'panicking', panic: broken")),
+			expectedData:      map[string]interface{}{"order": 42, "stale": true},
+			expectedCancelled: []string{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cancelled := &sync.Map{}
+			race, _ := NewRaceNode("race", testCase.givenProviders(cancelled)...)
+			c := NewContext(map[string]interface{}{"order": 42, "stale": true})
+
+			state := race.Compute(c)
+
+			if errorMessage(state.Error) != errorMessage(testCase.expectedState.Error) || state.Value != testCase.expectedState.Value {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+			time.Sleep(10 * time.Millisecond)
+			names := make([]string, 0)
+			cancelled.Range(func(key, value interface{}) bool {
+				names = append(names, key.(string))
+				return true
+			})
+			if !cmp.Equal(names, testCase.expectedCancelled) {
+				t.Errorf("cancelled - got: %+v, want: %+v", names, testCase.expectedCancelled)
+			}
+		})
+	}
+}
+
+func Test_RaceNode_Compute_decision(t *testing.T) {
+	slow, _ := NewDecisionNode("slow", func(c *Context) (bool, error) {
+		<-c.GoContext().Done()
+		return false, c.GoContext().Err()
+	})
+	fast, _ := NewDecisionNode("fast", func(*Context) (bool, error) { return true, nil })
+	race, _ := NewRaceNode("race", slow, fast)
+
+	state := race.Compute(NewContextWithoutData())
+
+	expectedState := NewContinueOnBranchComputeState(true)
+	if !race.DecideCapability() || !cmp.Equal(state, expectedState) {
+		t.Errorf("got: %+v, want: %+v", state, expectedState)
+	}
+}
+
+func Test_RaceNode_engine(t *testing.T) {
+	cleaned := make(chan string, 2)
+	provider := func(name string, delay time.Duration) Node {
+		node, _ := NewActionNode(name, func(c *Context) error {
+			c.AddCleanup(func() { cleaned <- name })
+			select {
+			case <-time.After(delay):
+				c.Store("provider", name)
+				return nil
+			case <-c.GoContext().Done():
+				return c.GoContext().Err()
+			}
+		})
+		return node
+	}
+	race, _ := NewRaceNode("race", provider("slow", time.Minute), provider("fast", time.Millisecond))
+	ns := NewNodeSystem()
+	ns.AddNode(race)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	result := eng.Compute(map[string]interface{}{})
+
+	if result.Error != nil || !cmp.Equal(result.Data, map[string]interface{}{"provider": "fast"}) {
+		t.Errorf("result - got: %+v %+v, want: %+v", result.Error, result.Data, map[string]interface{}{"provider": "fast"})
+	}
+	got := []string{<-cleaned, <-cleaned}
+	if !cmp.Equal(got, []string{"fast", "slow"}) && !cmp.Equal(got, []string{"slow", "fast"}) {
+		t.Errorf("cleaned - got: %+v, want: %+v", got, []string{"fast", "slow"})
+	}
+}
+
+// computeWithExternalValue compute a node between a node storing the key "big" in a context store,
+// and a node reading it into the key "read", and give the result with the store.
+func computeWithExternalValue(node Node, configure func(*NodeSystem, *Engine)) (ComputationResult, *MemoryContextStore) {
+	writer, _ := NewActionNode("writer", func(c *Context) error {
+		return c.StoreExternal("big", "payload")
+	})
+	reader, _ := NewActionNode("reader", func(c *Context) error {
+		value, found := c.Read("big")
+		if !found {
+			return errors.New("can't read big")
+		}
+		c.Store("read", value)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(writer)
+	ns.AddNode(node)
+	ns.AddNode(reader)
+	ns.AddLink(writer, node)
+	ns.AddLink(node, reader)
+	store := NewMemoryContextStore()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureContextStore(store)
+	configure(ns, eng)
+	ns.ActivateInPlace()
+	eng.ConfigureNodeSystem(ns)
+	return eng.Compute(map[string]interface{}{}), store
+}
+
+func Test_RaceNode_engine_externalDelete(t *testing.T) {
+	failing, _ := NewActionNode("failing", func(c *Context) error {
+		c.StoreExternal("draft", "payload")
+		c.Delete("big")
+		return errors.New("unavailable")
+	})
+	slow, _ := NewActionNode("slow", func(c *Context) error {
+		c.Delete("big")
+		<-c.GoContext().Done()
+		return c.GoContext().Err()
+	})
+	fast, _ := NewActionNode("fast", func(c *Context) error {
+		time.Sleep(10 * time.Millisecond)
+		c.Store("provider", "fast")
+		return nil
+	})
+	race, _ := NewRaceNode("race", failing, slow, fast)
+
+	result, store := computeWithExternalValue(race, func(*NodeSystem, *Engine) {})
+
+	if result.Error != nil || result.Data["read"] != "payload" {
+		t.Errorf("result - got: %+v %+v, want: %+v", result.Error, result.Data["read"], "payload")
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.values) != 1 {
+		t.Errorf("store - got: %+v, want: only the value of big", store.values)
+	}
+}
+
+func Test_Context_merge_staging(t *testing.T) {
+	store := NewMemoryContextStore()
+	c := NewContext(map[string]interface{}{"draft": "yes"})
+	c.ConfigureStore(store)
+	c.StoreExternal("big", "payload")
+	branch := c.branch(context.Background())
+	branch.Delete("draft")
+	branch.Delete("big")
+	branch.StoreExternal("big", "new payload")
+
+	buffer := c.startStaging()
+	c.merge(branch)
+
+	if _, found := c.Data["draft"]; !found || len(store.values) != 2 {
+		t.Errorf("before commit - got: %+v %+v, want: draft and both values", c.Data, store.values)
+	}
+	if c.HaveKey("draft") {
+		t.Errorf("staged - got: draft, want: deleted")
+	}
+	c.commitStaging(buffer)
+	value, _ := c.Read("big")
+	if _, found := c.Data["draft"]; found || len(store.values) != 1 || value != "new payload" {
+		t.Errorf("after commit - got: %+v %+v, want: only the new payload", c.Data, store.values)
+	}
+}
@@ -0,0 +1,58 @@
+package hoff
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// Rand give the random generator of the computation, seeded by the computation seed (see Seed).
+// The generator is safe for concurrent use, but only a sequential computation draw
+// the same numbers from the same seed.
+func (c *Context) Rand() *rand.Rand {
+	if c.random == nil {
+		c.setSeed(c.seed)
+	}
+	return c.random
+}
+
+// Seed give the seed of the computation random generator,
+// to compute again with the same random numbers (see SubmitOptions).
+func (c *Context) Seed() int64 {
+	return c.seed
+}
+
+// setSeed seed the random generator.
+func (c *Context) setSeed(seed int64) {
+	c.seed = seed
+	c.random = rand.New(&lockedSource{source: rand.NewSource(seed)})
+}
+
+// newSeed generate a random seed.
+func newSeed() (int64, error) {
+	bytes := make([]byte, 8)
+	_, err := cryptorand.Read(bytes)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(bytes)), nil
+}
+
+// lockedSource is a random source safe for concurrent use.
+type lockedSource struct {
+	mu     sync.Mutex
+	source rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.source.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source.Seed(seed)
+}
@@ -0,0 +1,42 @@
+package hoff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Context_Rand(t *testing.T) {
+	sampleAction, _ := NewActionNode("sampleAction", func(c *Context) error {
+		c.Store("sample", c.Rand().Intn(1000000))
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(sampleAction)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	first := eng.Compute(make(map[string]interface{}))
+	if first.Error != nil {
+		t.Errorf("error - got: %+v, want: %+v", first.Error, nil)
+	}
+
+	replayed, _ := eng.Submit(make(map[string]interface{}), SubmitOptions{Seed: &first.Seed}).Wait(context.Background())
+	if replayed.Seed != first.Seed {
+		t.Errorf("seed - got: %+v, want: %+v", replayed.Seed, first.Seed)
+	}
+	if !cmp.Equal(replayed.Data, first.Data) {
+		t.Errorf("data - got: %+v, want: %+v", replayed.Data, first.Data)
+	}
+
+	seed := int64(42)
+	expectedSample := NewContextWithoutData()
+	expectedSample.setSeed(seed)
+	expectedData := map[string]interface{}{"sample": expectedSample.Rand().Intn(1000000)}
+	seeded, _ := eng.Submit(make(map[string]interface{}), SubmitOptions{Seed: &seed}).Wait(context.Background())
+	if !cmp.Equal(seeded.Data, expectedData) {
+		t.Errorf("seeded data - got: %+v, want: %+v", seeded.Data, expectedData)
+	}
+}
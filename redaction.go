@@ -0,0 +1,130 @@
+package hoff
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Redactor mask the sensitive values of the computations before their reporting,
+// based on keys of the context data and on patterns of the text values and errors.
+// It's independent of the secrets, who are always redacted.
+type Redactor struct {
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor create a redactor without rules.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		keys: make(map[string]bool),
+	}
+}
+
+// AddKeys mask the whole value of the context keys (at any depth in the data).
+func (r *Redactor) AddKeys(keys ...string) {
+	for _, key := range keys {
+		r.keys[key] = true
+	}
+}
+
+// AddPattern mask the parts of the text values and errors matching a regular expression,
+// e.g. `[a-z0-9._%+-]+@[a-z0-9.-]+` for emails.
+func (r *Redactor) AddPattern(pattern string) error {
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("can't add redaction pattern '%v': %v", pattern, err)
+	}
+	r.patterns = append(r.patterns, expression)
+	return nil
+}
+
+// RedactString mask the parts of a text matching the patterns.
+func (r *Redactor) RedactString(text string) string {
+	for _, pattern := range r.patterns {
+		text = pattern.ReplaceAllString(text, redactedValue)
+	}
+	return text
+}
+
+// Redact give a copy of a computation result with its data and errors masked,
+// to be reported, sent to a dead letter, or exported.
+func (r *Redactor) Redact(result ComputationResult) ComputationResult {
+	redacted := result
+	if result.Data != nil {
+		redacted.Data = r.redactData(result.Data)
+	}
+	if result.Outputs != nil {
+		redacted.Outputs = r.redactData(result.Outputs)
+	}
+	redacted.Error = r.redactError(result.Error)
+	if result.Report != nil {
+		redacted.Report = make(map[Node]ComputeState, len(result.Report))
+		for node, state := range result.Report {
+			state.Error = r.redactError(state.Error)
+			redacted.Report[node] = state
+		}
+	}
+	return redacted
+}
+
+// RedactEvent give a copy of an event with its errors masked.
+func (r *Redactor) RedactEvent(event Event) Event {
+	event.Error = r.redactError(event.Error)
+	event.State.Error = r.redactError(event.State.Error)
+	return event
+}
+
+func (r *Redactor) redactData(data map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if r.keys[key] {
+			redacted[key] = redactedValue
+			continue
+		}
+		redacted[key] = r.redactValue(value)
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case map[string]interface{}:
+		return r.redactData(v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) redactError(err error) error {
+	if err == nil || len(r.patterns) == 0 {
+		return err
+	}
+	message := r.RedactString(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{message: message, err: err}
+}
+
+// redactedError is an error with a masked message, who still wrap the original error
+// to be found with errors.Is or errors.As.
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,109 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Redactor_AddPattern(t *testing.T) {
+	redactor := NewRedactor()
+	err := redactor.AddPattern("[a-z")
+
+	expectedError := errors.New("can't add redaction pattern '[a-z': error parsing regexp: missing closing ]: `[a-z`")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Redactor_Redact(t *testing.T) {
+	redactor := NewRedactor()
+	redactor.AddKeys("token")
+	_ = redactor.AddPattern(`[a-z0-9.]+@[a-z0-9.]+`)
+
+	testCases := []struct {
+		name           string
+		givenResult    ComputationResult
+		expectedResult ComputationResult
+	}{
+		{
+			name:           "Can redact a result without data",
+			givenResult:    ComputationResult{ID: "id"},
+			expectedResult: ComputationResult{ID: "id"},
+		},
+		{
+			name: "Can redact data by key and pattern",
+			givenResult: ComputationResult{
+				Data: map[string]interface{}{
+					"token": "abc123",
+					"user":  map[string]interface{}{"email": "john@doe.com", "token": 42},
+					"cc":    []interface{}{"jane@doe.com", 7},
+					"count": 3,
+				},
+			},
+			expectedResult: ComputationResult{
+				Data: map[string]interface{}{
+					"token": "[REDACTED]",
+					"user":  map[string]interface{}{"email": "[REDACTED]", "token": "[REDACTED]"},
+					"cc":    []interface{}{"[REDACTED]", 7},
+					"count": 3,
+				},
+			},
+		},
+		{
+			name: "Can redact errors by pattern",
+			givenResult: ComputationResult{
+				Error:  errors.New("can't notify john@doe.com"),
+				Report: map[Node]ComputeState{someActionNode: NewAbortComputeState(errors.New("unknown user jane@doe.com"))},
+			},
+			expectedResult: ComputationResult{
+				Error:  errors.New("can't notify [REDACTED]"),
+				Report: map[Node]ComputeState{someActionNode: NewAbortComputeState(errors.New("unknown user [REDACTED]"))},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := redactor.Redact(testCase.givenResult)
+
+			if !cmp.Equal(result, testCase.expectedResult, errorComparator, NodeComparator) {
+				t.Errorf("got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+		})
+	}
+}
+
+func Test_Redactor_Redact_keep_original(t *testing.T) {
+	redactor := NewRedactor()
+	redactor.AddKeys("token")
+	data := map[string]interface{}{"token": "abc123"}
+
+	redactor.Redact(ComputationResult{Data: data})
+
+	if data["token"] != "abc123" {
+		t.Errorf("got: %+v, want: %+v", data["token"], "abc123")
+	}
+}
+
+func Test_JSONLinesEventSink_ConfigureRedactor(t *testing.T) {
+	redactor := NewRedactor()
+	_ = redactor.AddPattern(`token=\w+`)
+	var buffer bytes.Buffer
+	sink, _ := NewJSONLinesEventSink(&buffer)
+	sink.ConfigureRedactor(redactor)
+
+	sink.Handle(Event{
+		Type:          ComputationEndedEvent,
+		Time:          time.Date(2019, 1, 2, 10, 7, 30, 0, time.UTC),
+		ComputationID: "id",
+		Error:         errors.New("can't call api with token=abc123"),
+	})
+
+	expectedLine := `{"type":"computation_ended","time":"2019-01-02T10:07:30Z","computation_id":"id","error":"can't call api with [REDACTED]"}` + "\n"
+	if buffer.String() != expectedLine {
+		t.Errorf("got: %v, want: %v", buffer.String(), expectedLine)
+	}
+}
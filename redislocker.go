@@ -0,0 +1,206 @@
+package hoff
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// redisUnlockScript delete a lock only if still owned by the token.
+const redisUnlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// RedisLocker is a Locker based on Redis keys (SET NX PX), shared by the engine replicas using the same Redis.
+// It talk to Redis with the RESP protocol over a single connection, reopened after an error.
+type RedisLocker struct {
+	dial     func() (net.Conn, error)
+	prefix   string
+	password string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisLocker create a locker on the Redis server at an address (host:port),
+// with the keys prefixed by 'hoff:lock:'.
+func NewRedisLocker(address string) (*RedisLocker, error) {
+	if address == "" {
+		return nil, errors.New("can't create redis locker without address")
+	}
+	return &RedisLocker{
+		dial: func() (net.Conn, error) {
+			return net.DialTimeout("tcp", address, 5*time.Second)
+		},
+		prefix: "hoff:lock:",
+	}, nil
+}
+
+// ConfigureDialer replace how to open the connection to Redis, like to use TLS.
+func (l *RedisLocker) ConfigureDialer(dial func() (net.Conn, error)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dial = dial
+	l.closeConnection()
+}
+
+// ConfigurePassword add the password to authenticate on Redis.
+func (l *RedisLocker) ConfigurePassword(password string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.password = password
+	l.closeConnection()
+}
+
+// ConfigureKeyPrefix replace the prefix of the Redis keys.
+func (l *RedisLocker) ConfigureKeyPrefix(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prefix = prefix
+}
+
+// Lock acquire the lock of a key for a time to live.
+func (l *RedisLocker) Lock(key string, ttl time.Duration) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	milliseconds := ttl.Nanoseconds() / int64(time.Millisecond)
+	if milliseconds < 1 {
+		milliseconds = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	reply, err := l.command("SET", l.prefix+key, token, "NX", "PX", strconv.FormatInt(milliseconds, 10))
+	if err != nil {
+		return "", fmt.Errorf("can't lock '%v' on redis: %w", key, err)
+	}
+	if reply == nil {
+		return "", ErrLocked
+	}
+	return token, nil
+}
+
+// Unlock release the lock of a key if still acquired with the token.
+func (l *RedisLocker) Unlock(key, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.command("EVAL", redisUnlockScript, "1", l.prefix+key, token)
+	if err != nil {
+		return fmt.Errorf("can't unlock '%v' on redis: %w", key, err)
+	}
+	return nil
+}
+
+// Close close the connection to Redis.
+func (l *RedisLocker) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closeConnection()
+}
+
+func (l *RedisLocker) closeConnection() error {
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn, l.reader = nil, nil
+	return err
+}
+
+// command send a command and give its reply, the connection is closed on error.
+func (l *RedisLocker) command(args ...string) (interface{}, error) {
+	if l.conn == nil {
+		conn, err := l.dial()
+		if err != nil {
+			return nil, err
+		}
+		l.conn, l.reader = conn, bufio.NewReader(conn)
+		if l.password != "" {
+			if _, err = l.roundTrip("AUTH", l.password); err != nil {
+				l.closeConnection()
+				return nil, err
+			}
+		}
+	}
+	reply, err := l.roundTrip(args...)
+	if _, isRedisError := err.(redisError); err != nil && !isRedisError {
+		l.closeConnection()
+	}
+	return reply, err
+}
+
+func (l *RedisLocker) roundTrip(args ...string) (interface{}, error) {
+	l.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	_, err := l.conn.Write(encodeRedisCommand(args))
+	if err != nil {
+		return nil, err
+	}
+	return readRedisReply(l.reader)
+}
+
+// redisError is an error reply of Redis.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// encodeRedisCommand give a command as a RESP array of bulk strings.
+func encodeRedisCommand(args []string) []byte {
+	command := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		command = append(command, fmt.Sprintf("$%d\r\n%v\r\n", len(arg), arg)...)
+	}
+	return command
+}
+
+// readRedisReply read a RESP reply, a nil bulk string or array is read as nil.
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("can't read redis reply: %q", line)
+	}
+	kind, value := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return value, nil
+	case '-':
+		return nil, redisError(value)
+	case ':':
+		return strconv.ParseInt(value, 10, 64)
+	case '$':
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 0 {
+			return nil, err
+		}
+		bulk := make([]byte, length+2)
+		if _, err = io.ReadFull(reader, bulk); err != nil {
+			return nil, err
+		}
+		return string(bulk[:length]), nil
+	case '*':
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 0 {
+			return nil, err
+		}
+		array := make([]interface{}, 0, length)
+		for index := 0; index < length; index++ {
+			element, err := readRedisReply(reader)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, element)
+		}
+		return array, nil
+	}
+	return nil, fmt.Errorf("can't read redis reply: %q", line)
+}
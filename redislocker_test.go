@@ -0,0 +1,161 @@
+package hoff
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeRedis serve the commands used by the RedisLocker on a local listener.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+	mu       sync.Mutex
+	values   map[string]string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeRedis{listener: listener, password: password, values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return
+		}
+		args := make([]string, 0)
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, arg.(string))
+		}
+
+		s.mu.Lock()
+		var response string
+		switch {
+		case args[0] == "AUTH" && args[1] == s.password:
+			authenticated = true
+			response = "+OK\r\n"
+		case args[0] == "AUTH":
+			response = "-WRONGPASS invalid password\r\n"
+		case !authenticated:
+			response = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SET":
+			if _, found := s.values[args[1]]; found {
+				response = "$-1\r\n"
+			} else {
+				s.values[args[1]] = args[2]
+				response = "+OK\r\n"
+			}
+		case args[0] == "EVAL":
+			if s.values[args[3]] == args[4] {
+				delete(s.values, args[3])
+				response = ":1\r\n"
+			} else {
+				response = ":0\r\n"
+			}
+		default:
+			response = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		conn.Write([]byte(response))
+	}
+}
+
+func Test_RedisLocker(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.listener.Close()
+
+	locker, _ := NewRedisLocker(server.listener.Addr().String())
+	defer locker.Close()
+
+	_, err := locker.Lock("key", time.Minute)
+	expectedError := fmt.Errorf("can't lock 'key' on redis: %w", redisError("NOAUTH Authentication required."))
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("lock without password - got: %+v, want: %+v", err, expectedError)
+	}
+
+	locker.ConfigurePassword("secret")
+	token, err := locker.Lock("key", time.Minute)
+	if err != nil {
+		t.Errorf("lock - got: %+v, want: %+v", err, nil)
+	}
+	if server.values["hoff:lock:key"] != token {
+		t.Errorf("redis value - got: %+v, want: %+v", server.values["hoff:lock:key"], token)
+	}
+	_, err = locker.Lock("key", time.Minute)
+	if err != ErrLocked {
+		t.Errorf("lock again - got: %+v, want: %+v", err, ErrLocked)
+	}
+
+	locker.Unlock("key", "another token")
+	_, err = locker.Lock("key", time.Minute)
+	if err != ErrLocked {
+		t.Errorf("lock after unlock with another token - got: %+v, want: %+v", err, ErrLocked)
+	}
+	locker.Unlock("key", token)
+	_, err = locker.Lock("key", time.Minute)
+	if err != nil {
+		t.Errorf("lock after unlock - got: %+v, want: %+v", err, nil)
+	}
+}
+
+func Test_NewRedisLocker(t *testing.T) {
+	_, err := NewRedisLocker("")
+	expectedError := errors.New("can't create redis locker without address")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_readRedisReply(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenReply    string
+		expectedValue interface{}
+		expectedError error
+	}{
+		{name: "Can read a simple string", givenReply: "+OK\r\n", expectedValue: "OK"},
+		{name: "Can read an integer", givenReply: ":42\r\n", expectedValue: int64(42)},
+		{name: "Can read a bulk string", givenReply: "$5\r\nhello\r\n", expectedValue: "hello"},
+		{name: "Can read a nil bulk string", givenReply: "$-1\r\n"},
+		{name: "Can read an array", givenReply: "*2\r\n$1\r\na\r\n:1\r\n", expectedValue: []interface{}{"a", int64(1)}},
+		{name: "Can read an error", givenReply: "-ERR failure\r\n", expectedError: redisError("ERR failure")},
+		{name: "Can't read an unknown reply", givenReply: "?\r\n", expectedError: errors.New(`can't read redis reply: "?\r\n"`)},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := readRedisReply(bufio.NewReader(strings.NewReader(testCase.givenReply)))
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(value, testCase.expectedValue) {
+				t.Errorf("got: %+v, want: %+v", value, testCase.expectedValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// WorkflowRegistry hold the engines of named workflows, and let a workflow be replaced by a new version
+// without restart (see Watcher).
+type WorkflowRegistry struct {
+	mu      sync.RWMutex
+	engines map[string]*Engine
+}
+
+// NewWorkflowRegistry create an empty WorkflowRegistry.
+func NewWorkflowRegistry() *WorkflowRegistry {
+	return &WorkflowRegistry{engines: make(map[string]*Engine)}
+}
+
+// Register add the engine of a new workflow, with a configured node system.
+func (r *WorkflowRegistry) Register(name string, engine *Engine) error {
+	if err := checkRegisteredEngine(name, engine); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.engines[name]; found {
+		return fmt.Errorf("can't register already registered workflow '%v'", name)
+	}
+	r.engines[name] = engine
+	return nil
+}
+
+// Engine give the engine of the current version of a workflow.
+func (r *WorkflowRegistry) Engine(name string) (*Engine, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	engine, found := r.engines[name]
+	return engine, found
+}
+
+// NodeSystem give the node system of the current version of a workflow.
+func (r *WorkflowRegistry) NodeSystem(name string) (*NodeSystem, bool) {
+	engine, found := r.Engine(name)
+	if !found {
+		return nil, false
+	}
+	return engine.system, true
+}
+
+// Workflows give the names of the registered workflows, sorted.
+func (r *WorkflowRegistry) Workflows() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.engines))
+	for name := range r.engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Swap atomically replace the engine of a workflow, or register it when missing.
+// The new computations are submitted to the new engine right away,
+// then the previous engine is shut down to drain its running computations (see Engine.Shutdown).
+func (r *WorkflowRegistry) Swap(ctx context.Context, name string, engine *Engine) ([]ComputationResult, error) {
+	if err := checkRegisteredEngine(name, engine); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	previous, found := r.engines[name]
+	r.engines[name] = engine
+	r.mu.Unlock()
+	if !found || previous == engine {
+		return nil, nil
+	}
+	return previous.Shutdown(ctx)
+}
+
+func checkRegisteredEngine(name string, engine *Engine) error {
+	if name == "" {
+		return errors.New("can't register workflow without name")
+	}
+	if engine == nil || engine.system == nil {
+		return fmt.Errorf("can't register workflow '%v' without engine configured with a node system", name)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_WorkflowRegistry_Register(t *testing.T) {
+	configured := NewEngine(SequentialComputation)
+	configured.ConfigureNodeSystem(activatedNodeSystemOf(someActionNode))
+	testCases := []struct {
+		name          string
+		givenName     string
+		givenEngine   *Engine
+		expectedError error
+	}{
+		{
+			name:        "Can register a workflow",
+			givenName:   "other",
+			givenEngine: configured,
+		},
+		{
+			name:          "Can't register a workflow twice",
+			givenName:     "orders",
+			givenEngine:   configured,
+			expectedError: errors.New("can't register already registered workflow 'orders'"),
+		},
+		{
+			name:          "Can't register a workflow without name",
+			givenEngine:   configured,
+			expectedError: errors.New("can't register workflow without name"),
+		},
+		{
+			name:          "Can't register a workflow without node system",
+			givenName:     "other",
+			givenEngine:   NewEngine(SequentialComputation),
+			expectedError: errors.New("can't register workflow 'other' without engine configured with a node system"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry := NewWorkflowRegistry()
+			registry.Register("orders", configured)
+
+			err := registry.Register(testCase.givenName, testCase.givenEngine)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_WorkflowRegistry_Swap(t *testing.T) {
+	previous := NewEngine(SequentialComputation)
+	previous.ConfigureNodeSystem(activatedNodeSystemOf(someActionNode))
+	registry := NewWorkflowRegistry()
+	registry.Register("orders", previous)
+	next := NewEngine(SequentialComputation)
+	next.ConfigureNodeSystem(activatedNodeSystemOf(anotherActionNode))
+
+	_, err := registry.Swap(context.Background(), "orders", next)
+
+	if err != nil {
+		t.Errorf("error - got: %+v, want: nothing", err)
+	}
+	engine, _ := registry.Engine("orders")
+	if engine != next {
+		t.Errorf("engine - got: %p, want: %p", engine, next)
+	}
+	if result := previous.Compute(nil); result.Error == nil {
+		t.Errorf("previous engine - got: no error, want: shut down")
+	}
+	if names := registry.Workflows(); !cmp.Equal(names, []string{"orders"}) {
+		t.Errorf("workflows - got: %+v, want: [orders]", names)
+	}
+}
+
+func activatedNodeSystemOf(nodes ...Node) *NodeSystem {
+	ns := NewNodeSystem()
+	for _, node := range nodes {
+		ns.AddNode(node)
+	}
+	ns.ActivateInPlace()
+	return ns
+}
@@ -0,0 +1,209 @@
+package hoff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ComputeService is the contract to compute a Node hosted in another service.
+// It follow the ComputeService definition of proto/compute.proto,
+// a generated gRPC client (or server) only need a thin adapter to be used here.
+type ComputeService interface {
+	// Compute run the requested node against the context data
+	// and respond with its compute state and the changes made on the context data.
+	Compute(ctx context.Context, request *ComputeRequest) (*ComputeResponse, error)
+}
+
+// ComputeRequest hold the node to compute and the context data with JSON-encoded values.
+type ComputeRequest struct {
+	Node string
+	Data map[string][]byte
+}
+
+// ComputeResponse hold the compute state of the node
+// and the changes made on the context data with JSON-encoded values.
+type ComputeResponse struct {
+	State  StateType
+	Branch *bool
+	Error  string
+	// ErrorCauses are the names of the registered errors wrapped by the error (see RegisterError)
+	ErrorCauses []string
+	// Code is the abort code of the error (see AbortCode)
+	Code    AbortCode
+	Stored  map[string][]byte
+	Deleted []string
+}
+
+// RemoteNode is a type of Node who delegate its computation to a ComputeService.
+// The context values go through JSON, so a value is read back
+// as its JSON representation (e.g. a number become a float64).
+type RemoteNode struct {
+	name             string
+	service          ComputeService
+	decideCapability bool
+}
+
+func (n RemoteNode) String() string {
+	return n.name
+}
+
+// Compute send the context data to the remote node with the Go context of the computation, apply the context changes
+// and return the compute state of the remote node.
+func (n *RemoteNode) Compute(c *Context) ComputeState {
+	data, err := encodeContextData(c.readAll())
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+
+	response, err := n.service.Compute(c.GoContext(), &ComputeRequest{
+		Node: n.name,
+		Data: data,
+	})
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't compute remote node '%v': %w", n.name, err))
+	}
+
+	stored, err := decodeContextData(response.Stored)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	for key, value := range stored {
+		c.Store(key, value)
+	}
+	for _, key := range response.Deleted {
+		c.Delete(key)
+	}
+
+	switch response.State {
+	case ContinueState:
+		if n.decideCapability {
+			if response.Branch == nil {
+				return NewAbortComputeState(fmt.Errorf("can't continue remote node '%v' without branch", n.name))
+			}
+			return NewContinueOnBranchComputeState(*response.Branch)
+		}
+		return NewContinueComputeState()
+	case SkipState:
+		return NewSkipComputeState()
+	case AbortState:
+		err := restoreError(response.Error, response.ErrorCauses)
+		if response.Code != "" {
+			return NewAbortWithCodeComputeState(response.Code, err)
+		}
+		return NewAbortComputeState(err)
+	}
+	return NewAbortComputeState(fmt.Errorf("can't handle state '%v' of remote node '%v'", response.State, n.name))
+}
+
+// DecideCapability tell if the remote node take a decision during compute.
+func (n *RemoteNode) DecideCapability() bool {
+	return n.decideCapability
+}
+
+// NewRemoteNode create a RemoteNode based on the name of the node in the remote service,
+// the service to call, and its capability to take a decision.
+func NewRemoteNode(name string, service ComputeService, decideCapability bool) (*RemoteNode, error) {
+	if service == nil {
+		return nil, errors.New("can't create remote node without compute service")
+	}
+	return &RemoteNode{name: name, service: service, decideCapability: decideCapability}, nil
+}
+
+// ComputeServer expose local nodes through the ComputeService contract, by their names.
+type ComputeServer struct {
+	nodes map[string]Node
+}
+
+// NewComputeServer create a ComputeServer who serve the nodes.
+func NewComputeServer(nodes ...Node) (*ComputeServer, error) {
+	server := &ComputeServer{
+		nodes: make(map[string]Node),
+	}
+	for _, node := range nodes {
+		if node == nil {
+			return nil, errors.New("can't serve a missing node")
+		}
+		name := fmt.Sprint(node)
+		if _, found := server.nodes[name]; found {
+			return nil, fmt.Errorf("can't serve multiple nodes with the same name: %v", name)
+		}
+		server.nodes[name] = node
+	}
+	return server, nil
+}
+
+// Compute run the requested node against the context data, with the Go context of the request (see Context.GoContext).
+func (s *ComputeServer) Compute(ctx context.Context, request *ComputeRequest) (*ComputeResponse, error) {
+	node, found := s.nodes[request.Node]
+	if !found {
+		return nil, fmt.Errorf("can't find node '%v'", request.Node)
+	}
+
+	data, err := decodeContextData(request.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewContext(data)
+	c.goContext = ctx
+	state := node.Compute(c)
+
+	computedData, err := encodeContextData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ComputeResponse{
+		State:   state.Value,
+		Branch:  state.Branch,
+		Stored:  make(map[string][]byte),
+		Deleted: make([]string, 0),
+	}
+	if state.Error != nil {
+		response.Error = state.Error.Error()
+		response.ErrorCauses = errorCauses(state.Error)
+		response.Code = state.Code
+	}
+	for key, value := range computedData {
+		previousValue, found := request.Data[key]
+		if !found || !bytes.Equal(previousValue, value) {
+			response.Stored[key] = value
+		}
+	}
+	for key := range request.Data {
+		if _, found := computedData[key]; !found {
+			response.Deleted = append(response.Deleted, key)
+		}
+	}
+	sort.Strings(response.Deleted)
+	return response, nil
+}
+
+func encodeContextData(data map[string]interface{}) (map[string][]byte, error) {
+	encodedData := make(map[string][]byte)
+	for key, value := range data {
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("can't encode context value of '%v': %w", key, err)
+		}
+		encodedData[key] = encodedValue
+	}
+	return encodedData, nil
+}
+
+func decodeContextData(encodedData map[string][]byte) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	for key, encodedValue := range encodedData {
+		var value interface{}
+		err := json.Unmarshal(encodedValue, &value)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode context value of '%v': %w", key, err)
+		}
+		data[key] = value
+	}
+	return data, nil
+}
@@ -0,0 +1,215 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewRemoteNode(t *testing.T) {
+	server, _ := NewComputeServer()
+
+	testCases := []struct {
+		name          string
+		givenService  ComputeService
+		expectedError error
+	}{
+		{
+			name:          "Can't create a remote node without compute service",
+			expectedError: errors.New("can't create remote node without compute service"),
+		},
+		{
+			name:         "Can create a remote node",
+			givenService: server,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewRemoteNode("RemoteNode", testCase.givenService, false)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if testCase.givenService == nil && node != nil {
+				t.Errorf("remote node - got: %+v, want: <nil>", node)
+			}
+		})
+	}
+}
+
+func Test_NewComputeServer(t *testing.T) {
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	sameNameAction, _ := NewActionNode("action", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name          string
+		givenNodes    []Node
+		expectedError error
+	}{
+		{
+			name:       "Can create a compute server",
+			givenNodes: []Node{action},
+		},
+		{
+			name:          "Can't create a compute server with a missing node",
+			givenNodes:    []Node{nil},
+			expectedError: errors.New("can't serve a missing node"),
+		},
+		{
+			name:          "Can't create a compute server with nodes of the same name",
+			givenNodes:    []Node{action, sameNameAction},
+			expectedError: errors.New("can't serve multiple nodes with the same name: action"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewComputeServer(testCase.givenNodes...)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_RemoteNode_Compute(t *testing.T) {
+	writeAction, _ := NewActionNode("writeAction", func(c *Context) error {
+		c.Store("written", "value")
+		c.Store("updated", 2)
+		c.Delete("deleted")
+		return nil
+	})
+	errorAction, _ := NewActionNode("errorAction", func(c *Context) error {
+		return errors.New("action error")
+	})
+	isPresent, _ := NewDecisionNode("isPresent", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	codedErrorAction, _ := NewActionNode("codedErrorAction", func(c *Context) error {
+		return WithAbortCode(TransientAbort, errors.New("service unavailable"))
+	})
+	server, _ := NewComputeServer(writeAction, errorAction, codedErrorAction, isPresent)
+
+	remoteWriteAction, _ := NewRemoteNode("writeAction", server, false)
+	remoteErrorAction, _ := NewRemoteNode("errorAction", server, false)
+	remoteCodedErrorAction, _ := NewRemoteNode("codedErrorAction", server, false)
+	remoteIsPresent, _ := NewRemoteNode("isPresent", server, true)
+	remoteUnknownNode, _ := NewRemoteNode("unknownNode", server, false)
+
+	tc := []NodeTestCase{
+		{
+			name:                 "Should apply the context changes of the remote node",
+			givenContextData:     map[string]interface{}{"kept": "value", "updated": 1, "deleted": "value"},
+			givenNode:            remoteWriteAction,
+			expectedComputeState: NewContinueComputeState(),
+			expectedContextData:  map[string]interface{}{"kept": "value", "updated": float64(2), "written": "value"},
+		},
+		{
+			name:                 "Should Abort with the error of the remote node",
+			givenNode:            remoteErrorAction,
+			expectedComputeState: NewAbortComputeState(errors.New("action error")),
+		},
+		{
+			name:                 "Should Abort with the abort code of the remote node",
+			givenNode:            remoteCodedErrorAction,
+			expectedComputeState: NewAbortWithCodeComputeState(TransientAbort, errors.New("service unavailable")),
+		},
+		{
+			name:                 "Should Continue on the branch taken by the remote node",
+			givenContextData:     map[string]interface{}{"key": "value"},
+			givenNode:            remoteIsPresent,
+			expectedComputeState: NewContinueOnBranchComputeState(true),
+		},
+		{
+			name:                 "Should Abort on unknown remote node",
+			givenNode:            remoteUnknownNode,
+			expectedComputeState: NewAbortComputeState(errors.New("can't compute remote node 'unknownNode': can't find node 'unknownNode'")),
+		},
+		{
+			name:                 "Should Abort on unencodable context value",
+			givenContextData:     map[string]interface{}{"key": func() {}},
+			givenNode:            remoteWriteAction,
+			expectedComputeState: NewAbortComputeState(errors.New("can't encode context value of 'key': json: unsupported type: func()")),
+		},
+	}
+	RunTestOnNode(t, tc)
+}
+
+func Test_ComputeServer_Compute(t *testing.T) {
+	writeAction, _ := NewActionNode("writeAction", func(c *Context) error {
+		c.Store("written", "value")
+		c.Delete("deleted")
+		return nil
+	})
+	server, _ := NewComputeServer(writeAction)
+
+	response, err := server.Compute(context.Background(), &ComputeRequest{
+		Node: "writeAction",
+		Data: map[string][]byte{
+			"kept":    []byte(`"value"`),
+			"deleted": []byte(`"value"`),
+		},
+	})
+	expectedResponse := &ComputeResponse{
+		State:   ContinueState,
+		Stored:  map[string][]byte{"written": []byte(`"value"`)},
+		Deleted: []string{"deleted"},
+	}
+
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+	if !cmp.Equal(response, expectedResponse) {
+		t.Errorf("response - got: %+v, want: %+v", response, expectedResponse)
+	}
+}
+
+func Test_RemoteNode_Compute_goContext(t *testing.T) {
+	traceAction, _ := NewActionNode("traceAction", func(c *Context) error {
+		c.Store("trace", c.GoContext().Value(traceKey{}))
+		return nil
+	})
+	server, _ := NewComputeServer(traceAction)
+	remoteTraceAction, _ := NewRemoteNode("traceAction", server, false)
+
+	ns := NewNodeSystem()
+	ns.AddNode(remoteTraceAction)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	result := eng.ComputeWithContext(ctx, map[string]interface{}{})
+	if result.Data["trace"] != "trace-1" {
+		t.Errorf("got: %+v, want: %+v", result.Data["trace"], "trace-1")
+	}
+}
+
+func Test_RemoteNode_Compute_readData(t *testing.T) {
+	readAction, _ := NewActionNode("readAction", func(c *Context) error {
+		read := make(map[string]interface{}, len(c.Data))
+		for key, value := range c.Data {
+			read[key] = value
+		}
+		c.Store("read", read)
+		return nil
+	})
+	server, _ := NewComputeServer(readAction)
+	remoteReadAction, _ := NewRemoteNode("readAction", server, false)
+	c := NewContext(map[string]interface{}{"amount": 21.0, "draft": true})
+	c.ConfigureStore(NewMemoryContextStore())
+	c.StoreExternal("document", "content")
+	buffer := c.startStaging()
+	c.Store("total", 42.0)
+	c.Delete("draft")
+
+	remoteReadAction.Compute(c)
+	c.commitStaging(buffer)
+
+	expected := map[string]interface{}{"amount": 21.0, "document": "content", "total": 42.0}
+	if !cmp.Equal(c.Data["read"], expected) {
+		t.Errorf("got: %+v, want: %+v", c.Data["read"], expected)
+	}
+}
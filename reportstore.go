@@ -0,0 +1,318 @@
+package hoff
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoredReport is the report of an ended computation kept in a ReportStore.
+type StoredReport struct {
+	Workflow string
+	// Tenant is the tenant of the computation (see SubmitOptions.Tenant)
+	Tenant  string
+	Started time.Time
+	Ended   time.Time
+	State   HandleState
+	Report  ComputationReport
+}
+
+// ReportQuery select the stored reports, an empty field select all the reports.
+type ReportQuery struct {
+	// ID select the report of a computation
+	ID       string
+	Workflow string
+	Tenant   string
+	// From and To select the reports of the computations started in [From, To)
+	From time.Time
+	To   time.Time
+	// States select the reports by final state
+	States []HandleState
+	// Limit is the maximum number of reports, 0 for no limit
+	Limit int
+}
+
+// ReportStore persist the reports of the ended computations to query them later.
+type ReportStore interface {
+	// Save store the report of an ended computation, replacing the report of a resumed computation.
+	Save(report StoredReport) error
+	// Query give the reports matching the query, the last started first.
+	Query(query ReportQuery) ([]StoredReport, error)
+}
+
+// ConfigureReportStore add a store to save the report of each ended computation under a workflow name.
+func (e *Engine) ConfigureReportStore(store ReportStore, workflow string) {
+	e.reportStore = store
+	e.workflow = workflow
+}
+
+// saveReport save the report of an ended computation.
+func (e *Engine) saveReport(result ComputationResult, start, end time.Time) error {
+	report, err := newEncodableComputationReport(result)
+	if err != nil {
+		return err
+	}
+	return e.reportStore.Save(StoredReport{
+		Workflow: e.workflow,
+		Tenant:   result.Tenant,
+		Started:  start,
+		Ended:    end,
+		State:    resultHandleState(result),
+		Report:   report,
+	})
+}
+
+// newEncodableComputationReport create a report of a computation result,
+// with a context value who can't be encoded in JSON as its string representation.
+func newEncodableComputationReport(result ComputationResult) (ComputationReport, error) {
+	withEncodableData := result
+	record := newComputationRecord(result)
+	withEncodableData.Data = record.Data
+	withEncodableData.Outputs = record.Outputs
+	return NewComputationReport(withEncodableData)
+}
+
+// matches tell if a report match the query, without the limit.
+func (q ReportQuery) matches(report StoredReport) bool {
+	if q.ID != "" && report.Report.ID != q.ID {
+		return false
+	}
+	if q.Workflow != "" && report.Workflow != q.Workflow {
+		return false
+	}
+	if q.Tenant != "" && report.Tenant != q.Tenant {
+		return false
+	}
+	if !q.From.IsZero() && report.Started.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !report.Started.Before(q.To) {
+		return false
+	}
+	if len(q.States) == 0 {
+		return true
+	}
+	for _, state := range q.States {
+		if report.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryReportStore is a ReportStore keeping the reports in memory, mainly for testing.
+type MemoryReportStore struct {
+	mu      sync.Mutex
+	reports []StoredReport
+}
+
+// NewMemoryReportStore create an empty in memory report store.
+func NewMemoryReportStore() *MemoryReportStore {
+	return &MemoryReportStore{}
+}
+
+// Save store the report of an ended computation, replacing the report of a resumed computation.
+func (s *MemoryReportStore) Save(report StoredReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for index, storedReport := range s.reports {
+		if storedReport.Report.ID == report.Report.ID {
+			report.Started = storedReport.Started
+			s.reports[index] = report
+			return nil
+		}
+	}
+	s.reports = append(s.reports, report)
+	return nil
+}
+
+// Query give the reports matching the query, the last started first.
+func (s *MemoryReportStore) Query(query ReportQuery) ([]StoredReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reports := make([]StoredReport, 0)
+	for _, report := range s.reports {
+		if query.matches(report) {
+			reports = append(reports, report)
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Started.After(reports[j].Started)
+	})
+	if query.Limit > 0 && len(reports) > query.Limit {
+		reports = reports[:query.Limit]
+	}
+	return reports, nil
+}
+
+// SQLDialect define the SQL specificities of a database used by a SQLReportStore.
+type SQLDialect struct {
+	// Placeholder give the placeholder of the n-th (from 1) query argument
+	Placeholder func(n int) string
+	// BlobType is the column type of binary data
+	BlobType string
+}
+
+var (
+	// SQLiteDialect is the SQLDialect of SQLite.
+	SQLiteDialect = SQLDialect{
+		Placeholder: func(int) string { return "?" },
+		BlobType:    "BLOB",
+	}
+	// PostgresDialect is the SQLDialect of PostgreSQL.
+	PostgresDialect = SQLDialect{
+		Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+		BlobType:    "BYTEA",
+	}
+)
+
+// SQLReportStore is a ReportStore keeping the reports in a database table through database/sql,
+// the report itself is stored as a ComputationReport message of proto/flow.proto.
+// The database driver is chosen by the caller.
+type SQLReportStore struct {
+	db      *sql.DB
+	dialect SQLDialect
+	table   string
+}
+
+// NewSQLReportStore create a report store on a database table, see CreateTable to create it.
+func NewSQLReportStore(db *sql.DB, dialect SQLDialect, table string) (*SQLReportStore, error) {
+	if db == nil {
+		return nil, errors.New("can't create sql report store without database")
+	}
+	if dialect.Placeholder == nil || dialect.BlobType == "" {
+		return nil, errors.New("can't create sql report store without dialect")
+	}
+	if !isSQLIdentifier(table) {
+		return nil, fmt.Errorf("can't create sql report store with invalid table name: %v", table)
+	}
+	return &SQLReportStore{db: db, dialect: dialect, table: table}, nil
+}
+
+// CreateTable create the table of the reports and its indexes if missing.
+// A table created before the tenants need a tenant column:
+// ALTER TABLE reports ADD COLUMN tenant VARCHAR(255) NOT NULL DEFAULT ”.
+func (s *SQLReportStore) CreateTable() error {
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (id VARCHAR(64) PRIMARY KEY, workflow VARCHAR(255) NOT NULL, tenant VARCHAR(255) NOT NULL DEFAULT '', started BIGINT NOT NULL, ended BIGINT NOT NULL, state VARCHAR(32) NOT NULL, report %v NOT NULL)", s.table, s.dialect.BlobType),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %v_workflow_started ON %v (workflow, started)", s.table, s.table),
+	}
+	for _, statement := range statements {
+		if _, err := s.db.Exec(statement); err != nil {
+			return fmt.Errorf("can't create report table: %w", err)
+		}
+	}
+	return nil
+}
+
+// Save store the report of an ended computation, replacing the report of a resumed computation.
+func (s *SQLReportStore) Save(report StoredReport) error {
+	encodedReport, err := report.Report.MarshalProto()
+	if err != nil {
+		return err
+	}
+	statement := fmt.Sprintf("INSERT INTO %v (id, workflow, tenant, started, ended, state, report) VALUES (%v, %v, %v, %v, %v, %v, %v)"+
+		" ON CONFLICT (id) DO UPDATE SET ended = excluded.ended, state = excluded.state, report = excluded.report",
+		s.table, s.dialect.Placeholder(1), s.dialect.Placeholder(2), s.dialect.Placeholder(3),
+		s.dialect.Placeholder(4), s.dialect.Placeholder(5), s.dialect.Placeholder(6), s.dialect.Placeholder(7))
+	_, err = s.db.Exec(statement, report.Report.ID, report.Workflow, report.Tenant, report.Started.UnixNano(), report.Ended.UnixNano(), string(report.State), encodedReport)
+	if err != nil {
+		return fmt.Errorf("can't save report '%v': %w", report.Report.ID, err)
+	}
+	return nil
+}
+
+// Query give the reports matching the query, the last started first.
+func (s *SQLReportStore) Query(query ReportQuery) ([]StoredReport, error) {
+	statement, args := s.selectStatement(query)
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]StoredReport, 0)
+	for rows.Next() {
+		var report StoredReport
+		var started, ended int64
+		var state string
+		var encodedReport []byte
+		err = rows.Scan(&report.Workflow, &report.Tenant, &started, &ended, &state, &encodedReport)
+		if err != nil {
+			return nil, fmt.Errorf("can't read report: %w", err)
+		}
+		err = report.Report.UnmarshalProto(encodedReport)
+		if err != nil {
+			return nil, err
+		}
+		report.Started = time.Unix(0, started)
+		report.Ended = time.Unix(0, ended)
+		report.State = HandleState(state)
+		reports = append(reports, report)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't read reports: %w", err)
+	}
+	return reports, nil
+}
+
+// selectStatement give the select statement of a query and its arguments.
+func (s *SQLReportStore) selectStatement(query ReportQuery) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	condition := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, s.dialect.Placeholder(len(args))))
+	}
+	if query.ID != "" {
+		condition("id = %v", query.ID)
+	}
+	if query.Workflow != "" {
+		condition("workflow = %v", query.Workflow)
+	}
+	if query.Tenant != "" {
+		condition("tenant = %v", query.Tenant)
+	}
+	if !query.From.IsZero() {
+		condition("started >= %v", query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		condition("started < %v", query.To.UnixNano())
+	}
+	if len(query.States) > 0 {
+		placeholders := make([]string, 0, len(query.States))
+		for _, state := range query.States {
+			args = append(args, string(state))
+			placeholders = append(placeholders, s.dialect.Placeholder(len(args)))
+		}
+		conditions = append(conditions, fmt.Sprintf("state IN (%v)", strings.Join(placeholders, ", ")))
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "SELECT workflow, tenant, started, ended, state, report FROM %v", s.table)
+	if len(conditions) > 0 {
+		fmt.Fprintf(&builder, " WHERE %v", strings.Join(conditions, " AND "))
+	}
+	builder.WriteString(" ORDER BY started DESC")
+	if query.Limit > 0 {
+		fmt.Fprintf(&builder, " LIMIT %d", query.Limit)
+	}
+	return builder.String(), args
+}
+
+// isSQLIdentifier tell if a name is a plain SQL identifier.
+func isSQLIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for index, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (index > 0 && r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package hoff
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_MemoryReportStore_Query(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := StoredReport{Workflow: "billing", Started: start, State: HandleSucceeded, Report: ComputationReport{ID: "1"}}
+	second := StoredReport{Workflow: "billing", Tenant: "team-a", Started: start.Add(time.Hour), State: HandleFailed, Report: ComputationReport{ID: "2"}}
+	third := StoredReport{Workflow: "shipping", Started: start.Add(2 * time.Hour), State: HandleSucceeded, Report: ComputationReport{ID: "3"}}
+
+	store := NewMemoryReportStore()
+	store.Save(first)
+	store.Save(second)
+	store.Save(third)
+
+	testCases := []struct {
+		name            string
+		givenQuery      ReportQuery
+		expectedReports []StoredReport
+	}{
+		{
+			name:            "Can query all the reports",
+			givenQuery:      ReportQuery{},
+			expectedReports: []StoredReport{third, second, first},
+		},
+		{
+			name:            "Can query by workflow",
+			givenQuery:      ReportQuery{Workflow: "billing"},
+			expectedReports: []StoredReport{second, first},
+		},
+		{
+			name:            "Can query by tenant",
+			givenQuery:      ReportQuery{Tenant: "team-a"},
+			expectedReports: []StoredReport{second},
+		},
+		{
+			name:            "Can query by computation ID",
+			givenQuery:      ReportQuery{ID: "2"},
+			expectedReports: []StoredReport{second},
+		},
+		{
+			name:            "Can query by time range",
+			givenQuery:      ReportQuery{From: start.Add(time.Hour), To: start.Add(2 * time.Hour)},
+			expectedReports: []StoredReport{second},
+		},
+		{
+			name:            "Can query by states",
+			givenQuery:      ReportQuery{States: []HandleState{HandleSucceeded}},
+			expectedReports: []StoredReport{third, first},
+		},
+		{
+			name:            "Can query with a limit",
+			givenQuery:      ReportQuery{Limit: 1},
+			expectedReports: []StoredReport{third},
+		},
+		{
+			name:            "Can query without matching report",
+			givenQuery:      ReportQuery{Workflow: "billing", States: []HandleState{HandleCanceled}},
+			expectedReports: []StoredReport{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			reports, err := store.Query(testCase.givenQuery)
+
+			if err != nil {
+				t.Errorf("error - got: %+v, want: %+v", err, nil)
+			}
+			if !cmp.Equal(reports, testCase.expectedReports) {
+				t.Errorf("got: %+v, want: %+v", reports, testCase.expectedReports)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureReportStore(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.ActivateInPlace()
+
+	store := NewMemoryReportStore()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureReportStore(store, "approvals")
+
+	result := eng.Compute(map[string]interface{}{"channel": make(chan int)})
+	reports, _ := store.Query(ReportQuery{Workflow: "approvals", States: []HandleState{HandlePaused}})
+	if len(reports) != 1 || reports[0].Report.ID != result.ID {
+		t.Errorf("paused reports - got: %+v, want: report of %+v", reports, result.ID)
+	}
+
+	eng.Deliver(result.PausedTokens()[0], "approved")
+	reports, _ = store.Query(ReportQuery{})
+	if len(reports) != 1 || reports[0].State != HandleSucceeded {
+		t.Errorf("resumed reports - got: %+v, want: a succeeded report", reports)
+	}
+	if string(reports[0].Report.Data["approval_payload"]) != `"approved"` {
+		t.Errorf("resumed data - got: %+v, want: %+v", reports[0].Report.Data, `"approved"`)
+	}
+}
+
+func Test_NewSQLReportStore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenDB       *sql.DB
+		givenDialect  SQLDialect
+		givenTable    string
+		expectedError error
+	}{
+		{
+			name:          "Can't create a store without database",
+			givenDialect:  SQLiteDialect,
+			givenTable:    "reports",
+			expectedError: errors.New("can't create sql report store without database"),
+		},
+		{
+			name:          "Can't create a store without dialect",
+			givenDB:       &sql.DB{},
+			givenTable:    "reports",
+			expectedError: errors.New("can't create sql report store without dialect"),
+		},
+		{
+			name:          "Can't create a store with an invalid table name",
+			givenDB:       &sql.DB{},
+			givenDialect:  PostgresDialect,
+			givenTable:    "reports; DROP TABLE reports",
+			expectedError: errors.New("can't create sql report store with invalid table name: reports; DROP TABLE reports"),
+		},
+		{
+			name:         "Can create a store",
+			givenDB:      &sql.DB{},
+			givenDialect: PostgresDialect,
+			givenTable:   "flow_reports",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewSQLReportStore(testCase.givenDB, testCase.givenDialect, testCase.givenTable)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_SQLReportStore_selectStatement(t *testing.T) {
+	start := time.Unix(0, 100)
+	testCases := []struct {
+		name              string
+		givenDialect      SQLDialect
+		givenQuery        ReportQuery
+		expectedStatement string
+		expectedArgs      []interface{}
+	}{
+		{
+			name:              "Can select all the reports",
+			givenDialect:      SQLiteDialect,
+			expectedStatement: "SELECT workflow, tenant, started, ended, state, report FROM reports ORDER BY started DESC",
+			expectedArgs:      []interface{}{},
+		},
+		{
+			name:              "Can select reports with SQLite",
+			givenDialect:      SQLiteDialect,
+			givenQuery:        ReportQuery{Workflow: "billing", From: start, To: start.Add(1), States: []HandleState{HandleFailed, HandleCanceled}, Limit: 10},
+			expectedStatement: "SELECT workflow, tenant, started, ended, state, report FROM reports WHERE workflow = ? AND started >= ? AND started < ? AND state IN (?, ?) ORDER BY started DESC LIMIT 10",
+			expectedArgs:      []interface{}{"billing", int64(100), int64(101), "Failed", "Canceled"},
+		},
+		{
+			name:              "Can select reports with PostgreSQL",
+			givenDialect:      PostgresDialect,
+			givenQuery:        ReportQuery{Workflow: "billing", Tenant: "team-a", States: []HandleState{HandleFailed}},
+			expectedStatement: "SELECT workflow, tenant, started, ended, state, report FROM reports WHERE workflow = $1 AND tenant = $2 AND state IN ($3) ORDER BY started DESC",
+			expectedArgs:      []interface{}{"billing", "team-a", "Failed"},
+		},
+		{
+			name:              "Can select the report of a computation",
+			givenDialect:      PostgresDialect,
+			givenQuery:        ReportQuery{ID: "1", Limit: 1},
+			expectedStatement: "SELECT workflow, tenant, started, ended, state, report FROM reports WHERE id = $1 ORDER BY started DESC LIMIT 1",
+			expectedArgs:      []interface{}{"1"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store, _ := NewSQLReportStore(&sql.DB{}, testCase.givenDialect, "reports")
+			statement, args := store.selectStatement(testCase.givenQuery)
+
+			if statement != testCase.expectedStatement {
+				t.Errorf("statement - got: %+v, want: %+v", statement, testCase.expectedStatement)
+			}
+			if !cmp.Equal(args, testCase.expectedArgs) {
+				t.Errorf("args - got: %+v, want: %+v", args, testCase.expectedArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// RerunOptions hold the options of a rerun of a computation.
+type RerunOptions struct {
+	// Data replace or add values in the recorded context before the rerun, e.g. to fix the cause of the failure
+	Data map[string]interface{}
+	// Nodes are the names of the aborted nodes to compute again, all the aborted nodes if empty
+	Nodes []string
+}
+
+// Rerun compute again the aborted nodes of a computation from its report, e.g. after a transient failure,
+// with the recorded context of the computation, and their following nodes.
+// The other nodes are not computed again, and keep their compute state in the merged result
+// (the computation keep its ID, and the context values are restored from JSON).
+func (e *Engine) Rerun(report ComputationReport, options RerunOptions) ComputationResult {
+	if e.system == nil {
+		return ComputationResult{ID: report.ID, Error: errors.New("need a configured node system")}
+	}
+	checkpoint := Checkpoint{Report: report}
+	if e.checkpointStore != nil {
+		stored, err := e.checkpointStore.Load(report.ID)
+		if err != nil && !errors.Is(err, ErrCheckpointNotFound) {
+			return ComputationResult{ID: report.ID, Error: fmt.Errorf("can't rerun computation '%v': %w", report.ID, err)}
+		}
+		checkpoint.Version = stored.Version
+	}
+	cp, err := e.restoreComputation(checkpoint)
+	if err != nil {
+		return ComputationResult{ID: report.ID, Error: fmt.Errorf("can't rerun computation '%v': %w", report.ID, err)}
+	}
+	for key, value := range options.Data {
+		cp.Context.Data[key] = value
+	}
+	rerunNodes, err := cp.abortedNodes(options.Nodes)
+	if err != nil {
+		return newComputationResult(cp, fmt.Errorf("can't rerun computation '%v': %w", report.ID, err))
+	}
+	cp.prepareRerun(rerunNodes, report.States)
+
+	unlock, err := e.lockComputation(cp)
+	if err != nil {
+		return newComputationResult(cp, err)
+	}
+	defer unlock()
+	err = e.startComputation(cp)
+	if err != nil {
+		return newComputationResult(cp, err)
+	}
+	defer e.endComputation(cp)
+
+	start := time.Now()
+	err = cp.Continue()
+	return e.endResult(cp, err, start)
+}
+
+// abortedNodes give the aborted nodes of the computation, or the ones named.
+func (cp *Computation) abortedNodes(names []string) ([]Node, error) {
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
+	}
+	nodes := make([]Node, 0)
+	for _, node := range cp.System.nodes {
+		state, found := cp.stateOf(node)
+		if !found || state.Value != AbortState || (len(names) > 0 && !selected[fmt.Sprint(node)]) {
+			continue
+		}
+		delete(selected, fmt.Sprint(node))
+		nodes = append(nodes, node)
+	}
+	for _, name := range names {
+		if selected[name] {
+			return nil, fmt.Errorf("can't find aborted node: %v", name)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("can't find aborted node")
+	}
+	return nodes, nil
+}
+
+// prepareRerun limit the computation to the nodes to compute again and their following nodes,
+// and keep the compute state, and the duration, of the other nodes.
+func (cp *Computation) prepareRerun(nodes []Node, states []NodeStateReport) {
+	cp.entryNodes = nodes
+	cp.scope = make(map[string]bool)
+	for _, node := range nodes {
+		for id := range followingNodesOf(cp.System, node) {
+			cp.scope[id] = true
+		}
+	}
+	durations := make(map[string]time.Duration, len(states))
+	for _, state := range states {
+		durations[state.Node] = state.Duration
+	}
+	cp.durations = make(map[string]time.Duration)
+	for _, node := range cp.System.nodes {
+		id := cp.System.nodeID(node)
+		if _, found := cp.stateOf(node); !found {
+			continue
+		}
+		if cp.scope[id] {
+			delete(cp.Report, cp.System.nodeMapKey(node))
+		} else if duration := durations[fmt.Sprint(node)]; duration > 0 {
+			cp.durations[id] = duration
+		}
+	}
+	completedNodes := int32(0)
+	for _, state := range cp.Report {
+		if state.Value != PauseState {
+			completedNodes++
+		}
+	}
+	atomic.StoreInt32(&cp.completedNodes, completedNodes)
+}
@@ -0,0 +1,98 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_Engine_Rerun(t *testing.T) {
+	fetches := 0
+	fetch, _ := NewActionNode("fetch", func(c *Context) error {
+		fetches++
+		c.Store("fetched", true)
+		return nil
+	})
+	send, _ := NewActionNode("send", func(c *Context) error {
+		if _, found := c.Read("endpoint"); !found {
+			return errors.New("missing endpoint")
+		}
+		c.Store("sent", true)
+		return nil
+	})
+	archive, _ := NewActionNode("archive", func(c *Context) error {
+		c.Store("archived", true)
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(fetch)
+	ns.AddNode(send)
+	ns.AddNode(archive)
+	ns.AddLink(fetch, send)
+	ns.AddLink(send, archive)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	failed := eng.Compute(map[string]interface{}{})
+	report, _ := NewComputationReport(failed)
+
+	result := eng.Rerun(report, RerunOptions{Data: map[string]interface{}{"endpoint": "https://example.com"}})
+	expectedResult := ComputationResult{
+		ID:   failed.ID,
+		Data: map[string]interface{}{"fetched": true, "endpoint": "https://example.com", "sent": true, "archived": true},
+		Report: map[Node]ComputeState{
+			fetch:   NewContinueComputeState(),
+			send:    NewContinueComputeState(),
+			archive: NewContinueComputeState(),
+		},
+		Success: true,
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator, cmpopts.IgnoreFields(ComputationResult{}, "Fingerprint", "Durations", "Seed")) {
+		t.Errorf("got: %+v, want: %+v", result, expectedResult)
+	}
+	if fetches != 1 {
+		t.Errorf("fetches - got: %+v, want: %+v", fetches, 1)
+	}
+	if _, found := result.Durations[fetch]; !found {
+		t.Errorf("kept duration - got: %+v, want: a duration of %v", result.Durations, fetch)
+	}
+
+	successReport, _ := NewComputationReport(result)
+	testCases := []struct {
+		name          string
+		givenReport   ComputationReport
+		givenOptions  RerunOptions
+		expectedError error
+	}{
+		{
+			name:          "Can't rerun a computation without aborted node",
+			givenReport:   successReport,
+			expectedError: fmt.Errorf("can't rerun computation '%v': can't find aborted node", failed.ID),
+		},
+		{
+			name:          "Can't rerun an unknown aborted node",
+			givenReport:   report,
+			givenOptions:  RerunOptions{Nodes: []string{"archive"}},
+			expectedError: fmt.Errorf("can't rerun computation '%v': can't find aborted node: archive", failed.ID),
+		},
+		{
+			name:          "Can't rerun a computation of another node system",
+			givenReport:   ComputationReport{ID: "other", Fingerprint: "other"},
+			expectedError: errors.New("can't rerun computation 'other': can't restore checkpoint 'other' of another node system"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := eng.Rerun(testCase.givenReport, testCase.givenOptions)
+
+			if errorMessage(result.Error) != errorMessage(testCase.expectedError) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.expectedError)
+			}
+		})
+	}
+}
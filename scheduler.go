@@ -0,0 +1,203 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// OverlapPolicy define how a Scheduler handle a trigger
+// while the previous computation of the same schedule is still running.
+type OverlapPolicy string
+
+const (
+	// SkipOverlap will ignore the trigger.
+	SkipOverlap OverlapPolicy = "skip"
+	// QueueOverlap will run the computation once the running ones are done.
+	QueueOverlap = "queue"
+	// ParallelOverlap will run the computation alongside the running ones.
+	ParallelOverlap = "parallel"
+)
+
+// Scheduler trigger computations of an engine based on cron expressions.
+type Scheduler struct {
+	engine        *Engine
+	resultHandler func(ComputationResult)
+
+	mu       sync.Mutex
+	jobs     []*scheduledJob
+	started  bool
+	stopping bool
+	stop     chan struct{}
+	running  sync.WaitGroup
+	timers   sync.WaitGroup
+}
+
+type scheduledJob struct {
+	schedule       *cronSchedule
+	contextFactory func() map[string]interface{}
+	policy         OverlapPolicy
+
+	mu      sync.Mutex
+	running int
+	queued  int
+}
+
+// NewScheduler create a scheduler who trigger computations on a configured engine.
+func NewScheduler(engine *Engine) (*Scheduler, error) {
+	if engine == nil {
+		return nil, errors.New("can't create scheduler without engine")
+	}
+	return &Scheduler{
+		engine: engine,
+		jobs:   make([]*scheduledJob, 0),
+		stop:   make(chan struct{}),
+	}, nil
+}
+
+// ConfigureResultHandler add a function to receive the result of each triggered computation.
+func (s *Scheduler) ConfigureResultHandler(handler func(ComputationResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resultHandler = handler
+}
+
+// Schedule add a computation to trigger on a cron expression
+// (minute, hour, day of month, month, day of week) with an initial context
+// created by the context factory for each computation.
+func (s *Scheduler) Schedule(spec string, contextFactory func() map[string]interface{}, policy OverlapPolicy) error {
+	schedule, err := parseCronSchedule(spec)
+	if err != nil {
+		return err
+	}
+	if contextFactory == nil {
+		return errors.New("can't schedule a computation without context factory")
+	}
+	switch policy {
+	case SkipOverlap, QueueOverlap, ParallelOverlap:
+	default:
+		return errors.New("can't schedule a computation with unknown overlap policy")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopping {
+		return errors.New("can't schedule a computation, scheduler is shut down")
+	}
+	job := &scheduledJob{
+		schedule:       schedule,
+		contextFactory: contextFactory,
+		policy:         policy,
+	}
+	s.jobs = append(s.jobs, job)
+	if s.started {
+		s.startJob(job)
+	}
+	return nil
+}
+
+// Start trigger the scheduled computations until the scheduler is shut down.
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopping {
+		return errors.New("can't start scheduler, scheduler is shut down")
+	}
+	if s.started {
+		return nil
+	}
+	s.started = true
+	for _, job := range s.jobs {
+		s.startJob(job)
+	}
+	return nil
+}
+
+// Shutdown stop triggering computations, drop the queued ones,
+// and wait for the running ones within the context deadline.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopping {
+		s.stopping = true
+		close(s.stop)
+	}
+	s.mu.Unlock()
+	s.timers.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) startJob(job *scheduledJob) {
+	s.timers.Add(1)
+	go func() {
+		defer s.timers.Done()
+		for {
+			now := time.Now()
+			next := job.schedule.next(now)
+			if next.IsZero() {
+				return
+			}
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-s.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+				s.trigger(job)
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) trigger(job *scheduledJob) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.running > 0 {
+		switch job.policy {
+		case SkipOverlap:
+			return
+		case QueueOverlap:
+			job.queued++
+			return
+		}
+	}
+	job.running++
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		for {
+			result := s.engine.Compute(job.contextFactory())
+
+			s.mu.Lock()
+			handler := s.resultHandler
+			stopping := s.stopping
+			s.mu.Unlock()
+			if handler != nil {
+				handler(result)
+			}
+
+			job.mu.Lock()
+			if job.queued > 0 && !stopping {
+				job.queued--
+				job.mu.Unlock()
+				continue
+			}
+			job.queued = 0
+			job.running--
+			job.mu.Unlock()
+			return
+		}
+	}()
+}
@@ -0,0 +1,147 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewScheduler(t *testing.T) {
+	_, err := NewScheduler(nil)
+	expectedError := errors.New("can't create scheduler without engine")
+
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Scheduler_Schedule(t *testing.T) {
+	factory := func() map[string]interface{} { return make(map[string]interface{}) }
+
+	testCases := []struct {
+		name          string
+		givenSpec     string
+		givenFactory  func() map[string]interface{}
+		givenPolicy   OverlapPolicy
+		expectedError error
+	}{
+		{
+			name:         "Can schedule a computation",
+			givenSpec:    "@hourly",
+			givenFactory: factory,
+			givenPolicy:  SkipOverlap,
+		},
+		{
+			name:          "Can't schedule a computation with invalid cron expression",
+			givenSpec:     "@never",
+			givenFactory:  factory,
+			givenPolicy:   SkipOverlap,
+			expectedError: errors.New("can't parse cron expression '@never': need 5 fields"),
+		},
+		{
+			name:          "Can't schedule a computation without context factory",
+			givenSpec:     "@hourly",
+			givenPolicy:   SkipOverlap,
+			expectedError: errors.New("can't schedule a computation without context factory"),
+		},
+		{
+			name:          "Can't schedule a computation with unknown overlap policy",
+			givenSpec:     "@hourly",
+			givenFactory:  factory,
+			givenPolicy:   "unknown",
+			expectedError: errors.New("can't schedule a computation with unknown overlap policy"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scheduler, _ := NewScheduler(NewEngine(SequentialComputation))
+			err := scheduler.Schedule(testCase.givenSpec, testCase.givenFactory, testCase.givenPolicy)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Scheduler_trigger(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		givenPolicy          OverlapPolicy
+		expectedComputations int
+	}{
+		{
+			name:                 "Skip overlapping triggers",
+			givenPolicy:          SkipOverlap,
+			expectedComputations: 1,
+		},
+		{
+			name:                 "Queue overlapping triggers",
+			givenPolicy:          QueueOverlap,
+			expectedComputations: 3,
+		},
+		{
+			name:                 "Run overlapping triggers in parallel",
+			givenPolicy:          ParallelOverlap,
+			expectedComputations: 3,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			started := make(chan struct{}, 3)
+			release := make(chan struct{})
+			blockingAction, _ := NewActionNode("blockingAction", func(*Context) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(blockingAction)
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+
+			var mu sync.Mutex
+			computations := 0
+			scheduler, _ := NewScheduler(eng)
+			scheduler.ConfigureResultHandler(func(ComputationResult) {
+				mu.Lock()
+				computations++
+				mu.Unlock()
+			})
+			scheduler.Schedule("@hourly", func() map[string]interface{} { return make(map[string]interface{}) }, testCase.givenPolicy)
+			job := scheduler.jobs[0]
+
+			scheduler.trigger(job)
+			<-started
+			scheduler.trigger(job)
+			scheduler.trigger(job)
+			close(release)
+			scheduler.running.Wait()
+
+			if computations != testCase.expectedComputations {
+				t.Errorf("computations - got: %+v, want: %+v", computations, testCase.expectedComputations)
+			}
+		})
+	}
+}
+
+func Test_Scheduler_Shutdown(t *testing.T) {
+	scheduler, _ := NewScheduler(NewEngine(SequentialComputation))
+	scheduler.Schedule("@hourly", func() map[string]interface{} { return make(map[string]interface{}) }, SkipOverlap)
+	scheduler.Start()
+
+	err := scheduler.Shutdown(context.Background())
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+
+	err = scheduler.Schedule("@hourly", func() map[string]interface{} { return make(map[string]interface{}) }, SkipOverlap)
+	expectedError := errors.New("can't schedule a computation, scheduler is shut down")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -0,0 +1,73 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SchemaValidationNode is a type of Node who validate a context value against a JSON Schema,
+// and decide on the true branch if the value is valid, or on the false branch otherwise,
+// with the violations (see SchemaViolation) stored in a context key for the following nodes.
+// A string or bytes value is validated as a JSON document.
+type SchemaValidationNode struct {
+	name          string
+	schema        *JSONSchema
+	source        string
+	violationsKey string
+}
+
+func (n SchemaValidationNode) String() string {
+	return n.name
+}
+
+// Compute validate the context value, and store its violations if any.
+func (n *SchemaValidationNode) Compute(c *Context) ComputeState {
+	value, found := c.Read(n.source)
+	var violations []SchemaViolation
+	if !found {
+		violations = []SchemaViolation{{Message: fmt.Sprintf("missing key: %v", n.source)}}
+	} else {
+		if text, ok := value.(string); ok {
+			value = []byte(text)
+		}
+		violations = n.schema.Validate(value)
+	}
+	if len(violations) > 0 {
+		c.Store(n.violationsKey, violations)
+		return NewContinueOnBranchComputeState(false)
+	}
+	c.Delete(n.violationsKey)
+	return NewContinueOnBranchComputeState(true)
+}
+
+// DecideCapability is actived due to the fact that the validity of the value is a decision.
+func (n *SchemaValidationNode) DecideCapability() bool {
+	return true
+}
+
+// Schema give the JSON Schema of the node.
+func (n *SchemaValidationNode) Schema() *JSONSchema {
+	return n.schema
+}
+
+// RequiredKeys give the context key of the validated value.
+func (n *SchemaValidationNode) RequiredKeys() []string {
+	return []string{n.source}
+}
+
+// ProducedKeys give the context key of the violations.
+func (n *SchemaValidationNode) ProducedKeys() []string {
+	return []string{n.violationsKey}
+}
+
+// NewSchemaValidationNode create a SchemaValidationNode based on a name, a JSON Schema (see ParseJSONSchema),
+// the context key of the value to validate, and the context key of the violations.
+func NewSchemaValidationNode(name string, schema *JSONSchema, source, violationsKey string) (*SchemaValidationNode, error) {
+	if schema == nil {
+		return nil, errors.New("can't create schema validation node without schema")
+	}
+	if source == "" || violationsKey == "" {
+		return nil, errors.New("can't create schema validation node without source and violations keys")
+	}
+	return &SchemaValidationNode{name: name, schema: schema, source: source, violationsKey: violationsKey}, nil
+}
@@ -0,0 +1,100 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewSchemaValidationNode(t *testing.T) {
+	schema, _ := ParseJSONSchema([]byte(`{"type": "object"}`))
+
+	testCases := []struct {
+		name          string
+		givenSchema   *JSONSchema
+		givenSource   string
+		expectedError error
+	}{
+		{
+			name:        "Can create a schema validation node",
+			givenSchema: schema,
+			givenSource: "order",
+		},
+		{
+			name:          "Can't create a schema validation node without schema",
+			givenSource:   "order",
+			expectedError: errors.New("can't create schema validation node without schema"),
+		},
+		{
+			name:          "Can't create a schema validation node without source key",
+			givenSchema:   schema,
+			expectedError: errors.New("can't create schema validation node without source and violations keys"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewSchemaValidationNode("validate", testCase.givenSchema, testCase.givenSource, "violations")
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_SchemaValidationNode_Compute(t *testing.T) {
+	schema, _ := ParseJSONSchema([]byte(`{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`))
+
+	testCases := []struct {
+		name          string
+		givenData     map[string]interface{}
+		expectedState ComputeState
+		expectedData  map[string]interface{}
+	}{
+		{
+			name:          "Can decide on a valid value",
+			givenData:     map[string]interface{}{"order": map[string]interface{}{"id": 42}, "violations": []SchemaViolation{{Message: "previous"}}},
+			expectedState: NewContinueOnBranchComputeState(true),
+			expectedData:  map[string]interface{}{"order": map[string]interface{}{"id": 42}},
+		},
+		{
+			name:          "Can decide on a valid JSON document",
+			givenData:     map[string]interface{}{"order": `{"id": 42}`},
+			expectedState: NewContinueOnBranchComputeState(true),
+			expectedData:  map[string]interface{}{"order": `{"id": 42}`},
+		},
+		{
+			name:          "Can decide on an invalid value",
+			givenData:     map[string]interface{}{"order": map[string]interface{}{"id": "42"}},
+			expectedState: NewContinueOnBranchComputeState(false),
+			expectedData: map[string]interface{}{
+				"order":      map[string]interface{}{"id": "42"},
+				"violations": []SchemaViolation{{Path: "/id", Message: "expected integer, got string"}},
+			},
+		},
+		{
+			name:          "Can decide on a missing value",
+			givenData:     map[string]interface{}{},
+			expectedState: NewContinueOnBranchComputeState(false),
+			expectedData: map[string]interface{}{
+				"violations": []SchemaViolation{{Message: "missing key: order"}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, _ := NewSchemaValidationNode("validate", schema, "order", "violations")
+			c := NewContext(testCase.givenData)
+
+			state := node.Compute(c)
+
+			if !cmp.Equal(state, testCase.expectedState) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
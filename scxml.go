@@ -0,0 +1,104 @@
+package hoff
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+const (
+	scxmlNamespace = "http://www.w3.org/2005/07/scxml"
+	scxmlEndState  = "end"
+)
+
+type scxmlDocument struct {
+	XMLName xml.Name     `xml:"scxml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Version string       `xml:"version,attr"`
+	Initial string       `xml:"initial,attr,omitempty"`
+	States  []scxmlState `xml:"state"`
+	Final   scxmlFinal   `xml:"final"`
+}
+
+type scxmlState struct {
+	ID          string            `xml:"id,attr"`
+	Transitions []scxmlTransition `xml:"transition"`
+}
+
+type scxmlTransition struct {
+	Event  string `xml:"event,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type scxmlFinal struct {
+	ID string `xml:"id,attr"`
+}
+
+// ExportSCXML write the activated node system as a SCXML state machine.
+// Each node is a state with a transition on the 'continue' event (or on the 'true' and 'false'
+// events for a decision node) to its following nodes, the last nodes go to the 'end' final state.
+func (s *NodeSystem) ExportSCXML(w io.Writer) error {
+	if !s.activated {
+		return errors.New("can't export a node system if not activated")
+	}
+
+	initialStates := make([]string, 0, len(s.initialNodes))
+	for _, node := range s.initialNodes {
+		initialStates = append(initialStates, scxmlID(node))
+	}
+
+	document := scxmlDocument{
+		Xmlns:   scxmlNamespace,
+		Version: "1.0",
+		Initial: strings.Join(initialStates, " "),
+		States:  make([]scxmlState, 0, len(s.nodes)),
+		Final:   scxmlFinal{ID: scxmlEndState},
+	}
+	for _, node := range s.nodes {
+		state := scxmlState{ID: scxmlID(node)}
+		for _, branch := range nodeBranches(node) {
+			event := "continue"
+			if branch != nil {
+				event = fmt.Sprint(*branch)
+			}
+			followingNodes, _ := s.Follow(node, branch)
+			for _, followingNode := range followingNodes {
+				state.Transitions = append(state.Transitions, scxmlTransition{Event: event, Target: scxmlID(followingNode)})
+			}
+			if len(followingNodes) == 0 {
+				state.Transitions = append(state.Transitions, scxmlTransition{Event: event, Target: scxmlEndState})
+			}
+		}
+		document.States = append(document.States, state)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// scxmlID give a valid XML identifier based on the node name.
+func scxmlID(node Node) string {
+	id := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, fmt.Sprint(node))
+	if id == "" || !(unicode.IsLetter(rune(id[0])) || id[0] == '_') {
+		id = "_" + id
+	}
+	return id
+}
@@ -0,0 +1,50 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ExportSCXML(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("key is present", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	readKey, _ := NewActionNode("readKey", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(readKey)
+	ns.AddLinkOnBranch(keyIsPresent, readKey, true)
+
+	var buffer bytes.Buffer
+	err := ns.ExportSCXML(&buffer)
+	expectedError := errors.New("can't export a node system if not activated")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	ns.ActivateInPlace()
+	buffer.Reset()
+	err = ns.ExportSCXML(&buffer)
+	expectedSCXML := `<?xml version="1.0" encoding="UTF-8"?>
+<scxml xmlns="http://www.w3.org/2005/07/scxml" version="1.0" initial="key_is_present">
+  <state id="key_is_present">
+    <transition event="true" target="readKey"></transition>
+    <transition event="false" target="end"></transition>
+  </state>
+  <state id="readKey">
+    <transition event="continue" target="end"></transition>
+  </state>
+  <final id="end"></final>
+</scxml>
+`
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+	if buffer.String() != expectedSCXML {
+		t.Errorf("got: %v, want: %v", buffer.String(), expectedSCXML)
+	}
+}
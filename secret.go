@@ -0,0 +1,116 @@
+package hoff
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// redactedValue is the representation of a secret in reports, logs, and journals.
+const redactedValue = "[REDACTED]"
+
+// Cipher encrypt and decrypt the secrets of a context, e.g. through a KMS.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// Secret is the value kept in a context for a secret, only its encrypted form is kept.
+// It's redacted when printed or serialized as JSON (e.g. in the reports and logs),
+// and kept encrypted in the checkpoints (see Engine.ConfigureCheckpointStore).
+type Secret struct {
+	Ciphertext []byte
+}
+
+// String print a redacted version of a secret
+func (s Secret) String() string {
+	return redactedValue
+}
+
+// GoString print a redacted version of a secret
+func (s Secret) GoString() string {
+	return redactedValue
+}
+
+// MarshalJSON serialize a redacted version of a secret
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redactedValue + `"`), nil
+}
+
+// AESCipher is a Cipher based on AES-GCM with a static key.
+type AESCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipher create an AES-GCM cipher based on a key of 16, 24, or 32 bytes.
+func NewAESCipher(key []byte) (*AESCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("can't create AES cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("can't create AES cipher: %v", err)
+	}
+	return &AESCipher{aead: aead}, nil
+}
+
+// Encrypt encrypt a plaintext with a random nonce, put ahead of the ciphertext.
+func (c *AESCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypt a ciphertext with its nonce ahead.
+func (c *AESCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return nil, errors.New("can't decrypt a too short ciphertext")
+	}
+	nonceSize := c.aead.NonceSize()
+	return c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+// ConfigureCipher add a cipher to encrypt and decrypt the secrets of the context.
+func (c *Context) ConfigureCipher(cipher Cipher) {
+	c.cipher = cipher
+}
+
+// StoreSecret add a key and its secret value to the context,
+// only the encrypted value is kept in the context.
+func (c *Context) StoreSecret(key, value string) error {
+	if c.cipher == nil {
+		return fmt.Errorf("can't store secret '%v' without cipher", key)
+	}
+	ciphertext, err := c.cipher.Encrypt([]byte(value))
+	if err != nil {
+		return fmt.Errorf("can't encrypt secret '%v': %v", key, err)
+	}
+	c.Store(key, Secret{Ciphertext: ciphertext})
+	return nil
+}
+
+// ReadSecret get the decrypted value of a secret in the context by its key.
+func (c *Context) ReadSecret(key string) (string, error) {
+	value, ok := c.Read(key)
+	if !ok {
+		return "", fmt.Errorf("can't find secret '%v'", key)
+	}
+	secret, isSecret := value.(Secret)
+	if !isSecret {
+		return "", fmt.Errorf("can't read key '%v' as a secret", key)
+	}
+	if c.cipher == nil {
+		return "", fmt.Errorf("can't read secret '%v' without cipher", key)
+	}
+	plaintext, err := c.cipher.Decrypt(secret.Ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("can't decrypt secret '%v': %v", key, err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,158 @@
+package hoff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var secretTestKey = []byte("0123456789abcdef")
+
+func Test_NewAESCipher(t *testing.T) {
+	_, err := NewAESCipher([]byte("short"))
+
+	expectedError := errors.New("can't create AES cipher: crypto/aes: invalid key size 5")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Context_StoreSecret(t *testing.T) {
+	cipher, _ := NewAESCipher(secretTestKey)
+	c := NewContextWithoutData()
+	c.ConfigureCipher(cipher)
+
+	err := c.StoreSecret("token", "s3cr3t")
+	if err != nil {
+		t.Fatalf("error - got: %+v, want: %+v", err, nil)
+	}
+
+	secret, isSecret := c.Data["token"].(Secret)
+	if !isSecret || bytes.Contains(secret.Ciphertext, []byte("s3cr3t")) {
+		t.Errorf("stored - got: %+v, want: an encrypted secret", c.Data["token"])
+	}
+	value, err := c.ReadSecret("token")
+	if err != nil || value != "s3cr3t" {
+		t.Errorf("value - got: %+v (error: %+v), want: %+v", value, err, "s3cr3t")
+	}
+}
+
+func Test_Context_StoreSecret_without_cipher(t *testing.T) {
+	c := NewContextWithoutData()
+
+	err := c.StoreSecret("token", "s3cr3t")
+
+	expectedError := errors.New("can't store secret 'token' without cipher")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+	if c.HaveKey("token") {
+		t.Errorf("stored - got: %+v, want: no key", c.Data["token"])
+	}
+}
+
+func Test_Context_ReadSecret(t *testing.T) {
+	cipher, _ := NewAESCipher(secretTestKey)
+	anotherCipher, _ := NewAESCipher([]byte("fedcba9876543210"))
+
+	testCases := []struct {
+		name          string
+		givenCipher   Cipher
+		givenData     map[string]interface{}
+		expectedError error
+	}{
+		{
+			name:          "Can't read a missing secret",
+			givenCipher:   cipher,
+			givenData:     map[string]interface{}{},
+			expectedError: errors.New("can't find secret 'token'"),
+		},
+		{
+			name:          "Can't read a plain value as secret",
+			givenCipher:   cipher,
+			givenData:     map[string]interface{}{"token": "s3cr3t"},
+			expectedError: errors.New("can't read key 'token' as a secret"),
+		},
+		{
+			name:          "Can't read a secret without cipher",
+			givenData:     map[string]interface{}{"token": Secret{}},
+			expectedError: errors.New("can't read secret 'token' without cipher"),
+		},
+		{
+			name:          "Can't read a secret encrypted by another cipher",
+			givenCipher:   anotherCipher,
+			expectedError: errors.New("can't decrypt secret 'token': cipher: message authentication failed"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := NewContextWithoutData()
+			c.ConfigureCipher(cipher)
+			_ = c.StoreSecret("token", "s3cr3t")
+			if testCase.givenData != nil {
+				c = NewContextWithoutData()
+				for key, value := range testCase.givenData {
+					c.Store(key, value)
+				}
+			}
+			c.ConfigureCipher(testCase.givenCipher)
+
+			_, err := c.ReadSecret("token")
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Secret_redaction(t *testing.T) {
+	secret := Secret{Ciphertext: []byte("ciphertext")}
+
+	for _, printed := range []string{fmt.Sprint(secret), fmt.Sprintf("%+v", secret), fmt.Sprintf("%#v", secret)} {
+		if printed != "[REDACTED]" {
+			t.Errorf("printed - got: %+v, want: %+v", printed, "[REDACTED]")
+		}
+	}
+	serialized, _ := json.Marshal(map[string]interface{}{"token": secret})
+	if string(serialized) != `{"token":"[REDACTED]"}` {
+		t.Errorf("serialized - got: %+v, want: %+v", string(serialized), `{"token":"[REDACTED]"}`)
+	}
+}
+
+func Test_Engine_ConfigureCipher(t *testing.T) {
+	cipher, _ := NewAESCipher(secretTestKey)
+	var read string
+	storeToken, _ := NewActionNode("storeToken", func(c *Context) error {
+		return c.StoreSecret("token", "s3cr3t")
+	})
+	readToken, _ := NewActionNode("readToken", func(c *Context) error {
+		var err error
+		read, err = c.ReadSecret("token")
+		return err
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(storeToken)
+	ns.AddNode(readToken)
+	ns.AddLink(storeToken, readToken)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureCipher(cipher)
+	result := eng.Compute(map[string]interface{}{})
+
+	if result.IsAborted() || read != "s3cr3t" {
+		t.Errorf("got: %+v (read: %+v), want: a secret read", result, read)
+	}
+	var report bytes.Buffer
+	_ = RenderHTMLReport(&report, ns, result)
+	if strings.Contains(report.String(), "s3cr3t") || !strings.Contains(report.String(), "[REDACTED]") {
+		t.Errorf("report - got: %+v, want: a redacted secret", report.String())
+	}
+}
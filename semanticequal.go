@@ -0,0 +1,103 @@
+package hoff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// semanticNodeSystem is a representation of a node system independent of the node instances,
+// with the nodes identified by their type and name.
+type semanticNodeSystem struct {
+	Activated    bool
+	Nodes        []string
+	Links        []string
+	PortLinks    []string
+	InitialNodes []string
+	Trees        []string
+}
+
+// SemanticEqual tell if two node systems have the same structure, comparing the nodes by their type and name
+// instead of their instance, with their configuration (join modes and expressions, feature flags, tags, keys, ports),
+// their links (with metadata), and their activated structures (initial nodes, following and ancestors trees).
+func (s *NodeSystem) SemanticEqual(o *NodeSystem) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return cmp.Equal(newSemanticNodeSystem(s), newSemanticNodeSystem(o))
+}
+
+func newSemanticNodeSystem(s *NodeSystem) semanticNodeSystem {
+	semantic := semanticNodeSystem{
+		Activated:    s.activated,
+		Nodes:        make([]string, 0, len(s.nodes)),
+		Links:        make([]string, 0, len(s.links)),
+		PortLinks:    make([]string, 0, len(s.portLinks)),
+		InitialNodes: make([]string, 0, len(s.initialNodes)),
+		Trees:        make([]string, 0),
+	}
+	for _, node := range s.nodes {
+		expression, _ := s.JoinExpressionOfNode(node)
+		flag, _ := s.FlagOfNode(node)
+		required, produced := s.KeysOfNode(node)
+		inputs, outputs := s.PortsOfNode(node)
+		description := fmt.Sprintf("%v join:%v expression:%v flag:%v tags:%v keys:%v/%v ports:%v/%v",
+			semanticNodeID(node), s.JoinModeOfNode(node), expression, flag, s.TagsOfNode(node), required, produced, inputs, outputs)
+		if s.terminalNodes[s.nodeID(node)] {
+			description += " terminal"
+		}
+		if s.isDeclaredInitialNode(node) {
+			description += " initial"
+		}
+		for _, name := range s.Triggers() {
+			if containsNode(s, s.triggers[name], node) {
+				description += " trigger:" + name
+			}
+		}
+		semantic.Nodes = append(semantic.Nodes, description)
+	}
+	for _, link := range s.links {
+		semantic.Links = append(semantic.Links, fmt.Sprintf("%v -> %v branch:%v metadata:%v",
+			semanticNodeID(link.From), semanticNodeID(link.To), semanticBranch(link.Branch), link.Metadata))
+	}
+	for _, link := range s.portLinks {
+		semantic.PortLinks = append(semantic.PortLinks, fmt.Sprintf("%v.%v -> %v.%v",
+			semanticNodeID(link.From), link.Output, semanticNodeID(link.To), link.Input))
+	}
+	for _, node := range s.initialNodes {
+		semantic.InitialNodes = append(semantic.InitialNodes, semanticNodeID(node))
+	}
+	for kind, tree := range map[string]map[string]map[*bool][]Node{"following": s.followingNodesTree, "ancestors": s.ancestorsNodesTree} {
+		for _, node := range s.nodes {
+			for branch, nodes := range tree[s.nodeID(node)] {
+				ids := make([]string, 0, len(nodes))
+				for _, node := range nodes {
+					ids = append(ids, semanticNodeID(node))
+				}
+				sort.Strings(ids)
+				semantic.Trees = append(semantic.Trees, fmt.Sprintf("%v %v branch:%v: %v",
+					kind, semanticNodeID(node), semanticBranch(branch), strings.Join(ids, ", ")))
+			}
+		}
+	}
+
+	sort.Strings(semantic.Nodes)
+	sort.Strings(semantic.Links)
+	sort.Strings(semantic.PortLinks)
+	sort.Strings(semantic.InitialNodes)
+	sort.Strings(semantic.Trees)
+	return semantic
+}
+
+func semanticNodeID(node Node) string {
+	return fmt.Sprintf("%T(%v)", node, node)
+}
+
+func semanticBranch(branch *bool) string {
+	if branch == nil {
+		return "none"
+	}
+	return fmt.Sprint(*branch)
+}
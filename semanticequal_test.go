@@ -0,0 +1,88 @@
+package hoff
+
+import (
+	"testing"
+)
+
+func Test_NodeSystem_SemanticEqual(t *testing.T) {
+	newSystem := func(configure func(*NodeSystem, Node, Node, Node), activate bool) *NodeSystem {
+		keyIsPresent, _ := NewDecisionNode("keyIsPresent", func(c *Context) (bool, error) { return c.HaveKey("key"), nil })
+		readKey, _ := NewActionNode("readKey", func(*Context) error { return nil })
+		writeKey, _ := NewActionNode("writeKey", func(*Context) error { return nil })
+		ns := NewNodeSystem()
+		ns.AddNode(keyIsPresent)
+		ns.AddNode(readKey)
+		ns.AddNode(writeKey)
+		ns.AddLinkOnBranch(keyIsPresent, readKey, true)
+		ns.AddLinkOnBranch(keyIsPresent, writeKey, false)
+		ns.AddLink(readKey, writeKey)
+		ns.ConfigureJoinModeOnNode(writeKey, JoinOr)
+		if configure != nil {
+			configure(ns, keyIsPresent, readKey, writeKey)
+		}
+		if activate {
+			ns.ActivateInPlace()
+		}
+		return ns
+	}
+
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenOther    *NodeSystem
+		expectedEqual bool
+	}{
+		{
+			name:          "Same structure with other node instances",
+			givenSystem:   newSystem(nil, true),
+			givenOther:    newSystem(nil, true),
+			expectedEqual: true,
+		},
+		{
+			name:        "Different activation",
+			givenSystem: newSystem(nil, true),
+			givenOther:  newSystem(nil, false),
+		},
+		{
+			name:        "Different join mode",
+			givenSystem: newSystem(nil, true),
+			givenOther: newSystem(func(ns *NodeSystem, keyIsPresent, readKey, writeKey Node) {
+				ns.ConfigureJoinModeOnNode(writeKey, JoinAnd)
+			}, true),
+		},
+		{
+			name:        "Different link metadata",
+			givenSystem: newSystem(nil, true),
+			givenOther: newSystem(func(ns *NodeSystem, keyIsPresent, readKey, writeKey Node) {
+				ns.ConfigureMetadataOnLink(readKey, writeKey, nil, map[string]string{"owner": "team"})
+			}, true),
+		},
+		{
+			name:        "Different tags",
+			givenSystem: newSystem(nil, false),
+			givenOther: newSystem(func(ns *NodeSystem, keyIsPresent, readKey, writeKey Node) {
+				ns.ConfigureTagsOnNode(readKey, "db")
+			}, false),
+		},
+		{
+			name:        "Different links",
+			givenSystem: newSystem(nil, true),
+			givenOther: newSystem(func(ns *NodeSystem, keyIsPresent, readKey, writeKey Node) {
+				ns.links = ns.links[:2]
+			}, true),
+		},
+		{
+			name:        "Missing system",
+			givenSystem: newSystem(nil, true),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			equal := testCase.givenSystem.SemanticEqual(testCase.givenOther)
+
+			if equal != testCase.expectedEqual {
+				t.Errorf("got: %+v, want: %+v", equal, testCase.expectedEqual)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// NodeDuration is the expected duration of a node, as a normal distribution.
+type NodeDuration struct {
+	Mean   time.Duration
+	StdDev time.Duration
+}
+
+// SimulationOptions hold the options of a simulation of a node system.
+type SimulationOptions struct {
+	// Runs is the number of simulated computations
+	Runs int
+	// Seed of the random decisions and durations, the same seed give the same simulation
+	Seed int64
+	// Durations of the nodes, a node without duration take no time
+	Durations map[Node]NodeDuration
+}
+
+// SimulationResult hold the estimations of a simulation.
+type SimulationResult struct {
+	Runs int
+	// VisitFrequencies give the ratio of computations where each node is computed
+	VisitFrequencies map[Node]float64
+	// Durations are the durations of the simulated computations, from the shortest
+	Durations []time.Duration
+}
+
+// ConfigureBranchProbability configure the expected probability of the true branch of a decision node
+// into the system before activation, used by Simulate (0.5 by default).
+func (s *NodeSystem) ConfigureBranchProbability(n Node, probability float64) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't add branch probability, node system is freeze due to activation")
+	}
+	if n == nil || !n.DecideCapability() {
+		return false, fmt.Errorf("can't add branch probability on non decision node: %v", n)
+	}
+	if probability < 0 || probability > 1 {
+		return false, fmt.Errorf("can't add branch probability outside of [0, 1]: %v", probability)
+	}
+	if s.nodesProbabilities == nil {
+		s.nodesProbabilities = make(map[string]float64)
+	}
+	s.nodesProbabilities[s.nodeID(n)] = probability
+	return true, nil
+}
+
+// BranchProbabilityOfNode get the expected probability of the true branch of a decision node.
+func (s *NodeSystem) BranchProbabilityOfNode(n Node) float64 {
+	probability, found := s.nodesProbabilities[s.nodeID(n)]
+	if !found {
+		return 0.5
+	}
+	return probability
+}
+
+// Simulate estimate the frequency of computation of each node, and the duration of the computations,
+// with Monte-Carlo computations of the activated node system where the nodes are not computed:
+// a decision node take its branches by their probabilities, and a node take a random duration.
+// As in a sequential computation, the duration of a computation is the sum of the durations of its computed nodes.
+func (s *NodeSystem) Simulate(options SimulationOptions) (SimulationResult, error) {
+	if !s.activated {
+		return SimulationResult{}, errors.New("can't simulate a not activated node system")
+	}
+	if options.Runs <= 0 {
+		return SimulationResult{}, fmt.Errorf("can't simulate under 1 run: %v", options.Runs)
+	}
+
+	random := rand.New(rand.NewSource(options.Seed))
+	durations := make(map[string]NodeDuration, len(options.Durations))
+	for node, duration := range options.Durations {
+		durations[s.nodeID(node)] = duration
+	}
+	visits := make(map[string]int)
+	result := SimulationResult{
+		Runs:             options.Runs,
+		VisitFrequencies: make(map[Node]float64),
+		Durations:        make([]time.Duration, 0, options.Runs),
+	}
+	for run := 0; run < options.Runs; run++ {
+		cp, err := NewComputation(s, NewContextWithoutData())
+		if err != nil {
+			return SimulationResult{}, err
+		}
+		var total time.Duration
+		cp.interceptors = []nodeInterceptor{func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+			id := s.nodeID(node)
+			visits[id]++
+			if duration, found := durations[id]; found {
+				sample := duration.Mean + time.Duration(random.NormFloat64()*float64(duration.StdDev))
+				if sample > 0 {
+					total += sample
+				}
+			}
+			if node.DecideCapability() {
+				return NewContinueOnBranchComputeState(random.Float64() < s.BranchProbabilityOfNode(node))
+			}
+			return NewContinueComputeState()
+		}}
+		err = cp.Compute()
+		if err != nil {
+			return SimulationResult{}, err
+		}
+		result.Durations = append(result.Durations, total)
+	}
+
+	for _, node := range s.nodes {
+		result.VisitFrequencies[s.nodeMapKey(node)] = float64(visits[s.nodeID(node)]) / float64(options.Runs)
+	}
+	sort.Slice(result.Durations, func(i, j int) bool {
+		return result.Durations[i] < result.Durations[j]
+	})
+	return result, nil
+}
+
+// MeanDuration give the mean duration of the simulated computations.
+func (r SimulationResult) MeanDuration() time.Duration {
+	if len(r.Durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, duration := range r.Durations {
+		total += duration
+	}
+	return total / time.Duration(len(r.Durations))
+}
+
+// DurationPercentile give the duration under which a percentage (in [0, 100]) of the simulated computations end.
+func (r SimulationResult) DurationPercentile(percentage float64) time.Duration {
+	return durationPercentile(r.Durations, percentage)
+}
+
+// durationPercentile give the nearest-rank percentile of sorted durations.
+func durationPercentile(sortedDurations []time.Duration, percentage float64) time.Duration {
+	if len(sortedDurations) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentage/100*float64(len(sortedDurations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sortedDurations) {
+		rank = len(sortedDurations) - 1
+	}
+	return sortedDurations[rank]
+}
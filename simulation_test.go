@@ -0,0 +1,129 @@
+package hoff
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ConfigureBranchProbability(t *testing.T) {
+	activated := NewNodeSystem()
+	activated.ActivateInPlace()
+
+	testCases := []struct {
+		name             string
+		givenSystem      *NodeSystem
+		givenNode        Node
+		givenProbability float64
+		expectedError    error
+	}{
+		{
+			name:             "Can configure a branch probability",
+			givenSystem:      NewNodeSystem(),
+			givenNode:        alwaysTrueDecisionNode,
+			givenProbability: 0.2,
+		},
+		{
+			name:             "Can't configure a branch probability on an activated node system",
+			givenSystem:      activated,
+			givenNode:        alwaysTrueDecisionNode,
+			givenProbability: 0.2,
+			expectedError:    errors.New("can't add branch probability, node system is freeze due to activation"),
+		},
+		{
+			name:             "Can't configure a branch probability on a non decision node",
+			givenSystem:      NewNodeSystem(),
+			givenNode:        someActionNode,
+			givenProbability: 0.2,
+			expectedError:    errors.New("can't add branch probability on non decision node: someActionNode"),
+		},
+		{
+			name:             "Can't configure a branch probability above 1",
+			givenSystem:      NewNodeSystem(),
+			givenNode:        alwaysTrueDecisionNode,
+			givenProbability: 1.5,
+			expectedError:    errors.New("can't add branch probability outside of [0, 1]: 1.5"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := testCase.givenSystem.ConfigureBranchProbability(testCase.givenNode, testCase.givenProbability)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && testCase.givenSystem.BranchProbabilityOfNode(testCase.givenNode) != testCase.givenProbability {
+				t.Errorf("probability - got: %+v, want: %+v", testCase.givenSystem.BranchProbabilityOfNode(testCase.givenNode), testCase.givenProbability)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_Simulate(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.ConfigureBranchProbability(alwaysTrueDecisionNode, 0.8)
+	ns.IsValid()
+
+	_, err := ns.Simulate(SimulationOptions{Runs: 1})
+	expectedError := errors.New("can't simulate a not activated node system")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("not activated - got: %+v, want: %+v", err, expectedError)
+	}
+	ns.ActivateInPlace()
+	_, err = ns.Simulate(SimulationOptions{})
+	expectedError = errors.New("can't simulate under 1 run: 0")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("without run - got: %+v, want: %+v", err, expectedError)
+	}
+
+	options := SimulationOptions{
+		Runs: 10000,
+		Seed: 42,
+		Durations: map[Node]NodeDuration{
+			alwaysTrueDecisionNode: {Mean: 10 * time.Millisecond},
+			someActionNode:         {Mean: 100 * time.Millisecond},
+			anotherActionNode:      {Mean: 50 * time.Millisecond},
+		},
+	}
+	result, err := ns.Simulate(options)
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	if result.VisitFrequencies[alwaysTrueDecisionNode] != 1 ||
+		math.Abs(result.VisitFrequencies[someActionNode]-0.8) > 0.02 ||
+		math.Abs(result.VisitFrequencies[anotherActionNode]-0.2) > 0.02 {
+		t.Errorf("visit frequencies - got: %+v, want: 1, ~0.8, ~0.2", result.VisitFrequencies)
+	}
+	if result.DurationPercentile(10) != 60*time.Millisecond || result.DurationPercentile(50) != 110*time.Millisecond {
+		t.Errorf("percentiles - got: %v %v, want: 60ms 110ms", result.DurationPercentile(10), result.DurationPercentile(50))
+	}
+	if mean := result.MeanDuration(); mean < 98*time.Millisecond || mean > 102*time.Millisecond {
+		t.Errorf("mean duration - got: %v, want: ~100ms", mean)
+	}
+
+	sameResult, _ := ns.Simulate(options)
+	if !cmp.Equal(sameResult.Durations, result.Durations) {
+		t.Errorf("same seed - got: different durations, want: same durations")
+	}
+}
+
+func Test_NodeSystem_Activate_branchProbability(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.ConfigureBranchProbability(alwaysTrueDecisionNode, 0.8)
+
+	activated, _ := ns.Activate()
+	if probability := activated.NodeSystem().BranchProbabilityOfNode(alwaysTrueDecisionNode); probability != 0.8 {
+		t.Errorf("got: %+v, want: %+v", probability, 0.8)
+	}
+}
@@ -0,0 +1,202 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrErrorBudgetExhausted is the error of a node not computed by the circuit breaker of its SLO (see SLO.Breaker).
+var ErrErrorBudgetExhausted = errors.New("can't compute node with exhausted error budget")
+
+// SLO is the service level objective of a node, as the rate of its computations not aborted over a rolling window.
+// The error budget of the node is the rate of aborted computations allowed by the target.
+type SLO struct {
+	// Target is the expected success rate, between 0 (excluded) and 1, e.g. 0.99
+	Target float64
+	// Window is the rolling duration of the tracked computations
+	Window time.Duration
+	// MinComputations is the number of computations in the window before the budget can be exhausted
+	MinComputations int
+	// Breaker abort the node without computing it while its budget is exhausted,
+	// until enough aborted computations leave the window
+	Breaker bool
+}
+
+// SLOStatus hold the state of the SLO of a node over its window, e.g. to feed metrics.
+type SLOStatus struct {
+	Node         string
+	Target       float64
+	Computations int
+	Aborts       int
+	// SuccessRate is the rate of computations not aborted, 1 without computation
+	SuccessRate float64
+	// BudgetRemaining is the rate of the error budget not consumed, 0 or less when exhausted
+	BudgetRemaining float64
+	Exhausted       bool
+}
+
+type sloOutcome struct {
+	time    time.Time
+	aborted bool
+}
+
+type trackedSLO struct {
+	slo       SLO
+	outcomes  []sloOutcome
+	exhausted bool
+}
+
+// SLOTracker follow the computed nodes as an EventSink of an engine (see Engine.ConfigureSLOTracker),
+// and track the error budget of the nodes with a SLO.
+// The paused, waiting, and skipped nodes are not tracked.
+type SLOTracker struct {
+	mu    sync.Mutex
+	nodes map[string]*trackedSLO
+	alert func(SLOStatus)
+	now   func() time.Time
+}
+
+// NewSLOTracker create a SLOTracker without SLO.
+func NewSLOTracker() *SLOTracker {
+	return &SLOTracker{
+		nodes: make(map[string]*trackedSLO),
+		now:   time.Now,
+	}
+}
+
+// ConfigureSLOOnNode configure the SLO of a node, named after its string representation.
+func (t *SLOTracker) ConfigureSLOOnNode(n Node, slo SLO) error {
+	if n == nil {
+		return errors.New("can't configure slo on missing node")
+	}
+	if slo.Target <= 0 || slo.Target > 1 {
+		return fmt.Errorf("can't configure slo with target out of ]0, 1]: %v", slo.Target)
+	}
+	if slo.Window <= 0 || slo.MinComputations < 0 {
+		return fmt.Errorf("can't configure slo with not positive window or negative minimum: %v, %v", slo.Window, slo.MinComputations)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nodes[fmt.Sprint(n)] = &trackedSLO{slo: slo}
+	return nil
+}
+
+// ConfigureAlertHook configure a function called with the status of a node when its budget become exhausted.
+// The function is called synchronously, and need to return quickly.
+func (t *SLOTracker) ConfigureAlertHook(alert func(SLOStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.alert = alert
+}
+
+// Handle track the ended nodes with a SLO.
+func (t *SLOTracker) Handle(event Event) {
+	if event.Type != NodeEndedEvent {
+		return
+	}
+	var aborted bool
+	switch event.State.Value {
+	case ContinueState:
+	case AbortState:
+		if errors.Is(event.State.Error, ErrErrorBudgetExhausted) {
+			return
+		}
+		aborted = true
+	default:
+		return
+	}
+
+	t.mu.Lock()
+	name := fmt.Sprint(event.Node)
+	tracked, found := t.nodes[name]
+	if !found {
+		t.mu.Unlock()
+		return
+	}
+	tracked.outcomes = append(tracked.outcomes, sloOutcome{time: t.now(), aborted: aborted})
+	status := t.status(name, tracked)
+	alert := status.Exhausted && !tracked.exhausted && t.alert != nil
+	tracked.exhausted = status.Exhausted
+	hook := t.alert
+	t.mu.Unlock()
+
+	if alert {
+		hook(status)
+	}
+}
+
+// Status give the status of the SLO of each node, sorted by node.
+func (t *SLOTracker) Status() []SLOStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	statuses := make([]SLOStatus, 0, len(t.nodes))
+	for name, tracked := range t.nodes {
+		statuses = append(statuses, t.status(name, tracked))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Node < statuses[j].Node
+	})
+	return statuses
+}
+
+// exhausted tell if a node have a SLO with a breaker, and an exhausted budget.
+func (t *SLOTracker) exhausted(n Node) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	name := fmt.Sprint(n)
+	tracked, found := t.nodes[name]
+	if !found || !tracked.slo.Breaker {
+		return false
+	}
+	status := t.status(name, tracked)
+	tracked.exhausted = status.Exhausted
+	return status.Exhausted
+}
+
+// status give the status of a node after removing its outcomes out of the window.
+func (t *SLOTracker) status(name string, tracked *trackedSLO) SLOStatus {
+	since := t.now().Add(-tracked.slo.Window)
+	kept := tracked.outcomes[:0]
+	for _, outcome := range tracked.outcomes {
+		if outcome.time.After(since) {
+			kept = append(kept, outcome)
+		}
+	}
+	tracked.outcomes = kept
+
+	status := SLOStatus{Node: name, Target: tracked.slo.Target, Computations: len(kept), SuccessRate: 1, BudgetRemaining: 1}
+	for _, outcome := range kept {
+		if outcome.aborted {
+			status.Aborts++
+		}
+	}
+	if status.Computations > 0 {
+		abortRate := float64(status.Aborts) / float64(status.Computations)
+		status.SuccessRate = 1 - abortRate
+		if budget := 1 - tracked.slo.Target; budget > 0 {
+			status.BudgetRemaining = 1 - abortRate/budget
+		} else if status.Aborts > 0 {
+			status.BudgetRemaining = 0
+		}
+	}
+	status.Exhausted = status.Aborts > 0 && status.BudgetRemaining <= 0 && status.Computations >= tracked.slo.MinComputations
+	return status
+}
+
+// ConfigureSLOTracker add a tracker of the SLO of the nodes as an event sink,
+// and abort with ErrErrorBudgetExhausted the nodes whose SLO breaker is open.
+func (e *Engine) ConfigureSLOTracker(tracker *SLOTracker) {
+	e.sloTracker = tracker
+	e.AddEventSink(tracker)
+}
+
+// breakOnExhaustedBudget is the interceptor of the SLO breakers.
+func (e *Engine) breakOnExhaustedBudget(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	if e.sloTracker.exhausted(node) {
+		return NewAbortWithCodeComputeState(TransientAbort, fmt.Errorf("%w: %v", ErrErrorBudgetExhausted, node))
+	}
+	return compute(c)
+}
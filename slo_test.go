@@ -0,0 +1,180 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_SLOTracker_ConfigureSLOOnNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenNode     Node
+		givenSLO      SLO
+		expectedError error
+	}{
+		{
+			name:      "Can configure a slo",
+			givenNode: someActionNode,
+			givenSLO:  SLO{Target: 0.99, Window: time.Hour},
+		},
+		{
+			name:          "Can't configure a slo on missing node",
+			givenSLO:      SLO{Target: 0.99, Window: time.Hour},
+			expectedError: errors.New("can't configure slo on missing node"),
+		},
+		{
+			name:          "Can't configure a slo with a target out of range",
+			givenNode:     someActionNode,
+			givenSLO:      SLO{Target: 1.5, Window: time.Hour},
+			expectedError: errors.New("can't configure slo with target out of ]0, 1]: 1.5"),
+		},
+		{
+			name:          "Can't configure a slo without window",
+			givenNode:     someActionNode,
+			givenSLO:      SLO{Target: 0.9},
+			expectedError: errors.New("can't configure slo with not positive window or negative minimum: 0s, 0"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewSLOTracker().ConfigureSLOOnNode(testCase.givenNode, testCase.givenSLO)
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_SLOTracker_Handle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenSLO       SLO
+		givenStates    []ComputeState
+		givenElapsed   time.Duration
+		expectedStatus SLOStatus
+		expectedAlerts int
+	}{
+		{
+			name:           "Can track a node without computation",
+			givenSLO:       SLO{Target: 0.5, Window: time.Minute},
+			expectedStatus: SLOStatus{Node: "someActionNode", Target: 0.5, SuccessRate: 1, BudgetRemaining: 1},
+		},
+		{
+			name:           "Can track a node consuming its budget",
+			givenSLO:       SLO{Target: 0.5, Window: time.Minute},
+			givenStates:    []ComputeState{NewContinueComputeState(), NewContinueComputeState(), NewContinueComputeState(), NewAbortComputeState(errors.New("failed"))},
+			expectedStatus: SLOStatus{Node: "someActionNode", Target: 0.5, Computations: 4, Aborts: 1, SuccessRate: 0.75, BudgetRemaining: 0.5},
+		},
+		{
+			name:           "Can track a node exhausting its budget",
+			givenSLO:       SLO{Target: 0.5, Window: time.Minute},
+			givenStates:    []ComputeState{NewContinueComputeState(), NewAbortComputeState(errors.New("failed")), NewAbortComputeState(errors.New("failed"))},
+			expectedStatus: SLOStatus{Node: "someActionNode", Target: 0.5, Computations: 3, Aborts: 2, SuccessRate: 1 - 2.0/3, BudgetRemaining: 1 - (2.0/3)/0.5, Exhausted: true},
+			expectedAlerts: 1,
+		},
+		{
+			name:           "Can't exhaust the budget before the minimum of computations",
+			givenSLO:       SLO{Target: 0.5, Window: time.Minute, MinComputations: 5},
+			givenStates:    []ComputeState{NewAbortComputeState(errors.New("failed"))},
+			expectedStatus: SLOStatus{Node: "someActionNode", Target: 0.5, Computations: 1, Aborts: 1, BudgetRemaining: -1},
+		},
+		{
+			name:           "Can forget the computations out of the window",
+			givenSLO:       SLO{Target: 0.5, Window: time.Minute},
+			givenStates:    []ComputeState{NewAbortComputeState(errors.New("failed"))},
+			givenElapsed:   2 * time.Minute,
+			expectedStatus: SLOStatus{Node: "someActionNode", Target: 0.5, SuccessRate: 1, BudgetRemaining: 1},
+			expectedAlerts: 1,
+		},
+		{
+			name:           "Can ignore the skipped nodes and the breaker aborts",
+			givenSLO:       SLO{Target: 0.5, Window: time.Minute},
+			givenStates:    []ComputeState{NewSkipComputeState(), NewAbortComputeState(ErrErrorBudgetExhausted)},
+			expectedStatus: SLOStatus{Node: "someActionNode", Target: 0.5, SuccessRate: 1, BudgetRemaining: 1},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			tracker := NewSLOTracker()
+			tracker.now = func() time.Time { return now }
+			tracker.ConfigureSLOOnNode(someActionNode, testCase.givenSLO)
+			alerts := 0
+			tracker.ConfigureAlertHook(func(SLOStatus) { alerts++ })
+
+			for _, state := range testCase.givenStates {
+				tracker.Handle(Event{Type: NodeEndedEvent, Node: someActionNode, State: state})
+				tracker.Handle(Event{Type: NodeEndedEvent, Node: anotherActionNode, State: state})
+			}
+			now = now.Add(testCase.givenElapsed)
+
+			status := tracker.Status()
+			expectedStatus := []SLOStatus{testCase.expectedStatus}
+			if !cmp.Equal(status, expectedStatus, cmpopts.EquateApprox(0, 1e-9)) {
+				t.Errorf("status - got: %+v, want: %+v", status, expectedStatus)
+			}
+			if alerts != testCase.expectedAlerts {
+				t.Errorf("alerts - got: %+v, want: %+v", alerts, testCase.expectedAlerts)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureSLOTracker(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenBreaker     bool
+		expectedComputed int
+		expectedStatus   SLOStatus
+	}{
+		{
+			name:             "Can compute a node with an exhausted budget without breaker",
+			expectedComputed: 3,
+			expectedStatus:   SLOStatus{Node: "failing", Target: 0.5, Computations: 3, Aborts: 3, BudgetRemaining: -1, Exhausted: true},
+		},
+		{
+			name:             "Can't compute a node with an exhausted budget with breaker",
+			givenBreaker:     true,
+			expectedComputed: 1,
+			expectedStatus:   SLOStatus{Node: "failing", Target: 0.5, Computations: 1, Aborts: 1, BudgetRemaining: -1, Exhausted: true},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			computed := 0
+			failing, _ := NewActionNode("failing", func(*Context) error {
+				computed++
+				return errors.New("failed")
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(failing)
+			ns.ActivateInPlace()
+			tracker := NewSLOTracker()
+			tracker.ConfigureSLOOnNode(failing, SLO{Target: 0.5, Window: time.Hour, Breaker: testCase.givenBreaker})
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigureSLOTracker(tracker)
+
+			var result ComputationResult
+			for i := 0; i < 3; i++ {
+				result = eng.Compute(nil)
+			}
+
+			if computed != testCase.expectedComputed {
+				t.Errorf("computed - got: %+v, want: %+v", computed, testCase.expectedComputed)
+			}
+			if testCase.givenBreaker && !errors.Is(result.Report[failing].Error, ErrErrorBudgetExhausted) {
+				t.Errorf("error - got: %+v, want: %+v", result.Report[failing].Error, ErrErrorBudgetExhausted)
+			}
+			status := tracker.Status()
+			expectedStatus := []SLOStatus{testCase.expectedStatus}
+			if !cmp.Equal(status, expectedStatus) {
+				t.Errorf("status - got: %+v, want: %+v", status, expectedStatus)
+			}
+		})
+	}
+}
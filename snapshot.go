@@ -0,0 +1,104 @@
+package hoff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ContextSnapshot is a copy of the context data as it was when a node started.
+// The values are not deeply copied, a value modified in place is seen modified in the snapshot.
+type ContextSnapshot map[string]interface{}
+
+// ContextDiff hold the changes of the context data between two snapshots.
+type ContextDiff struct {
+	Added   map[string]interface{}
+	Removed map[string]interface{}
+	Changed map[string]ContextChange
+}
+
+// ContextChange hold the values of a key changed between two snapshots.
+type ContextChange struct {
+	Before interface{}
+	After  interface{}
+}
+
+// ConfigureContextSnapshots make the computation keep a snapshot of the context data
+// each time a node start, available through ContextAt.
+func (cp *Computation) ConfigureContextSnapshots() {
+	cp.snapshots = make(map[string]ContextSnapshot)
+}
+
+// ContextAt give the snapshot of the context data taken when a node started,
+// when the snapshots are enabled and the node was computed.
+func (cp *Computation) ContextAt(node Node) (ContextSnapshot, bool) {
+	snapshot, found := cp.snapshots[cp.System.nodeID(node)]
+	return snapshot, found
+}
+
+// ContextAt give the snapshot of the context data taken when a node started,
+// when the snapshots are enabled on the engine and the node was computed.
+func (r ComputationResult) ContextAt(node Node) (ContextSnapshot, bool) {
+	snapshot, found := r.Snapshots[node]
+	return snapshot, found
+}
+
+func (cp *Computation) takeSnapshot(node Node) {
+	if cp.snapshots == nil {
+		return
+	}
+	snapshot := make(ContextSnapshot, len(cp.Context.Data))
+	for key, value := range cp.Context.Data {
+		snapshot[key] = value
+	}
+	cp.snapshots[cp.System.nodeID(node)] = snapshot
+}
+
+// DiffContexts give the changes of the context data from a snapshot to another.
+func DiffContexts(from, to ContextSnapshot) ContextDiff {
+	diff := ContextDiff{
+		Added:   make(map[string]interface{}),
+		Removed: make(map[string]interface{}),
+		Changed: make(map[string]ContextChange),
+	}
+	for key, before := range from {
+		after, found := to[key]
+		switch {
+		case !found:
+			diff.Removed[key] = before
+		case !reflect.DeepEqual(before, after):
+			diff.Changed[key] = ContextChange{Before: before, After: after}
+		}
+	}
+	for key, after := range to {
+		if _, found := from[key]; !found {
+			diff.Added[key] = after
+		}
+	}
+	return diff
+}
+
+// IsEmpty tell if there is no change between the snapshots.
+func (d ContextDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String print the changes, one key by line sorted by key,
+// prefixed by '+' when added, '-' when removed, and '~' when changed.
+func (d ContextDiff) String() string {
+	lines := make([]string, 0, len(d.Added)+len(d.Removed)+len(d.Changed))
+	for key, value := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %v: %v", key, value))
+	}
+	for key, value := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %v: %v", key, value))
+	}
+	for key, change := range d.Changed {
+		lines = append(lines, fmt.Sprintf("~ %v: %v -> %v", key, change.Before, change.After))
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,127 @@
+package hoff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_ComputationResult_ContextAt(t *testing.T) {
+	first, _ := NewActionNode("first", func(c *Context) error {
+		c.Store("step", 1)
+		c.Store("temporary", true)
+		return nil
+	})
+	second, _ := NewActionNode("second", func(c *Context) error {
+		c.Store("step", 2)
+		c.Delete("temporary")
+		c.Store("done", true)
+		return nil
+	})
+	third, _ := NewActionNode("third", func(*Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(first)
+	ns.AddNode(second)
+	ns.AddNode(third)
+	ns.AddLink(first, second)
+	ns.AddLink(second, third)
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureContextSnapshots(true)
+	result := eng.Compute(map[string]interface{}{"input": "a"})
+
+	testCases := []struct {
+		name             string
+		givenNode        Node
+		expectedSnapshot ContextSnapshot
+	}{
+		{
+			name:             "Can get the context before the first node",
+			givenNode:        first,
+			expectedSnapshot: ContextSnapshot{"input": "a"},
+		},
+		{
+			name:             "Can get the context before the second node",
+			givenNode:        second,
+			expectedSnapshot: ContextSnapshot{"input": "a", "step": 1, "temporary": true},
+		},
+		{
+			name:             "Can get the context before the last node",
+			givenNode:        third,
+			expectedSnapshot: ContextSnapshot{"input": "a", "step": 2, "done": true},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			snapshot, found := result.ContextAt(testCase.givenNode)
+
+			if !found || !cmp.Equal(snapshot, testCase.expectedSnapshot) {
+				t.Errorf("got: %+v (found: %+v), want: %+v", snapshot, found, testCase.expectedSnapshot)
+			}
+		})
+	}
+
+	from, _ := result.ContextAt(second)
+	to, _ := result.ContextAt(third)
+	diff := DiffContexts(from, to)
+	expectedDiff := "+ done: true\n~ step: 1 -> 2\n- temporary: true"
+	if diff.String() != expectedDiff {
+		t.Errorf("diff - got: %v, want: %v", diff.String(), expectedDiff)
+	}
+}
+
+func Test_ComputationResult_ContextAt_without_snapshots(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	result := eng.Compute(map[string]interface{}{})
+
+	_, found := result.ContextAt(someActionNode)
+	if found {
+		t.Errorf("got: %+v, want: %+v", found, false)
+	}
+}
+
+func Test_DiffContexts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenFrom     ContextSnapshot
+		givenTo       ContextSnapshot
+		expectedDiff  ContextDiff
+		expectedEmpty bool
+	}{
+		{
+			name:          "Can diff identical snapshots",
+			givenFrom:     ContextSnapshot{"list": []int{1, 2}},
+			givenTo:       ContextSnapshot{"list": []int{1, 2}},
+			expectedEmpty: true,
+		},
+		{
+			name:      "Can diff changed snapshots",
+			givenFrom: ContextSnapshot{"list": []int{1, 2}, "removed": "x"},
+			givenTo:   ContextSnapshot{"list": []int{1, 2, 3}, "added": "y"},
+			expectedDiff: ContextDiff{
+				Added:   map[string]interface{}{"added": "y"},
+				Removed: map[string]interface{}{"removed": "x"},
+				Changed: map[string]ContextChange{"list": {Before: []int{1, 2}, After: []int{1, 2, 3}}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diff := DiffContexts(testCase.givenFrom, testCase.givenTo)
+
+			if !cmp.Equal(diff, testCase.expectedDiff, cmpopts.EquateEmpty()) {
+				t.Errorf("got: %+v, want: %+v", diff, testCase.expectedDiff)
+			}
+			if diff.IsEmpty() != testCase.expectedEmpty {
+				t.Errorf("empty - got: %+v, want: %+v", diff.IsEmpty(), testCase.expectedEmpty)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package hoff
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sqliteCheckpointMigrations are the schema changes of a SQLiteCheckpointStore, applied in order.
+// A released migration is never modified, a change is a new migration.
+var sqliteCheckpointMigrations = []string{
+	`CREATE TABLE {{table}} (
+		id TEXT PRIMARY KEY,
+		version INTEGER NOT NULL,
+		completed INTEGER NOT NULL,
+		updated INTEGER NOT NULL,
+		report BLOB NOT NULL
+	)`,
+	`CREATE INDEX {{table}}_completed_updated ON {{table}} (completed, updated)`,
+	`CREATE TABLE {{table}}_tokens (
+		token TEXT PRIMARY KEY,
+		id TEXT NOT NULL REFERENCES {{table}} (id) ON DELETE CASCADE
+	)`,
+}
+
+// SQLiteCheckpointStore is a CheckpointStore keeping the checkpoints in a SQLite database through database/sql,
+// the report of a checkpoint is stored as a ComputationReport message of proto/flow.proto.
+// The SQLite driver is chosen by the caller, and the schema is created or upgraded by Migrate.
+type SQLiteCheckpointStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLiteCheckpointStore create a checkpoint store on a SQLite table.
+func NewSQLiteCheckpointStore(db *sql.DB, table string) (*SQLiteCheckpointStore, error) {
+	if db == nil {
+		return nil, errors.New("can't create sqlite checkpoint store without database")
+	}
+	if !isSQLIdentifier(table) {
+		return nil, fmt.Errorf("can't create sqlite checkpoint store with invalid table name: %v", table)
+	}
+	return &SQLiteCheckpointStore{db: db, table: table}, nil
+}
+
+// Migrate create or upgrade the schema of the store, the applied migrations are kept in the '<table>_migrations' table.
+func (s *SQLiteCheckpointStore) Migrate() error {
+	_, err := s.db.Exec(s.statement("CREATE TABLE IF NOT EXISTS {{table}}_migrations (version INTEGER PRIMARY KEY)"))
+	if err != nil {
+		return fmt.Errorf("can't migrate checkpoint store: %w", err)
+	}
+	var applied int
+	err = s.db.QueryRow(s.statement("SELECT COALESCE(MAX(version), 0) FROM {{table}}_migrations")).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("can't migrate checkpoint store: %w", err)
+	}
+
+	for version := applied + 1; version <= len(sqliteCheckpointMigrations); version++ {
+		err = s.transaction(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(s.statement(sqliteCheckpointMigrations[version-1])); err != nil {
+				return err
+			}
+			_, err := tx.Exec(s.statement("INSERT INTO {{table}}_migrations (version) VALUES (?)"), version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("can't migrate checkpoint store to version %v: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// Save store a checkpoint if its version is the stored one (or 0 for a new checkpoint).
+func (s *SQLiteCheckpointStore) Save(checkpoint Checkpoint) (Checkpoint, error) {
+	encodedReport, err := checkpoint.Report.MarshalProto()
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if checkpoint.Updated.IsZero() {
+		checkpoint.Updated = time.Now()
+	}
+	id := checkpoint.Report.ID
+
+	err = s.transaction(func(tx *sql.Tx) error {
+		var result sql.Result
+		var err error
+		if checkpoint.Version == 0 {
+			result, err = tx.Exec(s.statement("INSERT OR IGNORE INTO {{table}} (id, version, completed, updated, report) VALUES (?, 1, ?, ?, ?)"),
+				id, checkpoint.Completed, checkpoint.Updated.UnixNano(), encodedReport)
+		} else {
+			result, err = tx.Exec(s.statement("UPDATE {{table}} SET version = version + 1, completed = ?, updated = ?, report = ? WHERE id = ? AND version = ?"),
+				checkpoint.Completed, checkpoint.Updated.UnixNano(), encodedReport, id, checkpoint.Version)
+		}
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrCheckpointConflict
+		}
+
+		if _, err = tx.Exec(s.statement("DELETE FROM {{table}}_tokens WHERE id = ?"), id); err != nil {
+			return err
+		}
+		if checkpoint.Completed {
+			return nil
+		}
+		for _, state := range checkpoint.Report.States {
+			if state.State != PauseState {
+				continue
+			}
+			if _, err = tx.Exec(s.statement("INSERT INTO {{table}}_tokens (token, id) VALUES (?, ?)"), state.Token, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, ErrCheckpointConflict) {
+		return Checkpoint{}, err
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("can't save checkpoint '%v': %w", id, err)
+	}
+	checkpoint.Version++
+	return checkpoint, nil
+}
+
+// Load give the checkpoint of a computation.
+func (s *SQLiteCheckpointStore) Load(id string) (Checkpoint, error) {
+	var checkpoint Checkpoint
+	var updated int64
+	var encodedReport []byte
+	err := s.db.QueryRow(s.statement("SELECT version, completed, updated, report FROM {{table}} WHERE id = ?"), id).
+		Scan(&checkpoint.Version, &checkpoint.Completed, &updated, &encodedReport)
+	if err == sql.ErrNoRows {
+		return Checkpoint{}, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("can't load checkpoint '%v': %w", id, err)
+	}
+	err = checkpoint.Report.UnmarshalProto(encodedReport)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	checkpoint.Updated = time.Unix(0, updated)
+	return checkpoint, nil
+}
+
+// FindPaused give the checkpoint of the computation paused on a token.
+func (s *SQLiteCheckpointStore) FindPaused(token string) (Checkpoint, error) {
+	var id string
+	err := s.db.QueryRow(s.statement("SELECT id FROM {{table}}_tokens WHERE token = ?"), token).Scan(&id)
+	if err == sql.ErrNoRows {
+		return Checkpoint{}, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("can't find checkpoint paused on token '%v': %w", token, err)
+	}
+	return s.Load(id)
+}
+
+// Cleanup remove the checkpoints of the completed computations updated before a time.
+// The tokens are removed explicitly, as SQLite only follow ON DELETE CASCADE with the foreign keys enabled on the connection.
+func (s *SQLiteCheckpointStore) Cleanup(before time.Time) (int, error) {
+	var removed int64
+	err := s.transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(s.statement("DELETE FROM {{table}}_tokens WHERE id IN (SELECT id FROM {{table}} WHERE completed = 1 AND updated < ?)"), before.UnixNano())
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(s.statement("DELETE FROM {{table}} WHERE completed = 1 AND updated < ?"), before.UnixNano())
+		if err != nil {
+			return err
+		}
+		removed, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("can't cleanup checkpoints: %w", err)
+	}
+	return int(removed), nil
+}
+
+// statement give a statement on the table of the store.
+func (s *SQLiteCheckpointStore) statement(statement string) string {
+	return strings.Replace(statement, "{{table}}", s.table, -1)
+}
+
+func (s *SQLiteCheckpointStore) transaction(run func(*sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	err = run(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,434 @@
+package hoff
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_NewSQLiteCheckpointStore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenDB       *sql.DB
+		givenTable    string
+		expectedError error
+	}{
+		{
+			name:          "Can't create a store without database",
+			givenTable:    "checkpoints",
+			expectedError: errors.New("can't create sqlite checkpoint store without database"),
+		},
+		{
+			name:          "Can't create a store with an invalid table name",
+			givenDB:       &sql.DB{},
+			givenTable:    "1checkpoints",
+			expectedError: errors.New("can't create sqlite checkpoint store with invalid table name: 1checkpoints"),
+		},
+		{
+			name:       "Can create a store",
+			givenDB:    &sql.DB{},
+			givenTable: "checkpoints",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewSQLiteCheckpointStore(testCase.givenDB, testCase.givenTable)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_SQLiteCheckpointStore_statement(t *testing.T) {
+	store, _ := NewSQLiteCheckpointStore(&sql.DB{}, "flow_checkpoints")
+	statement := store.statement("SELECT id FROM {{table}}_tokens JOIN {{table}} USING (id)")
+	expectedStatement := "SELECT id FROM flow_checkpoints_tokens JOIN flow_checkpoints USING (id)"
+	if statement != expectedStatement {
+		t.Errorf("got: %+v, want: %+v", statement, expectedStatement)
+	}
+}
+
+// fakeSQLiteCheckpoints is a database/sql connector who keep in memory the tables of a SQLiteCheckpointStore
+// on the 'checkpoints' table, answering to the statements of the store only.
+type fakeSQLiteCheckpoints struct {
+	mu                sync.Mutex
+	migrations        []int64
+	checkpoints       map[string]fakeSQLiteCheckpoint
+	tokens            map[string]string
+	rowsAffectedError error
+}
+
+type fakeSQLiteCheckpoint struct {
+	version   int64
+	completed bool
+	updated   int64
+	report    []byte
+}
+
+func newFakeSQLiteCheckpoints() *fakeSQLiteCheckpoints {
+	return &fakeSQLiteCheckpoints{
+		checkpoints: make(map[string]fakeSQLiteCheckpoint),
+		tokens:      make(map[string]string),
+	}
+}
+
+func (f *fakeSQLiteCheckpoints) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeSQLiteCheckpointsConn{db: f}, nil
+}
+
+func (f *fakeSQLiteCheckpoints) Driver() driver.Driver {
+	return nil
+}
+
+func (f *fakeSQLiteCheckpoints) exec(query string, args []driver.NamedValue) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case strings.HasPrefix(query, "CREATE "):
+		return 0, nil
+	case query == "INSERT INTO checkpoints_migrations (version) VALUES (?)":
+		f.migrations = append(f.migrations, args[0].Value.(int64))
+		return 1, nil
+	case strings.HasPrefix(query, "INSERT OR IGNORE INTO checkpoints "):
+		id := args[0].Value.(string)
+		if _, found := f.checkpoints[id]; found {
+			return 0, nil
+		}
+		f.checkpoints[id] = fakeSQLiteCheckpoint{version: 1, completed: args[1].Value.(bool), updated: args[2].Value.(int64), report: args[3].Value.([]byte)}
+		return 1, nil
+	case strings.HasPrefix(query, "UPDATE checkpoints "):
+		id, version := args[3].Value.(string), args[4].Value.(int64)
+		checkpoint, found := f.checkpoints[id]
+		if !found || checkpoint.version != version {
+			return 0, nil
+		}
+		f.checkpoints[id] = fakeSQLiteCheckpoint{version: version + 1, completed: args[0].Value.(bool), updated: args[1].Value.(int64), report: args[2].Value.([]byte)}
+		return 1, nil
+	case query == "DELETE FROM checkpoints_tokens WHERE id = ?":
+		return f.deleteTokens(func(id string) bool { return id == args[0].Value.(string) }), nil
+	case query == "INSERT INTO checkpoints_tokens (token, id) VALUES (?, ?)":
+		f.tokens[args[0].Value.(string)] = args[1].Value.(string)
+		return 1, nil
+	case strings.HasPrefix(query, "DELETE FROM checkpoints_tokens WHERE id IN "):
+		return f.deleteTokens(func(id string) bool { return f.expired(id, args[0].Value.(int64)) }), nil
+	case query == "DELETE FROM checkpoints WHERE completed = 1 AND updated < ?":
+		removed := int64(0)
+		for id := range f.checkpoints {
+			if f.expired(id, args[0].Value.(int64)) {
+				delete(f.checkpoints, id)
+				removed++
+			}
+		}
+		return removed, nil
+	}
+	return 0, fmt.Errorf("unexpected statement: %v", query)
+}
+
+func (f *fakeSQLiteCheckpoints) query(query string, args []driver.NamedValue) ([][]driver.Value, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch query {
+	case "SELECT COALESCE(MAX(version), 0) FROM checkpoints_migrations":
+		applied := int64(0)
+		for _, version := range f.migrations {
+			if version > applied {
+				applied = version
+			}
+		}
+		return [][]driver.Value{{applied}}, nil
+	case "SELECT version, completed, updated, report FROM checkpoints WHERE id = ?":
+		checkpoint, found := f.checkpoints[args[0].Value.(string)]
+		if !found {
+			return nil, nil
+		}
+		completed := int64(0)
+		if checkpoint.completed {
+			completed = 1
+		}
+		return [][]driver.Value{{checkpoint.version, completed, checkpoint.updated, checkpoint.report}}, nil
+	case "SELECT id FROM checkpoints_tokens WHERE token = ?":
+		id, found := f.tokens[args[0].Value.(string)]
+		if !found {
+			return nil, nil
+		}
+		return [][]driver.Value{{id}}, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %v", query)
+}
+
+func (f *fakeSQLiteCheckpoints) deleteTokens(selected func(id string) bool) int64 {
+	removed := int64(0)
+	for token, id := range f.tokens {
+		if selected(id) {
+			delete(f.tokens, token)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (f *fakeSQLiteCheckpoints) expired(id string, before int64) bool {
+	checkpoint, found := f.checkpoints[id]
+	return found && checkpoint.completed && checkpoint.updated < before
+}
+
+func (f *fakeSQLiteCheckpoints) tokensOf(id string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens := make([]string, 0)
+	for token, tokenID := range f.tokens {
+		if tokenID == id {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+type fakeSQLiteCheckpointsConn struct {
+	db *fakeSQLiteCheckpoints
+}
+
+func (c *fakeSQLiteCheckpointsConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeSQLiteCheckpointsConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLiteCheckpointsConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeSQLiteCheckpointsConn) Commit() error {
+	return nil
+}
+
+func (c *fakeSQLiteCheckpointsConn) Rollback() error {
+	return nil
+}
+
+func (c *fakeSQLiteCheckpointsConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	rows, err := c.db.exec(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return fakeSQLiteResult{rows: rows, err: c.db.rowsAffectedError}, nil
+}
+
+func (c *fakeSQLiteCheckpointsConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values, err := c.db.query(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeSQLiteRows{values: values}, nil
+}
+
+type fakeSQLiteRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeSQLiteRows) Columns() []string {
+	if len(r.values) == 0 {
+		return nil
+	}
+	return make([]string, len(r.values[0]))
+}
+
+func (r *fakeSQLiteRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLiteRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+type fakeSQLiteResult struct {
+	rows int64
+	err  error
+}
+
+func (r fakeSQLiteResult) LastInsertId() (int64, error) {
+	return 0, errors.New("last insert id not supported")
+}
+
+func (r fakeSQLiteResult) RowsAffected() (int64, error) {
+	return r.rows, r.err
+}
+
+func newFakeSQLiteCheckpointStore(t *testing.T) (*SQLiteCheckpointStore, *fakeSQLiteCheckpoints) {
+	fake := newFakeSQLiteCheckpoints()
+	store, _ := NewSQLiteCheckpointStore(sql.OpenDB(fake), "checkpoints")
+	err := store.Migrate()
+	if err != nil {
+		t.Fatalf("migrate - got: %+v, want: %+v", err, nil)
+	}
+	return store, fake
+}
+
+func Test_SQLiteCheckpointStore_Migrate(t *testing.T) {
+	store, fake := newFakeSQLiteCheckpointStore(t)
+	err := store.Migrate()
+	if err != nil {
+		t.Errorf("migrate again - got: %+v, want: %+v", err, nil)
+	}
+	expectedMigrations := []int64{1, 2, 3}
+	if !cmp.Equal(fake.migrations, expectedMigrations) {
+		t.Errorf("migrations - got: %+v, want: %+v", fake.migrations, expectedMigrations)
+	}
+}
+
+func Test_SQLiteCheckpointStore_Save(t *testing.T) {
+	pausedReport := ComputationReport{ID: "computation-1", States: []NodeStateReport{
+		{Node: "someActionNode", State: ContinueState},
+		{Node: "approval", State: PauseState, Token: "token-1"},
+	}}
+	updated := time.Unix(0, 42)
+
+	testCases := []struct {
+		name               string
+		givenCheckpoints   []Checkpoint
+		givenCheckpoint    Checkpoint
+		givenRowsError     error
+		expectedCheckpoint Checkpoint
+		expectedTokens     []string
+		expectedError      error
+	}{
+		{
+			name:               "Can save a new checkpoint",
+			givenCheckpoint:    Checkpoint{Report: pausedReport, Updated: updated},
+			expectedCheckpoint: Checkpoint{Report: pausedReport, Updated: updated, Version: 1},
+			expectedTokens:     []string{"token-1"},
+		},
+		{
+			name:               "Can save a completed checkpoint",
+			givenCheckpoints:   []Checkpoint{{Report: pausedReport, Updated: updated}},
+			givenCheckpoint:    Checkpoint{Report: pausedReport, Updated: updated, Version: 1, Completed: true},
+			expectedCheckpoint: Checkpoint{Report: pausedReport, Updated: updated, Version: 2, Completed: true},
+			expectedTokens:     []string{},
+		},
+		{
+			name:             "Can't save a new checkpoint already saved",
+			givenCheckpoints: []Checkpoint{{Report: pausedReport, Updated: updated}},
+			givenCheckpoint:  Checkpoint{Report: pausedReport, Updated: updated},
+			expectedTokens:   []string{"token-1"},
+			expectedError:    ErrCheckpointConflict,
+		},
+		{
+			name:             "Can't save a checkpoint on an outdated version",
+			givenCheckpoints: []Checkpoint{{Report: pausedReport, Updated: updated}, {Report: pausedReport, Updated: updated, Version: 1}},
+			givenCheckpoint:  Checkpoint{Report: pausedReport, Updated: updated, Version: 1, Completed: true},
+			expectedTokens:   []string{"token-1"},
+			expectedError:    ErrCheckpointConflict,
+		},
+		{
+			name:            "Can't save a checkpoint without affected rows",
+			givenCheckpoint: Checkpoint{Report: pausedReport, Updated: updated},
+			givenRowsError:  errors.New("rows affected not supported"),
+			expectedTokens:  []string{},
+			expectedError:   fmt.Errorf("can't save checkpoint 'computation-1': %w", errors.New("rows affected not supported")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store, fake := newFakeSQLiteCheckpointStore(t)
+			for _, checkpoint := range testCase.givenCheckpoints {
+				store.Save(checkpoint)
+			}
+			fake.rowsAffectedError = testCase.givenRowsError
+
+			checkpoint, err := store.Save(testCase.givenCheckpoint)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(checkpoint, testCase.expectedCheckpoint) {
+				t.Errorf("checkpoint - got: %+v, want: %+v", checkpoint, testCase.expectedCheckpoint)
+			}
+			tokens := fake.tokensOf("computation-1")
+			if !cmp.Equal(tokens, testCase.expectedTokens) {
+				t.Errorf("tokens - got: %+v, want: %+v", tokens, testCase.expectedTokens)
+			}
+		})
+	}
+}
+
+func Test_SQLiteCheckpointStore_Load(t *testing.T) {
+	store, _ := newFakeSQLiteCheckpointStore(t)
+	report := ComputationReport{ID: "computation-1", Data: map[string][]byte{"count": []byte("1")}}
+	store.Save(Checkpoint{Report: report, Updated: time.Unix(0, 42), Completed: true})
+
+	checkpoint, err := store.Load("computation-1")
+	if err != nil {
+		t.Errorf("error - got: %+v, want: %+v", err, nil)
+	}
+	expectedCheckpoint := Checkpoint{Report: report, Updated: time.Unix(0, 42), Version: 1, Completed: true}
+	if !cmp.Equal(checkpoint, expectedCheckpoint, cmpopts.EquateEmpty()) {
+		t.Errorf("checkpoint - got: %+v, want: %+v", checkpoint, expectedCheckpoint)
+	}
+
+	_, err = store.Load("unknown")
+	if err != ErrCheckpointNotFound {
+		t.Errorf("unknown - got: %+v, want: %+v", err, ErrCheckpointNotFound)
+	}
+}
+
+func Test_SQLiteCheckpointStore_FindPaused(t *testing.T) {
+	store, _ := newFakeSQLiteCheckpointStore(t)
+	report := ComputationReport{ID: "computation-1", States: []NodeStateReport{{Node: "approval", State: PauseState, Token: "token-1"}}}
+	store.Save(Checkpoint{Report: report, Updated: time.Unix(0, 42)})
+
+	checkpoint, err := store.FindPaused("token-1")
+	if err != nil || checkpoint.Report.ID != "computation-1" {
+		t.Errorf("paused - got: %+v (error: %+v), want: checkpoint of computation-1", checkpoint, err)
+	}
+
+	_, err = store.FindPaused("unknown")
+	if err != ErrCheckpointNotFound {
+		t.Errorf("unknown - got: %+v, want: %+v", err, ErrCheckpointNotFound)
+	}
+}
+
+func Test_SQLiteCheckpointStore_Cleanup(t *testing.T) {
+	store, fake := newFakeSQLiteCheckpointStore(t)
+	paused := ComputationReport{ID: "paused", States: []NodeStateReport{{Node: "approval", State: PauseState, Token: "token-1"}}}
+	store.Save(Checkpoint{Report: paused, Updated: time.Unix(0, 10)})
+	store.Save(Checkpoint{Report: ComputationReport{ID: "old"}, Updated: time.Unix(0, 10), Completed: true})
+	store.Save(Checkpoint{Report: ComputationReport{ID: "recent"}, Updated: time.Unix(0, 30), Completed: true})
+
+	removed, err := store.Cleanup(time.Unix(0, 20))
+	if err != nil || removed != 1 {
+		t.Errorf("removed - got: %+v (error: %+v), want: %+v", removed, err, 1)
+	}
+	ids := make([]string, 0)
+	for id := range fake.checkpoints {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	expectedIDs := []string{"paused", "recent"}
+	if !cmp.Equal(ids, expectedIDs) {
+		t.Errorf("checkpoints - got: %+v, want: %+v", ids, expectedIDs)
+	}
+	if tokens := fake.tokensOf("paused"); !cmp.Equal(tokens, []string{"token-1"}) {
+		t.Errorf("tokens - got: %+v, want: %+v", tokens, []string{"token-1"})
+	}
+}
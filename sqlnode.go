@@ -0,0 +1,257 @@
+package hoff
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLNode is a type of Node who run a parameterized query on a database through database/sql.
+// The parameters of the query are read from context keys, and the result is stored in a context key:
+// the rows (as a list of column/value maps) for a query, or the number of affected rows for an exec.
+// Between a SQLBeginNode and a SQLCommitNode on the same database,
+// the query is run in the transaction of the computation.
+type SQLNode struct {
+	name    string
+	db      *sql.DB
+	query   string
+	params  []string
+	target  string
+	exec    bool
+	timeout time.Duration
+}
+
+func (n SQLNode) String() string {
+	return n.name
+}
+
+// Compute run the query with the parameters and store its result.
+func (n *SQLNode) Compute(c *Context) ComputeState {
+	args := make([]interface{}, 0, len(n.params))
+	for _, param := range n.params {
+		value, found := c.Read(param)
+		if !found {
+			return NewAbortWithCodeComputeState(ValidationAbort, fmt.Errorf("can't run query of node '%v' with missing parameter: %v", n.name, param))
+		}
+		args = append(args, value)
+	}
+
+	ctx := c.GoContext()
+	if n.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+	}
+	var runner sqlRunner = n.db
+	if tx, found := sqlTransaction(c, n.db); found {
+		runner = tx
+	}
+
+	var result interface{}
+	var err error
+	if n.exec {
+		result, err = execSQL(ctx, runner, n.query, args)
+	} else {
+		result, err = querySQL(ctx, runner, n.query, args)
+	}
+	if err != nil {
+		err = fmt.Errorf("can't run query of node '%v': %w", n.name, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return NewAbortWithCodeComputeState(TransientAbort, err)
+		}
+		return NewAbortComputeState(err)
+	}
+	c.Store(n.target, result)
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a query don't take a decision.
+func (n *SQLNode) DecideCapability() bool {
+	return false
+}
+
+// RequiredKeys give the context keys of the parameters.
+func (n *SQLNode) RequiredKeys() []string {
+	return append([]string(nil), n.params...)
+}
+
+// ProducedKeys give the context key of the result.
+func (n *SQLNode) ProducedKeys() []string {
+	return []string{n.target}
+}
+
+// ConfigureTimeout limit the duration of the query, a query running longer is aborted as transient.
+func (n *SQLNode) ConfigureTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return fmt.Errorf("can't configure negative timeout: %v", timeout)
+	}
+	n.timeout = timeout
+	return nil
+}
+
+// NewSQLQueryNode create a SQLNode who store the rows of a query, as a list of column/value maps
+// (text and blob values are stored as strings), based on a name, a database,
+// the query, the key of the result, and the keys of the query parameters in order.
+func NewSQLQueryNode(name string, db *sql.DB, query, target string, params ...string) (*SQLNode, error) {
+	return newSQLNode(name, db, query, target, params, false)
+}
+
+// NewSQLExecNode create a SQLNode who store the number of rows affected by a statement,
+// based on a name, a database, the statement, the key of the result, and the keys of the statement parameters in order.
+func NewSQLExecNode(name string, db *sql.DB, statement, target string, params ...string) (*SQLNode, error) {
+	return newSQLNode(name, db, statement, target, params, true)
+}
+
+func newSQLNode(name string, db *sql.DB, query, target string, params []string, exec bool) (*SQLNode, error) {
+	if db == nil {
+		return nil, errors.New("can't create sql node without database")
+	}
+	if query == "" || target == "" {
+		return nil, errors.New("can't create sql node without query and target key")
+	}
+	return &SQLNode{name: name, db: db, query: query, params: append([]string(nil), params...), target: target, exec: exec}, nil
+}
+
+// sqlRunner run queries on a database or in a transaction.
+type sqlRunner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func execSQL(ctx context.Context, runner sqlRunner, query string, args []interface{}) (int64, error) {
+	result, err := runner.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func querySQL(ctx context.Context, runner sqlRunner, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := runner.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if bytes, ok := values[i].([]byte); ok {
+				row[column] = string(bytes)
+				continue
+			}
+			row[column] = values[i]
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// sqlTransactionKey is the key of the transaction of a computation on a database.
+type sqlTransactionKey struct {
+	db *sql.DB
+}
+
+// sqlTransaction give the transaction of the computation on a database.
+func sqlTransaction(c *Context, db *sql.DB) (*sql.Tx, bool) {
+	tx, found := c.resource(sqlTransactionKey{db: db})
+	if !found {
+		return nil, false
+	}
+	return tx.(*sql.Tx), true
+}
+
+// SQLBeginNode is a type of Node who begin a transaction on a database for the computation,
+// used by the following SQL nodes on this database until a SQLCommitNode.
+// A transaction not committed is rolled back at the end of the computation (see Context.AddCleanup),
+// so a computation can't be paused during a transaction.
+type SQLBeginNode struct {
+	name string
+	db   *sql.DB
+}
+
+func (n SQLBeginNode) String() string {
+	return n.name
+}
+
+// Compute begin the transaction.
+func (n *SQLBeginNode) Compute(c *Context) ComputeState {
+	if _, found := sqlTransaction(c, n.db); found {
+		return NewAbortComputeState(fmt.Errorf("can't begin transaction of node '%v' during another transaction", n.name))
+	}
+	tx, err := n.db.BeginTx(c.GoContext(), nil)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't begin transaction of node '%v': %w", n.name, err))
+	}
+	c.keepResource(sqlTransactionKey{db: n.db}, tx)
+	c.AddCleanup(func() {
+		if current, _ := sqlTransaction(c, n.db); current == tx {
+			c.forgetResource(sqlTransactionKey{db: n.db})
+		}
+		tx.Rollback()
+	})
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a transaction don't take a decision.
+func (n *SQLBeginNode) DecideCapability() bool {
+	return false
+}
+
+// NewSQLBeginNode create a SQLBeginNode based on a name and a database.
+func NewSQLBeginNode(name string, db *sql.DB) (*SQLBeginNode, error) {
+	if db == nil {
+		return nil, errors.New("can't create sql node without database")
+	}
+	return &SQLBeginNode{name: name, db: db}, nil
+}
+
+// SQLCommitNode is a type of Node who commit the transaction on a database begun by a SQLBeginNode.
+type SQLCommitNode struct {
+	name string
+	db   *sql.DB
+}
+
+func (n SQLCommitNode) String() string {
+	return n.name
+}
+
+// Compute commit the transaction.
+func (n *SQLCommitNode) Compute(c *Context) ComputeState {
+	tx, found := sqlTransaction(c, n.db)
+	if !found {
+		return NewAbortComputeState(fmt.Errorf("can't commit transaction of node '%v' without transaction", n.name))
+	}
+	c.forgetResource(sqlTransactionKey{db: n.db})
+	if err := tx.Commit(); err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't commit transaction of node '%v': %w", n.name, err))
+	}
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a transaction don't take a decision.
+func (n *SQLCommitNode) DecideCapability() bool {
+	return false
+}
+
+// NewSQLCommitNode create a SQLCommitNode based on a name and a database.
+func NewSQLCommitNode(name string, db *sql.DB) (*SQLCommitNode, error) {
+	if db == nil {
+		return nil, errors.New("can't create sql node without database")
+	}
+	return &SQLCommitNode{name: name, db: db}, nil
+}
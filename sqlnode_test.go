@@ -0,0 +1,347 @@
+package hoff
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeSQL is a database/sql connector who log the statements, and answer to any query
+// with the same rows. The 'SLEEP' query wait the end of the go context, and the 'FAIL' query fail.
+type fakeSQL struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (f *fakeSQL) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeSQLConn{db: f}, nil
+}
+
+func (f *fakeSQL) Driver() driver.Driver {
+	return nil
+}
+
+func (f *fakeSQL) record(format string, a ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.log = append(f.log, fmt.Sprintf(format, a...))
+}
+
+func (f *fakeSQL) statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.log...)
+}
+
+type fakeSQLConn struct {
+	db   *fakeSQL
+	inTx bool
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeSQLConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.inTx = true
+	c.db.record("begin")
+	return c, nil
+}
+
+func (c *fakeSQLConn) Commit() error {
+	c.inTx = false
+	c.db.record("commit")
+	return nil
+}
+
+func (c *fakeSQLConn) Rollback() error {
+	c.inTx = false
+	c.db.record("rollback")
+	return nil
+}
+
+func (c *fakeSQLConn) run(ctx context.Context, query string, args []driver.NamedValue) error {
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	if c.inTx {
+		c.db.record("%v %v in transaction", query, values)
+	} else {
+		c.db.record("%v %v", query, values)
+	}
+	switch query {
+	case "SLEEP":
+		<-ctx.Done()
+		return ctx.Err()
+	case "FAIL":
+		return errors.New("syntax error")
+	}
+	return nil
+}
+
+func (c *fakeSQLConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.run(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (c *fakeSQLConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.run(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return &fakeSQLRows{values: [][]driver.Value{{int64(1), []byte("bob")}, {int64(2), nil}}}, nil
+}
+
+type fakeSQLRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return []string{"id", "name"}
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func Test_NewSQLQueryNode(t *testing.T) {
+	db := sql.OpenDB(&fakeSQL{})
+	defer db.Close()
+
+	testCases := []struct {
+		name          string
+		givenDB       *sql.DB
+		givenQuery    string
+		givenTarget   string
+		expectedError error
+	}{
+		{
+			name:        "Can create a sql node",
+			givenDB:     db,
+			givenQuery:  "SELECT id, name FROM users WHERE team = ?",
+			givenTarget: "users",
+		},
+		{
+			name:          "Can't create a sql node without database",
+			givenQuery:    "SELECT id, name FROM users WHERE team = ?",
+			givenTarget:   "users",
+			expectedError: errors.New("can't create sql node without database"),
+		},
+		{
+			name:          "Can't create a sql node without query",
+			givenDB:       db,
+			givenTarget:   "users",
+			expectedError: errors.New("can't create sql node without query and target key"),
+		},
+		{
+			name:          "Can't create a sql node without target key",
+			givenDB:       db,
+			givenQuery:    "SELECT id, name FROM users WHERE team = ?",
+			expectedError: errors.New("can't create sql node without query and target key"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewSQLQueryNode("users", testCase.givenDB, testCase.givenQuery, testCase.givenTarget, "team")
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && (!cmp.Equal(node.RequiredKeys(), []string{"team"}) || !cmp.Equal(node.ProducedKeys(), []string{"users"})) {
+				t.Errorf("keys - got: %+v %+v, want: %+v %+v", node.RequiredKeys(), node.ProducedKeys(), "team", "users")
+			}
+		})
+	}
+}
+
+func Test_SQLNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenNode          func(db *sql.DB) *SQLNode
+		givenData          map[string]interface{}
+		expectedState      ComputeState
+		expectedData       map[string]interface{}
+		expectedStatements []string
+	}{
+		{
+			name: "Can store the rows of a query",
+			givenNode: func(db *sql.DB) *SQLNode {
+				node, _ := NewSQLQueryNode("users", db, "SELECT id, name FROM users WHERE team = ?", "users", "team")
+				return node
+			},
+			givenData:     map[string]interface{}{"team": "dev"},
+			expectedState: NewContinueComputeState(),
+			expectedData: map[string]interface{}{"team": "dev", "users": []map[string]interface{}{
+				{"id": int64(1), "name": "bob"},
+				{"id": int64(2), "name": nil},
+			}},
+			expectedStatements: []string{"SELECT id, name FROM users WHERE team = ? [dev]"},
+		},
+		{
+			name: "Can store the number of affected rows of a statement",
+			givenNode: func(db *sql.DB) *SQLNode {
+				node, _ := NewSQLExecNode("rename", db, "UPDATE users SET name = ? WHERE id = ?", "renamed", "name", "id")
+				return node
+			},
+			givenData:          map[string]interface{}{"name": "alice", "id": 2},
+			expectedState:      NewContinueComputeState(),
+			expectedData:       map[string]interface{}{"name": "alice", "id": 2, "renamed": int64(2)},
+			expectedStatements: []string{"UPDATE users SET name = ? WHERE id = ? [alice 2]"},
+		},
+		{
+			name: "Can't run a query with missing parameter",
+			givenNode: func(db *sql.DB) *SQLNode {
+				node, _ := NewSQLQueryNode("users", db, "SELECT id, name FROM users WHERE team = ?", "users", "team")
+				return node
+			},
+			givenData:          map[string]interface{}{},
+			expectedState:      NewAbortWithCodeComputeState(ValidationAbort, errors.New("can't run query of node 'users' with missing parameter: team")),
+			expectedData:       map[string]interface{}{},
+			expectedStatements: []string{},
+		},
+		{
+			name: "Can't run a failing query",
+			givenNode: func(db *sql.DB) *SQLNode {
+				node, _ := NewSQLQueryNode("users", db, "FAIL", "users")
+				return node
+			},
+			givenData:          map[string]interface{}{},
+			expectedState:      NewAbortComputeState(errors.New("can't run query of node 'users': syntax error")),
+			expectedData:       map[string]interface{}{},
+			expectedStatements: []string{"FAIL []"},
+		},
+		{
+			name: "Can't run a query longer than the timeout",
+			givenNode: func(db *sql.DB) *SQLNode {
+				node, _ := NewSQLExecNode("sleep", db, "SLEEP", "slept")
+				node.ConfigureTimeout(10 * time.Millisecond)
+				return node
+			},
+			givenData:          map[string]interface{}{},
+			expectedState:      NewAbortWithCodeComputeState(TransientAbort, errors.New("can't run query of node 'sleep': context deadline exceeded")),
+			expectedData:       map[string]interface{}{},
+			expectedStatements: []string{"SLEEP []"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake := &fakeSQL{}
+			db := sql.OpenDB(fake)
+			defer db.Close()
+			c := NewContext(testCase.givenData)
+
+			state := testCase.givenNode(db).Compute(c)
+
+			if !cmp.Equal(errorMessage(state.Error), errorMessage(testCase.expectedState.Error)) || state.Value != testCase.expectedState.Value || state.Code != testCase.expectedState.Code {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+			if !cmp.Equal(fake.statements(), testCase.expectedStatements) {
+				t.Errorf("statements - got: %+v, want: %+v", fake.statements(), testCase.expectedStatements)
+			}
+		})
+	}
+}
+
+func Test_SQLNode_transaction(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenError         error
+		expectedStatements []string
+	}{
+		{
+			name: "Can commit a transaction across sql nodes",
+			expectedStatements: []string{
+				"begin",
+				"INSERT INTO orders (id) VALUES (?) [42] in transaction",
+				"UPDATE stock SET count = count - 1 [] in transaction",
+				"commit",
+			},
+		},
+		{
+			name:       "Can rollback a transaction of an aborted computation",
+			givenError: errors.New("payment failure"),
+			expectedStatements: []string{
+				"begin",
+				"INSERT INTO orders (id) VALUES (?) [42] in transaction",
+				"rollback",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake := &fakeSQL{}
+			db := sql.OpenDB(fake)
+			defer db.Close()
+			begin, _ := NewSQLBeginNode("begin", db)
+			insert, _ := NewSQLExecNode("insert", db, "INSERT INTO orders (id) VALUES (?)", "inserted", "order")
+			payment, _ := NewActionNode("payment", func(c *Context) error { return testCase.givenError })
+			update, _ := NewSQLExecNode("update", db, "UPDATE stock SET count = count - 1", "updated")
+			commit, _ := NewSQLCommitNode("commit", db)
+			ns := NewNodeSystem()
+			ns.AddNode(begin)
+			ns.AddNode(insert)
+			ns.AddNode(payment)
+			ns.AddNode(update)
+			ns.AddNode(commit)
+			ns.AddLink(begin, insert)
+			ns.AddLink(insert, payment)
+			ns.AddLink(payment, update)
+			ns.AddLink(update, commit)
+			ns.ActivateInPlace()
+
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			result := eng.Compute(map[string]interface{}{"order": 42})
+
+			if !cmp.Equal(result.Error, testCase.givenError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.givenError)
+			}
+			if !cmp.Equal(fake.statements(), testCase.expectedStatements) {
+				t.Errorf("statements - got: %+v, want: %+v", fake.statements(), testCase.expectedStatements)
+			}
+		})
+	}
+}
+
+func Test_SQLCommitNode_Compute_withoutTransaction(t *testing.T) {
+	db := sql.OpenDB(&fakeSQL{})
+	defer db.Close()
+	commit, _ := NewSQLCommitNode("commit", db)
+
+	state := commit.Compute(NewContextWithoutData())
+
+	expectedState := NewAbortComputeState(errors.New("can't commit transaction of node 'commit' without transaction"))
+	if !cmp.Equal(state, expectedState, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", state, expectedState)
+	}
+}
@@ -0,0 +1,82 @@
+package hoff
+
+import "fmt"
+
+// ReportMessage is a fixed message of the computation reports, formatted by a StateFormatter.
+type ReportMessage string
+
+const (
+	// NotComputedMessage is the message of a node not computed
+	NotComputedMessage ReportMessage = "not computed"
+	// DanglingPathMessage is the message of a node ending a path without reaching a terminal node
+	DanglingPathMessage ReportMessage = "dangling path"
+)
+
+// StateFormatter format the compute states and the messages of the reports for the users,
+// e.g. to localize them, or to give structured messages to an embedding application.
+type StateFormatter interface {
+	// FormatState give the text of the compute state of a node
+	FormatState(node Node, state ComputeState) string
+	// FormatMessage give the text of a report message about a node
+	FormatMessage(node Node, message ReportMessage) string
+}
+
+// EnglishStateFormatter is the default StateFormatter, in english (see ComputeState.String).
+type EnglishStateFormatter struct{}
+
+// FormatState give the human-readable version of the compute state.
+func (EnglishStateFormatter) FormatState(node Node, state ComputeState) string {
+	return state.String()
+}
+
+// FormatMessage give the message as is.
+func (EnglishStateFormatter) FormatMessage(node Node, message ReportMessage) string {
+	return string(message)
+}
+
+// StateFormatterFuncs is a StateFormatter based on functions,
+// falling back to EnglishStateFormatter for a missing function.
+type StateFormatterFuncs struct {
+	State   func(node Node, state ComputeState) string
+	Message func(node Node, message ReportMessage) string
+}
+
+// FormatState give the text of the State function.
+func (f StateFormatterFuncs) FormatState(node Node, state ComputeState) string {
+	if f.State == nil {
+		return EnglishStateFormatter{}.FormatState(node, state)
+	}
+	return f.State(node, state)
+}
+
+// FormatMessage give the text of the Message function.
+func (f StateFormatterFuncs) FormatMessage(node Node, message ReportMessage) string {
+	if f.Message == nil {
+		return EnglishStateFormatter{}.FormatMessage(node, message)
+	}
+	return f.Message(node, message)
+}
+
+// FormatReport give the text of the state of each node of an activated node system in a computation result,
+// using a StateFormatter, english if nil.
+func FormatReport(system *NodeSystem, result ComputationResult, formatter StateFormatter) map[Node]string {
+	if formatter == nil {
+		formatter = EnglishStateFormatter{}
+	}
+	dangling := make(map[string]bool)
+	for _, node := range system.DanglingNodes(result) {
+		dangling[system.nodeID(node)] = true
+	}
+	texts := make(map[Node]string, len(system.nodes))
+	for _, node := range system.nodes {
+		text := formatter.FormatMessage(node, NotComputedMessage)
+		if state, found := result.Report[system.nodeMapKey(node)]; found {
+			text = formatter.FormatState(node, state)
+		}
+		if dangling[system.nodeID(node)] {
+			text = fmt.Sprintf("%v (%v)", text, formatter.FormatMessage(node, DanglingPathMessage))
+		}
+		texts[system.nodeMapKey(node)] = text
+	}
+	return texts
+}
@@ -0,0 +1,120 @@
+package hoff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var frenchStateFormatter = StateFormatterFuncs{
+	State: func(node Node, state ComputeState) string {
+		switch state.Value {
+		case ContinueState:
+			return "continué"
+		case SkipState:
+			return "ignoré"
+		case AbortState:
+			return fmt.Sprintf("abandonné : %v", state.Error)
+		}
+		return string(state.Value)
+	},
+	Message: func(node Node, message ReportMessage) string {
+		if message == NotComputedMessage {
+			return "non calculé"
+		}
+		return "chemin sans fin"
+	},
+}
+
+func Test_FormatReport(t *testing.T) {
+	start, _ := NewActionNode("start", func(*Context) error { return nil })
+	check, _ := NewDecisionNode("check", func(*Context) (bool, error) { return false, nil })
+	fail, _ := NewActionNode("fail", func(*Context) error { return errors.New("boom") })
+	never, _ := NewActionNode("never", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(start)
+	ns.AddNode(check)
+	ns.AddNode(fail)
+	ns.AddNode(never)
+	ns.AddLink(start, check)
+	ns.AddLinkOnBranch(check, fail, false)
+	ns.AddLinkOnBranch(check, never, true)
+	ns.ConfigureAsTerminal(fail)
+	ns.ActivateInPlace()
+	result := ComputationResult{Report: map[Node]ComputeState{
+		start: NewContinueComputeState(),
+		check: NewContinueOnBranchComputeState(true),
+	}}
+
+	testCases := []struct {
+		name           string
+		givenFormatter StateFormatter
+		expectedTexts  map[Node]string
+	}{
+		{
+			name:           "Can format in english by default",
+			givenFormatter: nil,
+			expectedTexts: map[Node]string{
+				start: "'Continue'",
+				check: "'Continue on true' (dangling path)",
+				fail:  "not computed",
+				never: "not computed",
+			},
+		},
+		{
+			name:           "Can format with a formatter",
+			givenFormatter: frenchStateFormatter,
+			expectedTexts: map[Node]string{
+				start: "continué",
+				check: "continué (chemin sans fin)",
+				fail:  "non calculé",
+				never: "non calculé",
+			},
+		},
+		{
+			name:           "Can format with a formatter falling back to english",
+			givenFormatter: StateFormatterFuncs{Message: frenchStateFormatter.Message},
+			expectedTexts: map[Node]string{
+				start: "'Continue'",
+				check: "'Continue on true' (chemin sans fin)",
+				fail:  "non calculé",
+				never: "non calculé",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			texts := FormatReport(ns, result, testCase.givenFormatter)
+
+			if !cmp.Equal(texts, testCase.expectedTexts) {
+				t.Errorf("got: %+v, want: %+v", texts, testCase.expectedTexts)
+			}
+		})
+	}
+}
+
+func Test_RenderHTMLReportWithFormatter(t *testing.T) {
+	fail, _ := NewActionNode("fail", func(*Context) error { return errors.New("boom") })
+	ns := NewNodeSystem()
+	ns.AddNode(fail)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	result := eng.Compute(nil)
+
+	var buffer bytes.Buffer
+	err := RenderHTMLReportWithFormatter(&buffer, ns, result, frenchStateFormatter)
+
+	if err != nil {
+		t.Errorf("error - got: %+v, want: <nil>", err)
+	}
+	expectedContent := "<summary>fail: abandonné : boom</summary>"
+	if !strings.Contains(buffer.String(), expectedContent) {
+		t.Errorf("got: %v, want to contain: %v", buffer.String(), expectedContent)
+	}
+}
@@ -12,4 +12,10 @@ const (
 	// AbortState tell the Node computation encounter an error
 	// and abort the computation
 	AbortState = "Abort"
+	// PauseState tell that the Node computation wait for an external event
+	// before continuing to compute the following nodes
+	PauseState = "Pause"
+	// WaitState tell that the Node computation wait for an external condition
+	// and need to be computed again after a poll interval
+	WaitState = "Wait"
 )
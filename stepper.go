@@ -0,0 +1,46 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StepAction define what to do with a node about to be computed, as decided by a Stepper.
+type StepAction string
+
+const (
+	// StepRun compute the node.
+	StepRun StepAction = "run"
+	// StepSkip skip the node without computing it.
+	StepSkip = "skip"
+	// StepAbort abort the node without computing it.
+	StepAbort = "abort"
+)
+
+// ErrAbortedByStepper is the error of a node aborted by a Stepper.
+var ErrAbortedByStepper = errors.New("can't compute node, aborted by stepper")
+
+// Stepper is called before each node computation to run a computation step by step,
+// like an operator approving or skipping nodes while debugging a node system.
+// The stepper is called synchronously and can block until a decision is taken.
+type Stepper interface {
+	Step(node Node, c *Context) StepAction
+}
+
+// ConfigureStepper add a stepper called before each node computation.
+func (e *Engine) ConfigureStepper(stepper Stepper) {
+	e.stepper = stepper
+}
+
+func (e *Engine) stepNode(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	switch action := e.stepper.Step(node, c); action {
+	case StepRun:
+		return compute(c)
+	case StepSkip:
+		return NewSkipComputeState()
+	case StepAbort:
+		return NewAbortComputeState(ErrAbortedByStepper)
+	default:
+		return NewAbortComputeState(fmt.Errorf("can't compute node with unknown step action: %v", action))
+	}
+}
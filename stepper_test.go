@@ -0,0 +1,91 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type stepperFunc func(Node, *Context) StepAction
+
+func (f stepperFunc) Step(node Node, c *Context) StepAction {
+	return f(node, c)
+}
+
+func Test_Engine_ConfigureStepper(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.ActivateInPlace()
+
+	testCases := []struct {
+		name           string
+		givenAction    StepAction
+		expectedReport map[Node]ComputeState
+		expectedError  error
+	}{
+		{
+			name:        "Can run the node",
+			givenAction: StepRun,
+			expectedReport: map[Node]ComputeState{
+				someActionNode:    NewContinueComputeState(),
+				anotherActionNode: NewContinueComputeState(),
+			},
+		},
+		{
+			name:        "Can skip the node",
+			givenAction: StepSkip,
+			expectedReport: map[Node]ComputeState{
+				someActionNode:    NewContinueComputeState(),
+				anotherActionNode: NewSkipComputeState(),
+			},
+		},
+		{
+			name:        "Can abort the node",
+			givenAction: StepAbort,
+			expectedReport: map[Node]ComputeState{
+				someActionNode:    NewContinueComputeState(),
+				anotherActionNode: NewAbortComputeState(ErrAbortedByStepper),
+			},
+			expectedError: ErrAbortedByStepper,
+		},
+		{
+			name:        "Can't handle an unknown action",
+			givenAction: "pause",
+			expectedReport: map[Node]ComputeState{
+				someActionNode:    NewContinueComputeState(),
+				anotherActionNode: NewAbortComputeState(errors.New("can't compute node with unknown step action: pause")),
+			},
+			expectedError: errors.New("can't compute node with unknown step action: pause"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			steppedNodes := make([]Node, 0)
+			eng.ConfigureStepper(stepperFunc(func(node Node, c *Context) StepAction {
+				steppedNodes = append(steppedNodes, node)
+				if node == anotherActionNode {
+					return testCase.givenAction
+				}
+				return StepRun
+			}))
+
+			result := eng.Compute(map[string]interface{}{})
+
+			if !cmp.Equal(result.Error, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", result.Error, testCase.expectedError)
+			}
+			if !cmp.Equal(result.Report, testCase.expectedReport, NodeComparator, errorComparator) {
+				t.Errorf("report - got: %+v, want: %+v", result.Report, testCase.expectedReport)
+			}
+			expectedSteppedNodes := []Node{someActionNode, anotherActionNode}
+			if !cmp.Equal(steppedNodes, expectedSteppedNodes, NodeComparator) {
+				t.Errorf("stepped nodes - got: %+v, want: %+v", steppedNodes, expectedSteppedNodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package hoff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var nodeType = reflect.TypeOf((*Node)(nil)).Elem()
+
+// NewNodeSystemFromStruct create a NodeSystem from a struct, or a pointer to a struct, as a compact in-code workflow.
+// The exported fields implementing Node are the nodes, added in the order of the fields,
+// and their `hoff` tag describe them with options separated by commas:
+//   - after=Validate link the node after the node of the field Validate,
+//     after=Validate:true after a branch of a decision node, after=Charge|Reject after several nodes
+//   - join=and or join=or configure the join mode of the node
+//   - tags=slow|db configure the tags of the node
+//   - initial declare the node as initial node, terminal configure it as terminal node
+//
+// A field tagged with `hoff:"-"` is ignored. The node system is not activated,
+// and is validated on activation as any node system.
+func NewNodeSystemFromStruct(flow interface{}) (*NodeSystem, error) {
+	value := reflect.ValueOf(flow)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't create node system from not struct value: %T", flow)
+	}
+
+	ns := NewNodeSystem()
+	names := make([]string, 0)
+	tags := make(map[string]string)
+	nodes := make(map[string]Node)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag, tagged := field.Tag.Lookup("hoff")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if !field.Type.Implements(nodeType) {
+			if tagged {
+				return nil, fmt.Errorf("can't use field '%v' of type %v as node", field.Name, field.Type)
+			}
+			continue
+		}
+		node, _ := value.Field(i).Interface().(Node)
+		if node == nil || (value.Field(i).Kind() == reflect.Ptr && value.Field(i).IsNil()) {
+			return nil, fmt.Errorf("can't use field '%v' without node", field.Name)
+		}
+		if _, err := ns.AddNode(node); err != nil {
+			return nil, fmt.Errorf("can't add node of field '%v': %w", field.Name, err)
+		}
+		names = append(names, field.Name)
+		tags[field.Name] = tag
+		nodes[field.Name] = node
+	}
+
+	for _, name := range names {
+		if tags[name] == "" {
+			continue
+		}
+		for _, option := range strings.Split(tags[name], ",") {
+			if err := applyStructOption(ns, nodes, name, strings.TrimSpace(option)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ns, nil
+}
+
+func applyStructOption(ns *NodeSystem, nodes map[string]Node, name, option string) error {
+	node := nodes[name]
+	key, value := option, ""
+	if index := strings.Index(option, "="); index >= 0 {
+		key, value = option[:index], option[index+1:]
+	}
+	var err error
+	switch {
+	case key == "after" && value != "":
+		for _, after := range strings.Split(value, "|") {
+			fromName, branch := after, ""
+			if index := strings.Index(after, ":"); index >= 0 {
+				fromName, branch = after[:index], after[index+1:]
+			}
+			from, found := nodes[fromName]
+			if !found {
+				return fmt.Errorf("can't link field '%v' after unknown field '%v'", name, fromName)
+			}
+			switch branch {
+			case "":
+				_, err = ns.AddLink(from, node)
+			case "true", "false":
+				_, err = ns.AddLinkOnBranch(from, node, branch == "true")
+			default:
+				return fmt.Errorf("can't link field '%v' after field '%v' on unknown branch '%v'", name, fromName, branch)
+			}
+			if err != nil {
+				return fmt.Errorf("can't link field '%v' after field '%v': %w", name, fromName, err)
+			}
+		}
+		return nil
+	case key == "join" && (value == string(JoinAnd) || value == JoinOr):
+		_, err = ns.ConfigureJoinModeOnNode(node, JoinMode(value))
+	case key == "tags" && value != "":
+		_, err = ns.ConfigureTagsOnNode(node, strings.Split(value, "|")...)
+	case option == "initial":
+		_, err = ns.DeclareInitialNode(node)
+	case option == "terminal":
+		_, err = ns.ConfigureAsTerminal(node)
+	default:
+		return fmt.Errorf("can't use unknown option '%v' on field '%v'", option, name)
+	}
+	if err != nil {
+		return fmt.Errorf("can't use option '%v' on field '%v': %w", option, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewNodeSystemFromStruct(t *testing.T) {
+	validate := &DecisionNode{name: "validate", decisionFunc: func(*Context) (bool, error) { return true, nil }}
+	charge := &ActionNode{name: "charge", actionFunc: func(*Context) error { return nil }}
+	reject := &ActionNode{name: "reject", actionFunc: func(*Context) error { return nil }}
+	notify := &ActionNode{name: "notify", actionFunc: func(*Context) error { return nil }}
+
+	type orderFlow struct {
+		Validate *DecisionNode `hoff:"initial"`
+		Charge   *ActionNode   `hoff:"after=Validate:true,tags=payment|slow"`
+		Reject   Node          `hoff:"after=Validate:false"`
+		Notify   Node          `hoff:"after=Charge|Reject, join=or, terminal"`
+		Ignored  Node          `hoff:"-"`
+		Label    string
+		private  Node
+	}
+
+	ns, err := NewNodeSystemFromStruct(&orderFlow{Validate: validate, Charge: charge, Reject: reject, Notify: notify, private: notify})
+	if err != nil {
+		t.Fatalf("error - got: %+v, want: %+v", err, nil)
+	}
+
+	expected := NewNodeSystem()
+	expected.AddNode(validate)
+	expected.AddNode(charge)
+	expected.AddNode(reject)
+	expected.AddNode(notify)
+	expected.AddLinkOnBranch(validate, charge, true)
+	expected.AddLinkOnBranch(validate, reject, false)
+	expected.AddLink(charge, notify)
+	expected.AddLink(reject, notify)
+	expected.ConfigureTagsOnNode(charge, "payment", "slow")
+	expected.ConfigureJoinModeOnNode(notify, JoinOr)
+	expected.ConfigureAsTerminal(notify)
+	expected.DeclareInitialNode(validate)
+	if !ns.Equal(expected) || !cmp.Equal(ns.declaredInitialNodes, expected.declaredInitialNodes, NodeComparator) {
+		t.Errorf("node system - got: %+v, want: %+v", ns, expected)
+	}
+
+	if err := ns.ActivateInPlace(); err != nil {
+		t.Errorf("activation - got: %+v, want: %+v", err, nil)
+	}
+}
+
+func Test_NewNodeSystemFromStruct_errors(t *testing.T) {
+	action := &ActionNode{name: "action", actionFunc: func(*Context) error { return nil }}
+	decision := &DecisionNode{name: "decision", decisionFunc: func(*Context) (bool, error) { return true, nil }}
+
+	testCases := []struct {
+		name          string
+		givenFlow     interface{}
+		expectedError error
+	}{
+		{
+			name:          "Can't create from a not struct value",
+			givenFlow:     "flow",
+			expectedError: errors.New("can't create node system from not struct value: string"),
+		},
+		{
+			name: "Can't create with a tagged field not implementing node",
+			givenFlow: struct {
+				Label string `hoff:"initial"`
+			}{},
+			expectedError: errors.New("can't use field 'Label' of type string as node"),
+		},
+		{
+			name: "Can't create with a field without node",
+			givenFlow: struct {
+				Action *ActionNode
+			}{},
+			expectedError: errors.New("can't use field 'Action' without node"),
+		},
+		{
+			name: "Can't create with a link after an unknown field",
+			givenFlow: struct {
+				Action *ActionNode `hoff:"after=Missing"`
+			}{Action: action},
+			expectedError: errors.New("can't link field 'Action' after unknown field 'Missing'"),
+		},
+		{
+			name: "Can't create with a link after an unknown branch",
+			givenFlow: struct {
+				Decision *DecisionNode
+				Action   *ActionNode `hoff:"after=Decision:maybe"`
+			}{Decision: decision, Action: action},
+			expectedError: errors.New("can't link field 'Action' after field 'Decision' on unknown branch 'maybe'"),
+		},
+		{
+			name: "Can't create with a link after a decision node without branch",
+			givenFlow: struct {
+				Decision *DecisionNode
+				Action   *ActionNode `hoff:"after=Decision"`
+			}{Decision: decision, Action: action},
+			expectedError: fmt.Errorf("can't link field 'Action' after field 'Decision': %w", errors.New("can't have missing branch")),
+		},
+		{
+			name: "Can't create with an unknown option",
+			givenFlow: struct {
+				Action *ActionNode `hoff:"join=xor"`
+			}{Action: action},
+			expectedError: errors.New("can't use unknown option 'join=xor' on field 'Action'"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns, err := NewNodeSystemFromStruct(testCase.givenFlow)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if ns != nil {
+				t.Errorf("node system - got: %+v, want: %+v", ns, nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SubFlowMapping define the context keys exchanged between a parent computation and its sub-flow.
+// The other keys are isolated, the sub-flow don't read nor write the parent context data outside the mapping.
+type SubFlowMapping struct {
+	// Inputs map a key of the parent context data to an input key of the sub-flow
+	Inputs map[string]string
+	// Outputs map an output key of the sub-flow to a key of the parent context data
+	Outputs map[string]string
+}
+
+// SubFlowNode is a type of Node who compute a nested workflow with the node system of an engine.
+type SubFlowNode struct {
+	name    string
+	engine  *Engine
+	mapping SubFlowMapping
+}
+
+func (n SubFlowNode) String() string {
+	return n.name
+}
+
+// Compute run the sub-flow on the mapped inputs, and store the mapped outputs on success.
+// The node abort if the sub-flow fail or pause.
+func (n *SubFlowNode) Compute(c *Context) ComputeState {
+	data := make(map[string]interface{}, len(n.mapping.Inputs))
+	for _, parentKey := range sortedKeys(n.mapping.Inputs) {
+		if value, found := c.Read(parentKey); found {
+			data[n.mapping.Inputs[parentKey]] = value
+		}
+	}
+
+	result := n.engine.ComputeWithContext(c.GoContext(), data)
+	if result.Error != nil {
+		return NewAbortComputeState(fmt.Errorf("can't compute sub-flow '%v': %w", n.name, result.Error))
+	}
+	if tokens := result.PausedTokens(); len(tokens) > 0 {
+		sort.Strings(tokens)
+		return NewAbortComputeState(fmt.Errorf("can't compute sub-flow '%v', paused on %v", n.name, strings.Join(tokens, ", ")))
+	}
+
+	for _, childKey := range sortedKeys(n.mapping.Outputs) {
+		if value, found := result.Data[childKey]; found {
+			c.Store(n.mapping.Outputs[childKey], value)
+		}
+	}
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a sub-flow don't take a decision.
+func (n *SubFlowNode) DecideCapability() bool {
+	return false
+}
+
+// NewSubFlowNode create a SubFlowNode based on a name, an engine configured with the node system of the sub-flow,
+// and the mapping of its inputs and outputs.
+// When the sub-flow declare an input schema (or an output contract), only its declared inputs (or outputs) can be mapped.
+func NewSubFlowNode(name string, engine *Engine, mapping SubFlowMapping) (*SubFlowNode, error) {
+	if engine == nil || engine.system == nil {
+		return nil, errors.New("can't create sub-flow node without an engine configured with a node system")
+	}
+	inputs := engine.system.InputSchema()
+	for _, parentKey := range sortedKeys(mapping.Inputs) {
+		if len(inputs) > 0 && !isDeclaredInput(inputs, mapping.Inputs[parentKey]) {
+			return nil, fmt.Errorf("can't map undeclared input of sub-flow: %v", mapping.Inputs[parentKey])
+		}
+	}
+	outputs := engine.system.OutputContract()
+	for _, childKey := range sortedKeys(mapping.Outputs) {
+		if len(outputs) > 0 && !isDeclaredOutput(outputs, childKey) {
+			return nil, fmt.Errorf("can't map undeclared output of sub-flow: %v", childKey)
+		}
+	}
+	return &SubFlowNode{name: name, engine: engine, mapping: mapping}, nil
+}
+
+func isDeclaredInput(inputs []InputParameter, key string) bool {
+	for _, input := range inputs {
+		if input.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func isDeclaredOutput(outputs []OutputParameter, key string) bool {
+	for _, output := range outputs {
+		if output.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(mapping map[string]string) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,140 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func subFlowEngine(t *testing.T) *Engine {
+	greet, _ := NewActionNode("greet", func(c *Context) error {
+		name, _ := c.Read("name")
+		c.Store("greeting", fmt.Sprintf("hello %v", name))
+		c.Store("internal", true)
+		if c.HaveKey("secret") {
+			return errors.New("can't read parent secret")
+		}
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(greet)
+	ns.DeclareInput(InputParameter{Key: "name", Type: StringInput, Required: true})
+	ns.DeclareOutput(OutputParameter{Key: "greeting", Type: StringInput, Required: true})
+	err := ns.ActivateInPlace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	return eng
+}
+
+func Test_NewSubFlowNode(t *testing.T) {
+	eng := subFlowEngine(t)
+
+	testCases := []struct {
+		name          string
+		givenEngine   *Engine
+		givenMapping  SubFlowMapping
+		expectedError error
+	}{
+		{
+			name:         "Can create a sub-flow node",
+			givenEngine:  eng,
+			givenMapping: SubFlowMapping{Inputs: map[string]string{"user": "name"}, Outputs: map[string]string{"greeting": "message"}},
+		},
+		{
+			name:          "Can't create a sub-flow node without engine",
+			expectedError: errors.New("can't create sub-flow node without an engine configured with a node system"),
+		},
+		{
+			name:          "Can't create a sub-flow node without node system",
+			givenEngine:   NewEngine(SequentialComputation),
+			expectedError: errors.New("can't create sub-flow node without an engine configured with a node system"),
+		},
+		{
+			name:          "Can't create a sub-flow node with undeclared input",
+			givenEngine:   eng,
+			givenMapping:  SubFlowMapping{Inputs: map[string]string{"user": "username"}},
+			expectedError: errors.New("can't map undeclared input of sub-flow: username"),
+		},
+		{
+			name:          "Can't create a sub-flow node with undeclared output",
+			givenEngine:   eng,
+			givenMapping:  SubFlowMapping{Outputs: map[string]string{"internal": "internal"}},
+			expectedError: errors.New("can't map undeclared output of sub-flow: internal"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewSubFlowNode("subFlow", testCase.givenEngine, testCase.givenMapping)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if testCase.expectedError != nil && node != nil {
+				t.Errorf("sub-flow node - got: %+v, want: <nil>", node)
+			}
+		})
+	}
+}
+
+func Test_SubFlowNode_Compute(t *testing.T) {
+	eng := subFlowEngine(t)
+	mapping := SubFlowMapping{
+		Inputs:  map[string]string{"user": "name"},
+		Outputs: map[string]string{"greeting": "message"},
+	}
+	node, _ := NewSubFlowNode("subFlow", eng, mapping)
+
+	testCases := []struct {
+		name          string
+		givenData     map[string]interface{}
+		expectedState ComputeState
+		expectedData  map[string]interface{}
+	}{
+		{
+			name:          "Can compute a sub-flow with mapped inputs and outputs only",
+			givenData:     map[string]interface{}{"user": "bob", "secret": "s3cr3t", "greeting": "parent"},
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"user": "bob", "secret": "s3cr3t", "greeting": "parent", "message": "hello bob"},
+		},
+		{
+			name:          "Can't compute a sub-flow with missing input",
+			givenData:     map[string]interface{}{"secret": "s3cr3t"},
+			expectedState: NewAbortComputeState(errors.New("can't compute sub-flow 'subFlow': can't compute with invalid input: can't have missing required input: name")),
+			expectedData:  map[string]interface{}{"secret": "s3cr3t"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := NewContext(testCase.givenData)
+			state := node.Compute(c)
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_SubFlowNode_Compute_paused(t *testing.T) {
+	approval, _ := NewWaitForEventNode("approval", "approval_payload")
+	ns := NewNodeSystem()
+	ns.AddNode(approval)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	node, _ := NewSubFlowNode("subFlow", eng, SubFlowMapping{})
+
+	state := node.Compute(NewContext(map[string]interface{}{}))
+
+	if state.Value != AbortState || state.Error == nil {
+		t.Errorf("got: %+v, want: an abort state", state)
+	}
+}
@@ -0,0 +1,185 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// successTerm is a parsed part of a success criteria expression.
+//
+// The grammar is:
+//
+//	expression := term ( "or" term )*
+//	term       := factor ( "and" factor )*
+//	factor     := "not" factor | "(" expression ")" | reference
+//	reference  := name ( "continued" | "skipped" | "aborted" | "paused" | "computed" )
+//
+// where a name with spaces or parenthesis is written between single quotes.
+type successTerm func(report map[Node]ComputeState) bool
+
+// successReferenceStates give the states matching a state of a success criteria reference.
+var successReferenceStates = map[string][]StateType{
+	"continued": {ContinueState},
+	"skipped":   {SkipState},
+	"aborted":   {AbortState},
+	"paused":    {PauseState},
+	"computed":  {ContinueState, SkipState, AbortState, PauseState},
+}
+
+// ConfigureSuccessCriteria define what count as a successful computation with an expression
+// over the compute states of the nodes, e.g. "validate continued and not store aborted".
+// The computations ended on an error who is not due to an aborted node are not successful.
+func (e *Engine) ConfigureSuccessCriteria(expression string) error {
+	term, err := parseSuccessCriteria(expression)
+	if err != nil {
+		return err
+	}
+	e.ConfigureSuccessPredicate(func(result ComputationResult) bool {
+		return term(result.Report)
+	})
+	return nil
+}
+
+// ConfigureSuccessPredicate define what count as a successful computation with a predicate.
+// The computations ended on an error who is not due to an aborted node are not successful.
+func (e *Engine) ConfigureSuccessPredicate(predicate func(ComputationResult) bool) {
+	e.successPredicate = predicate
+}
+
+// IsSuccess tell if the computation is successful, based on the success criteria of the engine,
+// or by ending without error, abort, and pause by default.
+func (r ComputationResult) IsSuccess() bool {
+	return r.Success
+}
+
+func (e *Engine) isSuccess(result ComputationResult) bool {
+	if e.successPredicate == nil {
+		return result.Error == nil && !result.IsAborted() && len(result.PausedTokens()) == 0
+	}
+	if result.Error != nil && !result.IsAborted() {
+		return false
+	}
+	return e.successPredicate(result)
+}
+
+func parseSuccessCriteria(source string) (successTerm, error) {
+	tokens, err := tokenizeJoinExpression(source)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse success criteria '%v': %v", source, err)
+	}
+	parser := &successCriteriaParser{joinExpressionParser{tokens: tokens}}
+	term, err := parser.parseOr()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected '%v'", tokens[parser.position].value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse success criteria '%v': %v", source, err)
+	}
+	return term, nil
+}
+
+type successCriteriaParser struct {
+	joinExpressionParser
+}
+
+func (p *successCriteriaParser) parseOr() (successTerm, error) {
+	terms := make([]successTerm, 0)
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.peekKeyword("or") {
+			break
+		}
+		p.position++
+	}
+	return func(report map[Node]ComputeState) bool {
+		for _, term := range terms {
+			if term(report) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (p *successCriteriaParser) parseAnd() (successTerm, error) {
+	terms := make([]successTerm, 0)
+	for {
+		term, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.peekKeyword("and") {
+			break
+		}
+		p.position++
+	}
+	return func(report map[Node]ComputeState) bool {
+		for _, term := range terms {
+			if !term(report) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (p *successCriteriaParser) parseFactor() (successTerm, error) {
+	if p.position >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	token := p.tokens[p.position]
+	p.position++
+	if !token.quoted {
+		switch token.value {
+		case "not":
+			term, err := p.parseFactor()
+			if err != nil {
+				return nil, err
+			}
+			return func(report map[Node]ComputeState) bool {
+				return !term(report)
+			}, nil
+		case "(":
+			term, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.peekKeyword(")") {
+				return nil, errors.New("missing closing parenthesis")
+			}
+			p.position++
+			return term, nil
+		case ")", "and", "or":
+			return nil, fmt.Errorf("unexpected '%v'", token.value)
+		}
+	}
+
+	if p.position >= len(p.tokens) {
+		return nil, fmt.Errorf("missing state of node '%v'", token.value)
+	}
+	stateToken := p.tokens[p.position]
+	states, found := successReferenceStates[stateToken.value]
+	if stateToken.quoted || !found {
+		return nil, fmt.Errorf("unknown state '%v' of node '%v'", stateToken.value, token.value)
+	}
+	p.position++
+	name := token.value
+	return func(report map[Node]ComputeState) bool {
+		for node, state := range report {
+			if fmt.Sprint(node) != name {
+				continue
+			}
+			for _, expectedState := range states {
+				if state.Value == expectedState {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,103 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigureSuccessCriteria(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenCriteria string
+		expectedError error
+	}{
+		{
+			name:          "Can configure success criteria",
+			givenCriteria: "validate continued and not (store aborted or 'send mail' skipped)",
+		},
+		{
+			name:          "Can't configure success criteria without state",
+			givenCriteria: "validate",
+			expectedError: errors.New("can't parse success criteria 'validate': missing state of node 'validate'"),
+		},
+		{
+			name:          "Can't configure success criteria with unknown state",
+			givenCriteria: "validate done",
+			expectedError: errors.New("can't parse success criteria 'validate done': unknown state 'done' of node 'validate'"),
+		},
+		{
+			name:          "Can't configure success criteria with unbalanced parenthesis",
+			givenCriteria: "(validate continued",
+			expectedError: errors.New("can't parse success criteria '(validate continued': missing closing parenthesis"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewEngine(SequentialComputation).ConfigureSuccessCriteria(testCase.givenCriteria)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_ComputationResult_IsSuccess(t *testing.T) {
+	validate, _ := NewActionNode("validate", func(*Context) error { return nil })
+	notify, _ := NewActionNode("notify", func(c *Context) error {
+		if c.HaveKey("notifyFailure") {
+			return errors.New("can't notify")
+		}
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(validate)
+	ns.AddNode(notify)
+	ns.AddLink(validate, notify)
+	ns.ActivateInPlace()
+
+	testCases := []struct {
+		name            string
+		givenCriteria   string
+		givenData       map[string]interface{}
+		expectedSuccess bool
+	}{
+		{
+			name:            "Can succeed without abort by default",
+			givenData:       map[string]interface{}{},
+			expectedSuccess: true,
+		},
+		{
+			name:      "Can't succeed with abort by default",
+			givenData: map[string]interface{}{"notifyFailure": true},
+		},
+		{
+			name:            "Can succeed with abort on success criteria",
+			givenCriteria:   "validate continued and not validate aborted",
+			givenData:       map[string]interface{}{"notifyFailure": true},
+			expectedSuccess: true,
+		},
+		{
+			name:          "Can't succeed without success criteria",
+			givenCriteria: "notify continued",
+			givenData:     map[string]interface{}{"notifyFailure": true},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			if testCase.givenCriteria != "" {
+				eng.ConfigureSuccessCriteria(testCase.givenCriteria)
+			}
+
+			result := eng.Compute(testCase.givenData)
+
+			if result.IsSuccess() != testCase.expectedSuccess {
+				t.Errorf("got: %+v, want: %+v", result.IsSuccess(), testCase.expectedSuccess)
+			}
+		})
+	}
+}
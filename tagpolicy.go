@@ -0,0 +1,150 @@
+package hoff
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TagPolicy hold the operational policy applied by an engine on the nodes with a tag.
+// A zero value disable the corresponding part of the policy.
+type TagPolicy struct {
+	// Timeout abort a node computing longer, the node is computed on a copy of the context whose Go context
+	// is canceled on timeout (see Context.GoContext), and the changes of a timed out computation are dropped
+	Timeout time.Duration
+	// Retries compute again an aborted node, up to the number of retries
+	Retries int
+	// MaxConcurrency limit the number of simultaneous computations of the nodes across all computations
+	MaxConcurrency int
+	// RatePerSecond limit the number of computations of the nodes started per second
+	RatePerSecond float64
+	// RetryOn limit the retries to the aborted nodes with one of the codes, all codes if empty
+	RetryOn []AbortCode
+	// HedgeAfter compute again a node still running after the delay, on a copy of the context, and keep the first attempt to end
+	HedgeAfter time.Duration
+}
+
+// tagPolicyState hold the policy of a tag and the state needed to apply it.
+type tagPolicyState struct {
+	tag    string
+	policy TagPolicy
+	slots  chan struct{}
+
+	mu        sync.Mutex
+	nextStart time.Time
+}
+
+// ConfigurePolicyOnTag apply a policy on all nodes having a tag.
+// For a node with multiple tags, the policies are applied in the order of its tags.
+func (e *Engine) ConfigurePolicyOnTag(tag string, policy TagPolicy) error {
+	if policy.Timeout < 0 || policy.Retries < 0 || policy.MaxConcurrency < 0 || policy.RatePerSecond < 0 || policy.HedgeAfter < 0 {
+		return fmt.Errorf("can't configure policy with negative values on tag '%v': %+v", tag, policy)
+	}
+	state := &tagPolicyState{
+		tag:    tag,
+		policy: policy,
+	}
+	if policy.MaxConcurrency > 0 {
+		state.slots = make(chan struct{}, policy.MaxConcurrency)
+	}
+	if e.tagsPolicies == nil {
+		e.tagsPolicies = make(map[string]*tagPolicyState)
+	}
+	e.tagsPolicies[tag] = state
+	return nil
+}
+
+func (e *Engine) applyTagPolicies(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	tags := e.system.TagsOfNode(node)
+	for i := len(tags) - 1; i >= 0; i-- {
+		state, found := e.tagsPolicies[tags[i]]
+		if !found {
+			continue
+		}
+		next := compute
+		compute = func(c *Context) ComputeState {
+			return state.apply(node, c, next)
+		}
+	}
+	return compute(c)
+}
+
+func (s *tagPolicyState) apply(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	s.waitRate()
+	if s.slots != nil {
+		s.slots <- struct{}{}
+		defer func() {
+			<-s.slots
+		}()
+	}
+
+	var state ComputeState
+	for attempt := 0; attempt <= s.policy.Retries; attempt++ {
+		state = s.computeWithTimeout(node, c, compute)
+		if state.Value != AbortState || !s.retryOn(state.Code) {
+			break
+		}
+	}
+	return state
+}
+
+// retryOn tell if an aborted node with a code can be computed again.
+func (s *tagPolicyState) retryOn(code AbortCode) bool {
+	if len(s.policy.RetryOn) == 0 {
+		return true
+	}
+	for _, retryCode := range s.policy.RetryOn {
+		if retryCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// waitRate wait until the rate limit allow a new computation to start.
+func (s *tagPolicyState) waitRate() {
+	if s.policy.RatePerSecond == 0 {
+		return
+	}
+	interval := time.Duration(float64(time.Second) / s.policy.RatePerSecond)
+
+	s.mu.Lock()
+	now := time.Now()
+	start := s.nextStart
+	if start.Before(now) {
+		start = now
+	}
+	s.nextStart = start.Add(interval)
+	s.mu.Unlock()
+
+	time.Sleep(start.Sub(now))
+}
+
+// computeWithTimeout compute a node on a branch of the context, merged if the node end before the timeout.
+// A timed out computation is canceled through its Go context, and its cleanup functions run once it end.
+func (s *tagPolicyState) computeWithTimeout(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+	if s.policy.Timeout == 0 {
+		return compute(c)
+	}
+	ctx, cancel := context.WithCancel(c.GoContext())
+	defer cancel()
+	branch := c.branch(ctx)
+	result := make(chan ComputeState, 1)
+	go func() {
+		result <- compute(branch)
+	}()
+	timer := time.NewTimer(s.policy.Timeout)
+	defer timer.Stop()
+	select {
+	case state := <-result:
+		c.merge(branch)
+		return state
+	case <-timer.C:
+		go func() {
+			<-result
+			branch.release()
+		}()
+		return NewAbortWithCodeComputeState(TransientAbort, fmt.Errorf("can't compute node '%v' within the timeout of tag '%v': %v", node, s.tag, s.policy.Timeout))
+	}
+}
@@ -0,0 +1,230 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigurePolicyOnTag(t *testing.T) {
+	eng := NewEngine(SequentialComputation)
+	err := eng.ConfigurePolicyOnTag("slow", TagPolicy{Retries: -1})
+
+	expectedError := errors.New("can't configure policy with negative values on tag 'slow': {Timeout:0s Retries:-1 MaxConcurrency:0 RatePerSecond:0 RetryOn:[] HedgeAfter:0s}")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Engine_Compute_TagPolicy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenPolicy   TagPolicy
+		givenFailures int32
+		givenCode     AbortCode
+		givenDuration time.Duration
+		expectedState ComputeState
+	}{
+		{
+			name:          "Retry an aborted node",
+			givenPolicy:   TagPolicy{Retries: 2},
+			givenFailures: 2,
+			expectedState: NewContinueComputeState(),
+		},
+		{
+			name:          "Abort a node after all retries",
+			givenPolicy:   TagPolicy{Retries: 1},
+			givenFailures: 2,
+			expectedState: NewAbortComputeState(errors.New("failure 2")),
+		},
+		{
+			name:          "Retry an aborted node with a retried code",
+			givenPolicy:   TagPolicy{Retries: 1, RetryOn: []AbortCode{TransientAbort}},
+			givenFailures: 1,
+			givenCode:     TransientAbort,
+			expectedState: NewContinueComputeState(),
+		},
+		{
+			name:          "Don't retry an aborted node without a retried code",
+			givenPolicy:   TagPolicy{Retries: 1, RetryOn: []AbortCode{TransientAbort}},
+			givenFailures: 1,
+			givenCode:     PermanentAbort,
+			expectedState: NewAbortWithCodeComputeState(PermanentAbort, errors.New("failure 1")),
+		},
+		{
+			name:          "Abort a node after the timeout",
+			givenPolicy:   TagPolicy{Timeout: time.Millisecond},
+			givenDuration: 100 * time.Millisecond,
+			expectedState: NewAbortWithCodeComputeState(TransientAbort, errors.New("can't compute node 'externalAction' within the timeout of tag 'external': 1ms")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var attempts int32
+			externalAction, _ := NewActionNode("externalAction", func(*Context) error {
+				attempt := atomic.AddInt32(&attempts, 1)
+				time.Sleep(testCase.givenDuration)
+				if attempt <= testCase.givenFailures {
+					return WithAbortCode(testCase.givenCode, fmt.Errorf("failure %v", attempt))
+				}
+				return nil
+			})
+			ns := NewNodeSystem()
+			ns.AddNode(externalAction)
+			ns.ConfigureTagsOnNode(externalAction, "external")
+			ns.ActivateInPlace()
+
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+			eng.ConfigurePolicyOnTag("external", testCase.givenPolicy)
+
+			result := eng.Compute(make(map[string]interface{}))
+
+			if !cmp.Equal(result.Report[externalAction], testCase.expectedState, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", result.Report[externalAction], testCase.expectedState)
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_TagPolicy_Timeout(t *testing.T) {
+	var attempts int32
+	canceled := make(chan struct{}, 1)
+	externalAction, _ := NewActionNode("externalAction", func(c *Context) error {
+		attempt := atomic.AddInt32(&attempts, 1)
+		if attempt > 1 {
+			c.Store("result", "done")
+			return nil
+		}
+		for {
+			select {
+			case <-c.GoContext().Done():
+				canceled <- struct{}{}
+				return c.GoContext().Err()
+			default:
+				c.Store("progress", attempt)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	})
+	readAction, _ := NewActionNode("readAction", func(c *Context) error {
+		for i := 0; i < 10; i++ {
+			c.Read("progress")
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(externalAction)
+	ns.AddNode(readAction)
+	ns.AddLink(externalAction, readAction)
+	ns.ConfigureTagsOnNode(externalAction, "external")
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigurePolicyOnTag("external", TagPolicy{Timeout: 5 * time.Millisecond, Retries: 1})
+
+	result := eng.Compute(make(map[string]interface{}))
+
+	expectedData := map[string]interface{}{"result": "done"}
+	if !cmp.Equal(result.Data, expectedData) {
+		t.Errorf("data - got: %+v, want: %+v", result.Data, expectedData)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Errorf("timed out attempt - got: running, want: canceled")
+	}
+}
+
+func Test_Engine_Compute_TagPolicy_MaxConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	var mu sync.Mutex
+	newDatabaseAction := func(name string) Node {
+		node, _ := NewActionNode(name, func(*Context) error {
+			current := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			if current > maxRunning {
+				maxRunning = current
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+		return node
+	}
+	readAction, writeAction := newDatabaseAction("readAction"), newDatabaseAction("writeAction")
+	ns := NewNodeSystem()
+	ns.AddNode(readAction)
+	ns.AddNode(writeAction)
+	ns.ConfigureTagsOnNode(readAction, "db")
+	ns.ConfigureTagsOnNode(writeAction, "db")
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigurePolicyOnTag("db", TagPolicy{MaxConcurrency: 1})
+
+	handles := make([]*Handle, 0)
+	for i := 0; i < 4; i++ {
+		handles = append(handles, eng.Submit(make(map[string]interface{}), SubmitOptions{}))
+	}
+	for _, handle := range handles {
+		handle.Wait(context.Background())
+	}
+
+	if maxRunning != 1 {
+		t.Errorf("got: %+v, want: %+v", maxRunning, 1)
+	}
+}
+
+func Test_Engine_Compute_TagPolicy_RatePerSecond(t *testing.T) {
+	externalAction, _ := NewActionNode("externalAction", func(*Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(externalAction)
+	ns.ConfigureTagsOnNode(externalAction, "external")
+	ns.ActivateInPlace()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigurePolicyOnTag("external", TagPolicy{RatePerSecond: 100})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		eng.Compute(make(map[string]interface{}))
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 20*time.Millisecond {
+		t.Errorf("got: %+v, want: at least %+v", elapsed, 20*time.Millisecond)
+	}
+}
+
+func Test_Engine_Compute_TagPolicy_Timeout_externalDelete(t *testing.T) {
+	var attempts int32
+	externalAction, _ := NewActionNode("externalAction", func(c *Context) error {
+		if atomic.AddInt32(&attempts, 1) > 1 {
+			return nil
+		}
+		c.Delete("big")
+		<-c.GoContext().Done()
+		return c.GoContext().Err()
+	})
+
+	result, _ := computeWithExternalValue(externalAction, func(ns *NodeSystem, eng *Engine) {
+		ns.ConfigureTagsOnNode(externalAction, "external")
+		eng.ConfigurePolicyOnTag("external", TagPolicy{Timeout: 5 * time.Millisecond, Retries: 1})
+	})
+
+	if result.Error != nil || result.Data["read"] != "payload" {
+		t.Errorf("got: %+v %+v, want: %+v", result.Error, result.Data["read"], "payload")
+	}
+}
@@ -0,0 +1,108 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrTenantQuotaExceeded is the error when the computations of a tenant reach its quota (see TenantQuota).
+var ErrTenantQuotaExceeded = errors.New("can't submit computation, tenant quota exceeded")
+
+// TenantQuota limit the resources of an engine used by the computations of a tenant (see SubmitOptions.Tenant),
+// so a shared engine can serve several teams without one starving the others.
+type TenantQuota struct {
+	// MaxComputations is the maximum number of submitted computations of the tenant not ended, 0 for no limit
+	MaxComputations int
+	// WorkerShare is the maximum number of nodes of the tenant computing at once,
+	// e.g. the share of the workers of the engine worker pool, 0 for no limit
+	WorkerShare int
+}
+
+// TenantMetrics hold the state of the computations of a tenant.
+type TenantMetrics struct {
+	Tenant string
+	// Running is the number of submitted computations not ended
+	Running int
+	// Rejected is the number of computations rejected by the quota of the tenant
+	Rejected uint64
+}
+
+// ConfigureTenantQuota limit the computations of a tenant, the empty tenant being the computations without tenant.
+func (e *Engine) ConfigureTenantQuota(tenant string, quota TenantQuota) error {
+	if quota.MaxComputations < 0 || quota.WorkerShare < 0 {
+		return fmt.Errorf("can't configure negative quota of tenant '%v'", tenant)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tenantQuotas == nil {
+		e.tenantQuotas = make(map[string]TenantQuota)
+		e.tenantSlots = make(map[string]chan struct{})
+	}
+	e.tenantQuotas[tenant] = quota
+	delete(e.tenantSlots, tenant)
+	if quota.WorkerShare > 0 {
+		e.tenantSlots[tenant] = make(chan struct{}, quota.WorkerShare)
+	}
+	return nil
+}
+
+// TenantMetrics give the state of the computations of each tenant with submitted computations, sorted by tenant.
+func (e *Engine) TenantMetrics() []TenantMetrics {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	tenants := make(map[string]bool)
+	for tenant := range e.tenantRunning {
+		tenants[tenant] = true
+	}
+	for tenant := range e.tenantRejected {
+		tenants[tenant] = true
+	}
+	metrics := make([]TenantMetrics, 0, len(tenants))
+	for tenant := range tenants {
+		metrics = append(metrics, TenantMetrics{Tenant: tenant, Running: e.tenantRunning[tenant], Rejected: e.tenantRejected[tenant]})
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Tenant < metrics[j].Tenant
+	})
+	return metrics
+}
+
+// admitTenant count a submitted computation of a tenant, and give the function to call once ended,
+// or give an ErrTenantQuotaExceeded.
+func (e *Engine) admitTenant(tenant string) (func(), error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tenantRunning == nil {
+		e.tenantRunning = make(map[string]int)
+		e.tenantRejected = make(map[string]uint64)
+	}
+	if max := e.tenantQuotas[tenant].MaxComputations; max > 0 && e.tenantRunning[tenant] >= max {
+		e.tenantRejected[tenant]++
+		return nil, fmt.Errorf("%w: '%v' have %v computations", ErrTenantQuotaExceeded, tenant, max)
+	}
+	e.tenantRunning[tenant]++
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.tenantRunning[tenant]--
+	}, nil
+}
+
+// tenantShare give the slots of the nodes of a tenant computing at once, nil without limit.
+func (e *Engine) tenantShare(tenant string) chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.tenantSlots[tenant]
+}
+
+// limitTenantShare give the interceptor limiting the nodes of a tenant computing at once.
+func limitTenantShare(slots chan struct{}) nodeInterceptor {
+	return func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+		slots <- struct{}{}
+		defer func() {
+			<-slots
+		}()
+		return compute(c)
+	}
+}
@@ -0,0 +1,140 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigureTenantQuota(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenQuota    TenantQuota
+		expectedError error
+	}{
+		{
+			name:       "Can configure a quota",
+			givenQuota: TenantQuota{MaxComputations: 2, WorkerShare: 1},
+		},
+		{
+			name:          "Can't configure a negative quota",
+			givenQuota:    TenantQuota{MaxComputations: -1},
+			expectedError: errors.New("can't configure negative quota of tenant 'team-a'"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewEngine(SequentialComputation).ConfigureTenantQuota("team-a", testCase.givenQuota)
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Engine_TrySubmit_tenantQuota(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	blocking, _ := NewActionNode("blocking", func(*Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(blocking)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureTenantQuota("team-a", TenantQuota{MaxComputations: 1})
+
+	first, err := eng.TrySubmit(context.Background(), nil, SubmitOptions{Tenant: "team-a"})
+	if err != nil {
+		t.Fatalf("first error - got: %+v, want: nothing", err)
+	}
+	<-started
+	_, err = eng.TrySubmit(context.Background(), nil, SubmitOptions{Tenant: "team-a"})
+	if !errors.Is(err, ErrTenantQuotaExceeded) {
+		t.Errorf("second error - got: %+v, want: %+v", err, ErrTenantQuotaExceeded)
+	}
+	other, err := eng.TrySubmit(context.Background(), nil, SubmitOptions{Tenant: "team-b"})
+	if err != nil {
+		t.Errorf("other tenant error - got: %+v, want: nothing", err)
+	}
+	<-started
+
+	expectedMetrics := []TenantMetrics{{Tenant: "team-a", Running: 1, Rejected: 1}, {Tenant: "team-b", Running: 1}}
+	if metrics := eng.TenantMetrics(); !cmp.Equal(metrics, expectedMetrics) {
+		t.Errorf("metrics - got: %+v, want: %+v", metrics, expectedMetrics)
+	}
+	close(release)
+	result, _ := first.Wait(context.Background())
+	other.Wait(context.Background())
+	if result.Tenant != "team-a" {
+		t.Errorf("tenant - got: %+v, want: %+v", result.Tenant, "team-a")
+	}
+	if _, err = eng.TrySubmit(context.Background(), nil, SubmitOptions{Tenant: "team-a"}); err != nil {
+		t.Errorf("error once ended - got: %+v, want: nothing", err)
+	}
+}
+
+func Test_Engine_ConfigureTenantQuota_workerShare(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	slow, _ := NewActionNode("slow", func(*Context) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(slow)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureWorkerPool(4)
+	eng.ConfigureTenantQuota("team-a", TenantQuota{WorkerShare: 1})
+
+	handles := make([]*Handle, 0)
+	for i := 0; i < 3; i++ {
+		handles = append(handles, eng.Submit(nil, SubmitOptions{Tenant: "team-a"}))
+	}
+	for _, handle := range handles {
+		handle.Wait(context.Background())
+	}
+
+	if maxRunning != 1 {
+		t.Errorf("max running nodes - got: %+v, want: %+v", maxRunning, 1)
+	}
+}
+
+func Test_Engine_tenantEvents(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	sink := &recordingEventSink{}
+	eng.AddEventSink(sink)
+
+	eng.Submit(nil, SubmitOptions{Tenant: "team-a"}).Wait(context.Background())
+
+	if len(sink.events) != 4 {
+		t.Errorf("events - got: %+v, want: 4 events", sink.events)
+	}
+	for _, event := range sink.events {
+		if event.Tenant != "team-a" {
+			t.Errorf("tenant of %v - got: %+v, want: %+v", event.Type, event.Tenant, "team-a")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConfigureAsTerminal mark a node as a terminal node into the system before activation,
+// a successful end state of the computations who can't have links from it.
+// Once a node is marked, only the marked nodes are terminal nodes.
+func (s *NodeSystem) ConfigureAsTerminal(n Node) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't mark node as terminal, node system is freeze due to activation")
+	}
+	if s.terminalNodes == nil {
+		s.terminalNodes = make(map[string]bool)
+	}
+	s.terminalNodes[s.nodeID(n)] = true
+	return true, nil
+}
+
+// IsTerminal tell if a node is a terminal node, marked as terminal or without links from it
+// when no node is marked.
+func (s *NodeSystem) IsTerminal(n Node) bool {
+	if len(s.terminalNodes) > 0 {
+		return s.terminalNodes[s.nodeID(n)]
+	}
+	for _, link := range s.links {
+		if s.sameNode(link.From, n) {
+			return false
+		}
+	}
+	return s.haveNode(n)
+}
+
+// TerminalNodes get the terminal nodes after activation.
+func (s *NodeSystem) TerminalNodes() ([]Node, error) {
+	if !s.activated {
+		return nil, errors.New("can't get terminal nodes if system is not activated")
+	}
+	nodes := make([]Node, 0)
+	for _, node := range s.nodes {
+		if s.IsTerminal(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// DanglingNodes get the nodes ending a path of a computation without being terminal,
+// who continue on a branch without following nodes, or whose following nodes are not computed.
+func (s *NodeSystem) DanglingNodes(result ComputationResult) []Node {
+	nodes := make([]Node, 0)
+	for _, node := range s.nodes {
+		state, found := result.Report[s.nodeMapKey(node)]
+		if !found || state.Value != ContinueState || s.IsTerminal(node) {
+			continue
+		}
+		followingNodes, _ := s.Follow(node, state.Branch)
+		dangling := true
+		for _, followingNode := range followingNodes {
+			if _, computed := result.Report[s.nodeMapKey(followingNode)]; computed {
+				dangling = false
+				break
+			}
+		}
+		if dangling {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// ReachedTerminal tell if a computation reached a successful end state,
+// ended without error on terminal nodes only.
+func (s *NodeSystem) ReachedTerminal(result ComputationResult) bool {
+	if result.Error != nil || result.IsAborted() || len(result.PausedTokens()) > 0 {
+		return false
+	}
+	reached := false
+	for _, node := range s.nodes {
+		if state, found := result.Report[s.nodeMapKey(node)]; found && state.Value == ContinueState && s.IsTerminal(node) {
+			reached = true
+		}
+	}
+	return reached && len(s.DanglingNodes(result)) == 0
+}
+
+func checkForLinkFromTerminalNode(s *NodeSystem) []error {
+	errors := make([]error, 0)
+	for _, link := range s.links {
+		if s.terminalNodes[s.nodeID(link.From)] {
+			errors = append(errors, fmt.Errorf("can't have link from terminal node: %v", link))
+		}
+	}
+	return errors
+}
@@ -0,0 +1,133 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_NodeSystem_ConfigureAsTerminal(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.ConfigureAsTerminal(someActionNode)
+
+	_, validationErr := ns.IsValid()
+
+	errs := validationErrors(validationErr)
+
+	expectedErrors := []error{fmt.Errorf("can't have link from terminal node: %v", newNodeLink(someActionNode, anotherActionNode))}
+	if !cmp.Equal(errs, expectedErrors, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", errs, expectedErrors)
+	}
+
+	ns = NewNodeSystem()
+	ns.ActivateInPlace()
+	_, err := ns.ConfigureAsTerminal(someActionNode)
+	expectedError := errors.New("can't mark node as terminal, node system is freeze due to activation")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("activated - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_NodeSystem_TerminalNodes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenTerminals []Node
+		expectedNodes  []Node
+	}{
+		{
+			name:          "Can have the nodes without links from them as terminal nodes",
+			expectedNodes: []Node{someActionNode, anotherActionNode},
+		},
+		{
+			name:           "Can have the marked nodes as terminal nodes",
+			givenTerminals: []Node{anotherActionNode},
+			expectedNodes:  []Node{anotherActionNode},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(alwaysTrueDecisionNode)
+			ns.AddNode(someActionNode)
+			ns.AddNode(anotherActionNode)
+			ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+			ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+			for _, node := range testCase.givenTerminals {
+				ns.ConfigureAsTerminal(node)
+			}
+			_, err := ns.TerminalNodes()
+			if err == nil {
+				t.Errorf("not activated - got: %+v, want: an error", err)
+			}
+			ns.ActivateInPlace()
+
+			nodes, _ := ns.TerminalNodes()
+
+			if !cmp.Equal(nodes, testCase.expectedNodes, NodeComparator) {
+				t.Errorf("got: %+v, want: %+v", nodes, testCase.expectedNodes)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_ReachedTerminal(t *testing.T) {
+	isValid, _ := NewDecisionNode("isValid", func(c *Context) (bool, error) {
+		return c.HaveKey("valid"), nil
+	})
+	store, _ := NewActionNode("store", func(*Context) error { return nil })
+	failing, _ := NewActionNode("failing", func(*Context) error { return errors.New("failure") })
+
+	testCases := []struct {
+		name             string
+		givenNextNode    Node
+		givenData        map[string]interface{}
+		expectedDangling []Node
+		expectedReached  bool
+	}{
+		{
+			name:            "Can reach a terminal node",
+			givenNextNode:   store,
+			givenData:       map[string]interface{}{"valid": true},
+			expectedReached: true,
+		},
+		{
+			name:             "Can end on a branch without following nodes",
+			givenNextNode:    store,
+			givenData:        map[string]interface{}{},
+			expectedDangling: []Node{isValid},
+		},
+		{
+			name:          "Can't reach a terminal node on abort",
+			givenNextNode: failing,
+			givenData:     map[string]interface{}{"valid": true},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(isValid)
+			ns.AddNode(testCase.givenNextNode)
+			ns.AddLinkOnBranch(isValid, testCase.givenNextNode, true)
+			ns.ConfigureAsTerminal(testCase.givenNextNode)
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+
+			result := eng.Compute(testCase.givenData)
+
+			dangling := ns.DanglingNodes(result)
+			if !cmp.Equal(dangling, testCase.expectedDangling, NodeComparator, cmpopts.EquateEmpty()) {
+				t.Errorf("dangling - got: %+v, want: %+v", dangling, testCase.expectedDangling)
+			}
+			if ns.ReachedTerminal(result) != testCase.expectedReached {
+				t.Errorf("reached - got: %+v, want: %+v", ns.ReachedTerminal(result), testCase.expectedReached)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Transform compute a context key from an expression over the context data (see Expression).
+type Transform struct {
+	Key        string `json:"key"`
+	Expression string `json:"expression"`
+}
+
+// ExpressionError is the error of an expression of a node who can't be compiled or evaluated.
+type ExpressionError struct {
+	Node       string
+	Key        string
+	Expression string
+	Err        error
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("can't transform key '%v' of node '%v': %v", e.Key, e.Node, e.Err)
+}
+
+// Unwrap give the cause of the error.
+func (e *ExpressionError) Unwrap() error {
+	return e.Err
+}
+
+// expressionsNode is a Node with expressions compiled on activation of the node system.
+type expressionsNode interface {
+	Node
+	compileExpressions() []error
+}
+
+// TransformNode is a type of Node who compute new context values from expressions.
+// The expressions are compiled on activation, and evaluated in order,
+// a transform can read the key of a previous transform.
+// The expressions are written in the hoff expression language described by Expression.
+type TransformNode struct {
+	name       string
+	transforms []Transform
+
+	mu          sync.Mutex
+	compiled    []*Expression
+	compileErrs []error
+}
+
+func (n *TransformNode) String() string {
+	return n.name
+}
+
+// Compute evaluate the expressions and store their values,
+// no value is stored if an expression fail.
+func (n *TransformNode) Compute(c *Context) ComputeState {
+	if errs := n.compileExpressions(); len(errs) > 0 {
+		return NewAbortComputeState(errs[0])
+	}
+	values := make(map[string]interface{}, len(n.transforms))
+	read := func(key string) (interface{}, bool) {
+		if value, found := values[key]; found {
+			return value, true
+		}
+		return c.Read(key)
+	}
+	for i, transform := range n.transforms {
+		value, err := n.compiled[i].evalRead(read)
+		if err != nil {
+			return NewAbortComputeState(n.expressionError(transform, err))
+		}
+		values[transform.Key] = value
+	}
+	for _, transform := range n.transforms {
+		c.Store(transform.Key, values[transform.Key])
+	}
+	return NewContinueComputeState()
+}
+
+// DecideCapability is desactived due to the fact that a transform don't take a decision.
+func (n *TransformNode) DecideCapability() bool {
+	return false
+}
+
+// Transforms give the transforms of the node.
+func (n *TransformNode) Transforms() []Transform {
+	return append([]Transform(nil), n.transforms...)
+}
+
+// RequiredKeys give the context keys read by the expressions, without the keys of the previous transforms.
+func (n *TransformNode) RequiredKeys() []string {
+	n.compileExpressions()
+	produced := make(map[string]bool, len(n.transforms))
+	required := make([]string, 0)
+	seen := make(map[string]bool)
+	for i, transform := range n.transforms {
+		if i < len(n.compiled) && n.compiled[i] != nil {
+			for _, key := range n.compiled[i].Keys() {
+				if !produced[key] && !seen[key] {
+					seen[key] = true
+					required = append(required, key)
+				}
+			}
+		}
+		produced[transform.Key] = true
+	}
+	return required
+}
+
+// ProducedKeys give the keys of the transforms.
+func (n *TransformNode) ProducedKeys() []string {
+	keys := make([]string, 0, len(n.transforms))
+	for _, transform := range n.transforms {
+		keys = append(keys, transform.Key)
+	}
+	return keys
+}
+
+// compileExpressions parse the expressions once, and give their errors.
+func (n *TransformNode) compileExpressions() []error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.compiled != nil {
+		return n.compileErrs
+	}
+	n.compiled = make([]*Expression, len(n.transforms))
+	for i, transform := range n.transforms {
+		expression, err := ParseExpression(transform.Expression)
+		if err != nil {
+			n.compileErrs = append(n.compileErrs, n.expressionError(transform, err))
+			continue
+		}
+		n.compiled[i] = expression
+	}
+	return n.compileErrs
+}
+
+func (n *TransformNode) expressionError(transform Transform, err error) error {
+	return &ExpressionError{Node: n.name, Key: transform.Key, Expression: transform.Expression, Err: err}
+}
+
+// NewTransformNode create a TransformNode based on a name and its transforms,
+// the expressions are compiled on activation of the node system.
+func NewTransformNode(name string, transforms ...Transform) (*TransformNode, error) {
+	if len(transforms) == 0 {
+		return nil, errors.New("can't create transform node without transform")
+	}
+	keys := make(map[string]bool, len(transforms))
+	for _, transform := range transforms {
+		if transform.Key == "" {
+			return nil, errors.New("can't create transform node with transform without key")
+		}
+		if keys[transform.Key] {
+			return nil, fmt.Errorf("can't create transform node with key '%v' transformed twice", transform.Key)
+		}
+		keys[transform.Key] = true
+	}
+	return &TransformNode{name: name, transforms: append([]Transform(nil), transforms...)}, nil
+}
+
+func checkForInvalidExpressions(s *NodeSystem) []error {
+	errs := make([]error, 0)
+	for _, node := range s.nodes {
+		if expressions, ok := node.(expressionsNode); ok {
+			errs = append(errs, expressions.compileExpressions()...)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,169 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewTransformNode(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenTransforms []Transform
+		expectedError   error
+	}{
+		{
+			name:            "Can create a transform node",
+			givenTransforms: []Transform{{Key: "total", Expression: "price * quantity"}},
+		},
+		{
+			name:            "Can create a transform node with invalid expression, compiled on activation",
+			givenTransforms: []Transform{{Key: "total", Expression: "price *"}},
+		},
+		{
+			name:          "Can't create a transform node without transform",
+			expectedError: errors.New("can't create transform node without transform"),
+		},
+		{
+			name:            "Can't create a transform node with transform without key",
+			givenTransforms: []Transform{{Expression: "1"}},
+			expectedError:   errors.New("can't create transform node with transform without key"),
+		},
+		{
+			name:            "Can't create a transform node with key transformed twice",
+			givenTransforms: []Transform{{Key: "total", Expression: "1"}, {Key: "total", Expression: "2"}},
+			expectedError:   errors.New("can't create transform node with key 'total' transformed twice"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewTransformNode("transform", testCase.givenTransforms...)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err == nil && !cmp.Equal(node.Transforms(), testCase.givenTransforms) {
+				t.Errorf("transforms - got: %+v, want: %+v", node.Transforms(), testCase.givenTransforms)
+			}
+		})
+	}
+}
+
+func Test_TransformNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenTransforms []Transform
+		givenData       map[string]interface{}
+		expectedState   ComputeState
+		expectedData    map[string]interface{}
+	}{
+		{
+			name: "Can compute transforms in order",
+			givenTransforms: []Transform{
+				{Key: "total", Expression: "price * quantity"},
+				{Key: "label", Expression: `customer.name + ": " + string(total)`},
+			},
+			givenData:     map[string]interface{}{"price": 2.5, "quantity": 4, "customer": map[string]interface{}{"name": "bob"}},
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"price": 2.5, "quantity": 4, "customer": map[string]interface{}{"name": "bob"}, "total": 10.0, "label": "bob: 10"},
+		},
+		{
+			name: "Can't compute transforms with a failing expression",
+			givenTransforms: []Transform{
+				{Key: "total", Expression: "price * quantity"},
+				{Key: "label", Expression: "customer.name"},
+			},
+			givenData:     map[string]interface{}{"price": 2.5, "quantity": 4},
+			expectedState: NewAbortComputeState(errors.New("can't transform key 'label' of node 'transform': can't evaluate expression 'customer.name': can't find key: customer.name")),
+			expectedData:  map[string]interface{}{"price": 2.5, "quantity": 4},
+		},
+		{
+			name:            "Can't compute transforms with invalid expression",
+			givenTransforms: []Transform{{Key: "total", Expression: "price *"}},
+			givenData:       map[string]interface{}{"price": 2.5},
+			expectedState:   NewAbortComputeState(errors.New("can't transform key 'total' of node 'transform': can't parse expression 'price *': unexpected end of expression")),
+			expectedData:    map[string]interface{}{"price": 2.5},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, _ := NewTransformNode("transform", testCase.givenTransforms...)
+			c := NewContext(testCase.givenData)
+
+			state := node.Compute(c)
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_TransformNode_Compute_expressionError(t *testing.T) {
+	node, _ := NewTransformNode("transform", Transform{Key: "total", Expression: "price * quantity"})
+
+	state := node.Compute(NewContext(map[string]interface{}{"price": 2.5}))
+
+	var expressionErr *ExpressionError
+	if !errors.As(state.Error, &expressionErr) {
+		t.Fatalf("got: %+v, want: an expression error", state.Error)
+	}
+	expected := ExpressionError{Node: "transform", Key: "total", Expression: "price * quantity"}
+	if expressionErr.Node != expected.Node || expressionErr.Key != expected.Key || expressionErr.Expression != expected.Expression {
+		t.Errorf("got: %+v, want: %+v", expressionErr, expected)
+	}
+}
+
+func Test_TransformNode_Keys(t *testing.T) {
+	node, _ := NewTransformNode("transform",
+		Transform{Key: "total", Expression: "price * quantity"},
+		Transform{Key: "discounted", Expression: "total * (1 - discount)"},
+	)
+
+	required, produced := node.RequiredKeys(), node.ProducedKeys()
+
+	expectedRequired, expectedProduced := []string{"price", "quantity", "discount"}, []string{"total", "discounted"}
+	if !cmp.Equal(required, expectedRequired) || !cmp.Equal(produced, expectedProduced) {
+		t.Errorf("got: %+v %+v, want: %+v %+v", required, produced, expectedRequired, expectedProduced)
+	}
+}
+
+func Test_NodeSystem_Activate_invalidExpression(t *testing.T) {
+	invalid, _ := NewTransformNode("transform", Transform{Key: "total", Expression: "price *"}, Transform{Key: "count", Expression: "size("})
+	ns := NewNodeSystem()
+	ns.AddNode(invalid)
+
+	err := ns.ActivateInPlace()
+
+	expectedErrors := []error{
+		errors.New("can't transform key 'total' of node 'transform': can't parse expression 'price *': unexpected end of expression"),
+		errors.New("can't transform key 'count' of node 'transform': can't parse expression 'size(': unexpected end of expression"),
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !cmp.Equal(validationErr.Errors, expectedErrors, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedErrors)
+	}
+}
+
+func Test_TransformNode_Describe(t *testing.T) {
+	node, _ := NewTransformNode("transform", Transform{Key: "total", Expression: "price * quantity"})
+	ns := NewNodeSystem()
+	ns.AddNode(node)
+
+	description := ns.Describe()
+	expectedNode := NodeDescription{Name: "transform", Transforms: []Transform{{Key: "total", Expression: "price * quantity"}}}
+	if !cmp.Equal(description.Nodes[0], expectedNode) {
+		t.Errorf("describe - got: %+v, want: %+v", description.Nodes[0], expectedNode)
+	}
+
+	buffer, _ := description.MarshalProto()
+	var unmarshaled SystemDescription
+	unmarshaled.UnmarshalProto(buffer)
+	if !cmp.Equal(unmarshaled.Nodes[0], expectedNode) {
+		t.Errorf("proto - got: %+v, want: %+v", unmarshaled.Nodes[0], expectedNode)
+	}
+}
@@ -0,0 +1,119 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// DeclareTrigger declare a named entry point of the system before activation, as a subset of its initial nodes,
+// to compute only from them (see Engine.ComputeFrom). The nodes following only the other initial nodes are skipped.
+// Declaring again a trigger replace its nodes.
+func (s *NodeSystem) DeclareTrigger(name string, nodes ...Node) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't declare trigger, node system is freeze due to activation")
+	}
+	if name == "" {
+		return false, errors.New("can't declare trigger without name")
+	}
+	if len(nodes) == 0 {
+		return false, fmt.Errorf("can't declare trigger '%v' without nodes", name)
+	}
+	for _, node := range nodes {
+		if node == nil {
+			return false, fmt.Errorf("can't declare trigger '%v' with nil node", name)
+		}
+	}
+	if s.triggers == nil {
+		s.triggers = make(map[string][]Node)
+	}
+	s.triggers[name] = append([]Node(nil), nodes...)
+	return true, nil
+}
+
+// TriggerNodes get the nodes of a trigger.
+func (s *NodeSystem) TriggerNodes(name string) ([]Node, bool) {
+	nodes, found := s.triggers[name]
+	return nodes, found
+}
+
+// Triggers get the names of the triggers, sorted.
+func (s *NodeSystem) Triggers() []string {
+	names := make([]string, 0, len(s.triggers))
+	for name := range s.triggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComputeFrom run the nodes from the initial nodes of a trigger, the other initial nodes are skipped.
+func (cp *Computation) ComputeFrom(trigger string) error {
+	nodes, found := cp.System.TriggerNodes(trigger)
+	if !found {
+		return fmt.Errorf("can't compute from unknown trigger: %v", trigger)
+	}
+	cp.entryNodes, cp.scope = nil, nil
+	cp.Report = make(map[Node]ComputeState)
+	atomic.StoreInt32(&cp.completedNodes, 0)
+	for _, node := range cp.System.InitialNodes() {
+		if containsNode(cp.System, nodes, node) {
+			continue
+		}
+		cp.recordState(node, NewSkipComputeState())
+		err := cp.computeFollowingNodes(node, nodeBranches(node)...)
+		if err != nil {
+			return err
+		}
+	}
+	err := cp.computeNodes(nodes)
+	if err != nil {
+		return err
+	}
+	cp.Status = !cp.IsPaused()
+	return nil
+}
+
+// ComputeFrom run computation against node system with input data, from the initial nodes of a trigger.
+func (e *Engine) ComputeFrom(trigger string, data map[string]interface{}) ComputationResult {
+	return e.compute(context.Background(), data, SubmitOptions{Trigger: trigger}, nil)
+}
+
+// isInitialCandidate tell if a node is an initial node, before activation.
+func (s *NodeSystem) isInitialCandidate(n Node) bool {
+	if len(s.declaredInitialNodes) > 0 {
+		return s.isDeclaredInitialNode(n)
+	}
+	for _, link := range s.links {
+		if s.sameNode(link.To, n) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsNode(s *NodeSystem, nodes []Node, n Node) bool {
+	for _, node := range nodes {
+		if s.sameNode(node, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkForInvalidTriggers check the nodes of the triggers are declared initial nodes.
+func checkForInvalidTriggers(s *NodeSystem) []error {
+	errs := make([]error, 0)
+	for _, name := range s.Triggers() {
+		for _, node := range s.triggers[name] {
+			if !s.haveNode(node) {
+				errs = append(errs, fmt.Errorf("can't have trigger '%v' on undeclared node: %v", name, node))
+			} else if !s.isInitialCandidate(node) {
+				errs = append(errs, fmt.Errorf("can't have trigger '%v' on not initial node: %v", name, node))
+			}
+		}
+	}
+	return errs
+}
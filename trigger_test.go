@@ -0,0 +1,131 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_DeclareTrigger(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenSystem    func() *NodeSystem
+		givenName      string
+		givenNodes     []Node
+		expectedResult bool
+		expectedError  error
+	}{
+		{
+			name:           "Can declare a trigger",
+			givenSystem:    NewNodeSystem,
+			givenName:      "created",
+			givenNodes:     []Node{someActionNode},
+			expectedResult: true,
+		},
+		{
+			name:          "Can't declare a trigger without name",
+			givenSystem:   NewNodeSystem,
+			givenNodes:    []Node{someActionNode},
+			expectedError: errors.New("can't declare trigger without name"),
+		},
+		{
+			name:          "Can't declare a trigger without nodes",
+			givenSystem:   NewNodeSystem,
+			givenName:     "created",
+			expectedError: errors.New("can't declare trigger 'created' without nodes"),
+		},
+		{
+			name:          "Can't declare a trigger with nil node",
+			givenSystem:   NewNodeSystem,
+			givenName:     "created",
+			givenNodes:    []Node{nil},
+			expectedError: errors.New("can't declare trigger 'created' with nil node"),
+		},
+		{
+			name:          "Can't declare a trigger after activation",
+			givenSystem:   activatedNodeSystem,
+			givenName:     "created",
+			givenNodes:    []Node{someActionNode},
+			expectedError: errors.New("can't declare trigger, node system is freeze due to activation"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := testCase.givenSystem().DeclareTrigger(testCase.givenName, testCase.givenNodes...)
+
+			if result != testCase.expectedResult {
+				t.Errorf("result - got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_checkForInvalidTriggers(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.DeclareTrigger("created", someActionNode, anotherActionNode, alwaysTrueDecisionNode)
+
+	errs := checkForInvalidTriggers(ns)
+	expectedErrors := []error{
+		fmt.Errorf("can't have trigger 'created' on not initial node: %v", anotherActionNode),
+		fmt.Errorf("can't have trigger 'created' on undeclared node: %v", alwaysTrueDecisionNode),
+	}
+	if !cmp.Equal(errs, expectedErrors, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", errs, expectedErrors)
+	}
+}
+
+func Test_Engine_ComputeFrom(t *testing.T) {
+	onCreated, _ := NewActionNode("onCreated", func(c *Context) error {
+		c.Store("event", "created")
+		return nil
+	})
+	onDeleted, _ := NewActionNode("onDeleted", func(c *Context) error {
+		c.Store("event", "deleted")
+		return nil
+	})
+	notify, _ := NewActionNode("notify", func(c *Context) error {
+		c.Store("notified", true)
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(onCreated)
+	ns.AddNode(onDeleted)
+	ns.AddNode(notify)
+	ns.AddLink(onCreated, notify)
+	ns.AddLink(onDeleted, notify)
+	ns.ConfigureJoinModeOnNode(notify, JoinOr)
+	ns.DeclareTrigger("created", onCreated)
+	ns.DeclareTrigger("deleted", onDeleted)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	result := eng.ComputeFrom("deleted", map[string]interface{}{})
+	expectedResult := ComputationResult{
+		Data: map[string]interface{}{"event": "deleted", "notified": true},
+		Report: map[Node]ComputeState{
+			onCreated: NewSkipComputeState(),
+			onDeleted: NewContinueComputeState(),
+			notify:    NewContinueComputeState(),
+		},
+		Success: true,
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator, computationResultGeneratedFieldsIgnorer) {
+		t.Errorf("got: %+v, want: %+v", result, expectedResult)
+	}
+
+	result = eng.ComputeFrom("updated", map[string]interface{}{})
+	expectedError := errors.New("can't compute from unknown trigger: updated")
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("unknown trigger - got: %+v, want: %+v", result.Error, expectedError)
+	}
+}
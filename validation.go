@@ -0,0 +1,225 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ValidationCheck is the name of a check run to validate a node system.
+type ValidationCheck string
+
+const (
+	// OrphanDecisionNodeCheck check for decision node without node links from it
+	OrphanDecisionNodeCheck ValidationCheck = "orphan-decision-node"
+	// CycleCheck check for cyclic redundancy in node links
+	CycleCheck ValidationCheck = "cycle"
+	// UndeclaredNodeCheck check for undeclared node used in node links
+	UndeclaredNodeCheck ValidationCheck = "undeclared-node"
+	// MultipleInstanceCheck check for multiple declaration of same node instance
+	MultipleInstanceCheck ValidationCheck = "multiple-instance"
+	// MultipleLinksCheck check for multiple node links to a node without join mode
+	MultipleLinksCheck ValidationCheck = "multiple-links"
+	// PortLinkCheck check for port links with undeclared or not assignable ports
+	PortLinkCheck ValidationCheck = "port-link"
+	// JoinExpressionCheck check for join expressions referencing not linked nodes
+	JoinExpressionCheck ValidationCheck = "join-expression"
+	// TerminalNodeCheck check for links from terminal nodes
+	TerminalNodeCheck ValidationCheck = "terminal-node"
+	// InitialNodeCheck check for undeclared nodes declared as initial nodes, or nodes never computed
+	// without links to them when initial nodes are declared
+	InitialNodeCheck ValidationCheck = "initial-node"
+	// TriggerCheck check for triggers on undeclared or not initial nodes
+	TriggerCheck ValidationCheck = "trigger"
+	// ExpressionCheck check for expressions of nodes who can't be compiled
+	ExpressionCheck ValidationCheck = "expression"
+	// ConcurrentWriteCheck check for nodes who may run concurrently and write the same declared context key,
+	// reported as warnings unless upgraded to errors
+	ConcurrentWriteCheck ValidationCheck = "concurrent-write"
+)
+
+// validationChecks hold the checks of a node system, in the order of their errors.
+var validationChecks = []struct {
+	name  ValidationCheck
+	check func(*NodeSystem) []error
+}{
+	{OrphanDecisionNodeCheck, checkForOrphanMultiBranchesNode},
+	{CycleCheck, checkForCyclicRedundancyInNodeLinks},
+	{UndeclaredNodeCheck, checkForUndeclaredNodeInNodeLink},
+	{MultipleInstanceCheck, checkForMultipleInstanceOfSameNode},
+	{MultipleLinksCheck, checkForMultipleLinksToNodeWithoutJoinMode},
+	{PortLinkCheck, checkForInvalidPortLinks},
+	{JoinExpressionCheck, checkForUnlinkedReferenceInJoinExpression},
+	{TerminalNodeCheck, checkForLinkFromTerminalNode},
+	{InitialNodeCheck, checkForInconsistentInitialNodes},
+	{TriggerCheck, checkForInvalidTriggers},
+	{ExpressionCheck, checkForInvalidExpressions},
+	{ConcurrentWriteCheck, checkForConcurrentWrites},
+}
+
+// warningValidationChecks hold the checks whose errors are warnings by default.
+var warningValidationChecks = []ValidationCheck{ConcurrentWriteCheck}
+
+// ValidationConfig select the checks run to validate a node system, e.g. to iterate faster in tooling.
+// A node system is only activated when valid against all checks.
+type ValidationConfig struct {
+	// Checks is the checks to run, all checks if empty
+	Checks []ValidationCheck
+	// Omit is the checks to not run
+	Omit []ValidationCheck
+	// Sequential run the checks one after the other instead of concurrently
+	Sequential bool
+	// Warnings is the checks whose errors are downgraded to warnings, e.g. in development mode
+	Warnings []ValidationCheck
+	// Errors is the checks reported as warnings by default (like ConcurrentWriteCheck) whose warnings are upgraded to errors
+	Errors []ValidationCheck
+}
+
+// ValidationResult hold the errors, and the warnings of the checks downgraded to warnings.
+type ValidationResult struct {
+	Errors   []error
+	Warnings []error
+}
+
+// IsValid tell if there is no errors, whatever the warnings.
+func (r ValidationResult) IsValid() bool {
+	return len(r.Errors) == 0
+}
+
+// ValidationError hold the errors of an invalid node system,
+// each one can be found with errors.Is or errors.As.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap give the errors of the node system.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// IsValidWith check if the configuration of the node system is valid based on the checks of a configuration,
+// the warnings are ignored. The errors are given as a ValidationError.
+func (s *NodeSystem) IsValidWith(config ValidationConfig) (bool, error) {
+	result := s.Validate(config)
+	if result.IsValid() {
+		return true, nil
+	}
+	return false, &ValidationError{Errors: result.Errors}
+}
+
+// Validate check the configuration of the node system based on the checks of a configuration.
+// The errors and warnings are in the order of the checks whatever the checks run concurrently or not.
+func (s *NodeSystem) Validate(config ValidationConfig) ValidationResult {
+	selected, err := config.selectedChecks()
+	if err != nil {
+		return ValidationResult{Errors: []error{err}}
+	}
+	warnings := make(map[ValidationCheck]bool, len(warningValidationChecks)+len(config.Warnings))
+	for _, check := range warningValidationChecks {
+		warnings[check] = true
+	}
+	for _, check := range config.Errors {
+		delete(warnings, check)
+	}
+	for _, check := range config.Warnings {
+		warnings[check] = true
+	}
+
+	results := make([][]error, len(validationChecks))
+	var wg sync.WaitGroup
+	for index, validationCheck := range validationChecks {
+		if !selected[validationCheck.name] {
+			continue
+		}
+		if config.Sequential {
+			results[index] = validationCheck.check(s)
+			continue
+		}
+		wg.Add(1)
+		go func(index int, check func(*NodeSystem) []error) {
+			defer wg.Done()
+			results[index] = check(s)
+		}(index, validationCheck.check)
+	}
+	wg.Wait()
+
+	var validation ValidationResult
+	for index, result := range results {
+		if warnings[validationChecks[index].name] {
+			validation.Warnings = append(validation.Warnings, result...)
+		} else {
+			validation.Errors = append(validation.Errors, result...)
+		}
+	}
+	return validation
+}
+
+// ConfigureValidation configure the checks run to validate the node system on activation before activation,
+// e.g. to downgrade some checks to warnings in development mode.
+// IsValid still run all checks as errors, except the checks reported as warnings by default.
+func (s *NodeSystem) ConfigureValidation(config ValidationConfig) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't configure validation, node system is freeze due to activation")
+	}
+	_, err := config.selectedChecks()
+	if err != nil {
+		return false, err
+	}
+	for _, check := range config.Warnings {
+		if !isKnownValidationCheck(check) {
+			return false, fmt.Errorf("can't downgrade unknown validation check: %v", check)
+		}
+	}
+	for _, check := range config.Errors {
+		if !isKnownValidationCheck(check) {
+			return false, fmt.Errorf("can't upgrade unknown validation check: %v", check)
+		}
+	}
+	s.validation = config
+	return true, nil
+}
+
+// ActivationWarnings get the warnings of the validation on activation.
+func (s *NodeSystem) ActivationWarnings() []error {
+	return s.activationWarnings
+}
+
+func (c ValidationConfig) selectedChecks() (map[ValidationCheck]bool, error) {
+	selected := make(map[ValidationCheck]bool, len(validationChecks))
+	if len(c.Checks) == 0 {
+		for _, validationCheck := range validationChecks {
+			selected[validationCheck.name] = true
+		}
+	}
+	for _, check := range c.Checks {
+		if !isKnownValidationCheck(check) {
+			return nil, fmt.Errorf("can't run unknown validation check: %v", check)
+		}
+		selected[check] = true
+	}
+	for _, check := range c.Omit {
+		if !isKnownValidationCheck(check) {
+			return nil, fmt.Errorf("can't omit unknown validation check: %v", check)
+		}
+		delete(selected, check)
+	}
+	return selected, nil
+}
+
+func isKnownValidationCheck(check ValidationCheck) bool {
+	for _, validationCheck := range validationChecks {
+		if validationCheck.name == check {
+			return true
+		}
+	}
+	return false
+}
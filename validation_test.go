@@ -0,0 +1,168 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_IsValidWith(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.AddLink(anotherActionNode, someActionNode)
+
+	cycleError := fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{newNodeLink(someActionNode, anotherActionNode), newNodeLink(anotherActionNode, someActionNode)})
+	orphanError := fmt.Errorf("can't have decision node without link from it: %+v", alwaysTrueDecisionNode)
+
+	testCases := []struct {
+		name           string
+		givenConfig    ValidationConfig
+		expectedValid  bool
+		expectedErrors []error
+	}{
+		{
+			name:           "Can run all checks concurrently",
+			expectedErrors: []error{orphanError, cycleError},
+		},
+		{
+			name:           "Can run all checks sequentially",
+			givenConfig:    ValidationConfig{Sequential: true},
+			expectedErrors: []error{orphanError, cycleError},
+		},
+		{
+			name:           "Can omit a check",
+			givenConfig:    ValidationConfig{Omit: []ValidationCheck{CycleCheck}},
+			expectedErrors: []error{orphanError},
+		},
+		{
+			name:          "Can select checks",
+			givenConfig:   ValidationConfig{Checks: []ValidationCheck{UndeclaredNodeCheck, MultipleInstanceCheck}},
+			expectedValid: true,
+		},
+		{
+			name:           "Can't select an unknown check",
+			givenConfig:    ValidationConfig{Checks: []ValidationCheck{"unknown"}},
+			expectedErrors: []error{errors.New("can't run unknown validation check: unknown")},
+		},
+		{
+			name:           "Can't omit an unknown check",
+			givenConfig:    ValidationConfig{Omit: []ValidationCheck{"unknown"}},
+			expectedErrors: []error{errors.New("can't omit unknown validation check: unknown")},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			valid, validationErr := ns.IsValidWith(testCase.givenConfig)
+			errs := validationErrors(validationErr)
+
+			if valid != testCase.expectedValid {
+				t.Errorf("valid - got: %+v, want: %+v", valid, testCase.expectedValid)
+			}
+			if !cmp.Equal(errs, testCase.expectedErrors, errorComparator) {
+				t.Errorf("errors - got: %+v, want: %+v", errs, testCase.expectedErrors)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_ConfigureValidation(t *testing.T) {
+	testCases := []struct {
+		name               string
+		givenConfig        ValidationConfig
+		expectedError      error
+		expectedActivation bool
+		expectedWarnings   []error
+	}{
+		{
+			name:        "Can't activate with all checks as errors",
+			givenConfig: ValidationConfig{},
+			expectedError: fmt.Errorf("can't activate a unvalidated node system: %w", &ValidationError{Errors: []error{
+				fmt.Errorf("can't have decision node without link from it: %+v", alwaysTrueDecisionNode),
+			}}),
+		},
+		{
+			name:               "Can activate with a check downgraded to warnings",
+			givenConfig:        ValidationConfig{Warnings: []ValidationCheck{OrphanDecisionNodeCheck}},
+			expectedActivation: true,
+			expectedWarnings:   []error{fmt.Errorf("can't have decision node without link from it: %+v", alwaysTrueDecisionNode)},
+		},
+		{
+			name:          "Can't downgrade an unknown check",
+			givenConfig:   ValidationConfig{Warnings: []ValidationCheck{"unknown"}},
+			expectedError: errors.New("can't downgrade unknown validation check: unknown"),
+		},
+		{
+			name:          "Can't upgrade an unknown check",
+			givenConfig:   ValidationConfig{Errors: []ValidationCheck{"unknown"}},
+			expectedError: errors.New("can't upgrade unknown validation check: unknown"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ns := NewNodeSystem()
+			ns.AddNode(someActionNode)
+			ns.AddNode(alwaysTrueDecisionNode)
+			_, err := ns.ConfigureValidation(testCase.givenConfig)
+			if err == nil {
+				err = ns.ActivateInPlace()
+			}
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if ns.IsActivated() != testCase.expectedActivation {
+				t.Errorf("activation - got: %+v, want: %+v", ns.IsActivated(), testCase.expectedActivation)
+			}
+			if !cmp.Equal(ns.ActivationWarnings(), testCase.expectedWarnings, errorComparator) {
+				t.Errorf("warnings - got: %+v, want: %+v", ns.ActivationWarnings(), testCase.expectedWarnings)
+			}
+			if valid, _ := ns.IsValid(); valid {
+				t.Errorf("strict validity - got: %+v, want: %+v", valid, false)
+			}
+		})
+	}
+}
+
+// validationErrors give the errors of a ValidationError.
+func validationErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return []error{err}
+	}
+	return validationErr.Errors
+}
+
+func Test_ValidationError(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+
+	err := ns.ActivateInPlace()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got: %+v, want: a validation error", err)
+	}
+	expectedErrors := []error{
+		fmt.Errorf("can't have decision node without link from it: %+v", alwaysTrueDecisionNode),
+		errors.New("can't have undeclared node 'someActionNode' as 'from' in branch link {from:'someActionNode' to:'anotherActionNode'}"),
+		errors.New("can't have undeclared node 'anotherActionNode' as 'to' in branch link {from:'someActionNode' to:'anotherActionNode'}"),
+	}
+	if !cmp.Equal(validationErr.Errors, expectedErrors, errorComparator) {
+		t.Errorf("errors - got: %+v, want: %+v", validationErr.Errors, expectedErrors)
+	}
+	if !errors.Is(err, validationErr.Errors[1]) {
+		t.Errorf("is - got: %+v, want: %+v", false, true)
+	}
+	expectedMessage := expectedErrors[0].Error() + "\n" + expectedErrors[1].Error() + "\n" + expectedErrors[2].Error()
+	if validationErr.Error() != expectedMessage {
+		t.Errorf("message - got: %+v, want: %+v", validationErr.Error(), expectedMessage)
+	}
+}
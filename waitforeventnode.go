@@ -0,0 +1,64 @@
+package hoff
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// EventReceiverNode is a Node who can be paused during a computation
+// until an external event is delivered to it.
+type EventReceiverNode interface {
+	Node
+	// Receive handle the payload of the delivered event and decide which compute state to return.
+	Receive(c *Context, payload interface{}) ComputeState
+}
+
+// WaitForEventNode is a type of Node who pause the computation
+// until an external event (human approval, external callback, ...) is delivered to it.
+type WaitForEventNode struct {
+	name       string
+	payloadKey string
+}
+
+func (n WaitForEventNode) String() string {
+	return n.name
+}
+
+// Compute pause the computation with a new token to deliver the event to.
+func (n *WaitForEventNode) Compute(c *Context) ComputeState {
+	token, err := newToken()
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	return NewPauseComputeState(token)
+}
+
+// DecideCapability is desactived due to the fact that waiting for an event don't take a decision.
+func (n *WaitForEventNode) DecideCapability() bool {
+	return false
+}
+
+// Receive store the payload of the event in the context and continue the computation.
+func (n *WaitForEventNode) Receive(c *Context, payload interface{}) ComputeState {
+	c.Store(n.payloadKey, payload)
+	return NewContinueComputeState()
+}
+
+// NewWaitForEventNode create a WaitForEventNode based on a name
+// and the context key to store the payload of the delivered event.
+func NewWaitForEventNode(name, payloadKey string) (*WaitForEventNode, error) {
+	if payloadKey == "" {
+		return nil, errors.New("can't create wait for event node without payload key")
+	}
+	return &WaitForEventNode{name: name, payloadKey: payloadKey}, nil
+}
+
+func newToken() (string, error) {
+	bytes := make([]byte, 16)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
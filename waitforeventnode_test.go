@@ -0,0 +1,80 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewWaitForEventNode(t *testing.T) {
+	testCases := []struct {
+		name            string
+		givenPayloadKey string
+		expectedError   error
+	}{
+		{
+			name:          "Can't create a wait for event node without payload key",
+			expectedError: errors.New("can't create wait for event node without payload key"),
+		},
+		{
+			name:            "Can create a wait for event node",
+			givenPayloadKey: "payload",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewWaitForEventNode("WaitForEventNode", testCase.givenPayloadKey)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if testCase.givenPayloadKey == "" && node != nil {
+				t.Errorf("wait for event node - got: %+v, want: <nil>", node)
+			}
+		})
+	}
+}
+
+func Test_WaitForEventNode_Compute(t *testing.T) {
+	node, _ := NewWaitForEventNode("approval", "approval_payload")
+
+	state := node.Compute(NewContextWithoutData())
+	anotherState := node.Compute(NewContextWithoutData())
+
+	if state.Value != PauseState {
+		t.Errorf("state - got: %+v, want: %+v", state.Value, PauseState)
+	}
+	if state.Token == "" || state.Token == anotherState.Token {
+		t.Errorf("token - got: %+v and %+v, want: unique tokens", state.Token, anotherState.Token)
+	}
+}
+
+func Test_WaitForEventNode_Receive(t *testing.T) {
+	node, _ := NewWaitForEventNode("approval", "approval_payload")
+	context := NewContextWithoutData()
+
+	state := node.Receive(context, "approved")
+	expectedData := map[string]interface{}{"approval_payload": "approved"}
+
+	if !cmp.Equal(state, NewContinueComputeState(), errorComparator) {
+		t.Errorf("state - got: %+v, want: %+v", state, NewContinueComputeState())
+	}
+	if !cmp.Equal(context.Data, expectedData) {
+		t.Errorf("context data - got: %+v, want: %+v", context.Data, expectedData)
+	}
+}
+
+func Test_WaitForEventNode_DecideCapability(t *testing.T) {
+	node, _ := NewWaitForEventNode("approval", "approval_payload")
+	if node.DecideCapability() {
+		t.Error("wait for event node must have no decide capability")
+	}
+}
+
+func Test_WaitForEventNode_String(t *testing.T) {
+	node, _ := NewWaitForEventNode("approval", "approval_payload")
+	if node.String() != "approval" {
+		t.Error("wait for event node must print its name")
+	}
+}
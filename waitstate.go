@@ -0,0 +1,55 @@
+package hoff
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// minimalPollInterval avoid to busy-loop on a node waiting without poll interval.
+const minimalPollInterval = time.Millisecond
+
+// interruptionCheckInterval is the maximal duration of a poll interval without checking for an interruption.
+const interruptionCheckInterval = 10 * time.Millisecond
+
+// waitNode compute again a node while it wait for an external condition, after each poll interval.
+// The node is aborted once the deadline of its first wait state is passed,
+// and the waiting stop with ErrComputationInterrupted if the computation is interrupted.
+func (cp *Computation) waitNode(node Node, state ComputeState) (ComputeState, error) {
+	deadline := state.Deadline
+	for state.Value == WaitState {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return NewAbortWithCodeComputeState(TransientAbort, fmt.Errorf("can't have node '%v' waiting after its deadline: %v", node, deadline.Format(time.RFC3339Nano))), nil
+		}
+		interval := state.PollInterval
+		if interval < minimalPollInterval {
+			interval = minimalPollInterval
+		}
+		if !deadline.IsZero() && time.Until(deadline) < interval {
+			interval = time.Until(deadline)
+		}
+		if !cp.sleep(interval) {
+			return state, ErrComputationInterrupted
+		}
+		state = cp.runNode(node)
+	}
+	return state, nil
+}
+
+// sleep wait for a duration, and tell if the computation is not interrupted meanwhile.
+func (cp *Computation) sleep(duration time.Duration) bool {
+	end := time.Now().Add(duration)
+	for {
+		if atomic.LoadInt32(&cp.interrupted) == 1 {
+			return false
+		}
+		remaining := time.Until(end)
+		if remaining <= 0 {
+			return true
+		}
+		if remaining > interruptionCheckInterval {
+			remaining = interruptionCheckInterval
+		}
+		time.Sleep(remaining)
+	}
+}
@@ -0,0 +1,96 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// pollingNode wait until it's computed a number of times.
+type pollingNode struct {
+	name     string
+	attempts int32
+	ready    int32
+	timeout  time.Duration
+}
+
+func (n *pollingNode) String() string { return n.name }
+func (n *pollingNode) Compute(c *Context) ComputeState {
+	if atomic.AddInt32(&n.attempts, 1) < n.ready {
+		return NewWaitComputeState(time.Millisecond, time.Now().Add(n.timeout))
+	}
+	return NewContinueComputeState()
+}
+func (n *pollingNode) DecideCapability() bool { return false }
+
+func Test_Engine_Compute_WaitState(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenReady       int32
+		givenTimeout     time.Duration
+		expectedState    ComputeState
+		expectedAttempts int32
+	}{
+		{
+			name:             "Compute again a waiting node until it's ready",
+			givenReady:       3,
+			givenTimeout:     time.Second,
+			expectedState:    NewContinueComputeState(),
+			expectedAttempts: 3,
+		},
+		{
+			name:          "Abort a waiting node after its deadline",
+			givenReady:    1000,
+			givenTimeout:  20 * time.Millisecond,
+			expectedState: NewAbortWithCodeComputeState(TransientAbort, errors.New("can't have node 'fileAppears' waiting after its deadline")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node := &pollingNode{name: "fileAppears", ready: testCase.givenReady, timeout: testCase.givenTimeout}
+			ns := NewNodeSystem()
+			ns.AddNode(node)
+			ns.ActivateInPlace()
+			eng := NewEngine(SequentialComputation)
+			eng.ConfigureNodeSystem(ns)
+
+			result := eng.Compute(map[string]interface{}{})
+
+			state := result.Report[node]
+			if state.Value == AbortState {
+				// the deadline in the error message depend on the time of the first wait state
+				state.Error = errors.New(state.Error.Error()[:len(testCase.expectedState.Error.Error())])
+			}
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if testCase.expectedAttempts > 0 && atomic.LoadInt32(&node.attempts) != testCase.expectedAttempts {
+				t.Errorf("attempts - got: %+v, want: %+v", atomic.LoadInt32(&node.attempts), testCase.expectedAttempts)
+			}
+		})
+	}
+}
+
+func Test_Handle_Cancel_WaitState(t *testing.T) {
+	node := &pollingNode{name: "jobFinishes", ready: 1000, timeout: time.Minute}
+	ns := NewNodeSystem()
+	ns.AddNode(node)
+	ns.ActivateInPlace()
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	handle := eng.Submit(map[string]interface{}{}, SubmitOptions{})
+	for atomic.LoadInt32(&node.attempts) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	handle.Cancel()
+	result, _ := handle.Wait(context.Background())
+
+	if result.Error != ErrComputationInterrupted || handle.State() != HandleCanceled {
+		t.Errorf("got: %+v (state: %+v), want: %+v", result.Error, handle.State(), ErrComputationInterrupted)
+	}
+}
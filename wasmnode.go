@@ -0,0 +1,159 @@
+package hoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+)
+
+// WasmRuntime is the contract to run a function of a WebAssembly module in a sandbox,
+// no WebAssembly runtime is given with hoff to keep it without dependencies,
+// any WebAssembly runtime (wazero, wasmtime, ...) only need a thin adapter to be used here.
+// The runtime choose how the input and output are exchanged with the module (memory, WASI stdin/stdout, ...)
+// and is in charge of the limits of the module (memory, fuel, host functions, ...).
+type WasmRuntime interface {
+	// Run instantiate the module and call its function with the input, and give the output of the function.
+	// The run must stop once the Go context is done.
+	Run(ctx context.Context, module []byte, function string, input []byte) ([]byte, error)
+}
+
+// wasmInput is the JSON input of the function of a WebAssembly module.
+type wasmInput struct {
+	Node string                 `json:"node"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// wasmOutput is the JSON output of the function of a WebAssembly module.
+type wasmOutput struct {
+	State   StateType              `json:"state"`
+	Branch  *bool                  `json:"branch,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Stored  map[string]interface{} `json:"stored,omitempty"`
+	Deleted []string               `json:"deleted,omitempty"`
+}
+
+// WasmNode is a type of Node who run a function of a WebAssembly module through a WasmRuntime,
+// to compute a user-provided step in a sandbox.
+// The function get the node name and the context data as JSON ({"node": "...", "data": {...}}),
+// and give its compute state and the changes made on the context data as JSON
+// ({"state": "Continue", "branch": true, "error": "...", "stored": {...}, "deleted": [...]}).
+// The context values go through JSON, so a value is read back
+// as its JSON representation (e.g. a number become a float64),
+// and the values persisted in the context store are fetched (see Context.StoreExternal).
+type WasmNode struct {
+	name             string
+	runtime          WasmRuntime
+	module           []byte
+	function         string
+	decideCapability bool
+}
+
+func (n WasmNode) String() string {
+	return n.name
+}
+
+// Compute run the function of the module against the context data, apply the context changes
+// and return the compute state given by the function.
+func (n *WasmNode) Compute(c *Context) ComputeState {
+	input, err := json.Marshal(wasmInput{Node: n.name, Data: c.readAll()})
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't encode input of wasm node '%v': %w", n.name, err))
+	}
+
+	encodedOutput, err := n.runtime.Run(c.GoContext(), n.module, n.function, input)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't compute wasm node '%v': %w", n.name, err))
+	}
+	var output wasmOutput
+	err = json.Unmarshal(encodedOutput, &output)
+	if err != nil {
+		return NewAbortComputeState(fmt.Errorf("can't decode output of wasm node '%v': %w", n.name, err))
+	}
+
+	for key, value := range output.Stored {
+		c.Store(key, value)
+	}
+	for _, key := range output.Deleted {
+		c.Delete(key)
+	}
+
+	switch output.State {
+	case ContinueState:
+		if n.decideCapability {
+			if output.Branch == nil {
+				return NewAbortComputeState(fmt.Errorf("can't continue wasm node '%v' without branch", n.name))
+			}
+			return NewContinueOnBranchComputeState(*output.Branch)
+		}
+		return NewContinueComputeState()
+	case SkipState:
+		return NewSkipComputeState()
+	case AbortState:
+		return NewAbortComputeState(errors.New(output.Error))
+	}
+	return NewAbortComputeState(fmt.Errorf("can't handle state '%v' of wasm node '%v'", output.State, n.name))
+}
+
+// DecideCapability tell if the wasm node take a decision during compute.
+func (n *WasmNode) DecideCapability() bool {
+	return n.decideCapability
+}
+
+// NewWasmNode create a WasmNode based on a name, the runtime to use,
+// the WebAssembly module (binary format) and its function to call, and its capability to take a decision.
+func NewWasmNode(name string, runtime WasmRuntime, module []byte, function string, decideCapability bool) (*WasmNode, error) {
+	if runtime == nil {
+		return nil, errors.New("can't create wasm node without runtime")
+	}
+	if len(module) == 0 {
+		return nil, errors.New("can't create wasm node without module")
+	}
+	if function == "" {
+		return nil, errors.New("can't create wasm node without function")
+	}
+	return &WasmNode{
+		name:             name,
+		runtime:          runtime,
+		module:           module,
+		function:         function,
+		decideCapability: decideCapability,
+	}, nil
+}
+
+// RegisterWasmNodeType register the 'wasm' node type (see RegisterNodeType) creating WasmNodes run by the runtime,
+// configured by the path of the module file ('module'), its function ('function', 'compute' by default),
+// and its capability to take a decision ('decision', false by default).
+func RegisterWasmNodeType(runtime WasmRuntime) error {
+	if runtime == nil {
+		return errors.New("can't register wasm node type without runtime")
+	}
+	return RegisterNodeType("wasm", func(name string, config map[string]string) (Node, error) {
+		path, found := config["module"]
+		if !found {
+			return nil, errors.New("missing module")
+		}
+		module, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read module: %w", err)
+		}
+		function := config["function"]
+		if function == "" {
+			function = "compute"
+		}
+		decideCapability := false
+		if decision, found := config["decision"]; found {
+			decideCapability, err = strconv.ParseBool(decision)
+			if err != nil {
+				return nil, fmt.Errorf("invalid decision: %v", decision)
+			}
+		}
+		node, err := NewWasmNode(name, runtime, module, function, decideCapability)
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	})
+}
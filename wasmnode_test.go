@@ -0,0 +1,243 @@
+package hoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeWasmRuntime run a Go function in place of the module function.
+type fakeWasmRuntime func(input wasmInput) (string, error)
+
+func (r fakeWasmRuntime) Run(ctx context.Context, module []byte, function string, input []byte) ([]byte, error) {
+	var decodedInput wasmInput
+	err := json.Unmarshal(input, &decodedInput)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r(decodedInput)
+	return []byte(output), err
+}
+
+var wasmNodeTypeRegistration = RegisterWasmNodeType(fakeWasmRuntime(func(input wasmInput) (string, error) {
+	return fmt.Sprintf(`{"state": "Continue", "stored": {"computed_by": %q}}`, input.Node), nil
+}))
+
+func Test_NewWasmNode(t *testing.T) {
+	runtime := fakeWasmRuntime(func(wasmInput) (string, error) { return `{"state": "Continue"}`, nil })
+
+	testCases := []struct {
+		name          string
+		givenRuntime  WasmRuntime
+		givenModule   []byte
+		givenFunction string
+		expectedError error
+	}{
+		{
+			name:          "Can create a wasm node",
+			givenRuntime:  runtime,
+			givenModule:   []byte("\x00asm"),
+			givenFunction: "compute",
+		},
+		{
+			name:          "Can't create a wasm node without runtime",
+			givenModule:   []byte("\x00asm"),
+			givenFunction: "compute",
+			expectedError: errors.New("can't create wasm node without runtime"),
+		},
+		{
+			name:          "Can't create a wasm node without module",
+			givenRuntime:  runtime,
+			givenFunction: "compute",
+			expectedError: errors.New("can't create wasm node without module"),
+		},
+		{
+			name:          "Can't create a wasm node without function",
+			givenRuntime:  runtime,
+			givenModule:   []byte("\x00asm"),
+			expectedError: errors.New("can't create wasm node without function"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewWasmNode("WasmNode", testCase.givenRuntime, testCase.givenModule, testCase.givenFunction, false)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if testCase.expectedError != nil && node != nil {
+				t.Errorf("wasm node - got: %+v, want: <nil>", node)
+			}
+		})
+	}
+}
+
+func Test_WasmNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenOutput      string
+		givenRunError    error
+		givenDecision    bool
+		expectedState    ComputeState
+		expectedData     map[string]interface{}
+		expectedRunInput wasmInput
+	}{
+		{
+			name:          "Can continue with the changes on the context data",
+			givenOutput:   `{"state": "Continue", "stored": {"total": 42}, "deleted": ["draft"]}`,
+			expectedState: NewContinueComputeState(),
+			expectedData:  map[string]interface{}{"amount": float64(21), "total": float64(42)},
+		},
+		{
+			name:          "Can continue on a branch",
+			givenOutput:   `{"state": "Continue", "branch": true}`,
+			givenDecision: true,
+			expectedState: NewContinueOnBranchComputeState(true),
+			expectedData:  map[string]interface{}{"amount": float64(21), "draft": true},
+		},
+		{
+			name:          "Can skip",
+			givenOutput:   `{"state": "Skip"}`,
+			expectedState: NewSkipComputeState(),
+			expectedData:  map[string]interface{}{"amount": float64(21), "draft": true},
+		},
+		{
+			name:          "Can abort with the error of the module",
+			givenOutput:   `{"state": "Abort", "error": "amount too low"}`,
+			expectedState: NewAbortComputeState(errors.New("amount too low")),
+			expectedData:  map[string]interface{}{"amount": float64(21), "draft": true},
+		},
+		{
+			name:          "Can't continue a decision without branch",
+			givenOutput:   `{"state": "Continue"}`,
+			givenDecision: true,
+			expectedState: NewAbortComputeState(errors.New("can't continue wasm node 'WasmNode' without branch")),
+			expectedData:  map[string]interface{}{"amount": float64(21), "draft": true},
+		},
+		{
+			name:          "Can't handle an unknown state",
+			givenOutput:   `{"state": "Unknown"}`,
+			expectedState: NewAbortComputeState(errors.New("can't handle state 'Unknown' of wasm node 'WasmNode'")),
+			expectedData:  map[string]interface{}{"amount": float64(21), "draft": true},
+		},
+		{
+			name:          "Can't compute when the module fail",
+			givenRunError: errors.New("out of fuel"),
+			expectedState: NewAbortComputeState(fmt.Errorf("can't compute wasm node 'WasmNode': %w", errors.New("out of fuel"))),
+			expectedData:  map[string]interface{}{"amount": float64(21), "draft": true},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var runInput wasmInput
+			runtime := fakeWasmRuntime(func(input wasmInput) (string, error) {
+				runInput = input
+				return testCase.givenOutput, testCase.givenRunError
+			})
+			node, _ := NewWasmNode("WasmNode", runtime, []byte("\x00asm"), "compute", testCase.givenDecision)
+			c := NewContext(map[string]interface{}{"amount": float64(21), "draft": true})
+
+			state := node.Compute(c)
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+			expectedInput := wasmInput{Node: "WasmNode", Data: map[string]interface{}{"amount": float64(21), "draft": true}}
+			if !cmp.Equal(runInput, expectedInput) {
+				t.Errorf("input - got: %+v, want: %+v", runInput, expectedInput)
+			}
+		})
+	}
+}
+
+func Test_WasmNode_Compute_readInput(t *testing.T) {
+	var runInput wasmInput
+	runtime := fakeWasmRuntime(func(input wasmInput) (string, error) {
+		runInput = input
+		return `{"state": "Continue"}`, nil
+	})
+	node, _ := NewWasmNode("WasmNode", runtime, []byte("\x00asm"), "compute", false)
+	c := NewContext(map[string]interface{}{"amount": float64(21), "draft": true})
+	c.ConfigureStore(NewMemoryContextStore())
+	c.StoreExternal("document", "content")
+	buffer := c.startStaging()
+	c.Store("total", float64(42))
+	c.Delete("draft")
+
+	node.Compute(c)
+	c.commitStaging(buffer)
+
+	expectedInput := wasmInput{Node: "WasmNode", Data: map[string]interface{}{"amount": float64(21), "document": "content", "total": float64(42)}}
+	if !cmp.Equal(runInput, expectedInput) {
+		t.Errorf("got: %+v, want: %+v", runInput, expectedInput)
+	}
+}
+
+func Test_RegisterWasmNodeType(t *testing.T) {
+	if wasmNodeTypeRegistration != nil {
+		t.Fatalf("registration error - got: %+v, want: nothing", wasmNodeTypeRegistration)
+	}
+	dir, _ := ioutil.TempDir("", "hoff-wasm")
+	defer os.RemoveAll(dir)
+	module := filepath.Join(dir, "step.wasm")
+	ioutil.WriteFile(module, []byte("\x00asm"), 0600)
+
+	testCases := []struct {
+		name          string
+		givenConfig   map[string]string
+		expectedData  map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:         "Can create a wasm node from its config",
+			givenConfig:  map[string]string{"module": module},
+			expectedData: map[string]interface{}{"computed_by": "step"},
+		},
+		{
+			name:          "Can't create a wasm node without module",
+			givenConfig:   map[string]string{},
+			expectedError: "can't create node 'step' of type 'wasm': missing module",
+		},
+		{
+			name:          "Can't create a wasm node with an invalid decision",
+			givenConfig:   map[string]string{"module": module, "decision": "maybe"},
+			expectedError: "can't create node 'step' of type 'wasm': invalid decision: maybe",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			node, err := NewNodeOfType("wasm", "step", testCase.givenConfig)
+
+			if testCase.expectedError != "" {
+				if err == nil || err.Error() != testCase.expectedError {
+					t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error - got: %+v, want: nothing", err)
+			}
+			c := NewContext(map[string]interface{}{})
+			node.Compute(c)
+			if !cmp.Equal(c.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", c.Data, testCase.expectedData)
+			}
+		})
+	}
+
+	err := RegisterWasmNodeType(nil)
+	expectedError := errors.New("can't register wasm node type without runtime")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -0,0 +1,169 @@
+package hoff
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// WorkflowLoader create a node system from the content of a workflow definition file,
+// e.g. with ImportGraphML and the nodes of the workflow.
+type WorkflowLoader func(r io.Reader) (*NodeSystem, error)
+
+// EngineFactory create and configure an engine for an activated node system.
+type EngineFactory func(system *NodeSystem) (*Engine, error)
+
+// Watcher reload a workflow definition file on change, and swap the version of the workflow in a registry,
+// to update a workflow from its configuration without restart.
+// The file is polled, and a new version is only registered once loaded, activated (so validated),
+// and its engine created. On failure, the current version is kept.
+type Watcher struct {
+	registry *WorkflowRegistry
+	name     string
+	path     string
+	loader   WorkflowLoader
+	factory  EngineFactory
+
+	interval     time.Duration
+	drainTimeout time.Duration
+	handler      func(error)
+
+	mu       sync.Mutex
+	loaded   [sha256.Size]byte
+	rejected [sha256.Size]byte
+	version  int
+}
+
+// NewWatcher create a Watcher of the definition file of a workflow, polling the file every second,
+// and draining the computations of a previous version up to a minute.
+func NewWatcher(registry *WorkflowRegistry, name, path string, loader WorkflowLoader, factory EngineFactory) (*Watcher, error) {
+	if registry == nil || name == "" || path == "" {
+		return nil, errors.New("can't create watcher without registry, workflow name, or file")
+	}
+	if loader == nil || factory == nil {
+		return nil, errors.New("can't create watcher without loader or engine factory")
+	}
+	return &Watcher{
+		registry:     registry,
+		name:         name,
+		path:         path,
+		loader:       loader,
+		factory:      factory,
+		interval:     time.Second,
+		drainTimeout: time.Minute,
+	}, nil
+}
+
+// ConfigurePollInterval replace the interval between two polls of the file.
+func (w *Watcher) ConfigurePollInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("can't configure poll interval not positive: %v", interval)
+	}
+	w.interval = interval
+	return nil
+}
+
+// ConfigureDrainTimeout replace the time given to the running computations of a previous version to end,
+// before being interrupted.
+func (w *Watcher) ConfigureDrainTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("can't configure drain timeout not positive: %v", timeout)
+	}
+	w.drainTimeout = timeout
+	return nil
+}
+
+// ConfigureReloadHandler add a function called after each reload of a changed file,
+// with the error of the reload if any.
+func (w *Watcher) ConfigureReloadHandler(handler func(error)) {
+	w.handler = handler
+}
+
+// Version give the number of versions of the workflow registered by the watcher.
+func (w *Watcher) Version() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.version
+}
+
+// Reload load the file, and register it as new version of the workflow when changed since the last registered version.
+// It tell if a new version have been registered. A rejected content is not reloaded until the file change again.
+func (w *Watcher) Reload(ctx context.Context) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	content, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return false, fmt.Errorf("can't read workflow '%v': %w", w.name, err)
+	}
+	sum := sha256.Sum256(content)
+	if (w.version > 0 && sum == w.loaded) || sum == w.rejected {
+		return false, nil
+	}
+
+	reloaded, err := w.reload(ctx, content)
+	if reloaded {
+		w.loaded = sum
+		w.version++
+	} else {
+		w.rejected = sum
+	}
+	if w.handler != nil {
+		w.handler(err)
+	}
+	return reloaded, err
+}
+
+func (w *Watcher) reload(ctx context.Context, content []byte) (bool, error) {
+	system, err := w.loader(bytes.NewReader(content))
+	if err != nil {
+		return false, fmt.Errorf("can't load workflow '%v': %w", w.name, err)
+	}
+	if system == nil {
+		return false, fmt.Errorf("can't load workflow '%v' without node system", w.name)
+	}
+	if err := system.ActivateInPlace(); err != nil {
+		return false, fmt.Errorf("can't load workflow '%v': %w", w.name, err)
+	}
+	engine, err := w.factory(system)
+	if err != nil {
+		return false, fmt.Errorf("can't create engine of workflow '%v': %w", w.name, err)
+	}
+	if engine == nil {
+		return false, fmt.Errorf("can't create engine of workflow '%v'", w.name)
+	}
+	if engine.system == nil {
+		if err := engine.ConfigureNodeSystem(system); err != nil {
+			return false, fmt.Errorf("can't create engine of workflow '%v': %w", w.name, err)
+		}
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, w.drainTimeout)
+	defer cancel()
+	_, err = w.registry.Swap(drainCtx, w.name, engine)
+	if err != nil {
+		return true, fmt.Errorf("can't drain previous version of workflow '%v': %w", w.name, err)
+	}
+	return true, nil
+}
+
+// Run reload the file, then poll it until the context is done.
+// The reload errors are given to the reload handler, and don't stop the watcher.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.Reload(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
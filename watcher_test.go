@@ -0,0 +1,126 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	watchedWorkflowV1 = `<graphml><graph edgedefault="directed"><node id="someActionNode"/></graph></graphml>`
+	watchedWorkflowV2 = `<graphml><graph edgedefault="directed">
+  <node id="someActionNode"/>
+  <node id="anotherActionNode"/>
+  <edge source="someActionNode" target="anotherActionNode"/>
+</graph></graphml>`
+	watchedWorkflowCycle = `<graphml><graph edgedefault="directed">
+  <node id="someActionNode"/>
+  <node id="anotherActionNode"/>
+  <edge source="someActionNode" target="anotherActionNode"/>
+  <edge source="anotherActionNode" target="someActionNode"/>
+</graph></graphml>`
+)
+
+func Test_Watcher_Reload(t *testing.T) {
+	testCases := []struct {
+		name              string
+		givenContents     []string
+		expectedReloaded  []bool
+		expectedErrors    []bool
+		expectedVersion   int
+		expectedNodeCount int
+	}{
+		{
+			name:              "Can load a workflow",
+			givenContents:     []string{watchedWorkflowV1},
+			expectedReloaded:  []bool{true},
+			expectedErrors:    []bool{false},
+			expectedVersion:   1,
+			expectedNodeCount: 1,
+		},
+		{
+			name:              "Can reload a changed workflow",
+			givenContents:     []string{watchedWorkflowV1, watchedWorkflowV2},
+			expectedReloaded:  []bool{true, true},
+			expectedErrors:    []bool{false, false},
+			expectedVersion:   2,
+			expectedNodeCount: 2,
+		},
+		{
+			name:              "Can't reload an unchanged workflow",
+			givenContents:     []string{watchedWorkflowV1, watchedWorkflowV1},
+			expectedReloaded:  []bool{true, false},
+			expectedErrors:    []bool{false, false},
+			expectedVersion:   1,
+			expectedNodeCount: 1,
+		},
+		{
+			name:              "Can't reload an invalid workflow",
+			givenContents:     []string{watchedWorkflowV1, watchedWorkflowCycle, watchedWorkflowCycle},
+			expectedReloaded:  []bool{true, false, false},
+			expectedErrors:    []bool{false, true, false},
+			expectedVersion:   1,
+			expectedNodeCount: 1,
+		},
+		{
+			name:              "Can't reload an unreadable workflow",
+			givenContents:     []string{watchedWorkflowV1, "<graphml>"},
+			expectedReloaded:  []bool{true, false},
+			expectedErrors:    []bool{false, true},
+			expectedVersion:   1,
+			expectedNodeCount: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir, _ := ioutil.TempDir("", "hoff")
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "orders.graphml")
+			registry := NewWorkflowRegistry()
+			watcher, _ := NewWatcher(registry, "orders", path, func(r io.Reader) (*NodeSystem, error) {
+				return ImportGraphML(r, someActionNode, anotherActionNode)
+			}, func(*NodeSystem) (*Engine, error) {
+				return NewEngine(SequentialComputation), nil
+			})
+			handled := 0
+			watcher.ConfigureReloadHandler(func(error) { handled++ })
+
+			reloaded := make([]bool, 0)
+			errs := make([]bool, 0)
+			for _, content := range testCase.givenContents {
+				ioutil.WriteFile(path, []byte(content), 0644)
+				ok, err := watcher.Reload(context.Background())
+				reloaded = append(reloaded, ok)
+				errs = append(errs, err != nil)
+			}
+
+			if !cmp.Equal(reloaded, testCase.expectedReloaded) {
+				t.Errorf("reloaded - got: %+v, want: %+v", reloaded, testCase.expectedReloaded)
+			}
+			if !cmp.Equal(errs, testCase.expectedErrors) {
+				t.Errorf("errors - got: %+v, want: %+v", errs, testCase.expectedErrors)
+			}
+			if watcher.Version() != testCase.expectedVersion {
+				t.Errorf("version - got: %+v, want: %+v", watcher.Version(), testCase.expectedVersion)
+			}
+			system, _ := registry.NodeSystem("orders")
+			if len(system.nodes) != testCase.expectedNodeCount {
+				t.Errorf("nodes - got: %+v, want: %+v", len(system.nodes), testCase.expectedNodeCount)
+			}
+		})
+	}
+}
+
+func Test_NewWatcher(t *testing.T) {
+	_, err := NewWatcher(NewWorkflowRegistry(), "orders", "orders.graphml", nil, nil)
+	expectedError := errors.New("can't create watcher without loader or engine factory")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
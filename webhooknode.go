@@ -0,0 +1,166 @@
+package hoff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// WebhookNode is a type of Node who send a JSON payload, built from the context data, to a webhook.
+// A failed request is sent again with an exponential backoff, on network errors and on responses
+// with status 429 or 5xx.
+type WebhookNode struct {
+	name    string
+	url     string
+	payload func(c *Context) ([]byte, error)
+	headers map[string]string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+}
+
+func (n WebhookNode) String() string {
+	return n.name
+}
+
+// Compute build the payload and send it to the webhook, until a success or the end of the retries.
+func (n *WebhookNode) Compute(c *Context) ComputeState {
+	payload, err := n.payload(c)
+	if err != nil {
+		return NewAbortWithCodeComputeState(ValidationAbort, fmt.Errorf("can't build payload of node '%v': %v", n.name, err))
+	}
+	delay := n.backoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := n.send(c, payload)
+		if err == nil {
+			return NewContinueComputeState()
+		}
+		if !retryable {
+			return NewAbortWithCodeComputeState(PermanentAbort, err)
+		}
+		if attempt == n.retries {
+			return NewAbortWithCodeComputeState(TransientAbort, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.GoContext().Done():
+			timer.Stop()
+			return NewAbortWithCodeComputeState(TransientAbort, err)
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// send post the payload once, and tell if a failure can be retried.
+func (n *WebhookNode) send(c *Context, payload []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(c.GoContext(), http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("can't call webhook of node '%v': %v", n.name, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range n.headers {
+		request.Header.Set(key, value)
+	}
+	response, err := n.client.Do(request)
+	if err != nil {
+		return true, fmt.Errorf("can't call webhook of node '%v': %v", n.name, err)
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, 1<<16))
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	return retryable, fmt.Errorf("can't call webhook of node '%v': %v", n.name, response.Status)
+}
+
+// DecideCapability is desactived due to the fact that a notification don't take a decision.
+func (n *WebhookNode) DecideCapability() bool {
+	return false
+}
+
+// ConfigureRetries send again a failed request up to a number of retries,
+// waiting a backoff doubled after each retry.
+func (n *WebhookNode) ConfigureRetries(retries int, backoff time.Duration) error {
+	if retries < 0 || backoff < 0 {
+		return fmt.Errorf("can't configure retries with negative values: %v, %v", retries, backoff)
+	}
+	n.retries = retries
+	n.backoff = backoff
+	return nil
+}
+
+// ConfigureHeader add a header to the requests, like an authorization token.
+func (n *WebhookNode) ConfigureHeader(key, value string) {
+	n.headers[key] = value
+}
+
+// ConfigureClient replace the HTTP client used to call the webhook.
+func (n *WebhookNode) ConfigureClient(client *http.Client) {
+	n.client = client
+}
+
+// NewWebhookNode create a WebhookNode based on a name, the webhook URL, and the template of the payload
+// (see text/template), executed on the context data, who must give a JSON document.
+// The 'json' function of the template encode a value, like '{"order": {{json .order}}}'.
+func NewWebhookNode(name, webhookURL, payload string) (*WebhookNode, error) {
+	parsed, err := template.New("payload").Option("missingkey=error").Funcs(template.FuncMap{"json": jsonTemplateFunc}).Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("can't create webhook node with invalid payload template: %v", err)
+	}
+	return newWebhookNode(name, webhookURL, func(c *Context) ([]byte, error) {
+		var buffer bytes.Buffer
+		err := parsed.Execute(&buffer, c.Data)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(buffer.Bytes()) {
+			return nil, fmt.Errorf("invalid JSON: %v", buffer.String())
+		}
+		return buffer.Bytes(), nil
+	})
+}
+
+// NewSlackNode create a WebhookNode who post a message on a Slack incoming webhook,
+// based on a name, the webhook URL, and the template of the message text (see text/template).
+func NewSlackNode(name, webhookURL, text string) (*WebhookNode, error) {
+	parsed, err := template.New("text").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("can't create slack node with invalid text template: %v", err)
+	}
+	return newWebhookNode(name, webhookURL, func(c *Context) ([]byte, error) {
+		var buffer bytes.Buffer
+		err := parsed.Execute(&buffer, c.Data)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"text": buffer.String()})
+	})
+}
+
+func newWebhookNode(name, webhookURL string, payload func(c *Context) ([]byte, error)) (*WebhookNode, error) {
+	parsedURL, err := url.Parse(webhookURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, errors.New("can't create webhook node without http(s) url")
+	}
+	return &WebhookNode{
+		name:    name,
+		url:     webhookURL,
+		payload: payload,
+		headers: make(map[string]string),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func jsonTemplateFunc(value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
@@ -0,0 +1,183 @@
+package hoff
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewWebhookNode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenURL      string
+		givenPayload  string
+		expectedError error
+	}{
+		{
+			name:         "Can create a webhook node",
+			givenURL:     "https://hooks.localhost/notify",
+			givenPayload: `{"order": {{json .order}}}`,
+		},
+		{
+			name:          "Can't create a webhook node without url",
+			givenPayload:  `{}`,
+			expectedError: errors.New("can't create webhook node without http(s) url"),
+		},
+		{
+			name:          "Can't create a webhook node with non http url",
+			givenURL:      "ftp://hooks.localhost/notify",
+			givenPayload:  `{}`,
+			expectedError: errors.New("can't create webhook node without http(s) url"),
+		},
+		{
+			name:          "Can't create a webhook node with invalid payload template",
+			givenURL:      "https://hooks.localhost/notify",
+			givenPayload:  `{"order": {{json .order}`,
+			expectedError: errors.New(`can't create webhook node with invalid payload template: template: payload:1: bad character U+007D '}'`),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewWebhookNode("webhook", testCase.givenURL, testCase.givenPayload)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_WebhookNode_Compute(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenNode        func(url string) (*WebhookNode, error)
+		givenStatuses    []int
+		givenData        map[string]interface{}
+		expectedState    ComputeState
+		expectedPayloads []string
+	}{
+		{
+			name: "Can send a payload",
+			givenNode: func(url string) (*WebhookNode, error) {
+				return NewWebhookNode("webhook", url, `{"order": {{json .order}}, "by": {{json .user}}}`)
+			},
+			givenStatuses:    []int{http.StatusNoContent},
+			givenData:        map[string]interface{}{"order": 42, "user": "bob \"the builder\""},
+			expectedState:    NewContinueComputeState(),
+			expectedPayloads: []string{`{"order": 42, "by": "bob \"the builder\""}`},
+		},
+		{
+			name: "Can send a slack message",
+			givenNode: func(url string) (*WebhookNode, error) {
+				return NewSlackNode("slack", url, `Order {{.order}} approved by {{.user}}`)
+			},
+			givenStatuses:    []int{http.StatusOK},
+			givenData:        map[string]interface{}{"order": 42, "user": "bob"},
+			expectedState:    NewContinueComputeState(),
+			expectedPayloads: []string{`{"text":"Order 42 approved by bob"}`},
+		},
+		{
+			name: "Can send a payload again after a transient failure",
+			givenNode: func(url string) (*WebhookNode, error) {
+				node, err := NewSlackNode("slack", url, `Order {{.order}}`)
+				node.ConfigureRetries(2, time.Millisecond)
+				return node, err
+			},
+			givenStatuses:    []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			givenData:        map[string]interface{}{"order": 42},
+			expectedState:    NewContinueComputeState(),
+			expectedPayloads: []string{`{"text":"Order 42"}`, `{"text":"Order 42"}`, `{"text":"Order 42"}`},
+		},
+		{
+			name: "Can't send a payload after the retries",
+			givenNode: func(url string) (*WebhookNode, error) {
+				node, err := NewSlackNode("slack", url, `Order {{.order}}`)
+				node.ConfigureRetries(1, time.Millisecond)
+				return node, err
+			},
+			givenStatuses:    []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			givenData:        map[string]interface{}{"order": 42},
+			expectedState:    NewAbortWithCodeComputeState(TransientAbort, errors.New("can't call webhook of node 'slack': 502 Bad Gateway")),
+			expectedPayloads: []string{`{"text":"Order 42"}`, `{"text":"Order 42"}`},
+		},
+		{
+			name: "Can't send a payload rejected by the webhook",
+			givenNode: func(url string) (*WebhookNode, error) {
+				node, err := NewSlackNode("slack", url, `Order {{.order}}`)
+				node.ConfigureRetries(3, time.Millisecond)
+				return node, err
+			},
+			givenStatuses:    []int{http.StatusBadRequest, http.StatusOK},
+			givenData:        map[string]interface{}{"order": 42},
+			expectedState:    NewAbortWithCodeComputeState(PermanentAbort, errors.New("can't call webhook of node 'slack': 400 Bad Request")),
+			expectedPayloads: []string{`{"text":"Order 42"}`},
+		},
+		{
+			name: "Can't send an invalid JSON payload",
+			givenNode: func(url string) (*WebhookNode, error) {
+				return NewWebhookNode("webhook", url, `{"order": {{.order}}}`)
+			},
+			givenData:     map[string]interface{}{"order": "forty-two"},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New(`can't build payload of node 'webhook': invalid JSON: {"order": forty-two}`)),
+		},
+		{
+			name: "Can't send a payload with missing key",
+			givenNode: func(url string) (*WebhookNode, error) {
+				return NewSlackNode("slack", url, `Order {{.order}}`)
+			},
+			givenData:     map[string]interface{}{},
+			expectedState: NewAbortWithCodeComputeState(ValidationAbort, errors.New(`can't build payload of node 'slack': template: text:1:8: executing "text" at <.order>: map has no entry for key "order"`)),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var mu sync.Mutex
+			payloads := make([]string, 0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				mu.Lock()
+				defer mu.Unlock()
+				if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(testCase.givenStatuses[len(payloads)])
+				payloads = append(payloads, string(body))
+			}))
+			defer server.Close()
+			node, err := testCase.givenNode(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			node.ConfigureHeader("Authorization", "Bearer token")
+
+			state := node.Compute(NewContext(testCase.givenData))
+
+			if !cmp.Equal(state, testCase.expectedState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !cmp.Equal(payloads, append([]string{}, testCase.expectedPayloads...)) {
+				t.Errorf("payloads - got: %+v, want: %+v", payloads, testCase.expectedPayloads)
+			}
+		})
+	}
+}
+
+func Test_WebhookNode_ConfigureRetries(t *testing.T) {
+	node, _ := NewSlackNode("slack", "https://hooks.localhost/notify", "done")
+
+	err := node.ConfigureRetries(-1, time.Second)
+
+	expectedError := errors.New("can't configure retries with negative values: -1, 1s")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -0,0 +1,198 @@
+package hoff
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// SubmitOptions hold the options of a computation submitted to an engine.
+type SubmitOptions struct {
+	// Priority of the computation nodes on the engine worker pool, the highest first
+	Priority int
+	// Seed replace the random seed of the computation, to compute again
+	// with the random numbers of a previous computation (see Context.Rand)
+	Seed *int64
+	// Overrides force to skip or to run nodes by name, whatever the branch decisions
+	Overrides map[string]NodeOverride
+	// Trigger is the name of the trigger to compute from (see NodeSystem.DeclareTrigger), all initial nodes if empty
+	Trigger string
+	// Tenant is the team or customer owning the computation, to apply its quota (see Engine.ConfigureTenantQuota),
+	// and to label its events and reports
+	Tenant string
+	// Budget replace the budget of the computation configured on the engine (see Engine.ConfigureComputationBudget)
+	Budget *ComputationBudget
+	// fromNode is the node to compute from without its ancestors
+	fromNode Node
+	// computationID replace the generated ID of the computation
+	computationID string
+	// release end the admission of the computation by the quota of its tenant, once admitted
+	release func()
+}
+
+// nodeTask is a node execution waiting for a worker.
+type nodeTask struct {
+	priority int
+	sequence uint64
+	run      func()
+}
+
+// before tell if a task run before another one, by priority then by submission order.
+func (t nodeTask) before(other nodeTask) bool {
+	if t.priority != other.priority {
+		return t.priority > other.priority
+	}
+	return t.sequence < other.sequence
+}
+
+// nodeTaskQueue is a heap of node tasks by priority, then by submission order.
+type nodeTaskQueue []nodeTask
+
+func (q nodeTaskQueue) Len() int { return len(q) }
+
+func (q nodeTaskQueue) Less(i, j int) bool { return q[i].before(q[j]) }
+
+func (q nodeTaskQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nodeTaskQueue) Push(x interface{}) { *q = append(*q, x.(nodeTask)) }
+
+func (q *nodeTaskQueue) Pop() interface{} {
+	old := *q
+	task := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return task
+}
+
+// workerPool run the node executions of all computations of one or several engines
+// on a fixed number of workers, by priority, or fairly across the workflows of the engines.
+type workerPool struct {
+	mu       sync.Mutex
+	ready    *sync.Cond
+	tasks    map[string]*nodeTaskQueue
+	pending  int
+	sequence uint64
+	closed   bool
+	workers  sync.WaitGroup
+
+	// fair enable the weighted round-robin across workflows, with the weights by workflow (1 by default)
+	// and the current credits of the workflows with waiting tasks
+	fair    bool
+	weights map[string]int
+	credits map[string]int
+}
+
+func newWorkerPool(workers int) *workerPool {
+	p := &workerPool{
+		tasks:   make(map[string]*nodeTaskQueue),
+		credits: make(map[string]int),
+	}
+	p.ready = sync.NewCond(&p.mu)
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// execute run a function on a worker and wait for its end,
+// once the pool is closed the function is run directly.
+func (p *workerPool) execute(priority int, run func()) {
+	p.executeFor("", priority, run)
+}
+
+// executeFor run a function of a workflow on a worker and wait for its end.
+func (p *workerPool) executeFor(workflow string, priority int, run func()) {
+	done := make(chan struct{})
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		run()
+		return
+	}
+	p.sequence++
+	queue, found := p.tasks[workflow]
+	if !found {
+		queue = &nodeTaskQueue{}
+		p.tasks[workflow] = queue
+	}
+	heap.Push(queue, nodeTask{priority: priority, sequence: p.sequence, run: func() {
+		run()
+		close(done)
+	}})
+	p.pending++
+	p.mu.Unlock()
+	p.ready.Signal()
+	<-done
+}
+
+func (p *workerPool) work() {
+	defer p.workers.Done()
+	for {
+		p.mu.Lock()
+		for p.pending == 0 && !p.closed {
+			p.ready.Wait()
+		}
+		if p.pending == 0 {
+			p.mu.Unlock()
+			return
+		}
+		task := p.next()
+		p.mu.Unlock()
+		task.run()
+	}
+}
+
+// next pop the next task to run, from the workflow chosen by weighted round-robin when fair,
+// or the task with the highest priority of all workflows.
+func (p *workerPool) next() nodeTask {
+	workflows := make([]string, 0, len(p.tasks))
+	for workflow := range p.tasks {
+		workflows = append(workflows, workflow)
+	}
+	sort.Strings(workflows)
+
+	chosen := ""
+	if p.fair {
+		total := 0
+		for index, workflow := range workflows {
+			weight := p.weight(workflow)
+			p.credits[workflow] += weight
+			total += weight
+			if index == 0 || p.credits[workflow] > p.credits[chosen] {
+				chosen = workflow
+			}
+		}
+		p.credits[chosen] -= total
+	} else {
+		for index, workflow := range workflows {
+			if index == 0 || (*p.tasks[workflow])[0].before((*p.tasks[chosen])[0]) {
+				chosen = workflow
+			}
+		}
+	}
+
+	queue := p.tasks[chosen]
+	task := heap.Pop(queue).(nodeTask)
+	if queue.Len() == 0 {
+		delete(p.tasks, chosen)
+		delete(p.credits, chosen)
+	}
+	p.pending--
+	return task
+}
+
+func (p *workerPool) weight(workflow string) int {
+	if weight, found := p.weights[workflow]; found {
+		return weight
+	}
+	return 1
+}
+
+// close stop the workers once the waiting tasks are run.
+func (p *workerPool) close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.ready.Broadcast()
+	p.workers.Wait()
+}
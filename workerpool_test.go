@@ -0,0 +1,63 @@
+package hoff
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_workerPool_execute(t *testing.T) {
+	pool := newWorkerPool(1)
+	defer pool.close()
+
+	blocked, release := make(chan struct{}), make(chan struct{})
+	go pool.execute(0, func() {
+		close(blocked)
+		<-release
+	})
+	<-blocked
+
+	var mu sync.Mutex
+	order := make([]int, 0)
+	var wg sync.WaitGroup
+	for _, priority := range []int{1, 5, 3} {
+		wg.Add(1)
+		go func(priority int) {
+			defer wg.Done()
+			pool.execute(priority, func() {
+				mu.Lock()
+				order = append(order, priority)
+				mu.Unlock()
+			})
+		}(priority)
+	}
+	for waitingTasks := 0; waitingTasks < 3; {
+		time.Sleep(time.Millisecond)
+		pool.mu.Lock()
+		waitingTasks = pool.pending
+		pool.mu.Unlock()
+	}
+	close(release)
+	wg.Wait()
+
+	expectedOrder := []int{5, 3, 1}
+	if !cmp.Equal(order, expectedOrder) {
+		t.Errorf("got: %+v, want: %+v", order, expectedOrder)
+	}
+}
+
+func Test_workerPool_close(t *testing.T) {
+	pool := newWorkerPool(2)
+	pool.close()
+
+	executed := false
+	pool.execute(0, func() {
+		executed = true
+	})
+
+	if !executed {
+		t.Errorf("got: %+v, want: %+v", executed, true)
+	}
+}
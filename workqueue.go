@@ -0,0 +1,225 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrClaimLost is the error of a WorkQueue when a worker use a claim it don't hold anymore,
+// like after its claim expired and the task was claimed by another worker.
+var ErrClaimLost = errors.New("can't use claim, task claimed by another worker")
+
+// WorkTask is a node execution shared on a WorkQueue.
+type WorkTask struct {
+	ID            string
+	ComputationID string
+	Request       ComputeRequest
+}
+
+// WorkQueue share the node executions of the computations of a leader engine with its followers.
+// A claim is kept by heartbeats, a task with an expired claim is given again to another worker.
+type WorkQueue interface {
+	// Enqueue add a task to be claimed.
+	Enqueue(task WorkTask) error
+	// Claim give the next available task to a worker, false when there is none.
+	Claim(worker string) (WorkTask, bool, error)
+	// Heartbeat extend the claim of a worker on a task, or give ErrClaimLost.
+	Heartbeat(worker, taskID string) error
+	// Complete give the response of a claimed task, or ErrClaimLost.
+	Complete(worker, taskID string, response ComputeResponse) error
+	// Result give the response of a completed task, false when not completed yet.
+	// The task is removed once its response is given.
+	Result(taskID string) (ComputeResponse, bool, error)
+}
+
+// ConfigureWorkQueue make the engine a leader who share the node executions on a work queue,
+// to be computed by followers (see Follower), the work queue is checked for results at an interval.
+// The context values go through JSON like with a RemoteNode,
+// and an EventReceiverNode is still computed by the engine.
+func (e *Engine) ConfigureWorkQueue(queue WorkQueue, pollInterval time.Duration) error {
+	if queue != nil && pollInterval <= 0 {
+		return fmt.Errorf("can't configure work queue with a non positive poll interval: %v", pollInterval)
+	}
+	e.workQueue = queue
+	e.workQueuePoll = pollInterval
+	return nil
+}
+
+func (e *Engine) shareOnWorkQueue(cp *Computation) nodeInterceptor {
+	service := &workQueueService{queue: e.workQueue, computationID: cp.ID, pollInterval: e.workQueuePoll}
+	return func(node Node, c *Context, compute func(*Context) ComputeState) ComputeState {
+		if _, ok := node.(EventReceiverNode); ok {
+			return compute(c)
+		}
+		remote := RemoteNode{name: fmt.Sprint(node), service: service, decideCapability: node.DecideCapability()}
+		return remote.Compute(c)
+	}
+}
+
+// workQueueService is a ComputeService enqueuing the requests on a work queue and waiting for their responses.
+type workQueueService struct {
+	queue         WorkQueue
+	computationID string
+	pollInterval  time.Duration
+}
+
+func (s *workQueueService) Compute(ctx context.Context, request *ComputeRequest) (*ComputeResponse, error) {
+	id, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.queue.Enqueue(WorkTask{ID: id, ComputationID: s.computationID, Request: *request})
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		response, completed, err := s.queue.Result(id)
+		if err != nil {
+			return nil, err
+		}
+		if completed {
+			return &response, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+type workEntry struct {
+	task      WorkTask
+	worker    string
+	heartbeat time.Time
+	claims    int
+	completed bool
+	response  ComputeResponse
+}
+
+// MemoryWorkQueue is a WorkQueue for the engines of a single process, mainly for testing.
+type MemoryWorkQueue struct {
+	claimTimeout time.Duration
+	maxClaims    int
+
+	mu      sync.Mutex
+	entries map[string]*workEntry
+	order   []string
+}
+
+// NewMemoryWorkQueue create an empty work queue where a claim expire without heartbeat during a timeout,
+// and where a task claimed a maximum number of times is completed in Abort.
+func NewMemoryWorkQueue(claimTimeout time.Duration, maxClaims int) (*MemoryWorkQueue, error) {
+	if claimTimeout <= 0 {
+		return nil, fmt.Errorf("can't create work queue with a non positive claim timeout: %v", claimTimeout)
+	}
+	if maxClaims <= 0 {
+		return nil, fmt.Errorf("can't create work queue under 1 claim by task: %v", maxClaims)
+	}
+	return &MemoryWorkQueue{
+		claimTimeout: claimTimeout,
+		maxClaims:    maxClaims,
+		entries:      make(map[string]*workEntry),
+	}, nil
+}
+
+// Enqueue add a task to be claimed.
+func (q *MemoryWorkQueue) Enqueue(task WorkTask) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, found := q.entries[task.ID]; found {
+		return fmt.Errorf("can't enqueue task '%v' twice", task.ID)
+	}
+	q.entries[task.ID] = &workEntry{task: task}
+	q.order = append(q.order, task.ID)
+	return nil
+}
+
+// Claim give the next available task to a worker, a task with an expired claim first.
+func (q *MemoryWorkQueue) Claim(worker string) (WorkTask, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for _, id := range q.order {
+		entry := q.entries[id]
+		if entry.completed || (entry.worker != "" && now.Sub(entry.heartbeat) < q.claimTimeout) {
+			continue
+		}
+		if entry.claims >= q.maxClaims {
+			entry.completed = true
+			entry.worker = ""
+			entry.response = ComputeResponse{
+				State: AbortState,
+				Error: fmt.Sprintf("can't compute node '%v', claimed %v times without completion", entry.task.Request.Node, entry.claims),
+			}
+			continue
+		}
+		entry.worker = worker
+		entry.heartbeat = now
+		entry.claims++
+		return entry.task, true, nil
+	}
+	return WorkTask{}, false, nil
+}
+
+// Heartbeat extend the claim of a worker on a task.
+func (q *MemoryWorkQueue) Heartbeat(worker, taskID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, err := q.claimedEntry(worker, taskID)
+	if err != nil {
+		return err
+	}
+	entry.heartbeat = time.Now()
+	return nil
+}
+
+// Complete give the response of a claimed task.
+func (q *MemoryWorkQueue) Complete(worker, taskID string, response ComputeResponse) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, err := q.claimedEntry(worker, taskID)
+	if err != nil {
+		return err
+	}
+	entry.completed = true
+	entry.worker = ""
+	entry.response = response
+	return nil
+}
+
+// Result give the response of a completed task.
+func (q *MemoryWorkQueue) Result(taskID string) (ComputeResponse, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entry, found := q.entries[taskID]
+	if !found {
+		return ComputeResponse{}, false, fmt.Errorf("can't find task '%v'", taskID)
+	}
+	if !entry.completed {
+		return ComputeResponse{}, false, nil
+	}
+	delete(q.entries, taskID)
+	for index, id := range q.order {
+		if id == taskID {
+			q.order = append(q.order[:index], q.order[index+1:]...)
+			break
+		}
+	}
+	return entry.response, true, nil
+}
+
+// claimedEntry give the entry of a task claimed by a worker, or ErrClaimLost.
+func (q *MemoryWorkQueue) claimedEntry(worker, taskID string) (*workEntry, error) {
+	entry, found := q.entries[taskID]
+	if !found || entry.completed || entry.worker != worker {
+		return nil, ErrClaimLost
+	}
+	return entry, nil
+}
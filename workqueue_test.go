@@ -0,0 +1,160 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NewMemoryWorkQueue(t *testing.T) {
+	testCases := []struct {
+		name              string
+		givenClaimTimeout time.Duration
+		givenMaxClaims    int
+		expectedError     error
+	}{
+		{
+			name:              "Can create a work queue",
+			givenClaimTimeout: time.Second,
+			givenMaxClaims:    3,
+		},
+		{
+			name:           "Can't create a work queue without claim timeout",
+			givenMaxClaims: 3,
+			expectedError:  errors.New("can't create work queue with a non positive claim timeout: 0s"),
+		},
+		{
+			name:              "Can't create a work queue without claim",
+			givenClaimTimeout: time.Second,
+			expectedError:     errors.New("can't create work queue under 1 claim by task: 0"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewMemoryWorkQueue(testCase.givenClaimTimeout, testCase.givenMaxClaims)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_MemoryWorkQueue(t *testing.T) {
+	queue, _ := NewMemoryWorkQueue(20*time.Millisecond, 2)
+	queue.Enqueue(WorkTask{ID: "task", Request: ComputeRequest{Node: "node"}})
+
+	err := queue.Enqueue(WorkTask{ID: "task"})
+	expectedError := errors.New("can't enqueue task 'task' twice")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("enqueue twice - got: %+v, want: %+v", err, expectedError)
+	}
+
+	task, claimed, _ := queue.Claim("orphan")
+	if !claimed || task.ID != "task" {
+		t.Errorf("claim - got: %+v, %v, want: task claimed", task, claimed)
+	}
+	_, claimed, _ = queue.Claim("worker")
+	if claimed {
+		t.Errorf("claim of claimed task - got: %v, want: %v", claimed, false)
+	}
+	if err = queue.Heartbeat("orphan", "task"); err != nil {
+		t.Errorf("heartbeat - got: %+v, want: %+v", err, nil)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	task, claimed, _ = queue.Claim("worker")
+	if !claimed || task.ID != "task" {
+		t.Errorf("claim of orphaned task - got: %+v, %v, want: task claimed", task, claimed)
+	}
+	if err = queue.Complete("orphan", "task", ComputeResponse{State: ContinueState}); err != ErrClaimLost {
+		t.Errorf("complete of lost claim - got: %+v, want: %+v", err, ErrClaimLost)
+	}
+	_, completed, _ := queue.Result("task")
+	if completed {
+		t.Errorf("result of claimed task - got: %v, want: %v", completed, false)
+	}
+	if err = queue.Complete("worker", "task", ComputeResponse{State: ContinueState}); err != nil {
+		t.Errorf("complete - got: %+v, want: %+v", err, nil)
+	}
+	response, completed, _ := queue.Result("task")
+	if !completed || response.State != ContinueState {
+		t.Errorf("result - got: %+v, %v, want: completed in Continue", response, completed)
+	}
+
+	_, _, err = queue.Result("task")
+	expectedError = errors.New("can't find task 'task'")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("result of removed task - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_MemoryWorkQueue_maxClaims(t *testing.T) {
+	queue, _ := NewMemoryWorkQueue(time.Millisecond, 1)
+	queue.Enqueue(WorkTask{ID: "task", Request: ComputeRequest{Node: "node"}})
+	queue.Claim("orphan")
+	time.Sleep(5 * time.Millisecond)
+
+	_, claimed, _ := queue.Claim("worker")
+	if claimed {
+		t.Errorf("claim - got: %v, want: %v", claimed, false)
+	}
+	response, completed, _ := queue.Result("task")
+	expectedResponse := ComputeResponse{State: AbortState, Error: "can't compute node 'node', claimed 1 times without completion"}
+	if !completed || !cmp.Equal(response, expectedResponse) {
+		t.Errorf("result - got: %+v, %v, want: %+v", response, completed, expectedResponse)
+	}
+}
+
+func Test_Engine_ConfigureWorkQueue(t *testing.T) {
+	leaderWrite, _ := NewActionNode("write", func(*Context) error { return errors.New("computed by leader") })
+	leaderDecide, _ := NewDecisionNode("decide", func(*Context) (bool, error) { return false, errors.New("computed by leader") })
+	followerWrite, _ := NewActionNode("write", func(c *Context) error {
+		c.Store("written", "by follower")
+		return nil
+	})
+	followerDecide, _ := NewDecisionNode("decide", func(c *Context) (bool, error) { return c.HaveKey("written"), nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(leaderWrite)
+	ns.AddNode(leaderDecide)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(leaderWrite, leaderDecide)
+	ns.AddLinkOnBranch(leaderDecide, anotherActionNode, true)
+	ns.IsValid()
+	ns.ActivateInPlace()
+
+	queue, _ := NewMemoryWorkQueue(time.Second, 3)
+	leader := NewEngine(SequentialComputation)
+	leader.ConfigureNodeSystem(ns)
+	err := leader.ConfigureWorkQueue(queue, 0)
+	expectedError := errors.New("can't configure work queue with a non positive poll interval: 0s")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("configure error - got: %+v, want: %+v", err, expectedError)
+	}
+	leader.ConfigureWorkQueue(queue, time.Millisecond)
+
+	server, _ := NewComputeServer(followerWrite, followerDecide, anotherActionNode)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, name := range []string{"follower-1", "follower-2"} {
+		follower, _ := NewFollower(name, queue, server)
+		follower.ConfigureIntervals(time.Millisecond, 10*time.Millisecond)
+		go follower.Run(ctx)
+	}
+
+	result := leader.Compute(map[string]interface{}{})
+	if result.Error != nil {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, nil)
+	}
+	if result.Data["written"] != "by follower" {
+		t.Errorf("data - got: %+v, want: written by follower", result.Data)
+	}
+	expectedState := NewContinueOnBranchComputeState(true)
+	if !cmp.Equal(result.Report[leaderDecide], expectedState, errorComparator) {
+		t.Errorf("decision - got: %+v, want: %+v", result.Report[leaderDecide], expectedState)
+	}
+}